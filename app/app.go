@@ -549,6 +549,11 @@ func New(
 		authtypes.NewModuleAddress(govtypes.ModuleName),
 		app.AccountKeeper,
 		app.BankKeeper,
+		app.StakingKeeper,
+		// the gov and wasm keepers are only created afterwards, as the wasm keeper queries the logic one and the gov
+		// keeper depends on the wasm one.
+		&app.GovKeeper,
+		&app.WasmKeeper,
 		app.provideFS,
 	)
 
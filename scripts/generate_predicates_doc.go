@@ -13,11 +13,16 @@ import (
 	"github.com/princjef/gomarkdoc"
 	"github.com/princjef/gomarkdoc/lang"
 	"github.com/princjef/gomarkdoc/logger"
+
+	"github.com/okp4/okp4d/x/logic/interpreter"
 )
 
 //go:embed templates/*.gotxt
 var f embed.FS
 
+// predicatePackage is the import path of the package of the predicates to document.
+const predicatePackage = "github.com/okp4/okp4d/x/logic/predicate"
+
 // globalCtx used to keep track of contexts between templates.
 // (yes it's a hack).
 var globalCtx = make(map[string]interface{})
@@ -44,6 +49,12 @@ func GeneratePredicateDocumentation() error {
 		return err
 	}
 
+	predicates, err := registeredPredicates(predicatePackage)
+	if err != nil {
+		return err
+	}
+	globalCtx["predicates"] = predicates
+
 	content, err := out.Package(pkg)
 	if err != nil {
 		return err
@@ -52,6 +63,29 @@ func GeneratePredicateDocumentation() error {
 	return writeToFile("docs/predicate/predicates.md", content)
 }
 
+// registeredPredicates returns the names (in the form of "atom/arity") under which the functions of the given package
+// are registered as predicates, indexed by function name, so that the documentation does not have to guess them from
+// the function names.
+func registeredPredicates(importPath string) (map[string]interface{}, error) {
+	predicates := make(map[string]interface{})
+	for _, name := range interpreter.RegistryNames {
+		fn, err := interpreter.PredicateFunc(name)
+		if err != nil {
+			return nil, err
+		}
+		funcName, ok := strings.CutPrefix(fn, importPath+".")
+		if !ok || strings.Contains(funcName, ".") { // not a function of the package, or a closure returned by one.
+			continue
+		}
+		if other, ok := predicates[funcName]; ok {
+			return nil, fmt.Errorf("function %s registered as both %s and %s", funcName, other, name)
+		}
+		predicates[funcName] = name
+	}
+
+	return predicates, nil
+}
+
 func createRenderer() (*gomarkdoc.Renderer, error) {
 	templateFunctionOpts := make([]gomarkdoc.RendererOption, 0)
 
@@ -76,9 +110,6 @@ func createRenderer() (*gomarkdoc.Renderer, error) {
 
 	templateFunctionOpts = append(
 		templateFunctionOpts,
-		gomarkdoc.WithTemplateFunc("countSubstr", func(substr string, s string) int {
-			return strings.Count(s, substr)
-		}),
 		gomarkdoc.WithTemplateFunc("globalCtx", func() map[string]interface{} {
 			return globalCtx
 		}),
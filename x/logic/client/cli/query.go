@@ -23,6 +23,7 @@ func GetQueryCmd(_ string) *cobra.Command {
 
 	cmd.AddCommand(CmdQueryParams())
 	cmd.AddCommand(CmdQueryAsk())
+	cmd.AddCommand(CmdQueryEstimateGas())
 
 	return cmd
 }
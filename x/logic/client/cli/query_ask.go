@@ -18,6 +18,8 @@ import (
 var (
 	program     string
 	programFile string
+	limit       uint64
+	offset      uint64
 )
 
 func CmdQueryAsk() *cobra.Command {
@@ -30,8 +32,18 @@ Optionally, a program can be transmitted, which will be interpreted before the q
 
 Since the query is without any side-effect, the query is not executed in the context of a transaction and no fee
 is charged for this, but the execution is constrained by the current limits configured in the module (that you can
-query).`,
-		Example: fmt.Sprintf(`$ %s query %s ask "chain_id(X)." # returns the chain-id`,
+query).
+
+The query is executed by the node against its latest state, or the state at the given --height, in the very same way
+as the programs executed on-chain, with the same predicates and limits. The answer, i.e. the substitutions of the
+variables of the query for each solution, is printed as JSON by default, for scripting purposes.
+
+The solutions can be retrieved page by page, with the --limit and --offset flags, the offset of a page being the one of
+the previous page increased by the number of its solutions. The pages are consistent with each other when the query is
+executed at the same --height.`,
+		Example: fmt.Sprintf(`$ %[1]s query %[2]s ask "chain_id(X)." # returns the chain-id
+$ %[1]s query %[2]s ask --program-file program.pl "allowed(X)." # returns the solutions of a program's goal
+$ %[1]s query %[2]s ask --limit 10 --offset 10 "between(1, 100, X)." # returns the second page of 10 solutions`,
 			version.AppName,
 			types.ModuleName),
 		Args: cobra.MinimumNArgs(1),
@@ -43,13 +55,18 @@ query).`,
 				}
 			}
 
-			clientCtx := client.GetClientContextFromCmd(cmd)
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return
+			}
 			query := args[0]
 			queryClient := types.NewQueryServiceClient(clientCtx)
 
 			res, err := queryClient.Ask(context.Background(), &types.QueryServiceAskRequest{
 				Program: program,
 				Query:   query,
+				Limit:   limit,
+				Offset:  offset,
 			})
 			if err != nil {
 				return
@@ -70,12 +87,30 @@ query).`,
 		"",
 		`reads the program from the given filename or from stdin if "-" is passed as the filename.`)
 	cmd.MarkFlagsMutuallyExclusive("program", "program-file")
+	cmd.Flags().Uint64Var(
+		&limit,
+		"limit",
+		0,
+		`the maximum number of solutions to return, or 0 for as many as the module limits permit.`)
+	cmd.Flags().Uint64Var(
+		&offset,
+		"offset",
+		0,
+		`the number of solutions to skip, to get the solutions following the ones of a previous query.`)
 
 	flags.AddQueryFlagsToCmd(cmd)
+	withDefaultOutput(cmd, "json")
 
 	return cmd
 }
 
+// withDefaultOutput sets the default output format of the given command, which must have the query flags.
+func withDefaultOutput(cmd *cobra.Command, format string) {
+	output := cmd.Flags().Lookup(flags.FlagOutput)
+	output.DefValue = format
+	_ = output.Value.Set(format)
+}
+
 // ReadProgramFromFile reads text from the given filename or from stdin if "-" is passed as the filename.
 // It returns the text as a string and an error value.
 func ReadProgramFromFile(filename string) (string, error) {
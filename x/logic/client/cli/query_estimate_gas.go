@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/flags"
+	"github.com/cosmos/cosmos-sdk/version"
+
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func CmdQueryEstimateGas() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "estimate-gas [query]",
+		Short: "estimates the amount of gas consumed by the execution of a logic query.",
+		Long: `Executes the [query] as the ask command does, and returns the amount of gas consumed by its execution.
+
+Optionally, a program can be transmitted, which will be interpreted before the query is processed.
+
+The execution is constrained by the current limits configured in the module (that you can query), including the
+max_gas limit: a query exceeding it fails as with the ask command.`,
+		Example: fmt.Sprintf(`$ %s query %s estimate-gas "chain_id(X)." # returns the gas consumed to get the chain-id`,
+			version.AppName,
+			types.ModuleName),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if programFile != "" {
+				program, err = ReadProgramFromFile(programFile)
+				if err != nil {
+					return
+				}
+			}
+
+			clientCtx, err := client.GetClientQueryContext(cmd)
+			if err != nil {
+				return
+			}
+			query := args[0]
+			queryClient := types.NewQueryServiceClient(clientCtx)
+
+			res, err := queryClient.EstimateGas(context.Background(), &types.QueryServiceEstimateGasRequest{
+				Program: program,
+				Query:   query,
+			})
+			if err != nil {
+				return
+			}
+
+			return clientCtx.PrintProto(res)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&program,
+		"program",
+		"",
+		`reads the program from the given string.`)
+	cmd.Flags().StringVar(
+		&programFile,
+		"program-file",
+		"",
+		`reads the program from the given filename or from stdin if "-" is passed as the filename.`)
+	cmd.MarkFlagsMutuallyExclusive("program", "program-file")
+
+	flags.AddQueryFlagsToCmd(cmd)
+	withDefaultOutput(cmd, "json")
+
+	return cmd
+}
@@ -255,3 +255,5 @@ maplist(Cont_7, [E1|E1s], [E2|E2s], [E3|E3s], [E4|E4s], [E5|E5s], [E6|E6s], [E7|
   maplist(Cont_7, E1s, E2s, E3s, E4s, E5s, E6s, E7s).
 
 source_files(Files) :- bagof(File, source_file(File), Files).
+
+parse_by_template(Template, Input, Bindings) :- parse_by_template(Template, Input, Bindings, []).
@@ -0,0 +1,138 @@
+package interpreter
+
+import (
+	"sort"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+// DepthLimit bounds the depth of the derivations built by an interpreter, i.e. the number of predicates called along
+// each branch of the resolution, a resource_error(stack) exception being raised by the predicate exceeding it. Both the
+// registered predicates and the predicates defined by the consulted programs are counted, the latter by expanding their
+// rules when they are compiled (see WithPredicates).
+//
+// The depth is recorded in the environment the predicates are called with, so that it follows the resolution: it is
+// restored on backtracking, as well as when an exception is caught. As it only depends on the program being executed,
+// the exception is deterministic, and may be caught by the program, unlike a Go stack overflow or an out of memory.
+//
+// A depth of n is recorded by binding the n first variables of a sequence, the environment being only extended by
+// unification, the depth of an environment being found by a binary search for the first of them which is unbound.
+type DepthLimit struct {
+	max       uint64
+	levels    []engine.Variable
+	expanding bool
+}
+
+// NewDepthLimit returns a new DepthLimit bounding the depth of the derivations to the given maximum. A DepthLimit
+// records the depth of the derivations of a single interpreter, and shall not be shared.
+func NewDepthLimit(maxDepth uint64) *DepthLimit {
+	return &DepthLimit{max: maxDepth}
+}
+
+// Guard returns the Guard of the predicate of the given indicator (i.e. name/arity), incrementing the depth of the
+// environment it is called with, or raising a resource_error(stack) exception if the maximum depth would be exceeded.
+// A nil DepthLimit returns a Guard leaving the environment unchanged.
+func (d *DepthLimit) Guard(pi engine.Term) Guard {
+	return func(env *engine.Env) (*engine.Env, error) {
+		if d == nil {
+			return env, nil
+		}
+
+		depth := sort.Search(len(d.levels), func(i int) bool {
+			_, unbound := env.Resolve(d.levels[i]).(engine.Variable)
+			return unbound
+		})
+		if uint64(depth) >= d.max {
+			formal := engine.NewAtom("resource_error").Apply(engine.NewAtom("stack"))
+			return nil, engine.NewException(engine.NewAtom("error").Apply(formal, pi), env)
+		}
+		if depth == len(d.levels) {
+			d.levels = append(d.levels, engine.NewVariable())
+		}
+		env, _ = env.Unify(d.levels[depth], engine.Integer(depth))
+
+		return env, nil
+	}
+}
+
+var (
+	atomDepth         = engine.NewAtom("$depth")
+	atomTermExpansion = engine.NewAtom("term_expansion")
+	atomRule          = engine.NewAtom(":-")
+	atomDCGRule       = engine.NewAtom("-->")
+	atomConjunction   = engine.NewAtom(",")
+	atomSlash         = engine.NewAtom("/")
+)
+
+// expand is the term_expansion/2 predicate making the depth account for the calls of the predicates defined by the
+// consulted programs, which would otherwise not be bounded: each rule H :- B, or each rule H --> B once translated, is
+// expanded into H :- '$depth'(Name/Arity), B, '$depth'/1 being the enter predicate of the DepthLimit (see enter). The
+// facts, which call no predicate, are left unchanged.
+//
+// As it would disable the expansion, a clause defining term_expansion/2 or '$depth'/1 raises a
+// permission_error(modify, static_procedure, Name/Arity) exception. The clauses added by asserta/1 and assertz/1 are
+// not expanded, their calls being only bounded by the maximum number of steps.
+func (d *DepthLimit) expand(vm *engine.VM, term, expanded engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	if c, ok := env.Resolve(term).(engine.Compound); ok && c.Functor() == atomDCGRule && c.Arity() == 2 {
+		if d.expanding {
+			// Let the interpreter translate the grammar rule, being called back by engine.ExpandTerm.
+			return engine.Bool(false)
+		}
+
+		d.expanding = true
+		defer func() { d.expanding = false }()
+		translated := engine.NewVariable()
+		return engine.ExpandTerm(vm, c, translated, func(env *engine.Env) *engine.Promise {
+			return d.expandClause(vm, translated, expanded, cont, env)
+		}, env)
+	}
+
+	return d.expandClause(vm, term, expanded, cont, env)
+}
+
+// expandClause expands the given clause as described by expand.
+func (d *DepthLimit) expandClause(vm *engine.VM, clause, expanded engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	head, body := env.Resolve(clause), engine.Term(nil)
+	if c, ok := head.(engine.Compound); ok && c.Functor() == atomRule {
+		if c.Arity() != 2 {
+			return engine.Bool(false)
+		}
+		head, body = env.Resolve(c.Arg(0)), c.Arg(1)
+	}
+
+	var pi engine.Term
+	switch h := head.(type) {
+	case engine.Atom:
+		pi = atomSlash.Apply(h, engine.Integer(0))
+	case engine.Compound:
+		pi = atomSlash.Apply(h.Functor(), engine.Integer(h.Arity()))
+	default:
+		return engine.Bool(false)
+	}
+	for _, reserved := range []engine.Term{
+		atomSlash.Apply(atomTermExpansion, engine.Integer(2)),
+		atomSlash.Apply(atomDepth, engine.Integer(1)),
+	} {
+		if reserved.Compare(pi, env) == 0 {
+			formal := engine.NewAtom("permission_error").Apply(
+				engine.NewAtom("modify"), engine.NewAtom("static_procedure"), pi)
+			return engine.Error(engine.NewException(engine.NewAtom("error").Apply(formal, pi), env))
+		}
+	}
+	if body == nil {
+		return engine.Bool(false)
+	}
+
+	return engine.Unify(vm, expanded, atomRule.Apply(head, atomConjunction.Apply(atomDepth.Apply(pi), body)), cont, env)
+}
+
+// enter is the '$depth'/1 predicate called by the rules expanded by expand, incrementing the depth on behalf of the
+// predicate of the given indicator, as its Guard would do.
+func (d *DepthLimit) enter(_ *engine.VM, pi engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	env, err := d.Guard(env.Resolve(pi))(env)
+	if err != nil {
+		return engine.Error(err)
+	}
+
+	return cont(env)
+}
@@ -107,3 +107,136 @@ func Instrument8[T any](hook Hook[T], p engine.Predicate8) engine.Predicate8 {
 		return p(vm, t1, t2, t3, t4, t5, t6, t7, t8, cont, env)
 	}
 }
+
+// Guard is a function checking the environment a predicate is called with, returning the environment to call it with,
+// or the error to raise instead of calling it.
+type Guard func(env *engine.Env) (*engine.Env, error)
+
+// Guard0 is a higher order function that given a 0arg-predicate and a guard returns a new predicate that calls the
+// guard before calling the predicate.
+func Guard0(guard Guard, p engine.Predicate0) engine.Predicate0 {
+	return func(vm *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise {
+		env, err := guard(env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		return p(vm, cont, env)
+	}
+}
+
+// Guard1 is a higher order function that given a 1arg-predicate and a guard returns a new predicate that calls the
+// guard before calling the predicate.
+func Guard1(guard Guard, p engine.Predicate1) engine.Predicate1 {
+	return func(vm *engine.VM, t1 engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+		env, err := guard(env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		return p(vm, t1, cont, env)
+	}
+}
+
+// Guard2 is a higher order function that given a 2args-predicate and a guard returns a new predicate that calls the
+// guard before calling the predicate.
+func Guard2(guard Guard, p engine.Predicate2) engine.Predicate2 {
+	return func(vm *engine.VM, t1 engine.Term, t2 engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+		env, err := guard(env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		return p(vm, t1, t2, cont, env)
+	}
+}
+
+// Guard3 is a higher order function that given a 3args-predicate and a guard returns a new predicate that calls the
+// guard before calling the predicate.
+func Guard3(guard Guard, p engine.Predicate3) engine.Predicate3 {
+	return func(vm *engine.VM, t1 engine.Term, t2 engine.Term, t3 engine.Term, cont engine.Cont,
+		env *engine.Env,
+	) *engine.Promise {
+		env, err := guard(env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		return p(vm, t1, t2, t3, cont, env)
+	}
+}
+
+// Guard4 is a higher order function that given a 4args-predicate and a guard returns a new predicate that calls the
+// guard before calling the predicate.
+//
+//nolint:lll
+func Guard4(guard Guard, p engine.Predicate4) engine.Predicate4 {
+	return func(vm *engine.VM, t1 engine.Term, t2 engine.Term, t3 engine.Term, t4 engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+		env, err := guard(env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		return p(vm, t1, t2, t3, t4, cont, env)
+	}
+}
+
+// Guard5 is a higher order function that given a 5args-predicate and a guard returns a new predicate that calls the
+// guard before calling the predicate.
+//
+//nolint:lll
+func Guard5(guard Guard, p engine.Predicate5) engine.Predicate5 {
+	return func(vm *engine.VM, t1 engine.Term, t2 engine.Term, t3 engine.Term, t4 engine.Term, t5 engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+		env, err := guard(env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		return p(vm, t1, t2, t3, t4, t5, cont, env)
+	}
+}
+
+// Guard6 is a higher order function that given a 6args-predicate and a guard returns a new predicate that calls the
+// guard before calling the predicate.
+//
+//nolint:lll
+func Guard6(guard Guard, p engine.Predicate6) engine.Predicate6 {
+	return func(vm *engine.VM, t1 engine.Term, t2 engine.Term, t3 engine.Term, t4 engine.Term, t5 engine.Term, t6 engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+		env, err := guard(env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		return p(vm, t1, t2, t3, t4, t5, t6, cont, env)
+	}
+}
+
+// Guard7 is a higher order function that given a 7args-predicate and a guard returns a new predicate that calls the
+// guard before calling the predicate.
+//
+//nolint:lll
+func Guard7(guard Guard, p engine.Predicate7) engine.Predicate7 {
+	return func(vm *engine.VM, t1 engine.Term, t2 engine.Term, t3 engine.Term, t4 engine.Term, t5 engine.Term, t6 engine.Term, t7 engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+		env, err := guard(env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		return p(vm, t1, t2, t3, t4, t5, t6, t7, cont, env)
+	}
+}
+
+// Guard8 is a higher order function that given a 8args-predicate and a guard returns a new predicate that calls the
+// guard before calling the predicate.
+//
+//nolint:lll
+func Guard8(guard Guard, p engine.Predicate8) engine.Predicate8 {
+	return func(vm *engine.VM, t1 engine.Term, t2 engine.Term, t3 engine.Term, t4 engine.Term, t5 engine.Term, t6 engine.Term, t7 engine.Term, t8 engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+		env, err := guard(env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		return p(vm, t1, t2, t3, t4, t5, t6, t7, t8, cont, env)
+	}
+}
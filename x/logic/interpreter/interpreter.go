@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"slices"
 
 	"github.com/ichiban/prolog"
 	"github.com/ichiban/prolog/engine"
+	"github.com/samber/lo"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/types"
 )
 
 // Predicates is a map of predicate names to their execution costs.
@@ -18,12 +22,42 @@ type Predicates map[string]uint64
 // Option is a function that configures an Interpreter.
 type Option func(*prolog.Interpreter) error
 
+// Keepers is a map of the keepers the predicates depend on, indexed by the context key under which they are provided.
+type Keepers map[types.ContextKey]any
+
+// ProvideKeepers returns a copy of the given context providing the given keepers to the predicates depending on them.
+func ProvideKeepers(ctx sdk.Context, keepers Keepers) sdk.Context {
+	keys := lo.Keys(keepers)
+	slices.Sort(keys)
+	for _, key := range keys {
+		ctx = ctx.WithValue(key, keepers[key])
+	}
+	return ctx
+}
+
 // WithPredicates configures the interpreter to register the specified predicates.
-// The predicates names must be present in the registry, otherwise the function will return an error.
-func WithPredicates(_ goctx.Context, predicates Predicates, meter sdk.GasMeter) Option {
+// The predicates names must be present in the registry, and the keepers they depend on must be provided by the given
+// context (see ProvideKeepers), otherwise the function will return an error.
+// The depth of the derivations is bounded by the given limit, if not nil (see DepthLimit), the term_expansion/2 and
+// '$depth'/1 predicates being then registered to count the calls of the predicates defined by the programs compiled
+// afterward, including the bootstrap script.
+func WithPredicates(ctx goctx.Context, predicates Predicates, meter sdk.GasMeter, depth *DepthLimit) Option {
 	return func(i *prolog.Interpreter) error {
+		if depth != nil {
+			i.Register2(atomTermExpansion, depth.expand)
+			i.Register1(atomDepth, depth.enter)
+		}
 		for predicate, cost := range predicates {
-			if err := Register(i, predicate, cost, meter); err != nil {
+			keepers, err := PredicateKeepers(predicate)
+			if err != nil {
+				return fmt.Errorf("error registering predicate '%s': %w", predicate, err)
+			}
+			for _, key := range keepers {
+				if ctx.Value(key) == nil {
+					return fmt.Errorf("error registering predicate '%s': missing keeper %s", predicate, key)
+				}
+			}
+			if err := Register(i, predicate, cost, meter, depth); err != nil {
 				return fmt.Errorf("error registering predicate '%s': %w", predicate, err)
 			}
 		}
@@ -42,6 +76,23 @@ func WithBootstrap(ctx goctx.Context, bootstrap string) Option {
 	}
 }
 
+// WithForbiddenPredicates configures the interpreter to raise a permission_error when one of the specified
+// predicates is called (see Forbid), the predicates remaining defined and their calls consuming their cost from the
+// given meter. As the bootstrap script may call them while being compiled, this option shall be given after
+// WithBootstrap.
+func WithForbiddenPredicates(predicates Predicates, meter sdk.GasMeter) Option {
+	return func(i *prolog.Interpreter) error {
+		names := lo.Keys(predicates)
+		slices.Sort(names)
+		for _, predicate := range names {
+			if err := Forbid(i, predicate, predicates[predicate], meter); err != nil {
+				return fmt.Errorf("error forbidding predicate '%s': %w", predicate, err)
+			}
+		}
+		return nil
+	}
+}
+
 // WithUserOutputWriter configures the interpreter to use the specified writer for user output.
 func WithUserOutputWriter(w io.Writer) Option {
 	return func(i *prolog.Interpreter) error {
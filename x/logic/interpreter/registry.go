@@ -2,6 +2,8 @@ package interpreter
 
 import (
 	"fmt"
+	"reflect"
+	"runtime"
 	"strconv"
 	"strings"
 
@@ -11,176 +13,439 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/okp4/okp4d/x/logic/predicate"
+	"github.com/okp4/okp4d/x/logic/types"
 )
 
-// registry is a map from predicate names (in the form of "atom/arity") to predicates functions.
-var registry = map[string]any{
-	"call/1":                    engine.Call,
-	"catch/3":                   engine.Catch,
-	"throw/1":                   engine.Throw,
-	"=/2":                       engine.Unify,
-	"unify_with_occurs_check/2": engine.UnifyWithOccursCheck,
-	"subsumes_term/2":           engine.SubsumesTerm,
-	"var/1":                     engine.TypeVar,
-	"atom/1":                    engine.TypeAtom,
-	"integer/1":                 engine.TypeInteger,
-	"float/1":                   engine.TypeFloat,
-	"compound/1":                engine.TypeCompound,
-	"acyclic_term/1":            engine.AcyclicTerm,
-	"compare/3":                 engine.Compare,
-	"sort/2":                    engine.Sort,
-	"keysort/2":                 engine.KeySort,
-	"functor/3":                 engine.Functor,
-	"arg/3":                     engine.Arg,
-	"=../2":                     engine.Univ,
-	"copy_term/2":               engine.CopyTerm,
-	"term_variables/2":          engine.TermVariables,
-	"is/2":                      engine.Is,
-	"=:=/2":                     engine.Equal,
-	"=\\=/2":                    engine.NotEqual,
-	"</2":                       engine.LessThan,
-	"=</2":                      engine.LessThanOrEqual,
-	">/2":                       engine.GreaterThan,
-	">=/2":                      engine.GreaterThanOrEqual,
-	"clause/2":                  engine.Clause,
-	"current_predicate/1":       engine.CurrentPredicate,
-	"asserta/1":                 engine.Asserta,
-	"assertz/1":                 engine.Assertz,
-	"retract/1":                 engine.Retract,
-	"abolish/1":                 engine.Abolish,
-	"findall/3":                 engine.FindAll,
-	"bagof/3":                   engine.BagOf,
-	"setof/3":                   engine.SetOf,
-	"current_input/1":           engine.CurrentInput,
-	"current_output/1":          engine.CurrentOutput,
-	"set_input/1":               engine.SetInput,
-	"set_output/1":              engine.SetOutput,
-	"open/4":                    predicate.Open,
-	"close/2":                   engine.Close,
-	"flush_output/1":            engine.FlushOutput,
-	"stream_property/2":         engine.StreamProperty,
-	"set_stream_position/2":     engine.SetStreamPosition,
-	"get_char/2":                engine.GetChar,
-	"peek_char/2":               engine.PeekChar,
-	"put_char/2":                engine.PutChar,
-	"get_byte/2":                engine.GetByte,
-	"peek_byte/2":               engine.PeekByte,
-	"put_byte/2":                engine.PutByte,
-	"read_term/3":               engine.ReadTerm,
-	"write_term/3":              engine.WriteTerm,
-	"op/3":                      engine.Op,
-	"current_op/3":              engine.CurrentOp,
-	"char_conversion/2":         engine.CharConversion,
-	"current_char_conversion/2": engine.CurrentCharConversion,
-	`\+/1`:                      engine.Negate,
-	"repeat/0":                  engine.Repeat,
-	"call/2":                    engine.Call1,
-	"call/3":                    engine.Call2,
-	"call/4":                    engine.Call3,
-	"call/5":                    engine.Call4,
-	"call/6":                    engine.Call5,
-	"call/7":                    engine.Call6,
-	"call/8":                    engine.Call7,
-	"atom_length/2":             engine.AtomLength,
-	"atom_concat/3":             engine.AtomConcat,
-	"sub_atom/5":                engine.SubAtom,
-	"atom_chars/2":              engine.AtomChars,
-	"atom_codes/2":              engine.AtomCodes,
-	"char_code/2":               engine.CharCode,
-	"number_chars/2":            engine.NumberChars,
-	"number_codes/2":            engine.NumberCodes,
-	"set_prolog_flag/2":         engine.SetPrologFlag,
-	"current_prolog_flag/2":     engine.CurrentPrologFlag,
-	"halt/1":                    engine.Halt,
-	"consult/1":                 engine.Consult,
-	"phrase/3":                  engine.Phrase,
-	"expand_term/2":             engine.ExpandTerm,
-	"append/3":                  engine.Append,
-	"length/2":                  engine.Length,
-	"between/3":                 engine.Between,
-	"succ/2":                    engine.Succ,
-	"nth0/3":                    engine.Nth0,
-	"nth1/3":                    engine.Nth1,
-	"call_nth/2":                engine.CallNth,
-	"chain_id/1":                predicate.ChainID,
-	"block_height/1":            predicate.BlockHeight,
-	"block_time/1":              predicate.BlockTime,
-	"bank_balances/2":           predicate.BankBalances,
-	"bank_spendable_balances/2": predicate.BankSpendableBalances,
-	"bank_locked_balances/2":    predicate.BankLockedBalances,
-	"did_components/2":          predicate.DIDComponents,
-	"sha_hash/2":                predicate.SHAHash,
-	"hex_bytes/2":               predicate.HexBytes,
-	"bech32_address/2":          predicate.Bech32Address,
-	"source_file/1":             predicate.SourceFile,
-	"json_prolog/2":             predicate.JSONProlog,
-	"uri_encoded/3":             predicate.URIEncoded,
-	"read_string/3":             predicate.ReadString,
-	"eddsa_verify/4":            predicate.EDDSAVerify,
-	"ecdsa_verify/4":            predicate.ECDSAVerify,
+// registration is a predicate of the registry, along with the keepers it depends on.
+type registration struct {
+	// register registers the predicate in an interpreter under the given atom, calling the given hook and guard before
+	// each of its calls.
+	register func(i *prolog.Interpreter, atom engine.Atom, hook Hook[sdk.Gas], guard Guard)
+	// keepers are the context keys of the keepers the predicate depends on.
+	keepers []types.ContextKey
+	// fn is the fully qualified name of the Go function implementing the predicate.
+	fn string
 }
 
+// registry is a map from predicate names (in the form of "atom/arity") to the registered predicates.
+var registry = map[string]registration{}
+
 // RegistryNames is the list of the predicate names in the Registry.
-var RegistryNames = func() []string {
-	names := make([]string, 0, len(registry))
+var RegistryNames []string
+
+func init() {
+	RegisterPredicate("call/1", engine.Call)
+	RegisterPredicate("catch/3", engine.Catch)
+	RegisterPredicate("throw/1", engine.Throw)
+	RegisterPredicate("=/2", engine.Unify)
+	RegisterPredicate("unify_with_occurs_check/2", engine.UnifyWithOccursCheck)
+	RegisterPredicate("subsumes_term/2", engine.SubsumesTerm)
+	RegisterPredicate("var/1", engine.TypeVar)
+	RegisterPredicate("atom/1", engine.TypeAtom)
+	RegisterPredicate("integer/1", engine.TypeInteger)
+	RegisterPredicate("float/1", engine.TypeFloat)
+	RegisterPredicate("compound/1", engine.TypeCompound)
+	RegisterPredicate("acyclic_term/1", engine.AcyclicTerm)
+	RegisterPredicate("compare/3", engine.Compare)
+	RegisterPredicate("sort/2", engine.Sort)
+	RegisterPredicate("keysort/2", engine.KeySort)
+	RegisterPredicate("msort/2", predicate.MSort)
+	RegisterPredicate("sort/4", predicate.Sort4)
+	RegisterPredicate("predsort/3", predicate.PredSort)
+	RegisterPredicate("empty_assoc/1", predicate.EmptyAssoc)
+	RegisterPredicate("list_to_assoc/2", predicate.ListToAssoc)
+	RegisterPredicate("get_assoc/3", predicate.GetAssoc)
+	RegisterPredicate("put_assoc/4", predicate.PutAssoc)
+	RegisterPredicate("assoc_to_list/2", predicate.AssocToList)
+	RegisterPredicate("assoc_to_keys/2", predicate.AssocToKeys)
+	RegisterPredicate("assoc_to_values/2", predicate.AssocToValues)
+	RegisterPredicate("functor/3", engine.Functor)
+	RegisterPredicate("arg/3", engine.Arg)
+	RegisterPredicate("=../2", engine.Univ)
+	RegisterPredicate("copy_term/2", engine.CopyTerm)
+	RegisterPredicate("term_variables/2", engine.TermVariables)
+	RegisterPredicate("is/2", predicate.Is)
+	RegisterPredicate("=:=/2", deterministicArithmetic(engine.Equal))
+	RegisterPredicate("=\\=/2", deterministicArithmetic(engine.NotEqual))
+	RegisterPredicate("</2", deterministicArithmetic(engine.LessThan))
+	RegisterPredicate("=</2", deterministicArithmetic(engine.LessThanOrEqual))
+	RegisterPredicate(">/2", deterministicArithmetic(engine.GreaterThan))
+	RegisterPredicate(">=/2", deterministicArithmetic(engine.GreaterThanOrEqual))
+	RegisterPredicate("clause/2", engine.Clause)
+	RegisterPredicate("current_predicate/1", engine.CurrentPredicate)
+	RegisterPredicate("asserta/1", engine.Asserta)
+	RegisterPredicate("assertz/1", engine.Assertz)
+	RegisterPredicate("retract/1", engine.Retract)
+	RegisterPredicate("abolish/1", engine.Abolish)
+	RegisterPredicate("findall/3", engine.FindAll)
+	RegisterPredicate("findall/4", predicate.FindAll4)
+	RegisterPredicate("aggregate_all/3", predicate.AggregateAll)
+	RegisterPredicate("bagof/3", engine.BagOf)
+	RegisterPredicate("setof/3", engine.SetOf)
+	RegisterPredicate("current_input/1", engine.CurrentInput)
+	RegisterPredicate("current_output/1", engine.CurrentOutput)
+	RegisterPredicate("set_input/1", engine.SetInput)
+	RegisterPredicate("set_output/1", engine.SetOutput)
+	RegisterPredicate("open/4", predicate.Open)
+	RegisterPredicate("close/2", engine.Close)
+	RegisterPredicate("flush_output/1", engine.FlushOutput)
+	RegisterPredicate("stream_property/2", engine.StreamProperty)
+	RegisterPredicate("set_stream_position/2", engine.SetStreamPosition)
+	RegisterPredicate("get_char/2", engine.GetChar)
+	RegisterPredicate("peek_char/2", engine.PeekChar)
+	RegisterPredicate("put_char/2", engine.PutChar)
+	RegisterPredicate("get_byte/2", engine.GetByte)
+	RegisterPredicate("peek_byte/2", engine.PeekByte)
+	RegisterPredicate("put_byte/2", engine.PutByte)
+	RegisterPredicate("read_term/3", engine.ReadTerm)
+	RegisterPredicate("read_term_from_atom/3", predicate.ReadTermFromAtom)
+	RegisterPredicate("term_to_atom/2", predicate.TermToAtom)
+	RegisterPredicate("reify/2", predicate.Reify)
+	RegisterPredicate("substitute/3", predicate.Substitute)
+	RegisterPredicate("write_term/3", engine.WriteTerm)
+	RegisterPredicate("op/3", engine.Op)
+	RegisterPredicate("current_op/3", engine.CurrentOp)
+	RegisterPredicate("char_conversion/2", engine.CharConversion)
+	RegisterPredicate("current_char_conversion/2", engine.CurrentCharConversion)
+	RegisterPredicate(`\+/1`, engine.Negate)
+	RegisterPredicate("repeat/0", engine.Repeat)
+	RegisterPredicate("call/2", engine.Call1)
+	RegisterPredicate("call/3", engine.Call2)
+	RegisterPredicate("call/4", engine.Call3)
+	RegisterPredicate("call/5", engine.Call4)
+	RegisterPredicate("call/6", engine.Call5)
+	RegisterPredicate("call/7", engine.Call6)
+	RegisterPredicate("call/8", engine.Call7)
+	RegisterPredicate("atom_length/2", engine.AtomLength)
+	RegisterPredicate("atom_concat/3", engine.AtomConcat)
+	RegisterPredicate("sub_atom/5", predicate.SubAtom)
+	RegisterPredicate("sub_atom_icasechk/3", predicate.SubAtomIcasechk)
+	RegisterPredicate("atom_chars/2", engine.AtomChars)
+	RegisterPredicate("atom_codes/2", engine.AtomCodes)
+	RegisterPredicate("char_code/2", engine.CharCode)
+	RegisterPredicate("number_chars/2", engine.NumberChars)
+	RegisterPredicate("number_codes/2", engine.NumberCodes)
+	RegisterPredicate("number_string/2", predicate.NumberString)
+	RegisterPredicate("read_number_radix/3", predicate.ReadNumberRadix)
+	RegisterPredicate("set_prolog_flag/2", engine.SetPrologFlag)
+	RegisterPredicate("current_prolog_flag/2", engine.CurrentPrologFlag)
+	RegisterPredicate("halt/1", engine.Halt)
+	RegisterPredicate("consult/1", engine.Consult)
+	RegisterPredicate("phrase/3", engine.Phrase)
+	RegisterPredicate("phrase_check/2", predicate.PhraseCheck)
+	RegisterPredicate("expand_term/2", engine.ExpandTerm)
+	RegisterPredicate("append/3", engine.Append)
+	RegisterPredicate("length/2", engine.Length)
+	RegisterPredicate("between/3", predicate.Between)
+	RegisterPredicate("between/4", predicate.BetweenStep)
+	RegisterPredicate("succ/2", engine.Succ)
+	RegisterPredicate("nth0/3", engine.Nth0)
+	RegisterPredicate("nth1/3", engine.Nth1)
+	RegisterPredicate("contiguous/3", predicate.Contiguous)
+	RegisterPredicate("first_gap/2", predicate.FirstGap)
+	RegisterPredicate("rle_encode/2", predicate.RLEEncode)
+	RegisterPredicate("rle_decode/2", predicate.RLEDecode)
+	RegisterPredicate("interleave/2", predicate.Interleave)
+	RegisterPredicate("deinterleave/3", predicate.Deinterleave)
+	RegisterPredicate("ord_symdiff/3", predicate.OrdSymdiff)
+	RegisterPredicate("powerset/3", predicate.Powerset)
+	RegisterPredicate("combination/3", predicate.Combination)
+	RegisterPredicate("permutation_k/3", predicate.PermutationK)
+	RegisterPredicate("bin_pack/4", predicate.BinPack)
+	RegisterPredicate("round_robin/3", predicate.RoundRobin)
+	RegisterPredicate("call_nth/2", engine.CallNth)
+	RegisterPredicate("chain_id/1", predicate.ChainID)
+	RegisterPredicate("block_height/1", predicate.BlockHeight)
+	RegisterPredicate("block_time/1", predicate.BlockTime)
+	RegisterPredicate("comet_header_hash/2", predicate.CometHeaderHash)
+	RegisterPredicate("comet_verify_commit/4", predicate.CometVerifyCommit)
+	RegisterPredicate("canonical_vote_bytes/2", predicate.CanonicalVoteBytes)
+	RegisterPredicate("tendermint_verify/4", predicate.TendermintVerify)
+	RegisterPredicate("bank_balances/2", predicate.BankBalances, types.BankKeeperContextKey)
+	RegisterPredicate("bank_spendable_balances/2", predicate.BankSpendableBalances, types.BankKeeperContextKey)
+	RegisterPredicate("bank_locked_balances/2", predicate.BankLockedBalances, types.BankKeeperContextKey)
+	RegisterPredicate("staking_delegations/2", predicate.StakingDelegations, types.StakingKeeperContextKey)
+	RegisterPredicate("gov_proposal/2", predicate.GovProposal, types.GovKeeperContextKey)
+	RegisterPredicate("smart_query/3", predicate.SmartQuery, types.WasmKeeperContextKey)
+	RegisterPredicate("consult_program/1", predicate.ConsultProgram, types.WasmKeeperContextKey)
+	RegisterPredicate("coins_delta/3", predicate.CoinsDelta)
+	RegisterPredicate("bignum_add/3", predicate.BignumAdd)
+	RegisterPredicate("bignum_sub/3", predicate.BignumSub)
+	RegisterPredicate("bignum_mul/3", predicate.BignumMul)
+	RegisterPredicate("bignum_div/4", predicate.BignumDiv)
+	RegisterPredicate("bignum_cmp/3", predicate.BignumCmp)
+	RegisterPredicate("mod_pow/4", predicate.ModPow)
+	RegisterPredicate("dec_add/3", predicate.DecAdd)
+	RegisterPredicate("dec_mul/3", predicate.DecMul)
+	RegisterPredicate("dec_div/3", predicate.DecDiv)
+	RegisterPredicate("dec_cmp/3", predicate.DecCmp)
+	RegisterPredicate("parse_coin/2", predicate.ParseCoin)
+	RegisterPredicate("format_coin/2", predicate.FormatCoin)
+	RegisterPredicate("canonicalize_positions/2", predicate.CanonicalizePositions)
+	RegisterPredicate("iban_valid/1", predicate.IBANValid)
+	RegisterPredicate("iban_components/4", predicate.IBANComponents)
+	RegisterPredicate("phone_e164/3", predicate.PhoneE164)
+	RegisterPredicate("did_components/2", predicate.DIDComponents)
+	RegisterPredicate("sha_hash/2", predicate.SHAHash)
+	RegisterPredicate("sha3_hash/3", predicate.SHA3Hash)
+	RegisterPredicate("blake2b/3", predicate.Blake2b)
+	RegisterPredicate("hash_equal/2", predicate.HashEqual)
+	RegisterPredicate("crc32/3", predicate.CRC32)
+	RegisterPredicate("crc64/3", predicate.CRC64)
+	RegisterPredicate("hash_bucket_percent/2", predicate.HashBucketPercent)
+	RegisterPredicate("deterministic_random/3", predicate.DeterministicRandom)
+	RegisterPredicate("deterministic_random_permutation/3", predicate.DeterministicRandomPermutation)
+	RegisterPredicate("crypto_random_bytes/3", predicate.CryptoRandomBytes)
+	RegisterPredicate("hex_bytes/2", predicate.HexBytes)
+	RegisterPredicate("hex_bytes/3", predicate.HexBytesWithOptions)
+	RegisterPredicate("bytes_hex/2", predicate.BytesHex)
+	RegisterPredicate("hex_bytes_atom/2", predicate.HexBytesAtom)
+	RegisterPredicate("base64url_bytes/2", predicate.Base64URLBytes)
+	RegisterPredicate("base64url_bytes/3", predicate.Base64URLBytesWithOptions)
+	RegisterPredicate("bech32_address/2", predicate.Bech32Address)
+	RegisterPredicate("pubkey_address/3", predicate.PubkeyAddress)
+	RegisterPredicate("source_file/1", predicate.SourceFile)
+	RegisterPredicate("json_prolog/2", predicate.JSONProlog)
+	RegisterPredicate("encoded_length/3", predicate.EncodedLength)
+	RegisterPredicate("json_read/3", predicate.JSONRead)
+	RegisterPredicate("json_get/3", predicate.JSONGet)
+	RegisterPredicate("json_sort_by/4", predicate.JSONSortBy)
+	RegisterPredicate("json_canonical/2", predicate.JSONCanonical)
+	RegisterPredicate("cbor_prolog/2", predicate.CBORProlog)
+	RegisterPredicate("cbor_bytes/2", predicate.CBORBytes)
+	RegisterPredicate("uri_encoded/3", predicate.URIEncoded)
+	RegisterPredicate("uri_components/2", predicate.URIComponents)
+	RegisterPredicate("read_string/3", predicate.ReadString)
+	RegisterPredicate("parse_by_template/4", predicate.ParseByTemplate)
+	RegisterPredicate("string_concat/3", predicate.StringConcat)
+	RegisterPredicate("atomic_list_concat/3", predicate.AtomicListConcat)
+	RegisterPredicate("format_atom/3", predicate.FormatAtom)
+	RegisterPredicate("split_string/4", predicate.SplitString)
+	RegisterPredicate("string_lower/2", predicate.StringLower)
+	RegisterPredicate("string_upper/2", predicate.StringUpper)
+	RegisterPredicate("utf8_bytes/2", predicate.UTF8Bytes)
+	RegisterPredicate("char_type/2", predicate.CharType)
+	RegisterPredicate("re_match/3", predicate.ReMatch)
+	RegisterPredicate("re_matchsub/4", predicate.ReMatchSub)
+	RegisterPredicate("re_replace/4", predicate.ReReplace)
+	RegisterPredicate("eddsa_verify/4", predicate.EDDSAVerify)
+	RegisterPredicate("eddsa_verify_batch/2", predicate.EDDSAVerifyBatch)
+	RegisterPredicate("openssh_pubkey/3", predicate.OpenSSHPubKey)
+	RegisterPredicate("sshsig_verify/4", predicate.SSHSigVerify)
+	RegisterPredicate("ecdsa_verify/4", predicate.ECDSAVerify)
+	RegisterPredicate("rsa_verify/4", predicate.RSAVerify)
+	RegisterPredicate("jwt_verify/3", predicate.JWTVerify)
+	RegisterPredicate("cose_sign1_verify/3", predicate.COSESign1Verify)
+	RegisterPredicate("signed_token_verify/4", predicate.SignedTokenVerify)
+	RegisterPredicate("verify_any/5", predicate.VerifyAny)
+	RegisterPredicate("eth_verify_address/3", predicate.EthVerifyAddress)
+	RegisterPredicate("pubkey_normalize/3", predicate.PubkeyNormalize)
+	RegisterPredicate("permissions_decode/3", predicate.PermissionsDecode)
+	RegisterPredicate("permissions_encode/3", predicate.PermissionsEncode)
+	RegisterPredicate("accumulator_empty/1", predicate.AccumulatorEmpty)
+	RegisterPredicate("accumulator_add/3", predicate.AccumulatorAdd)
+	RegisterPredicate("accumulator_contains/2", predicate.AccumulatorContains)
+	RegisterPredicate("merkle_file_root/3", predicate.MerkleFileRoot)
+	RegisterPredicate("pow_verify/4", predicate.PowVerify)
+	RegisterPredicate("pow_leading_zeros/2", predicate.PowLeadingZeros)
+	RegisterPredicate("rbac_allowed/4", predicate.RBACAllowed)
+	RegisterPredicate("abac_allowed/2", predicate.ABACAllowed)
+}
+
+// deterministicArithmetic returns the given arithmetic comparison, refusing the floats (see predicate.Is).
+func deterministicArithmetic(
+	p engine.Predicate2,
+) func(*engine.VM, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise {
+	return predicate.DeterministicArithmetic(p)
+}
+
+// RegisterPredicate registers a predicate in the registry, so that it can be made available to the interpreters, along
+// with the keepers it depends on.
+// name is the name of the predicate in the form of "atom/arity".
+// p is the predicate, as a function of the engine taking as many terms as the arity of the predicate.
+// keepers are the context keys under which the keepers the predicate depends on are provided (see ProvideKeepers): a
+// predicate reading the state of a module declares the keeper of this module, while the other ones declare none.
+//
+// It panics if the name is invalid or already registered, or if the predicate does not have the given arity, as the
+// registry is built at the initialization of the program.
+//
+//nolint:lll,cyclop
+func RegisterPredicate(name string, p any, keepers ...types.ContextKey) {
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("predicate already registered: %s", name))
+	}
+	idx := strings.LastIndex(name, "/")
+	if idx <= 0 {
+		panic(fmt.Sprintf("invalid name: %s", name))
+	}
+	arity, err := strconv.Atoi(name[idx+1:])
+	if err != nil {
+		panic(fmt.Sprintf("invalid name: %s", name))
+	}
+
+	var register func(i *prolog.Interpreter, atom engine.Atom, hook Hook[sdk.Gas], guard Guard)
+	switch p := p.(type) {
+	case func(*engine.VM, engine.Cont, *engine.Env) *engine.Promise:
+		register = func(i *prolog.Interpreter, atom engine.Atom, hook Hook[sdk.Gas], guard Guard) {
+			i.Register0(atom, Instrument0(hook, Guard0(guard, p)))
+		}
+	case func(*engine.VM, engine.Term, engine.Cont, *engine.Env) *engine.Promise:
+		register = func(i *prolog.Interpreter, atom engine.Atom, hook Hook[sdk.Gas], guard Guard) {
+			i.Register1(atom, Instrument1(hook, Guard1(guard, p)))
+		}
+	case func(*engine.VM, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise:
+		register = func(i *prolog.Interpreter, atom engine.Atom, hook Hook[sdk.Gas], guard Guard) {
+			i.Register2(atom, Instrument2(hook, Guard2(guard, p)))
+		}
+	case func(*engine.VM, engine.Term, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise:
+		register = func(i *prolog.Interpreter, atom engine.Atom, hook Hook[sdk.Gas], guard Guard) {
+			i.Register3(atom, Instrument3(hook, Guard3(guard, p)))
+		}
+	case func(*engine.VM, engine.Term, engine.Term, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise:
+		register = func(i *prolog.Interpreter, atom engine.Atom, hook Hook[sdk.Gas], guard Guard) {
+			i.Register4(atom, Instrument4(hook, Guard4(guard, p)))
+		}
+	case func(*engine.VM, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise:
+		register = func(i *prolog.Interpreter, atom engine.Atom, hook Hook[sdk.Gas], guard Guard) {
+			i.Register5(atom, Instrument5(hook, Guard5(guard, p)))
+		}
+	case func(*engine.VM, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise:
+		register = func(i *prolog.Interpreter, atom engine.Atom, hook Hook[sdk.Gas], guard Guard) {
+			i.Register6(atom, Instrument6(hook, Guard6(guard, p)))
+		}
+	case func(*engine.VM, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise:
+		register = func(i *prolog.Interpreter, atom engine.Atom, hook Hook[sdk.Gas], guard Guard) {
+			i.Register7(atom, Instrument7(hook, Guard7(guard, p)))
+		}
+	case func(*engine.VM, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise:
+		register = func(i *prolog.Interpreter, atom engine.Atom, hook Hook[sdk.Gas], guard Guard) {
+			i.Register8(atom, Instrument8(hook, Guard8(guard, p)))
+		}
+	default:
+		panic(fmt.Sprintf("unsupported predicate: %s", name))
+	}
+	if reflect.TypeOf(p).NumIn()-3 != arity {
+		panic(fmt.Sprintf("invalid arity: %s", name))
+	}
 
-	for name := range registry {
-		names = append(names, name)
+	registry[name] = registration{
+		register: register,
+		keepers:  keepers,
+		fn:       runtime.FuncForPC(reflect.ValueOf(p).Pointer()).Name(),
 	}
-	return names
-}()
+	RegistryNames = append(RegistryNames, name)
+}
 
 // Register registers a well-known predicate in the interpreter with support for consumption measurement.
 // name is the name of the predicate in the form of "atom/arity".
 // cost is the cost of executing the predicate.
 // meter is the gas meter object that is called when the predicate is called and which allows to count the cost of
 // executing the predicate(ctx).
+// depth is the limit of the depth of the derivations the predicate is counted in, or nil for no limit.
+func Register(i *prolog.Interpreter, name string, cost uint64, meter sdk.GasMeter, depth *DepthLimit) error {
+	r, ok := registry[name]
+	if !ok {
+		return fmt.Errorf("unknown predicate %s", name)
+	}
+
+	hook := func() sdk.Gas {
+		meter.ConsumeGas(cost, fmt.Sprintf("predicate %s", name))
+
+		return meter.GasRemaining()
+	}
+	idx := strings.LastIndex(name, "/")
+	atom := engine.NewAtom(name[:idx])
+	arity, _ := strconv.Atoi(name[idx+1:])
+	r.register(i, atom, hook, depth.Guard(engine.NewAtom("/").Apply(atom, engine.Integer(arity))))
+
+	return nil
+}
+
+// PredicateKeepers returns the context keys of the keepers the registered predicate of the given name depends on.
+func PredicateKeepers(name string) ([]types.ContextKey, error) {
+	r, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown predicate %s", name)
+	}
+
+	return r.keepers, nil
+}
+
+// PredicateFunc returns the fully qualified name of the Go function implementing the registered predicate of the given
+// name, e.g. "github.com/okp4/okp4d/x/logic/predicate.SHAHash" for "sha_hash/2".
+func PredicateFunc(name string) (string, error) {
+	r, ok := registry[name]
+	if !ok {
+		return "", fmt.Errorf("unknown predicate %s", name)
+	}
+
+	return r.fn, nil
+}
+
+// Forbid replaces in the interpreter the well-known predicate of the given name by a predicate raising a
+// permission_error(execute, procedure, Name/Arity) exception when called, so that the programs are not permitted to
+// call it.
+// name is the name of the predicate in the form of "atom/arity".
+// cost is the cost of calling the predicate, consumed from the given meter as for the permitted predicates (see
+// Register), so that calling a forbidden predicate is not cheaper than calling a permitted one.
 //
 //nolint:lll
-func Register(i *prolog.Interpreter, name string, cost uint64, meter sdk.GasMeter) error {
-	if p, ok := registry[name]; ok {
-		parts := strings.Split(name, "/")
-		if len(parts) == 2 {
-			atom := engine.NewAtom(parts[0])
-			arity, err := strconv.Atoi(parts[1])
-			if err != nil {
-				return err
-			}
-
-			hook := func() sdk.Gas {
-				meter.ConsumeGas(cost, fmt.Sprintf("predicate %s", name))
-
-				return meter.GasRemaining()
-			}
-
-			switch arity {
-			case 0:
-				i.Register0(atom, Instrument0(hook, p.(func(*engine.VM, engine.Cont, *engine.Env) *engine.Promise)))
-			case 1:
-				i.Register1(atom, Instrument1(hook, p.(func(*engine.VM, engine.Term, engine.Cont, *engine.Env) *engine.Promise)))
-			case 2:
-				i.Register2(atom, Instrument2(hook, p.(func(*engine.VM, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise)))
-			case 3:
-				i.Register3(atom, Instrument3(hook, p.(func(*engine.VM, engine.Term, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise)))
-			case 4:
-				i.Register4(atom, Instrument4(hook, p.(func(*engine.VM, engine.Term, engine.Term, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise)))
-			case 5:
-				i.Register5(atom, Instrument5(hook, p.(func(*engine.VM, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise)))
-			case 6:
-				i.Register6(atom, Instrument6(hook, p.(func(*engine.VM, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise)))
-			case 7:
-				i.Register7(atom, Instrument7(hook, p.(func(*engine.VM, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise)))
-			case 8:
-				i.Register8(atom, Instrument8(hook, p.(func(*engine.VM, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Term, engine.Cont, *engine.Env) *engine.Promise)))
-			default:
-				panic(fmt.Sprintf("unsupported arity: %s", name))
-			}
-		} else {
-			panic(fmt.Sprintf("invalid name: %s", name))
-		}
+func Forbid(i *prolog.Interpreter, name string, cost uint64, meter sdk.GasMeter) error {
+	if _, ok := registry[name]; !ok {
+		return fmt.Errorf("unknown predicate %s", name)
+	}
+	idx := strings.LastIndex(name, "/")
+	atom := engine.NewAtom(name[:idx])
+	arity, _ := strconv.Atoi(name[idx+1:])
+
+	hook := func() sdk.Gas {
+		meter.ConsumeGas(cost, fmt.Sprintf("predicate %s", name))
 
-		return nil
+		return meter.GasRemaining()
+	}
+	pi := engine.NewAtom("/").Apply(atom, engine.Integer(arity))
+	forbidden := func(env *engine.Env) *engine.Promise {
+		formal := engine.NewAtom("permission_error").Apply(engine.NewAtom("execute"), engine.NewAtom("procedure"), pi)
+		return engine.Error(engine.NewException(engine.NewAtom("error").Apply(formal, pi), env))
+	}
+	switch arity {
+	case 0:
+		i.Register0(atom, Instrument0(hook, func(_ *engine.VM, _ engine.Cont, env *engine.Env) *engine.Promise {
+			return forbidden(env)
+		}))
+	case 1:
+		i.Register1(atom, Instrument1(hook, func(_ *engine.VM, _ engine.Term, _ engine.Cont, env *engine.Env) *engine.Promise {
+			return forbidden(env)
+		}))
+	case 2:
+		i.Register2(atom, Instrument2(hook, func(_ *engine.VM, _, _ engine.Term, _ engine.Cont, env *engine.Env) *engine.Promise {
+			return forbidden(env)
+		}))
+	case 3:
+		i.Register3(atom, Instrument3(hook, func(_ *engine.VM, _, _, _ engine.Term, _ engine.Cont, env *engine.Env) *engine.Promise {
+			return forbidden(env)
+		}))
+	case 4:
+		i.Register4(atom, Instrument4(hook, func(_ *engine.VM, _, _, _, _ engine.Term, _ engine.Cont, env *engine.Env) *engine.Promise {
+			return forbidden(env)
+		}))
+	case 5:
+		i.Register5(atom, Instrument5(hook, func(_ *engine.VM, _, _, _, _, _ engine.Term, _ engine.Cont, env *engine.Env) *engine.Promise {
+			return forbidden(env)
+		}))
+	case 6:
+		i.Register6(atom, Instrument6(hook, func(_ *engine.VM, _, _, _, _, _, _ engine.Term, _ engine.Cont, env *engine.Env) *engine.Promise {
+			return forbidden(env)
+		}))
+	case 7:
+		i.Register7(atom, Instrument7(hook, func(_ *engine.VM, _, _, _, _, _, _, _ engine.Term, _ engine.Cont, env *engine.Env) *engine.Promise {
+			return forbidden(env)
+		}))
+	case 8:
+		i.Register8(atom, Instrument8(hook, func(_ *engine.VM, _, _, _, _, _, _, _, _ engine.Term, _ engine.Cont, env *engine.Env) *engine.Promise {
+			return forbidden(env)
+		}))
 	}
 
-	return fmt.Errorf("unknown predicate %s", name)
+	return nil
 }
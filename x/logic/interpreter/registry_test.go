@@ -0,0 +1,102 @@
+package interpreter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ichiban/prolog"
+	"github.com/ichiban/prolog/engine"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRegistryNames(t *testing.T) {
+	Convey("Given the predicates of the registry before its migration to RegisterPredicate", t, func() {
+		names := []string{
+			"call/1", "catch/3", "throw/1", "=/2", "unify_with_occurs_check/2", "subsumes_term/2", "var/1", "atom/1",
+			"integer/1", "float/1", "compound/1", "acyclic_term/1", "compare/3", "sort/2", "keysort/2", "functor/3",
+			"arg/3", "=../2", "copy_term/2", "term_variables/2", "is/2", "=:=/2", "=\\=/2", "</2", "=</2", ">/2",
+			">=/2", "clause/2", "current_predicate/1", "asserta/1", "assertz/1", "retract/1", "abolish/1", "findall/3",
+			"bagof/3", "setof/3", "current_input/1", "current_output/1", "set_input/1", "set_output/1", "open/4",
+			"close/2", "flush_output/1", "stream_property/2", "set_stream_position/2", "get_char/2", "peek_char/2",
+			"put_char/2", "get_byte/2", "peek_byte/2", "put_byte/2", "read_term/3", "write_term/3", "op/3",
+			"current_op/3", "char_conversion/2", "current_char_conversion/2", `\+/1`, "repeat/0", "call/2", "call/3",
+			"call/4", "call/5", "call/6", "call/7", "call/8", "atom_length/2", "atom_concat/3", "sub_atom/5",
+			"atom_chars/2", "atom_codes/2", "char_code/2", "number_chars/2", "number_codes/2", "set_prolog_flag/2",
+			"current_prolog_flag/2", "halt/1", "consult/1", "phrase/3", "expand_term/2", "append/3", "length/2",
+			"between/3", "succ/2", "nth0/3", "nth1/3", "call_nth/2", "chain_id/1", "block_height/1", "block_time/1",
+			"bank_balances/2", "bank_spendable_balances/2", "bank_locked_balances/2", "did_components/2", "sha_hash/2",
+			"hex_bytes/2", "bech32_address/2", "source_file/1", "json_prolog/2", "uri_encoded/3", "read_string/3",
+			"eddsa_verify/4", "ecdsa_verify/4",
+		}
+
+		for _, name := range names {
+			Convey("When looking for the predicate "+name, func() {
+				Convey("Then it should still be registered", func() {
+					So(RegistryNames, ShouldContain, name)
+					So(registry, ShouldContainKey, name)
+				})
+			})
+		}
+	})
+}
+
+func TestPredicateFunc(t *testing.T) {
+	Convey("Given a registered predicate", t, func() {
+		Convey("When looking for the function implementing it", func() {
+			fn, err := PredicateFunc("sha_hash/2")
+
+			Convey("Then it should return its fully qualified name", func() {
+				So(err, ShouldBeNil)
+				So(fn, ShouldEqual, "github.com/okp4/okp4d/x/logic/predicate.SHAHash")
+			})
+		})
+	})
+
+	Convey("Given an unknown predicate", t, func() {
+		Convey("When looking for the function implementing it", func() {
+			_, err := PredicateFunc("foo/1")
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldBeError, "unknown predicate foo/1")
+			})
+		})
+	})
+}
+
+func TestForbid(t *testing.T) {
+	Convey("Given an interpreter with a forbidden predicate", t, func() {
+		meter := sdk.NewGasMeter(1000)
+		i := prolog.New(nil, nil)
+		So(Forbid(i, "sha_hash/2", 42, meter), ShouldBeNil)
+
+		Convey("When the forbidden predicate is called", func() {
+			sol := i.QuerySolution("sha_hash(foo, X).")
+
+			Convey("Then it should raise a permission error", func() {
+				var exception engine.Exception
+				So(errors.As(sol.Err(), &exception), ShouldBeTrue)
+
+				pi := engine.NewAtom("/").Apply(engine.NewAtom("sha_hash"), engine.Integer(2))
+				So(exception.Term(), ShouldResemble, engine.NewAtom("error").Apply(
+					engine.NewAtom("permission_error").Apply(engine.NewAtom("execute"), engine.NewAtom("procedure"), pi), pi))
+			})
+
+			Convey("Then it should consume the cost of the predicate", func() {
+				So(meter.GasConsumed(), ShouldEqual, 42)
+			})
+		})
+	})
+
+	Convey("Given an unknown predicate", t, func() {
+		Convey("When forbidding it", func() {
+			err := Forbid(prolog.New(nil, nil), "foo/1", 42, sdk.NewGasMeter(1000))
+
+			Convey("Then it should return an error", func() {
+				So(err, ShouldBeError, "unknown predicate foo/1")
+			})
+		})
+	})
+}
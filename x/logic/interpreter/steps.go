@@ -0,0 +1,56 @@
+package interpreter
+
+import (
+	goctx "context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrStepBudgetExceeded is returned when the execution of the interpreter exceeds its budget of steps.
+var ErrStepBudgetExceeded = errors.New("step budget exceeded")
+
+// closedChan is a channel that is already closed, used to signal the exhaustion of the budget of steps.
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// stepBudgetContext is a context.Context which is cancelled once its budget of steps is exhausted.
+//
+// The interpreter checks whether its context is done before resolving each of its promises, so each call to Done is
+// counted as an execution step. As the number of steps only depends on the program being executed, and not on the
+// time it takes, the cancellation is deterministic.
+type stepBudgetContext struct {
+	goctx.Context
+	budget uint64
+	steps  atomic.Uint64
+}
+
+// NewStepBudgetContext returns a new context.Context derived from the given one, which is cancelled with the
+// ErrStepBudgetExceeded error once the interpreter executing with it has performed more than the given number of steps.
+func NewStepBudgetContext(ctx goctx.Context, budget uint64) goctx.Context {
+	return &stepBudgetContext{
+		Context: ctx,
+		budget:  budget,
+	}
+}
+
+// Done counts an execution step and returns a closed channel if the budget of steps is exhausted, or the channel of
+// the parent context otherwise.
+func (c *stepBudgetContext) Done() <-chan struct{} {
+	if c.steps.Add(1) > c.budget {
+		return closedChan
+	}
+
+	return c.Context.Done()
+}
+
+// Err returns ErrStepBudgetExceeded if the budget of steps is exhausted, or the error of the parent context otherwise.
+func (c *stepBudgetContext) Err() error {
+	if c.steps.Load() > c.budget {
+		return ErrStepBudgetExceeded
+	}
+
+	return c.Context.Err()
+}
@@ -12,8 +12,6 @@ import (
 )
 
 func (k Keeper) Ask(ctx goctx.Context, req *types.QueryServiceAskRequest) (response *types.QueryServiceAskResponse, err error) {
-	sdkCtx := sdk.UnwrapSDKContext(ctx)
-
 	if req == nil {
 		return nil, errorsmod.Wrap(types.InvalidArgument, "request is nil")
 	}
@@ -23,7 +21,15 @@ func (k Keeper) Ask(ctx goctx.Context, req *types.QueryServiceAskRequest) (respo
 		return nil, err
 	}
 
-	sdkCtx = withGasMeter(sdkCtx, limits)
+	return k.executeWithGasLimit(ctx, req.Program, req.Query, req.Offset, req.Limit, limits.MaxGas.Uint64())
+}
+
+// executeWithGasLimit executes the given query (see execute) with a gas meter that has the given limit, reporting the
+// exhaustion of the gas as a limit exceeded error.
+func (k Keeper) executeWithGasLimit(
+	ctx goctx.Context, program, query string, offset, limit, gasLimit uint64,
+) (response *types.QueryServiceAskResponse, err error) {
+	sdkCtx := withGasMeter(sdk.UnwrapSDKContext(ctx), gasLimit)
 	defer func() {
 		if r := recover(); r != nil {
 			if gasError, ok := r.(sdk.ErrorOutOfGas); ok {
@@ -42,14 +48,16 @@ func (k Keeper) Ask(ctx goctx.Context, req *types.QueryServiceAskRequest) (respo
 	//nolint:contextcheck
 	return k.execute(
 		sdkCtx,
-		req.Program,
-		req.Query)
+		program,
+		query,
+		offset,
+		limit)
 }
 
 // withGasMeter returns a new context with a gas meter that has the given limit.
 // The gas meter is go-router-safe.
-func withGasMeter(sdkCtx sdk.Context, limits types.Limits) sdk.Context {
-	gasMeter := meter.WithSafeMeter(sdk.NewGasMeter(limits.MaxGas.Uint64()))
+func withGasMeter(sdkCtx sdk.Context, limit uint64) sdk.Context {
+	gasMeter := meter.WithSafeMeter(sdk.NewGasMeter(limit))
 
 	return sdkCtx.WithGasMeter(gasMeter)
 }
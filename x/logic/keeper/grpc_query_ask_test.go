@@ -7,9 +7,12 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/samber/lo"
 
 	. "github.com/smartystreets/goconvey/convey"
 
+	sdkmath "cosmossdk.io/math"
+
 	"github.com/cosmos/cosmos-sdk/baseapp"
 	storetypes "github.com/cosmos/cosmos-sdk/store/types"
 	"github.com/cosmos/cosmos-sdk/testutil"
@@ -26,10 +29,20 @@ import (
 func TestGRPCAsk(t *testing.T) {
 	Convey("Given a test cases", t, func() {
 		cases := []struct {
-			program        string
-			query          string
-			expectedAsnwer *types.Answer
-			expectedError  bool
+			program           string
+			query             string
+			algorithmCosts    []types.AlgorithmCost
+			maxInputSize      *sdkmath.Uint
+			maxSteps          *sdkmath.Uint
+			maxCollectionSize *sdkmath.Uint
+			maxDepth          *sdkmath.Uint
+			maxResultCount    *sdkmath.Uint
+			limit             uint64
+			offset            uint64
+			permissions       types.Filter
+			expectedAsnwer    *types.Answer
+			expectedError     bool
+			errorContains     string
 		}{
 			{
 				program: "father(bob, alice).",
@@ -65,6 +78,29 @@ func TestGRPCAsk(t *testing.T) {
 				},
 				expectedError: false,
 			},
+			{
+				program: "owns(bob, token(nft, [1, 2])). owns(alice, 'a coin').",
+				query:   "owns(Who, What).",
+				expectedAsnwer: &types.Answer{
+					Success:   true,
+					HasMore:   true,
+					Variables: []string{"What", "Who"},
+					Results: []types.Result{{Substitutions: []types.Substitution{{
+						Variable: "What",
+						Term: types.Term{
+							Name:      "token(nft,[1,2])",
+							Arguments: nil,
+						},
+					}, {
+						Variable: "Who",
+						Term: types.Term{
+							Name:      "bob",
+							Arguments: nil,
+						},
+					}}}},
+				},
+				expectedError: false,
+			},
 			{
 				program: "father(bob, alice).",
 				query:   "father(bob, john).",
@@ -76,12 +112,294 @@ func TestGRPCAsk(t *testing.T) {
 				},
 				expectedError: false,
 			},
+			{
+				program: "father(bob, alice).",
+				query:   "father(bob, X), (X \\= alice ; fail).",
+				expectedAsnwer: &types.Answer{
+					Success:   false,
+					HasMore:   false,
+					Variables: nil,
+					Results:   nil,
+				},
+				expectedError: false,
+			},
 			{
 				program:        "father(bob, alice).",
 				query:          "father(bob, X, O).",
 				expectedAsnwer: nil,
 				expectedError:  true,
 			},
+			{
+				query: "sha_hash(foo, Hash).",
+				algorithmCosts: []types.AlgorithmCost{
+					{Algorithm: "sha256", BaseCost: lo.ToPtr(sdkmath.NewUint(1000000))},
+				},
+				expectedAsnwer: nil,
+				expectedError:  true,
+				errorContains:  "out of gas: logic <Prolog interpreter execution>",
+			},
+			{
+				query:          "sha_hash(foobar, Hash).",
+				maxInputSize:   lo.ToPtr(sdkmath.NewUint(3)),
+				expectedAsnwer: nil,
+				expectedError:  true,
+				errorContains:  "sha_hash/2: input exceeds the maximum size of 3 bytes",
+			},
+			{
+				query:             "powerset([a, b, c], Subsets, []).",
+				maxCollectionSize: lo.ToPtr(sdkmath.NewUint(4)),
+				expectedAsnwer:    nil,
+				expectedError:     true,
+				errorContains:     "powerset/3: collection of 8 elements exceeds the maximum size of 4 elements",
+			},
+			{
+				program:  "father(bob, alice).",
+				query:    "father(bob, X).",
+				maxSteps: lo.ToPtr(sdkmath.NewUint(1000)),
+				expectedAsnwer: &types.Answer{
+					Success:   true,
+					HasMore:   false,
+					Variables: []string{"X"},
+					Results: []types.Result{{Substitutions: []types.Substitution{{
+						Variable: "X",
+						Term: types.Term{
+							Name:      "alice",
+							Arguments: nil,
+						},
+					}}}},
+				},
+				expectedError: false,
+			},
+			{
+				program:        "loop :- loop.",
+				query:          "loop.",
+				maxSteps:       lo.ToPtr(sdkmath.NewUint(1000)),
+				expectedAsnwer: nil,
+				expectedError:  true,
+				errorContains:  "error interpreting solutions: step budget exceeded (MaxSteps: 1000): limit exceeded",
+			},
+			{
+				program:        ":- initialization(loop). loop :- loop.",
+				query:          "true.",
+				maxSteps:       lo.ToPtr(sdkmath.NewUint(1000)),
+				expectedAsnwer: nil,
+				expectedError:  true,
+				errorContains:  "step budget exceeded (MaxSteps: 1000): limit exceeded",
+			},
+			{
+				program:        "p(1). p(2). p(3). p(4).",
+				query:          "p(X).",
+				maxResultCount: lo.ToPtr(sdkmath.NewUint(3)),
+				limit:          2,
+				offset:         1,
+				expectedAsnwer: &types.Answer{
+					Success:   true,
+					HasMore:   true,
+					Variables: []string{"X"},
+					Results: []types.Result{{Substitutions: []types.Substitution{{
+						Variable: "X",
+						Term: types.Term{
+							Name:      "2",
+							Arguments: nil,
+						},
+					}}}, {Substitutions: []types.Substitution{{
+						Variable: "X",
+						Term: types.Term{
+							Name:      "3",
+							Arguments: nil,
+						},
+					}}}},
+				},
+				expectedError: false,
+			},
+			{
+				program:        "p(1). p(2). p(3). p(4).",
+				query:          "p(X).",
+				maxResultCount: lo.ToPtr(sdkmath.NewUint(3)),
+				offset:         3,
+				expectedAsnwer: &types.Answer{
+					Success:   true,
+					HasMore:   false,
+					Variables: []string{"X"},
+					Results: []types.Result{{Substitutions: []types.Substitution{{
+						Variable: "X",
+						Term: types.Term{
+							Name:      "4",
+							Arguments: nil,
+						},
+					}}}},
+				},
+				expectedError: false,
+			},
+			{
+				program:        "p(1). p(2). p(3). p(4).",
+				query:          "p(X).",
+				maxResultCount: lo.ToPtr(sdkmath.NewUint(3)),
+				offset:         10,
+				expectedAsnwer: &types.Answer{
+					Success: false,
+					HasMore: false,
+				},
+				expectedError: false,
+			},
+			{
+				program:        "p(1). p(2). p(3). p(4).",
+				query:          "p(X).",
+				maxResultCount: lo.ToPtr(sdkmath.NewUint(3)),
+				limit:          5,
+				expectedAsnwer: nil,
+				expectedError:  true,
+				errorContains:  "limit: 5 > MaxResultCount: 3: limit exceeded",
+			},
+			{
+				program:  "loop(N) :- M is N + 1, loop(M).",
+				query:    "catch(loop(0), error(E, _), true).",
+				maxDepth: lo.ToPtr(sdkmath.NewUint(1000)),
+				expectedAsnwer: &types.Answer{
+					Success:   true,
+					HasMore:   false,
+					Variables: []string{"E"},
+					Results: []types.Result{{Substitutions: []types.Substitution{{
+						Variable: "E",
+						Term: types.Term{
+							Name:      "resource_error(stack)",
+							Arguments: nil,
+						},
+					}}}},
+				},
+				expectedError: false,
+			},
+			{
+				program:  "loop :- loop.",
+				query:    "catch(loop, error(E, _), true).",
+				maxDepth: lo.ToPtr(sdkmath.NewUint(1000)),
+				expectedAsnwer: &types.Answer{
+					Success:   true,
+					HasMore:   false,
+					Variables: []string{"E"},
+					Results: []types.Result{{Substitutions: []types.Substitution{{
+						Variable: "E",
+						Term: types.Term{
+							Name:      "resource_error(stack)",
+							Arguments: nil,
+						},
+					}}}},
+				},
+				expectedError: false,
+			},
+			{
+				program:  "p :- p, q. q.",
+				query:    "catch(p, error(E, p/0), true).",
+				maxDepth: lo.ToPtr(sdkmath.NewUint(1000)),
+				expectedAsnwer: &types.Answer{
+					Success:   true,
+					HasMore:   false,
+					Variables: []string{"E"},
+					Results: []types.Result{{Substitutions: []types.Substitution{{
+						Variable: "E",
+						Term: types.Term{
+							Name:      "resource_error(stack)",
+							Arguments: nil,
+						},
+					}}}},
+				},
+				expectedError: false,
+			},
+			{
+				program:  "g --> g.",
+				query:    "catch(phrase(g, []), error(E, g/2), true).",
+				maxDepth: lo.ToPtr(sdkmath.NewUint(1000)),
+				expectedAsnwer: &types.Answer{
+					Success:   true,
+					HasMore:   false,
+					Variables: []string{"E"},
+					Results: []types.Result{{Substitutions: []types.Substitution{{
+						Variable: "E",
+						Term: types.Term{
+							Name:      "resource_error(stack)",
+							Arguments: nil,
+						},
+					}}}},
+				},
+				expectedError: false,
+			},
+			{
+				program:        "term_expansion(_, foo).",
+				query:          "foo.",
+				maxDepth:       lo.ToPtr(sdkmath.NewUint(1000)),
+				expectedAsnwer: nil,
+				expectedError:  true,
+				errorContains:  "permission_error(modify,static_procedure,term_expansion/2)",
+			},
+			{
+				program:  "count(N, N) :- !. count(N, M) :- N1 is N + 1, count(N1, M).",
+				query:    "count(0, 100), between(1, 2000, X), X >= 2000.",
+				maxDepth: lo.ToPtr(sdkmath.NewUint(1000)),
+				expectedAsnwer: &types.Answer{
+					Success:   true,
+					HasMore:   false,
+					Variables: []string{"X"},
+					Results: []types.Result{{Substitutions: []types.Substitution{{
+						Variable: "X",
+						Term: types.Term{
+							Name:      "2000",
+							Arguments: nil,
+						},
+					}}}},
+				},
+				expectedError: false,
+			},
+			{
+				query:       "sha_hash(foo, _), X = a.",
+				permissions: types.Filter{Whitelist: []string{"=/2"}},
+				expectedAsnwer: &types.Answer{
+					Success:   true,
+					HasMore:   false,
+					Variables: []string{"X"},
+					Results: []types.Result{{Substitutions: []types.Substitution{{
+						Variable: "X",
+						Term: types.Term{
+							Name:      "a",
+							Arguments: nil,
+						},
+					}}}},
+				},
+				expectedError: false,
+			},
+			{
+				query:       "catch(consult(foo), error(E, _), true).",
+				permissions: types.Filter{Blacklist: []string{"consult"}},
+				expectedAsnwer: &types.Answer{
+					Success:   true,
+					HasMore:   false,
+					Variables: []string{"E"},
+					Results: []types.Result{{Substitutions: []types.Substitution{{
+						Variable: "E",
+						Term: types.Term{
+							Name:      "permission_error(execute,procedure,consult/1)",
+							Arguments: nil,
+						},
+					}}}},
+				},
+				expectedError: false,
+			},
+			{
+				query:       "catch(sha_hash(foo, _), error(E, _), true).",
+				permissions: types.Filter{Whitelist: []string{"catch/3"}, Blacklist: []string{"sha_hash/2"}},
+				expectedAsnwer: &types.Answer{
+					Success:   true,
+					HasMore:   false,
+					Variables: []string{"E"},
+					Results: []types.Result{{Substitutions: []types.Substitution{{
+						Variable: "E",
+						Term: types.Term{
+							Name:      "permission_error(execute,procedure,sha_hash/2)",
+							Arguments: nil,
+						},
+					}}}},
+				},
+				expectedError: false,
+			},
 		}
 
 		for nc, tc := range cases {
@@ -96,6 +414,9 @@ func TestGRPCAsk(t *testing.T) {
 					ctrl := gomock.NewController(t)
 					accountKeeper := logictestutil.NewMockAccountKeeper(ctrl)
 					bankKeeper := logictestutil.NewMockBankKeeper(ctrl)
+					stakingKeeper := logictestutil.NewMockStakingKeeper(ctrl)
+					govKeeper := logictestutil.NewMockGovKeeper(ctrl)
+					wasmKeeper := logictestutil.NewMockWasmKeeper(ctrl)
 					fsProvider := logictestutil.NewMockFS(ctrl)
 
 					logicKeeper := keeper.NewKeeper(
@@ -105,11 +426,24 @@ func TestGRPCAsk(t *testing.T) {
 						authtypes.NewModuleAddress(govtypes.ModuleName),
 						accountKeeper,
 						bankKeeper,
+						stakingKeeper,
+						govKeeper,
+						wasmKeeper,
 						func(ctx gocontext.Context) fs.FS {
 							return fsProvider
 						},
 					)
-					err := logicKeeper.SetParams(testCtx.Ctx, types.DefaultParams())
+					params := types.DefaultParams()
+					params.GasPolicy.AlgorithmCosts = tc.algorithmCosts
+					params.Limits.MaxInputSize = tc.maxInputSize
+					params.Limits.MaxSteps = tc.maxSteps
+					params.Limits.MaxCollectionSize = tc.maxCollectionSize
+					params.Limits.MaxDepth = tc.maxDepth
+					if tc.maxResultCount != nil {
+						params.Limits.MaxResultCount = tc.maxResultCount
+					}
+					params.Interpreter.PredicatesPermissions = tc.permissions
+					err := logicKeeper.SetParams(testCtx.Ctx, params)
 
 					So(err, ShouldBeNil)
 
@@ -122,6 +456,8 @@ func TestGRPCAsk(t *testing.T) {
 						query := types.QueryServiceAskRequest{
 							Program: tc.program,
 							Query:   tc.query,
+							Limit:   tc.limit,
+							Offset:  tc.offset,
 						}
 
 						Convey("when the grpc query ask is called", func() {
@@ -130,11 +466,13 @@ func TestGRPCAsk(t *testing.T) {
 							Convey("Then it should return the expected answer", func() {
 								if tc.expectedError {
 									So(err, ShouldNotBeNil)
+									So(err.Error(), ShouldContainSubstring, tc.errorContains)
 									So(result, ShouldBeNil)
 								} else {
 									So(err, ShouldBeNil)
 									So(result, ShouldNotBeNil)
 									So(result.Answer, ShouldResemble, tc.expectedAsnwer)
+									So(result.GasUsed, ShouldBeGreaterThan, 0)
 								}
 							})
 						})
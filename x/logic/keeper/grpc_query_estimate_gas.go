@@ -0,0 +1,39 @@
+package keeper
+
+import (
+	goctx "context"
+
+	errorsmod "cosmossdk.io/errors"
+
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+// EstimateGas dry-runs the given query against the given program, as Ask does but without returning its answer, and
+// returns the gas it consumed. The execution is subject to the limits of the module, the gas being capped at max_gas.
+func (k Keeper) EstimateGas(
+	ctx goctx.Context, req *types.QueryServiceEstimateGasRequest,
+) (*types.QueryServiceEstimateGasResponse, error) {
+	if req == nil {
+		return nil, errorsmod.Wrap(types.InvalidArgument, "request is nil")
+	}
+
+	ask := &types.QueryServiceAskRequest{
+		Program: req.Program,
+		Query:   req.Query,
+	}
+	limits := k.limits(ctx)
+	if err := checkLimits(ask, limits); err != nil {
+		return nil, err
+	}
+
+	// the gas ceiling is the one of Ask, so that an estimation cannot be used to run a query the module would refuse.
+	response, err := k.executeWithGasLimit(ctx, ask.Program, ask.Query, 0, 0, limits.MaxGas.Uint64())
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.QueryServiceEstimateGasResponse{
+		Height:  response.Height,
+		GasUsed: response.GasUsed,
+	}, nil
+}
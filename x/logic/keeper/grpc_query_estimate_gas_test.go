@@ -0,0 +1,140 @@
+package keeper_test
+
+import (
+	gocontext "context"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	"github.com/cosmos/cosmos-sdk/testutil"
+	moduletestutil "github.com/cosmos/cosmos-sdk/types/module/testutil"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	govtypes "github.com/cosmos/cosmos-sdk/x/gov/types"
+
+	"github.com/okp4/okp4d/x/logic"
+	"github.com/okp4/okp4d/x/logic/keeper"
+	logictestutil "github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestGRPCEstimateGas(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program       string
+			query         string
+			maxGas        uint64
+			expectedError bool
+			errorContains string
+		}{
+			{
+				program: "father(bob, alice).",
+				query:   "father(bob, X).",
+				maxGas:  100000,
+			},
+			{
+				query:  "between(1, 100, X), X =:= 100.",
+				maxGas: 100000,
+			},
+			{
+				query:         "between(1, 100, X), X =:= 100.",
+				maxGas:        1500,
+				expectedError: true,
+				errorContains: "out of gas: logic <ReadFlat> (2147/1500): limit exceeded",
+			},
+			{
+				query:         "between(1, 100000, X), X =:= 100000.",
+				maxGas:        500,
+				expectedError: true,
+				errorContains: "out of gas: logic <ReadFlat> (1000/500): limit exceeded",
+			},
+			{
+				query:         "father(bob, X",
+				maxGas:        100000,
+				expectedError: true,
+				errorContains: "error executing query",
+			},
+		}
+
+		for nc, tc := range cases {
+			Convey(
+				fmt.Sprintf("Given test case #%d with program: %v and query: %v", nc, tc.program, tc.query),
+				func() {
+					encCfg := moduletestutil.MakeTestEncodingConfig(logic.AppModuleBasic{})
+					key := storetypes.NewKVStoreKey(types.StoreKey)
+					testCtx := testutil.DefaultContextWithDB(t, key, storetypes.NewTransientStoreKey("transient_test"))
+
+					// gomock initializations
+					ctrl := gomock.NewController(t)
+					accountKeeper := logictestutil.NewMockAccountKeeper(ctrl)
+					bankKeeper := logictestutil.NewMockBankKeeper(ctrl)
+					stakingKeeper := logictestutil.NewMockStakingKeeper(ctrl)
+					govKeeper := logictestutil.NewMockGovKeeper(ctrl)
+					wasmKeeper := logictestutil.NewMockWasmKeeper(ctrl)
+					fsProvider := logictestutil.NewMockFS(ctrl)
+
+					logicKeeper := keeper.NewKeeper(
+						encCfg.Codec,
+						key,
+						key,
+						authtypes.NewModuleAddress(govtypes.ModuleName),
+						accountKeeper,
+						bankKeeper,
+						stakingKeeper,
+						govKeeper,
+						wasmKeeper,
+						func(ctx gocontext.Context) fs.FS {
+							return fsProvider
+						},
+					)
+					params := types.DefaultParams()
+					maxGas := sdkmath.NewUint(tc.maxGas)
+					params.Limits.MaxGas = &maxGas
+					err := logicKeeper.SetParams(testCtx.Ctx, params)
+
+					So(err, ShouldBeNil)
+
+					Convey("and given a query with program and query to grpc", func() {
+						queryHelper := baseapp.NewQueryServerTestHelper(testCtx.Ctx, encCfg.InterfaceRegistry)
+						types.RegisterQueryServiceServer(queryHelper, logicKeeper)
+
+						queryClient := types.NewQueryServiceClient(queryHelper)
+
+						Convey("when the grpc query estimate gas is called", func() {
+							result, err := queryClient.EstimateGas(gocontext.Background(), &types.QueryServiceEstimateGasRequest{
+								Program: tc.program,
+								Query:   tc.query,
+							})
+
+							Convey("Then it should return the gas consumed by the query", func() {
+								if tc.expectedError {
+									So(err, ShouldNotBeNil)
+									So(err.Error(), ShouldContainSubstring, tc.errorContains)
+									So(result, ShouldBeNil)
+									return
+								}
+
+								So(err, ShouldBeNil)
+								So(result, ShouldNotBeNil)
+								So(result.GasUsed, ShouldBeGreaterThan, 0)
+
+								ask, err := queryClient.Ask(gocontext.Background(), &types.QueryServiceAskRequest{
+									Program: tc.program,
+									Query:   tc.query,
+								})
+								So(err, ShouldBeNil)
+								So(result.GasUsed, ShouldEqual, ask.GasUsed)
+							})
+						})
+					})
+				})
+		}
+	})
+}
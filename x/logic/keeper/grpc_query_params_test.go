@@ -37,6 +37,7 @@ func TestGRPCParams(t *testing.T) {
 						types.WithPredicatesBlacklist([]string{"halt/1"}),
 						types.WithPredicatesWhitelist([]string{"source_file/1"}),
 						types.WithVirtualFilesBlacklist([]string{"file1"}),
+						types.WithPredicatesPermissionsBlacklist([]string{"consult/1"}),
 						types.WithVirtualFilesWhitelist([]string{"file2"}),
 					),
 					types.NewLimits(
@@ -44,6 +45,10 @@ func TestGRPCParams(t *testing.T) {
 						types.WithMaxSize(math.NewUint(2)),
 						types.WithMaxResultCount(math.NewUint(3)),
 						types.WithMaxUserOutputSize(math.NewUint(4)),
+						types.WithMaxInputSize(math.NewUint(5)),
+						types.WithMaxSteps(math.NewUint(6)),
+						types.WithMaxCollectionSize(math.NewUint(7)),
+						types.WithMaxDepth(math.NewUint(8)),
 					),
 				),
 			},
@@ -60,6 +65,9 @@ func TestGRPCParams(t *testing.T) {
 					ctrl := gomock.NewController(t)
 					accountKeeper := logictestutil.NewMockAccountKeeper(ctrl)
 					bankKeeper := logictestutil.NewMockBankKeeper(ctrl)
+					stakingKeeper := logictestutil.NewMockStakingKeeper(ctrl)
+					govKeeper := logictestutil.NewMockGovKeeper(ctrl)
+					wasmKeeper := logictestutil.NewMockWasmKeeper(ctrl)
 					fsProvider := logictestutil.NewMockFS(ctrl)
 
 					logicKeeper := keeper.NewKeeper(
@@ -69,6 +77,9 @@ func TestGRPCParams(t *testing.T) {
 						authtypes.NewModuleAddress(govtypes.ModuleName),
 						accountKeeper,
 						bankKeeper,
+						stakingKeeper,
+						govKeeper,
+						wasmKeeper,
 						func(ctx gocontext.Context) fs.FS {
 							return fsProvider
 						},
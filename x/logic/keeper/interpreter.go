@@ -2,7 +2,9 @@ package keeper
 
 import (
 	goctx "context"
+	"errors"
 	"math"
+	"slices"
 
 	"github.com/ichiban/prolog"
 	"github.com/samber/lo"
@@ -32,12 +34,27 @@ func (k Keeper) limits(ctx goctx.Context) types.Limits {
 
 func (k Keeper) enhanceContext(ctx goctx.Context) goctx.Context {
 	sdkCtx := sdk.UnwrapSDKContext(ctx)
-	sdkCtx = sdkCtx.WithValue(types.AuthKeeperContextKey, k.authKeeper)
-	sdkCtx = sdkCtx.WithValue(types.BankKeeperContextKey, k.bankKeeper)
+	sdkCtx = interpreter.ProvideKeepers(sdkCtx, interpreter.Keepers{
+		types.AuthKeeperContextKey:    k.authKeeper,
+		types.BankKeeperContextKey:    k.bankKeeper,
+		types.StakingKeeperContextKey: k.stakingKeeper,
+		types.GovKeeperContextKey:     k.govKeeper,
+		types.WasmKeeperContextKey:    k.wasmKeeper,
+	})
+	params := k.GetParams(sdkCtx)
+	sdkCtx = sdkCtx.WithValue(types.AlgorithmCostsContextKey, params.GasPolicy.AlgorithmCosts)
+	if params.Limits.MaxInputSize != nil {
+		sdkCtx = sdkCtx.WithValue(types.MaxInputSizeContextKey, *params.Limits.MaxInputSize)
+	}
+	if params.Limits.MaxCollectionSize != nil {
+		sdkCtx = sdkCtx.WithValue(types.MaxCollectionSizeContextKey, *params.Limits.MaxCollectionSize)
+	}
 	return sdkCtx
 }
 
-func (k Keeper) execute(ctx goctx.Context, program, query string) (*types.QueryServiceAskResponse, error) {
+// execute executes the given query against the given program, and returns its solutions, the given offset of them
+// being skipped, within the given limit, or the max_result_count limit if 0.
+func (k Keeper) execute(ctx goctx.Context, program, query string, offset, limit uint64) (*types.QueryServiceAskResponse, error) {
 	ctx = k.enhanceContext(ctx)
 	sdkCtx := sdk.UnwrapSDKContext(ctx)
 
@@ -45,7 +62,16 @@ func (k Keeper) execute(ctx goctx.Context, program, query string) (*types.QueryS
 	if err != nil {
 		return nil, errorsmod.Wrapf(types.Internal, "error creating interpreter: %v", err.Error())
 	}
+
+	limits := k.limits(ctx)
+	if limits.MaxSteps != nil {
+		ctx = interpreter.NewStepBudgetContext(ctx, limits.MaxSteps.Uint64())
+	}
+
 	if err := i.ExecContext(ctx, program); err != nil {
+		if errors.Is(err, interpreter.ErrStepBudgetExceeded) {
+			return nil, errorsmod.Wrapf(types.LimitExceeded, "error compiling query: %v (MaxSteps: %s)", err.Error(), limits.MaxSteps)
+		}
 		return nil, errorsmod.Wrapf(types.InvalidArgument, "error compiling query: %v", err.Error())
 	}
 
@@ -57,11 +83,20 @@ func (k Keeper) execute(ctx goctx.Context, program, query string) (*types.QueryS
 		_ = sols.Close()
 	}()
 
+	// once the solutions are exhausted, sols.Next shall not be called more than once, as it would block.
+	exhausted := false
+	for skipped := uint64(0); skipped < offset && !exhausted; skipped++ {
+		exhausted = !sols.Next()
+	}
+
+	count := *limits.MaxResultCount
+	if limit != 0 {
+		count = sdkmath.NewUint(limit)
+	}
 	success := false
-	limits := k.limits(ctx)
 	var variables []string
 	results := make([]types.Result, 0)
-	for nb := sdkmath.ZeroUint(); nb.LT(*limits.MaxResultCount) && sols.Next(); nb = nb.Incr() {
+	for nb := sdkmath.ZeroUint(); !exhausted && nb.LT(count) && sols.Next(); nb = nb.Incr() {
 		success = true
 
 		m := types.TermResults{}
@@ -79,6 +114,9 @@ func (k Keeper) execute(ctx goctx.Context, program, query string) (*types.QueryS
 		if sdkCtx.GasMeter().IsOutOfGas() {
 			panic(sdk.ErrorOutOfGas{Descriptor: "Prolog interpreter execution"})
 		}
+		if errors.Is(err, interpreter.ErrStepBudgetExceeded) {
+			return nil, errorsmod.Wrapf(types.LimitExceeded, "error interpreting solutions: %v (MaxSteps: %s)", err.Error(), limits.MaxSteps)
+		}
 		return nil, errorsmod.Wrapf(types.InvalidArgument, "error interpreting solutions: %v", err.Error())
 	}
 
@@ -92,7 +130,7 @@ func (k Keeper) execute(ctx goctx.Context, program, query string) (*types.QueryS
 		GasUsed: sdkCtx.GasMeter().GasConsumed(),
 		Answer: &types.Answer{
 			Success:   success,
-			HasMore:   sols.Next(),
+			HasMore:   !exhausted && sols.Next(),
 			Variables: variables,
 			Results:   results,
 		},
@@ -107,6 +145,10 @@ func checkLimits(request *types.QueryServiceAskRequest, limits types.Limits) err
 		return errorsmod.Wrapf(types.LimitExceeded, "query: %d > MaxSize: %d", size, limit)
 	}
 
+	if count := sdkmath.NewUint(request.GetLimit()); count.GT(*limits.MaxResultCount) {
+		return errorsmod.Wrapf(types.LimitExceeded, "limit: %s > MaxResultCount: %s", count, limits.MaxResultCount)
+	}
+
 	return nil
 }
 
@@ -136,6 +178,17 @@ func (k Keeper) newInterpreter(ctx goctx.Context) (*prolog.Interpreter, *util.Bo
 		},
 		interpreter.Predicates{})
 
+	permissionsWhitelist := interpreterParams.PredicatesPermissions.Whitelist
+	if len(permissionsWhitelist) > 0 {
+		permissionsWhitelist = append(slices.Clone(permissionsWhitelist), types.CryptoPredicates...)
+	}
+	forbiddenPredicates := lo.Filter(
+		lo.Keys(predicates),
+		func(predicate string, _ int) bool {
+			return !util.WhitelistBlacklistMatches(
+				permissionsWhitelist, interpreterParams.PredicatesPermissions.Blacklist, util.PredicateMatches)(predicate)
+		})
+
 	whitelistUrls := lo.Map(
 		util.NonZeroOrDefault(interpreterParams.VirtualFilesFilter.Whitelist, []string{}),
 		util.Indexed(util.ParseURLMust))
@@ -143,9 +196,15 @@ func (k Keeper) newInterpreter(ctx goctx.Context) (*prolog.Interpreter, *util.Bo
 		util.NonZeroOrDefault(interpreterParams.VirtualFilesFilter.Whitelist, []string{}),
 		util.Indexed(util.ParseURLMust))
 
+	var depthLimit *interpreter.DepthLimit
+	if limits.MaxDepth != nil {
+		depthLimit = interpreter.NewDepthLimit(limits.MaxDepth.Uint64())
+	}
+
 	options := []interpreter.Option{
-		interpreter.WithPredicates(ctx, predicates, gasMeter),
+		interpreter.WithPredicates(ctx, predicates, gasMeter, depthLimit),
 		interpreter.WithBootstrap(ctx, util.NonZeroOrDefault(interpreterParams.GetBootstrap(), bootstrap.Bootstrap())),
+		interpreter.WithForbiddenPredicates(lo.PickByKeys(predicates, forbiddenPredicates), gasMeter),
 		interpreter.WithFS(fs.NewFilteredFS(whitelistUrls, blacklistUrls, k.fsProvider(ctx))),
 	}
 
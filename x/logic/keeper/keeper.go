@@ -24,9 +24,12 @@ type (
 		// the address capable of executing a MsgUpdateParams message. Typically, this should be the x/gov module account.
 		authority sdk.AccAddress
 
-		authKeeper types.AccountKeeper
-		bankKeeper types.BankKeeper
-		fsProvider FSProvider
+		authKeeper    types.AccountKeeper
+		bankKeeper    types.BankKeeper
+		stakingKeeper types.StakingKeeper
+		govKeeper     types.GovKeeper
+		wasmKeeper    types.WasmKeeper
+		fsProvider    FSProvider
 	}
 )
 
@@ -37,6 +40,9 @@ func NewKeeper(
 	authority sdk.AccAddress,
 	authKeeper types.AccountKeeper,
 	bankKeeper types.BankKeeper,
+	stakingKeeper types.StakingKeeper,
+	govKeeper types.GovKeeper,
+	wasmKeeper types.WasmKeeper,
 	fsProvider FSProvider,
 ) *Keeper {
 	// ensure gov module account is set and is not nil
@@ -45,13 +51,16 @@ func NewKeeper(
 	}
 
 	return &Keeper{
-		cdc:        cdc,
-		storeKey:   storeKey,
-		memKey:     memKey,
-		authority:  authority,
-		authKeeper: authKeeper,
-		bankKeeper: bankKeeper,
-		fsProvider: fsProvider,
+		cdc:           cdc,
+		storeKey:      storeKey,
+		memKey:        memKey,
+		authority:     authority,
+		authKeeper:    authKeeper,
+		bankKeeper:    bankKeeper,
+		stakingKeeper: stakingKeeper,
+		govKeeper:     govKeeper,
+		wasmKeeper:    wasmKeeper,
+		fsProvider:    fsProvider,
 	}
 }
 
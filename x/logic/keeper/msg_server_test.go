@@ -57,6 +57,9 @@ func TestUpdateParams(t *testing.T) {
 					ctrl := gomock.NewController(t)
 					accountKeeper := logictestutil.NewMockAccountKeeper(ctrl)
 					bankKeeper := logictestutil.NewMockBankKeeper(ctrl)
+					stakingKeeper := logictestutil.NewMockStakingKeeper(ctrl)
+					govKeeper := logictestutil.NewMockGovKeeper(ctrl)
+					wasmKeeper := logictestutil.NewMockWasmKeeper(ctrl)
 					fsProvider := logictestutil.NewMockFS(ctrl)
 
 					logicKeeper := keeper.NewKeeper(
@@ -66,6 +69,9 @@ func TestUpdateParams(t *testing.T) {
 						authtypes.NewModuleAddress(govtypes.ModuleName),
 						accountKeeper,
 						bankKeeper,
+						stakingKeeper,
+						govKeeper,
+						wasmKeeper,
 						func(ctx gocontext.Context) fs.FS {
 							return fsProvider
 						},
@@ -0,0 +1,243 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+var (
+	// AtomRule are terms with principal functor rule/2.
+	// It is used to represent a rule of an attribute based access control policy.
+	AtomRule = engine.NewAtom("rule")
+
+	// AtomAllow is the term allow.
+	AtomAllow = engine.NewAtom("allow")
+
+	// AtomDeny is the term deny.
+	AtomDeny = engine.NewAtom("deny")
+)
+
+// ABACAllowed is a predicate which checks whether a request, described by its attributes, is allowed according to an
+// attribute based access control (ABAC) policy.
+//
+// The signature is as follows:
+//
+//	abac_allowed(+Attributes, +Policy) is semidet
+//
+// Where:
+//   - Attributes is the list of Name-Value pairs describing the subject, the resource and the environment of the
+//     request, where each Name is an Atom (e.g. 'subject.role') and each Value is any ground term.
+//   - Policy is the list of rule(Effect, Condition) terms, where Effect is either allow or deny, and Condition is the
+//     condition on the attributes for the rule to apply.
+//
+// A Condition is one of the following terms:
+//   - true, which always holds;
+//   - eq(Name, Value) and neq(Name, Value), which hold if the attribute Name is (or is not) equal to Value;
+//   - lt(Name, Number), le(Name, Number), gt(Name, Number) and ge(Name, Number), which hold if the attribute Name is
+//     a number respectively lower than, lower than or equal to, greater than, greater than or equal to Number;
+//   - between(Name, Low, High), which holds if the attribute Name is a number between Low and High, inclusive;
+//   - in(Name, Values), which holds if the attribute Name is equal to one of the elements of the list Values;
+//   - and(Conditions), or(Conditions) and not(Condition), the logical combinations of conditions.
+//
+// A comparison referencing an attribute not present in Attributes does not hold, without raising any error.
+//
+// The rules are combined according to the deny-overrides algorithm: the predicate succeeds if and only if at least one
+// allow rule applies and no deny rule applies.
+//
+// Examples:
+//
+//	# Allow the admins, except outside the business hours.
+//	- abac_allowed(['subject.role'-admin, 'env.hour'-22],
+//	  [rule(allow, eq('subject.role', admin)), rule(deny, not(between('env.hour', 8, 18)))]).
+func ABACAllowed(_ *engine.VM, attributes, policy engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		attrs := make(map[engine.Atom]engine.Term)
+		iter := engine.ListIterator{List: attributes, Env: env}
+		for iter.Next() {
+			pair, ok := env.Resolve(iter.Current()).(engine.Compound)
+			if !ok || pair.Functor() != AtomPair || pair.Arity() != 2 {
+				return engine.Error(fmt.Errorf("abac_allowed/2: invalid attribute type: %T, should be Name-Value",
+					env.Resolve(iter.Current())))
+			}
+			name, ok := env.Resolve(pair.Arg(0)).(engine.Atom)
+			if !ok {
+				return engine.Error(fmt.Errorf("abac_allowed/2: invalid attribute name type: %T, should be Atom",
+					env.Resolve(pair.Arg(0))))
+			}
+			if _, ok := attrs[name]; !ok {
+				attrs[name] = env.Resolve(pair.Arg(1))
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return engine.Error(fmt.Errorf("abac_allowed/2: invalid attributes: %w", err))
+		}
+
+		allowed := false
+		iter = engine.ListIterator{List: policy, Env: env}
+		for iter.Next() {
+			rule, ok := env.Resolve(iter.Current()).(engine.Compound)
+			if !ok || rule.Functor() != AtomRule || rule.Arity() != 2 {
+				return engine.Error(fmt.Errorf("abac_allowed/2: invalid rule type: %T, should be rule(Effect, Condition)",
+					env.Resolve(iter.Current())))
+			}
+			effect := env.Resolve(rule.Arg(0))
+			if effect != AtomAllow && effect != AtomDeny {
+				return engine.Error(fmt.Errorf("abac_allowed/2: invalid rule effect: %v, valid values are 'allow' or 'deny'", effect))
+			}
+
+			holds, err := abacEval(rule.Arg(1), attrs, env)
+			if err != nil {
+				return engine.Error(fmt.Errorf("abac_allowed/2: %w", err))
+			}
+			if holds && effect == AtomDeny {
+				return engine.Bool(false)
+			}
+			allowed = allowed || holds
+		}
+		if err := iter.Err(); err != nil {
+			return engine.Error(fmt.Errorf("abac_allowed/2: invalid policy: %w", err))
+		}
+
+		if !allowed {
+			return engine.Bool(false)
+		}
+		return cont(env)
+	})
+}
+
+// abacEval evaluates the given condition against the given attributes.
+//
+//nolint:cyclop
+func abacEval(condition engine.Term, attrs map[engine.Atom]engine.Term, env *engine.Env) (bool, error) {
+	switch c := env.Resolve(condition).(type) {
+	case engine.Atom:
+		if c == AtomTrue {
+			return true, nil
+		}
+	case engine.Compound:
+		switch name := c.Functor().String(); {
+		case (name == "and" || name == "or") && c.Arity() == 1:
+			return abacEvalAll(c.Arg(0), name == "and", attrs, env)
+		case name == "not" && c.Arity() == 1:
+			holds, err := abacEval(c.Arg(0), attrs, env)
+			return !holds, err
+		case (name == "eq" || name == "neq") && c.Arity() == 2:
+			value, ok, err := abacAttribute(c.Arg(0), attrs, env)
+			if err != nil || !ok {
+				return false, err
+			}
+			return (value.Compare(env.Resolve(c.Arg(1)), env) == 0) == (name == "eq"), nil
+		case name == "in" && c.Arity() == 2:
+			value, ok, err := abacAttribute(c.Arg(0), attrs, env)
+			if err != nil || !ok {
+				return false, err
+			}
+			iter := engine.ListIterator{List: c.Arg(1), Env: env}
+			for iter.Next() {
+				if value.Compare(env.Resolve(iter.Current()), env) == 0 {
+					return true, nil
+				}
+			}
+			return false, iter.Err()
+		case (name == "lt" || name == "le" || name == "gt" || name == "ge") && c.Arity() == 2:
+			value, ok, err := abacAttribute(c.Arg(0), attrs, env)
+			if err != nil || !ok {
+				return false, err
+			}
+			cmp, ok := compareNumbers(value, env.Resolve(c.Arg(1)))
+			return ok && ((name == "lt" && cmp < 0) || (name == "le" && cmp <= 0) ||
+				(name == "gt" && cmp > 0) || (name == "ge" && cmp >= 0)), nil
+		case name == "between" && c.Arity() == 3:
+			value, ok, err := abacAttribute(c.Arg(0), attrs, env)
+			if err != nil || !ok {
+				return false, err
+			}
+			low, okLow := compareNumbers(value, env.Resolve(c.Arg(1)))
+			high, okHigh := compareNumbers(value, env.Resolve(c.Arg(2)))
+			return okLow && okHigh && low >= 0 && high <= 0, nil
+		}
+	}
+
+	if c, ok := env.Resolve(condition).(engine.Compound); ok {
+		return false, fmt.Errorf("invalid condition: %s/%d", c.Functor(), c.Arity())
+	}
+	return false, fmt.Errorf("invalid condition: %v", env.Resolve(condition))
+}
+
+// abacEvalAll evaluates the given list of conditions against the given attributes, combining them either with a
+// logical and, or with a logical or.
+func abacEvalAll(conditions engine.Term, and bool, attrs map[engine.Atom]engine.Term, env *engine.Env) (bool, error) {
+	iter := engine.ListIterator{List: conditions, Env: env}
+	for iter.Next() {
+		holds, err := abacEval(iter.Current(), attrs, env)
+		if err != nil {
+			return false, err
+		}
+		if holds != and {
+			return holds, nil
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return false, fmt.Errorf("invalid conditions: %w", err)
+	}
+
+	return and, nil
+}
+
+// abacAttribute returns the value of the attribute referenced by the given name, and whether it is present.
+func abacAttribute(name engine.Term, attrs map[engine.Atom]engine.Term, env *engine.Env) (engine.Term, bool, error) {
+	n, ok := env.Resolve(name).(engine.Atom)
+	if !ok {
+		return nil, false, fmt.Errorf("invalid attribute name type: %T, should be Atom", env.Resolve(name))
+	}
+	value, ok := attrs[n]
+	return value, ok, nil
+}
+
+// compareNumbers compares the two given numbers, returning -1, 0 or 1 if a is respectively lower than, equal to or
+// greater than b. It returns false if any of the given terms is not a number.
+func compareNumbers(a, b engine.Term) (int, bool) {
+	ia, aIsInt := a.(engine.Integer)
+	ib, bIsInt := b.(engine.Integer)
+	if aIsInt && bIsInt {
+		switch {
+		case ia < ib:
+			return -1, true
+		case ia > ib:
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+
+	fa, ok := toFloat(a)
+	if !ok {
+		return 0, false
+	}
+	fb, ok := toFloat(b)
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case fa < fb:
+		return -1, true
+	case fa > fb:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// toFloat converts the given number into a float.
+func toFloat(t engine.Term) (float64, bool) {
+	switch n := t.(type) {
+	case engine.Integer:
+		return float64(n), true
+	case engine.Float:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
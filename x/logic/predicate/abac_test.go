@@ -0,0 +1,170 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestABACAllowed(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		program := `policy([
+  rule(allow, and([eq('subject.role', admin), in('resource.type', [document, image])])),
+  rule(allow, and([eq('subject.role', auditor), eq('action', read)])),
+  rule(deny, not(between('env.hour', 8, 18))),
+  rule(deny, eq('subject.suspended', true))
+]).
+allowed(Attributes) :- policy(P), abac_allowed(Attributes, P).`
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				program:     program,
+				query:       `allowed(['subject.role'-admin, 'resource.type'-document, 'env.hour'-10]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `allowed(['subject.role'-auditor, action-read, 'env.hour'-18]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `allowed(['subject.role'-auditor, action-write, 'env.hour'-10]).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `allowed(['subject.role'-admin, 'resource.type'-document, 'env.hour'-22]).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `allowed(['subject.role'-admin, 'resource.type'-image, 'env.hour'-9, 'subject.suspended'-true]).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `allowed(['subject.role'-admin, 'resource.type'-image]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `abac_allowed([age-21], [rule(allow, ge(age, 18)), rule(deny, gt(age, 65.5))]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `abac_allowed([age-70], [rule(allow, ge(age, 18)), rule(deny, gt(age, 65.5))]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `abac_allowed([age-old], [rule(allow, ge(age, 18))]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `abac_allowed([role-admin], [rule(allow, or([eq(role, user), neq(role, guest)])), rule(deny, lt(unknown, 3))]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `abac_allowed([], [rule(allow, true)]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `abac_allowed([role-admin], []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `abac_allowed([role-admin], [rule(permit, true)]).`,
+				wantError:   fmt.Errorf("abac_allowed/2: invalid rule effect: permit, valid values are 'allow' or 'deny'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `abac_allowed([role-admin], [rule(allow, like(role, adm))]).`,
+				wantError:   fmt.Errorf("abac_allowed/2: invalid condition: like/2"),
+				wantSuccess: false,
+			},
+			{
+				query:       `abac_allowed([role], [rule(allow, true)]).`,
+				wantError:   fmt.Errorf("abac_allowed/2: invalid attribute type: engine.Atom, should be Name-Value"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("abac_allowed"), ABACAllowed)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package predicate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"github.com/ichiban/prolog/engine"
+
+	cometcrypto "github.com/cometbft/cometbft/crypto"
+)
+
+// hashSetDigestSize is the size in bytes of each digest held by a hash-set commitment.
+const hashSetDigestSize = sha256.Size
+
+// AccumulatorEmpty is a predicate which unifies the given term with the empty accumulator.
+//
+// The signature is as follows:
+//
+//	accumulator_empty(-Acc) is det
+//
+// Where:
+//   - Acc is the empty accumulator, represented as an empty list of bytes.
+//
+// Despite the name of the predicates, the accumulator is not a cryptographic accumulator, whose size would be constant
+// and whose membership proofs would need a witness, but a hash-set commitment: an append-only set of elements encoded
+// as a list of bytes being the concatenation of the SHA-256 digests of its elements, sorted in ascending lexicographic
+// order and without duplicates. Its encoding is canonical, i.e. it only depends on the set of elements, not on the
+// order in which they have been added, so that two accumulators can be compared for equality.
+//
+// Its security properties and limits are the following:
+//   - It is binding: adding an element to an accumulator, or checking its membership, with another element of the same
+//     digest would require a collision of SHA-256, which is considered computationally infeasible.
+//   - It is not hiding: anyone knowing a candidate element can check its membership, and the number of elements is
+//     given by the size of the accumulator.
+//   - Its size is not constant but grows by 32 bytes per element, whatever the size of the element, and is bounded by
+//     the maximum input size of the interpreter, so that it only suits small sets. Adding an element or checking its
+//     membership takes a time linear in the size of the accumulator, which is to be decoded.
+//   - An Atom element and the list of the bytes of its text are the same element, as no domain separation is made.
+//
+// Examples:
+//
+//	# Create an empty accumulator.
+//	- accumulator_empty(Acc).
+func AccumulatorEmpty(vm *engine.VM, acc engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Unify(vm, acc, BytesToList([]byte{}), cont, env)
+}
+
+// AccumulatorAdd is a predicate which adds an element to an accumulator.
+//
+// The signature is as follows:
+//
+//	accumulator_add(+Acc, +Element, -Acc2) is det
+//
+// Where:
+//   - Acc is the accumulator, represented as a list of bytes (see accumulator_empty/1).
+//   - Element is the element to add, given either as an Atom (whose text is used) or as a list of bytes.
+//   - Acc2 is the resulting accumulator. Adding an element already present in Acc gives back Acc unchanged.
+//
+// Examples:
+//
+//	# Add two elements to an empty accumulator.
+//	- accumulator_empty(Acc0), accumulator_add(Acc0, alice, Acc1), accumulator_add(Acc1, bob, Acc2).
+func AccumulatorAdd(vm *engine.VM, acc, element, acc2 engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		digests, err := termToHashSet(ctx, acc, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("accumulator_add/3: %w", err))
+		}
+
+		digest, err := hashSetElementDigest(ctx, element, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("accumulator_add/3: %w", err))
+		}
+
+		index, found := searchDigest(digests, digest)
+		if !found {
+			digests = append(digests, nil)
+			copy(digests[index+1:], digests[index:])
+			digests[index] = digest
+		}
+
+		return engine.Unify(vm, acc2, BytesToList(bytes.Join(digests, nil)), cont, env)
+	})
+}
+
+// AccumulatorContains is a predicate which checks whether an element has been added to an accumulator.
+//
+// The signature is as follows:
+//
+//	accumulator_contains(+Acc, +Element) is semidet
+//
+// Where:
+//   - Acc is the accumulator, represented as a list of bytes (see accumulator_empty/1).
+//   - Element is the element to look for, given either as an Atom (whose text is used) or as a list of bytes.
+//
+// The membership is decided on the SHA-256 digest of the element: a false positive would require a collision of
+// SHA-256, which is considered computationally infeasible. There are no false negatives. No witness is needed as the
+// accumulator holds the digests of all its elements (see accumulator_empty/1 for its security properties and limits).
+//
+// Examples:
+//
+//	# Check the membership of an element.
+//	- accumulator_empty(Acc0), accumulator_add(Acc0, alice, Acc1), accumulator_contains(Acc1, alice).
+func AccumulatorContains(_ *engine.VM, acc, element engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		digests, err := termToHashSet(ctx, acc, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("accumulator_contains/2: %w", err))
+		}
+
+		digest, err := hashSetElementDigest(ctx, element, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("accumulator_contains/2: %w", err))
+		}
+
+		if _, found := searchDigest(digests, digest); !found {
+			return engine.Bool(false)
+		}
+
+		return cont(env)
+	})
+}
+
+// termToHashSet converts the given accumulator term into the sorted list of digests of the hash-set commitment.
+func termToHashSet(ctx context.Context, acc engine.Term, env *engine.Env) ([][]byte, error) {
+	data, err := TermToBytes(ctx, acc, AtomEncoding.Apply(AtomOctet), env)
+	if err != nil {
+		return nil, fmt.Errorf("invalid accumulator: %w", err)
+	}
+	if len(data)%hashSetDigestSize != 0 {
+		return nil, fmt.Errorf("invalid accumulator: length %d is not a multiple of %d", len(data), hashSetDigestSize)
+	}
+
+	digests := make([][]byte, 0, len(data)/hashSetDigestSize)
+	for i := 0; i < len(data); i += hashSetDigestSize {
+		digest := data[i : i+hashSetDigestSize]
+		if len(digests) > 0 && bytes.Compare(digests[len(digests)-1], digest) >= 0 {
+			return nil, fmt.Errorf("invalid accumulator: digests are not sorted or contain duplicates")
+		}
+		digests = append(digests, digest)
+	}
+
+	return digests, nil
+}
+
+// hashSetElementDigest returns the digest of the given element, given either as an atom or as a list of bytes.
+func hashSetElementDigest(ctx context.Context, element engine.Term, env *engine.Env) ([]byte, error) {
+	switch e := env.Resolve(element).(type) {
+	case engine.Atom:
+		return cometcrypto.Sha256([]byte(e.String())), nil
+	default:
+		data, err := TermToBytes(ctx, e, AtomEncoding.Apply(AtomOctet), env)
+		if err != nil {
+			return nil, fmt.Errorf("invalid element: %w", err)
+		}
+		return cometcrypto.Sha256(data), nil
+	}
+}
+
+// searchDigest looks for the given digest in the sorted list of digests, returning the index where it is, or where it
+// should be inserted, and whether it has been found.
+func searchDigest(digests [][]byte, digest []byte) (int, bool) {
+	index := sort.Search(len(digests), func(i int) bool {
+		return bytes.Compare(digests[i], digest) >= 0
+	})
+
+	return index, index < len(digests) && bytes.Equal(digests[index], digest)
+}
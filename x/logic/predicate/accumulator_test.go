@@ -0,0 +1,179 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestAccumulator(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `accumulator_empty(Acc).`,
+				wantResult:  []types.TermResults{{"Acc": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				program: `acc([], Acc) :- accumulator_empty(Acc).
+acc([E|Es], Acc) :- acc(Es, Acc0), accumulator_add(Acc0, E, Acc).
+in(E, Es) :- acc(Es, Acc), accumulator_contains(Acc, E).
+same(Es1, Es2) :- acc(Es1, Acc), acc(Es2, Acc).`,
+				query:       `acc([alice], Acc).`,
+				wantResult:  []types.TermResults{{"Acc": "[43,216,6,201,127,14,0,175,26,31,195,50,143,167,99,169,38,151,35,200,219,143,172,79,147,175,113,219,24,109,110,144]"}},
+				wantSuccess: true,
+			},
+			{
+				program: `acc([], Acc) :- accumulator_empty(Acc).
+acc([E|Es], Acc) :- acc(Es, Acc0), accumulator_add(Acc0, E, Acc).
+in(E, Es) :- acc(Es, Acc), accumulator_contains(Acc, E).
+same(Es1, Es2) :- acc(Es1, Acc), acc(Es2, Acc).`,
+				query:       `in(alice, [alice, bob]), in(bob, [alice, bob]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program: `acc([], Acc) :- accumulator_empty(Acc).
+acc([E|Es], Acc) :- acc(Es, Acc0), accumulator_add(Acc0, E, Acc).
+in(E, Es) :- acc(Es, Acc), accumulator_contains(Acc, E).
+same(Es1, Es2) :- acc(Es1, Acc), acc(Es2, Acc).`,
+				query:       `in(carol, [alice, bob]).`,
+				wantSuccess: false,
+			},
+			{
+				program: `acc([], Acc) :- accumulator_empty(Acc).
+acc([E|Es], Acc) :- acc(Es, Acc0), accumulator_add(Acc0, E, Acc).
+in(E, Es) :- acc(Es, Acc), accumulator_contains(Acc, E).
+same(Es1, Es2) :- acc(Es1, Acc), acc(Es2, Acc).`,
+				query:       `in(alice, []).`,
+				wantSuccess: false,
+			},
+			{
+				program: `acc([], Acc) :- accumulator_empty(Acc).
+acc([E|Es], Acc) :- acc(Es, Acc0), accumulator_add(Acc0, E, Acc).
+in(E, Es) :- acc(Es, Acc), accumulator_contains(Acc, E).
+same(Es1, Es2) :- acc(Es1, Acc), acc(Es2, Acc).`,
+				query:       `same([bob, alice], [alice, bob, alice]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program: `acc([], Acc) :- accumulator_empty(Acc).
+acc([E|Es], Acc) :- acc(Es, Acc0), accumulator_add(Acc0, E, Acc).
+in(E, Es) :- acc(Es, Acc), accumulator_contains(Acc, E).
+same(Es1, Es2) :- acc(Es1, Acc), acc(Es2, Acc).`,
+				query:       `same([bob], [alice, bob]).`,
+				wantSuccess: false,
+			},
+			{
+				program: `acc([], Acc) :- accumulator_empty(Acc).
+acc([E|Es], Acc) :- acc(Es, Acc0), accumulator_add(Acc0, E, Acc).
+in(E, Es) :- acc(Es, Acc), accumulator_contains(Acc, E).
+same(Es1, Es2) :- acc(Es1, Acc), acc(Es2, Acc).`,
+				query:       `in([1, 2, 3], [alice, [1, 2, 3]]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program: `acc([], Acc) :- accumulator_empty(Acc).
+acc([E|Es], Acc) :- acc(Es, Acc0), accumulator_add(Acc0, E, Acc).
+in(E, Es) :- acc(Es, Acc), accumulator_contains(Acc, E).
+same(Es1, Es2) :- acc(Es1, Acc), acc(Es2, Acc).`,
+				query:       `in([1, 2], [alice, [1, 2, 3]]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `accumulator_contains([1, 2, 3], alice).`,
+				wantError:   fmt.Errorf("accumulator_contains/2: invalid accumulator: length 3 is not a multiple of 32"),
+				wantSuccess: false,
+			},
+			{
+				query:       `accumulator_add(foo, alice, Acc).`,
+				wantError:   fmt.Errorf("accumulator_add/3: invalid accumulator: term should be a List, given engine.Atom"),
+				wantSuccess: false,
+			},
+			{
+				query:       `accumulator_add([], 42, Acc).`,
+				wantError:   fmt.Errorf("accumulator_add/3: invalid element: term should be a List, given engine.Integer"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register1(engine.NewAtom("accumulator_empty"), AccumulatorEmpty)
+						interpreter.Register3(engine.NewAtom("accumulator_add"), AccumulatorAdd)
+						interpreter.Register2(engine.NewAtom("accumulator_contains"), AccumulatorContains)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
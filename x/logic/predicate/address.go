@@ -3,9 +3,13 @@ package predicate
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/ichiban/prolog/engine"
 
+	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	bech322 "github.com/cosmos/cosmos-sdk/types/bech32"
 
 	"github.com/okp4/okp4d/x/logic/util"
@@ -53,7 +57,7 @@ func Bech32Address(vm *engine.VM, address, bech32 engine.Term, cont engine.Cont,
 
 		switch addressPair := env.Resolve(address).(type) {
 		case engine.Compound:
-			bech32Decoded, err := addressPairToBech32(addressPair, env)
+			bech32Decoded, err := addressPairToBech32(ctx, addressPair, env)
 			if err != nil {
 				return engine.Error(fmt.Errorf("bech32_address/2: %w", err))
 			}
@@ -64,7 +68,121 @@ func Bech32Address(vm *engine.VM, address, bech32 engine.Term, cont engine.Cont,
 	})
 }
 
-func addressPairToBech32(addressPair engine.Compound, env *engine.Env) (string, error) {
+// AtomHRP is the term used to indicate the hrp option, giving the Human-Readable Part of a bech32 address.
+var AtomHRP = engine.NewAtom("hrp")
+
+// pubkeyAddressTypes are the key types of pubkey_address/3, by the size of their public keys.
+var pubkeyAddressTypes = map[int]string{
+	secp256k1.PubKeySize: "secp256k1",
+	ed25519.PubKeySize:   "ed25519",
+}
+
+// PubkeyAddress is a predicate that derives the bech32 account address of a public key, as the Cosmos SDK does.
+//
+// The signature is as follows:
+//
+//	pubkey_address(+PubKey, -Address, +Options) is det
+//
+// Where:
+//   - PubKey is the public key, as a list of bytes: a compressed secp256k1 key of 33 bytes, or an ed25519 key of 32
+//     bytes.
+//   - Address is the bech32 encoded account address of PubKey, as an Atom.
+//   - Options are additional configurations for the derivation. Supported options include: type(+Alg) which specifies
+//     the type of PubKey, either secp256k1 or ed25519, and defaults to the type of its size, and hrp(+Prefix) which
+//     specifies the Human-Readable Part of Address, and defaults to the account address prefix of the chain. Any other
+//     option raises a domain_error(option, Option) error.
+//
+// The address of a secp256k1 key is the RIPEMD-160 hash of its SHA-256 hash, and the one of an ed25519 key its SHA-256
+// hash truncated to 20 bytes, both being encoded with bech32 along with Prefix (see bech32_address/2).
+//
+// An unbound PubKey, Alg or Prefix raises an instantiation_error, a PubKey which is not a list of bytes a
+// type_error(list, PubKey) or type_error(byte, Element) error, an Alg or a Prefix which is not an Atom a
+// type_error(atom, Term) error, an unsupported Alg a domain_error(algorithm, Alg) error, a Prefix which is not a valid
+// Human-Readable Part as specified by BIP-173 a domain_error(hrp, Prefix) error, and a PubKey which is not a valid key
+// of type Alg a domain_error(public_key, PubKey) error.
+//
+// Examples:
+//
+//	# Derive the address of a secp256k1 public key on the chain.
+//	- pubkey_address([2, 142, ...], Address, []).
+//
+//	# Derive the address of an ed25519 public key with the cosmos prefix.
+//	- pubkey_address([215, 90, ...], Address, [type(ed25519), hrp(cosmos)]).
+func PubkeyAddress(vm *engine.VM, pubKey, address, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "pubkey_address/3"
+
+		if err := util.CheckOptions(options, []engine.Atom{AtomType, AtomHRP}, env); err != nil {
+			return engine.Error(err)
+		}
+		key, err := decodeBytes(ctx, pubKey, AtomOctet, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		typTerm, err := util.GetOptionWithDefault(AtomType, options, engine.NewAtom(pubkeyAddressTypes[len(key)]), env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		typ, ok := env.Resolve(typTerm).(engine.Atom)
+		if !ok {
+			return engine.Error(typeError(AtomAtom, typTerm, env))
+		}
+		hrpTerm, err := util.GetOptionWithDefault(AtomHRP, options, engine.NewAtom(sdk.GetConfig().GetBech32AccountAddrPrefix()), env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		hrp, ok := env.Resolve(hrpTerm).(engine.Atom)
+		if !ok {
+			return engine.Error(typeError(AtomAtom, hrpTerm, env))
+		}
+		if !isValidHRP(hrp.String()) {
+			return engine.Error(domainError(AtomHRP, hrp, env))
+		}
+
+		var addr []byte
+		switch typ.String() {
+		case "secp256k1":
+			if len(key) != secp256k1.PubKeySize || (key[0] != 0x02 && key[0] != 0x03) {
+				return engine.Error(domainError(AtomPublicKey, pubKey, env))
+			}
+			addr = (&secp256k1.PubKey{Key: key}).Address()
+		case "ed25519":
+			if len(key) != ed25519.PubKeySize {
+				return engine.Error(domainError(AtomPublicKey, pubKey, env))
+			}
+			addr = (&ed25519.PubKey{Key: key}).Address()
+		case "":
+			return engine.Error(domainError(AtomPublicKey, pubKey, env))
+		default:
+			return engine.Error(domainError(AtomAlgorithm, typ, env))
+		}
+		if err := consumeAlgorithmGas(ctx, functor, "sha256", len(key)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		bech32, err := bech322.ConvertAndEncode(hrp.String(), addr)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: failed to encode the address: %w", functor, err))
+		}
+		return engine.Unify(vm, address, engine.NewAtom(bech32), cont, env)
+	})
+}
+
+// isValidHRP tells whether the given Human-Readable Part is valid, as specified by BIP-173: it is made of 1 to 83
+// printable US-ASCII characters, and is not of mixed case.
+func isValidHRP(hrp string) bool {
+	if len(hrp) < 1 || len(hrp) > 83 || (strings.ToLower(hrp) != hrp && strings.ToUpper(hrp) != hrp) {
+		return false
+	}
+	for _, c := range hrp {
+		if c < 33 || c > 126 {
+			return false
+		}
+	}
+	return true
+}
+
+func addressPairToBech32(ctx context.Context, addressPair engine.Compound, env *engine.Env) (string, error) {
 	if addressPair.Functor() != AtomPair || addressPair.Arity() != 2 {
 		return "", fmt.Errorf("address should be a Pair '-(Hrp, Address)'")
 	}
@@ -76,7 +194,7 @@ func addressPairToBech32(addressPair engine.Compound, env *engine.Env) (string,
 		}
 
 		iter := engine.ListIterator{List: a, Env: env}
-		data, err := ListToBytes(iter, env)
+		data, err := ListToBytes(ctx, iter, env)
 		if err != nil {
 			return "", fmt.Errorf("failed to convert term to bytes list: %w", err)
 		}
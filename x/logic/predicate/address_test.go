@@ -170,3 +170,196 @@ func TestBech32(t *testing.T) {
 		}
 	})
 }
+
+func TestPubkeyAddress(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `pubkey_address([2, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142], Address, [hrp(okp4)]).`,
+				wantResult:  []types.TermResults{{"Address": "okp41evxmw5ef9syrx7h6t9x5tsfc0fg23fpa6hfpj9"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pubkey_address([2, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142], Address, [type(secp256k1), hrp(cosmos)]).`,
+				wantResult:  []types.TermResults{{"Address": "cosmos1evxmw5ef9syrx7h6t9x5tsfc0fg23fpahtvfw7"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pubkey_address([2, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142], Address, []).`,
+				wantResult:  []types.TermResults{{"Address": "cosmos1evxmw5ef9syrx7h6t9x5tsfc0fg23fpahtvfw7"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pubkey_address([0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31], Address, [hrp(okp4)]).`,
+				wantResult:  []types.TermResults{{"Address": "okp41vvxu62txcsekdygj23ythvjmfl6p9fyucdk5yr"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pubkey_address([0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31], Address, [type(ed25519), hrp(cosmos)]).`,
+				wantResult:  []types.TermResults{{"Address": "cosmos1vvxu62txcsekdygj23ythvjmfl6p9fyu43nucc"}},
+				wantSuccess: true,
+			},
+			{
+				query: `pubkey_address([2, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142], Address, [hrp(okp4)]),
+				        bech32_address(-(okp4, Hash), Address), length(Hash, 20).`,
+				wantResult: []types.TermResults{{
+					"Address": "okp41evxmw5ef9syrx7h6t9x5tsfc0fg23fpa6hfpj9",
+					"Hash":    "[203,13,183,83,41,44,8,51,122,250,89,77,69,193,56,122,80,168,164,61]",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pubkey_address([0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31], okp41evxmw5ef9syrx7h6t9x5tsfc0fg23fpa6hfpj9, [hrp(okp4)]).`,
+				wantSuccess: false,
+			},
+			{
+				query: `catch(pubkey_address([0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31], Address, [type(secp256k1)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Address": "_1", "E": "error(domain_error(public_key,[0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31]),/(pubkey_address,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(pubkey_address([2, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142], Address, [type(ed25519)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Address": "_1", "E": "error(domain_error(public_key,[2,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142,142]),/(pubkey_address,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{ // an uncompressed secp256k1 prefix on a key of the size of a compressed one
+				query: `catch(pubkey_address([4, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32], Address, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Address": "_1", "E": "error(domain_error(public_key,[4,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32]),/(pubkey_address,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(pubkey_address([1, 2, 3], Address, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Address": "_1", "E": "error(domain_error(public_key,[1,2,3]),/(pubkey_address,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(pubkey_address([0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31], Address, [type(secp256r1)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Address": "_1", "E": "error(domain_error(algorithm,secp256r1),/(pubkey_address,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(pubkey_address([0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31], Address, [hrp("okp4")]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Address": "_1", "E": "error(type_error(atom,[o,k,p,'4']),/(pubkey_address,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(pubkey_address(Key, Address, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Key": "_1", "Address": "_1", "E": "error(instantiation_error,/(pubkey_address,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(pubkey_address([1, 256], Address, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Address": "_1", "E": "error(type_error(byte,256),/(pubkey_address,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(pubkey_address([0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31], Address, [prefix(okp4)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Address": "_1", "E": "error(domain_error(option,prefix(okp4)),/(pubkey_address,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(pubkey_address([0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31], Address, [hrp('')]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Address": "_1", "E": "error(domain_error(hrp,''),/(pubkey_address,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(pubkey_address([0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31], Address, [hrp('Okp4')]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Address": "_1", "E": "error(domain_error(hrp,'Okp4'),/(pubkey_address,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(pubkey_address([0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31], Address, [hrp('okp 4')]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Address": "_1", "E": "error(domain_error(hrp,'okp 4'),/(pubkey_address,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("pubkey_address"), PubkeyAddress)
+						interpreter.Register2(engine.NewAtom("bech32_address"), Bech32Address)
+						interpreter.Register2(engine.NewAtom("length"), engine.Length)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
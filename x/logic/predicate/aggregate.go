@@ -0,0 +1,220 @@
+package predicate
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+var (
+	// AtomCount is the term count, the aggregation counting the solutions of a goal.
+	AtomCount = engine.NewAtom("count")
+
+	// AtomSum are terms with principal functor sum/1, the aggregation summing an expression over the solutions of a
+	// goal.
+	AtomSum = engine.NewAtom("sum")
+
+	// AtomMax are terms with principal functor max/1 or max/2, the aggregation of the maximum of an expression over the
+	// solutions of a goal.
+	AtomMax = engine.NewAtom("max")
+
+	// AtomMin are terms with principal functor min/1 or min/2, the aggregation of the minimum of an expression over the
+	// solutions of a goal.
+	AtomMin = engine.NewAtom("min")
+
+	// AtomBag are terms with principal functor bag/1, the aggregation of the instances of a template for the solutions
+	// of a goal.
+	AtomBag = engine.NewAtom("bag")
+
+	// AtomSet are terms with principal functor set/1, the aggregation of the sorted distinct instances of a template for
+	// the solutions of a goal.
+	AtomSet = engine.NewAtom("set")
+
+	// AtomAggregateSpec is the term used to indicate the aggregation specification domain in a domain error.
+	AtomAggregateSpec = engine.NewAtom("aggregate_spec")
+)
+
+// AggregateAll is a predicate which aggregates the solutions of a goal into a single result.
+//
+// The signature is as follows:
+//
+//	aggregate_all(+Template, :Goal, -Result) is semidet
+//
+// Where:
+//   - Template is the aggregation to compute (see below).
+//   - Goal is the goal to run.
+//   - Result is the aggregated result.
+//
+// The supported aggregations are, as in SWI-Prolog:
+//   - count: the number of solutions of Goal.
+//   - sum(Expr): the sum of the values of Expr for the solutions of Goal, 0 if there is none.
+//   - max(Expr): the maximum value of Expr for the solutions of Goal, failing if there is none.
+//   - max(Expr, Witness): max(Max, Witness), where Max is the maximum value of Expr and Witness the instance of Witness
+//     for the first solution giving it, failing if there is none.
+//   - min(Expr) and min(Expr, Witness): the same as max/1 and max/2 for the minimum value of Expr.
+//   - bag(Template): the list of the instances of Template for the solutions of Goal, as findall/3 does.
+//   - set(Template): the sorted list of the distinct instances of Template for the solutions of Goal.
+//
+// Expr is an arithmetic expression, or an Atom holding the decimal representation of an integer of arbitrary size, as
+// the amounts of the coins (see bignum_add/3). The integer results are computed without overflow: a result exceeding
+// the range of the Integers is given as an Atom holding its decimal representation. As for is/2, an Expr involving
+// floats raises an evaluation_error(nondeterministic_float).
+//
+// An unsupported Template raises a domain_error(aggregate_spec, Template), and a cyclic instance of Witness a
+// type_error(acyclic_term, _). The errors raised by Goal are propagated, and the ones raised by the evaluation of Expr
+// are raised by aggregate_all/3.
+//
+// Examples:
+//
+//	# Count the solutions of a goal.
+//	- aggregate_all(count, member(_, [a, b]), Count).
+//
+//	# Sum the amounts of a list of coins.
+//	- aggregate_all(sum(Amount), member(coin(uknow, Amount), [coin(uknow, '18446744073709551615'), coin(uknow, 1)]), Sum).
+//
+//	# Get the largest amount, and its denomination.
+//	- aggregate_all(max(Amount, Denom), member(Denom-Amount, [uknow-100, uatom-200]), max(Max, Which)).
+func AggregateAll(vm *engine.VM, template, goal, result engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		switch t := env.Resolve(template).(type) {
+		case engine.Atom:
+			if t == AtomCount {
+				count := 0
+				if _, err := engine.Call(vm, goal, func(*engine.Env) *engine.Promise {
+					count++
+					return engine.Bool(false)
+				}, env).Force(ctx); err != nil {
+					return engine.Error(err)
+				}
+				return engine.Unify(vm, result, engine.Integer(count), cont, env)
+			}
+		case engine.Compound:
+			switch {
+			case t.Functor() == AtomBag && t.Arity() == 1:
+				return engine.FindAll(vm, t.Arg(0), goal, result, cont, env)
+			case t.Functor() == AtomSet && t.Arity() == 1:
+				bag := engine.NewVariable()
+				return engine.FindAll(vm, t.Arg(0), goal, bag, func(env *engine.Env) *engine.Promise {
+					return engine.Sort(vm, bag, result, cont, env)
+				}, env)
+			case t.Functor() == AtomSum && t.Arity() == 1:
+				sum := new(big.Int)
+				if err := aggregateValues(ctx, vm, t.Arg(0), nil, goal, func(n *big.Int, _ engine.Term) {
+					sum.Add(sum, n)
+				}, env); err != nil {
+					return engine.Error(err)
+				}
+				return engine.Unify(vm, result, bigIntToTerm(sum), cont, env)
+			case (t.Functor() == AtomMax || t.Functor() == AtomMin) && (t.Arity() == 1 || t.Arity() == 2):
+				return aggregateExtremum(ctx, vm, t, goal, result, cont, env)
+			}
+		}
+		return engine.Error(domainError(AtomAggregateSpec, template, env))
+	})
+}
+
+// aggregateExtremum unifies the given result with the maximum, or the minimum, of the expression of the given
+// max(Expr), max(Expr, Witness), min(Expr) or min(Expr, Witness) template over the solutions of the given goal.
+func aggregateExtremum(
+	ctx context.Context, vm *engine.VM, template engine.Compound, goal, result engine.Term, cont engine.Cont, env *engine.Env,
+) *engine.Promise {
+	var witness engine.Term
+	if template.Arity() == 2 {
+		witness = template.Arg(1)
+	}
+
+	var best *big.Int
+	var bestWitness engine.Term
+	if err := aggregateValues(ctx, vm, template.Arg(0), witness, goal, func(n *big.Int, w engine.Term) {
+		c := 0
+		if best != nil {
+			c = n.Cmp(best)
+		}
+		if best == nil || (template.Functor() == AtomMax && c > 0) || (template.Functor() == AtomMin && c < 0) {
+			best, bestWitness = n, w
+		}
+	}, env); err != nil {
+		return engine.Error(err)
+	}
+	if best == nil {
+		return engine.Bool(false)
+	}
+
+	if witness == nil {
+		return engine.Unify(vm, result, bigIntToTerm(best), cont, env)
+	}
+	return engine.Unify(vm, result, template.Functor().Apply(bigIntToTerm(best), bestWitness), cont, env)
+}
+
+// aggregateValues calls the given function with the value of the given expression, and the instance of the given
+// witness, if any, for each solution of the given goal.
+func aggregateValues(
+	ctx context.Context, vm *engine.VM, expr, witness, goal engine.Term, f func(*big.Int, engine.Term),
+	env *engine.Env,
+) error {
+	_, err := engine.Call(vm, goal, func(solution *engine.Env) *engine.Promise {
+		n, err := evalAggregateNumber(ctx, vm, expr, solution)
+		if err != nil {
+			return engine.Error(aggregateError(err, solution, env))
+		}
+		var w engine.Term
+		if witness != nil {
+			if isCyclicTerm(witness, solution) {
+				return engine.Error(cyclicTermError(env))
+			}
+			w = resolveTerm(witness, solution)
+		}
+		f(n, w)
+		return engine.Bool(false)
+	}, env).Force(ctx)
+	return err
+}
+
+// aggregateError returns the given error raised while evaluating the expression of a solution of the goal, an ISO
+// error having its context replaced by the one of the given environment of aggregate_all/3, as the environment of the
+// solution is the one of the last predicate called by the goal.
+func aggregateError(err error, solution, env *engine.Env) error {
+	var exception engine.Exception
+	if !errors.As(err, &exception) {
+		return err
+	}
+	e, ok := solution.Resolve(exception.Term()).(engine.Compound)
+	if !ok || e.Functor() != AtomError || e.Arity() != 2 {
+		return err
+	}
+	return isoError(resolveTerm(e.Arg(0), solution), env)
+}
+
+// evalAggregateNumber evaluates the given expression, an Atom holding the decimal representation of an integer being
+// taken as this integer, and an expression involving floats raising an evaluation_error(nondeterministic_float), as
+// is/2 does.
+func evalAggregateNumber(ctx context.Context, vm *engine.VM, expr engine.Term, env *engine.Env) (*big.Int, error) {
+	if atom, ok := env.Resolve(expr).(engine.Atom); ok {
+		if i, ok := new(big.Int).SetString(atom.String(), 10); ok {
+			return i, nil
+		}
+	}
+
+	value := engine.NewVariable()
+	var n *big.Int
+	if _, err := Is(vm, value, expr, func(env *engine.Env) *engine.Promise {
+		if i, ok := env.Resolve(value).(engine.Integer); ok {
+			n = big.NewInt(int64(i))
+		}
+		return engine.Bool(true)
+	}, env).Force(ctx); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// bigIntToTerm returns the given integer as an Integer, or as an Atom holding its decimal representation if it exceeds
+// the range of the Integers.
+func bigIntToTerm(n *big.Int) engine.Term {
+	if n.IsInt64() {
+		return engine.Integer(n.Int64())
+	}
+	return engine.NewAtom(n.String())
+}
@@ -0,0 +1,243 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestAggregateAll(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		program := `p(1). p(3). p(2). p(3).
+coin(uknow, '18446744073709551615'). coin(uatom, 1). coin(uknow, 10).
+f(2.5).
+pf(X) :- p(X).
+pf(X) :- f(X).`
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `aggregate_all(count, p(_), Count).`,
+				wantResult:  []types.TermResults{{"Count": "4"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(count, p(4), Count).`,
+				wantResult:  []types.TermResults{{"Count": "0"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(sum(X), p(X), Sum).`,
+				wantResult:  []types.TermResults{{"Sum": "9", "X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(sum(+(*(X, 2), 1)), p(X), Sum).`,
+				wantResult:  []types.TermResults{{"Sum": "22", "X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(sum(X), p(4), Sum).`,
+				wantResult:  []types.TermResults{{"Sum": "0", "X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(sum(A), coin(_, A), Sum).`,
+				wantResult:  []types.TermResults{{"Sum": "'18446744073709551626'", "A": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(sum(A), coin(uatom, A), Sum).`,
+				wantResult:  []types.TermResults{{"Sum": "1", "A": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(aggregate_all(sum(X), pf(X), _), error(evaluation_error(nondeterministic_float), _), true).`,
+				wantResult:  []types.TermResults{{"X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(aggregate_all(sum(/(X, 2)), p(X), _), error(evaluation_error(nondeterministic_float), _), true).`,
+				wantResult:  []types.TermResults{{"X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(sum(X), p(X), 9).`,
+				wantResult:  []types.TermResults{{"X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(max(X), p(X), Max), aggregate_all(min(X), p(X), Min).`,
+				wantResult:  []types.TermResults{{"Max": "3", "Min": "1", "X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(max(A), coin(_, A), Max).`,
+				wantResult:  []types.TermResults{{"Max": "'18446744073709551615'", "A": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(max(A, D), coin(D, A), max(Max, Which)).`,
+				wantResult:  []types.TermResults{{"Max": "'18446744073709551615'", "Which": "uknow", "A": "_1", "D": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(min(A, D-A), coin(D, A), Min).`,
+				wantResult:  []types.TermResults{{"Min": "min(1,uatom-1)", "A": "_1", "D": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(aggregate_all(max(X), f(X), _), error(evaluation_error(nondeterministic_float), _), true).`,
+				wantResult:  []types.TermResults{{"X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(max(X), p(4), _).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `aggregate_all(min(X, X), p(4), _).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `aggregate_all(bag(X), p(X), Bag).`,
+				wantResult:  []types.TermResults{{"Bag": "[1,3,2,3]", "X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(set(X), p(X), Set).`,
+				wantResult:  []types.TermResults{{"Set": "[1,2,3]", "X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(set(D), coin(D, _), Set).`,
+				wantResult:  []types.TermResults{{"Set": "[uatom,uknow]", "D": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `aggregate_all(bag(X), p(4), Bag), aggregate_all(set(X), p(4), Set).`,
+				wantResult:  []types.TermResults{{"Bag": "[]", "Set": "[]", "X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(aggregate_all(avg(X), p(X), _), error(domain_error(aggregate_spec, avg(X)), _), true).`,
+				wantResult:  []types.TermResults{{"X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(aggregate_all(_, p(_), _), error(instantiation_error, _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(aggregate_all(sum(D), coin(D, _), _), error(type_error(evaluable, /(uknow, 0)), _), true).`,
+				wantResult:  []types.TermResults{{"D": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(aggregate_all(sum(D), coin(D, _), _), error(_, C), true).`,
+				wantResult:  []types.TermResults{{"D": "_1", "C": "/(aggregate_all,3)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(aggregate_all(max(X, W), (pf(X), W = w), _), error(_, C), true).`,
+				wantResult:  []types.TermResults{{"X": "_1", "W": "_1", "C": "/(aggregate_all,3)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(aggregate_all(max(1, W), W = f(W), _), error(E, C), true).`,
+				wantResult:  []types.TermResults{{"W": "_1", "E": "type_error(acyclic_term,_1)", "C": "/(aggregate_all,3)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(aggregate_all(sum(X), member(X, [a]), _), error(E, C), true).`,
+				wantResult:  []types.TermResults{{"X": "_1", "E": "type_error(evaluable,/(a,0))", "C": "/(aggregate_all,3)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(aggregate_all(count, _, _), error(instantiation_error, _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+		}
+		for i := range cases {
+			cases[i].program = program
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("aggregate_all"), AggregateAll)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
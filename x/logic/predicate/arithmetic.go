@@ -0,0 +1,97 @@
+package predicate
+
+import (
+	"slices"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+// AtomNondeterministicFloat is the term used to indicate, in an evaluation error, an arithmetic evaluation involving
+// floats, which is refused as its result may differ from one architecture to another.
+var AtomNondeterministicFloat = engine.NewAtom("nondeterministic_float")
+
+// floatFunctions are the evaluable functors, by name and arity, whose value is a float whatever their arguments.
+var floatFunctions = map[engine.Atom][]int{
+	engine.NewAtom("pi"):                    {0},
+	engine.NewAtom("/"):                     {2},
+	engine.NewAtom("**"):                    {2},
+	engine.NewAtom("float"):                 {1},
+	engine.NewAtom("float_integer_part"):    {1},
+	engine.NewAtom("float_fractional_part"): {1},
+	engine.NewAtom("sqrt"):                  {1},
+	engine.NewAtom("exp"):                   {1},
+	engine.NewAtom("log"):                   {1},
+	engine.NewAtom("sin"):                   {1},
+	engine.NewAtom("cos"):                   {1},
+	engine.NewAtom("tan"):                   {1},
+	engine.NewAtom("asin"):                  {1},
+	engine.NewAtom("acos"):                  {1},
+	engine.NewAtom("atan"):                  {1, 2},
+	engine.NewAtom("atan2"):                 {2},
+}
+
+// Is is a predicate which evaluates an arithmetic expression, as the ISO is/2 does, restricted to the integers.
+//
+// The signature is as follows:
+//
+//	is(-Result, +Expression) is semidet
+//
+// Where:
+//   - Result is the value of Expression.
+//   - Expression is the arithmetic expression to evaluate.
+//
+// The floating point arithmetic is not guaranteed to give the same results on all the architectures the validators
+// run on, e.g. because of fused multiply-add instructions or of the implementation of the transcendental functions,
+// which would break the consensus on the results of the queries. Therefore, the arithmetic is restricted to the
+// integers: an evaluation involving a float, either as a Float in the expression or as a function whose value is a
+// float, i.e. /, **, float/1, float_integer_part/1, float_fractional_part/1, sqrt/1, exp/1, log/1, sin/1, cos/1, tan/1,
+// asin/1, acos/1, atan/1, atan/2, atan2/2 or pi, raises an evaluation_error(nondeterministic_float). The same applies
+// to the arithmetic comparisons (=:=, =\=, <, =<, > and >=) and to aggregate_all/3.
+//
+// The integer division is given by // (truncating) or div (flooring), and the fixed-point decimals and integers of
+// arbitrary size by the dec_* and bignum_* predicates, e.g. dec_div/3 for a ratio. The floats remain usable as terms,
+// e.g. to be unified, compared by the standard order of terms or converted from and into JSON.
+//
+// Examples:
+//
+//	# Compute a fee in basis points.
+//	- Fee is 1000000 * 25 // 10000.
+func Is(vm *engine.VM, result, expression engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return DeterministicArithmetic(engine.Is)(vm, result, expression, cont, env)
+}
+
+// DeterministicArithmetic returns the given arithmetic predicate, evaluating its two arguments, raising an
+// evaluation_error(nondeterministic_float) if any of them involves a float (see is/2).
+func DeterministicArithmetic(p engine.Predicate2) engine.Predicate2 {
+	return func(vm *engine.VM, x, y engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+		for _, expression := range []engine.Term{x, y} {
+			if err := checkDeterministicArithmetic(expression, env); err != nil {
+				return engine.Error(err)
+			}
+		}
+		return p(vm, x, y, cont, env)
+	}
+}
+
+// checkDeterministicArithmetic checks the given arithmetic expression involves no float, i.e. neither a Float nor a
+// function whose value is a float, raising an evaluation_error(nondeterministic_float) otherwise.
+func checkDeterministicArithmetic(expression engine.Term, env *engine.Env) error {
+	switch e := env.Resolve(expression).(type) {
+	case engine.Float:
+		return isoError(AtomEvaluationError.Apply(AtomNondeterministicFloat), env)
+	case engine.Atom:
+		if slices.Contains(floatFunctions[e], 0) {
+			return isoError(AtomEvaluationError.Apply(AtomNondeterministicFloat), env)
+		}
+	case engine.Compound:
+		if slices.Contains(floatFunctions[e.Functor()], e.Arity()) {
+			return isoError(AtomEvaluationError.Apply(AtomNondeterministicFloat), env)
+		}
+		for i := 0; i < e.Arity(); i++ {
+			if err := checkDeterministicArithmetic(e.Arg(i), env); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestDeterministicArithmetic(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `is(X, +(*(1000000, 25), 1)).`,
+				wantResult:  []types.TermResults{{"X": "25000001"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `is(X, //(7, 2)), is(Y, div(-7, 2)), is(Z, mod(-7, 2)).`,
+				wantResult:  []types.TermResults{{"X": "3", "Y": "-4", "Z": "1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `is(X, max(min(3, 5), abs(-4))).`,
+				wantResult:  []types.TermResults{{"X": "4"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `is(4, +(2, 2)).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `<(1, 2), >=(2, 2), =:=(+(1, 1), 2).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `<(2, 1).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(is(_, +(1, 1.5)), error(evaluation_error(nondeterministic_float), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(is(_, /(4, 2)), error(evaluation_error(nondeterministic_float), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(is(_, truncate(sqrt(2))), error(evaluation_error(nondeterministic_float), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(is(_, **(2, 3)), error(evaluation_error(nondeterministic_float), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(is(_, *(2, pi)), error(evaluation_error(nondeterministic_float), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(is(_, atan(1, 2)), error(evaluation_error(nondeterministic_float), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `X = 2.5, catch(is(_, +(X, 1)), error(evaluation_error(nondeterministic_float), _), true).`,
+				wantResult:  []types.TermResults{{"X": "2.5"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(<(1, 1.5), error(evaluation_error(nondeterministic_float), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(=:=(/(1, 3), 0), error(evaluation_error(nondeterministic_float), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(is(_, foo), error(type_error(evaluable, /(foo, 0)), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("is"), Is)
+						interpreter.Register2(engine.NewAtom("<"), DeterministicArithmetic(engine.LessThan))
+						interpreter.Register2(engine.NewAtom(">="), DeterministicArithmetic(engine.GreaterThanOrEqual))
+						interpreter.Register2(engine.NewAtom("=:="), DeterministicArithmetic(engine.Equal))
+						interpreter.Register2(engine.NewAtom("="), engine.Unify)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
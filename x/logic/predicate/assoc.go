@@ -0,0 +1,406 @@
+package predicate
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+var (
+	// AtomT is the term t, the empty association list, and the principal functor t/5 of its nodes.
+	AtomT = engine.NewAtom("t")
+
+	// AtomAssoc is the term used to indicate the association list type in a type error.
+	AtomAssoc = engine.NewAtom("assoc")
+
+	// AtomUniqueKeyPairs is the term used to indicate the domain of the pairs of unique keys in a domain error.
+	AtomUniqueKeyPairs = engine.NewAtom("unique_key_pairs")
+)
+
+// assocNode is a node of an association list, i.e. an AVL tree represented by the term t(Key, Value, Balance, Left,
+// Right), as in the library(assoc) of SWI-Prolog, where Balance is <, = or > for a Left subtree shallower than, as deep
+// as or deeper than the Right one, the empty tree being t.
+type assocNode struct {
+	key, value  engine.Term
+	balance     engine.Atom
+	left, right engine.Term
+}
+
+// term returns the term representing the node.
+func (n assocNode) term() engine.Term {
+	return AtomT.Apply(n.key, n.value, n.balance, n.left, n.right)
+}
+
+// EmptyAssoc is a predicate which unifies the given term with the empty association list.
+//
+// The signature is as follows:
+//
+//	empty_assoc(?Assoc) is semidet
+//
+// Where:
+//   - Assoc is the empty association list.
+//
+// Examples:
+//
+//	# Create an empty association list.
+//	- empty_assoc(Assoc).
+func EmptyAssoc(vm *engine.VM, assoc engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Unify(vm, assoc, AtomT, cont, env)
+}
+
+// ListToAssoc is a predicate which converts a list of pairs into an association list.
+//
+// The signature is as follows:
+//
+//	list_to_assoc(+Pairs, -Assoc) is det
+//
+// Where:
+//   - Pairs is the list of the Key-Value pairs of the association list, in any order.
+//   - Assoc is the association list mapping each Key to its Value.
+//
+// An association list is a balanced binary tree (AVL tree) of its pairs, ordered by their keys according to the
+// standard order of terms, so that a value is looked up, added or replaced in logarithmic time (see get_assoc/3 and
+// put_assoc/4), e.g. for the objects of a large JSON document. Its representation only depends on its pairs, not on
+// the order in which they are given.
+//
+// A Pairs holding twice the same key raises a domain_error(unique_key_pairs, Pairs), and an element of Pairs which is
+// not a pair a type_error(pair, Element).
+//
+// Examples:
+//
+//	# Convert the fields of a JSON object into an association list.
+//	- json_prolog('{"name": "okp4", "height": 42}', json(Fields)), list_to_assoc(Fields, Assoc).
+func ListToAssoc(vm *engine.VM, pairs, assoc engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		elems, err := listElements(pairs, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		nodes := make([]assocNode, 0, len(elems))
+		for _, elem := range elems {
+			pair, ok := elem.(engine.Compound)
+			if !ok || pair.Functor() != AtomPair || pair.Arity() != 2 {
+				return engine.Error(typeError(engine.NewAtom("pair"), elem, env))
+			}
+			nodes = append(nodes, assocNode{key: env.Resolve(pair.Arg(0)), value: pair.Arg(1)})
+		}
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return nodes[i].key.Compare(nodes[j].key, env) < 0
+		})
+		for i := 1; i < len(nodes); i++ {
+			if nodes[i].key.Compare(nodes[i-1].key, env) == 0 {
+				return engine.Error(domainError(AtomUniqueKeyPairs, pairs, env))
+			}
+		}
+
+		tree, _ := buildAssoc(nodes)
+		return engine.Unify(vm, assoc, tree, cont, env)
+	})
+}
+
+// GetAssoc is a predicate which looks up the value of a key in an association list.
+//
+// The signature is as follows:
+//
+//	get_assoc(+Key, +Assoc, ?Value) is semidet
+//
+// Where:
+//   - Key is the key to look up.
+//   - Assoc is the association list (see list_to_assoc/2).
+//   - Value is the value associated to Key in Assoc.
+//
+// The predicate fails if there is no Key in Assoc, and an Assoc which is not an association list raises a
+// type_error(assoc, Assoc).
+//
+// Examples:
+//
+//	# Get the value of a key.
+//	- list_to_assoc([a-1, b-2], Assoc), get_assoc(b, Assoc, Value).
+func GetAssoc(vm *engine.VM, key, assoc, value engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		tree := assoc
+		for {
+			node, ok, err := termToAssocNode(tree, env)
+			if err != nil {
+				return engine.Error(err)
+			}
+			if !ok {
+				return engine.Bool(false)
+			}
+			switch c := env.Resolve(key).Compare(node.key, env); {
+			case c < 0:
+				tree = node.left
+			case c > 0:
+				tree = node.right
+			default:
+				return engine.Unify(vm, value, node.value, cont, env)
+			}
+		}
+	})
+}
+
+// PutAssoc is a predicate which associates a value to a key in an association list.
+//
+// The signature is as follows:
+//
+//	put_assoc(+Key, +Assoc0, +Value, -Assoc) is det
+//
+// Where:
+//   - Key is the key to associate Value to.
+//   - Assoc0 is the association list (see list_to_assoc/2).
+//   - Value is the value to associate to Key.
+//   - Assoc is Assoc0 in which Key is associated to Value, replacing its previous value, if any.
+//
+// An Assoc0 which is not an association list raises a type_error(assoc, Assoc0).
+//
+// Examples:
+//
+//	# Add a key to an association list.
+//	- list_to_assoc([a-1], Assoc0), put_assoc(b, Assoc0, 2, Assoc).
+func PutAssoc(vm *engine.VM, key, assoc0, value, assoc engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		tree, _, err := insertAssoc(assoc0, env.Resolve(key), value, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		return engine.Unify(vm, assoc, tree, cont, env)
+	})
+}
+
+// AssocToList is a predicate which converts an association list into the list of its pairs.
+//
+// The signature is as follows:
+//
+//	assoc_to_list(+Assoc, -Pairs) is det
+//
+// Where:
+//   - Assoc is the association list (see list_to_assoc/2).
+//   - Pairs is the list of the Key-Value pairs of Assoc, in ascending order of their keys.
+//
+// An Assoc which is not an association list raises a type_error(assoc, Assoc).
+//
+// Examples:
+//
+//	# Get the pairs of an association list.
+//	- list_to_assoc([b-2, a-1], Assoc), assoc_to_list(Assoc, Pairs).
+func AssocToList(vm *engine.VM, assoc, pairs engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return assocElements(vm, assoc, pairs, func(n assocNode) engine.Term {
+		return AtomPair.Apply(n.key, n.value)
+	}, cont, env)
+}
+
+// AssocToKeys is a predicate which converts an association list into the list of its keys.
+//
+// The signature is as follows:
+//
+//	assoc_to_keys(+Assoc, -Keys) is det
+//
+// Where:
+//   - Assoc is the association list (see list_to_assoc/2).
+//   - Keys is the list of the keys of Assoc, in ascending order.
+//
+// An Assoc which is not an association list raises a type_error(assoc, Assoc).
+//
+// Examples:
+//
+//	# Get the keys of an association list.
+//	- list_to_assoc([b-2, a-1], Assoc), assoc_to_keys(Assoc, Keys).
+func AssocToKeys(vm *engine.VM, assoc, keys engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return assocElements(vm, assoc, keys, func(n assocNode) engine.Term { return n.key }, cont, env)
+}
+
+// AssocToValues is a predicate which converts an association list into the list of its values.
+//
+// The signature is as follows:
+//
+//	assoc_to_values(+Assoc, -Values) is det
+//
+// Where:
+//   - Assoc is the association list (see list_to_assoc/2).
+//   - Values is the list of the values of Assoc, in ascending order of their keys.
+//
+// An Assoc which is not an association list raises a type_error(assoc, Assoc).
+//
+// Examples:
+//
+//	# Get the values of an association list.
+//	- list_to_assoc([b-2, a-1], Assoc), assoc_to_values(Assoc, Values).
+func AssocToValues(vm *engine.VM, assoc, values engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return assocElements(vm, assoc, values, func(n assocNode) engine.Term { return n.value }, cont, env)
+}
+
+// assocElements unifies the given list with the elements built from the nodes of the given association list, in
+// ascending order of their keys.
+func assocElements(
+	vm *engine.VM, assoc, list engine.Term, element func(assocNode) engine.Term, cont engine.Cont, env *engine.Env,
+) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		elems := make([]engine.Term, 0)
+		var walk func(tree engine.Term) error
+		walk = func(tree engine.Term) error {
+			node, ok, err := termToAssocNode(tree, env)
+			if err != nil || !ok {
+				return err
+			}
+			if err := walk(node.left); err != nil {
+				return err
+			}
+			elems = append(elems, element(node))
+			return walk(node.right)
+		}
+		if err := walk(assoc); err != nil {
+			return engine.Error(err)
+		}
+
+		return engine.Unify(vm, list, engine.List(elems...), cont, env)
+	})
+}
+
+// termToAssocNode converts the given term into a node of an association list, reporting false for the empty one.
+func termToAssocNode(tree engine.Term, env *engine.Env) (assocNode, bool, error) {
+	switch t := env.Resolve(tree).(type) {
+	case engine.Atom:
+		if t == AtomT {
+			return assocNode{}, false, nil
+		}
+	case engine.Compound:
+		if t.Functor() == AtomT && t.Arity() == 5 {
+			balance, ok := env.Resolve(t.Arg(2)).(engine.Atom)
+			if ok && (balance == AtomLess || balance == AtomEqual || balance == AtomGreater) {
+				return assocNode{
+					key:     env.Resolve(t.Arg(0)),
+					value:   t.Arg(1),
+					balance: balance,
+					left:    t.Arg(3),
+					right:   t.Arg(4),
+				}, true, nil
+			}
+		}
+	}
+	return assocNode{}, false, typeError(AtomAssoc, tree, env)
+}
+
+// buildAssoc builds the association list of the given nodes, sorted by their keys, and returns its height.
+func buildAssoc(nodes []assocNode) (engine.Term, int) {
+	if len(nodes) == 0 {
+		return AtomT, 0
+	}
+
+	mid := len(nodes) / 2
+	node := nodes[mid]
+	left, leftHeight := buildAssoc(nodes[:mid])
+	right, rightHeight := buildAssoc(nodes[mid+1:])
+	node.left, node.right = left, right
+	switch {
+	case leftHeight < rightHeight:
+		node.balance = AtomLess
+	case leftHeight > rightHeight:
+		node.balance = AtomGreater
+	default:
+		node.balance = AtomEqual
+	}
+
+	return node.term(), max(leftHeight, rightHeight) + 1
+}
+
+// insertAssoc associates the given value to the given key in the given association list, rebalancing it as an AVL
+// tree, and reports whether its height has grown.
+func insertAssoc(tree, key, value engine.Term, env *engine.Env) (engine.Term, bool, error) {
+	node, ok, err := termToAssocNode(tree, env)
+	if err != nil {
+		return nil, false, err
+	}
+	if !ok {
+		return assocNode{key: key, value: value, balance: AtomEqual, left: AtomT, right: AtomT}.term(), true, nil
+	}
+
+	c := key.Compare(node.key, env)
+	if c == 0 {
+		node.value = value
+		return node.term(), false, nil
+	}
+
+	if c < 0 {
+		left, grown, err := insertAssoc(node.left, key, value, env)
+		if err != nil {
+			return nil, false, err
+		}
+		node.left = left
+		if !grown {
+			return node.term(), false, nil
+		}
+		switch node.balance {
+		case AtomLess:
+			node.balance = AtomEqual
+			return node.term(), false, nil
+		case AtomEqual:
+			node.balance = AtomGreater
+			return node.term(), true, nil
+		default:
+			return rotateAssoc(node, true, env), false, nil
+		}
+	}
+
+	right, grown, err := insertAssoc(node.right, key, value, env)
+	if err != nil {
+		return nil, false, err
+	}
+	node.right = right
+	if !grown {
+		return node.term(), false, nil
+	}
+	switch node.balance {
+	case AtomGreater:
+		node.balance = AtomEqual
+		return node.term(), false, nil
+	case AtomEqual:
+		node.balance = AtomLess
+		return node.term(), true, nil
+	default:
+		return rotateAssoc(node, false, env), false, nil
+	}
+}
+
+// rotateAssoc rebalances the given node, whose left subtree, or right one, is two levels deeper than the other one
+// after an insertion, by a single or a double rotation.
+func rotateAssoc(node assocNode, leftHeavy bool, env *engine.Env) engine.Term {
+	// the rotations to the right mirror the ones to the left, the subtrees and balances being swapped.
+	heavy, light := AtomGreater, AtomLess
+	inner := func(n assocNode) engine.Term { return n.right }
+	outer := func(n assocNode) engine.Term { return n.left }
+	build := func(n assocNode, outerTree, innerTree engine.Term) assocNode {
+		n.left, n.right = outerTree, innerTree
+		return n
+	}
+	if !leftHeavy {
+		heavy, light = AtomLess, AtomGreater
+		inner, outer = outer, inner
+		build = func(n assocNode, outerTree, innerTree engine.Term) assocNode {
+			n.left, n.right = innerTree, outerTree
+			return n
+		}
+	}
+
+	child, _, _ := termToAssocNode(outer(node), env)
+	if child.balance == heavy {
+		node.balance, child.balance = AtomEqual, AtomEqual
+		return build(child, outer(child), build(node, inner(child), inner(node)).term()).term()
+	}
+
+	grandchild, _, _ := termToAssocNode(inner(child), env)
+	child.balance, node.balance = AtomEqual, AtomEqual
+	switch grandchild.balance {
+	case heavy:
+		node.balance = light
+	case light:
+		child.balance = heavy
+	}
+	grandchild.balance = AtomEqual
+	return build(
+		grandchild,
+		build(child, outer(child), outer(grandchild)).term(),
+		build(node, inner(grandchild), inner(node)).term(),
+	).term()
+}
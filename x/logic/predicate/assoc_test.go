@@ -0,0 +1,242 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestAssoc(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `empty_assoc(A).`,
+				wantResult:  []types.TermResults{{"A": "t"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `list_to_assoc([c-3, a-1, b-2], A).`,
+				wantResult:  []types.TermResults{{"A": "t(b,2,=,t(a,1,=,t,t),t(c,3,=,t,t))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `list_to_assoc([a-1, b-2], A).`,
+				wantResult:  []types.TermResults{{"A": "t(b,2,>,t(a,1,=,t,t),t)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `list_to_assoc([], A).`,
+				wantResult:  []types.TermResults{{"A": "t"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(list_to_assoc([a-1, a-2], _), error(domain_error(unique_key_pairs, [a-1, a-2]), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(list_to_assoc([a-1, b], _), error(type_error(pair, b), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(list_to_assoc([a-1|_], _), error(instantiation_error, _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `list_to_assoc([name-okp4, height-42, f(x)-[1]], A), get_assoc(height, A, V), get_assoc(f(x), A, W).`,
+				wantResult:  []types.TermResults{{"A": "t(name,okp4,=,t(height,42,=,t,t),t(f(x),[1],=,t,t))", "V": "42", "W": "[1]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `list_to_assoc([a-1, b-2], A), get_assoc(c, A, _).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `get_assoc(a, t, _).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(get_assoc(a, foo, _), error(type_error(assoc, foo), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(get_assoc(a, _, _), error(instantiation_error, _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `put_assoc(a, t, 1, A).`,
+				wantResult:  []types.TermResults{{"A": "t(a,1,=,t,t)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `list_to_assoc([a-1, b-2], A0), put_assoc(a, A0, 3, A).`,
+				wantResult:  []types.TermResults{{"A0": "t(b,2,>,t(a,1,=,t,t),t)", "A": "t(b,2,>,t(a,3,=,t,t),t)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `put_assoc(1, t, a, A1), put_assoc(2, A1, b, A2), put_assoc(3, A2, c, A).`,
+				wantResult:  []types.TermResults{{"A1": "t(1,a,=,t,t)", "A2": "t(1,a,<,t,t(2,b,=,t,t))", "A": "t(2,b,=,t(1,a,=,t,t),t(3,c,=,t,t))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `put_assoc(3, t, c, A1), put_assoc(2, A1, b, A2), put_assoc(1, A2, a, A).`,
+				wantResult:  []types.TermResults{{"A1": "t(3,c,=,t,t)", "A2": "t(3,c,>,t(2,b,=,t,t),t)", "A": "t(2,b,=,t(1,a,=,t,t),t(3,c,=,t,t))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `put_assoc(3, t, c, A1), put_assoc(1, A1, a, A2), put_assoc(2, A2, b, A).`,
+				wantResult:  []types.TermResults{{"A1": "t(3,c,=,t,t)", "A2": "t(3,c,>,t(1,a,=,t,t),t)", "A": "t(2,b,=,t(1,a,=,t,t),t(3,c,=,t,t))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `put_assoc(1, t, a, A1), put_assoc(3, A1, c, A2), put_assoc(2, A2, b, A).`,
+				wantResult:  []types.TermResults{{"A1": "t(1,a,=,t,t)", "A2": "t(1,a,<,t,t(3,c,=,t,t))", "A": "t(2,b,=,t(1,a,=,t,t),t(3,c,=,t,t))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(put_assoc(a, foo, 1, _), error(type_error(assoc, foo), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `list_to_assoc([c-3, a-1, b-2], A), assoc_to_list(A, L), assoc_to_keys(A, K), assoc_to_values(A, V).`,
+				wantResult:  []types.TermResults{{"A": "t(b,2,=,t(a,1,=,t,t),t(c,3,=,t,t))", "L": "[a-1,b-2,c-3]", "K": "[a,b,c]", "V": "[1,2,3]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `assoc_to_list(t, L).`,
+				wantResult:  []types.TermResults{{"L": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(assoc_to_keys(t(a, 1, x, t, t), _), error(type_error(assoc, t(a, 1, x, t, t)), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register1(engine.NewAtom("empty_assoc"), EmptyAssoc)
+						interpreter.Register2(engine.NewAtom("list_to_assoc"), ListToAssoc)
+						interpreter.Register3(engine.NewAtom("get_assoc"), GetAssoc)
+						interpreter.Register4(engine.NewAtom("put_assoc"), PutAssoc)
+						interpreter.Register2(engine.NewAtom("assoc_to_list"), AssocToList)
+						interpreter.Register2(engine.NewAtom("assoc_to_keys"), AssocToKeys)
+						interpreter.Register2(engine.NewAtom("assoc_to_values"), AssocToValues)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestPutAssocBalance(t *testing.T) {
+	Convey("Given an association list built by inserting keys in a scrambled order", t, func() {
+		env := engine.NewEnv()
+		tree := engine.Term(AtomT)
+		const n = 1000
+		for i := 0; i < n; i++ {
+			var err error
+			tree, _, err = insertAssoc(tree, engine.Integer((i*7919)%n), engine.Integer(i), env)
+			So(err, ShouldBeNil)
+		}
+
+		Convey("Then it should be a balanced search tree of all the keys", func() {
+			var keys []engine.Term
+			var check func(tree engine.Term) int
+			check = func(tree engine.Term) int {
+				node, ok, err := termToAssocNode(tree, env)
+				So(err, ShouldBeNil)
+				if !ok {
+					return 0
+				}
+				left := check(node.left)
+				keys = append(keys, node.key)
+				right := check(node.right)
+				So(left-right, ShouldBeBetweenOrEqual, -1, 1)
+				So(node.balance, ShouldEqual, map[int]engine.Atom{-1: AtomLess, 0: AtomEqual, 1: AtomGreater}[left-right])
+				return max(left, right) + 1
+			}
+			So(check(tree), ShouldBeLessThanOrEqualTo, 14)
+			So(len(keys), ShouldEqual, n)
+			for i, key := range keys {
+				So(key, ShouldEqual, engine.Integer(i))
+			}
+		})
+	})
+}
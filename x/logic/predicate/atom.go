@@ -28,6 +28,10 @@ var (
 
 	// AtomNull is the term null.
 	AtomNull = engine.NewAtom("null")
+
+	// AtomBig are terms with principal functor big/1.
+	// It is used to represent big integers in json objects.
+	AtomBig = engine.NewAtom("big")
 )
 
 // MakeNull returns the compound term @(null).
@@ -0,0 +1,151 @@
+package predicate
+
+import (
+	"context"
+	"math"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+var (
+	// AtomInf is the term inf, used to denote an unbounded upper limit.
+	AtomInf = engine.NewAtom("inf")
+
+	// AtomInfinite is the term infinite, used to denote an unbounded upper limit.
+	AtomInfinite = engine.NewAtom("infinite")
+
+	// AtomNotZero is the term used to indicate the non-zero domain in a domain error.
+	AtomNotZero = engine.NewAtom("not_zero")
+)
+
+// Between is a predicate which enumerates the integers of a range.
+//
+// The signature is as follows:
+//
+//	between(+Low, +High, ?Value) is nondet
+//
+// Where:
+//   - Low is the lower bound of the range, as an Integer.
+//   - High is the upper bound of the range, as an Integer, or inf or infinite for an unbounded range.
+//   - Value is an integer of the range, between Low and High inclusive.
+//
+// If Value is bound, the predicate checks it belongs to the range. Otherwise, it enumerates the integers of the range
+// in ascending order, one on each backtracking. The integers are generated lazily, with a single choice point whatever
+// the size of the range, so that iterating over a large range, e.g. a window of block heights, runs in constant memory.
+//
+// Examples:
+//
+//	# Enumerate the integers from 1 to 3.
+//	- between(1, 3, X).
+//
+//	# Check an integer belongs to a range.
+//	- between(1, inf, 42).
+func Between(vm *engine.VM, low, high, value engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return BetweenStep(vm, low, high, engine.Integer(1), value, cont, env)
+}
+
+// BetweenStep is a predicate which enumerates the integers of a range by a given step.
+//
+// The signature is as follows:
+//
+//	between(+Low, +High, +Step, ?Value) is nondet
+//
+// Where:
+//   - Low is the first integer of the range, as an Integer.
+//   - High is the bound of the range, as an Integer, or inf or infinite for an unbounded range.
+//   - Step is the difference between two consecutive integers of the range, as a non-zero Integer, which is negative
+//     for a descending range.
+//   - Value is an integer of the range, i.e. Low + K * Step for a non-negative K, not beyond High.
+//
+// The integers are enumerated from Low, as with between/3, in ascending order for a positive Step, up to High, and in
+// descending order for a negative one, down to High, the range being empty if High is inf or infinite. A Step of zero
+// raises a domain_error(not_zero, Step).
+//
+// Examples:
+//
+//	# Enumerate the block heights of a window, every 100 blocks.
+//	- between(1000, 2000, 100, Height).
+//
+//	# Enumerate the integers from 10 down to 0, two by two.
+//	- between(10, 0, -2, X).
+func BetweenStep(vm *engine.VM, low, high, step, value engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	first, ok := env.Resolve(low).(engine.Integer)
+	if !ok {
+		return engine.Error(typeError(AtomInteger, low, env))
+	}
+	s, ok := env.Resolve(step).(engine.Integer)
+	if !ok {
+		return engine.Error(typeError(AtomInteger, step, env))
+	}
+	if s == 0 {
+		return engine.Error(domainError(AtomNotZero, step, env))
+	}
+	last, err := rangeBound(high, s, env)
+	if err != nil {
+		return engine.Error(err)
+	}
+	if (s > 0 && first > last) || (s < 0 && first < last) {
+		return engine.Bool(false)
+	}
+
+	switch v := env.Resolve(value).(type) {
+	case engine.Integer:
+		inRange := v >= min(first, last) && v <= max(first, last)
+		if !inRange || rangeDistance(first, v, s)%rangeDistance(0, s, s) != 0 {
+			return engine.Bool(false)
+		}
+		return cont(env)
+	case engine.Variable:
+		next := first
+		return lazyChoices(func(ctx context.Context) (*engine.Promise, bool) {
+			current := next
+			more := rangeDistance(current, last, s) >= rangeDistance(0, s, s)
+			next += s
+			return engine.Unify(vm, value, current, cont, env), more
+		})
+	default:
+		return engine.Error(typeError(AtomInteger, value, env))
+	}
+}
+
+// rangeBound returns the bound of a range as an Integer, an unbounded range being bounded by the greatest Integer for
+// a positive step, and empty for a negative one.
+func rangeBound(high engine.Term, step engine.Integer, env *engine.Env) (engine.Integer, error) {
+	switch h := env.Resolve(high).(type) {
+	case engine.Integer:
+		return h, nil
+	case engine.Atom:
+		if h == AtomInf || h == AtomInfinite {
+			return math.MaxInt64, nil
+		}
+	}
+	return 0, typeError(AtomInteger, high, env)
+}
+
+// rangeDistance returns the distance from an integer to another one in the direction of the given step, the latter
+// not preceding the former, which may exceed the greatest Integer.
+func rangeDistance(from, to, step engine.Integer) uint64 {
+	if step < 0 {
+		return uint64(from) - uint64(to)
+	}
+	return uint64(to) - uint64(from)
+}
+
+// lazyChoices returns a promise trying the alternatives returned by next one after the other, as long as next reports
+// more of them, while keeping a single choice point whatever their number, so that the memory used does not grow with
+// them, unlike a chain of engine.Delay.
+func lazyChoices(next func(ctx context.Context) (*engine.Promise, bool)) *engine.Promise {
+	var p *engine.Promise
+	var alternative func(ctx context.Context) *engine.Promise
+	alternative = func(ctx context.Context) *engine.Promise {
+		q, more := next(ctx)
+		if more {
+			// Once an alternative has returned, the engine drops the first pending alternative of the promise, which is
+			// here replaced beforehand by a placeholder followed by the alternative itself, so that it remains pending.
+			*p = *engine.Delay(nil, alternative)
+		}
+		return q
+	}
+	p = engine.Delay(alternative)
+	return p
+}
@@ -0,0 +1,257 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestBetween(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `between(1, 3, X).`,
+				wantResult:  []types.TermResults{{"X": "1"}, {"X": "2"}, {"X": "3"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `between(3, 3, X).`,
+				wantResult:  []types.TermResults{{"X": "3"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `between(3, 1, X).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `between(1, 3, 2).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `between(1, 3, 4).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `between(1, inf, 1000000000).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `between(9223372036854775806, infinite, X).`,
+				wantResult:  []types.TermResults{{"X": "9223372036854775806"}, {"X": "9223372036854775807"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `between(1, inf, X), X == 3, !.`,
+				wantResult:  []types.TermResults{{"X": "3"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `between(1, 100000, X), X == 100000.`,
+				wantResult:  []types.TermResults{{"X": "100000"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(between(_, 3, _), error(instantiation_error, _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(between(1, a, _), error(type_error(integer, a), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(between(1, 3, a), error(type_error(integer, a), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `between(0, 10, 5, X).`,
+				wantResult:  []types.TermResults{{"X": "0"}, {"X": "5"}, {"X": "10"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `between(0, 9, 4, X).`,
+				wantResult:  []types.TermResults{{"X": "0"}, {"X": "4"}, {"X": "8"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `between(10, 0, -4, X).`,
+				wantResult:  []types.TermResults{{"X": "10"}, {"X": "6"}, {"X": "2"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `between(0, 10, -1, X).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `between(0, inf, -1, X).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `between(1000, inf, 100, 1300).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `between(1000, inf, 100, 1350).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `between(10, 0, -2, 4).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `between(-9223372036854775808, 9223372036854775807, 9223372036854775807, X).`,
+				wantResult:  []types.TermResults{{"X": "-9223372036854775808"}, {"X": "-1"}, {"X": "9223372036854775806"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(between(0, 10, 0, _), error(domain_error(not_zero, 0), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(between(0, 10, _, _), error(instantiation_error, _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(between(0, 10, 1.5, _), error(type_error(integer, 1.5), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("between"), Between)
+						interpreter.Register4(engine.NewAtom("between"), BetweenStep)
+						interpreter.Register2(engine.NewAtom("=="), engine.Equal)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func BenchmarkBetween(b *testing.B) {
+	for _, n := range []int{10000, 100000, 1000000} {
+		b.Run(fmt.Sprintf("range=%d", n), func(b *testing.B) {
+			db := tmdb.NewMemDB()
+			stateStore := store.NewCommitMultiStore(db)
+			ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+			// heap_sample/1 records the heap in use every 10000 integers, to check it does not grow with the range.
+			var stats runtime.MemStats
+			var base, peak uint64
+			interpreter := testutil.NewLightInterpreterMust(ctx)
+			interpreter.Register3(engine.NewAtom("between"), Between)
+			interpreter.Register0(engine.NewAtom("fail"), func(_ *engine.VM, _ engine.Cont, _ *engine.Env) *engine.Promise {
+				return engine.Bool(false)
+			})
+			interpreter.Register1(engine.NewAtom("heap_sample"), func(_ *engine.VM, x engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+				if env.Resolve(x).(engine.Integer)%10000 == 0 {
+					runtime.GC()
+					runtime.ReadMemStats(&stats)
+					if stats.HeapInuse > base {
+						peak = max(peak, stats.HeapInuse-base)
+					}
+				}
+				return cont(env)
+			})
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				runtime.GC()
+				runtime.ReadMemStats(&stats)
+				base = stats.HeapInuse
+
+				sols, err := interpreter.QueryContext(ctx, fmt.Sprintf("between(1, %d, X), heap_sample(X), fail.", n))
+				if err != nil {
+					b.Fatal(err)
+				}
+				for sols.Next() {
+				}
+				if err := sols.Err(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(peak), "peak-heap-B")
+		})
+	}
+}
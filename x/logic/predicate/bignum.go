@@ -0,0 +1,328 @@
+package predicate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+var (
+	// AtomEvaluationError are terms with principal functor evaluation_error/1.
+	// It is used to represent the formal part of the errors raised when an arithmetic evaluation fails.
+	AtomEvaluationError = engine.NewAtom("evaluation_error")
+
+	// AtomZeroDivisor is the term used to indicate a division by zero in an evaluation error.
+	AtomZeroDivisor = engine.NewAtom("zero_divisor")
+)
+
+const (
+	// bignumMaxBits is the maximum size in bits of the operands of the big integer predicates, so that the cost of an
+	// operation is bounded whatever the limits of the interpreter.
+	bignumMaxBits = 8192
+
+	// bignumMaxDigits is the maximum length of the decimal representation of an operand, sign included, i.e. the
+	// length of the representation of the greatest operand of bignumMaxBits bits, plus a sign.
+	bignumMaxDigits = 2467 + 1
+
+	// bignumAlgorithm is the name of the algorithm whose cost is consumed by the big integer predicates.
+	bignumAlgorithm = "bignum"
+)
+
+// BignumAdd is a predicate which adds two integers of arbitrary size.
+//
+// The signature is as follows:
+//
+//	bignum_add(+X, +Y, -Sum) is det
+//
+// Where:
+//   - X and Y are the integers to add, given either as an Integer or as an Atom holding the decimal representation of
+//     a signed integer of arbitrary size.
+//   - Sum is X + Y, as an Atom holding its decimal representation.
+//
+// The operands are limited to 8192 bits, a greater operand raising an evaluation_error(int_overflow) error, and an
+// operand which is not an integer a type_error(integer, Operand) error. The gas of the bignum algorithm of the gas
+// policy is consumed for an input size of 8 bytes per 64-bit word of the operands.
+//
+// Examples:
+//
+//	# Add two amounts exceeding the range of Integers.
+//	- bignum_add('18446744073709551615', '1', Sum).
+func BignumAdd(vm *engine.VM, x, y, sum engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return bignumOperation(vm, x, y, sum, (*big.Int).Add, bignumLinearSize, "bignum_add/3", cont, env)
+}
+
+// BignumSub is a predicate which subtracts two integers of arbitrary size.
+//
+// The signature is as follows:
+//
+//	bignum_sub(+X, +Y, -Difference) is det
+//
+// Where:
+//   - X and Y are the integers to subtract, given either as an Integer or as an Atom holding the decimal
+//     representation of a signed integer of arbitrary size.
+//   - Difference is X - Y, as an Atom holding its decimal representation.
+//
+// The operands are limited to 8192 bits, a greater operand raising an evaluation_error(int_overflow) error, and an
+// operand which is not an integer a type_error(integer, Operand) error. The gas of the bignum algorithm of the gas
+// policy is consumed for an input size of 8 bytes per 64-bit word of the operands.
+//
+// Examples:
+//
+//	# Subtract an amount from a balance.
+//	- bignum_sub('18446744073709551616', 100, Difference).
+func BignumSub(vm *engine.VM, x, y, difference engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return bignumOperation(vm, x, y, difference, (*big.Int).Sub, bignumLinearSize, "bignum_sub/3", cont, env)
+}
+
+// BignumMul is a predicate which multiplies two integers of arbitrary size.
+//
+// The signature is as follows:
+//
+//	bignum_mul(+X, +Y, -Product) is det
+//
+// Where:
+//   - X and Y are the integers to multiply, given either as an Integer or as an Atom holding the decimal
+//     representation of a signed integer of arbitrary size.
+//   - Product is X * Y, as an Atom holding its decimal representation.
+//
+// The operands are limited to 8192 bits, a greater operand raising an evaluation_error(int_overflow) error, and an
+// operand which is not an integer a type_error(integer, Operand) error. The gas of the bignum algorithm of the gas
+// policy is consumed for an input size of 8 bytes per product of a 64-bit word of X by a 64-bit word of Y.
+//
+// Examples:
+//
+//	# Multiply an amount by a rate.
+//	- bignum_mul('18446744073709551616', 3, Product).
+func BignumMul(vm *engine.VM, x, y, product engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return bignumOperation(vm, x, y, product, (*big.Int).Mul, bignumProductSize, "bignum_mul/3", cont, env)
+}
+
+// BignumDiv is a predicate which divides two integers of arbitrary size.
+//
+// The signature is as follows:
+//
+//	bignum_div(+X, +Y, -Quotient, -Remainder) is det
+//
+// Where:
+//   - X and Y are the dividend and the divisor, given either as an Integer or as an Atom holding the decimal
+//     representation of a signed integer of arbitrary size.
+//   - Quotient is X / Y truncated towards zero, as an Atom holding its decimal representation.
+//   - Remainder is X - Y * Quotient, having the sign of X, as an Atom holding its decimal representation.
+//
+// A division by zero raises a catchable error(evaluation_error(zero_divisor), Context) exception.
+//
+// The operands are limited to 8192 bits, a greater operand raising an evaluation_error(int_overflow) error, and an
+// operand which is not an integer a type_error(integer, Operand) error. The gas of the bignum algorithm of the gas
+// policy is consumed for an input size of 8 bytes per product of a 64-bit word of X by a 64-bit word of Y.
+//
+// Examples:
+//
+//	# Split an amount in 3 shares.
+//	- bignum_div('18446744073709551617', 3, Share, Rest).
+func BignumDiv(vm *engine.VM, x, y, quotient, remainder engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "bignum_div/4"
+
+		a, b, err := termsToBigInts(ctx, x, y, env)
+		if err != nil {
+			return bignumError(functor, err)
+		}
+		if b.Sign() == 0 {
+			return engine.Error(isoError(AtomEvaluationError.Apply(AtomZeroDivisor), env))
+		}
+		if err := consumeAlgorithmGas(ctx, functor, bignumAlgorithm, bignumProductSize(a, b)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+		return engine.Unify(vm, Tuple(quotient, remainder), Tuple(engine.NewAtom(q.String()), engine.NewAtom(r.String())),
+			cont, env)
+	})
+}
+
+// BignumCmp is a predicate which compares two integers of arbitrary size.
+//
+// The signature is as follows:
+//
+//	bignum_cmp(?Order, +X, +Y) is det
+//
+// Where:
+//   - Order is the result of the comparison, among the atoms <, = and >, as for compare/3.
+//   - X and Y are the integers to compare, given either as an Integer or as an Atom holding the decimal
+//     representation of a signed integer of arbitrary size.
+//
+// The integers are compared by their value, whatever their representation, e.g. 100 and '100' are equal.
+//
+// The operands are limited to 8192 bits, a greater operand raising an evaluation_error(int_overflow) error, and an
+// operand which is not an integer a type_error(integer, Operand) error. The gas of the bignum algorithm of the gas
+// policy is consumed for an input size of 8 bytes per 64-bit word of the operands.
+//
+// Examples:
+//
+//	# Check that a balance covers an amount.
+//	- bank_spendable_balances(Address, [uknow-Balance]), bignum_cmp(>, Balance, '1000000000000000000000').
+func BignumCmp(vm *engine.VM, order, x, y engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "bignum_cmp/3"
+
+		a, b, err := termsToBigInts(ctx, x, y, env)
+		if err != nil {
+			return bignumError(functor, err)
+		}
+		if err := consumeAlgorithmGas(ctx, functor, bignumAlgorithm, bignumLinearSize(a, b)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		return engine.Unify(vm, order, engine.NewAtom([]string{"<", "=", ">"}[a.Cmp(b)+1]), cont, env)
+	})
+}
+
+// ModPow is a predicate which computes the modular exponentiation of integers of arbitrary size.
+//
+// The signature is as follows:
+//
+//	mod_pow(+Base, +Exp, +Modulus, -Result) is det
+//
+// Where:
+//   - Base, Exp and Modulus are the integers of the exponentiation, given either as an Integer or as an Atom holding
+//     the decimal representation of a signed integer of arbitrary size, where Exp is non-negative and Modulus is
+//     positive.
+//   - Result is (Base ^ Exp) mod Modulus, between 0 and Modulus - 1, as an Atom holding its decimal representation.
+//
+// The exponentiation is computed by squaring, so that its cost grows with the size of the operands rather than with
+// the value of Exp, which makes it suitable for RSA-like verifications.
+//
+// The operands are limited to 8192 bits, as for bignum_add/3, a greater operand raising an
+// evaluation_error(int_overflow) error, and an operand which is not an integer a type_error(integer, Operand) error. A
+// negative Exp raises a domain_error(not_less_than_zero, Exp) error, a zero Modulus an
+// evaluation_error(zero_divisor) error and a negative one an evaluation_error(undefined) error. The gas of the bignum
+// algorithm of the gas policy is consumed for an input size of 8 bytes per product of two 64-bit words of Modulus and
+// per bit of Exp, i.e. the size of the multiplications of the exponentiation.
+//
+// Examples:
+//
+//	# Compute a modular exponentiation.
+//	- mod_pow('4', '13', '497', Result).
+func ModPow(vm *engine.VM, base, exp, modulus, result engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "mod_pow/4"
+
+		b, e, err := termsToBigInts(ctx, base, exp, env)
+		if err != nil {
+			return bignumError(functor, err)
+		}
+		m, err := termToBigInt(ctx, modulus, env)
+		if err != nil {
+			return bignumError(functor, err)
+		}
+		if e.Sign() < 0 {
+			return engine.Error(domainError(AtomNotLessThanZero, exp, env))
+		}
+		switch m.Sign() {
+		case 0:
+			return engine.Error(isoError(AtomEvaluationError.Apply(AtomZeroDivisor), env))
+		case -1:
+			return engine.Error(isoError(AtomEvaluationError.Apply(AtomUndefined), env))
+		}
+		if err := consumeAlgorithmGas(ctx, functor, bignumAlgorithm, bignumModPowSize(e, m)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		return engine.Unify(vm, result, engine.NewAtom(new(big.Int).Exp(b, e, m).String()), cont, env)
+	})
+}
+
+// bignumOperation unifies the given result with the decimal representation of the given operation applied to the
+// integers of arbitrary size x and y, consuming the gas of the input size given by size.
+func bignumOperation(vm *engine.VM, x, y, result engine.Term, op func(z, x, y *big.Int) *big.Int,
+	size func(x, y *big.Int) int, indicator string, cont engine.Cont, env *engine.Env,
+) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		a, b, err := termsToBigInts(ctx, x, y, env)
+		if err != nil {
+			return bignumError(indicator, err)
+		}
+		if err := consumeAlgorithmGas(ctx, indicator, bignumAlgorithm, size(a, b)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", indicator, err))
+		}
+
+		return engine.Unify(vm, result, engine.NewAtom(op(new(big.Int), a, b).String()), cont, env)
+	})
+}
+
+// bignumError returns the promise of the given error raised by the big integer predicate of the given indicator, the
+// ISO errors being raised as is so that they can be caught.
+func bignumError(indicator string, err error) *engine.Promise {
+	var exception engine.Exception
+	if errors.As(err, &exception) {
+		return engine.Error(err)
+	}
+	return engine.Error(fmt.Errorf("%s: %w", indicator, err))
+}
+
+// bignumWords returns the number of 64-bit words of the given integer, at least 1.
+func bignumWords(x *big.Int) int {
+	return max(1, (x.BitLen()+63)/64)
+}
+
+// bignumLinearSize returns the input size of an operation on the given integers taking a time linear in their size.
+func bignumLinearSize(x, y *big.Int) int {
+	return 8 * (bignumWords(x) + bignumWords(y))
+}
+
+// bignumProductSize returns the input size of an operation on the given integers taking a time proportional to the
+// product of their sizes, as a multiplication or a division.
+func bignumProductSize(x, y *big.Int) int {
+	return 8 * bignumWords(x) * bignumWords(y)
+}
+
+// bignumModPowSize returns the input size of the modular exponentiation of the given exponent and modulus, made of a
+// multiplication of integers of the size of the modulus per bit of the exponent.
+func bignumModPowSize(exp, modulus *big.Int) int {
+	return bignumProductSize(modulus, modulus) * max(1, exp.BitLen())
+}
+
+// termsToBigInts converts the given operands into integers of arbitrary size.
+func termsToBigInts(ctx context.Context, x, y engine.Term, env *engine.Env) (*big.Int, *big.Int, error) {
+	a, err := termToBigInt(ctx, x, env)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := termToBigInt(ctx, y, env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a, b, nil
+}
+
+// termToBigInt converts the given term into a signed integer of arbitrary size. The term is expected to be either an
+// Integer or an Atom holding the decimal representation of an integer of at most bignumMaxBits bits, otherwise a
+// type_error(integer, Term) or an evaluation_error(int_overflow) error is returned.
+func termToBigInt(ctx context.Context, term engine.Term, env *engine.Env) (*big.Int, error) {
+	switch t := env.Resolve(term).(type) {
+	case engine.Integer:
+		return big.NewInt(int64(t)), nil
+	case engine.Atom:
+		text := t.String()
+		if err := checkInputSize(ctx, len(text)); err != nil {
+			return nil, err
+		}
+		if len(text) > bignumMaxDigits {
+			return nil, isoError(AtomEvaluationError.Apply(AtomIntOverflow), env)
+		}
+		i, ok := new(big.Int).SetString(text, 10)
+		if !ok {
+			return nil, typeError(AtomInteger, t, env)
+		}
+		if i.BitLen() > bignumMaxBits {
+			return nil, isoError(AtomEvaluationError.Apply(AtomIntOverflow), env)
+		}
+		return i, nil
+	default:
+		return nil, typeError(AtomInteger, term, env)
+	}
+}
@@ -0,0 +1,319 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+	"github.com/samber/lo"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	"cosmossdk.io/math"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestBignum(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `bignum_add('18446744073709551615', '1', Sum).`,
+				wantResult:  []types.TermResults{{"Sum": "'18446744073709551616'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_add(9223372036854775807, 9223372036854775807, Sum).`,
+				wantResult:  []types.TermResults{{"Sum": "'18446744073709551614'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_add('-5', 3, '-2').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_add(2, 3, 5).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `bignum_sub('18446744073709551616', 100, Difference).`,
+				wantResult:  []types.TermResults{{"Difference": "'18446744073709551516'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_sub(100, '18446744073709551616', Difference).`,
+				wantResult:  []types.TermResults{{"Difference": "'-18446744073709551516'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_mul('18446744073709551616', '18446744073709551616', Product).`,
+				wantResult:  []types.TermResults{{"Product": "'340282366920938463463374607431768211456'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_mul('-3', 0, Product).`,
+				wantResult:  []types.TermResults{{"Product": "'0'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_div('18446744073709551617', 3, Quotient, Remainder).`,
+				wantResult:  []types.TermResults{{"Quotient": "'6148914691236517205'", "Remainder": "'2'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_div('-7', 2, Quotient, Remainder).`,
+				wantResult:  []types.TermResults{{"Quotient": "'-3'", "Remainder": "'-1'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_div(7, '-2', Quotient, Remainder).`,
+				wantResult:  []types.TermResults{{"Quotient": "'-3'", "Remainder": "'1'"}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(bignum_div('18446744073709551617', '0', Quotient, Remainder), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Quotient": "_1", "Remainder": "_1",
+					"E": "error(evaluation_error(zero_divisor),/(bignum_div,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_cmp(Order, '18446744073709551616', 9223372036854775807).`,
+				wantResult:  []types.TermResults{{"Order": ">"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_cmp(Order, '-18446744073709551616', 0).`,
+				wantResult:  []types.TermResults{{"Order": "<"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_cmp(Order, 100, '100').`,
+				wantResult:  []types.TermResults{{"Order": "="}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bignum_cmp(<, 100, '99').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `mod_pow('4', '13', '497', Result).`,
+				wantResult:  []types.TermResults{{"Result": "'445'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `mod_pow(2, '340282366920938463463374607431768211456', '1000000007', Result).`,
+				wantResult:  []types.TermResults{{"Result": "'129275987'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `mod_pow('-4', 3, 10, Result).`,
+				wantResult:  []types.TermResults{{"Result": "'6'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `mod_pow(7, 0, 1, Result).`,
+				wantResult:  []types.TermResults{{"Result": "'0'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `mod_pow(7, 0, 5, '1').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(mod_pow(4, 13, 0, _), E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(evaluation_error(zero_divisor),/(mod_pow,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(mod_pow(4, 13, '-497', _), E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(evaluation_error(undefined),/(mod_pow,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(mod_pow(4, -1, 497, _), E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(not_less_than_zero,-1),/(mod_pow,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       fmt.Sprintf("catch(mod_pow(4, '%s', 497, _), E, R = caught).", new(big.Int).Lsh(big.NewInt(1), 8192)),
+				wantResult:  []types.TermResults{{"E": "error(evaluation_error(int_overflow),/(mod_pow,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(mod_pow(4, 13, bar, _), E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(type_error(integer,bar),/(mod_pow,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(bignum_add(foo, 1, _), E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(type_error(integer,foo),/(bignum_add,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(bignum_sub(1, '1.5', _), E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(type_error(integer,'1.5'),/(bignum_sub,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(bignum_mul(1, _, _), E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(instantiation_error,/(bignum_mul,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(bignum_cmp(_, 1.0, 1), E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(type_error(integer,1.0),/(bignum_cmp,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       fmt.Sprintf("bignum_cmp(Order, '%s', 0).", new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 8192), big.NewInt(1))),
+				wantResult:  []types.TermResults{{"Order": ">"}},
+				wantSuccess: true,
+			},
+			{
+				query:       fmt.Sprintf("catch(bignum_add('%s', 1, _), E, R = caught).", new(big.Int).Lsh(big.NewInt(1), 8192)),
+				wantResult:  []types.TermResults{{"E": "error(evaluation_error(int_overflow),/(bignum_add,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       fmt.Sprintf("catch(bignum_mul('%s', 1, _), E, R = caught).", strings.Repeat("9", 10000)),
+				wantResult:  []types.TermResults{{"E": "error(evaluation_error(int_overflow),/(bignum_mul,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("bignum_add"), BignumAdd)
+						interpreter.Register3(engine.NewAtom("bignum_sub"), BignumSub)
+						interpreter.Register3(engine.NewAtom("bignum_mul"), BignumMul)
+						interpreter.Register4(engine.NewAtom("bignum_div"), BignumDiv)
+						interpreter.Register3(engine.NewAtom("bignum_cmp"), BignumCmp)
+						interpreter.Register4(engine.NewAtom("mod_pow"), ModPow)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register2(engine.NewAtom("="), engine.Unify)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestBignumGas(t *testing.T) {
+	Convey("Given the algorithm costs of the big integer predicates", t, func() {
+		costs := []types.AlgorithmCost{{Algorithm: "bignum", BaseCost: lo.ToPtr(math.NewUint(10)), ByteCost: lo.ToPtr(math.NewUint(1))}}
+
+		cases := []struct {
+			query   string
+			wantGas uint64
+		}{
+			{query: `bignum_add(1, 2, Sum).`, wantGas: 26},
+			{query: `bignum_cmp(Order, '18446744073709551616', 1).`, wantGas: 34},
+			{query: `bignum_mul('18446744073709551616', '18446744073709551616', Product).`, wantGas: 42},
+			{query: `bignum_div('340282366920938463463374607431768211456', '18446744073709551616', Q, R).`, wantGas: 58},
+			{query: `mod_pow(4, 13, '18446744073709551616', Result).`, wantGas: 138},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context with a gas meter and algorithm costs", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger()).
+						WithGasMeter(sdk.NewGasMeter(1000))
+					ctx = ctx.WithValue(types.AlgorithmCostsContextKey, costs)
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("bignum_add"), BignumAdd)
+						interpreter.Register3(engine.NewAtom("bignum_mul"), BignumMul)
+						interpreter.Register4(engine.NewAtom("bignum_div"), BignumDiv)
+						interpreter.Register3(engine.NewAtom("bignum_cmp"), BignumCmp)
+						interpreter.Register4(engine.NewAtom("mod_pow"), ModPow)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+							So(err, ShouldBeNil)
+							So(sols, ShouldNotBeNil)
+
+							Convey("Then the gas consumed should be in proportion to the size of the operands", func() {
+								for sols.Next() {
+									So(sols.Scan(types.TermResults{}), ShouldBeNil)
+								}
+
+								So(sols.Err(), ShouldBeNil)
+								So(ctx.GasMeter().GasConsumed(), ShouldEqual, tc.wantGas)
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
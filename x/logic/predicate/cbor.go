@@ -0,0 +1,623 @@
+package predicate
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+var (
+	// AtomBytes are terms with principal functor bytes/1.
+	// It is used to represent CBOR byte strings.
+	AtomBytes = engine.NewAtom("bytes")
+
+	// AtomTag are terms with principal functor tag/2.
+	// It is used to represent CBOR tagged data items.
+	AtomTag = engine.NewAtom("tag")
+
+	// AtomSimple are terms with principal functor simple/1.
+	// It is used to represent the CBOR simple values having no other representation.
+	AtomSimple = engine.NewAtom("simple")
+
+	// AtomUndefined is the term undefined.
+	AtomUndefined = engine.NewAtom("undefined")
+)
+
+// cborMaxDepth is the maximum nesting depth of the arrays, maps and tags of a decoded CBOR document, as for the JSON
+// documents parsed by the encoding/json package.
+const cborMaxDepth = 10000
+
+// The major types of the CBOR data items.
+const (
+	cborUnsigned byte = iota
+	cborNegative
+	cborByteString
+	cborTextString
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+// cborBreak is the "break" stop code, ending the indefinite-length items.
+const cborBreak = 0xff
+
+// CBORProlog is a predicate that will unify a CBOR document, given in hexadecimal, into prolog terms and vice versa.
+//
+// The signature is as follows:
+//
+//	cbor_prolog(?Cbor, ?Term) is det
+//
+// Where:
+//   - Cbor is the hexadecimal encoding of the CBOR document, as an Atom.
+//   - Term is the Prolog representation of the CBOR document.
+//
+// The CBOR data items, as specified by [RFC 8949], are mapped as in json_prolog/2 whenever they have a JSON
+// counterpart:
+//   - An unsigned or negative integer is represented as an Integer, or as a big(Atom) term, Atom holding its decimal
+//     representation, if it does not fit in a 64-bit signed integer.
+//   - A byte string is represented as a bytes(Bytes) term, Bytes being the list of its bytes.
+//   - A text string is represented as an Atom.
+//   - An array is represented as a list, the empty array being represented as @([]).
+//   - A map is represented as json([Key-Value, ...]), where each Key is the representation of any data item, e.g. an
+//     Integer for the labels of the COSE headers. The pairs are kept in the order of the document.
+//   - A tagged data item is represented as a tag(Number, Content) term, the tags not being interpreted, e.g. a bignum
+//     (tag 2) is represented as tag(2, bytes(Bytes)).
+//   - A floating-point number is represented as a Float. The infinities and NaNs are not supported.
+//   - The simple values false, true, null and undefined are respectively represented as @(false), @(true), @(null)
+//     and @(undefined), and the other simple values as simple(N) terms, N being their number.
+//
+// The indefinite-length items are accepted when decoding a document, while a map with duplicated keys is rejected.
+// The nesting depth of the arrays, maps and tags is limited to 10000.
+//
+// A Term is encoded with the core deterministic encoding requirements of the RFC, so the same Term always gives the
+// same bytes: the integers, lengths and floats are encoded in their shortest form, the lengths are always definite and
+// the map keys are sorted by the bytewise lexicographic order of their encoding.
+//
+// Examples:
+//
+//	# CBOR conversion to Prolog.
+//	- cbor_prolog('a2616101616282f5f6', json([a-1, b-[@(true), @(null)]])).
+//
+//	# Prolog conversion to CBOR of a COSE header, assigning the algorithm ES256 (-7).
+//	- cbor_prolog(Cbor, json([1 - -7])).
+//
+// [RFC 8949]: https://datatracker.ietf.org/doc/html/rfc8949
+func CBORProlog(vm *engine.VM, cbor, term engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return cborConvert(vm, "cbor_prolog/2", cbor, AtomHex, term, cont, env)
+}
+
+// CBORBytes is a predicate that will unify a CBOR document, given as a list of bytes, into prolog terms and vice
+// versa.
+//
+// The signature is as follows:
+//
+//	cbor_bytes(?Bytes, ?Term) is det
+//
+// Where:
+//   - Bytes is the CBOR document, as a list of bytes.
+//   - Term is the Prolog representation of the CBOR document, using the same mapping as cbor_prolog/2.
+//
+// Examples:
+//
+//	# CBOR conversion to Prolog of a byte string.
+//	- cbor_bytes([67, 1, 2, 3], bytes([1, 2, 3])).
+//
+//	# Prolog conversion to CBOR of a tagged date.
+//	- cbor_bytes(Bytes, tag(1, 1363896240)).
+func CBORBytes(vm *engine.VM, bts, term engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return cborConvert(vm, "cbor_bytes/2", bts, AtomOctet, term, cont, env)
+}
+
+// cborConvert unifies the given CBOR document, encoded with the given encoding, with its Prolog representation.
+func cborConvert(
+	vm *engine.VM, functor string, cbor engine.Term, encoding engine.Atom, term engine.Term, cont engine.Cont,
+	env *engine.Env,
+) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		if _, ok := env.Resolve(cbor).(engine.Variable); !ok {
+			data, err := decodeBytes(ctx, cbor, encoding, env)
+			if err != nil {
+				return engine.Error(err)
+			}
+			result, err := decodeCBOR(data)
+			if err != nil {
+				return engine.Error(fmt.Errorf("%s: %w", functor, err))
+			}
+			return engine.Unify(vm, term, result, cont, env)
+		}
+
+		if _, ok := env.Resolve(term).(engine.Variable); ok {
+			return engine.Error(engine.InstantiationError(env))
+		}
+		data, err := appendCBOR(ctx, nil, term, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		if encoding == AtomHex {
+			return engine.Unify(vm, cbor, engine.NewAtom(hex.EncodeToString(data)), cont, env)
+		}
+		return engine.Unify(vm, cbor, BytesToList(data), cont, env)
+	})
+}
+
+// decodeCBOR decodes the given CBOR document into its Prolog representation (see cbor_prolog/2).
+func decodeCBOR(data []byte) (engine.Term, error) {
+	decoder := &cborDecoder{data: data}
+	result, err := decoder.decode(0)
+	if err != nil {
+		return nil, err
+	}
+	if decoder.offset != len(data) {
+		return nil, decoder.errorf("unexpected data after CBOR data item")
+	}
+	return result, nil
+}
+
+// cborDecoder decodes a CBOR document data item by data item.
+type cborDecoder struct {
+	data   []byte
+	offset int
+}
+
+// decode decodes the next data item, at the given nesting depth.
+func (d *cborDecoder) decode(depth int) (engine.Term, error) {
+	start := d.offset
+	major, info, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUnsigned:
+		return cborUintToTerm(arg), nil
+	case cborNegative:
+		if arg > math.MaxInt64 {
+			n := new(big.Int).SetUint64(arg)
+			return AtomBig.Apply(engine.NewAtom(n.Neg(n).Sub(n, big.NewInt(1)).String())), nil
+		}
+		return engine.Integer(-1 - int64(arg)), nil
+	case cborByteString, cborTextString:
+		s, err := d.readString(major, info, arg)
+		if err != nil {
+			return nil, err
+		}
+		if major == cborByteString {
+			return AtomBytes.Apply(BytesToList(s)), nil
+		}
+		if !utf8.Valid(s) {
+			return nil, d.errorAt(start, "invalid UTF-8 text string")
+		}
+		return util.StringToTerm(string(s)), nil
+	case cborArray, cborMap, cborTag:
+		if depth >= cborMaxDepth {
+			return nil, d.errorAt(start, "maximum depth of %d exceeded", cborMaxDepth)
+		}
+		switch major {
+		case cborArray:
+			return d.readArray(info, arg, depth+1)
+		case cborMap:
+			return d.readMap(info, arg, depth+1)
+		}
+		content, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return AtomTag.Apply(cborUintToTerm(arg), content), nil
+	default:
+		return d.readSimple(start, info, arg)
+	}
+}
+
+// head reads the head of the next data item, i.e. its major type, its additional information and its argument, the
+// latter being 0 for an indefinite length.
+func (d *cborDecoder) head() (byte, byte, uint64, error) {
+	if d.offset >= len(d.data) {
+		return 0, 0, 0, d.errorf("unexpected end of data")
+	}
+	start := d.offset
+	major, info := d.data[d.offset]>>5, d.data[d.offset]&0x1f
+	d.offset++
+
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(d.data)-d.offset < size {
+			return 0, 0, 0, d.errorf("unexpected end of data")
+		}
+		var arg uint64
+		for _, b := range d.data[d.offset : d.offset+size] {
+			arg = arg<<8 | uint64(b)
+		}
+		d.offset += size
+		return major, info, arg, nil
+	case info == 31 && major >= cborByteString && major <= cborMap:
+		return major, info, 0, nil
+	case info == 31 && major == cborSimple:
+		return 0, 0, 0, d.errorAt(start, "unexpected break")
+	default:
+		return 0, 0, 0, d.errorAt(start, "invalid additional information %d", info)
+	}
+}
+
+// readString reads the content of a byte or text string, whose head has been read, concatenating its chunks if it has
+// an indefinite length.
+func (d *cborDecoder) readString(major, info byte, length uint64) ([]byte, error) {
+	if info != 31 {
+		if length > uint64(len(d.data)-d.offset) {
+			return nil, d.errorf("unexpected end of data")
+		}
+		s := d.data[d.offset : d.offset+int(length)]
+		d.offset += int(length)
+		return s, nil
+	}
+
+	var s []byte
+	for !d.atBreak() {
+		start := d.offset
+		chunkMajor, chunkInfo, chunkLength, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+		if chunkMajor != major || chunkInfo == 31 {
+			return nil, d.errorAt(start, "invalid chunk of indefinite-length string")
+		}
+		chunk, err := d.readString(chunkMajor, chunkInfo, chunkLength)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, chunk...)
+	}
+	return s, nil
+}
+
+// readArray reads the elements of an array, whose head has been read.
+func (d *cborDecoder) readArray(info byte, length uint64, depth int) (engine.Term, error) {
+	if info != 31 && length > uint64(len(d.data)-d.offset) {
+		return nil, d.errorf("unexpected end of data")
+	}
+
+	elements := make([]engine.Term, 0, length)
+	for i := uint64(0); (info == 31 && !d.atBreak()) || (info != 31 && i < length); i++ {
+		element, err := d.decode(depth)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+	}
+
+	if len(elements) == 0 {
+		return MakeEmptyArray(), nil
+	}
+	return engine.List(elements...), nil
+}
+
+// readMap reads the pairs of a map, whose head has been read, rejecting the duplicated keys.
+func (d *cborDecoder) readMap(info byte, length uint64, depth int) (engine.Term, error) {
+	if info != 31 && length > uint64(len(d.data)-d.offset)/2 {
+		return nil, d.errorf("unexpected end of data")
+	}
+
+	pairs := make([]engine.Term, 0, length)
+	keys := make(map[string]struct{}, length)
+	for i := uint64(0); (info == 31 && !d.atBreak()) || (info != 31 && i < length); i++ {
+		start := d.offset
+		key, err := d.decode(depth)
+		if err != nil {
+			return nil, err
+		}
+		encodedKey := string(d.data[start:d.offset])
+		if _, ok := keys[encodedKey]; ok {
+			return nil, d.errorAt(start, "duplicated map key")
+		}
+		keys[encodedKey] = struct{}{}
+
+		value, err := d.decode(depth)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, AtomPair.Apply(key, value))
+	}
+
+	return AtomJSON.Apply(engine.List(pairs...)), nil
+}
+
+// readSimple returns the simple value or floating-point number of the given head, read at the given offset.
+func (d *cborDecoder) readSimple(start int, info byte, arg uint64) (engine.Term, error) {
+	var f float64
+	switch info {
+	case 20:
+		return MakeBool(false), nil
+	case 21:
+		return MakeBool(true), nil
+	case 22:
+		return MakeNull(), nil
+	case 23:
+		return AtomAt.Apply(AtomUndefined), nil
+	case 24:
+		if arg < 32 {
+			return nil, d.errorAt(start, "invalid simple value %d", arg)
+		}
+		return AtomSimple.Apply(engine.Integer(arg)), nil
+	case 25:
+		f = halfToFloat(uint16(arg))
+	case 26:
+		f = float64(math.Float32frombits(uint32(arg)))
+	case 27:
+		f = math.Float64frombits(arg)
+	default:
+		return AtomSimple.Apply(engine.Integer(arg)), nil
+	}
+
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, d.errorAt(start, "unsupported non-finite float")
+	}
+	return engine.Float(f), nil
+}
+
+// atBreak reports whether the next byte is the "break" stop code, consuming it if so.
+func (d *cborDecoder) atBreak() bool {
+	if d.offset < len(d.data) && d.data[d.offset] == cborBreak {
+		d.offset++
+		return true
+	}
+	return false
+}
+
+// errorf returns a new error located at the current offset of the document.
+func (d *cborDecoder) errorf(format string, args ...any) error {
+	return d.errorAt(d.offset, format, args...)
+}
+
+// errorAt returns a new error located at the given offset of the document.
+func (d *cborDecoder) errorAt(offset int, format string, args ...any) error {
+	return fmt.Errorf("invalid CBOR at offset %d: %s", offset, fmt.Sprintf(format, args...))
+}
+
+// cborUintToTerm returns the given unsigned integer as an Integer, or as a big(Atom) term if it exceeds the range of
+// the Integers.
+func cborUintToTerm(n uint64) engine.Term {
+	if n > math.MaxInt64 {
+		return AtomBig.Apply(engine.NewAtom(new(big.Int).SetUint64(n).String()))
+	}
+	return engine.Integer(n)
+}
+
+// halfToFloat returns the value of the given IEEE 754 half-precision floating-point number.
+func halfToFloat(h uint16) float64 {
+	exponent, mantissa := int(h>>10)&0x1f, float64(h&0x3ff)
+	var f float64
+	switch exponent {
+	case 0:
+		f = math.Ldexp(mantissa, -24)
+	case 0x1f:
+		f = math.Inf(1)
+		if mantissa != 0 {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mantissa+1024, exponent-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
+
+// appendCBOR appends the CBOR encoding of the given Prolog term to the given bytes (see cbor_prolog/2).
+func appendCBOR(ctx context.Context, dst []byte, term engine.Term, env *engine.Env) ([]byte, error) {
+	switch t := env.Resolve(term).(type) {
+	case engine.Atom:
+		if !utf8.ValidString(t.String()) {
+			return nil, fmt.Errorf("invalid text string: %q, should be UTF-8 encoded", t.String())
+		}
+		return append(appendCBORHead(dst, cborTextString, uint64(len(t.String()))), t.String()...), nil
+	case engine.Integer:
+		if t < 0 {
+			return appendCBORHead(dst, cborNegative, uint64(-1-t)), nil
+		}
+		return appendCBORHead(dst, cborUnsigned, uint64(t)), nil
+	case engine.Float:
+		return appendCBORFloat(dst, float64(t))
+	case engine.Compound:
+		switch {
+		case util.IsList(t):
+			elements, err := listElements(t, env)
+			if err != nil {
+				return nil, err
+			}
+			dst = appendCBORHead(dst, cborArray, uint64(len(elements)))
+			for _, element := range elements {
+				if dst, err = appendCBOR(ctx, dst, element, env); err != nil {
+					return nil, err
+				}
+			}
+			return dst, nil
+		case t.Functor() == AtomJSON && t.Arity() == 1:
+			return appendCBORMap(ctx, dst, t.Arg(0), env)
+		case t.Functor() == AtomBytes && t.Arity() == 1:
+			s, err := TermToBytes(ctx, t.Arg(0), AtomEncoding.Apply(AtomOctet), env)
+			if err != nil {
+				return nil, err
+			}
+			return append(appendCBORHead(dst, cborByteString, uint64(len(s))), s...), nil
+		case t.Functor() == AtomTag && t.Arity() == 2:
+			n, err := cborUint(t.Arg(0), env)
+			if err != nil {
+				return nil, err
+			}
+			return appendCBOR(ctx, appendCBORHead(dst, cborTag, n), t.Arg(1), env)
+		case t.Functor() == AtomBig && t.Arity() == 1:
+			return appendCBORBigInt(dst, t.Arg(0), env)
+		case t.Functor() == AtomSimple && t.Arity() == 1:
+			n, ok := env.Resolve(t.Arg(0)).(engine.Integer)
+			if !ok || n < 0 || n > 255 || n >= 20 && n < 32 {
+				return nil, fmt.Errorf("invalid simple value: %v", env.Resolve(t.Arg(0)))
+			}
+			return appendCBORHead(dst, cborSimple, uint64(n)), nil
+		case MakeBool(false).Compare(t, env) == 0:
+			return append(dst, 0xf4), nil
+		case MakeBool(true).Compare(t, env) == 0:
+			return append(dst, 0xf5), nil
+		case MakeNull().Compare(t, env) == 0:
+			return append(dst, 0xf6), nil
+		case AtomAt.Apply(AtomUndefined).Compare(t, env) == 0:
+			return append(dst, 0xf7), nil
+		case MakeEmptyArray().Compare(t, env) == 0:
+			return appendCBORHead(dst, cborArray, 0), nil
+		}
+		return nil, fmt.Errorf("invalid functor %s", t.Functor())
+	default:
+		return nil, fmt.Errorf("could not convert %s {%T} to cbor", t, t)
+	}
+}
+
+// appendCBORHead appends the head of a data item of the given major type and argument, in its shortest form.
+func appendCBORHead(dst []byte, major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return append(dst, major<<5|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(dst, major<<5|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, major<<5|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, major<<5|26), uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, major<<5|27), arg)
+	}
+}
+
+// appendCBORMap appends the CBOR encoding of the map with the given list of Key-Value pairs, sorted by the bytewise
+// lexicographic order of the encoding of their keys.
+func appendCBORMap(ctx context.Context, dst []byte, list engine.Term, env *engine.Env) ([]byte, error) {
+	pairs, err := listElements(list, env)
+	if err != nil {
+		return nil, err
+	}
+
+	type entry struct {
+		term       engine.Term
+		key, value []byte
+	}
+	entries := make([]entry, 0, len(pairs))
+	for _, p := range pairs {
+		pair, ok := p.(engine.Compound)
+		if !ok || pair.Functor() != AtomPair || pair.Arity() != 2 {
+			return nil, fmt.Errorf("map entries should be pairs")
+		}
+		key, err := appendCBOR(ctx, nil, pair.Arg(0), env)
+		if err != nil {
+			return nil, err
+		}
+		value, err := appendCBOR(ctx, nil, pair.Arg(1), env)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry{term: pair.Arg(0), key: key, value: value})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return bytes.Compare(entries[i].key, entries[j].key) < 0
+	})
+
+	dst = appendCBORHead(dst, cborMap, uint64(len(entries)))
+	for i, e := range entries {
+		if i > 0 && bytes.Equal(e.key, entries[i-1].key) {
+			return nil, fmt.Errorf("duplicated map key: %v", env.Resolve(e.term))
+		}
+		dst = append(append(dst, e.key...), e.value...)
+	}
+	return dst, nil
+}
+
+// appendCBORBigInt appends the CBOR encoding of the integer whose decimal representation is held by the given Atom,
+// which shall fit in an unsigned or negative CBOR integer.
+func appendCBORBigInt(dst []byte, term engine.Term, env *engine.Env) ([]byte, error) {
+	a, err := util.ResolveToAtom(env, term)
+	if err != nil {
+		return nil, err
+	}
+	n, ok := new(big.Int).SetString(a.String(), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid big integer: %s", a)
+	}
+	if n.Sign() >= 0 && n.IsUint64() {
+		return appendCBORHead(dst, cborUnsigned, n.Uint64()), nil
+	}
+	if m := new(big.Int).Sub(new(big.Int).Neg(n), big.NewInt(1)); n.Sign() < 0 && m.IsUint64() {
+		return appendCBORHead(dst, cborNegative, m.Uint64()), nil
+	}
+	return nil, fmt.Errorf("invalid big integer: %s, out of the range of the CBOR integers", a)
+}
+
+// appendCBORFloat appends the CBOR encoding of the given finite floating-point number, in the shortest of the half,
+// single and double precision forms preserving its value.
+func appendCBORFloat(dst []byte, f float64) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("invalid float: %v, should be finite", f)
+	}
+	if h, ok := floatToHalf(f); ok {
+		return binary.BigEndian.AppendUint16(append(dst, cborSimple<<5|25), h), nil
+	}
+	if f32 := float32(f); float64(f32) == f {
+		return binary.BigEndian.AppendUint32(append(dst, cborSimple<<5|26), math.Float32bits(f32)), nil
+	}
+	return binary.BigEndian.AppendUint64(append(dst, cborSimple<<5|27), math.Float64bits(f)), nil
+}
+
+// floatToHalf returns the IEEE 754 half-precision floating-point number of the given finite value, and whether it
+// represents it exactly.
+func floatToHalf(f float64) (uint16, bool) {
+	var sign uint16
+	if math.Signbit(f) {
+		sign, f = 0x8000, -f
+	}
+
+	switch {
+	case f == 0:
+		return sign, true
+	case f < 0x1p-14:
+		m := f * 0x1p24
+		return sign | uint16(m), m == math.Trunc(m)
+	case f < 0x1p16:
+		frac, exp := math.Frexp(f)
+		m := frac*2048 - 1024
+		if m != math.Trunc(m) {
+			return 0, false
+		}
+		return sign | uint16(exp-1+15)<<10 | uint16(m), true
+	default:
+		return 0, false
+	}
+}
+
+// cborUint returns the given term as an unsigned integer, either an Integer or a big(Atom) term.
+func cborUint(term engine.Term, env *engine.Env) (uint64, error) {
+	switch t := env.Resolve(term).(type) {
+	case engine.Integer:
+		if t >= 0 {
+			return uint64(t), nil
+		}
+	case engine.Compound:
+		if t.Functor() == AtomBig && t.Arity() == 1 {
+			if a, ok := env.Resolve(t.Arg(0)).(engine.Atom); ok {
+				if n, ok := new(big.Int).SetString(a.String(), 10); ok && n.IsUint64() {
+					return n.Uint64(), nil
+				}
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid tag number: %v, should be an unsigned integer", env.Resolve(term))
+}
@@ -0,0 +1,335 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestCBORProlog(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `cbor_prolog('a2616101616282f5f6', json([a-1, b-[@(true), @(null)]])).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `cbor_prolog(Cbor, json([b-1, a-2, 10-3, 1-4])).`,
+				wantResult:  []types.TermResults{{"Cbor": "a401040a03616102616201"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `cbor_prolog(Cbor, json([1 - -7])).`,
+				wantResult:  []types.TermResults{{"Cbor": "a10126"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `cbor_prolog('a201020304', json([3-4, 1-2])).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog(Cbor, [a, tag(24, bytes([161, 1, 38])), '[]']).`,
+				wantResult:  []types.TermResults{{"Cbor": "'836161d81843a10126625b5d'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `cbor_bytes([67, 1, 2, 3], Term).`,
+				wantResult:  []types.TermResults{{"Term": "bytes([1,2,3])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `cbor_bytes(Bytes, tag(1, 1363896240)).`,
+				wantResult:  []types.TermResults{{"Bytes": "[193,26,81,75,103,176]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `cbor_bytes(Bytes, json([alg- -7, kid-bytes([1])])).`,
+				wantResult:  []types.TermResults{{"Bytes": "[162,99,97,108,103,38,99,107,105,100,65,1]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `cbor_prolog('1c', Term).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid CBOR at offset 0: invalid additional information 28"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog(ff, Term).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid CBOR at offset 0: unexpected break"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog('18', Term).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid CBOR at offset 1: unexpected end of data"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog('9a00010000', Term).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid CBOR at offset 5: unexpected end of data"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog('0000', Term).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid CBOR at offset 1: unexpected data after CBOR data item"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog('9f01', Term).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid CBOR at offset 2: unexpected end of data"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog(a2616101616102, Term).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid CBOR at offset 4: duplicated map key"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog('62c328', Term).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid CBOR at offset 0: invalid UTF-8 text string"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog(f818, Term).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid CBOR at offset 0: invalid simple value 24"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog(f97c00, Term).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid CBOR at offset 0: unsupported non-finite float"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog('5f6161ff', Term).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid CBOR at offset 1: invalid chunk of indefinite-length string"),
+				wantSuccess: false,
+			},
+			{
+				query:       fmt.Sprintf("cbor_prolog('%s00', Term).", strings.Repeat("81", 10001)),
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid CBOR at offset 10000: maximum depth of 10000 exceeded"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog(Cbor, foo(bar)).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid functor foo"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog(Cbor, json([b-1, a-1, a-2])).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: duplicated map key: a"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog(Cbor, json([a])).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: map entries should be pairs"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog(Cbor, big('18446744073709551616')).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid big integer: 18446744073709551616, out of the range of the CBOR integers"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog(Cbor, simple(24)).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid simple value: 24"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_prolog(Cbor, tag(-1, a)).`,
+				wantError:   fmt.Errorf("cbor_prolog/2: invalid tag number: -1, should be an unsigned integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cbor_bytes(Bytes, bytes([256])).`,
+				wantError:   fmt.Errorf("cbor_bytes/2: invalid integer value in list at position 1: 256 is out of byte range (0-255)"),
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(cbor_prolog(zz, _), error(domain_error(encoding(hex), zz), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(cbor_bytes(_, _), error(instantiation_error, _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("cbor_prolog"), CBORProlog)
+						interpreter.Register2(engine.NewAtom("cbor_bytes"), CBORBytes)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestCBORPrologVectors(t *testing.T) {
+	Convey("Given the test vectors of RFC 8949, appendix A", t, func() {
+		cases := []struct {
+			cbor             string
+			term             string
+			nonDeterministic bool
+		}{
+			{cbor: "00", term: "0"},
+			{cbor: "17", term: "23"},
+			{cbor: "1818", term: "24"},
+			{cbor: "1903e8", term: "1000"},
+			{cbor: "1bffffffffffffffff", term: "big('18446744073709551615')"},
+			{cbor: "3bffffffffffffffff", term: "big('-18446744073709551616')"},
+			{cbor: "20", term: "-1"},
+			{cbor: "3903e7", term: "-1000"},
+			{cbor: "f90000", term: "0.0"},
+			{cbor: "f93c00", term: "1.0"},
+			{cbor: "fb3ff199999999999a", term: "1.1"},
+			{cbor: "f93e00", term: "1.5"},
+			{cbor: "f97bff", term: "65504.0"},
+			{cbor: "fa47c35000", term: "100000.0"},
+			{cbor: "fa7f7fffff", term: "3.4028234663852886e+38"},
+			{cbor: "fb7e37e43c8800759c", term: "1.0e+300"},
+			{cbor: "f90001", term: "5.960464477539063e-08"},
+			{cbor: "f90400", term: "6.103515625e-05"},
+			{cbor: "f9c400", term: "-4.0"},
+			{cbor: "fbc010666666666666", term: "-4.1"},
+			{cbor: "fa3fc00000", term: "1.5", nonDeterministic: true},
+			{cbor: "f4", term: "@(false)"},
+			{cbor: "f5", term: "@(true)"},
+			{cbor: "f6", term: "@(null)"},
+			{cbor: "f7", term: "@(undefined)"},
+			{cbor: "f0", term: "simple(16)"},
+			{cbor: "f8ff", term: "simple(255)"},
+			{cbor: "c074323031332d30332d32315432303a30343a30305a", term: "tag(0,'2013-03-21T20:04:00Z')"},
+			{cbor: "c11a514b67b0", term: "tag(1,1363896240)"},
+			{cbor: "c1fb41d452d9ec200000", term: "tag(1,1.3638962405e+09)"},
+			{cbor: "d74401020304", term: "tag(23,bytes([1,2,3,4]))"},
+			{cbor: "40", term: "bytes([])"},
+			{cbor: "4401020304", term: "bytes([1,2,3,4])"},
+			{cbor: "60", term: "''"},
+			{cbor: "6161", term: "a"},
+			{cbor: "6449455446", term: "'IETF'"},
+			{cbor: "62c3bc", term: "ü"},
+			{cbor: "80", term: "@([])"},
+			{cbor: "83010203", term: "[1,2,3]"},
+			{cbor: "8301820203820405", term: "[1,[2,3],[4,5]]"},
+			{cbor: "a0", term: "json([])"},
+			{cbor: "a201020304", term: "json([1-2,3-4])"},
+			{cbor: "a26161016162820203", term: "json([a-1,b-[2,3]])"},
+			{cbor: "826161a161626163", term: "[a,json([b-c])]"},
+			{cbor: "a2616201616102", term: "json([b-1,a-2])", nonDeterministic: true},
+			{cbor: "5f42010243030405ff", term: "bytes([1,2,3,4,5])", nonDeterministic: true},
+			{cbor: "7f657374726561646d696e67ff", term: "streaming", nonDeterministic: true},
+			{cbor: "9fff", term: "@([])", nonDeterministic: true},
+			{cbor: "9f018202039f0405ffff", term: "[1,[2,3],[4,5]]", nonDeterministic: true},
+			{cbor: "bf61610161629f0203ffff", term: "json([a-1,b-[2,3]])", nonDeterministic: true},
+		}
+		for _, tc := range cases {
+			Convey(fmt.Sprintf("Given the CBOR document %s", tc.cbor), func() {
+				db := tmdb.NewMemDB()
+				stateStore := store.NewCommitMultiStore(db)
+				ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+				interpreter := testutil.NewLightInterpreterMust(ctx)
+				interpreter.Register2(engine.NewAtom("cbor_prolog"), CBORProlog)
+
+				Convey("When the document is decoded", func() {
+					sols, err := interpreter.QueryContext(ctx, fmt.Sprintf("cbor_prolog('%s', Term).", tc.cbor))
+					So(err, ShouldBeNil)
+
+					Convey("Then the term should be as expected", func() {
+						So(sols.Next(), ShouldBeTrue)
+						m := types.TermResults{}
+						So(sols.Scan(m), ShouldBeNil)
+						So(string(m["Term"]), ShouldEqual, tc.term)
+						So(sols.Close(), ShouldBeNil)
+					})
+				})
+
+				if !tc.nonDeterministic {
+					Convey("When the term is encoded", func() {
+						sols, err := interpreter.QueryContext(ctx, fmt.Sprintf("cbor_prolog(Cbor, %s).", tc.term))
+						So(err, ShouldBeNil)
+
+						Convey("Then the document should be the same", func() {
+							So(sols.Next(), ShouldBeTrue)
+							m := types.TermResults{}
+							So(sols.Scan(m), ShouldBeNil)
+							So(strings.Trim(string(m["Cbor"]), "'"), ShouldEqual, tc.cbor)
+							So(sols.Close(), ShouldBeNil)
+						})
+					})
+				}
+			})
+		}
+	})
+}
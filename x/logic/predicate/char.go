@@ -0,0 +1,190 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+// charTypeNames is the ordered list of the character types supported by char_type/2, as Name/Arity.
+var charTypeNames = []string{
+	"alnum/0", "alpha/0", "csym/0", "csymf/0", "ascii/0", "white/0", "cntrl/0", "digit/1", "space/0", "end_of_line/0",
+	"lower/0", "lower/1", "upper/0", "upper/1", "punct/0", "graph/0", "print/0", "to_lower/1", "to_upper/1",
+}
+
+// CharType is a predicate which classifies characters.
+//
+// The signature is as follows:
+//
+//	char_type(?Char, ?Type) is nondet
+//
+// Where:
+//   - Char is a single character Atom.
+//   - Type is the type of the character, among the following ones:
+//     -- alnum: Char is a letter or a digit;
+//     -- alpha: Char is a letter;
+//     -- csym: Char is a letter, a digit or the underscore (_), i.e. it can appear in a C symbol;
+//     -- csymf: Char is a letter or the underscore (_), i.e. it can start a C symbol;
+//     -- ascii: Char is a 7 bits ASCII character;
+//     -- white: Char is a space or a tab;
+//     -- cntrl: Char is a control character;
+//     -- digit(Weight): Char is a decimal digit, whose value is the Integer Weight;
+//     -- space: Char is a white space character, including the line breaks;
+//     -- end_of_line: Char is a line feed or a carriage return;
+//     -- lower: Char is a lower case letter;
+//     -- lower(Upper): Char is a lower case letter, whose upper case is Upper;
+//     -- upper: Char is an upper case letter;
+//     -- upper(Lower): Char is an upper case letter, whose lower case is Lower;
+//     -- punct: Char is a punctuation or a symbol character;
+//     -- graph: Char is a visible character, i.e. a graphic character which is not a space;
+//     -- print: Char is a visible character or a space;
+//     -- to_lower(Lower): Lower is the lower case of Char, or Char itself if it has no lower case;
+//     -- to_upper(Upper): Upper is the upper case of Char, or Char itself if it has no upper case.
+//
+// The classification is Unicode-aware: letters and digits are not restricted to the ASCII ones, e.g. 'é' is an alpha
+// character and the Arabic-Indic digit '٣' is a digit of weight 3. When Char is not bound, the characters are
+// enumerated in the ascending order of their code points, and when Type is not bound, the types of Char are enumerated
+// in the order above.
+//
+// Examples:
+//
+//	# Check that a character is a digit, and get its value.
+//	- char_type('7', digit(Weight)).
+//
+//	# Get the upper case of a character.
+//	- char_type(é, to_upper(Upper)).
+func CharType(vm *engine.VM, char, typ engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		switch t := env.Resolve(typ).(type) {
+		case engine.Variable:
+		case engine.Atom, engine.Compound:
+			if !isCharTypeName(t) {
+				return engine.Error(fmt.Errorf("char_type/2: invalid type: %s. Possible values: %s",
+					charTypeIndicator(t), strings.Join(charTypeNames, ", ")))
+			}
+		default:
+			return engine.Error(fmt.Errorf("char_type/2: invalid type: %T, should be Atom or Compound", t))
+		}
+
+		switch c := env.Resolve(char).(type) {
+		case engine.Variable:
+			return charTypeEnumerate(vm, c, typ, 0, cont, env)
+		case engine.Atom:
+			r, size := utf8.DecodeRuneInString(c.String())
+			if size == 0 || size != len(c.String()) {
+				return engine.Error(fmt.Errorf("char_type/2: invalid character: %s, should be a single character", c))
+			}
+			return charTypeUnify(vm, r, typ, cont, env)
+		default:
+			return engine.Error(fmt.Errorf("char_type/2: invalid character type: %T, should be Atom or Variable", c))
+		}
+	})
+}
+
+// charTypeEnumerate enumerates the characters from the given one, unifying each one with char along with its types.
+func charTypeEnumerate(vm *engine.VM, char engine.Variable, typ engine.Term, r rune, cont engine.Cont,
+	env *engine.Env,
+) *engine.Promise {
+	for r <= unicode.MaxRune && !utf8.ValidRune(r) {
+		r++
+	}
+	if r > unicode.MaxRune {
+		return engine.Bool(false)
+	}
+
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		return engine.Unify(vm, char, engine.NewAtom(string(r)), func(env *engine.Env) *engine.Promise {
+			return charTypeUnify(vm, r, typ, cont, env)
+		}, env)
+	}, func(ctx context.Context) *engine.Promise {
+		return charTypeEnumerate(vm, char, typ, r+1, cont, env)
+	})
+}
+
+// charTypeUnify unifies the given type with each of the types of the given character.
+func charTypeUnify(vm *engine.VM, r rune, typ engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	types := charTypes(r)
+	ks := make([]func(context.Context) *engine.Promise, 0, len(types))
+	for _, t := range types {
+		t := t
+		ks = append(ks, func(context.Context) *engine.Promise {
+			return engine.Unify(vm, typ, t, cont, env)
+		})
+	}
+
+	return engine.Delay(ks...)
+}
+
+// charTypes returns the types of the given character, in the order of charTypeNames.
+//
+//nolint:cyclop
+func charTypes(r rune) []engine.Term {
+	types := make([]engine.Term, 0, len(charTypeNames))
+	add := func(holds bool, t engine.Term) {
+		if holds {
+			types = append(types, t)
+		}
+	}
+	char := func(r rune) engine.Term {
+		return engine.NewAtom(string(r))
+	}
+	isAlpha := unicode.IsLetter(r)
+	isDigit := unicode.IsDigit(r)
+
+	add(isAlpha || isDigit, engine.NewAtom("alnum"))
+	add(isAlpha, engine.NewAtom("alpha"))
+	add(isAlpha || isDigit || r == '_', engine.NewAtom("csym"))
+	add(isAlpha || r == '_', engine.NewAtom("csymf"))
+	add(r <= unicode.MaxASCII, engine.NewAtom("ascii"))
+	add(r == ' ' || r == '\t', engine.NewAtom("white"))
+	add(unicode.IsControl(r), engine.NewAtom("cntrl"))
+	add(isDigit, engine.NewAtom("digit").Apply(engine.Integer(digitWeight(r))))
+	add(unicode.IsSpace(r), engine.NewAtom("space"))
+	add(r == '\n' || r == '\r', engine.NewAtom("end_of_line"))
+	add(unicode.IsLower(r), engine.NewAtom("lower"))
+	add(unicode.IsLower(r), engine.NewAtom("lower").Apply(char(unicode.ToUpper(r))))
+	add(unicode.IsUpper(r), engine.NewAtom("upper"))
+	add(unicode.IsUpper(r), engine.NewAtom("upper").Apply(char(unicode.ToLower(r))))
+	add(unicode.IsPunct(r) || unicode.IsSymbol(r), engine.NewAtom("punct"))
+	add(unicode.IsGraphic(r) && !unicode.IsSpace(r), engine.NewAtom("graph"))
+	add(unicode.IsGraphic(r), engine.NewAtom("print"))
+	add(true, engine.NewAtom("to_lower").Apply(char(unicode.ToLower(r))))
+	add(true, engine.NewAtom("to_upper").Apply(char(unicode.ToUpper(r))))
+
+	return types
+}
+
+// digitWeight returns the value of the given decimal digit.
+//
+// The Unicode standard guarantees the decimal digits to be encoded in contiguous ranges of 10 characters, ordered from
+// 0 to 9, so the value of a digit is given by its offset from the start of its range.
+func digitWeight(r rune) int {
+	start := r
+	for unicode.IsDigit(start - 1) {
+		start--
+	}
+
+	return int(r-start) % 10
+}
+
+// isCharTypeName checks whether the given term denotes one of the types supported by char_type/2.
+func isCharTypeName(t engine.Term) bool {
+	return slices.Contains(charTypeNames, charTypeIndicator(t))
+}
+
+// charTypeIndicator returns the Name/Arity indicator of the given type term.
+func charTypeIndicator(t engine.Term) string {
+	switch t := t.(type) {
+	case engine.Compound:
+		return fmt.Sprintf("%s/%d", t.Functor(), t.Arity())
+	case engine.Atom:
+		return fmt.Sprintf("%s/0", t)
+	default:
+		return fmt.Sprintf("%v", t)
+	}
+}
@@ -0,0 +1,190 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestCharType(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `char_type('7', digit(W)).`,
+				wantResult:  []types.TermResults{{"W": "7"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type('\x663\', digit(W)).`,
+				wantResult:  []types.TermResults{{"W": "3"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type(é, alpha).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type(é, to_upper(U)).`,
+				wantResult:  []types.TermResults{{"U": "'É'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type('A', to_lower(L)).`,
+				wantResult:  []types.TermResults{{"L": "a"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type('1', to_lower(L)).`,
+				wantResult:  []types.TermResults{{"L": "'1'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type('A', upper(L)).`,
+				wantResult:  []types.TermResults{{"L": "a"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type('_', csym).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type('_', csymf).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type(' ', white).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type('\n', white).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `char_type('\n', end_of_line).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type(a, digit(_)).`,
+				wantSuccess: false,
+			},
+			{
+				query: `char_type(a, T).`,
+				wantResult: []types.TermResults{
+					{"T": "alnum"}, {"T": "alpha"}, {"T": "csym"}, {"T": "csymf"}, {"T": "ascii"}, {"T": "lower"},
+					{"T": "lower('A')"}, {"T": "graph"}, {"T": "print"}, {"T": "to_lower(a)"}, {"T": "to_upper('A')"},
+				},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type(C, digit(5)), !.`,
+				wantResult:  []types.TermResults{{"C": "'5'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type(C, upper(a)), !.`,
+				wantResult:  []types.TermResults{{"C": "'A'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `char_type(a, foo).`,
+				wantError:   fmt.Errorf("char_type/2: invalid type: foo/0. Possible values: alnum/0, alpha/0, csym/0, csymf/0, ascii/0, white/0, cntrl/0, digit/1, space/0, end_of_line/0, lower/0, lower/1, upper/0, upper/1, punct/0, graph/0, print/0, to_lower/1, to_upper/1"), //nolint:lll
+				wantSuccess: false,
+			},
+			{
+				query:       `char_type(a, 42).`,
+				wantError:   fmt.Errorf("char_type/2: invalid type: engine.Integer, should be Atom or Compound"),
+				wantSuccess: false,
+			},
+			{
+				query:       `char_type(ab, alpha).`,
+				wantError:   fmt.Errorf("char_type/2: invalid character: ab, should be a single character"),
+				wantSuccess: false,
+			},
+			{
+				query:       `char_type(42, alpha).`,
+				wantError:   fmt.Errorf("char_type/2: invalid character type: engine.Integer, should be Atom or Variable"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("char_type"), CharType)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,153 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"hash/crc64"
+
+	"github.com/ichiban/prolog/engine"
+	"github.com/samber/lo"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+// AtomPolynomial is the term used to indicate the polynomial option.
+var AtomPolynomial = engine.NewAtom("polynomial")
+
+// crc32Tables are the polynomials of crc32/3.
+var crc32Tables = map[string]*crc32.Table{
+	"ieee":       crc32.IEEETable,
+	"castagnoli": crc32.MakeTable(crc32.Castagnoli),
+}
+
+// crc64Tables are the polynomials of crc64/3.
+var crc64Tables = map[string]*crc64.Table{
+	"iso":  crc64.MakeTable(crc64.ISO),
+	"ecma": crc64.MakeTable(crc64.ECMA),
+}
+
+// CRC32 is a predicate that computes the CRC-32 checksum of the given Data.
+//
+// The signature is as follows:
+//
+//	crc32(+Data, -Checksum, +Options) is det
+//
+// Where:
+//   - Data is the data to checksum, either an Atom, whose text is checksummed, or a list of bytes.
+//   - Checksum is the checksum of Data, as an integer.
+//   - Options are additional configurations for the computation. Supported options include: polynomial(+Poly) which
+//     specifies the polynomial, either ieee (default), as used by zip, PNG or Ethernet, or castagnoli, as used by iSCSI
+//     or ext4. Any other option raises a domain_error(option, Option) error.
+//
+// A CRC detects accidental changes of the data but, unlike a cryptographic hash, gives no guarantee against deliberate
+// ones: it is meant as a cheap checksum of trusted data, e.g. to deduplicate it, and not to authenticate it.
+//
+// An unbound Data raises an instantiation_error, a Data which is neither an Atom nor a list of bytes a
+// type_error(list, Data) or type_error(byte, Element) error, and an unsupported Poly a domain_error(polynomial, Poly)
+// error.
+//
+// Examples:
+//
+//	# Compute the CRC-32 checksum of the given data.
+//	- crc32('Hello OKP4', Checksum, []).
+//
+//	# Compute the CRC-32C checksum of the given bytes.
+//	- crc32([72, 101, 108, 108, 111], Checksum, [polynomial(castagnoli)]).
+func CRC32(vm *engine.VM, data, checksum, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "crc32/3"
+
+		poly, err := checksumPolynomial(options, engine.NewAtom("ieee"), lo.Keys(crc32Tables), env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		input, err := checksumInput(ctx, functor, data, "crc32", env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		return engine.Unify(vm, checksum, engine.Integer(crc32.Checksum(input, crc32Tables[poly])), cont, env)
+	})
+}
+
+// CRC64 is a predicate that computes the CRC-64 checksum of the given Data.
+//
+// The signature is as follows:
+//
+//	crc64(+Data, -Checksum, +Options) is det
+//
+// Where:
+//   - Data is the data to checksum, either an Atom, whose text is checksummed, or a list of bytes.
+//   - Checksum is the checksum of Data, as an integer (see below for details).
+//   - Options are additional configurations for the computation. Supported options include: polynomial(+Poly) which
+//     specifies the polynomial, either iso (default), as defined by ISO 3309, or ecma, as defined by ECMA-182. Any other
+//     option raises a domain_error(option, Option) error.
+//
+// The integers being 64 bits signed integers, the checksums not lower than 2^63 are given as the negative integer of
+// the same 64 bits in two's complement, so that two data have the same checksum if and only if their Checksum are
+// equal.
+//
+// As for crc32/3, a CRC is meant as a cheap checksum of trusted data, and not to authenticate it.
+//
+// An unbound Data raises an instantiation_error, a Data which is neither an Atom nor a list of bytes a
+// type_error(list, Data) or type_error(byte, Element) error, and an unsupported Poly a domain_error(polynomial, Poly)
+// error.
+//
+// Examples:
+//
+//	# Compute the CRC-64 checksum of the given data.
+//	- crc64('Hello OKP4', Checksum, []).
+//
+//	# Compute the CRC-64 checksum of the given bytes with the ECMA polynomial.
+//	- crc64([72, 101, 108, 108, 111], Checksum, [polynomial(ecma)]).
+func CRC64(vm *engine.VM, data, checksum, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "crc64/3"
+
+		poly, err := checksumPolynomial(options, engine.NewAtom("iso"), lo.Keys(crc64Tables), env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		input, err := checksumInput(ctx, functor, data, "crc64", env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		sum := crc64.Checksum(input, crc64Tables[poly])
+		return engine.Unify(vm, checksum, engine.Integer(int64(sum)), cont, env)
+	})
+}
+
+// checksumPolynomial returns the polynomial of the given checksum options, among the given supported ones.
+func checksumPolynomial(options engine.Term, defaultPoly engine.Atom, supported []string, env *engine.Env,
+) (string, error) {
+	if err := util.CheckOptions(options, []engine.Atom{AtomPolynomial}, env); err != nil {
+		return "", err
+	}
+	polyTerm, err := util.GetOptionWithDefault(AtomPolynomial, options, defaultPoly, env)
+	if err != nil {
+		return "", err
+	}
+	poly, ok := env.Resolve(polyTerm).(engine.Atom)
+	if !ok {
+		return "", typeError(AtomAtom, polyTerm, env)
+	}
+	if !lo.Contains(supported, poly.String()) {
+		return "", domainError(AtomPolynomial, poly, env)
+	}
+	return poly.String(), nil
+}
+
+// checksumInput returns the bytes to checksum of the given data, and consumes the gas of the given algorithm for them.
+func checksumInput(ctx context.Context, functor string, data engine.Term, algorithm string, env *engine.Env,
+) ([]byte, error) {
+	input, err := hashInput(ctx, functor, data, env)
+	if err != nil {
+		return nil, err
+	}
+	if err := consumeAlgorithmGas(ctx, functor, algorithm, len(input)); err != nil {
+		return nil, fmt.Errorf("%s: %w", functor, err)
+	}
+	return input, nil
+}
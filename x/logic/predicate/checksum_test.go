@@ -0,0 +1,206 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestCRC(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{ // the check value of CRC-32/ISO-HDLC, 0xcbf43926
+				query:       `crc32('123456789', Checksum, []).`,
+				wantResult:  []types.TermResults{{"Checksum": "3421780262"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `crc32([72, 101, 108, 108, 111], Checksum, [polynomial(ieee)]).`,
+				wantResult:  []types.TermResults{{"Checksum": "4157704578"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `crc32([], Checksum, []).`,
+				wantResult:  []types.TermResults{{"Checksum": "0"}},
+				wantSuccess: true,
+			},
+			{ // the check value of CRC-32/ISCSI, 0xe3069283
+				query:       `crc32('123456789', Checksum, [polynomial(castagnoli)]).`,
+				wantResult:  []types.TermResults{{"Checksum": "3808858755"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `crc32('Hello', Checksum, [polynomial(castagnoli)]).`,
+				wantResult:  []types.TermResults{{"Checksum": "2178485787"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `crc32('Hello', 4157704578, []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `crc32('Hello', 4157704579, []).`,
+				wantSuccess: false,
+			},
+			{ // the check value of CRC-64/GO-ISO, 0xb90956c775a41001, in two's complement
+				query:       `crc64('123456789', Checksum, []).`,
+				wantResult:  []types.TermResults{{"Checksum": "-5113460487230320639"}},
+				wantSuccess: true,
+			},
+			{ // 0x3c3eeee2ee100000
+				query:       `crc64([72, 101, 108, 108, 111], Checksum, [polynomial(iso)]).`,
+				wantResult:  []types.TermResults{{"Checksum": "4341169749255782400"}},
+				wantSuccess: true,
+			},
+			{ // the check value of CRC-64/XZ, 0x995dc9bbdf1939fa, in two's complement
+				query:       `crc64('123456789', Checksum, [polynomial(ecma)]).`,
+				wantResult:  []types.TermResults{{"Checksum": "-7395533204333446662"}},
+				wantSuccess: true,
+			},
+			{ // 0x51cf5c3bc87bacc8
+				query:       `crc64('Hello', Checksum, [polynomial(ecma)]).`,
+				wantResult:  []types.TermResults{{"Checksum": "5895031849087642824"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `crc64([], Checksum, []).`,
+				wantResult:  []types.TermResults{{"Checksum": "0"}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crc32(Data, Checksum, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Data": "_1", "Checksum": "_1", "E": "error(instantiation_error,/(crc32,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crc64([1, 256], Checksum, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Checksum": "_1", "E": "error(type_error(byte,256),/(crc64,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crc32(foo(bar), Checksum, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Checksum": "_1", "E": "error(type_error(list,foo(bar)),/(crc32,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crc32('Hello', Checksum, [polynomial(iso)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Checksum": "_1", "E": "error(domain_error(polynomial,iso),/(crc32,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crc64('Hello', Checksum, [polynomial(castagnoli)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Checksum": "_1", "E": "error(domain_error(polynomial,castagnoli),/(crc64,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crc64('Hello', Checksum, [polynomial(P)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Checksum": "_1", "P": "_1", "E": "error(instantiation_error,/(crc64,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crc32('Hello', Checksum, [polynomial(1)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Checksum": "_1", "E": "error(type_error(atom,1),/(crc32,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crc32('Hello', Checksum, [encoding(hex)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Checksum": "_1", "E": "error(domain_error(option,encoding(hex)),/(crc32,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("crc32"), CRC32)
+						interpreter.Register3(engine.NewAtom("crc64"), CRC64)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
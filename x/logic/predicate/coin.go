@@ -0,0 +1,378 @@
+package predicate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ichiban/prolog/engine"
+
+	"cosmossdk.io/math"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+var (
+	// AtomCoin are terms with principal functor coin/2.
+	// It is used to represent a coin, i.e. an amount of a given denomination.
+	AtomCoin = engine.NewAtom("coin")
+
+	// AtomCoinDelta are terms with principal functor coin_delta/2.
+	// It is used to represent the signed difference of the amount of a coin denomination.
+	AtomCoinDelta = engine.NewAtom("coin_delta")
+
+	// AtomPosition are terms with principal functor position/2.
+	// It is used to represent the coins held by a position, identified by a key.
+	AtomPosition = engine.NewAtom("position")
+)
+
+// coinRegexp matches the textual representation of a coin, i.e. an amount immediately followed by a denomination,
+// possibly separated by spaces.
+var coinRegexp = regexp.MustCompile(`^([[:digit:]]+)[[:space:]]*(.*)$`)
+
+// ParseCoin is a predicate which parses the textual representation of a coin, i.e. an amount followed by a
+// denomination.
+//
+// The signature is as follows:
+//
+//	parse_coin(+Text, -Coin) is det
+//
+// Where:
+//   - Text is the textual representation of the coin, as an Atom, such as '100uknow'.
+//   - Coin is the resulting coin(Denom, Amount) term, where Denom is the denomination as an Atom and Amount is the
+//     amount, represented as an Atom holding a non-negative integer of arbitrary size.
+//
+// The parsing follows the semantics of the Cosmos SDK: the amount is made of decimal digits, the denomination must be
+// a valid coin denomination, and both may be separated by spaces. Leading and trailing spaces are ignored.
+//
+// Examples:
+//
+//	# Parse a coin.
+//	- parse_coin('100uknow', Coin).
+//
+//	# Parse an IBC coin.
+//	- parse_coin('42ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2', coin(Denom, Amount)).
+func ParseCoin(vm *engine.VM, text, coin engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		textAtom, err := util.ResolveToAtom(env, text)
+		if err != nil {
+			return engine.Error(fmt.Errorf("parse_coin/2: %w", err))
+		}
+
+		str := strings.TrimSpace(textAtom.String())
+		matches := coinRegexp.FindStringSubmatch(str)
+		if matches == nil {
+			return engine.Error(fmt.Errorf("parse_coin/2: invalid coin expression: %s, should start with an amount", str))
+		}
+		if matches[2] == "" {
+			return engine.Error(fmt.Errorf("parse_coin/2: invalid coin expression: %s, missing denomination", str))
+		}
+
+		amount, ok := new(big.Int).SetString(matches[1], 10)
+		if !ok || amount.BitLen() > math.MaxBitLen {
+			return engine.Error(fmt.Errorf("parse_coin/2: invalid coin amount: %s, out of range", matches[1]))
+		}
+		parsed := sdk.Coin{Denom: matches[2], Amount: math.NewIntFromBigInt(amount)}
+		if err := parsed.Validate(); err != nil {
+			return engine.Error(fmt.Errorf("parse_coin/2: %w", err))
+		}
+
+		return engine.Unify(vm, coin, AtomCoin.Apply(engine.NewAtom(parsed.Denom), engine.NewAtom(parsed.Amount.String())), cont, env)
+	})
+}
+
+// FormatCoin is a predicate which formats a coin into its textual representation, i.e. an amount followed by a
+// denomination.
+//
+// The signature is as follows:
+//
+//	format_coin(+Coin, -Text) is det
+//
+// Where:
+//   - Coin is the coin(Denom, Amount) term to format, where Denom is the denomination as an Atom and Amount is the
+//     amount, given either as an Integer or as an Atom holding a non-negative integer of arbitrary size.
+//   - Text is the textual representation of the coin, as an Atom.
+//
+// This predicate is the inverse of parse_coin/2.
+//
+// Examples:
+//
+//	# Format a coin.
+//	- format_coin(coin(uknow, 100), Text).
+func FormatCoin(vm *engine.VM, coin, text engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		c, ok := env.Resolve(coin).(engine.Compound)
+		if !ok || c.Functor() != AtomCoin || c.Arity() != 2 {
+			return engine.Error(fmt.Errorf("format_coin/2: invalid coin type: %T, should be coin(Denom, Amount)", env.Resolve(coin)))
+		}
+		denom, ok := env.Resolve(c.Arg(0)).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("format_coin/2: invalid coin denomination type: %T, should be Atom", env.Resolve(c.Arg(0))))
+		}
+		amount, err := termToCoinAmount(c.Arg(1), env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("format_coin/2: invalid amount for coin %s: %w", denom, err))
+		}
+		if amount.BitLen() > math.MaxBitLen {
+			return engine.Error(fmt.Errorf("format_coin/2: invalid amount for coin %s: %s is out of range", denom, amount))
+		}
+
+		formatted := sdk.Coin{Denom: denom.String(), Amount: math.NewIntFromBigInt(amount)}
+		if err := formatted.Validate(); err != nil {
+			return engine.Error(fmt.Errorf("format_coin/2: %w", err))
+		}
+
+		return engine.Unify(vm, text, util.StringToTerm(formatted.String()), cont, env)
+	})
+}
+
+// CoinsDelta is a predicate which computes the signed difference, per denomination, between two sets of coins.
+//
+// The signature is as follows:
+//
+//	coins_delta(+Before, +After, -Deltas) is det
+//
+// Where:
+//   - Before is the initial set of coins, as a list of pairs of coin denomination and amount.
+//   - After is the final set of coins, as a list of pairs of coin denomination and amount.
+//   - Deltas is the list of coin_delta(Denom, SignedAmount) terms, where SignedAmount is the difference After - Before
+//     for the denomination Denom, represented as an Atom holding a signed integer of arbitrary size.
+//
+// Amounts are given either as an Integer or as an Atom holding a non-negative integer of arbitrary size, which allows
+// to deal with amounts exceeding the range of Integers. A denomination present in only one of the sets is considered
+// as having a zero amount in the other one. Denominations whose amount is unchanged are omitted, and the resulting
+// list is sorted by denomination. An element of Before or After which is not a pair raises a type_error(pair,
+// Element), and a denomination which is not an Atom a type_error(atom, Denom).
+//
+// Examples:
+//
+//	# Compute the difference between two balances.
+//	- coins_delta([uknow-100, uatom-50], [uknow-80, uband-10], Deltas).
+func CoinsDelta(vm *engine.VM, before, after, deltas engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		beforeAmounts, err := termToCoinAmounts(before, env)
+		if err != nil {
+			return engine.Error(coinsDeltaError(err))
+		}
+
+		afterAmounts, err := termToCoinAmounts(after, env)
+		if err != nil {
+			return engine.Error(coinsDeltaError(err))
+		}
+
+		denoms := make([]string, 0, len(beforeAmounts)+len(afterAmounts))
+		for denom := range afterAmounts {
+			denoms = append(denoms, denom)
+		}
+		for denom := range beforeAmounts {
+			if _, ok := afterAmounts[denom]; !ok {
+				denoms = append(denoms, denom)
+			}
+		}
+		sort.Strings(denoms)
+
+		terms := make([]engine.Term, 0, len(denoms))
+		for _, denom := range denoms {
+			delta := new(big.Int)
+			if amount, ok := afterAmounts[denom]; ok {
+				delta.Set(amount)
+			}
+			if amount, ok := beforeAmounts[denom]; ok {
+				delta.Sub(delta, amount)
+			}
+			if delta.Sign() == 0 {
+				continue
+			}
+
+			terms = append(terms, AtomCoinDelta.Apply(engine.NewAtom(denom), engine.NewAtom(delta.String())))
+		}
+
+		return engine.Unify(vm, deltas, engine.List(terms...), cont, env)
+	})
+}
+
+// CanonicalizePositions is a predicate which computes the canonical representation of a list of positions, so that
+// logically equal positions have the same representation, and thus the same hash, whatever the way they are given.
+//
+// The signature is as follows:
+//
+//	canonicalize_positions(+Positions, -Canonical) is det
+//
+// Where:
+//   - Positions is the list of position(Key, Coins) terms, where Key is a ground term identifying the position (e.g.
+//     an owner address, or an Owner-Pool pair) and Coins is the list of the coins held by the position, given either
+//     as Denom-Amount pairs or as coin(Denom, Amount) terms, Amount being either an Integer or an Atom holding a
+//     non-negative integer of arbitrary size.
+//   - Canonical is the canonical list of position(Key, Coins) terms, where Coins is the list of coin(Denom, Amount)
+//     terms, Amount being an Atom holding the decimal representation of the amount.
+//
+// The canonicalization merges the positions having the same key, as well as the coins having the same denomination
+// in a position, by summing their amounts. The coins whose amount is zero are removed, then the positions holding no
+// coin. Finally, the coins are sorted by denomination and the positions by key, in the standard order of terms.
+//
+// Examples:
+//
+//	# Canonicalize positions, giving the same result whatever the order of the positions and of their coins.
+//	- canonicalize_positions([position(bob, [uknow-'100']), position(alice, [coin(uatom, 5), uknow-0])], Canonical).
+func CanonicalizePositions(vm *engine.VM, positions, canonical engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		if err := acyclicTermError(positions, env); err != nil {
+			return engine.Error(err)
+		}
+		terms, err := termToSlice(positions, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("canonicalize_positions/2: invalid positions: %w", err))
+		}
+
+		keys := make([]engine.Term, 0, len(terms))
+		amounts := make([]map[string]*big.Int, 0, len(terms))
+		for _, t := range terms {
+			p, ok := env.Resolve(t).(engine.Compound)
+			if !ok || p.Functor() != AtomPosition || p.Arity() != 2 {
+				return engine.Error(fmt.Errorf("canonicalize_positions/2: invalid position type: %T, should be position(Key, Coins)",
+					env.Resolve(t)))
+			}
+			if len(termVariables(p.Arg(0), env)) > 0 {
+				return engine.Error(fmt.Errorf("canonicalize_positions/2: position key is not sufficiently instantiated"))
+			}
+
+			idx := slices.IndexFunc(keys, func(k engine.Term) bool { return k.Compare(p.Arg(0), env) == 0 })
+			if idx == -1 {
+				idx = len(keys)
+				keys = append(keys, p.Arg(0))
+				amounts = append(amounts, make(map[string]*big.Int))
+			}
+			if err := addPositionCoins(amounts[idx], p.Arg(1), env); err != nil {
+				return engine.Error(fmt.Errorf("canonicalize_positions/2: invalid coins for position %v: %w",
+					env.Resolve(p.Arg(0)), err))
+			}
+		}
+
+		order := make([]int, 0, len(keys))
+		for i := range keys {
+			order = append(order, i)
+		}
+		sort.Slice(order, func(i, j int) bool { return keys[order[i]].Compare(keys[order[j]], env) < 0 })
+
+		result := make([]engine.Term, 0, len(keys))
+		for _, i := range order {
+			denoms := make([]string, 0, len(amounts[i]))
+			for denom, amount := range amounts[i] {
+				if amount.Sign() != 0 {
+					denoms = append(denoms, denom)
+				}
+			}
+			if len(denoms) == 0 {
+				continue
+			}
+			sort.Strings(denoms)
+
+			coins := make([]engine.Term, 0, len(denoms))
+			for _, denom := range denoms {
+				coins = append(coins, AtomCoin.Apply(engine.NewAtom(denom), engine.NewAtom(amounts[i][denom].String())))
+			}
+			result = append(result, AtomPosition.Apply(keys[i], engine.List(coins...)))
+		}
+
+		return engine.Unify(vm, canonical, engine.List(result...), cont, env)
+	})
+}
+
+// addPositionCoins adds the amounts of the given list of coins, given either as Denom-Amount pairs or as
+// coin(Denom, Amount) terms, to the given amounts indexed by denomination.
+func addPositionCoins(amounts map[string]*big.Int, coins engine.Term, env *engine.Env) error {
+	iter := engine.ListIterator{List: coins, Env: env}
+	for iter.Next() {
+		coin, ok := env.Resolve(iter.Current()).(engine.Compound)
+		if !ok || (coin.Functor() != AtomPair && coin.Functor() != AtomCoin) || coin.Arity() != 2 {
+			return fmt.Errorf("invalid coin type: %T, should be a Denom-Amount pair or a coin(Denom, Amount) term",
+				env.Resolve(iter.Current()))
+		}
+		denom, ok := env.Resolve(coin.Arg(0)).(engine.Atom)
+		if !ok {
+			return fmt.Errorf("invalid coin denomination: %v, should be Atom", env.Resolve(coin.Arg(0)))
+		}
+		amount, err := termToCoinAmount(coin.Arg(1), env)
+		if err != nil {
+			return fmt.Errorf("invalid amount for coin %s: %w", denom, err)
+		}
+
+		if sum, ok := amounts[denom.String()]; ok {
+			sum.Add(sum, amount)
+		} else {
+			amounts[denom.String()] = amount
+		}
+	}
+	return iter.Err()
+}
+
+// coinsDeltaError returns the given error raised by coins_delta/3, an ISO error being raised as is.
+func coinsDeltaError(err error) error {
+	var exception engine.Exception
+	if errors.As(err, &exception) {
+		return err
+	}
+	return fmt.Errorf("coins_delta/3: %w", err)
+}
+
+// termToCoinAmounts converts the given list of pairs of coin denomination and amount into a map of amounts indexed by
+// denomination.
+func termToCoinAmounts(coins engine.Term, env *engine.Env) (map[string]*big.Int, error) {
+	amounts := make(map[string]*big.Int)
+
+	iter := engine.ListIterator{List: coins, Env: env}
+	for iter.Next() {
+		pair, ok := env.Resolve(iter.Current()).(engine.Compound)
+		if !ok || pair.Functor() != AtomPair || pair.Arity() != 2 {
+			return nil, typeError(engine.NewAtom("pair"), iter.Current(), env)
+		}
+		denom, ok := env.Resolve(pair.Arg(0)).(engine.Atom)
+		if !ok {
+			return nil, typeError(AtomAtom, pair.Arg(0), env)
+		}
+		if _, ok := amounts[denom.String()]; ok {
+			return nil, fmt.Errorf("duplicated coin denomination: %s", denom)
+		}
+
+		amount, err := termToCoinAmount(pair.Arg(1), env)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount for coin %s: %w", denom, err)
+		}
+		amounts[denom.String()] = amount
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("invalid coins: %w", err)
+	}
+
+	return amounts, nil
+}
+
+// termToCoinAmount converts the given term into a non-negative big integer. The term is expected to be either an
+// Integer or an Atom holding the decimal representation of an integer.
+func termToCoinAmount(term engine.Term, env *engine.Env) (*big.Int, error) {
+	var amount *big.Int
+	switch t := env.Resolve(term).(type) {
+	case engine.Integer:
+		amount = big.NewInt(int64(t))
+	case engine.Atom:
+		i, ok := new(big.Int).SetString(t.String(), 10)
+		if !ok {
+			return nil, fmt.Errorf("%s is not an integer", t)
+		}
+		amount = i
+	default:
+		return nil, fmt.Errorf("%v, should be an Integer or an Atom", t)
+	}
+	if amount.Sign() < 0 {
+		return nil, fmt.Errorf("%s is negative", amount)
+	}
+
+	return amount, nil
+}
@@ -0,0 +1,437 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestCoinsDelta(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `coins_delta([uknow-100, uatom-50, uband-10], [uknow-80, uband-10, uakt-5, uatom-70], Deltas).`,
+				wantResult:  []types.TermResults{{"Deltas": "[coin_delta(uakt,'5'),coin_delta(uatom,'20'),coin_delta(uknow,'-20')]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `coins_delta([uknow-100], [], Deltas).`,
+				wantResult:  []types.TermResults{{"Deltas": "[coin_delta(uknow,'-100')]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `coins_delta([uknow-100, uatom-3], [uatom-3, uknow-100], Deltas).`,
+				wantResult:  []types.TermResults{{"Deltas": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `coins_delta([], [], []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `coins_delta([uknow-'100000000000000000000'], [uknow-1], Deltas).`,
+				wantResult:  []types.TermResults{{"Deltas": "[coin_delta(uknow,'-99999999999999999999')]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `coins_delta([uknow-9223372036854775807], [uknow-'9223372036854775808'], [coin_delta(uknow, '1')]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `coins_delta([uknow-1], [uknow-2], [coin_delta(uknow, '2')]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `coins_delta([uknow-1, uknow-2], [], Deltas).`,
+				wantError:   fmt.Errorf("coins_delta/3: duplicated coin denomination: uknow"),
+				wantSuccess: false,
+			},
+			{
+				query:       `coins_delta([], [uknow-(-1)], Deltas).`,
+				wantError:   fmt.Errorf("coins_delta/3: invalid amount for coin uknow: -1 is negative"),
+				wantSuccess: false,
+			},
+			{
+				query:       `coins_delta([], [uknow-foo], Deltas).`,
+				wantError:   fmt.Errorf("coins_delta/3: invalid amount for coin uknow: foo is not an integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `coins_delta([], [uknow-1.5], Deltas).`,
+				wantError:   fmt.Errorf("coins_delta/3: invalid amount for coin uknow: 1.5, should be an Integer or an Atom"),
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(coins_delta([uknow], [], _), error(E, _), true).`,
+				wantResult:  []types.TermResults{{"E": "type_error(pair,uknow)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(coins_delta([coin(1, x)], [coin(abc, x)], _), error(E, C), true).`,
+				wantResult:  []types.TermResults{{"E": "type_error(pair,coin(1,x))", "C": "/(coins_delta,3)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(coins_delta([], [1-1], _), error(E, _), true).`,
+				wantResult:  []types.TermResults{{"E": "type_error(atom,1)"}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("coins_delta"), CoinsDelta)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestParseCoin(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `parse_coin('100uknow', Coin).`,
+				wantResult:  []types.TermResults{{"Coin": "coin(uknow,'100')"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `parse_coin('42ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2', coin(Denom, Amount)).`,
+				wantResult:  []types.TermResults{{"Denom": "'ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2'", "Amount": "'42'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `parse_coin(' 007 uknow ', Coin).`,
+				wantResult:  []types.TermResults{{"Coin": "coin(uknow,'7')"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `parse_coin('100000000000000000000000000000uknow', Coin).`,
+				wantResult:  []types.TermResults{{"Coin": "coin(uknow,'100000000000000000000000000000')"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `parse_coin(uknow, Coin).`,
+				wantError:   fmt.Errorf("parse_coin/2: invalid coin expression: uknow, should start with an amount"),
+				wantSuccess: false,
+			},
+			{
+				query:       `parse_coin('100', Coin).`,
+				wantError:   fmt.Errorf("parse_coin/2: invalid coin expression: 100, missing denomination"),
+				wantSuccess: false,
+			},
+			{
+				query:       `parse_coin('1.5uknow', Coin).`,
+				wantError:   fmt.Errorf("parse_coin/2: invalid denom: .5uknow"),
+				wantSuccess: false,
+			},
+			{
+				query:       `parse_coin('10u', Coin).`,
+				wantError:   fmt.Errorf("parse_coin/2: invalid denom: u"),
+				wantSuccess: false,
+			},
+			{
+				query:       `format_coin(coin(uknow, 100), Text).`,
+				wantResult:  []types.TermResults{{"Text": "'100uknow'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_coin(coin('ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2', '100000000000000000000'), Text).`,
+				wantResult:  []types.TermResults{{"Text": "'100000000000000000000ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2'"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `round_trip(Text, Result) :- parse_coin(Text, Coin), format_coin(Coin, Result).`,
+				query:       `round_trip('100 uknow', Result).`,
+				wantResult:  []types.TermResults{{"Result": "'100uknow'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_coin(coin(u, 100), Text).`,
+				wantError:   fmt.Errorf("format_coin/2: invalid denom: u"),
+				wantSuccess: false,
+			},
+			{
+				query:       `format_coin(coin(uknow, -1), Text).`,
+				wantError:   fmt.Errorf("format_coin/2: invalid amount for coin uknow: -1 is negative"),
+				wantSuccess: false,
+			},
+			{
+				query:       `format_coin(uknow-100, Text).`,
+				wantError:   fmt.Errorf("format_coin/2: invalid coin type: *engine.compound, should be coin(Denom, Amount)"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("parse_coin"), ParseCoin)
+						interpreter.Register2(engine.NewAtom("format_coin"), FormatCoin)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestCanonicalizePositions(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `canonicalize_positions([], Canonical).`,
+				wantResult:  []types.TermResults{{"Canonical": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `canonicalize_positions([position(bob, [uknow-42]), position(alice, [uknow-100, uatom-5])], Canonical).`,
+				wantResult:  []types.TermResults{{"Canonical": "[position(alice,[coin(uatom,'5'),coin(uknow,'100')]),position(bob,[coin(uknow,'42')])]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `canonicalize_positions([position(alice, [coin(uatom, '5'), uknow-'0100']), position(bob, [uknow-'42'])], Canonical).`,
+				wantResult:  []types.TermResults{{"Canonical": "[position(alice,[coin(uatom,'5'),coin(uknow,'100')]),position(bob,[coin(uknow,'42')])]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `canonicalize_positions([position(alice, [uknow-60, uband-0]), position(bob, [uknow-42]), position(alice, [uatom-5, uknow-40])], Canonical).`,
+				wantResult:  []types.TermResults{{"Canonical": "[position(alice,[coin(uatom,'5'),coin(uknow,'100')]),position(bob,[coin(uknow,'42')])]"}},
+				wantSuccess: true,
+			},
+			{
+				query: `canonicalize_positions([position(bob, [coin(uknow, 42)]), position(alice, [uatom-5, uknow-100])], C1), canonicalize_positions([position(alice, [coin(uknow, '100'), coin(uatom, 5)]), position(bob, [uknow-'42'])], C2), C1 == C2.`,
+				wantResult: []types.TermResults{{
+					"C1": "[position(alice,[coin(uatom,'5'),coin(uknow,'100')]),position(bob,[coin(uknow,'42')])]",
+					"C2": "[position(alice,[coin(uatom,'5'),coin(uknow,'100')]),position(bob,[coin(uknow,'42')])]",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query:       `canonicalize_positions([position(alice-pool1, [uknow-1]), position(alice, [uknow-2]), position(bob-pool1, [uknow-0])], Canonical).`,
+				wantResult:  []types.TermResults{{"Canonical": "[position(alice,[coin(uknow,'2')]),position(alice-pool1,[coin(uknow,'1')])]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `canonicalize_positions([position(alice, ['ibc/27394FB0'-'18446744073709551616', 'ibc/27394FB0'-1])], Canonical).`,
+				wantResult:  []types.TermResults{{"Canonical": "[position(alice,[coin('ibc/27394FB0','18446744073709551617')])]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch((P = [position(P, [uknow-1])], canonicalize_positions(P, Canonical)), E, R = caught).`,
+				wantResult:  []types.TermResults{{"P": "_1", "Canonical": "_1", "R": "caught", "E": "error(type_error(acyclic_term,_1),/(canonicalize_positions,2))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `canonicalize_positions(foo, Canonical).`,
+				wantError:   fmt.Errorf("canonicalize_positions/2: invalid positions: invalid list: error(type_error(list,foo),canonicalize_positions/2)"),
+				wantSuccess: false,
+			},
+			{
+				query:       `canonicalize_positions([alice-[uknow-1]], Canonical).`,
+				wantError:   fmt.Errorf("canonicalize_positions/2: invalid position type: *engine.compound, should be position(Key, Coins)"),
+				wantSuccess: false,
+			},
+			{
+				query:       `canonicalize_positions([position(_, [uknow-1])], Canonical).`,
+				wantError:   fmt.Errorf("canonicalize_positions/2: position key is not sufficiently instantiated"),
+				wantSuccess: false,
+			},
+			{
+				query:       `canonicalize_positions([position(alice, [uknow-(-1)])], Canonical).`,
+				wantError:   fmt.Errorf("canonicalize_positions/2: invalid coins for position alice: invalid amount for coin uknow: -1 is negative"),
+				wantSuccess: false,
+			},
+			{
+				query:       `canonicalize_positions([position(alice, [uknow])], Canonical).`,
+				wantError:   fmt.Errorf("canonicalize_positions/2: invalid coins for position alice: invalid coin type: engine.Atom, should be a Denom-Amount pair or a coin(Denom, Amount) term"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("canonicalize_positions"), CanonicalizePositions)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
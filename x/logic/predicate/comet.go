@@ -0,0 +1,628 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/cometbft/cometbft/crypto"
+	"github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/crypto/secp256k1"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	cmtversion "github.com/cometbft/cometbft/proto/tendermint/version"
+	comettypes "github.com/cometbft/cometbft/types"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+var (
+	// AtomHeader are terms with principal functor header/1.
+	// It is used to represent a CometBFT block header.
+	AtomHeader = engine.NewAtom("header")
+
+	// AtomVersion are terms with principal functor version/2.
+	// It is used to represent the block and application protocol versions of a CometBFT block header.
+	AtomVersion = engine.NewAtom("version")
+
+	// AtomBlockID are terms with principal functor block_id/2.
+	// It is used to represent the identifier of a CometBFT block.
+	AtomBlockID = engine.NewAtom("block_id")
+
+	// AtomPartSetHeader are terms with principal functor part_set_header/2.
+	// It is used to represent the header of the set of parts of a CometBFT block.
+	AtomPartSetHeader = engine.NewAtom("part_set_header")
+
+	// AtomCommit are terms with principal functor commit/4.
+	// It is used to represent a CometBFT commit, i.e. the set of signatures of the validators for a block.
+	AtomCommit = engine.NewAtom("commit")
+
+	// AtomCommitSig are terms with principal functor commit_sig/4.
+	// It is used to represent the signature of a validator in a CometBFT commit.
+	AtomCommitSig = engine.NewAtom("commit_sig")
+
+	// AtomValidator are terms with principal functor validator/2.
+	// It is used to represent a CometBFT validator with its voting power.
+	AtomValidator = engine.NewAtom("validator")
+
+	// AtomVote are terms with principal functor vote/6.
+	// It is used to represent a CometBFT vote, as signed by a validator.
+	AtomVote = engine.NewAtom("vote")
+)
+
+// cometVoteTypes maps the atoms representing the type of a vote to their CometBFT signed message type.
+var cometVoteTypes = map[engine.Atom]cmtproto.SignedMsgType{
+	engine.NewAtom("prevote"):   cmtproto.PrevoteType,
+	engine.NewAtom("precommit"): cmtproto.PrecommitType,
+}
+
+// cometBlockIDFlags maps the atoms representing the kind of vote of a commit signature to their CometBFT flag.
+var cometBlockIDFlags = map[engine.Atom]comettypes.BlockIDFlag{
+	engine.NewAtom("absent"): comettypes.BlockIDFlagAbsent,
+	AtomCommit:               comettypes.BlockIDFlagCommit,
+	engine.NewAtom("nil"):    comettypes.BlockIDFlagNil,
+}
+
+// CometHeaderHash is a predicate which computes the hash of a CometBFT block header, i.e. the block hash.
+//
+// The signature is as follows:
+//
+//	comet_header_hash(+Header, -Hash) is det
+//
+// Where:
+//   - Header is the block header, as a header(Fields) term where Fields is a list of Name-Value pairs (see below).
+//   - Hash is the hash of the header, as a list of 32 bytes.
+//
+// The supported fields, all optional, are:
+//   - version-version(Block, App): the block and application protocol versions, as non-negative Integers;
+//   - chain_id-ChainID: the chain identifier, as an Atom;
+//   - height-Height: the block height, as an Integer;
+//   - time-Time: the block time, either as an Atom in the RFC 3339 format (with an optional fractional part of up to
+//     9 digits) or as an Integer being the number of seconds since the Unix epoch;
+//   - last_block_id-block_id(Hash, part_set_header(Total, PartsHash)): the identifier of the previous block;
+//   - last_commit_hash, data_hash, validators_hash, next_validators_hash, consensus_hash, app_hash,
+//     last_results_hash, evidence_hash and proposer_address: the merkle roots, hashes and address of the header.
+//
+// Hashes and addresses are given either as hexadecimal Atoms or as lists of bytes. A missing field takes the zero
+// value of its type, except the validators_hash field which is required. The computation follows exactly the one of
+// CometBFT: the hash is the root of the merkle tree of the protobuf encoding of the fields, in the order of the header.
+//
+// Examples:
+//
+//	# Compute the hash of a block header.
+//	- comet_header_hash(header([chain_id-'okp4-nemeton-1', height-42, time-'2023-06-01T10:00:00Z',
+//	  validators_hash-'3A8F0A4C09E1D1B8AF9B6F4C2F5D6E0C8D7B9A1E2F3C4D5B6A7980F1E2D3C4B5']), Hash).
+func CometHeaderHash(vm *engine.VM, header, hash engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		h, err := termToCometHeader(ctx, header, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("comet_header_hash/2: %w", err))
+		}
+		if len(h.ValidatorsHash) == 0 {
+			return engine.Error(fmt.Errorf("comet_header_hash/2: missing validators_hash field"))
+		}
+
+		return engine.Unify(vm, hash, BytesToList(h.Hash()), cont, env)
+	})
+}
+
+// CometVerifyCommit is a predicate which verifies that a CometBFT commit is signed by validators holding more than a
+// given part of the total voting power of a validator set, as done by light clients.
+//
+// The signature is as follows:
+//
+//	comet_verify_commit(+ChainID, +Commit, +ValidatorSet, +TrustLevel) is semidet
+//
+// Where:
+//   - ChainID is the identifier of the chain, as an Atom.
+//   - Commit is the commit, as a commit(Height, Round, BlockID, Signatures) term where Height and Round are Integers,
+//     BlockID is the block_id(Hash, part_set_header(Total, PartsHash)) term identifying the committed block (see
+//     comet_header_hash/2) and Signatures is the list of commit_sig(Flag, ValidatorAddress, Timestamp, Signature) terms.
+//   - ValidatorSet is the list of validator(PubKey, VotingPower) terms, where PubKey is the ed25519 public key of the
+//     validator and VotingPower its voting power, as a positive Integer.
+//   - TrustLevel is the part of the total voting power that must be exceeded, as a N/D fraction of Integers such that
+//     0 < N <= D.
+//
+// In a commit signature, Flag is one of absent, commit or nil, depending on whether the validator respectively did not
+// vote, voted for the block or voted for no block. Timestamp is the time of the vote, in the same format as the time of
+// comet_header_hash/2. Hashes, addresses, public keys and signatures are given either as hexadecimal Atoms or as lists
+// of bytes.
+//
+// Each signature for the block is verified over the canonical sign bytes of the vote. The signatures which are not for
+// the block, which are not from a validator of the set, which are duplicated or which are invalid are ignored: the
+// predicate only fails if the voting power of the valid signatures does not exceed the trust level.
+//
+// Examples:
+//
+//	# Verify that more than 2/3 of the voting power signed the commit.
+//	- comet_verify_commit('okp4-nemeton-1', commit(42, 0, block_id(Hash, part_set_header(1, PartsHash)), Signatures),
+//	  [validator('2866de9a5d64e18294079521b2b26279c0cc8e4428cf312279e32421d3a143eb', 10)], 2/3).
+func CometVerifyCommit(_ *engine.VM, chainID, commit, validatorSet, trustLevel engine.Term, cont engine.Cont,
+	env *engine.Env,
+) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		chainIDAtom, err := util.ResolveToAtom(env, chainID)
+		if err != nil {
+			return engine.Error(fmt.Errorf("comet_verify_commit/4: %w", err))
+		}
+		c, err := termToCometCommit(ctx, commit, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("comet_verify_commit/4: %w", err))
+		}
+		validators, totalPower, err := termToCometValidators(ctx, validatorSet, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("comet_verify_commit/4: %w", err))
+		}
+		numerator, denominator, err := termToTrustLevel(trustLevel, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("comet_verify_commit/4: %w", err))
+		}
+
+		needed := new(big.Int).Mul(totalPower, numerator)
+		needed.Quo(needed, denominator)
+
+		tallied := new(big.Int)
+		seen := make(map[string]struct{}, len(c.Signatures))
+		for idx, sig := range c.Signatures {
+			if !sig.ForBlock() {
+				continue
+			}
+			val, ok := validators[string(sig.ValidatorAddress)]
+			if !ok {
+				continue
+			}
+			if _, ok := seen[string(sig.ValidatorAddress)]; ok {
+				continue
+			}
+
+			signBytes := c.VoteSignBytes(chainIDAtom.String(), int32(idx))
+			if err := consumeAlgorithmGas(ctx, "comet_verify_commit/4", util.Ed25519.String(), len(signBytes)); err != nil {
+				return engine.Error(fmt.Errorf("comet_verify_commit/4: %w", err))
+			}
+			if !val.PubKey.VerifySignature(signBytes, sig.Signature) {
+				continue
+			}
+
+			seen[string(sig.ValidatorAddress)] = struct{}{}
+			tallied.Add(tallied, big.NewInt(val.VotingPower))
+			if tallied.Cmp(needed) > 0 {
+				return cont(env)
+			}
+		}
+
+		return engine.Bool(false)
+	})
+}
+
+// CanonicalVoteBytes is a predicate which computes the bytes signed by a CometBFT validator for a vote, i.e. the
+// length-delimited protobuf encoding of its canonical form.
+//
+// The signature is as follows:
+//
+//	canonical_vote_bytes(+Vote, -Bytes) is det
+//
+// Where:
+//   - Vote is the vote, as a vote(ChainID, Type, Height, Round, BlockID, Timestamp) term (see below).
+//   - Bytes is the list of bytes signed for the vote.
+//
+// In a vote, ChainID is the identifier of the chain, as an Atom, Type is either prevote or precommit, Height and Round
+// are non-negative Integers, BlockID is either the block_id(Hash, part_set_header(Total, PartsHash)) term identifying
+// the block voted for (see comet_header_hash/2) or nil for a vote for no block, and Timestamp is the time of the vote, in
+// the same format as the time of comet_header_hash/2.
+//
+// The bytes are the ones CometBFT signs, so that they can be given to eddsa_verify/4 along with the signature of the
+// vote, or to any other signature verification predicate.
+//
+// Examples:
+//
+//	# Compute the bytes signed for a precommit vote.
+//	- canonical_vote_bytes(vote('okp4-nemeton-1', precommit, 42, 0, block_id(Hash, part_set_header(1, PartsHash)),
+//	  '2023-06-01T10:00:00Z'), Bytes).
+func CanonicalVoteBytes(vm *engine.VM, vote, bytes engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		chainID, v, err := termToCometVote(ctx, vote, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("canonical_vote_bytes/2: %w", err))
+		}
+
+		return engine.Unify(vm, bytes, BytesToList(comettypes.VoteSignBytes(chainID, v)), cont, env)
+	})
+}
+
+// TendermintVerify is a predicate which verifies the signature of a CometBFT vote by a validator.
+//
+// The signature is as follows:
+//
+//	tendermint_verify(+PubKey, +Vote, +Signature, +Options) is semidet
+//
+// Where:
+//   - PubKey is the public key of the validator, either as an hexadecimal Atom or as a list of bytes.
+//   - Vote is the vote, as a vote(ChainID, Type, Height, Round, BlockID, Timestamp) term (see canonical_vote_bytes/2).
+//   - Signature is the signature of the vote, either as an hexadecimal Atom or as a list of bytes.
+//   - Options are additional configurations for the verification. Supported options include: type(+Type) which
+//     specifies the type of the key of the validator, either ed25519 (default) or secp256k1.
+//
+// The signature is verified over the canonical sign bytes of the vote, as given by canonical_vote_bytes/2, the way
+// CometBFT does: a secp256k1 signature is the 64 bytes R || S of the signature of the SHA-256 digest of those bytes
+// with a low S. The predicate fails if the signature is invalid.
+//
+// An unsupported Type raises a domain_error(algorithm, Type) error, and a PubKey which is not of the size of the keys
+// of Type a domain_error(public_key, PubKey) error.
+//
+// Examples:
+//
+//	# Verify the signature of a precommit vote by an ed25519 validator key.
+//	- tendermint_verify('2866de9a5d64e18294079521b2b26279c0cc8e4428cf312279e32421d3a143eb',
+//	  vote('okp4-nemeton-1', precommit, 42, 0, BlockID, '2023-06-01T10:00:00Z'), Signature, []).
+func TendermintVerify(_ *engine.VM, pubKey, vote, signature, options engine.Term, cont engine.Cont,
+	env *engine.Env,
+) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "tendermint_verify/4"
+
+		if err := util.CheckOptions(options, []engine.Atom{AtomType}, env); err != nil {
+			return engine.Error(err)
+		}
+		alg, err := verifyAlgorithm(options, util.Ed25519, []util.Alg{util.Ed25519, util.Secp256k1}, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		key, err := termToHexOrBytes(ctx, pubKey, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: invalid public key: %w", functor, err))
+		}
+		var pk crypto.PubKey
+		switch alg {
+		case util.Secp256k1:
+			if len(key) != secp256k1.PubKeySize {
+				return engine.Error(domainError(AtomPublicKey, pubKey, env))
+			}
+			pk = secp256k1.PubKey(key)
+		default:
+			if len(key) != ed25519.PubKeySize {
+				return engine.Error(domainError(AtomPublicKey, pubKey, env))
+			}
+			pk = ed25519.PubKey(key)
+		}
+		chainID, v, err := termToCometVote(ctx, vote, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		sig, err := termToHexOrBytes(ctx, signature, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: invalid signature: %w", functor, err))
+		}
+
+		signBytes := comettypes.VoteSignBytes(chainID, v)
+		if err := consumeAlgorithmGas(ctx, functor, alg.String(), len(signBytes)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		if !pk.VerifySignature(signBytes, sig) {
+			return engine.Bool(false)
+		}
+
+		return cont(env)
+	})
+}
+
+// termToCometVote converts the given vote(ChainID, Type, Height, Round, BlockID, Timestamp) term into a CometBFT vote
+// along with the identifier of the chain it is cast on.
+func termToCometVote(ctx context.Context, vote engine.Term, env *engine.Env) (string, *cmtproto.Vote, error) {
+	c, ok := env.Resolve(vote).(engine.Compound)
+	if !ok || c.Functor() != AtomVote || c.Arity() != 6 {
+		return "", nil, fmt.Errorf("invalid vote type: %T, should be vote(ChainID, Type, Height, Round, BlockID, Timestamp)",
+			env.Resolve(vote))
+	}
+	chainID, err := util.ResolveToAtom(env, c.Arg(0))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid vote chain id: %w", err)
+	}
+	typeAtom, ok := env.Resolve(c.Arg(1)).(engine.Atom)
+	if !ok {
+		return "", nil, fmt.Errorf("invalid vote type: %v, valid values are 'prevote' or 'precommit'", env.Resolve(c.Arg(1)))
+	}
+	typ, ok := cometVoteTypes[typeAtom]
+	if !ok {
+		return "", nil, fmt.Errorf("invalid vote type: %v, valid values are 'prevote' or 'precommit'", typeAtom)
+	}
+	height, ok := env.Resolve(c.Arg(2)).(engine.Integer)
+	if !ok || height < 0 {
+		return "", nil, fmt.Errorf("invalid vote height: %v, should be a non-negative Integer", env.Resolve(c.Arg(2)))
+	}
+	round, ok := env.Resolve(c.Arg(3)).(engine.Integer)
+	if !ok || round < 0 || round > math.MaxInt32 {
+		return "", nil, fmt.Errorf("invalid vote round: %v, should be a 32 bits non-negative Integer", env.Resolve(c.Arg(3)))
+	}
+	var blockID comettypes.BlockID
+	if env.Resolve(c.Arg(4)) != engine.NewAtom("nil") {
+		if blockID, err = termToCometBlockID(ctx, c.Arg(4), env); err != nil {
+			return "", nil, fmt.Errorf("invalid vote block id: %w", err)
+		}
+	}
+	timestamp, err := termToCometTime(c.Arg(5), env)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid vote timestamp: %w", err)
+	}
+
+	return chainID.String(), &cmtproto.Vote{
+		Type:      typ,
+		Height:    int64(height),
+		Round:     int32(round),
+		BlockID:   blockID.ToProto(),
+		Timestamp: timestamp,
+	}, nil
+}
+
+// termToCometHeader converts the given header(Fields) term into a CometBFT block header.
+//
+//nolint:funlen,cyclop
+func termToCometHeader(ctx context.Context, header engine.Term, env *engine.Env) (*comettypes.Header, error) {
+	c, ok := env.Resolve(header).(engine.Compound)
+	if !ok || c.Functor() != AtomHeader || c.Arity() != 1 {
+		return nil, fmt.Errorf("invalid header type: %T, should be header(Fields)", env.Resolve(header))
+	}
+
+	h := &comettypes.Header{}
+	iter := engine.ListIterator{List: c.Arg(0), Env: env}
+	for iter.Next() {
+		pair, ok := env.Resolve(iter.Current()).(engine.Compound)
+		if !ok || pair.Functor() != AtomPair || pair.Arity() != 2 {
+			return nil, fmt.Errorf("invalid header field type: %T, should be Name-Value", env.Resolve(iter.Current()))
+		}
+		name, ok := env.Resolve(pair.Arg(0)).(engine.Atom)
+		if !ok {
+			return nil, fmt.Errorf("invalid header field name type: %T, should be Atom", env.Resolve(pair.Arg(0)))
+		}
+
+		var err error
+		value := pair.Arg(1)
+		switch name.String() {
+		case "version":
+			h.Version, err = termToCometVersion(value, env)
+		case "chain_id":
+			var chainID engine.Atom
+			chainID, err = util.ResolveToAtom(env, value)
+			h.ChainID = chainID.String()
+		case "height":
+			height, ok := env.Resolve(value).(engine.Integer)
+			if !ok {
+				err = fmt.Errorf("should be an Integer")
+			}
+			h.Height = int64(height)
+		case "time":
+			h.Time, err = termToCometTime(value, env)
+		case "last_block_id":
+			h.LastBlockID, err = termToCometBlockID(ctx, value, env)
+		case "last_commit_hash":
+			h.LastCommitHash, err = termToHexOrBytes(ctx, value, env)
+		case "data_hash":
+			h.DataHash, err = termToHexOrBytes(ctx, value, env)
+		case "validators_hash":
+			h.ValidatorsHash, err = termToHexOrBytes(ctx, value, env)
+		case "next_validators_hash":
+			h.NextValidatorsHash, err = termToHexOrBytes(ctx, value, env)
+		case "consensus_hash":
+			h.ConsensusHash, err = termToHexOrBytes(ctx, value, env)
+		case "app_hash":
+			h.AppHash, err = termToHexOrBytes(ctx, value, env)
+		case "last_results_hash":
+			h.LastResultsHash, err = termToHexOrBytes(ctx, value, env)
+		case "evidence_hash":
+			h.EvidenceHash, err = termToHexOrBytes(ctx, value, env)
+		case "proposer_address":
+			h.ProposerAddress, err = termToHexOrBytes(ctx, value, env)
+		default:
+			return nil, fmt.Errorf("unknown header field: %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid header field %s: %w", name, err)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("invalid header fields: %w", err)
+	}
+
+	return h, nil
+}
+
+// termToCometVersion converts the given version(Block, App) term into the protocol versions of a block header.
+func termToCometVersion(version engine.Term, env *engine.Env) (cmtversion.Consensus, error) {
+	c, ok := env.Resolve(version).(engine.Compound)
+	if !ok || c.Functor() != AtomVersion || c.Arity() != 2 {
+		return cmtversion.Consensus{}, fmt.Errorf("invalid type: %T, should be version(Block, App)", env.Resolve(version))
+	}
+	block, ok := env.Resolve(c.Arg(0)).(engine.Integer)
+	if !ok || block < 0 {
+		return cmtversion.Consensus{}, fmt.Errorf("invalid block version: %v, should be a non-negative Integer", env.Resolve(c.Arg(0)))
+	}
+	app, ok := env.Resolve(c.Arg(1)).(engine.Integer)
+	if !ok || app < 0 {
+		return cmtversion.Consensus{}, fmt.Errorf("invalid app version: %v, should be a non-negative Integer", env.Resolve(c.Arg(1)))
+	}
+
+	return cmtversion.Consensus{Block: uint64(block), App: uint64(app)}, nil
+}
+
+// termToCometTime converts the given term into a time, the term being either an Atom in the RFC 3339 format or an
+// Integer being the number of seconds since the Unix epoch.
+func termToCometTime(t engine.Term, env *engine.Env) (time.Time, error) {
+	switch v := env.Resolve(t).(type) {
+	case engine.Integer:
+		return time.Unix(int64(v), 0).UTC(), nil
+	case engine.Atom:
+		parsed, err := time.Parse(time.RFC3339Nano, v.String())
+		if err != nil {
+			return time.Time{}, err
+		}
+		return parsed.UTC(), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid type: %T, should be Atom or Integer", v)
+	}
+}
+
+// termToCometBlockID converts the given block_id(Hash, part_set_header(Total, PartsHash)) term into a block
+// identifier.
+func termToCometBlockID(ctx context.Context, blockID engine.Term, env *engine.Env) (comettypes.BlockID, error) {
+	c, ok := env.Resolve(blockID).(engine.Compound)
+	if !ok || c.Functor() != AtomBlockID || c.Arity() != 2 {
+		return comettypes.BlockID{}, fmt.Errorf("invalid type: %T, should be block_id(Hash, PartSetHeader)", env.Resolve(blockID))
+	}
+	hash, err := termToHexOrBytes(ctx, c.Arg(0), env)
+	if err != nil {
+		return comettypes.BlockID{}, fmt.Errorf("invalid hash: %w", err)
+	}
+
+	psh, ok := env.Resolve(c.Arg(1)).(engine.Compound)
+	if !ok || psh.Functor() != AtomPartSetHeader || psh.Arity() != 2 {
+		return comettypes.BlockID{}, fmt.Errorf("invalid part set header type: %T, should be part_set_header(Total, Hash)",
+			env.Resolve(c.Arg(1)))
+	}
+	total, ok := env.Resolve(psh.Arg(0)).(engine.Integer)
+	if !ok || total < 0 || total > 1<<32-1 {
+		return comettypes.BlockID{}, fmt.Errorf("invalid part set header total: %v, should be a 32 bits unsigned Integer",
+			env.Resolve(psh.Arg(0)))
+	}
+	partsHash, err := termToHexOrBytes(ctx, psh.Arg(1), env)
+	if err != nil {
+		return comettypes.BlockID{}, fmt.Errorf("invalid part set header hash: %w", err)
+	}
+
+	return comettypes.BlockID{
+		Hash:          hash,
+		PartSetHeader: comettypes.PartSetHeader{Total: uint32(total), Hash: partsHash},
+	}, nil
+}
+
+// termToCometCommit converts the given commit(Height, Round, BlockID, Signatures) term into a CometBFT commit.
+func termToCometCommit(ctx context.Context, commit engine.Term, env *engine.Env) (*comettypes.Commit, error) {
+	c, ok := env.Resolve(commit).(engine.Compound)
+	if !ok || c.Functor() != AtomCommit || c.Arity() != 4 {
+		return nil, fmt.Errorf("invalid commit type: %T, should be commit(Height, Round, BlockID, Signatures)", env.Resolve(commit))
+	}
+	height, ok := env.Resolve(c.Arg(0)).(engine.Integer)
+	if !ok || height < 0 {
+		return nil, fmt.Errorf("invalid commit height: %v, should be a non-negative Integer", env.Resolve(c.Arg(0)))
+	}
+	round, ok := env.Resolve(c.Arg(1)).(engine.Integer)
+	if !ok || round < 0 || round > math.MaxInt32 {
+		return nil, fmt.Errorf("invalid commit round: %v, should be a 32 bits non-negative Integer", env.Resolve(c.Arg(1)))
+	}
+	blockID, err := termToCometBlockID(ctx, c.Arg(2), env)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit block id: %w", err)
+	}
+
+	result := &comettypes.Commit{Height: int64(height), Round: int32(round), BlockID: blockID}
+	iter := engine.ListIterator{List: c.Arg(3), Env: env}
+	for iter.Next() {
+		sig, err := termToCometCommitSig(ctx, iter.Current(), env)
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit signature: %w", err)
+		}
+		result.Signatures = append(result.Signatures, sig)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("invalid commit signatures: %w", err)
+	}
+
+	return result, nil
+}
+
+// termToCometCommitSig converts the given commit_sig(Flag, ValidatorAddress, Timestamp, Signature) term into the
+// signature of a validator in a CometBFT commit.
+func termToCometCommitSig(ctx context.Context, sig engine.Term, env *engine.Env) (comettypes.CommitSig, error) {
+	c, ok := env.Resolve(sig).(engine.Compound)
+	if !ok || c.Functor() != AtomCommitSig || c.Arity() != 4 {
+		return comettypes.CommitSig{}, fmt.Errorf("invalid type: %T, should be commit_sig(Flag, ValidatorAddress, Timestamp, Signature)",
+			env.Resolve(sig))
+	}
+	flagAtom, ok := env.Resolve(c.Arg(0)).(engine.Atom)
+	if !ok {
+		return comettypes.CommitSig{}, fmt.Errorf("invalid flag type: %T, should be Atom", env.Resolve(c.Arg(0)))
+	}
+	flag, ok := cometBlockIDFlags[flagAtom]
+	if !ok {
+		return comettypes.CommitSig{}, fmt.Errorf("invalid flag: %v, valid values are 'absent', 'commit' or 'nil'", env.Resolve(c.Arg(0)))
+	}
+	address, err := termToHexOrBytes(ctx, c.Arg(1), env)
+	if err != nil {
+		return comettypes.CommitSig{}, fmt.Errorf("invalid validator address: %w", err)
+	}
+	timestamp, err := termToCometTime(c.Arg(2), env)
+	if err != nil {
+		return comettypes.CommitSig{}, fmt.Errorf("invalid timestamp: %w", err)
+	}
+	signature, err := termToHexOrBytes(ctx, c.Arg(3), env)
+	if err != nil {
+		return comettypes.CommitSig{}, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	return comettypes.CommitSig{BlockIDFlag: flag, ValidatorAddress: address, Timestamp: timestamp, Signature: signature}, nil
+}
+
+// termToCometValidators converts the given list of validator(PubKey, VotingPower) terms into a map of validators
+// indexed by address, along with their total voting power.
+func termToCometValidators(ctx context.Context, validators engine.Term, env *engine.Env,
+) (map[string]*comettypes.Validator, *big.Int, error) {
+	result := make(map[string]*comettypes.Validator)
+	total := new(big.Int)
+
+	iter := engine.ListIterator{List: validators, Env: env}
+	for iter.Next() {
+		c, ok := env.Resolve(iter.Current()).(engine.Compound)
+		if !ok || c.Functor() != AtomValidator || c.Arity() != 2 {
+			return nil, nil, fmt.Errorf("invalid validator type: %T, should be validator(PubKey, VotingPower)", env.Resolve(iter.Current()))
+		}
+		pubKey, err := termToHexOrBytes(ctx, c.Arg(0), env)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid validator public key: %w", err)
+		}
+		if len(pubKey) != ed25519.PubKeySize {
+			return nil, nil, fmt.Errorf("invalid validator public key: should be %d bytes long, given %d", ed25519.PubKeySize, len(pubKey))
+		}
+		power, ok := env.Resolve(c.Arg(1)).(engine.Integer)
+		if !ok || power <= 0 {
+			return nil, nil, fmt.Errorf("invalid validator voting power: %v, should be a positive Integer", env.Resolve(c.Arg(1)))
+		}
+
+		val := comettypes.NewValidator(ed25519.PubKey(pubKey), int64(power))
+		if _, ok := result[string(val.Address)]; ok {
+			return nil, nil, fmt.Errorf("duplicated validator: %X", val.Address.Bytes())
+		}
+		result[string(val.Address)] = val
+		total.Add(total, big.NewInt(val.VotingPower))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, nil, fmt.Errorf("invalid validator set: %w", err)
+	}
+
+	return result, total, nil
+}
+
+// termToTrustLevel converts the given N/D term into the numerator and the denominator of a trust level.
+func termToTrustLevel(trustLevel engine.Term, env *engine.Env) (*big.Int, *big.Int, error) {
+	c, ok := env.Resolve(trustLevel).(engine.Compound)
+	if !ok || c.Functor() != engine.NewAtom("/") || c.Arity() != 2 {
+		return nil, nil, fmt.Errorf("invalid trust level type: %T, should be N/D", env.Resolve(trustLevel))
+	}
+	numerator, okNum := env.Resolve(c.Arg(0)).(engine.Integer)
+	denominator, okDen := env.Resolve(c.Arg(1)).(engine.Integer)
+	if !okNum || !okDen || numerator <= 0 || numerator > denominator {
+		return nil, nil, fmt.Errorf("invalid trust level: %v/%v, should be a fraction of Integers N/D such that 0 < N <= D",
+			env.Resolve(c.Arg(0)), env.Resolve(c.Arg(1)))
+	}
+
+	return big.NewInt(int64(numerator)), big.NewInt(int64(denominator)), nil
+}
+
+// termToHexOrBytes converts the given term, either an hexadecimal Atom or a list of bytes, into bytes.
+func termToHexOrBytes(ctx context.Context, term engine.Term, env *engine.Env) ([]byte, error) {
+	if atom, ok := env.Resolve(term).(engine.Atom); ok && atom != AtomEmptyArray {
+		return TermToBytes(ctx, atom, AtomEncoding.Apply(AtomHex), env)
+	}
+
+	return TermToBytes(ctx, term, AtomEncoding.Apply(AtomOctet), env)
+}
@@ -0,0 +1,554 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestCometHeaderHash(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		program := `header(header([
+  version-version(1, 2),
+  chain_id-chainId,
+  height-3,
+  time-'2019-10-13T16:14:44Z',
+  last_block_id-block_id('0000000000000000000000000000000000000000000000000000000000000000', part_set_header(6, '0000000000000000000000000000000000000000000000000000000000000000')),
+  last_commit_hash-e7aad01a1af897b05bcf78c7563b5d1adc2939d543dac949a5c8712156d19bf8,
+  data_hash-'6d6e28b8b98b5327042ea50a57dd46e6cc851c72e528bdeaa6efdeeefe66a0b8',
+  validators_hash-db5d0767f57d844ba68132eaf74f6b8b83df6c03810a6a4378c2a6b2caf93e8d,
+  next_validators_hash-'1eef9748a3c48ff996033757d73200886e7b2b4e9d9df07b19a34a44bae3e2c8',
+  consensus_hash-e5e566c41ed57e3ff8cc10f184178788b8faa602b07cf1f425217bd8179f1f24,
+  app_hash-'41cafae31cc70f5801fa1016a2dd54a9bcb8201b5b389919fe9976762532c516',
+  last_results_hash-'092e058630247ed6009863a12eee117d26cd9d08b5adcaab37f2ab35db475a37',
+  evidence_hash-'73865db08f49d58428905d389ab4ca4b96e45a3206c7a69d43a5dc7372e60714',
+  proposer_address-'27834082c131975497cdebfbdce6c8e5196a1354'
+])).
+header_hash(Hash) :- header(Header), comet_header_hash(Header, Hash).
+header_hash_hex(Hex) :- header_hash(Hash), hex_bytes(Hex, Hash).`
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				program:     program,
+				query:       `header_hash(Hash).`,
+				wantResult:  []types.TermResults{{"Hash": "[247,64,18,31,85,59,84,24,195,239,189,52,60,45,191,233,224,7,187,103,176,208,32,160,116,19,116,186,182,82,66,164]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `header_hash_hex(Hex).`,
+				wantResult:  []types.TermResults{{"Hex": "f740121f553b5418c3efbd343c2dbfe9e007bb67b0d020a0741374bab65242a4"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `comet_header_hash(header([chain_id-chainId, validators_hash-[219, 93], time-1570983284]), Hash), comet_header_hash(header([validators_hash-db5d, time-'2019-10-13T16:14:44Z', chain_id-chainId]), Hash).`,
+				wantResult:  []types.TermResults{{"Hash": "[75,235,221,136,54,214,89,227,64,3,118,192,133,73,253,186,131,193,16,248,229,48,49,134,95,188,250,213,239,163,158,225]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `comet_header_hash(header([chain_id-chainId]), Hash).`,
+				wantError:   fmt.Errorf("comet_header_hash/2: missing validators_hash field"),
+				wantSuccess: false,
+			},
+			{
+				query:       `comet_header_hash(header([chain-chainId]), Hash).`,
+				wantError:   fmt.Errorf("comet_header_hash/2: unknown header field: chain"),
+				wantSuccess: false,
+			},
+			{
+				query:       `comet_header_hash(header([height-'3']), Hash).`,
+				wantError:   fmt.Errorf("comet_header_hash/2: invalid header field height: should be an Integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `comet_header_hash(header([time-yesterday]), Hash).`,
+				wantError:   fmt.Errorf("comet_header_hash/2: invalid header field time: parsing time \"yesterday\" as \"2006-01-02T15:04:05.999999999Z07:00\": cannot parse \"yesterday\" as \"2006\""),
+				wantSuccess: false,
+			},
+			{
+				query:       `comet_header_hash(header([last_block_id-block_id(aa, part_set_header(-1, aa))]), Hash).`,
+				wantError:   fmt.Errorf("comet_header_hash/2: invalid header field last_block_id: invalid part set header total: -1, should be a 32 bits unsigned Integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `comet_header_hash([chain_id-chainId], Hash).`,
+				wantError:   fmt.Errorf("comet_header_hash/2: invalid header type: engine.list, should be header(Fields)"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("comet_header_hash"), CometHeaderHash)
+						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestCometVerifyCommit(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		program := `:-(op(400, yfx, '/')).
+validators([
+  validator('2866de9a5d64e18294079521b2b26279c0cc8e4428cf312279e32421d3a143eb', 10),
+  validator('63ed5d57a4be7484302282c48c83506f19fb32423f8f0f17eb9a06b2710f9a00', 20),
+  validator('5453f899a6c13a8f12f83ce17bb1f422efadb53c80240644785f10dbe9ed567f', 30)
+]).
+sig(1, commit_sig(commit, '43dd719cdf562e2c59e00d492c02e447ffa022a3', '2023-06-01T10:00:00Z', c8985eb0db06820b3b452ea87336c6bac02fd7b64fc3f94a10afa91691264c100e0e824a7ce3e07df9760306d7d58bc9230984963f3bbe7c14c2e849d5589d01)).
+sig(2, commit_sig(commit, '467dfcd636e6d2eb0158a960ea4890c26e91b161', '2023-06-01T10:00:00Z', '0d30cf57e367e26cc82f8ff279d84d88e83c59db9a6afad85452a95713c395b6d2c1c609a2df55f5bb0cb4f24cc6d52c50501fde2102b5fa1039e649af2f4707')).
+sig(3, commit_sig(commit, '02704b14c9f7f9cf47ba7bb5da4c9df06d5b8ece', '2023-06-01T10:00:00Z', '00514d8407f3bed5c4d2e7f75924886afd7c0e6df3020b5af38382d79938cbf33877998cecbb242b862f74cae298e97c786f6796a1e90a6e403e3e04fbba0000')).
+sig(bad, commit_sig(commit, '02704b14c9f7f9cf47ba7bb5da4c9df06d5b8ece', '2023-06-01T10:00:00Z', c8985eb0db06820b3b452ea87336c6bac02fd7b64fc3f94a10afa91691264c100e0e824a7ce3e07df9760306d7d58bc9230984963f3bbe7c14c2e849d5589d01)).
+sig(absent, commit_sig(absent, [], 0, [])).
+commit(Sigs, commit(42, 0, block_id('496aca80e4d8f29fb8e8cd816c3afb48d3f103970b3a2ee1600c08ca67326dee', part_set_header(1, d887db09649dab0d83951d8d5d69b2e7d8bb70e79daa2a3a279b4fd6b8346cea)), Sigs)).
+verify(ChainID, Names, TrustLevel) :-
+  sigs(Names, Sigs), commit(Sigs, Commit), validators(Validators),
+  comet_verify_commit(ChainID, Commit, Validators, TrustLevel).
+sigs([], []).
+sigs([Name|Names], [Sig|Sigs]) :- sig(Name, Sig), sigs(Names, Sigs).`
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				program:     program,
+				query:       `verify('okp4-test-1', [1, 2, 3], 2/3).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `verify('okp4-test-1', [1, 2, bad], 1/3).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `verify('okp4-test-1', [1, 2, bad], 2/3).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `verify('okp4-test-1', [absent, 2, 3], 2/3).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `verify('okp4-test-1', [1, 3, 3], 2/3).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `verify('okp4-test-2', [1, 2, 3], 1/3).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `verify('okp4-test-1', [1, 2, 3], 1/1).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `verify('okp4-test-1', [1, 2, 3], 3/2).`,
+				wantError:   fmt.Errorf("comet_verify_commit/4: invalid trust level: 3/2, should be a fraction of Integers N/D such that 0 < N <= D"),
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `verify('okp4-test-1', [1, 2, 3], 0.5).`,
+				wantError:   fmt.Errorf("comet_verify_commit/4: invalid trust level type: engine.Float, should be N/D"),
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `commit([commit_sig(yes, [], 0, [])], Commit), comet_verify_commit('okp4-test-1', Commit, [], 1/3).`,
+				wantError:   fmt.Errorf("comet_verify_commit/4: invalid commit signature: invalid flag: yes, valid values are 'absent', 'commit' or 'nil'"),
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `commit([], Commit), comet_verify_commit('okp4-test-1', Commit, [validator('2866de9a5d64e18294079521b2b26279c0cc8e4428cf312279e32421d3a143eb', 0)], 1/3).`,
+				wantError:   fmt.Errorf("comet_verify_commit/4: invalid validator voting power: 0, should be a positive Integer"),
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `commit([], Commit), comet_verify_commit('okp4-test-1', Commit, [validator('2866de9a', 10)], 1/3).`,
+				wantError:   fmt.Errorf("comet_verify_commit/4: invalid validator public key: should be 32 bytes long, given 4"),
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `comet_verify_commit('okp4-test-1', commit(42, 0, []), [], 1/3).`,
+				wantError:   fmt.Errorf("comet_verify_commit/4: invalid commit type: *engine.compound, should be commit(Height, Round, BlockID, Signatures)"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register4(engine.NewAtom("comet_verify_commit"), CometVerifyCommit)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestCanonicalVoteBytes(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		program := `block_id(block_id('496aca80e4d8f29fb8e8cd816c3afb48d3f103970b3a2ee1600c08ca67326dee', part_set_header(1, d887db09649dab0d83951d8d5d69b2e7d8bb70e79daa2a3a279b4fd6b8346cea))).
+vote_hex(Vote, Hex) :- canonical_vote_bytes(Vote, Bytes), hex_bytes(Hex, Bytes).`
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `canonical_vote_bytes(vote('', precommit, 1, 1, nil, '0001-01-01T00:00:00Z'), Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[33,8,2,17,1,0,0,0,0,0,0,0,25,1,0,0,0,0,0,0,0,42,11,8,128,146,184,195,152,254,255,255,255,1]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `block_id(BlockID), vote_hex(vote('okp4-test-1', precommit, 42, 0, BlockID, '2023-06-01T10:00:00Z'), Hex).`,
+				wantResult:  []types.TermResults{{"BlockID": "block_id('496aca80e4d8f29fb8e8cd816c3afb48d3f103970b3a2ee1600c08ca67326dee',part_set_header(1,d887db09649dab0d83951d8d5d69b2e7d8bb70e79daa2a3a279b4fd6b8346cea))", "Hex": "'6a0802112a0000000000000022480a20496aca80e4d8f29fb8e8cd816c3afb48d3f103970b3a2ee1600c08ca67326dee122408011220d887db09649dab0d83951d8d5d69b2e7d8bb70e79daa2a3a279b4fd6b8346cea2a0608a0d8e1a306320b6f6b70342d746573742d31'"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `vote_hex(vote('okp4-test-1', prevote, 42, 1, nil, 1685613600), Hex).`,
+				wantResult:  []types.TermResults{{"Hex": "'290801112a000000000000001901000000000000002a0608a0d8e1a306320b6f6b70342d746573742d31'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `canonical_vote_bytes(vote('okp4-test-1', proposal, 42, 0, nil, 0), Bytes).`,
+				wantError:   fmt.Errorf("canonical_vote_bytes/2: invalid vote type: proposal, valid values are 'prevote' or 'precommit'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `canonical_vote_bytes(vote('okp4-test-1', precommit, -1, 0, nil, 0), Bytes).`,
+				wantError:   fmt.Errorf("canonical_vote_bytes/2: invalid vote height: -1, should be a non-negative Integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `canonical_vote_bytes(vote('okp4-test-1', precommit, 42, 0, [], 0), Bytes).`,
+				wantError:   fmt.Errorf("canonical_vote_bytes/2: invalid vote block id: invalid type: engine.Atom, should be block_id(Hash, PartSetHeader)"),
+				wantSuccess: false,
+			},
+			{
+				query:       `canonical_vote_bytes(vote(precommit, 42, 0, nil, 0), Bytes).`,
+				wantError:   fmt.Errorf("canonical_vote_bytes/2: invalid vote type: *engine.compound, should be vote(ChainID, Type, Height, Round, BlockID, Timestamp)"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("canonical_vote_bytes"), CanonicalVoteBytes)
+						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestTendermintVerify(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		program := `vote(Height, vote('okp4-test-1', precommit, Height, 0, block_id('496aca80e4d8f29fb8e8cd816c3afb48d3f103970b3a2ee1600c08ca67326dee', part_set_header(1, d887db09649dab0d83951d8d5d69b2e7d8bb70e79daa2a3a279b4fd6b8346cea)), '2023-06-01T10:00:00Z')).
+key(ed25519, '934a9d1e98f9e7f2b4bfac1f7bc3785716b0c2b8eda3293f03a8f884f4fe2cf1').
+key(secp256k1, '0332d65fd1c1b007378a1cf621727db89fbd3c8def31a45fb5da90a6f9629cdca0').
+sig(ed25519, cb4abadab581f2432f79865acb5611b527629f7e70f67686bd4c7e317edde50ae29f579ccf944e49457d75d76e1bc2671b79f83788e719d4fd9e1c84699e700b).
+sig(secp256k1, '4b1162d8dc5db166bb224f533000ec52b5ad63b0fa197185499cab1a8d04d4b57435f2f1f1ec4780f8794b13d78a585b286bd0ef1faecb5020dade0b57c7c17c').
+verify(KeyType, SigType, Height, Options) :- key(KeyType, Key), sig(SigType, Sig), vote(Height, Vote), tendermint_verify(Key, Vote, Sig, Options).`
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				program:     program,
+				query:       `verify(ed25519, ed25519, 42, []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `verify(ed25519, ed25519, 42, [type(ed25519)]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `verify(secp256k1, secp256k1, 42, [type(secp256k1)]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `verify(ed25519, ed25519, 43, []).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `verify(secp256k1, secp256k1, 43, [type(secp256k1)]).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `verify(ed25519, secp256k1, 42, []).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `catch(verify(secp256k1, secp256k1, 42, []), E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(public_key,'0332d65fd1c1b007378a1cf621727db89fbd3c8def31a45fb5da90a6f9629cdca0'),/(tendermint_verify,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `catch(verify(ed25519, ed25519, 42, [type(secp256r1)]), E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(algorithm,secp256r1),/(tendermint_verify,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `catch(verify(ed25519, ed25519, 42, [encoding(hex)]), E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(option,encoding(hex)),/(tendermint_verify,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `key(ed25519, Key), sig(ed25519, Sig), tendermint_verify(Key, vote('okp4-test-1', precommit, 42, -1, nil, 0), Sig, []).`,
+				wantError:   fmt.Errorf("tendermint_verify/4: invalid vote round: -1, should be a 32 bits non-negative Integer"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register4(engine.NewAtom("tendermint_verify"), TendermintVerify)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,190 @@
+package predicate
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+// The labels of the COSE header parameters used by cose_sign1_verify/3, as registered by RFC 9052.
+const (
+	coseHeaderAlg  = 1
+	coseHeaderCrit = 2
+)
+
+// coseSign1Tag is the CBOR tag of the COSE_Sign1 structures.
+const coseSign1Tag = 18
+
+// coseAlgs maps the identifiers of the COSE signature algorithms supported by cose_sign1_verify/3 to their algorithm
+// of the signature verification predicates.
+var coseAlgs = map[engine.Integer]util.Alg{
+	-7:  util.Secp256r1,
+	-8:  util.Ed25519,
+	-47: util.Secp256k1,
+}
+
+// COSESign1Verify is a predicate which verifies a CBOR Object Signing and Encryption (COSE) single signer message and
+// unifies its payload.
+//
+// The signature is as follows:
+//
+//	cose_sign1_verify(+Key, +Sign1, -Payload) is semi-det
+//
+// Where:
+//   - Key is the public key to verify the signature with, as for ecdsa_verify/4 or eddsa_verify/4 depending on the
+//     algorithm.
+//   - Sign1 is the COSE_Sign1 structure, tagged or not, as defined by [RFC 9052], given as a list of bytes or as an
+//     hexadecimal Atom.
+//   - Payload is the payload of the structure, as a list of bytes, to be decoded with cbor_bytes/2 when it is a CBOR
+//     document, e.g. the Mobile Security Object of a mobile driving licence (mDL).
+//
+// The signature is verified against the Sig_structure built from the protected header and the payload of Sign1, with
+// an empty external additional authenticated data, using the algorithm given by the alg parameter (label 1) of the
+// protected header. The supported algorithms are:
+//   - ES256 (-7): the ECDSA with P-256 and SHA-256 algorithm.
+//   - ES256K (-47): the ECDSA with secp256k1 and SHA-256 algorithm.
+//   - EdDSA (-8): the EdDSA with Ed25519 algorithm.
+//
+// The predicate fails if the signature is not valid. A malformed structure, a detached payload, a protected header
+// without a supported algorithm or with critical parameters (label 2), and a key not suitable for the algorithm raise
+// an error.
+//
+// Examples:
+//
+//	# Verify a COSE_Sign1 structure signed with the ES256 algorithm, and get its payload.
+//	- cose_sign1_verify([4, 186, 197, ...], 'd28443a10126a10442313154...', Payload).
+//
+//	# Verify a COSE_Sign1 structure signed with the EdDSA algorithm, and decode its CBOR payload.
+//	- cose_sign1_verify([215, 90, 152, ...], Sign1, Bytes), cbor_bytes(Bytes, Payload).
+//
+// [RFC 9052]: https://datatracker.ietf.org/doc/html/rfc9052
+func COSESign1Verify(vm *engine.VM, key, sign1, payload engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "cose_sign1_verify/3"
+
+		encoding := AtomOctet
+		if atom, ok := env.Resolve(sign1).(engine.Atom); ok && atom != util.AtomEmptyList {
+			encoding = AtomHex
+		}
+		data, err := decodeBytes(ctx, sign1, encoding, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		protected, content, signature, err := decodeCOSESign1(ctx, data, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		alg, err := coseSign1Alg(protected, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		// The Sig_structure is ["Signature1", protected, external_aad, payload], the external_aad being empty.
+		toBeSigned := appendCBORHead(nil, cborArray, 4)
+		toBeSigned = append(appendCBORHead(toBeSigned, cborTextString, 10), "Signature1"...)
+		toBeSigned = append(appendCBORHead(toBeSigned, cborByteString, uint64(len(protected))), protected...)
+		toBeSigned = appendCBORHead(toBeSigned, cborByteString, 0)
+		toBeSigned = append(appendCBORHead(toBeSigned, cborByteString, uint64(len(content))), content...)
+		if alg != util.Ed25519 {
+			toBeSigned, signature = jwtDigest(crypto.SHA256, toBeSigned), jwtECDSASignature(signature)
+		}
+
+		valid, err := verifyJWTWithAlg(ctx, functor, alg, key, toBeSigned, signature, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		if !valid {
+			return engine.Bool(false)
+		}
+
+		return engine.Unify(vm, payload, BytesToList(content), cont, env)
+	})
+}
+
+// decodeCOSESign1 splits the given COSE_Sign1 structure into its serialized protected header, its payload and its
+// signature.
+func decodeCOSESign1(ctx context.Context, data []byte, env *engine.Env) ([]byte, []byte, []byte, error) {
+	structure, err := decodeCBOR(data)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid COSE_Sign1 structure: %w", err)
+	}
+	if t, ok := structure.(engine.Compound); ok && t.Functor() == AtomTag && t.Arity() == 2 {
+		if t.Arg(0) != engine.Integer(coseSign1Tag) {
+			return nil, nil, nil, fmt.Errorf("invalid COSE_Sign1 structure: unexpected tag %v", t.Arg(0))
+		}
+		structure = t.Arg(1)
+	}
+
+	elements, err := listElements(structure, env)
+	if err != nil || len(elements) != 4 {
+		return nil, nil, nil, fmt.Errorf("invalid COSE_Sign1 structure: should be an array of 4 elements")
+	}
+	if MakeNull().Compare(elements[2], env) == 0 {
+		return nil, nil, nil, fmt.Errorf("detached payload not supported")
+	}
+
+	parts := make([][]byte, 0, 3)
+	for _, part := range []struct {
+		index int
+		name  string
+	}{{0, "protected header"}, {2, "payload"}, {3, "signature"}} {
+		b, ok := env.Resolve(elements[part.index]).(engine.Compound)
+		if !ok || b.Functor() != AtomBytes || b.Arity() != 1 {
+			return nil, nil, nil, fmt.Errorf("invalid COSE_Sign1 structure: %s should be a byte string", part.name)
+		}
+		bts, err := TermToBytes(ctx, b.Arg(0), AtomEncoding.Apply(AtomOctet), env)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		parts = append(parts, bts)
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
+
+// coseSign1Alg returns the signature algorithm given by the alg parameter of the given serialized protected header,
+// rejecting the headers with critical parameters.
+func coseSign1Alg(protected []byte, env *engine.Env) (util.Alg, error) {
+	header := AtomJSON.Apply(util.AtomEmptyList)
+	if len(protected) > 0 {
+		var err error
+		if header, err = decodeCBOR(protected); err != nil {
+			return "", fmt.Errorf("invalid protected header: %w", err)
+		}
+	}
+	h, ok := header.(engine.Compound)
+	if !ok || h.Functor() != AtomJSON {
+		return "", fmt.Errorf("invalid protected header: should be a map")
+	}
+	pairs, err := listElements(h.Arg(0), env)
+	if err != nil {
+		return "", err
+	}
+
+	var alg engine.Term
+	for _, p := range pairs {
+		pair, ok := p.(engine.Compound)
+		if !ok {
+			continue
+		}
+		switch pair.Arg(0) {
+		case engine.Integer(coseHeaderAlg):
+			alg = pair.Arg(1)
+		case engine.Integer(coseHeaderCrit):
+			return "", fmt.Errorf("critical header parameters not supported")
+		}
+	}
+	if alg == nil {
+		return "", fmt.Errorf("missing alg parameter in protected header")
+	}
+	a, ok := alg.(engine.Integer)
+	if !ok || coseAlgs[a] == "" {
+		return "", fmt.Errorf("unsupported COSE algorithm: %v", alg)
+	}
+	return coseAlgs[a], nil
+}
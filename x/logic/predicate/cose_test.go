@@ -0,0 +1,185 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestCOSESign1Verify(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `hex_bytes('04bac5b11cad8f99f9c72b05cf4b9e26d244dc189f745228255a219a86d6a09eff20138bf82dc1b6d562be0fa54ab7804a3a64b6d72ccfed6b6fb6ed28bbfc117e', K), cose_sign1_verify(K, 'd28443a10126a10442313154546869732069732074686520636f6e74656e742e58408eb33e4ca31d1c465ab05aac34cc6b23d58fef5c083106c4d25a91aef0b0117e2af9a291aa32e14ab834dc56ed2a223444547e01f11d3b0916e5a4c345cacb36', Payload).`,
+				wantResult:  []types.TermResults{{"K": "[4,186,197,177,28,173,143,153,249,199,43,5,207,75,158,38,210,68,220,24,159,116,82,40,37,90,33,154,134,214,160,158,255,32,19,139,248,45,193,182,213,98,190,15,165,74,183,128,74,58,100,182,215,44,207,237,107,111,182,237,40,187,252,17,126]", "Payload": "[84,104,105,115,32,105,115,32,116,104,101,32,99,111,110,116,101,110,116,46]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes('04bac5b11cad8f99f9c72b05cf4b9e26d244dc189f745228255a219a86d6a09eff20138bf82dc1b6d562be0fa54ab7804a3a64b6d72ccfed6b6fb6ed28bbfc117e', K), cose_sign1_verify(K, '8443a10126a10442313154546869732069732074686520636f6e74656e742e58408eb33e4ca31d1c465ab05aac34cc6b23d58fef5c083106c4d25a91aef0b0117e2af9a291aa32e14ab834dc56ed2a223444547e01f11d3b0916e5a4c345cacb36', Payload).`,
+				wantResult:  []types.TermResults{{"K": "[4,186,197,177,28,173,143,153,249,199,43,5,207,75,158,38,210,68,220,24,159,116,82,40,37,90,33,154,134,214,160,158,255,32,19,139,248,45,193,182,213,98,190,15,165,74,183,128,74,58,100,182,215,44,207,237,107,111,182,237,40,187,252,17,126]", "Payload": "[84,104,105,115,32,105,115,32,116,104,101,32,99,111,110,116,101,110,116,46]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes('a5bc7b4b759ddd9df1920a768785c99644fb6ecc744531eee8c79cf279a8f534', K), cose_sign1_verify(K, 'd28443a10127a044a16161f55840fa8aa50dd36fe6e2c165f4c2e6ae31074a02094640daa4ce348aa17c0747ee55222e2ca27b7cd8e138b1fec5a9c3fefdf57b6877fc5d3b953d43a830dbcbc907', Bytes), cbor_bytes(Bytes, Payload).`,
+				wantResult:  []types.TermResults{{"K": "[165,188,123,75,117,157,221,157,241,146,10,118,135,133,201,150,68,251,110,204,116,69,49,238,232,199,156,242,121,168,245,52]", "Bytes": "[161,97,97,245]", "Payload": "json([a- @(true)])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes('a5bc7b4b759ddd9df1920a768785c99644fb6ecc744531eee8c79cf279a8f534', K), cose_sign1_verify(K, [210,132,67,161,1,39,160,68,161,97,97,245,88,64,250,138,165,13,211,111,230,226,193,101,244,194,230,174,49,7,74,2,9,70,64,218,164,206,52,138,161,124,7,71,238,85,34,46,44,162,123,124,216,225,56,177,254,197,169,195,254,253,245,123,104,119,252,93,59,149,61,67,168,48,219,203,201,7], Payload).`,
+				wantResult:  []types.TermResults{{"K": "[165,188,123,75,117,157,221,157,241,146,10,118,135,133,201,150,68,251,110,204,116,69,49,238,232,199,156,242,121,168,245,52]", "Payload": "[161,97,97,245]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes('02d5a704f9cb7a1f5ddb93f886f500e9a771540457d59fca86634540f553b5240d', K), cose_sign1_verify(K, 'd28444a101382ea044a16161f5584082e79ea4815621625e125cd34f21bdd8c5a151baeb5571e580f0bd99e32bd553a62fec8f378002e16a3adec85495458cf92750a2333215a88fde686aeb1ac801', Payload).`,
+				wantResult:  []types.TermResults{{"K": "[2,213,167,4,249,203,122,31,93,219,147,248,134,245,0,233,167,113,84,4,87,213,159,202,134,99,69,64,245,83,181,36,13]", "Payload": "[161,97,97,245]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes('a5bc7b4b759ddd9df1920a768785c99644fb6ecc744531eee8c79cf279a8f534', K), cose_sign1_verify(K, 'd28443a10127a044a16161f55840fa8aa50dd36fe6e2c165f4c2e6ae31074a02094640daa4ce348aa17c0747ee55222e2ca27b7cd8e138b1fec5a9c3fefdf57b6877fc5d3b953d43a830dbcbc908', Payload).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `hex_bytes('02d5a704f9cb7a1f5ddb93f886f500e9a771540457d59fca86634540f553b5240d', K), cose_sign1_verify(K, 'd28443a10126a10442313154546869732069732074686520636f6e74656e742e58408eb33e4ca31d1c465ab05aac34cc6b23d58fef5c083106c4d25a91aef0b0117e2af9a291aa32e14ab834dc56ed2a223444547e01f11d3b0916e5a4c345cacb36', Payload).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `hex_bytes('a5bc7b4b759ddd9df1920a768785c99644fb6ecc744531eee8c79cf279a8f534', K), cose_sign1_verify(K, 'd28443a10127a044a16161f55840fa8aa50dd36fe6e2c165f4c2e6ae31074a02094640daa4ce348aa17c0747ee55222e2ca27b7cd8e138b1fec5a9c3fefdf57b6877fc5d3b953d43a830dbcbc907', [1]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `cose_sign1_verify([], 'd18443a10126a04040', Payload).`,
+				wantError:   fmt.Errorf("cose_sign1_verify/3: invalid COSE_Sign1 structure: unexpected tag 17"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cose_sign1_verify([], 'd28343a10126a040', Payload).`,
+				wantError:   fmt.Errorf("cose_sign1_verify/3: invalid COSE_Sign1 structure: should be an array of 4 elements"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cose_sign1_verify([], 'd28443a10126a0f640', Payload).`,
+				wantError:   fmt.Errorf("cose_sign1_verify/3: detached payload not supported"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cose_sign1_verify([], 'd28443a10126a0616140', Payload).`,
+				wantError:   fmt.Errorf("cose_sign1_verify/3: invalid COSE_Sign1 structure: payload should be a byte string"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cose_sign1_verify([], 'd28440a04040', Payload).`,
+				wantError:   fmt.Errorf("cose_sign1_verify/3: missing alg parameter in protected header"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cose_sign1_verify([], 'd28441f6a04040', Payload).`,
+				wantError:   fmt.Errorf("cose_sign1_verify/3: invalid protected header: should be a map"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cose_sign1_verify([], 'd28446a20126028101a04040', Payload).`,
+				wantError:   fmt.Errorf("cose_sign1_verify/3: critical header parameters not supported"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cose_sign1_verify([], 'd28443a10120a04040', Payload).`,
+				wantError:   fmt.Errorf("cose_sign1_verify/3: unsupported COSE algorithm: -1"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cose_sign1_verify([], 'd28443a10126a040', Payload).`,
+				wantError:   fmt.Errorf("cose_sign1_verify/3: invalid COSE_Sign1 structure: invalid CBOR at offset 8: unexpected end of data"),
+				wantSuccess: false,
+			},
+			{
+				query:       `cose_sign1_verify([1, 2], 'd28443a10126a10442313154546869732069732074686520636f6e74656e742e58408eb33e4ca31d1c465ab05aac34cc6b23d58fef5c083106c4d25a91aef0b0117e2af9a291aa32e14ab834dc56ed2a223444547e01f11d3b0916e5a4c345cacb36', Payload).`,
+				wantError:   fmt.Errorf("cose_sign1_verify/3: failed to verify signature: invalid public key length: 2, expected 33 (compressed) or 65 (uncompressed)"),
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(cose_sign1_verify([], zz, _), error(domain_error(encoding(hex), zz), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("cose_sign1_verify"), COSESign1Verify)
+						interpreter.Register2(engine.NewAtom("cbor_bytes"), CBORBytes)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -2,18 +2,93 @@ package predicate
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"hash"
 	"slices"
 	"strings"
 
+	"github.com/hdevalence/ed25519consensus"
 	"github.com/ichiban/prolog/engine"
+	"github.com/samber/lo"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
 
 	cometcrypto "github.com/cometbft/cometbft/crypto"
 
 	"github.com/okp4/okp4d/x/logic/util"
 )
 
+var (
+	// AtomSig are terms with principal functor sig/3.
+	// It is used to represent an entry of a batch of signatures to verify.
+	AtomSig = engine.NewAtom("sig")
+
+	// AtomResults is the term used to indicate the results option.
+	AtomResults = engine.NewAtom("results")
+
+	// AtomType is the term used to indicate the type option.
+	AtomType = engine.NewAtom("type")
+
+	// AtomHash is the term used to indicate the hash option.
+	AtomHash = engine.NewAtom("hash")
+
+	// AtomNone is the term used to indicate that no hash algorithm is applied.
+	AtomNone = engine.NewAtom("none")
+
+	// AtomSize is the term used to indicate the size option.
+	AtomSize = engine.NewAtom("size")
+
+	// AtomKey is the term used to indicate the key option.
+	AtomKey = engine.NewAtom("key")
+
+	// AtomCompressed is the term used to indicate the compressed form of an ECDSA public key.
+	AtomCompressed = engine.NewAtom("compressed")
+
+	// AtomUncompressed is the term used to indicate the uncompressed form of an ECDSA public key.
+	AtomUncompressed = engine.NewAtom("uncompressed")
+
+	// AtomKeyFormat is the term used to indicate the public key format domain in a domain error.
+	AtomKeyFormat = engine.NewAtom("key_format")
+
+	// AtomReason is the term used to indicate the reason option.
+	AtomReason = engine.NewAtom("reason")
+
+	// AtomBadSignature is the term used to indicate that a signature does not match the data and the public key.
+	AtomBadSignature = engine.NewAtom("bad_signature")
+
+	// AtomMalformedPoint is the term used to indicate that a public key is not a valid point of the curve of the
+	// algorithm.
+	AtomMalformedPoint = engine.NewAtom("malformed_point")
+
+	// AtomWrongLength is the term used to indicate that a public key, or a signature, is not of a length supported by
+	// the algorithm.
+	AtomWrongLength = engine.NewAtom("wrong_length")
+)
+
+// verifyOptions are the options supported by the signature verification predicates.
+var verifyOptions = []engine.Atom{AtomEncoding, AtomType}
+
+// verifyKeySizes are the sizes of the public keys supported by the signature verification algorithms, an ECDSA key
+// being either in compressed or in uncompressed form.
+var verifyKeySizes = map[util.Alg][]int{
+	util.Ed25519:   {ed25519.PublicKeySize},
+	util.Secp256r1: {33, 65},
+	util.Secp256k1: {33, 65},
+}
+
+// verifyHashes are the hash algorithms of the hash option of the signature verification predicates.
+var verifyHashes = map[string]func([]byte) []byte{
+	"sha256": cometcrypto.Sha256,
+	"keccak256": func(data []byte) []byte {
+		hasher := sha3.NewLegacyKeccak256()
+		hasher.Write(data)
+		return hasher.Sum(nil)
+	},
+}
+
 // SHAHash is a predicate that computes the Hash of the given Data.
 //
 // The signature is as follows:
@@ -28,6 +103,8 @@ import (
 // Note: Due to the principles of the hash algorithm (pre-image resistance), this predicate can only compute the hash
 // value from input data, and cannot compute the original input data from the hash value.
 //
+// An unbound Data raises an instantiation_error, and a Data which is not an Atom raises a type_error(atom, Data) error.
+//
 // Examples:
 //
 //	# Compute the hash of the given data and unify it with the given Hash.
@@ -37,11 +114,260 @@ func SHAHash(vm *engine.VM, data, hash engine.Term, cont engine.Cont, env *engin
 		var result []byte
 		switch d := env.Resolve(data).(type) {
 		case engine.Atom:
+			if err := checkInputSize(ctx, len(d.String())); err != nil {
+				return engine.Error(fmt.Errorf("sha_hash/2: %w", err))
+			}
+			if err := consumeAlgorithmGas(ctx, "sha_hash/2", "sha256", len(d.String())); err != nil {
+				return engine.Error(fmt.Errorf("sha_hash/2: %w", err))
+			}
 			result = cometcrypto.Sha256([]byte(d.String()))
 			return engine.Unify(vm, hash, BytesToList(result), cont, env)
 		default:
-			return engine.Error(fmt.Errorf("sha_hash/2: invalid data type: %T, should be Atom", d))
+			return engine.Error(typeError(AtomAtom, data, env))
+		}
+	})
+}
+
+// sha3Hashes are the hash algorithms of sha3_hash/3.
+var sha3Hashes = map[string]func() hash.Hash{
+	"sha3_256": sha3.New256,
+	"sha3_512": sha3.New512,
+}
+
+// SHA3Hash is a predicate that computes the hash of the given Data with a SHA-3 algorithm, as standardized by FIPS 202.
+//
+// The signature is as follows:
+//
+//	sha3_hash(+Data, -Hash, +Options) is det
+//
+// Where:
+//   - Data is the data to hash, either an Atom, whose text is hashed, or a list of bytes.
+//   - Hash is the hash of Data.
+//   - Options are additional configurations for the computation. Supported options include: algorithm(+Alg) which
+//     specifies the SHA-3 algorithm, among sha3_256 (default) and sha3_512, and encoding(+Format) which specifies the
+//     encoding of Hash, either octet (default) for a list of bytes, or hex for an hexadecimal Atom. Any other option
+//     raises a domain_error(option, Option) error.
+//
+// The SHA-3 algorithms differ from the Keccak ones used by Ethereum (see the keccak256 hash option of ecdsa_verify/4)
+// by the padding of the input, so that they give different hashes for the same data.
+//
+// An unbound Data raises an instantiation_error, a Data which is neither an Atom nor a list of bytes a
+// type_error(list, Data) or type_error(byte, Element) error, and an unsupported Alg or Format respectively a
+// domain_error(algorithm, Alg) or domain_error(encoding, Format) error.
+//
+// Examples:
+//
+//	# Compute the SHA3-256 hash of the given data as a list of bytes.
+//	- sha3_hash('Hello OKP4', Hash, []).
+//
+//	# Compute the SHA3-512 hash of the given bytes as an hexadecimal atom.
+//	- sha3_hash([72, 101, 108, 108, 111], Hash, [algorithm(sha3_512), encoding(hex)]).
+func SHA3Hash(vm *engine.VM, data, hash, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "sha3_hash/3"
+
+		if err := util.CheckOptions(options, []engine.Atom{AtomAlgorithm, AtomEncoding}, env); err != nil {
+			return engine.Error(err)
+		}
+		algTerm, err := util.GetOptionWithDefault(AtomAlgorithm, options, engine.NewAtom("sha3_256"), env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		alg, ok := env.Resolve(algTerm).(engine.Atom)
+		if !ok {
+			return engine.Error(typeError(AtomAtom, algTerm, env))
+		}
+		newHash, ok := sha3Hashes[alg.String()]
+		if !ok {
+			return engine.Error(domainError(AtomAlgorithm, alg, env))
+		}
+		encoding, err := util.GetOptionWithDefault(AtomEncoding, options, AtomOctet, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		if enc := env.Resolve(encoding); enc != AtomOctet && enc != AtomHex {
+			return engine.Error(domainError(AtomEncoding, encoding, env))
+		}
+
+		input, err := hashInput(ctx, functor, data, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		if err := consumeAlgorithmGas(ctx, functor, alg.String(), len(input)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		hasher := newHash()
+		hasher.Write(input)
+		digest := hasher.Sum(nil)
+		result := BytesToList(digest)
+		if env.Resolve(encoding) == AtomHex {
+			result = engine.NewAtom(hex.EncodeToString(digest))
 		}
+
+		return engine.Unify(vm, hash, result, cont, env)
+	})
+}
+
+// Blake2b is a predicate that computes the BLAKE2b hash of the given Data, as specified by RFC 7693, with a configurable
+// digest size and an optional key.
+//
+// The signature is as follows:
+//
+//	blake2b(+Data, -Hash, +Options) is det
+//
+// Where:
+//   - Data is the data to hash, either an Atom, whose text is hashed, or a list of bytes.
+//   - Hash is the hash of Data.
+//   - Options are additional configurations for the computation. Supported options include: size(+Bytes) which
+//     specifies the size of Hash, as a number of bytes between 1 and 64 (default 64), key(+Key) which specifies the key
+//     of a keyed hash, as a list of at most 64 bytes (default none), and encoding(+Format) which specifies the encoding
+//     of Hash, either octet (default) for a list of bytes, or hex for an hexadecimal Atom. Any other option raises a
+//     domain_error(option, Option) error.
+//
+// The digest size is part of the parameters of the algorithm: a hash of a given size is not the prefix of a longer hash
+// of the same Data, and BLAKE2b with a size of 32 bytes, also known as BLAKE2b-256, differs from a truncated BLAKE2b-512.
+//
+// An unbound Data, Bytes or Key raises an instantiation_error, a Data or a Key which is neither an Atom nor a list of
+// bytes a type_error(list, Term) or type_error(byte, Element) error, a Bytes which is not an integer a
+// type_error(integer, Bytes) error, and a Bytes out of range, a Key longer than 64 bytes or an unsupported Format
+// respectively a domain_error(size, Bytes), domain_error(key, Key) or domain_error(encoding, Format) error.
+//
+// Examples:
+//
+//	# Compute the 32 bytes BLAKE2b hash of the given data as a list of bytes.
+//	- blake2b('Hello OKP4', Hash, [size(32)]).
+//
+//	# Compute a 16 bytes keyed BLAKE2b hash of the given bytes as an hexadecimal atom.
+//	- blake2b([72, 101, 108, 108, 111], Hash, [size(16), key([1, 2, 3, 4]), encoding(hex)]).
+//
+//nolint:cyclop
+func Blake2b(vm *engine.VM, data, hash, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "blake2b/3"
+
+		if err := util.CheckOptions(options, []engine.Atom{AtomSize, AtomKey, AtomEncoding}, env); err != nil {
+			return engine.Error(err)
+		}
+		sizeTerm, err := util.GetOptionWithDefault(AtomSize, options, engine.Integer(blake2b.Size), env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		size, ok := env.Resolve(sizeTerm).(engine.Integer)
+		if !ok {
+			return engine.Error(typeError(AtomInteger, sizeTerm, env))
+		}
+		if size < 1 || size > blake2b.Size {
+			return engine.Error(domainError(AtomSize, size, env))
+		}
+		var key []byte
+		keyTerm, err := util.GetOption(AtomKey, options, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		if keyTerm != nil {
+			if key, err = decodeBytes(ctx, keyTerm, AtomOctet, env); err != nil {
+				return engine.Error(err)
+			}
+			if len(key) > blake2b.Size {
+				return engine.Error(domainError(AtomKey, keyTerm, env))
+			}
+		}
+		encoding, err := util.GetOptionWithDefault(AtomEncoding, options, AtomOctet, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		if enc := env.Resolve(encoding); enc != AtomOctet && enc != AtomHex {
+			return engine.Error(domainError(AtomEncoding, encoding, env))
+		}
+
+		input, err := hashInput(ctx, functor, data, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		if err := consumeAlgorithmGas(ctx, functor, "blake2b", len(input)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		hasher, err := blake2b.New(int(size), key)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		hasher.Write(input)
+		digest := hasher.Sum(nil)
+		result := BytesToList(digest)
+		if env.Resolve(encoding) == AtomHex {
+			result = engine.NewAtom(hex.EncodeToString(digest))
+		}
+
+		return engine.Unify(vm, hash, result, cont, env)
+	})
+}
+
+// hashInput returns the bytes to hash of the given data, which is either an Atom, whose text is hashed, or a list of
+// bytes, decoded with decodeBytes.
+func hashInput(ctx context.Context, functor string, data engine.Term, env *engine.Env) ([]byte, error) {
+	if d, ok := env.Resolve(data).(engine.Atom); ok && d != util.AtomEmptyList {
+		if err := checkInputSize(ctx, len(d.String())); err != nil {
+			return nil, fmt.Errorf("%s: %w", functor, err)
+		}
+		return []byte(d.String()), nil
+	}
+	return decodeBytes(ctx, data, AtomOctet, env)
+}
+
+// HashEqual is a predicate which compares two hashes, or MACs, in constant time.
+//
+// The signature is as follows:
+//
+//	hash_equal(+A, +B) is semidet
+//
+// Where:
+//   - A is the first hash, either as a list of bytes or as an Atom holding its hexadecimal encoding.
+//   - B is the second hash, of the same type as A.
+//
+// The predicate succeeds if A and B are the same bytes, and fails otherwise, including when they are of different
+// lengths. Unlike ==/2, which stops at the first difference, the comparison takes a time only depending on the lengths
+// of the hashes (see util.ConstantTimeEqual), so that comparing a computed MAC with an expected one does not leak how
+// many of its leading bytes match. The hexadecimal encodings are compared once decoded, whatever their case.
+//
+// An unbound A or B raises an instantiation_error, an A which is neither a list nor an Atom a type_error(list, A)
+// error, and a B which is not of the type of A a type_error(list, B) or type_error(atom, B) error. The other errors are
+// the ones of the decoding of the hashes, as for eddsa_verify/4: a type_error(byte, Element) error for an element of a
+// list which is not a byte, and a domain_error(encoding(hex), Hash) error for an invalid hexadecimal encoding.
+//
+// Examples:
+//
+//	# Check a computed hash against an expected one.
+//	- sha_hash('Hello OKP4', Hash), hash_equal(Hash, [61, 176, ...]).
+//
+//	# Compare two hexadecimal hashes.
+//	- hash_equal('9b038f8ef6918cbb56040dfda401b56b', '9B038F8EF6918CBB56040DFDA401B56B').
+func HashEqual(_ *engine.VM, a, b engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		encoding := AtomOctet
+		if atom, ok := env.Resolve(a).(engine.Atom); ok && atom != util.AtomEmptyList {
+			encoding = AtomHex
+		}
+
+		decodedA, err := decodeBytes(ctx, a, encoding, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		// the empty list is an Atom, which shall not be taken as an hexadecimal encoding.
+		if encoding == AtomHex && env.Resolve(b) == util.AtomEmptyList {
+			return engine.Error(typeError(AtomAtom, b, env))
+		}
+		decodedB, err := decodeBytes(ctx, b, encoding, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		if !util.ConstantTimeEqual(decodedA, decodedB) {
+			return engine.Bool(false)
+		}
+
+		return cont(env)
 	})
 }
 
@@ -52,51 +378,238 @@ func SHAHash(vm *engine.VM, data, hash engine.Term, cont engine.Cont, env *engin
 //	hex_bytes(?Hex, ?Bytes) is det
 //
 // Where:
-//   - Hex is an Atom, string or list of characters in hexadecimal encoding.
+//   - Hex is the hexadecimal encoding of Bytes, as an Atom or as a list of characters or character codes.
 //   - Bytes is the list of numbers between 0 and 255 that represent the sequence of bytes.
 //
+// This predicate is hex_bytes/3 with the default options, giving Hex as an Atom.
+//
+// The predicate raises an instantiation_error if both Hex and Bytes are unbound, a type_error(atom, Hex) error if Hex
+// is neither a text nor unbound, a domain_error(encoding(hex), Hex) error if Hex is not a valid hexadecimal encoding,
+// a type_error(list, Bytes) error if Bytes is neither a list nor unbound, and a type_error(byte, Element) error if an
+// element of Bytes is not a byte. A Hex of odd length, which cannot be decoded into bytes, raises the same domain_error
+// but with the context(hex_bytes/2, 'odd number of hex digits') context, telling it apart from an invalid digit.
+//
 // Examples:
 //
 //	# Convert hexadecimal atom to list of bytes.
 //	- hex_bytes('2c26b46b68ffc68ff99b453c1d3041341342d706483bfa0f98a5e886266e7ae', Bytes).
 func HexBytes(vm *engine.VM, hexa, bts engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return HexBytesWithOptions(vm, hexa, bts, engine.List(), cont, env)
+}
+
+// HexBytesWithOptions is a predicate that unifies hexadecimal encoded bytes to a list of bytes, the hexadecimal
+// encoding being given as a text of the requested type.
+//
+// The signature is as follows:
+//
+//	hex_bytes(?Hex, ?Bytes, +Options) is det
+//
+// Where:
+//   - Hex is the hexadecimal encoding of Bytes, as an Atom or as a list of characters or character codes.
+//   - Bytes is the list of numbers between 0 and 255 that represent the sequence of bytes.
+//   - Options are additional configurations for the conversion. Supported options include: as(+Type) which specifies
+//     the type of Hex when it is computed from Bytes, either atom (default) for an Atom, string for a list of
+//     characters, as the double quoted strings are by default, or codes for a list of character codes. Any other
+//     option raises a domain_error(option, Option) error.
+//
+// Hex is decoded whatever its type when it is given. When both Hex and Bytes are given, Hex is compared with the
+// encoding of Bytes as a text of its own type.
+//
+// The errors are the ones of hex_bytes/2, along with an instantiation_error if Type is unbound, a type_error(atom, Type)
+// error if it is not an Atom, and a domain_error(text_type, Type) error if it is not supported.
+//
+// Examples:
+//
+//	# Convert a list of bytes to the character codes of its hexadecimal encoding.
+//	- hex_bytes(Hex, [44, 38, 180, 107], [as(codes)]).
+//
+//	# Convert the characters of an hexadecimal encoding to a list of bytes.
+//	- hex_bytes("2c26b46b", Bytes, []).
+func HexBytesWithOptions(vm *engine.VM, hexa, bts, options engine.Term, cont engine.Cont, env *engine.Env,
+) *engine.Promise {
 	return engine.Delay(func(ctx context.Context) *engine.Promise {
-		var result []byte
+		if err := util.CheckOptions(options, []engine.Atom{AtomAs}, env); err != nil {
+			return engine.Error(err)
+		}
+		typ, err := textTypeOption(options, env)
+		if err != nil {
+			return engine.Error(err)
+		}
 
-		switch h := env.Resolve(hexa).(type) {
-		case engine.Variable:
-		case engine.Atom:
-			src := []byte(h.String())
-			result = make([]byte, hex.DecodedLen(len(src)))
-			_, err := hex.Decode(result, src)
-			if err != nil {
-				return engine.Error(fmt.Errorf("hex_bytes/2: failed decode hexadecimal %w", err))
+		var result []byte
+		if _, ok := env.Resolve(hexa).(engine.Variable); !ok {
+			text, textType, ok := typedText(hexa, typ, env)
+			if !ok {
+				return engine.Error(typeError(AtomAtom, hexa, env))
 			}
-		default:
-			return engine.Error(fmt.Errorf("hex_bytes/2: invalid hex type: %T, should be Atom or Variable", h))
+			culprit := hexa
+			if a, ok := env.Resolve(hexa).(engine.Atom); !ok || a == util.AtomEmptyList {
+				culprit = engine.NewAtom(text)
+			}
+			if result, err = decodeBytes(ctx, culprit, AtomHex, env); err != nil {
+				return engine.Error(err)
+			}
+			typ = textType
 		}
 
-		switch b := env.Resolve(bts).(type) {
-		case engine.Variable:
+		if _, ok := env.Resolve(bts).(engine.Variable); ok {
 			if result == nil {
-				return engine.Error(fmt.Errorf("hex_bytes/2: nil hexadecimal conversion in input"))
+				return engine.Error(engine.InstantiationError(env))
 			}
 			return engine.Unify(vm, bts, BytesToList(result), cont, env)
-		case engine.Compound:
-			if b.Arity() != 2 || b.Functor().String() != "." {
-				return engine.Error(fmt.Errorf("hex_bytes/2: bytes should be a List, give %T", b))
-			}
-			iter := engine.ListIterator{List: b, Env: env}
+		}
+
+		src, err := decodeBytes(ctx, bts, AtomOctet, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		return engine.Unify(vm, hexa, textToTerm(hex.EncodeToString(src), typ), cont, env)
+	})
+}
+
+// BytesHex is a predicate that unifies a list of bytes to its hexadecimal encoding.
+//
+// The signature is as follows:
+//
+//	bytes_hex(+Bytes, -Hex) is det
+//
+// Where:
+//   - Bytes is the list of numbers between 0 and 255 that represent the sequence of bytes.
+//   - Hex is the Atom holding the lower case hexadecimal encoding of Bytes.
+//
+// This predicate is the deterministic counterpart of hex_bytes/2 when converting from bytes to hexadecimal. An unbound
+// Bytes, or an unbound element of it, raises an instantiation_error, a Bytes which is not a list a type_error(list,
+// Bytes), and an element which is not a byte a type_error(byte, Element).
+//
+// Examples:
+//
+//	# Convert a list of bytes to an hexadecimal atom.
+//	- bytes_hex([44, 38, 180, 107], Hex).
+func BytesHex(vm *engine.VM, bts, hexa engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		src, err := decodeBytes(ctx, bts, AtomOctet, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		return engine.Unify(vm, hexa, util.StringToTerm(hex.EncodeToString(src)), cont, env)
+	})
+}
+
+// HexBytesAtom is a predicate that unifies an hexadecimal encoded atom to its list of bytes.
+//
+// The signature is as follows:
+//
+//	hex_bytes_atom(+Hex, -Bytes) is det
+//
+// Where:
+//   - Hex is the Atom holding the hexadecimal encoding, either in lower or upper case.
+//   - Bytes is the list of numbers between 0 and 255 that represent the sequence of bytes.
+//
+// This predicate is the deterministic counterpart of hex_bytes/2 when converting from hexadecimal to bytes. An unbound
+// Hex raises an instantiation_error, a Hex which is not an Atom a type_error(atom, Hex), and a Hex which is not a valid
+// hexadecimal encoding a domain_error(encoding(hex), Hex).
+//
+// Examples:
+//
+//	# Convert an hexadecimal atom to a list of bytes.
+//	- hex_bytes_atom('2c26b46b', Bytes).
+func HexBytesAtom(vm *engine.VM, hexa, bts engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		decoded, err := decodeBytes(ctx, hexa, AtomHex, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		return engine.Unify(vm, bts, BytesToList(decoded), cont, env)
+	})
+}
+
+// Base64URLBytes is a predicate that unifies base64url encoded bytes to a list of bytes.
+//
+// The signature is as follows:
+//
+//	base64url_bytes(?Base64URL, ?Bytes) is det
+//
+// Where:
+//   - Base64URL is the base64url encoding of Bytes, i.e. the base64 encoding with the URL and filename safe alphabet,
+//     without padding, as specified by RFC 4648 and used by the segments of JWTs, as an Atom or as a list of characters
+//     or character codes.
+//   - Bytes is the list of numbers between 0 and 255 that represent the sequence of bytes.
+//
+// This predicate is base64url_bytes/3 with the default options, giving Base64URL as an Atom.
+//
+// A Base64URL holding a character out of the alphabet, including the = padding character, raises an error giving the
+// position of the offending character.
+//
+// Examples:
+//
+//	# Decode the header of a JWT.
+//	- base64url_bytes('eyJhbGciOiJFZERTQSJ9', Bytes).
+func Base64URLBytes(vm *engine.VM, b64, bts engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return base64URLBytes(vm, "base64url_bytes/2", b64, bts, engine.List(), cont, env)
+}
+
+// Base64URLBytesWithOptions is a predicate that unifies base64url encoded bytes to a list of bytes, the base64url
+// encoding being given as a text of the requested type.
+//
+// The signature is as follows:
+//
+//	base64url_bytes(?Base64URL, ?Bytes, +Options) is det
+//
+// Where:
+//   - Base64URL is the base64url encoding of Bytes, as an Atom or as a list of characters or character codes (see
+//     base64url_bytes/2).
+//   - Bytes is the list of numbers between 0 and 255 that represent the sequence of bytes.
+//   - Options are additional configurations for the conversion. Supported options include: as(+Type) which specifies
+//     the type of Base64URL when it is computed from Bytes, either atom (default) for an Atom, string for a list of
+//     characters, as the double quoted strings are by default, or codes for a list of character codes. Any other
+//     option raises a domain_error(option, Option) error.
+//
+// Base64URL is decoded whatever its type when it is given, as hex_bytes/3 does for the hexadecimal encoding.
+//
+// Examples:
+//
+//	# Encode a list of bytes as the characters of its base64url encoding, e.g. to parse it with a DCG.
+//	- base64url_bytes(B64, [104, 105, 63], [as(string)]).
+func Base64URLBytesWithOptions(vm *engine.VM, b64, bts, options engine.Term, cont engine.Cont, env *engine.Env,
+) *engine.Promise {
+	return base64URLBytes(vm, "base64url_bytes/3", b64, bts, options, cont, env)
+}
 
-			src, err := ListToBytes(iter, env)
+// base64URLBytes implements base64url_bytes/2 and base64url_bytes/3, raising the errors of the given predicate.
+func base64URLBytes(vm *engine.VM, functor string, b64, bts, options engine.Term, cont engine.Cont, env *engine.Env,
+) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		if err := util.CheckOptions(options, []engine.Atom{AtomAs}, env); err != nil {
+			return engine.Error(err)
+		}
+		typ, err := textTypeOption(options, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		if _, ok := env.Resolve(b64).(engine.Variable); !ok {
+			text, _, ok := typedText(b64, typ, env)
+			if !ok {
+				return engine.Error(fmt.Errorf("%s: invalid base64url type: %T, should be Atom, list of characters or codes, or Variable",
+					functor, env.Resolve(b64)))
+			}
+			if err := checkInputSize(ctx, base64.RawURLEncoding.DecodedLen(len(text))); err != nil {
+				return engine.Error(fmt.Errorf("%s: %w", functor, err))
+			}
+			decoded, err := base64.RawURLEncoding.DecodeString(text)
 			if err != nil {
-				return engine.Error(fmt.Errorf("hex_bytes/2: failed convert list into bytes: %w", err))
+				return engine.Error(fmt.Errorf("%s: failed decode base64url: %w", functor, err))
 			}
-			dst := hex.EncodeToString(src)
-			return engine.Unify(vm, hexa, util.StringToTerm(dst), cont, env)
-		default:
-			return engine.Error(fmt.Errorf("hex_bytes/2: invalid hex type: %T, should be Variable or List", b))
+			return engine.Unify(vm, bts, BytesToList(decoded), cont, env)
 		}
+
+		src, err := TermToBytes(ctx, bts, AtomEncoding.Apply(AtomOctet), env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: failed convert list into bytes: %w", functor, err))
+		}
+		return engine.Unify(vm, b64, textToTerm(base64.RawURLEncoding.EncodeToString(src), typ), cont, env)
 	})
 }
 
@@ -108,23 +621,37 @@ func HexBytes(vm *engine.VM, hexa, bts engine.Term, cont engine.Cont, env *engin
 //	eddsa_verify(+PubKey, +Data, +Signature, +Options) is semi-det
 //
 // Where:
-//   - PubKey is the encoded public key as a list of bytes.
+//   - PubKey is the encoded public key as a list of bytes, or as an atom in the encoding given by the encoding option.
 //   - Data is the message to verify, represented as either a hexadecimal atom or a list of bytes.
 //     It's important that the message isn't pre-hashed since the Ed25519 algorithm processes
 //     messages in two passes when signing.
-//   - Signature represents the signature corresponding to the data, provided as a list of bytes.
+//   - Signature represents the signature corresponding to the data, provided as a list of bytes, or as an atom in the
+//     encoding given by the encoding option.
 //   - Options are additional configurations for the verification process. Supported options include:
-//     encoding(+Format) which specifies the encoding used for the Data, and type(+Alg) which chooses the algorithm
-//     within the EdDSA family (see below for details).
+//     encoding(+Format) which specifies the encoding used for the Data, and for the PubKey and the Signature given as
+//     atoms, type(+Alg) which chooses the algorithm
+//     within the EdDSA family (see below for details), hash(+Hash) which, as for ecdsa_verify/4, hashes the Data
+//     before the verification (none by default), and reason(-Reason) which explains the outcome of the verification,
+//     as for ecdsa_verify/4.
 //
 // For Format, the supported encodings are:
 //
 //   - hex (default), the hexadecimal encoding represented as an atom.
 //   - octet, the plain byte encoding depicted as a list of integers ranging from 0 to 255.
+//   - base64, the standard base64 encoding, with padding, as specified by RFC 4648, represented as an atom.
+//   - base58, the base58 encoding with the Bitcoin alphabet, represented as an atom.
 //
 // For Alg, the supported algorithms are:
 //
-//   - ed25519 (default): The EdDSA signature scheme using SHA-512 (SHA-2) and Curve25519.
+//   - ed25519 (default): The EdDSA signature scheme using SHA-512 (SHA-2) and Curve25519, the signatures being
+//     validated according to the criteria of ZIP 215 (https://zips.z.cash/zip-0215), as for the consensus.
+//
+// An unknown or malformed option, e.g. a misspelled one, raises a domain_error(option, Option) error instead of being
+// ignored. The other errors are ISO errors as well, so that they can be caught with catch/3: an unbound argument raises
+// an instantiation_error, an argument not of the expected type a type_error(list, Arg), type_error(atom, Arg) or
+// type_error(byte, Element) error, and an unsupported Format or Alg, a Data not valid in its encoding and a PubKey not
+// valid for the algorithm respectively a domain_error(encoding, Format), domain_error(algorithm, Alg),
+// domain_error(encoding(Format), Data) and domain_error(public_key, PubKey) error.
 //
 // Examples:
 //
@@ -133,8 +660,112 @@ func HexBytes(vm *engine.VM, hexa, bts engine.Term, cont engine.Cont, env *engin
 //
 //	# Verify a signature for binary data.
 //	- eddsa_verify([127, ...], [56, 90, ..], [23, 56, ...], [encoding(octet), type(ed25519)])
-func EDDSAVerify(_ *engine.VM, key, data, sig, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
-	return xVerify("eddsa_verify/4", key, data, sig, options, util.Ed25519, []util.Alg{util.Ed25519}, cont, env)
+func EDDSAVerify(vm *engine.VM, key, data, sig, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return xVerify(vm, "eddsa_verify/4", key, data, sig, options, util.Ed25519, []util.Alg{util.Ed25519}, cont, env)
+}
+
+// EDDSAVerifyBatch determines if all the signatures of a batch are valid as per the EdDSA algorithm, each for the
+// provided data and using the specified public key.
+//
+// The signature is as follows:
+//
+//	eddsa_verify_batch(+Triples, +Options) is semi-det
+//
+// Where:
+//   - Triples is the list of sig(PubKey, Data, Signature) terms to verify, where PubKey, Data and Signature are given
+//     as for eddsa_verify/4.
+//   - Options are additional configurations for the verification process. Supported options include the ones of
+//     eddsa_verify/4, i.e. encoding(+Format) which specifies the encoding used for all the Data, and for the PubKey and
+//     the Signature given as atoms, and type(+Alg) which chooses the algorithm within the EdDSA family, and
+//     results(-Results) whose Results is unified with the list of the outcomes of the verification of each entry, in
+//     order, as true or false.
+//
+// Without the results option, the predicate succeeds if and only if all the signatures are valid, and fails
+// otherwise. With the results option, the predicate succeeds whatever the outcome of the verifications, whose
+// details are given by Results. The verification of an entry is as costly as a call to eddsa_verify/4, and raises the
+// same errors. Any other option raises a domain_error(option, Option) error.
+//
+// The signatures are verified at once by a batch verification, following the validation criteria of [ZIP 215] as
+// eddsa_verify/4 does, so that a batch is valid if and only if each of its signatures is. When the batch is not valid
+// and the results option is given, the signatures are then verified one by one to give their outcomes.
+//
+// Examples:
+//
+//	# Verify a batch of signatures for given hexadecimal data.
+//	- eddsa_verify_batch([sig([127, ...], '9b038f8ef6918cbb56040dfda401b56b...', [23, 56, ...]), ...], [encoding(hex)])
+//
+//	# Get the outcome of the verification of each signature of a batch.
+//	- eddsa_verify_batch([sig([127, ...], [56, 90, ..], [23, 56, ...]), ...], [encoding(octet), results(Results)])
+//
+// [ZIP 215]: https://zips.z.cash/zip-0215
+func EDDSAVerifyBatch(vm *engine.VM, triples, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "eddsa_verify_batch/2"
+
+		if err := util.CheckOptions(options, append(verifyOptions, AtomResults), env); err != nil {
+			return engine.Error(err)
+		}
+		alg, err := verifyAlgorithm(options, util.Ed25519, []util.Alg{util.Ed25519}, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		results, err := util.GetOption(AtomResults, options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		type batchEntry struct{ key, data, signature []byte }
+		entries := make([]batchEntry, 0)
+		batch := ed25519consensus.NewBatchVerifier()
+		iter := engine.ListIterator{List: triples, Env: env}
+		for iter.Next() {
+			entry, ok := env.Resolve(iter.Current()).(engine.Compound)
+			if !ok || entry.Functor() != AtomSig || entry.Arity() != 3 {
+				return engine.Error(fmt.Errorf("%s: invalid entry type: %T, should be sig(PubKey, Data, Signature)",
+					functor, env.Resolve(iter.Current())))
+			}
+
+			decodedKey, decodedData, decodedSignature, err := decodeVerifyInputs(ctx, entry.Arg(0), entry.Arg(1), entry.Arg(2), options, env)
+			if err != nil {
+				return engine.Error(err)
+			}
+			if err := consumeAlgorithmGas(ctx, functor, alg.String(), len(decodedData)); err != nil {
+				return engine.Error(fmt.Errorf("%s: %w", functor, err))
+			}
+			if len(decodedKey) != ed25519.PublicKeySize {
+				return engine.Error(domainError(AtomPublicKey, entry.Arg(0), env))
+			}
+
+			batch.Add(decodedKey, decodedData, decodedSignature)
+			entries = append(entries, batchEntry{decodedKey, decodedData, decodedSignature})
+		}
+		if err := iter.Err(); err != nil {
+			return engine.Error(fmt.Errorf("%s: invalid entries: %w", functor, err))
+		}
+
+		// The batch verification does not tell which entries are invalid, which are then verified one by one to give
+		// the outcomes of the verifications, if requested.
+		valid := len(entries) == 0 || batch.Verify()
+		outcomes := make([]engine.Term, 0, len(entries))
+		for _, entry := range entries {
+			r := valid
+			if !valid && results != nil {
+				if r, err = util.VerifySignature(alg, entry.key, entry.data, entry.signature); err != nil {
+					return engine.Error(fmt.Errorf("%s: %w", functor, err))
+				}
+			}
+			outcomes = append(outcomes, lo.Ternary(r, AtomTrue, AtomFalse))
+		}
+
+		if results != nil {
+			return engine.Unify(vm, results, engine.List(outcomes...), cont, env)
+		}
+		if !valid {
+			return engine.Bool(false)
+		}
+
+		return cont(env)
+	})
 }
 
 // ECDSAVerify determines if a given signature is valid as per the ECDSA algorithm for the provided data, using the
@@ -146,26 +777,62 @@ func EDDSAVerify(_ *engine.VM, key, data, sig, options engine.Term, cont engine.
 //
 // Where:
 //
-//   - PubKey is the 33-byte compressed public key, as specified in section 4.3.6 of ANSI X9.62.
+//   - PubKey is the public key, either in the 33-byte compressed form or in the 65-byte uncompressed form, as
+//     specified in section 4.3.6 of ANSI X9.62. The form is detected from the length and the prefix of the key. A key
+//     which is not a point of the prime order subgroup of the curve is rejected with a domain_error(public_key, PubKey)
+//     error, whatever the signature. It is given as a list of bytes, or as an atom in the encoding given by the encoding
+//     option.
 //
-//   - Data is the hash of the signed message, which can be either an atom or a list of bytes.
+//   - Data is the hash of the signed message, which can be either an atom or a list of bytes, or the signed message
+//     itself when the hash option is given.
 //
-//   - Signature represents the ASN.1 encoded signature corresponding to the Data.
+//   - Signature represents the ASN.1 encoded signature corresponding to the Data, given as for PubKey.
 //
 //   - Options are additional configurations for the verification process. Supported options include:
-//     encoding(+Format) which specifies the encoding used for the data, and type(+Alg) which chooses the algorithm
-//     within the ECDSA family (see below for details).
+//     encoding(+Format) which specifies the encoding used for the data, and for the PubKey and the Signature given as
+//     atoms, type(+Alg) which chooses the algorithm
+//     within the ECDSA family, hash(+Hash) which specifies how the data is hashed before the verification, and
+//     reason(-Reason) which explains the outcome of the verification (see below for details).
 //
 // For Format, the supported encodings are:
 //
 //   - hex (default), the hexadecimal encoding represented as an atom.
 //   - octet, the plain byte encoding depicted as a list of integers ranging from 0 to 255.
+//   - base64, the standard base64 encoding, with padding, as specified by RFC 4648, represented as an atom.
+//   - base58, the base58 encoding with the Bitcoin alphabet, represented as an atom.
 //
 // For Alg, the supported algorithms are:
 //
 //   - secp256r1 (default): Also known as P-256 and prime256v1.
 //   - secp256k1: The Koblitz elliptic curve used in Bitcoin's public-key cryptography.
 //
+// For Hash, the supported algorithms are:
+//
+//   - none (default): the data is the hash of the message, and is verified as is.
+//   - sha256: the data is the message, whose SHA-256 hash is verified.
+//   - keccak256: the data is the message, whose Keccak-256 hash is verified, as in the Ethereum flows.
+//
+// The encoding option applies to the data as given, i.e. to the message itself when it is hashed by the predicate:
+// the data is first decoded according to Format, then hashed according to Hash. Hashing the data is as costly as
+// calling the hash algorithm on it.
+//
+// As for eddsa_verify/4, an unknown or malformed option raises a domain_error(option, Option) error, and the other
+// errors are raised as ISO errors.
+//
+// Without the reason option, the predicate fails if the signature is not valid, and raises a
+// domain_error(public_key, PubKey) error for an invalid key. With the reason option, the predicate succeeds whatever
+// the outcome of the verification, and Reason tells why a verification failed, as signed_token_verify/4 does. Reason is
+// unified with:
+//
+//   - valid: the signature is valid.
+//   - bad_signature: the signature does not match the data and the public key, including a malformed signature.
+//   - malformed_point: the public key is of a supported length but is not a valid key of the algorithm, e.g. an ECDSA
+//     key with an invalid prefix or which is not a point of the prime order subgroup of the curve.
+//   - wrong_length: the public key, or the signature of an EdDSA algorithm, is not of a length supported by the
+//     algorithm.
+//
+// The malformed inputs, e.g. a PubKey which is not a list of bytes, still raise an error.
+//
 // Examples:
 //
 //	# Verify a signature for hexadecimal data using the ECDSA secp256r1 algorithm.
@@ -173,52 +840,317 @@ func EDDSAVerify(_ *engine.VM, key, data, sig, options engine.Term, cont engine.
 //
 //	# Verify a signature for binary data using the ECDSA secp256k1 algorithm.
 //	- ecdsa_verify([127, ...], [56, 90, ..], [23, 56, ...], [encoding(octet), type(secp256k1)])
-func ECDSAVerify(_ *engine.VM, key, data, sig, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
-	return xVerify("ecdsa_verify/4", key, data, sig, options, util.Secp256r1, []util.Alg{util.Secp256r1, util.Secp256k1}, cont, env)
+//
+//	# Verify a signature for a raw message hashed with Keccak-256.
+//	- ecdsa_verify([127, ...], [104, 101, 108, 108, 111], [23, 56, ...], [encoding(octet), type(secp256k1), hash(keccak256)])
+//
+//	# Tell why the verification of a signature fails.
+//	- ecdsa_verify([127, ...], [56, 90, ..], [23, 56, ...], [encoding(octet), reason(Reason)])
+func ECDSAVerify(vm *engine.VM, key, data, sig, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return xVerify(vm, "ecdsa_verify/4", key, data, sig, options, util.Secp256r1, []util.Alg{util.Secp256r1, util.Secp256k1}, cont, env)
+}
+
+// VerifyAny determines if a given signature is valid for the provided data using any of the given candidate public
+// keys, and unifies the first matching key.
+//
+// The signature is as follows:
+//
+//	verify_any(+Algorithm, +PubKeys, +Data, +Signature, -MatchedKey) is semi-det
+//
+// Where:
+//   - Algorithm is the signature algorithm to use, supported algorithms are the ones of eddsa_verify/4 and
+//     ecdsa_verify/4, that is: ed25519, secp256r1 and secp256k1.
+//   - PubKeys is the list of the candidate public keys, each one being encoded as a list of bytes.
+//   - Data is the data to verify, as a list of bytes. As for eddsa_verify/4 and ecdsa_verify/4, it's the message itself
+//     for the EdDSA algorithms and the hash of the message for the ECDSA ones.
+//   - Signature is the signature of the Data, as a list of bytes.
+//   - MatchedKey is the first candidate public key, in the order of PubKeys, for which the signature is valid.
+//
+// The candidate keys are tried in turn, and the predicate fails if none of them verifies the signature.
+//
+// For recoverable schemes, the public key is recovered from the signature instead of trying each candidate: this is
+// the case for the secp256k1 algorithm when given a 65-byte signature in the [R || S || V] form, where V is the
+// recovery id. The recovered key is then looked up among the candidates, which may be given in compressed or
+// uncompressed form. A signature from which no key can be recovered makes the predicate fail, whereas an invalid
+// recovery id raises a domain_error(signature, Signature) error.
+//
+// The errors are ISO errors, so that they can be caught with catch/3: an unbound argument raises an
+// instantiation_error, an argument not of the expected type a type_error(atom, Algorithm), type_error(list, Arg) or
+// type_error(byte, Element) error, and an unsupported Algorithm and a candidate key not valid for the algorithm
+// respectively a domain_error(algorithm, Algorithm) and domain_error(public_key, PubKey) error.
+//
+// Examples:
+//
+//	# Verify a signature against several candidate keys, allowing key rotation.
+//	- verify_any(ed25519, [[127, ...], [53, 22, ...]], [56, 90, ..], [23, 56, ...], MatchedKey).
+func VerifyAny(vm *engine.VM, alg, keys, data, sig, matched engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		algos := []util.Alg{util.Ed25519, util.Secp256r1, util.Secp256k1}
+		algAtom, ok := env.Resolve(alg).(engine.Atom)
+		if !ok {
+			return engine.Error(typeError(AtomAtom, alg, env))
+		}
+		if idx := slices.IndexFunc(algos, func(a util.Alg) bool { return a.String() == algAtom.String() }); idx == -1 {
+			return engine.Error(domainError(AtomAlgorithm, algAtom, env))
+		}
+		algo := util.Alg(algAtom.String())
+
+		decodedData, err := decodeBytes(ctx, data, AtomOctet, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		decodedSignature, err := decodeBytes(ctx, sig, AtomOctet, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		candidates := make([]lo.Tuple2[engine.Term, []byte], 0)
+		iter := engine.ListIterator{List: keys, Env: env}
+		for iter.Next() {
+			decodedKey, err := decodeBytes(ctx, iter.Current(), AtomOctet, env)
+			if err != nil {
+				return engine.Error(err)
+			}
+			candidates = append(candidates, lo.T2(iter.Current(), decodedKey))
+		}
+		if err := iter.Err(); err != nil {
+			return engine.Error(typeError(util.AtomList, keys, env))
+		}
+
+		if algo == util.Secp256k1 && len(decodedSignature) == 65 {
+			if v := decodedSignature[64]; v != 0 && v != 1 && v != 27 && v != 28 {
+				return engine.Error(domainError(AtomSignature, sig, env))
+			}
+			recoveredKey, err := util.RecoverPublicKey(algo, decodedData, decodedSignature)
+			if err != nil {
+				return engine.Bool(false)
+			}
+
+			for _, candidate := range candidates {
+				// The candidate keys may be given in compressed or uncompressed form, whereas the recovered key is in
+				// compressed form.
+				key, err := util.NormalizePublicKey(algo, candidate.B, true)
+				if err != nil {
+					return engine.Error(domainError(AtomPublicKey, candidate.A, env))
+				}
+				if util.ConstantTimeEqual(key, recoveredKey) {
+					return engine.Unify(vm, matched, candidate.A, cont, env)
+				}
+			}
+			return engine.Bool(false)
+		}
+
+		for _, candidate := range candidates {
+			r, err := util.VerifySignature(algo, candidate.B, decodedData, decodedSignature)
+			if err != nil {
+				return engine.Error(domainError(AtomPublicKey, candidate.A, env))
+			}
+			if r {
+				return engine.Unify(vm, matched, candidate.A, cont, env)
+			}
+		}
+
+		return engine.Bool(false)
+	})
+}
+
+// EthVerifyAddress is a predicate which verifies that a recoverable secp256k1 signature has been produced by the
+// holder of a given Ethereum address.
+//
+// The signature is as follows:
+//
+//	eth_verify_address(+Hash, +Signature, +ExpectedAddress) is semi-det
+//
+// Where:
+//   - Hash is the 32-byte hash of the signed message, as a list of bytes, e.g. the Keccak-256 hash of the message
+//     prefixed as specified by EIP-191 for the personal_sign method.
+//   - Signature is the 65-byte signature of Hash, as a list of bytes in the [R || S || V] form, where V is the
+//     recovery id (either 0, 1, 27 or 28).
+//   - ExpectedAddress is the Ethereum address of the expected signer, as an Atom holding the 0x prefixed hexadecimal
+//     encoding of the 20-byte address, either all lower case, all upper case or with the mixed case checksum of
+//     EIP-55.
+//
+// The public key is recovered from the signature, and the predicate succeeds if the Ethereum address derived from it,
+// i.e. the last 20 bytes of the Keccak-256 hash of the uncompressed public key, is ExpectedAddress. It fails if it is
+// another address, whereas a malformed hash, signature or address, including an address with an invalid EIP-55
+// checksum, raises an error.
+//
+// Examples:
+//
+//	# Verify the signer of a message.
+//	- eth_verify_address([56, 90, ..], [23, 56, ...], '0x2c7536E3605D9C16a7a3D7b1898e529396a65c23').
+func EthVerifyAddress(_ *engine.VM, hash, sig, address engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		decodedHash, err := TermToBytes(ctx, hash, AtomEncoding.Apply(AtomOctet), env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("eth_verify_address/3: failed to decode hash: %w", err))
+		}
+		if len(decodedHash) != 32 {
+			return engine.Error(fmt.Errorf("eth_verify_address/3: invalid hash length: %d, expected 32", len(decodedHash)))
+		}
+
+		decodedSignature, err := TermToBytes(ctx, sig, AtomEncoding.Apply(AtomOctet), env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("eth_verify_address/3: failed to decode signature: %w", err))
+		}
+
+		expected, err := termToEthereumAddress(address, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("eth_verify_address/3: %w", err))
+		}
+
+		recoveredKey, err := util.RecoverPublicKey(util.Secp256k1, decodedHash, decodedSignature)
+		if err != nil {
+			return engine.Error(fmt.Errorf("eth_verify_address/3: failed to recover public key: %w", err))
+		}
+		recovered, err := util.EthereumAddress(recoveredKey)
+		if err != nil {
+			return engine.Error(fmt.Errorf("eth_verify_address/3: %w", err))
+		}
+
+		if !util.ConstantTimeEqual(recovered, expected) {
+			return engine.Bool(false)
+		}
+
+		return cont(env)
+	})
+}
+
+// PubkeyNormalize is a predicate which converts an ECDSA public key between its compressed and uncompressed forms.
+//
+// The signature is as follows:
+//
+//	pubkey_normalize(+In, -Out, +Options) is det
+//
+// Where:
+//   - In is the public key, as a list of bytes, either in the 33-byte compressed form or in the 65-byte uncompressed
+//     form, as specified in section 4.3.6 of ANSI X9.62. The form is detected from the length and the prefix of the
+//     key, as for ecdsa_verify/4.
+//   - Out is the public key In, as a list of bytes, in the form given by the format option.
+//   - Options are additional configurations for the conversion. Supported options include: type(+Alg) which specifies
+//     the curve of the key, either secp256r1 (default) or secp256k1, as for ecdsa_verify/4, and format(+Format) which
+//     specifies the form of Out, either compressed (default) or uncompressed. Any other option raises a
+//     domain_error(option, Option) error.
+//
+// The key is converted whatever its form, so that Out is the same for the two forms of a key, e.g. to compare keys or
+// to derive an address from a key given in any form. As it is decoded as for the verification of a signature, a key
+// which is not a point of the prime order subgroup of the curve raises a domain_error(public_key, In) error.
+//
+// An unbound In, Alg or Format raises an instantiation_error, an In which is not a list of bytes a type_error(list, In)
+// or type_error(byte, Element) error, an Alg or a Format which is not an Atom a type_error(atom, Term) error, an
+// unsupported Alg a domain_error(algorithm, Alg) error and an unsupported Format a domain_error(key_format, Format)
+// error.
+//
+// Examples:
+//
+//	# Compress a secp256k1 public key.
+//	- pubkey_normalize([4, 142, ...], Key, type(secp256k1)).
+//
+//	# Decompress a secp256r1 public key.
+//	- pubkey_normalize([3, 127, ...], Key, format(uncompressed)).
+func PubkeyNormalize(vm *engine.VM, in, out, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "pubkey_normalize/3"
+
+		if err := util.CheckOptions(options, []engine.Atom{AtomType, AtomFormat}, env); err != nil {
+			return engine.Error(err)
+		}
+		alg, err := verifyAlgorithm(options, util.Secp256r1, []util.Alg{util.Secp256r1, util.Secp256k1}, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		formatTerm, err := util.GetOptionWithDefault(AtomFormat, options, AtomCompressed, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		format, ok := env.Resolve(formatTerm).(engine.Atom)
+		if !ok {
+			return engine.Error(typeError(AtomAtom, formatTerm, env))
+		}
+		if format != AtomCompressed && format != AtomUncompressed {
+			return engine.Error(domainError(AtomKeyFormat, format, env))
+		}
+
+		key, err := decodeBytes(ctx, in, AtomOctet, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		if err := consumeAlgorithmGas(ctx, functor, alg.String(), len(key)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		normalized, err := util.NormalizePublicKey(alg, key, format == AtomCompressed)
+		if err != nil {
+			return engine.Error(domainError(AtomPublicKey, in, env))
+		}
+		return engine.Unify(vm, out, BytesToList(normalized), cont, env)
+	})
+}
+
+// termToEthereumAddress converts the given 0x prefixed hexadecimal Ethereum address into its 20 bytes, checking its
+// EIP-55 checksum when given in mixed case.
+func termToEthereumAddress(address engine.Term, env *engine.Env) ([]byte, error) {
+	atom, ok := env.Resolve(address).(engine.Atom)
+	if !ok {
+		return nil, fmt.Errorf("invalid address type: %T, should be Atom", env.Resolve(address))
+	}
+
+	text := atom.String()
+	if !strings.HasPrefix(text, "0x") || len(text) != 42 {
+		return nil, fmt.Errorf("invalid address: %s, should be a 0x prefixed 20-byte hexadecimal address", text)
+	}
+	decoded, err := hex.DecodeString(text[2:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %s, should be a 0x prefixed 20-byte hexadecimal address", text)
+	}
+	if digits := text[2:]; strings.ToLower(digits) != digits && strings.ToUpper(digits) != digits &&
+		util.EthereumChecksumAddress(decoded) != text {
+		return nil, fmt.Errorf("invalid address checksum: %s", text)
+	}
+
+	return decoded, nil
 }
 
 // xVerify return `true` if the Signature can be verified as the signature for Data, using the given PubKey for a
-// considered algorithm.
+// considered algorithm, or unifies the reason of the outcome of the verification when the reason option is given.
 // This is a generic predicate implementation that can be used to verify any signature.
-func xVerify(functor string, key, data, sig, options engine.Term, defaultAlgo util.Alg,
+func xVerify(vm *engine.VM, functor string, key, data, sig, options engine.Term, defaultAlgo util.Alg,
 	algos []util.Alg, cont engine.Cont, env *engine.Env,
 ) *engine.Promise {
-	typeOpt := engine.NewAtom("type")
 	return engine.Delay(func(ctx context.Context) *engine.Promise {
-		typeTerm, err := util.GetOptionWithDefault(typeOpt, options, engine.NewAtom(defaultAlgo.String()), env)
+		if err := util.CheckOptions(options, append(verifyOptions, AtomHash, AtomReason), env); err != nil {
+			return engine.Error(err)
+		}
+		reason, err := util.GetOption(AtomReason, options, env)
 		if err != nil {
-			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+			return engine.Error(err)
 		}
-		typeAtom, err := util.ResolveToAtom(env, typeTerm)
+		alg, err := verifyAlgorithm(options, defaultAlgo, algos, env)
 		if err != nil {
-			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+			return engine.Error(err)
 		}
 
-		if idx := slices.IndexFunc(algos, func(a util.Alg) bool { return a.String() == typeAtom.String() }); idx == -1 {
-			return engine.Error(fmt.Errorf("%s: invalid type: %s. Possible values: %s",
-				functor,
-				typeAtom.String(),
-				strings.Join(util.Map(algos, func(a util.Alg) string { return a.String() }), ", ")))
+		decodedKey, decodedData, decodedSignature, err := decodeVerifyInputs(ctx, key, data, sig, options, env)
+		if err != nil {
+			return engine.Error(err)
 		}
-
-		decodedKey, err := TermToBytes(key, AtomEncoding.Apply(AtomOctet), env)
+		decodedData, err = hashVerifyData(ctx, functor, decodedData, options, env)
 		if err != nil {
-			return engine.Error(fmt.Errorf("%s: failed to decode public key: %w", functor, err))
+			return engine.Error(err)
 		}
 
-		decodedData, err := TermToBytes(data, options, env)
-		if err != nil {
-			return engine.Error(fmt.Errorf("%s: failed to decode data: %w", functor, err))
+		if err := consumeAlgorithmGas(ctx, functor, alg.String(), len(decodedData)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
 		}
 
-		decodedSignature, err := TermToBytes(sig, AtomEncoding.Apply(AtomOctet), env)
-		if err != nil {
-			return engine.Error(fmt.Errorf("%s: failed to decode signature: %w", functor, err))
+		if reason != nil {
+			return engine.Unify(vm, reason, verifyStatus(alg, decodedKey, decodedData, decodedSignature), cont, env)
 		}
 
-		r, err := util.VerifySignature(util.Alg(typeAtom.String()), decodedKey, decodedData, decodedSignature)
+		r, err := util.VerifySignature(alg, decodedKey, decodedData, decodedSignature)
 		if err != nil {
-			return engine.Error(fmt.Errorf("%s: failed to verify signature: %w", functor, err))
+			return engine.Error(domainError(AtomPublicKey, key, env))
 		}
 
 		if !r {
@@ -228,3 +1160,103 @@ func xVerify(functor string, key, data, sig, options engine.Term, defaultAlgo ut
 		return cont(env)
 	})
 }
+
+// verifyStatus verifies the given signature of the given data with the given public key using the given algorithm,
+// and returns the outcome of the verification, as given by the reason option of the signature verification
+// predicates.
+func verifyStatus(alg util.Alg, key, data, sig []byte) engine.Atom {
+	if !slices.Contains(verifyKeySizes[alg], len(key)) || (alg == util.Ed25519 && len(sig) != ed25519.SignatureSize) {
+		return AtomWrongLength
+	}
+
+	r, err := util.VerifySignature(alg, key, data, sig)
+	switch {
+	case err != nil:
+		return AtomMalformedPoint
+	case !r:
+		return AtomBadSignature
+	default:
+		return AtomValid
+	}
+}
+
+// verifyAlgorithm returns the signature algorithm given by the type option of the signature verification predicates,
+// checking that it is one of the given algorithms.
+func verifyAlgorithm(options engine.Term, defaultAlgo util.Alg, algos []util.Alg, env *engine.Env) (util.Alg, error) {
+	typeTerm, err := util.GetOptionWithDefault(AtomType, options, engine.NewAtom(defaultAlgo.String()), env)
+	if err != nil {
+		return "", err
+	}
+	typeAtom, ok := env.Resolve(typeTerm).(engine.Atom)
+	if !ok {
+		return "", typeError(AtomAtom, typeTerm, env)
+	}
+
+	if idx := slices.IndexFunc(algos, func(a util.Alg) bool { return a.String() == typeAtom.String() }); idx == -1 {
+		return "", domainError(AtomAlgorithm, typeAtom, env)
+	}
+
+	return util.Alg(typeAtom.String()), nil
+}
+
+// hashVerifyData hashes the given data with the algorithm given by the hash option of the signature verification
+// predicates, if any, so that the signature of a raw message can be verified against its digest.
+func hashVerifyData(ctx context.Context, functor string, data []byte, options engine.Term, env *engine.Env) ([]byte, error) {
+	hashTerm, err := util.GetOptionWithDefault(AtomHash, options, AtomNone, env)
+	if err != nil {
+		return nil, err
+	}
+	hashAtom, ok := env.Resolve(hashTerm).(engine.Atom)
+	if !ok {
+		return nil, typeError(AtomAtom, hashTerm, env)
+	}
+
+	if hashAtom == AtomNone {
+		return data, nil
+	}
+	digest, ok := verifyHashes[hashAtom.String()]
+	if !ok {
+		return nil, domainError(AtomHash, hashAtom, env)
+	}
+
+	if err := consumeAlgorithmGas(ctx, functor, hashAtom.String(), len(data)); err != nil {
+		return nil, err
+	}
+	return digest(data), nil
+}
+
+// decodeVerifyInputs decodes the public key, the data and the signature given to the signature verification
+// predicates according to the encoding option, the public key and the signature being also accepted as lists of bytes
+// whatever the encoding.
+func decodeVerifyInputs(ctx context.Context, key, data, sig, options engine.Term, env *engine.Env) ([]byte, []byte, []byte, error) {
+	encoding, err := util.GetOptionWithDefault(AtomEncoding, options, AtomHex, env)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	decodedKey, err := decodeBytes(ctx, key, verifyBytesEncoding(key, encoding, env), env)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	decodedData, err := decodeBytes(ctx, data, encoding, env)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	decodedSignature, err := decodeBytes(ctx, sig, verifyBytesEncoding(sig, encoding, env), env)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return decodedKey, decodedData, decodedSignature, nil
+}
+
+// verifyBytesEncoding returns the encoding of the given public key or signature of the signature verification
+// predicates: the given encoding for an Atom, and octet for a list of bytes or any other term.
+func verifyBytesEncoding(term, encoding engine.Term, env *engine.Env) engine.Term {
+	if atom, ok := env.Resolve(term).(engine.Atom); ok && atom != util.AtomEmptyList {
+		return encoding
+	}
+	return AtomOctet
+}
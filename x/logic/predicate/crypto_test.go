@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/ichiban/prolog/engine"
+	"github.com/samber/lo"
 
 	. "github.com/smartystreets/goconvey/convey"
 
@@ -13,6 +14,8 @@ import (
 	"github.com/cometbft/cometbft/libs/log"
 	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
 
+	"cosmossdk.io/math"
+
 	"github.com/cosmos/cosmos-sdk/store"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
@@ -37,10 +40,18 @@ func TestCryptoHash(t *testing.T) {
 				wantSuccess: true,
 			},
 			{
-				query:       `sha_hash(Foo, Hash).`,
-				wantResult:  []types.TermResults{},
-				wantError:   fmt.Errorf("sha_hash/2: invalid data type: engine.Variable, should be Atom"),
-				wantSuccess: false,
+				query: `catch(sha_hash(Foo, Hash), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Foo": "_1", "Hash": "_1", "E": "error(instantiation_error,/(sha_hash,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(sha_hash(foo(bar), Hash), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "E": "error(type_error(atom,foo(bar)),/(sha_hash,2))", "R": "caught",
+				}},
+				wantSuccess: true,
 			},
 			{
 				query: `sha_hash('bar',
@@ -96,10 +107,12 @@ H == [2252,222,43,46,219,165,107,244,8,96,31,183,33,254,155,92,51,141,16,238,66,
 				wantSuccess: false,
 			},
 			{
-				query: `hex_bytes('fail',
-[44,38,180,107,104,255,198,143,249,155,69,60,29,48,65,52,19,66,45,112,100,131,191,160,249,138,94,136,98,102,231,174]).`,
-				wantError:   fmt.Errorf("hex_bytes/2: failed decode hexadecimal encoding/hex: invalid byte: U+0069 'i'"),
-				wantSuccess: false,
+				query: `catch(hex_bytes('fail',
+[44,38,180,107,104,255,198,143,249,155,69,60,29,48,65,52,19,66,45,112,100,131,191,160,249,138,94,136,98,102,231,174]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(domain_error(encoding(hex),fail),/(hex_bytes,2))", "R": "caught",
+				}},
+				wantSuccess: true,
 			},
 			{
 				query: `hex_bytes('2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae',
@@ -107,10 +120,135 @@ H == [2252,222,43,46,219,165,107,244,8,96,31,183,33,254,155,92,51,141,16,238,66,
 				wantSuccess: false,
 			},
 			{
-				query: `hex_bytes('2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae',
-[345,38,'hey',107,104,255,198,143,249,155,69,60,29,48,65,52,19,66,45,112,100,131,191,160,249,138,94,136,98,102,231,174]).`,
+				query: `catch(hex_bytes('2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae',
+[345,38,'hey',107,104,255,198,143,249,155,69,60,29,48,65,52,19,66,45,112,100,131,191,160,249,138,94,136,98,102,231,174]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(type_error(byte,345),/(hex_bytes,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hex_bytes('abc', Bytes), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "E": "error(domain_error(encoding(hex),abc),context(/(hex_bytes,2),'odd number of hex digits'))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hex_bytes('0', Bytes), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "E": "error(domain_error(encoding(hex),'0'),context(/(hex_bytes,2),'odd number of hex digits'))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes(Hex, [44, 38, 180, 107], [as(codes)]).`,
+				wantResult:  []types.TermResults{{"Hex": "[50,99,50,54,98,52,54,98]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes(Hex, [44, 38], [as(string)]).`,
+				wantResult:  []types.TermResults{{"Hex": "['2',c,'2','6']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes(Hex, [44, 38], [as(atom)]).`,
+				wantResult:  []types.TermResults{{"Hex": "'2c26'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes("2c26", Bytes, []).`,
+				wantResult:  []types.TermResults{{"Bytes": "[44,38]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes([50, 99, 50, 54], Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[44,38]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes([], Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes("2c26", [44, 38], [as(atom)]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes([50, 99, 50, 54], [44, 39], [as(codes)]).`,
 				wantSuccess: false,
-				wantError:   fmt.Errorf("hex_bytes/2: failed convert list into bytes: invalid integer value in list at position 1: 345 is out of byte range (0-255)"),
+			},
+			{
+				query: `catch(hex_bytes("2c2", Bytes, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "E": "error(domain_error(encoding(hex),'2c2'),context(/(hex_bytes,3),'odd number of hex digits'))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hex_bytes([foo(bar)], Bytes), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "E": "error(type_error(atom,[foo(bar)]),/(hex_bytes,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hex_bytes(Hex, [44], [as(text)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hex": "_1", "E": "error(domain_error(text_type,text),/(hex_bytes,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hex_bytes(Hex, [44], [as(T)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hex": "_1", "T": "_1", "E": "error(instantiation_error,/(hex_bytes,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hex_bytes(Hex, [44], [as("codes")]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hex": "_1", "E": "error(type_error(atom,[c,o,d,e,s]),/(hex_bytes,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hex_bytes(Hex, [44], [encoding(hex)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hex": "_1", "E": "error(domain_error(option,encoding(hex)),/(hex_bytes,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hex_bytes('abcg', Bytes), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "E": "error(domain_error(encoding(hex),abcg),/(hex_bytes,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hex_bytes(Hex, Bytes), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hex": "_1", "Bytes": "_1", "E": "error(instantiation_error,/(hex_bytes,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hex_bytes(foo(bar), Bytes), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "E": "error(type_error(atom,foo(bar)),/(hex_bytes,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hex_bytes(Hex, foo), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hex": "_1", "E": "error(type_error(list,foo),/(hex_bytes,2))", "R": "caught",
+				}},
+				wantSuccess: true,
 			},
 		}
 		for nc, tc := range cases {
@@ -124,6 +262,8 @@ H == [2252,222,43,46,219,165,107,244,8,96,31,183,33,254,155,92,51,141,16,238,66,
 						interpreter := testutil.NewLightInterpreterMust(ctx)
 						interpreter.Register2(engine.NewAtom("sha_hash"), SHAHash)
 						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
+						interpreter.Register3(engine.NewAtom("hex_bytes"), HexBytesWithOptions)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
 
 						err := interpreter.Compile(ctx, tc.program)
 						So(err, ShouldBeNil)
@@ -172,6 +312,92 @@ H == [2252,222,43,46,219,165,107,244,8,96,31,183,33,254,155,92,51,141,16,238,66,
 	})
 }
 
+func TestCryptoGas(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		costs := []types.AlgorithmCost{
+			{Algorithm: "sha256", BaseCost: lo.ToPtr(math.NewUint(10)), ByteCost: lo.ToPtr(math.NewUint(2))},
+			{Algorithm: "ed25519", BaseCost: lo.ToPtr(math.NewUint(100))},
+		}
+		cases := []struct {
+			query        string
+			costs        []types.AlgorithmCost
+			gasLimit     uint64
+			wantGas      uint64
+			wantOutOfGas bool
+		}{
+			{
+				query:    `sha_hash('foo', Hash).`,
+				gasLimit: 1000,
+				wantGas:  0,
+			},
+			{
+				query:    `sha_hash('foo', Hash).`,
+				costs:    costs,
+				gasLimit: 1000,
+				wantGas:  16,
+			},
+			{
+				query:    `sha_hash('foobar', Hash).`,
+				costs:    costs,
+				gasLimit: 1000,
+				wantGas:  22,
+			},
+			{
+				query: `hex_bytes('53167ac3fc4b720daa45b04fc73fe752578fa23a10048422d6904b7f4f7bba5a', PubKey),
+hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig),
+eddsa_verify(PubKey, '9b038f8ef6918cbb56040dfda401b56bb1ce79c472e7736e8677758c83367a9d', Sig, encoding(hex)).`,
+				costs:    costs,
+				gasLimit: 1000,
+				wantGas:  100,
+			},
+			{
+				query:        `sha_hash('foo', Hash).`,
+				costs:        costs,
+				gasLimit:     15,
+				wantOutOfGas: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context with a gas meter and algorithm costs", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger()).
+						WithGasMeter(sdk.NewGasMeter(tc.gasLimit))
+					ctx = ctx.WithValue(types.AlgorithmCostsContextKey, tc.costs)
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("sha_hash"), SHAHash)
+						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
+						interpreter.Register4(engine.NewAtom("eddsa_verify"), EDDSAVerify)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+							So(err, ShouldBeNil)
+							So(sols, ShouldNotBeNil)
+
+							Convey("Then the gas consumed should be as expected", func() {
+								for sols.Next() {
+									So(sols.Scan(types.TermResults{}), ShouldBeNil)
+								}
+
+								if tc.wantOutOfGas {
+									So(sols.Err(), ShouldNotBeNil)
+									So(ctx.GasMeter().IsOutOfGas(), ShouldBeTrue)
+								} else {
+									So(sols.Err(), ShouldBeNil)
+									So(ctx.GasMeter().GasConsumed(), ShouldEqual, tc.wantGas)
+								}
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
 func TestXVerify(t *testing.T) {
 	Convey("Given a test cases", t, func() {
 		cases := []struct {
@@ -216,9 +442,9 @@ func TestXVerify(t *testing.T) {
 			hex_bytes('9b038f8ef6918cbb56040dfda401b56bb1ce79c472e7736e8677758c83367a9d', Msg),
 			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig),
 			eddsa_verify(PubKey, Msg, Sig, encoding(octet)).`,
-				query:       `verify.`,
-				wantSuccess: false,
-				wantError:   fmt.Errorf("eddsa_verify/4: failed to verify signature: ed25519: bad public key length: 33"),
+				query:       `catch(verify, E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(public_key,[83,22,122,195,252,75,114,13,170,69,176,79,199,63,231,82,87,143,162,58,16,4,132,34,214,144,75,127,79,123,186,91,91]),/(eddsa_verify,4))", "R": "caught"}},
+				wantSuccess: true,
 			},
 			{ // Wrong signature
 				program: `verify :-
@@ -235,9 +461,9 @@ func TestXVerify(t *testing.T) {
 			hex_bytes('9b038f8ef6918cbb56040dfda401b56bb1ce79c472e7736e8677758c83367a9d', Msg),
 			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig),
 			eddsa_verify(PubKey, Msg, Sig, [encoding(octet), type(foo)]).`,
-				query:       `verify.`,
-				wantSuccess: false,
-				wantError:   fmt.Errorf("eddsa_verify/4: invalid type: foo. Possible values: ed25519"),
+				query:       `catch(verify, E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(algorithm,foo),/(eddsa_verify,4))", "R": "caught"}},
+				wantSuccess: true,
 			},
 			// ECDSA - secp256r1
 			{
@@ -257,9 +483,49 @@ func TestXVerify(t *testing.T) {
 			hex_bytes('9b038f8ef6918cbb56040dfda401b56bb1ce79c472e7736e8677758c83367a9d', Msg),
 			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig),
 			ecdsa_verify(PubKey, Msg, Sig, encoding(octet)).`,
+				query:       `catch(verify, E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(public_key,[2,19,200,66,107,228,113,229,85,6,247,206,79,125,245,87]),/(ecdsa_verify,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // All good with an uncompressed public key
+				program: `verify :-
+			hex_bytes('0413c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b040913fa78a2b2a4ba5011d54645193943da21cddbe423df97f0fba67e07f99a', PubKey),
+			hex_bytes('e50c26e89f734b2ee12041ff27874c901891f74a0f0cf470333312a3034ce3be', Msg),
+			hex_bytes('30450220099e6f9dd218e0e304efa7a4224b0058a8e3aec73367ec239bee4ed8ed7d85db022100b504d3d0d2e879b04705c0e5a2b40b0521a5ab647ea207bd81134e1a4eb79e47', Sig),
+			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), type(secp256r1)]).`,
 				query:       `verify.`,
-				wantSuccess: false,
-				wantError:   fmt.Errorf("ecdsa_verify/4: failed to verify signature: failed to parse compressed public key (first 10 bytes): 0213c8426be471e55506"),
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{ // Uncompressed public key not on the curve
+				program: `verify :-
+			hex_bytes('0413c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b040913fa78a2b2a4ba5011d54645193943da21cddbe423df97f0fba67e07f99b', PubKey),
+			hex_bytes('e50c26e89f734b2ee12041ff27874c901891f74a0f0cf470333312a3034ce3be', Msg),
+			hex_bytes('30450220099e6f9dd218e0e304efa7a4224b0058a8e3aec73367ec239bee4ed8ed7d85db022100b504d3d0d2e879b04705c0e5a2b40b0521a5ab647ea207bd81134e1a4eb79e47', Sig),
+			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), type(secp256r1)]).`,
+				query:       `catch(verify, E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(public_key,[4,19,200,66,107,228,113,229,85,6,247,206,79,125,245,87,164,46,49,13,240,159,146,235,115,44,163,8,94,121,124,239,155,4,9,19,250,120,162,178,164,186,80,17,213,70,69,25,57,67,218,33,205,219,228,35,223,151,240,251,166,126,7,249,155]),/(ecdsa_verify,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // Invalid uncompressed public key prefix
+				program: `verify :-
+			hex_bytes('0513c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b040913fa78a2b2a4ba5011d54645193943da21cddbe423df97f0fba67e07f99a', PubKey),
+			hex_bytes('e50c26e89f734b2ee12041ff27874c901891f74a0f0cf470333312a3034ce3be', Msg),
+			hex_bytes('30450220099e6f9dd218e0e304efa7a4224b0058a8e3aec73367ec239bee4ed8ed7d85db022100b504d3d0d2e879b04705c0e5a2b40b0521a5ab647ea207bd81134e1a4eb79e47', Sig),
+			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), type(secp256r1)]).`,
+				query:       `catch(verify, E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(public_key,[5,19,200,66,107,228,113,229,85,6,247,206,79,125,245,87,164,46,49,13,240,159,146,235,115,44,163,8,94,121,124,239,155,4,9,19,250,120,162,178,164,186,80,17,213,70,69,25,57,67,218,33,205,219,228,35,223,151,240,251,166,126,7,249,154]),/(ecdsa_verify,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // Invalid compressed public key prefix
+				program: `verify :-
+			hex_bytes('0413c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b', PubKey),
+			hex_bytes('e50c26e89f734b2ee12041ff27874c901891f74a0f0cf470333312a3034ce3be', Msg),
+			hex_bytes('30450220099e6f9dd218e0e304efa7a4224b0058a8e3aec73367ec239bee4ed8ed7d85db022100b504d3d0d2e879b04705c0e5a2b40b0521a5ab647ea207bd81134e1a4eb79e47', Sig),
+			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), type(secp256r1)]).`,
+				query:       `catch(verify, E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(public_key,[4,19,200,66,107,228,113,229,85,6,247,206,79,125,245,87,164,46,49,13,240,159,146,235,115,44,163,8,94,121,124,239,155]),/(ecdsa_verify,4))", "R": "caught"}},
+				wantSuccess: true,
 			},
 			{ // Unsupported algo
 				program: `verify :-
@@ -267,9 +533,9 @@ func TestXVerify(t *testing.T) {
 			hex_bytes('9b038f8ef6918cbb56040dfda401b56bb1ce79c472e7736e8677758c83367a9d', Msg),
 			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig),
 			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), type(foo)]).`,
-				query:       `verify.`,
-				wantSuccess: false,
-				wantError:   fmt.Errorf("ecdsa_verify/4: invalid type: foo. Possible values: secp256r1, secp256k1"),
+				query:       `catch(verify, E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(algorithm,foo),/(ecdsa_verify,4))", "R": "caught"}},
+				wantSuccess: true,
 			},
 			{
 				// Wrong msg
@@ -305,6 +571,17 @@ func TestXVerify(t *testing.T) {
 				wantResult:  []types.TermResults{{}},
 				wantSuccess: true,
 			},
+			{
+				// All good with an uncompressed public key
+				program: `verify :-
+			hex_bytes('046b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71ce5f378de71b8f939a72a523695049eb999e644e0cce94fc3943297682ddd0e42', PubKey),
+			hex_bytes('dece063885d3648078f903b6a3e8989f649dc3368cd9c8d69755ed9dcb6a0995', Msg),
+			hex_bytes('304402201448201bb4408549b0997f4b9ad9ed36f3cf8bb9c433fc7f3ba48c6b6e39476e022053f7d056f7ffeab9a79f3a36bc2ba969ddd530a3a1495d1ed7bba00039820223', Sig),
+			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), type(secp256k1)]).`,
+				query:       `verify.`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
 			{
 				// Wrong signature
 				program: `verify :-
@@ -316,41 +593,1776 @@ func TestXVerify(t *testing.T) {
 				wantResult:  []types.TermResults{{}},
 				wantSuccess: false,
 			},
-		}
-		for nc, tc := range cases {
-			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
-				Convey("and a context", func() {
-					db := tmdb.NewMemDB()
-					stateStore := store.NewCommitMultiStore(db)
-					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
-
-					Convey("and a vm", func() {
-						interpreter := testutil.NewLightInterpreterMust(ctx)
-						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
-						interpreter.Register4(engine.NewAtom("eddsa_verify"), EDDSAVerify)
-						interpreter.Register4(engine.NewAtom("ecdsa_verify"), ECDSAVerify)
-
-						err := interpreter.Compile(ctx, tc.program)
-						So(err, ShouldBeNil)
-
-						Convey("When the predicate is called", func() {
-							sols, err := interpreter.QueryContext(ctx, tc.query)
-
-							Convey("Then the error should be nil", func() {
-								So(err, ShouldBeNil)
-								So(sols, ShouldNotBeNil)
-
-								Convey("and the bindings should be as expected", func() {
-									var got []types.TermResults
-									for sols.Next() {
-										m := types.TermResults{}
-										err := sols.Scan(m)
-										So(err, ShouldBeNil)
-
-										got = append(got, m)
-									}
-									if tc.wantError != nil {
-										So(sols.Err(), ShouldBeError, tc.wantError.Error())
+			// data encodings
+			{ // ed25519 with base64 data
+				program: `verify :-
+			hex_bytes('53167ac3fc4b720daa45b04fc73fe752578fa23a10048422d6904b7f4f7bba5a', PubKey),
+			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig),
+			eddsa_verify(PubKey, 'mwOPjvaRjLtWBA39pAG1a7HOecRy53Nuhnd1jIM2ep0=', Sig, [encoding(base64)]).`,
+				query:       `verify.`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{ // secp256k1 with base58 data
+				program: `verify :-
+			hex_bytes('026b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71c', PubKey),
+			hex_bytes('304402201448201bb4408549b0997f4b9ad9ed36f3cf8bb9c433fc7f3ba48c6b6e39476e022053f7d056f7ffeab9a79f3a36bc2ba969ddd530a3a1495d1ed7bba00039820223', Sig),
+			ecdsa_verify(PubKey, 'FzjidosJoSFmP48t7qV8o89qzS1tFqVbFJEomtMcvDYg', Sig, [encoding(base58), type(secp256k1)]).`,
+				query:       `verify.`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{ // Invalid base64 data
+				program: `verify :-
+			hex_bytes('53167ac3fc4b720daa45b04fc73fe752578fa23a10048422d6904b7f4f7bba5a', PubKey),
+			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig),
+			eddsa_verify(PubKey, 'mwOPjvaRjLtWBA39pAG1a7HOecRy53Nuhnd1jIM2ep0', Sig, [encoding(base64)]).`,
+				query:       `catch(verify, E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(encoding(base64),mwOPjvaRjLtWBA39pAG1a7HOecRy53Nuhnd1jIM2ep0),/(eddsa_verify,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // Invalid base58 data
+				program: `verify :-
+			hex_bytes('026b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71c', PubKey),
+			hex_bytes('304402201448201bb4408549b0997f4b9ad9ed36f3cf8bb9c433fc7f3ba48c6b6e39476e022053f7d056f7ffeab9a79f3a36bc2ba969ddd530a3a1495d1ed7bba00039820223', Sig),
+			ecdsa_verify(PubKey, '0FzjidosJoSFmP48t7qV8o89qzS1tFqVbFJEomtMcvDYg', Sig, [encoding(base58), type(secp256k1)]).`,
+				query:       `catch(verify, E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(encoding(base58),'0FzjidosJoSFmP48t7qV8o89qzS1tFqVbFJEomtMcvDYg'),/(ecdsa_verify,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // Unsupported encoding
+				program: `verify :-
+			hex_bytes('026b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71c', PubKey),
+			hex_bytes('304402201448201bb4408549b0997f4b9ad9ed36f3cf8bb9c433fc7f3ba48c6b6e39476e022053f7d056f7ffeab9a79f3a36bc2ba969ddd530a3a1495d1ed7bba00039820223', Sig),
+			ecdsa_verify(PubKey, 'FzjidosJoSFmP48t7qV8o89qzS1tFqVbFJEomtMcvDYg', Sig, [encoding(base32), type(secp256k1)]).`,
+				query:       `catch(verify, E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(encoding,base32),/(ecdsa_verify,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // ed25519 with base64 public key, data and signature
+				program: `verify :-
+			eddsa_verify('UxZ6w/xLcg2qRbBPxz/nUlePojoQBIQi1pBLf097ulo=', 'mwOPjvaRjLtWBA39pAG1a7HOecRy53Nuhnd1jIM2ep0=', 'iJvP0zHo5Dtev0MDAd/7asni/OafYie0NVL+PcjMHuAMHMU0UqhxLp1fgAht/4z0mZwbk+1sbkA8CTNMth3dCw==', [encoding(base64)]).`,
+				query:       `verify.`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{ // ed25519 with base64 data and signature, and public key as a list of bytes
+				program: `verify :-
+			hex_bytes('53167ac3fc4b720daa45b04fc73fe752578fa23a10048422d6904b7f4f7bba5a', PubKey),
+			eddsa_verify(PubKey, 'mwOPjvaRjLtWBA39pAG1a7HOecRy53Nuhnd1jIM2ep0=', 'iJvP0zHo5Dtev0MDAd/7asni/OafYie0NVL+PcjMHuAMHMU0UqhxLp1fgAht/4z0mZwbk+1sbkA8CTNMth3dCw==', [encoding(base64)]).`,
+				query:       `verify.`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{ // secp256k1 with base58 public key, data and signature
+				program: `verify :-
+			ecdsa_verify('igbw34Yk117iRbH6VVPfLRtjDUbeQa4Ch8nmB7J3uRiK', 'FzjidosJoSFmP48t7qV8o89qzS1tFqVbFJEomtMcvDYg', '381yXYoCFH5oFjphvrcsx4RkTNMtLh4aPJgKQZndMDgfTTj9tzMsNzRFsEtzPut2WPufYcF3SMKcbDqawZJj1e9CXUFRsmhL', [encoding(base58), type(secp256k1)]).`,
+				query:       `verify.`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{ // Invalid base58 public key
+				program: `verify :-
+			ecdsa_verify('0gbw34Yk117iRbH6VVPfLRtjDUbeQa4Ch8nmB7J3uRiK', 'FzjidosJoSFmP48t7qV8o89qzS1tFqVbFJEomtMcvDYg', '381yXYoCFH5oFjphvrcsx4RkTNMtLh4aPJgKQZndMDgfTTj9tzMsNzRFsEtzPut2WPufYcF3SMKcbDqawZJj1e9CXUFRsmhL', [encoding(base58), type(secp256k1)]).`,
+				query:       `catch(verify, E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(encoding(base58),'0gbw34Yk117iRbH6VVPfLRtjDUbeQa4Ch8nmB7J3uRiK'),/(ecdsa_verify,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // Public key as an atom with the octet encoding
+				program: `verify :-
+			eddsa_verify('UxZ6w/xLcg2qRbBPxz/nUlePojoQBIQi1pBLf097ulo=', [1, 2], [3, 4], [encoding(octet)]).`,
+				query:       `catch(verify, E, R = caught).`,
+				wantResult:  []types.TermResults{{"E": "error(type_error(list,'UxZ6w/xLcg2qRbBPxz/nUlePojoQBIQi1pBLf097ulo='),/(eddsa_verify,4))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			// reason option
+			{ // Valid ed25519 signature
+				program: `verify(Reason) :-
+			hex_bytes('53167ac3fc4b720daa45b04fc73fe752578fa23a10048422d6904b7f4f7bba5a', PubKey),
+			hex_bytes('9b038f8ef6918cbb56040dfda401b56bb1ce79c472e7736e8677758c83367a9d', Msg),
+			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig),
+			eddsa_verify(PubKey, Msg, Sig, [encoding(octet), reason(Reason)]).`,
+				query:       `verify(Reason).`,
+				wantResult:  []types.TermResults{{"Reason": "valid"}},
+				wantSuccess: true,
+			},
+			{ // Wrong ed25519 msg
+				program: `verify(Reason) :-
+			hex_bytes('53167ac3fc4b720daa45b04fc73fe752578fa23a10048422d6904b7f4f7bba5a', PubKey),
+			hex_bytes('9b038f8ef6918cbb56040dfda401b56bb1ce79c472e7736e8677758c83367a9e', Msg),
+			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig),
+			eddsa_verify(PubKey, Msg, Sig, [encoding(octet), reason(Reason)]).`,
+				query:       `verify(Reason).`,
+				wantResult:  []types.TermResults{{"Reason": "bad_signature"}},
+				wantSuccess: true,
+			},
+			{ // ed25519 public key of a wrong length
+				program: `verify(Reason) :-
+			hex_bytes('53167ac3fc4b720daa45b04fc73fe752578fa23a10048422d6904b7f4f7bba', PubKey),
+			hex_bytes('9b038f8ef6918cbb56040dfda401b56bb1ce79c472e7736e8677758c83367a9d', Msg),
+			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig),
+			eddsa_verify(PubKey, Msg, Sig, [encoding(octet), reason(Reason)]).`,
+				query:       `verify(Reason).`,
+				wantResult:  []types.TermResults{{"Reason": "wrong_length"}},
+				wantSuccess: true,
+			},
+			{ // ed25519 signature of a wrong length
+				program: `verify(Reason) :-
+			hex_bytes('53167ac3fc4b720daa45b04fc73fe752578fa23a10048422d6904b7f4f7bba5a', PubKey),
+			hex_bytes('9b038f8ef6918cbb56040dfda401b56bb1ce79c472e7736e8677758c83367a9d', Msg),
+			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd', Sig),
+			eddsa_verify(PubKey, Msg, Sig, [encoding(octet), reason(Reason)]).`,
+				query:       `verify(Reason).`,
+				wantResult:  []types.TermResults{{"Reason": "wrong_length"}},
+				wantSuccess: true,
+			},
+			{ // Valid secp256r1 signature with an uncompressed public key
+				program: `verify(Reason) :-
+			hex_bytes('0413c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b040913fa78a2b2a4ba5011d54645193943da21cddbe423df97f0fba67e07f99a', PubKey),
+			hex_bytes('e50c26e89f734b2ee12041ff27874c901891f74a0f0cf470333312a3034ce3be', Msg),
+			hex_bytes('30450220099e6f9dd218e0e304efa7a4224b0058a8e3aec73367ec239bee4ed8ed7d85db022100b504d3d0d2e879b04705c0e5a2b40b0521a5ab647ea207bd81134e1a4eb79e47', Sig),
+			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), reason(Reason)]).`,
+				query:       `verify(Reason).`,
+				wantResult:  []types.TermResults{{"Reason": "valid"}},
+				wantSuccess: true,
+			},
+			{ // secp256r1 public key not on the curve
+				program: `verify(Reason) :-
+			hex_bytes('0413c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b040913fa78a2b2a4ba5011d54645193943da21cddbe423df97f0fba67e07f99b', PubKey),
+			hex_bytes('e50c26e89f734b2ee12041ff27874c901891f74a0f0cf470333312a3034ce3be', Msg),
+			hex_bytes('30450220099e6f9dd218e0e304efa7a4224b0058a8e3aec73367ec239bee4ed8ed7d85db022100b504d3d0d2e879b04705c0e5a2b40b0521a5ab647ea207bd81134e1a4eb79e47', Sig),
+			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), reason(Reason)]).`,
+				query:       `verify(Reason).`,
+				wantResult:  []types.TermResults{{"Reason": "malformed_point"}},
+				wantSuccess: true,
+			},
+			{ // secp256r1 public key with an invalid prefix
+				program: `verify(Reason) :-
+			hex_bytes('0513c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b040913fa78a2b2a4ba5011d54645193943da21cddbe423df97f0fba67e07f99a', PubKey),
+			hex_bytes('e50c26e89f734b2ee12041ff27874c901891f74a0f0cf470333312a3034ce3be', Msg),
+			hex_bytes('30450220099e6f9dd218e0e304efa7a4224b0058a8e3aec73367ec239bee4ed8ed7d85db022100b504d3d0d2e879b04705c0e5a2b40b0521a5ab647ea207bd81134e1a4eb79e47', Sig),
+			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), reason(Reason)]).`,
+				query:       `verify(Reason).`,
+				wantResult:  []types.TermResults{{"Reason": "malformed_point"}},
+				wantSuccess: true,
+			},
+			{ // secp256r1 public key of a wrong length
+				program: `verify(Reason) :-
+			hex_bytes('0413c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b040913fa78a2b2a4ba5011d54645193943da21cddbe423df97f0fba67e07f9', PubKey),
+			hex_bytes('e50c26e89f734b2ee12041ff27874c901891f74a0f0cf470333312a3034ce3be', Msg),
+			hex_bytes('30450220099e6f9dd218e0e304efa7a4224b0058a8e3aec73367ec239bee4ed8ed7d85db022100b504d3d0d2e879b04705c0e5a2b40b0521a5ab647ea207bd81134e1a4eb79e47', Sig),
+			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), reason(Reason)]).`,
+				query:       `verify(Reason).`,
+				wantResult:  []types.TermResults{{"Reason": "wrong_length"}},
+				wantSuccess: true,
+			},
+			{ // Valid secp256k1 signature
+				program: `verify(Reason) :-
+			hex_bytes('026b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71c', PubKey),
+			hex_bytes('dece063885d3648078f903b6a3e8989f649dc3368cd9c8d69755ed9dcb6a0995', Msg),
+			hex_bytes('304402201448201bb4408549b0997f4b9ad9ed36f3cf8bb9c433fc7f3ba48c6b6e39476e022053f7d056f7ffeab9a79f3a36bc2ba969ddd530a3a1495d1ed7bba00039820223', Sig),
+			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), type(secp256k1), reason(Reason)]).`,
+				query:       `verify(Reason).`,
+				wantResult:  []types.TermResults{{"Reason": "valid"}},
+				wantSuccess: true,
+			},
+			{ // Wrong secp256k1 signature
+				program: `verify(Reason) :-
+			hex_bytes('026b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71c', PubKey),
+			hex_bytes('dece063885d3648078f903b6a3e8989f649dc3368cd9c8d69755ed9dcb6a0995', Msg),
+			hex_bytes('304402201448201bb4408549b0997f4b9ad9ed36f3cf8bb9c433fc7f3ba48c6b6e39476e022053f7d056f7ffeab9a79f3a36bc2ba969ddd530a3a1495d1ed7bba00039820224', Sig),
+			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), type(secp256k1), reason(Reason)]).`,
+				query:       `verify(Reason).`,
+				wantResult:  []types.TermResults{{"Reason": "bad_signature"}},
+				wantSuccess: true,
+			},
+			{ // Malformed secp256k1 signature
+				program: `verify(Reason) :-
+			hex_bytes('026b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71c', PubKey),
+			hex_bytes('dece063885d3648078f903b6a3e8989f649dc3368cd9c8d69755ed9dcb6a0995', Msg),
+			hex_bytes('3044', Sig),
+			ecdsa_verify(PubKey, Msg, Sig, [encoding(octet), type(secp256k1), reason(Reason)]).`,
+				query:       `verify(Reason).`,
+				wantResult:  []types.TermResults{{"Reason": "bad_signature"}},
+				wantSuccess: true,
+			},
+			{
+				// Misspelled option
+				query: `catch(eddsa_verify([], [], [], [encoding(octet), typ(ed25519)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(domain_error(option,typ(ed25519)),/(eddsa_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				// Malformed option
+				query: `catch(ecdsa_verify([], [], [], [encoding(octet), secp256k1]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(domain_error(option,secp256k1),/(ecdsa_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				// Options not sufficiently instantiated
+				query: `catch(ecdsa_verify([], [], [], [encoding(octet)|_]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(instantiation_error,/(ecdsa_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+
+			{ // Raw message hashed with Keccak-256
+				program: `verify :-
+			hex_bytes('03ecde6e57b31d529ac9473773ba51aeb2185cc5cb24e45864cfe758405c82547b', PubKey),
+			hex_bytes('3045022100d48eded1e62e91ff71dcffb7f65ad439bdc9e7e6d1974151d5c9495de5b8b37d022078136ee49da03e0753dcd1063b2e51f691ce6954418b54365a19c4c2255b8b02', Sig),
+			ecdsa_verify(PubKey, '68656c6c6f206f6b7034', Sig, [type(secp256k1), hash(keccak256)]).`,
+				query:       `verify.`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{ // Raw message verified as a hash
+				program: `verify :-
+			hex_bytes('03ecde6e57b31d529ac9473773ba51aeb2185cc5cb24e45864cfe758405c82547b', PubKey),
+			hex_bytes('3045022100d48eded1e62e91ff71dcffb7f65ad439bdc9e7e6d1974151d5c9495de5b8b37d022078136ee49da03e0753dcd1063b2e51f691ce6954418b54365a19c4c2255b8b02', Sig),
+			ecdsa_verify(PubKey, '68656c6c6f206f6b7034', Sig, [type(secp256k1)]).`,
+				query:       `verify.`,
+				wantSuccess: false,
+			},
+			{ // Raw message hashed with SHA-256
+				program: `verify :-
+			hex_bytes('02cdae3df6896808178ead593054560ac04c2b5abd4bf73707746cc5db5d39f8a1', PubKey),
+			hex_bytes('3044022027bdfe2b18136d0dac4e715a46a7bc49dfb998b70af194ea0a3c5d429434f87a02206623289a4ecc2558bb9426839e917a149004605dff1d4ebd967027d01e2045db', Sig),
+			ecdsa_verify(PubKey, [104,101,108,108,111,32,111,107,112,52], Sig, [encoding(octet), hash(sha256)]).`,
+				query:       `verify.`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(ecdsa_verify([], '00', [], [hash(md5)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(domain_error(hash,md5),/(ecdsa_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(ecdsa_verify([], '00', [], [hash(1)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(type_error(atom,1),/(ecdsa_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(eddsa_verify([], [], [], [type(1)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(type_error(atom,1),/(eddsa_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(eddsa_verify([], [], [], [encoding(base32)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(domain_error(encoding,base32),/(eddsa_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(eddsa_verify(foo, [], [], [encoding(octet)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(type_error(list,foo),/(eddsa_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(eddsa_verify([], [], [1, 300], [encoding(octet)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(type_error(byte,300),/(eddsa_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(eddsa_verify([], Data, [], []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Data": "_1", "E": "error(instantiation_error,/(eddsa_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(ecdsa_verify([], [1, 2], [], [encoding(hex)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(type_error(atom,[1,2]),/(ecdsa_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(ecdsa_verify([], '0g', [], []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(domain_error(encoding(hex),'0g'),/(ecdsa_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
+						interpreter.Register4(engine.NewAtom("eddsa_verify"), EDDSAVerify)
+						interpreter.Register4(engine.NewAtom("ecdsa_verify"), ECDSAVerify)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldBeError, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestVerifyAny(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{ // ed25519, the second of three keys matches
+				program: `verify(Key) :-
+			hex_bytes('5649f0a27a52d2f0ea6183f7b4772119cf4b981b006da589734d4deb3adcf059', K1),
+			hex_bytes('53167ac3fc4b720daa45b04fc73fe752578fa23a10048422d6904b7f4f7bba5a', K2),
+			hex_bytes('d6807d55906572ce29a591f2b2ef16dfbb401d53739bac4b380e4d6b7e908ee2', K3),
+			hex_bytes('9b038f8ef6918cbb56040dfda401b56bb1ce79c472e7736e8677758c83367a9d', Msg),
+			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig),
+			verify_any(ed25519, [K1, K2, K3], Msg, Sig, Matched),
+			hex_bytes(Key, Matched).`,
+				query:       `verify(Key).`,
+				wantResult:  []types.TermResults{{"Key": "'53167ac3fc4b720daa45b04fc73fe752578fa23a10048422d6904b7f4f7bba5a'"}},
+				wantSuccess: true,
+			},
+			{ // ed25519, no key matches
+				program: `verify(Key) :-
+			hex_bytes('5649f0a27a52d2f0ea6183f7b4772119cf4b981b006da589734d4deb3adcf059', K1),
+			hex_bytes('d6807d55906572ce29a591f2b2ef16dfbb401d53739bac4b380e4d6b7e908ee2', K3),
+			hex_bytes('9b038f8ef6918cbb56040dfda401b56bb1ce79c472e7736e8677758c83367a9d', Msg),
+			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig),
+			verify_any(ed25519, [K1, K3], Msg, Sig, Key).`,
+				query:       `verify(Key).`,
+				wantSuccess: false,
+			},
+			{ // secp256k1 with an ASN.1 signature, the second of three keys matches by trial
+				program: `verify(Key) :-
+			hex_bytes('035649f0a27a52d2f0ea6183f7b4772119cf4b981b006da589734d4deb3adcf059', K1),
+			hex_bytes('02d6807d55906572ce29a591f2b2ef16dfbb401d53739bac4b380e4d6b7e908ee2', K2),
+			hex_bytes('025b0450f1f7a0dcdff8c43b7f9b62c309ea06185853fca260252fcb32479d37df', K3),
+			hex_bytes('91179e2f5d22b0efb6695a3881fbdafe453a480839622e65f1a01fcf70872f91', Hash),
+			hex_bytes('3044022041239318b4cf65f0ac276c4566524dccf71507df7b391007392c4d9fdcb68b3102201971a967bcc257d4ad88a0c8e0d3e4d3cd2ba307ceb657e8fb447f3fe6a318c2', Sig),
+			verify_any(secp256k1, [K1, K2, K3], Hash, Sig, Matched),
+			hex_bytes(Key, Matched).`,
+				query:       `verify(Key).`,
+				wantResult:  []types.TermResults{{"Key": "'02d6807d55906572ce29a591f2b2ef16dfbb401d53739bac4b380e4d6b7e908ee2'"}},
+				wantSuccess: true,
+			},
+			{ // secp256k1 with a recoverable signature, the second of three keys matches by recovery
+				program: `verify(Key) :-
+			hex_bytes('035649f0a27a52d2f0ea6183f7b4772119cf4b981b006da589734d4deb3adcf059', K1),
+			hex_bytes('02d6807d55906572ce29a591f2b2ef16dfbb401d53739bac4b380e4d6b7e908ee2', K2),
+			hex_bytes('025b0450f1f7a0dcdff8c43b7f9b62c309ea06185853fca260252fcb32479d37df', K3),
+			hex_bytes('91179e2f5d22b0efb6695a3881fbdafe453a480839622e65f1a01fcf70872f91', Hash),
+			hex_bytes('41239318b4cf65f0ac276c4566524dccf71507df7b391007392c4d9fdcb68b311971a967bcc257d4ad88a0c8e0d3e4d3cd2ba307ceb657e8fb447f3fe6a318c201', Sig),
+			verify_any(secp256k1, [K1, K2, K3], Hash, Sig, Matched),
+			hex_bytes(Key, Matched).`,
+				query:       `verify(Key).`,
+				wantResult:  []types.TermResults{{"Key": "'02d6807d55906572ce29a591f2b2ef16dfbb401d53739bac4b380e4d6b7e908ee2'"}},
+				wantSuccess: true,
+			},
+			{ // secp256k1 with a recoverable signature, the recovered key is not a candidate
+				program: `verify(Key) :-
+			hex_bytes('035649f0a27a52d2f0ea6183f7b4772119cf4b981b006da589734d4deb3adcf059', K1),
+			hex_bytes('025b0450f1f7a0dcdff8c43b7f9b62c309ea06185853fca260252fcb32479d37df', K3),
+			hex_bytes('91179e2f5d22b0efb6695a3881fbdafe453a480839622e65f1a01fcf70872f91', Hash),
+			hex_bytes('41239318b4cf65f0ac276c4566524dccf71507df7b391007392c4d9fdcb68b311971a967bcc257d4ad88a0c8e0d3e4d3cd2ba307ceb657e8fb447f3fe6a318c201', Sig),
+			verify_any(secp256k1, [K1, K3], Hash, Sig, Key).`,
+				query:       `verify(Key).`,
+				wantSuccess: false,
+			},
+			{ // secp256k1 with a recoverable signature, the matching key being given in uncompressed form
+				program: `verify(Key) :-
+			hex_bytes('035649f0a27a52d2f0ea6183f7b4772119cf4b981b006da589734d4deb3adcf059', K1),
+			hex_bytes('04d6807d55906572ce29a591f2b2ef16dfbb401d53739bac4b380e4d6b7e908ee284ecb8cfae205c2f57f5415f58ae5119bcae2ada34984603b3c56a11640790a0', K2),
+			hex_bytes('91179e2f5d22b0efb6695a3881fbdafe453a480839622e65f1a01fcf70872f91', Hash),
+			hex_bytes('41239318b4cf65f0ac276c4566524dccf71507df7b391007392c4d9fdcb68b311971a967bcc257d4ad88a0c8e0d3e4d3cd2ba307ceb657e8fb447f3fe6a318c201', Sig),
+			verify_any(secp256k1, [K1, K2], Hash, Sig, Matched),
+			hex_bytes(Key, Matched).`,
+				query:       `verify(Key).`,
+				wantResult:  []types.TermResults{{"Key": "'04d6807d55906572ce29a591f2b2ef16dfbb401d53739bac4b380e4d6b7e908ee284ecb8cfae205c2f57f5415f58ae5119bcae2ada34984603b3c56a11640790a0'"}},
+				wantSuccess: true,
+			},
+			{ // secp256k1 with a recoverable signature, a candidate key not being a point of the curve
+				program: `verify(Key) :-
+			hex_bytes('91179e2f5d22b0efb6695a3881fbdafe453a480839622e65f1a01fcf70872f91', Hash),
+			hex_bytes('41239318b4cf65f0ac276c4566524dccf71507df7b391007392c4d9fdcb68b311971a967bcc257d4ad88a0c8e0d3e4d3cd2ba307ceb657e8fb447f3fe6a318c201', Sig),
+			verify_any(secp256k1, [[2, 1, 2]], Hash, Sig, Key).`,
+				query:       `catch(verify(_), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(public_key,[2,1,2]),/(verify_any,5))"}},
+				wantSuccess: true,
+			},
+			{ // secp256k1 with an invalid recovery id
+				program: `verify(Key) :-
+			hex_bytes('035649f0a27a52d2f0ea6183f7b4772119cf4b981b006da589734d4deb3adcf059', K1),
+			hex_bytes('91179e2f5d22b0efb6695a3881fbdafe453a480839622e65f1a01fcf70872f91', Hash),
+			hex_bytes('41239318b4cf65f0ac276c4566524dccf71507df7b391007392c4d9fdcb68b311971a967bcc257d4ad88a0c8e0d3e4d3cd2ba307ceb657e8fb447f3fe6a318c205', Sig),
+			verify_any(secp256k1, [K1], Hash, Sig, Key).`,
+				query:       `catch(verify(_), error(domain_error(D, _), C), true).`,
+				wantResult:  []types.TermResults{{"D": "signature", "C": "/(verify_any,5)"}},
+				wantSuccess: true,
+			},
+			{ // Unsupported algorithm
+				query:       `catch(verify_any(foo, [[1]], [1], [1], _), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(algorithm,foo),/(verify_any,5))"}},
+				wantSuccess: true,
+			},
+			{ // Unbound algorithm
+				query:       `catch(verify_any(_, [[1]], [1], [1], _), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(instantiation_error,/(verify_any,5))"}},
+				wantSuccess: true,
+			},
+			{ // Invalid candidate keys
+				query:       `catch(verify_any(ed25519, foo, [1], [1], _), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(type_error(list,foo),/(verify_any,5))"}},
+				wantSuccess: true,
+			},
+			{ // Invalid signature
+				query:       `catch(verify_any(ed25519, [[1]], [1], foo, _), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(type_error(list,foo),/(verify_any,5))"}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
+						interpreter.Register5(engine.NewAtom("verify_any"), VerifyAny)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise {
+							return cont(env)
+						})
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestEthVerifyAddress(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		program := `verify(Hash, Sig, Address) :-
+			hex_bytes(Hash, H),
+			hex_bytes(Sig, S),
+			eth_verify_address(H, S, Address).`
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{ // EIP-55 checksummed address
+				program:     program,
+				query:       `verify('a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2', '6f267aa61dda3067280942299172e2b9f6b4252b492e7c0343ecce6e7edfe0b24110a805ba6777e4303501ea9dfae133855f4ee2e34fd0444bb23d11f296eef301', '0x2c7536E3605D9C16a7a3D7b1898e529396a65c23').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{ // lower case address
+				program:     program,
+				query:       `verify('a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2', '6f267aa61dda3067280942299172e2b9f6b4252b492e7c0343ecce6e7edfe0b24110a805ba6777e4303501ea9dfae133855f4ee2e34fd0444bb23d11f296eef301', '0x2c7536e3605d9c16a7a3d7b1898e529396a65c23').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{ // recovery id given as 28
+				program:     program,
+				query:       `verify('a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2', '6f267aa61dda3067280942299172e2b9f6b4252b492e7c0343ecce6e7edfe0b24110a805ba6777e4303501ea9dfae133855f4ee2e34fd0444bb23d11f296eef31c', '0x2c7536E3605D9C16a7a3D7b1898e529396a65c23').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{ // another signer
+				program:     program,
+				query:       `verify('a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2', '6f267aa61dda3067280942299172e2b9f6b4252b492e7c0343ecce6e7edfe0b24110a805ba6777e4303501ea9dfae133855f4ee2e34fd0444bb23d11f296eef301', '0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed').`,
+				wantSuccess: false,
+			},
+			{ // wrong recovery id
+				program:     program,
+				query:       `verify('a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2', '6f267aa61dda3067280942299172e2b9f6b4252b492e7c0343ecce6e7edfe0b24110a805ba6777e4303501ea9dfae133855f4ee2e34fd0444bb23d11f296eef300', '0x2c7536E3605D9C16a7a3D7b1898e529396a65c23').`,
+				wantSuccess: false,
+			},
+			{ // another hash
+				program:     program,
+				query:       `verify('b1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2', '6f267aa61dda3067280942299172e2b9f6b4252b492e7c0343ecce6e7edfe0b24110a805ba6777e4303501ea9dfae133855f4ee2e34fd0444bb23d11f296eef301', '0x2c7536E3605D9C16a7a3D7b1898e529396a65c23').`,
+				wantSuccess: false,
+			},
+			{ // invalid EIP-55 checksum
+				program:     program,
+				query:       `verify('a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2', '6f267aa61dda3067280942299172e2b9f6b4252b492e7c0343ecce6e7edfe0b24110a805ba6777e4303501ea9dfae133855f4ee2e34fd0444bb23d11f296eef301', '0x2c7536e3605D9C16a7a3D7b1898e529396a65c23').`,
+				wantError:   fmt.Errorf("eth_verify_address/3: invalid address checksum: 0x2c7536e3605D9C16a7a3D7b1898e529396a65c23"),
+				wantSuccess: false,
+			},
+			{ // malformed address
+				program:     program,
+				query:       `verify('a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2', '6f267aa61dda3067280942299172e2b9f6b4252b492e7c0343ecce6e7edfe0b24110a805ba6777e4303501ea9dfae133855f4ee2e34fd0444bb23d11f296eef301', '2c7536E3605D9C16a7a3D7b1898e529396a65c23').`,
+				wantError:   fmt.Errorf("eth_verify_address/3: invalid address: 2c7536E3605D9C16a7a3D7b1898e529396a65c23, should be a 0x prefixed 20-byte hexadecimal address"),
+				wantSuccess: false,
+			},
+			{ // malformed signature
+				program:     program,
+				query:       `verify('a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2', '6f267aa61dda3067280942299172e2b9f6b4252b492e7c0343ecce6e7edfe0b24110a805ba6777e4303501ea9dfae133855f4ee2e34fd0444bb23d11f296eef3', '0x2c7536E3605D9C16a7a3D7b1898e529396a65c23').`,
+				wantError:   fmt.Errorf("eth_verify_address/3: failed to recover public key: invalid recoverable signature length: 64, expected 65"),
+				wantSuccess: false,
+			},
+			{ // invalid recovery id
+				program:     program,
+				query:       `verify('a1de988600a42c4b4ab089b619297c17d53cffae5d5120d82d8a92d0bb3b78f2', '6f267aa61dda3067280942299172e2b9f6b4252b492e7c0343ecce6e7edfe0b24110a805ba6777e4303501ea9dfae133855f4ee2e34fd0444bb23d11f296eef302', '0x2c7536E3605D9C16a7a3D7b1898e529396a65c23').`,
+				wantError:   fmt.Errorf("eth_verify_address/3: failed to recover public key: invalid recovery id: 2"),
+				wantSuccess: false,
+			},
+			{ // malformed hash
+				program:     program,
+				query:       `verify('a1de988600a42c4b4ab089b6', '6f267aa61dda3067280942299172e2b9f6b4252b492e7c0343ecce6e7edfe0b24110a805ba6777e4303501ea9dfae133855f4ee2e34fd0444bb23d11f296eef301', '0x2c7536E3605D9C16a7a3D7b1898e529396a65c23').`,
+				wantError:   fmt.Errorf("eth_verify_address/3: invalid hash length: 12, expected 32"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("eth_verify_address"), EthVerifyAddress)
+						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestPubkeyNormalize(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		program := `normalize(In, Out, Options) :-
+			hex_bytes(In, I),
+			pubkey_normalize(I, O, Options),
+			hex_bytes(Out, O).
+		consistent(Key, Msg, Sig, Alg) :-
+			hex_bytes(Key, K), hex_bytes(Msg, M), hex_bytes(Sig, S),
+			pubkey_normalize(K, U, [type(Alg), format(uncompressed)]),
+			pubkey_normalize(U, C, [type(Alg), format(compressed)]),
+			C = K,
+			ecdsa_verify(K, M, S, [encoding(octet), type(Alg)]),
+			ecdsa_verify(U, M, S, [encoding(octet), type(Alg)]).`
+		cases := []struct {
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{ // secp256r1 key decompressed
+				query:       `normalize('0213c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b', Out, format(uncompressed)).`,
+				wantResult:  []types.TermResults{{"Out": "'0413c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b040913fa78a2b2a4ba5011d54645193943da21cddbe423df97f0fba67e07f99a'"}},
+				wantSuccess: true,
+			},
+			{ // secp256r1 key compressed by default
+				query:       `normalize('0413c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b040913fa78a2b2a4ba5011d54645193943da21cddbe423df97f0fba67e07f99a', Out, []).`,
+				wantResult:  []types.TermResults{{"Out": "'0213c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b'"}},
+				wantSuccess: true,
+			},
+			{ // secp256k1 key compressed
+				query:       `normalize('046b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71ce5f378de71b8f939a72a523695049eb999e644e0cce94fc3943297682ddd0e42', Out, [type(secp256k1), format(compressed)]).`,
+				wantResult:  []types.TermResults{{"Out": "'026b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71c'"}},
+				wantSuccess: true,
+			},
+			{ // secp256k1 key decompressed
+				query:       `normalize('026b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71c', Out, [type(secp256k1), format(uncompressed)]).`,
+				wantResult:  []types.TermResults{{"Out": "'046b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71ce5f378de71b8f939a72a523695049eb999e644e0cce94fc3943297682ddd0e42'"}},
+				wantSuccess: true,
+			},
+			{ // secp256k1 key already in the requested form
+				query:       `normalize('026b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71c', Out, type(secp256k1)).`,
+				wantResult:  []types.TermResults{{"Out": "'026b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71c'"}},
+				wantSuccess: true,
+			},
+			{ // both forms of a secp256r1 key verify the same signature
+				query:       `consistent('0213c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b', 'e50c26e89f734b2ee12041ff27874c901891f74a0f0cf470333312a3034ce3be', '30450220099e6f9dd218e0e304efa7a4224b0058a8e3aec73367ec239bee4ed8ed7d85db022100b504d3d0d2e879b04705c0e5a2b40b0521a5ab647ea207bd81134e1a4eb79e47', secp256r1).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{ // both forms of a secp256k1 key verify the same signature
+				query:       `consistent('026b5450187ee9c63ba9e42cb6018d8469c903aca116178e223de76e49fe63b71c', 'dece063885d3648078f903b6a3e8989f649dc3368cd9c8d69755ed9dcb6a0995', '304402201448201bb4408549b0997f4b9ad9ed36f3cf8bb9c433fc7f3ba48c6b6e39476e022053f7d056f7ffeab9a79f3a36bc2ba969ddd530a3a1495d1ed7bba00039820223', secp256k1).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{ // uncompressed key not on the curve
+				query:       `catch(normalize('0413c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b040913fa78a2b2a4ba5011d54645193943da21cddbe423df97f0fba67e07f99b', _, []), error(E, _), R = caught).`,
+				wantResult:  []types.TermResults{{"E": "domain_error(public_key,[4,19,200,66,107,228,113,229,85,6,247,206,79,125,245,87,164,46,49,13,240,159,146,235,115,44,163,8,94,121,124,239,155,4,9,19,250,120,162,178,164,186,80,17,213,70,69,25,57,67,218,33,205,219,228,35,223,151,240,251,166,126,7,249,155])", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // key of another curve
+				query:       `catch(normalize('0413c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b040913fa78a2b2a4ba5011d54645193943da21cddbe423df97f0fba67e07f99a', _, type(secp256k1)), error(domain_error(D, _), _), R = caught).`,
+				wantResult:  []types.TermResults{{"D": "public_key", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // key of invalid length
+				query:       `catch(normalize('0213c8', _, []), error(E, _), R = caught).`,
+				wantResult:  []types.TermResults{{"E": "domain_error(public_key,[2,19,200])", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // unsupported format
+				query:       `catch(normalize('0213c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b', _, format(raw)), error(E, _), R = caught).`,
+				wantResult:  []types.TermResults{{"E": "domain_error(key_format,raw)", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // unsupported algorithm
+				query:       `catch(normalize('0213c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b', _, type(ed25519)), error(E, _), R = caught).`,
+				wantResult:  []types.TermResults{{"E": "domain_error(algorithm,ed25519)", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // unknown option
+				query:       `catch(normalize('0213c8426be471e55506f7ce4f7df557a42e310df09f92eb732ca3085e797cef9b', _, encoding(octet)), error(E, _), R = caught).`,
+				wantResult:  []types.TermResults{{"E": "domain_error(option,encoding(octet))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // unbound key
+				query:       `catch(pubkey_normalize(_, _, []), error(E, _), R = caught).`,
+				wantResult:  []types.TermResults{{"E": "instantiation_error", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{ // key which is not a list of bytes
+				query:       `catch(pubkey_normalize([2, 256], _, []), error(E, _), R = caught).`,
+				wantResult:  []types.TermResults{{"E": "type_error(byte,256)", "R": "caught"}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("pubkey_normalize"), PubkeyNormalize)
+						interpreter.Register4(engine.NewAtom("ecdsa_verify"), ECDSAVerify)
+						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
+						interpreter.Register2(engine.NewAtom("="), engine.Unify)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestEDDSAVerifyBatch(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		program := `e1(sig(PubKey, '9b038f8ef6918cbb56040dfda401b56bb1ce79c472e7736e8677758c83367a9d', Sig)) :-
+			hex_bytes('53167ac3fc4b720daa45b04fc73fe752578fa23a10048422d6904b7f4f7bba5a', PubKey),
+			hex_bytes('889bcfd331e8e43b5ebf430301dffb6ac9e2fce69f6227b43552fe3dc8cc1ee00c1cc53452a8712e9d5f80086dff8cf4999c1b93ed6c6e403c09334cb61ddd0b', Sig).
+		e2(sig(PubKey, '6f6b7034', Sig)) :-
+			hex_bytes('00d05a1d1ea251396d557afbd4588b3c6d99dbeb972fed10a32562ea26dcdcfa', PubKey),
+			hex_bytes('b9f8ee11aacead9084945e27d9fd9b4f231dd9f5fe920d0ce0e3852276b160f87899a40df02919778b37d9d0ae78e560c7514952500a266edd58b7b925ec3903', Sig).
+		e3(sig(PubKey, '6f6b7035', Sig)) :-
+			hex_bytes('00d05a1d1ea251396d557afbd4588b3c6d99dbeb972fed10a32562ea26dcdcfa', PubKey),
+			hex_bytes('b9f8ee11aacead9084945e27d9fd9b4f231dd9f5fe920d0ce0e3852276b160f87899a40df02919778b37d9d0ae78e560c7514952500a266edd58b7b925ec3903', Sig).
+		batch(valid, [E1, E2]) :- e1(E1), e2(E2).
+		batch(invalid, [E1, E3, E2]) :- e1(E1), e2(E2), e3(E3).
+		verify(Batch, Options) :- batch(Batch, Entries), eddsa_verify_batch(Entries, Options).`
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				program:     program,
+				query:       `verify(valid, [encoding(hex), type(ed25519)]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `verify(invalid, []).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `verify(invalid, [results(Results)]).`,
+				wantResult:  []types.TermResults{{"Results": "[true,false,true]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `eddsa_verify_batch([], []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `eddsa_verify_batch([foo], []).`,
+				wantError:   fmt.Errorf("eddsa_verify_batch/2: invalid entry type: engine.Atom, should be sig(PubKey, Data, Signature)"),
+				wantSuccess: false,
+			},
+			{
+				query: `catch(eddsa_verify_batch([sig([1,2], 'zz', [3])], []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(domain_error(encoding(hex),zz),/(eddsa_verify_batch,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(eddsa_verify_batch([sig([1,2], '00', [3])], []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(domain_error(public_key,[1,2]),/(eddsa_verify_batch,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(eddsa_verify_batch([], [type(secp256k1)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(domain_error(algorithm,secp256k1),/(eddsa_verify_batch,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(eddsa_verify_batch([], [encoding(octet), result(Results)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Results": "_1", "E": "error(domain_error(option,result(_1)),/(eddsa_verify_batch,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("eddsa_verify_batch"), EDDSAVerifyBatch)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestHexShortcuts(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `bytes_hex([44, 38, 180, 107], Hex).`,
+				wantResult:  []types.TermResults{{"Hex": "'2c26b46b'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bytes_hex([], Hex).`,
+				wantResult:  []types.TermResults{{"Hex": "''"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bytes_hex([44, 38, 180, 107], '2c26b46b').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bytes_hex([44, 38, 180, 107], '2C26B46B').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(bytes_hex([44, 256], _), error(E, _), true).`,
+				wantResult:  []types.TermResults{{"E": "type_error(byte,256)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(bytes_hex(foo, _), error(E, _), true).`,
+				wantResult:  []types.TermResults{{"E": "type_error(list,foo)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes_atom('2C26b46b', Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[44,38,180,107]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes_atom('', Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hex_bytes_atom('2c26', [44, 39]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(hex_bytes_atom(_, [44, 38]), error(E, _), true).`,
+				wantResult:  []types.TermResults{{"E": "instantiation_error"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(hex_bytes_atom([44, 38], _), error(E, _), true).`,
+				wantResult:  []types.TermResults{{"E": "type_error(atom,[44,38])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(hex_bytes_atom('2c2g', _), error(E, C), true).`,
+				wantResult:  []types.TermResults{{"E": "domain_error(encoding(hex),'2c2g')", "C": "/(hex_bytes_atom,2)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(hex_bytes_atom('2c2', _), error(E, C), true).`,
+				wantResult:  []types.TermResults{{"E": "domain_error(encoding(hex),'2c2')", "C": "context(/(hex_bytes_atom,2),'odd number of hex digits')"}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("bytes_hex"), BytesHex)
+						interpreter.Register2(engine.NewAtom("hex_bytes_atom"), HexBytesAtom)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestBase64URLBytes(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `base64url_bytes('eyJhbGciOiJFZERTQSJ9', Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[123,34,97,108,103,34,58,34,69,100,68,83,65,34,125]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes('-_A', Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[251,240]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes(B64, [251, 240]).`,
+				wantResult:  []types.TermResults{{"B64": "'-_A'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes(B64, [104, 105, 63]).`,
+				wantResult:  []types.TermResults{{"B64": "aGk_"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes('', Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes(B64, []).`,
+				wantResult:  []types.TermResults{{"B64": "''"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes(aGk_, [104, 105, 63]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes(aGk_, [104, 105]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `base64url_bytes('-_A=', Bytes).`,
+				wantError:   fmt.Errorf("base64url_bytes/2: failed decode base64url: illegal base64 data at input byte 3"),
+				wantSuccess: false,
+			},
+			{
+				query:       `base64url_bytes('ab+/', Bytes).`,
+				wantError:   fmt.Errorf("base64url_bytes/2: failed decode base64url: illegal base64 data at input byte 2"),
+				wantSuccess: false,
+			},
+			{
+				query:       `base64url_bytes(B64, [104, 105, 63], [as(string)]).`,
+				wantResult:  []types.TermResults{{"B64": "[a,'G',k,'_']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes(B64, [104, 105, 63], [as(codes)]).`,
+				wantResult:  []types.TermResults{{"B64": "[97,71,107,95]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes(B64, [], [as(codes)]).`,
+				wantResult:  []types.TermResults{{"B64": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes("aGk_", Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[104,105,63]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes([97, 71, 107, 95], Bytes, [as(atom)]).`,
+				wantResult:  []types.TermResults{{"Bytes": "[104,105,63]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes("aGk_", [104, 105, 63], []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes("ab+/", Bytes, [as(string)]).`,
+				wantError:   fmt.Errorf("base64url_bytes/3: failed decode base64url: illegal base64 data at input byte 2"),
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(base64url_bytes(B64, [104], [as(chars)]), E, R = caught).`,
+				wantResult:  []types.TermResults{{"B64": "_1", "E": "error(domain_error(text_type,chars),/(base64url_bytes,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `base64url_bytes(42, Bytes).`,
+				wantError:   fmt.Errorf("base64url_bytes/2: invalid base64url type: engine.Integer, should be Atom, list of characters or codes, or Variable"),
+				wantSuccess: false,
+			},
+			{
+				query:       `base64url_bytes(B64, [104, 256]).`,
+				wantError:   fmt.Errorf("base64url_bytes/2: failed convert list into bytes: invalid integer value in list at position 2: 256 is out of byte range (0-255)"),
+				wantSuccess: false,
+			},
+			{
+				query:       `base64url_bytes(B64, Bytes).`,
+				wantError:   fmt.Errorf("base64url_bytes/2: failed convert list into bytes: term should be a List, given engine.Variable"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("base64url_bytes"), Base64URLBytes)
+						interpreter.Register3(engine.NewAtom("base64url_bytes"), Base64URLBytesWithOptions)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestSHA3Hash(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `sha3_hash('abc', Hash, [encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Hash": "'3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sha3_hash([97, 98, 99], Hash, []).`,
+				wantResult:  []types.TermResults{{"Hash": "[58,152,93,167,79,226,37,178,4,92,23,45,107,211,144,189,133,95,8,110,62,157,82,91,70,191,226,69,17,67,21,50]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sha3_hash('', Hash, [algorithm(sha3_256), encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Hash": "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a"}},
+				wantSuccess: true,
+			},
+			{ // and not c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470, the Keccak-256 hash of the empty string
+				query:       `sha3_hash([], Hash, [encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Hash": "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sha3_hash('abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq', Hash, [encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Hash": "'41c0dba2a9d6240849100376a8235e2c82e1b9998a999e21db32dd97496d3376'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sha3_hash('abc', Hash, [algorithm(sha3_512), encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Hash": "b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sha3_hash('', Hash, [algorithm(sha3_512), encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Hash": "a69f73cca23a9ac5c8b567dc185a756e97c982164fe25859e0d1dcc1475c80a615b2123af1f5f94c11e3e9402c3ac558f500199d95b6d3e301758586281dcd26"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sha3_hash('abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq', Hash, [algorithm(sha3_512), encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Hash": "'04a371e84ecfb5b8b77cb48610fca8182dd457ce6f326a0fd3d7ec2f1e91636dee691fbe0c985302ba1b0d8dc78c086346b533b49c030d99a27daf1139d6e75e'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sha3_hash('abc', [58|_], []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sha3_hash('abc', '3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431533', [encoding(hex)]).`,
+				wantSuccess: false,
+			},
+			{
+				query: `catch(sha3_hash(Data, Hash, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Data": "_1", "Hash": "_1", "E": "error(instantiation_error,/(sha3_hash,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(sha3_hash([256], Hash, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "E": "error(type_error(byte,256),/(sha3_hash,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(sha3_hash('abc', Hash, [algorithm(keccak256)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "E": "error(domain_error(algorithm,keccak256),/(sha3_hash,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(sha3_hash('abc', Hash, [encoding(base64)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "E": "error(domain_error(encoding,base64),/(sha3_hash,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(sha3_hash('abc', Hash, [size(256)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "E": "error(domain_error(option,size(256)),/(sha3_hash,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("sha3_hash"), SHA3Hash)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestBlake2b(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{ // RFC 7693, appendix A
+				query:       `blake2b('abc', Hash, [encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Hash": "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `blake2b([], Hash, [size(64), encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Hash": "'786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce'"}},
+				wantSuccess: true,
+			},
+			{ // BLAKE2b-256, which is not a truncated BLAKE2b-512
+				query:       `blake2b([97, 98, 99], Hash, [size(32), encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Hash": "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `blake2b('abc', Hash, [size(4)]).`,
+				wantResult:  []types.TermResults{{"Hash": "[99,144,98,72]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `blake2b('', Hash, [size(1), encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Hash": "'2e'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `blake2b([72, 101, 108, 108, 111], Hash, [size(16), key([1, 2, 3, 4]), encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Hash": "c6acf2003e74938d6a1f0a084e409101"}},
+				wantSuccess: true,
+			},
+			{ // keyed test vector of the reference implementation, with the key 0x00..0x3f and the input 0x00 0x01 0x02
+				query: `Key = [0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44, 45, 46, 47, 48, 49, 50, 51, 52, 53, 54, 55, 56, 57, 58, 59, 60, 61, 62, 63],
+				        blake2b([0, 1, 2], Hash, [key(Key), encoding(hex)]).`,
+				wantResult: []types.TermResults{{
+					"Key":  "[0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32,33,34,35,36,37,38,39,40,41,42,43,44,45,46,47,48,49,50,51,52,53,54,55,56,57,58,59,60,61,62,63]",
+					"Hash": "'33d0825dddf7ada99b0e7e307104ad07ca9cfd9692214f1561356315e784f3e5a17e364ae9dbb14cb2036df932b77f4b292761365fb328de7afdc6d8998f5fc1'",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query:       `blake2b('abc', [186|_], [key([])]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `blake2b('abc', [99, 144, 98, 73], [size(4)]).`,
+				wantSuccess: false,
+			},
+			{
+				query: `catch(blake2b(Data, Hash, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Data": "_1", "Hash": "_1", "E": "error(instantiation_error,/(blake2b,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(blake2b('abc', Hash, [size(S)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "S": "_1", "E": "error(instantiation_error,/(blake2b,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(blake2b('abc', Hash, [size('32')]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "E": "error(type_error(integer,'32'),/(blake2b,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(blake2b('abc', Hash, [size(0)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "E": "error(domain_error(size,0),/(blake2b,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(blake2b('abc', Hash, [size(65)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "E": "error(domain_error(size,65),/(blake2b,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `Key = [0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44, 45, 46, 47, 48, 49, 50, 51, 52, 53, 54, 55, 56, 57, 58, 59, 60, 61, 62, 63, 64],
+				        catch(blake2b('abc', Hash, [key(Key)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Key":  "[0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32,33,34,35,36,37,38,39,40,41,42,43,44,45,46,47,48,49,50,51,52,53,54,55,56,57,58,59,60,61,62,63,64]",
+					"Hash": "_1",
+					"E":    "error(domain_error(key,[0,1,2,3,4,5,6,7,8,9,10,11,12,13,14,15,16,17,18,19,20,21,22,23,24,25,26,27,28,29,30,31,32,33,34,35,36,37,38,39,40,41,42,43,44,45,46,47,48,49,50,51,52,53,54,55,56,57,58,59,60,61,62,63,64]),/(blake2b,3))",
+					"R":    "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(blake2b('abc', Hash, [key(secret)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "E": "error(type_error(list,secret),/(blake2b,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(blake2b('abc', Hash, [key([1, 256])]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "E": "error(type_error(byte,256),/(blake2b,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(blake2b('abc', Hash, [encoding(base64)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "E": "error(domain_error(encoding,base64),/(blake2b,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(blake2b('abc', Hash, [algorithm(blake2s)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Hash": "_1", "E": "error(domain_error(option,algorithm(blake2s)),/(blake2b,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("blake2b"), Blake2b)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestHashEqual(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `hash_equal([1, 2, 3], [1, 2, 3]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hash_equal([], []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hash_equal('0a0B0c', '0A0b0C').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sha_hash('Hello OKP4', Hash), hash_equal(Hash, [61, 176, 118, 250, 188, 25, 14, 176, 28, 152, 45, 110, 162, 9, 87, 24, 84, 97, 196, 200, 149, 224, 44, 6, 244, 232, 241, 98, 18, 187, 125, 240]).`,
+				wantResult:  []types.TermResults{{"Hash": "[61,176,118,250,188,25,14,176,28,152,45,110,162,9,87,24,84,97,196,200,149,224,44,6,244,232,241,98,18,187,125,240]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hash_equal([1, 2, 3], [1, 2, 4]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `hash_equal([1, 2, 3], [1, 2]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `hash_equal('0a0b0c', '0a0b').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `hash_equal([], [0]).`,
+				wantSuccess: false,
+			},
+			{
+				query: `catch(hash_equal(A, [1]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"A": "_1", "E": "error(instantiation_error,/(hash_equal,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hash_equal([1], B), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"B": "_1", "E": "error(instantiation_error,/(hash_equal,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hash_equal([1, 2], '0102'), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(type_error(list,'0102'),/(hash_equal,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hash_equal('0102', [1, 2]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(type_error(atom,[1,2]),/(hash_equal,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hash_equal('0102', []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(type_error(atom,[]),/(hash_equal,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hash_equal(42, [1]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(type_error(list,42),/(hash_equal,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hash_equal([1, 256], [1]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(type_error(byte,256),/(hash_equal,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(hash_equal('0102', 'zz'), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(domain_error(encoding(hex),zz),/(hash_equal,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("hash_equal"), HashEqual)
+						interpreter.Register2(engine.NewAtom("sha_hash"), SHAHash)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
 									} else {
 										So(sols.Err(), ShouldBeNil)
 
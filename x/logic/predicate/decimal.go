@@ -0,0 +1,185 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+
+	sdkmath "cosmossdk.io/math"
+	"github.com/ichiban/prolog/engine"
+)
+
+// AtomIntOverflow is the term used to indicate an overflow in an evaluation error.
+var AtomIntOverflow = engine.NewAtom("int_overflow")
+
+// DecAdd is a predicate which adds two fixed-point decimals, with the semantics of the Cosmos SDK decimals.
+//
+// The signature is as follows:
+//
+//	dec_add(+X, +Y, -Sum) is det
+//
+// Where:
+//   - X and Y are the decimals to add, given either as an Integer or as an Atom holding the decimal representation of
+//     a number with at most 18 digits after the decimal point, e.g. '0.5' or '-12.000000000000000001'.
+//   - Sum is X + Y, as an Atom holding its decimal representation with 18 digits after the decimal point.
+//
+// The decimals being fixed-point numbers of 18 digits after the decimal point, as sdk.Dec, the computations are exact,
+// unlike the ones on floats. A result exceeding the range of the decimals, i.e. 2^315 units of 10^-18, raises a
+// catchable error(evaluation_error(int_overflow), Context) exception.
+//
+// Examples:
+//
+//	# Add two decimals.
+//	- dec_add('0.1', '0.2', Sum).
+func DecAdd(vm *engine.VM, x, y, sum engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return decOperation(vm, x, y, sum, sdkmath.LegacyDec.Add, "dec_add", cont, env)
+}
+
+// DecMul is a predicate which multiplies two fixed-point decimals, with the semantics of the Cosmos SDK decimals.
+//
+// The signature is as follows:
+//
+//	dec_mul(+X, +Y, -Product) is det
+//
+// Where:
+//   - X and Y are the decimals to multiply, given as for dec_add/3.
+//   - Product is X * Y, as an Atom holding its decimal representation with 18 digits after the decimal point.
+//
+// The product is rounded to 18 digits after the decimal point with the banker's rounding, i.e. to the nearest decimal
+// and, on a tie, to the one whose last digit is even. A result exceeding the range of the decimals raises a catchable
+// error(evaluation_error(int_overflow), Context) exception.
+//
+// Examples:
+//
+//	# Compute the voting power of a share of a stake.
+//	- dec_mul('1000000', '0.334', Product).
+func DecMul(vm *engine.VM, x, y, product engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return decOperation(vm, x, y, product, sdkmath.LegacyDec.Mul, "dec_mul", cont, env)
+}
+
+// DecDiv is a predicate which divides two fixed-point decimals, with the semantics of the Cosmos SDK decimals.
+//
+// The signature is as follows:
+//
+//	dec_div(+X, +Y, -Quotient) is det
+//
+// Where:
+//   - X and Y are the dividend and the divisor, given as for dec_add/3.
+//   - Quotient is X / Y, as an Atom holding its decimal representation with 18 digits after the decimal point.
+//
+// The quotient is rounded to 18 digits after the decimal point with the banker's rounding, as for dec_mul/3. A division
+// by zero raises a catchable error(evaluation_error(zero_divisor), Context) exception, and a result exceeding the
+// range of the decimals a catchable error(evaluation_error(int_overflow), Context) exception.
+//
+// Examples:
+//
+//	# Compute the turnout of a vote.
+//	- dec_div(3341, 10000, Turnout).
+func DecDiv(vm *engine.VM, x, y, quotient engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		a, b, err := termsToDecs(x, y, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("dec_div/3: %w", err))
+		}
+		if b.IsZero() {
+			return engine.Error(engine.NewException(
+				AtomError.Apply(AtomEvaluationError.Apply(AtomZeroDivisor), decIndicator("dec_div")), env))
+		}
+
+		return decUnify(vm, quotient, func() sdkmath.LegacyDec { return a.Quo(b) }, "dec_div", cont, env)
+	})
+}
+
+// DecCmp is a predicate which compares two fixed-point decimals.
+//
+// The signature is as follows:
+//
+//	dec_cmp(?Order, +X, +Y) is det
+//
+// Where:
+//   - Order is the result of the comparison, among the atoms <, = and >, as for compare/3.
+//   - X and Y are the decimals to compare, given as for dec_add/3.
+//
+// The decimals are compared by their value, whatever their representation, e.g. 1, '1' and '1.000' are equal.
+//
+// Examples:
+//
+//	# Check that a turnout reaches the quorum.
+//	- dec_div(3341, 10000, Turnout), dec_cmp(>, Turnout, '0.334').
+func DecCmp(vm *engine.VM, order, x, y engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		a, b, err := termsToDecs(x, y, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("dec_cmp/3: %w", err))
+		}
+
+		return engine.Unify(vm, order, engine.NewAtom([]string{"<", "=", ">"}[a.BigInt().Cmp(b.BigInt())+1]), cont, env)
+	})
+}
+
+// decOperation unifies the given result with the decimal representation of the given operation applied to the
+// decimals x and y.
+func decOperation(vm *engine.VM, x, y, result engine.Term, op func(x, y sdkmath.LegacyDec) sdkmath.LegacyDec, name string,
+	cont engine.Cont, env *engine.Env,
+) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		a, b, err := termsToDecs(x, y, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s/3: %w", name, err))
+		}
+
+		return decUnify(vm, result, func() sdkmath.LegacyDec { return op(a, b) }, name, cont, env)
+	})
+}
+
+// decUnify unifies the given result with the decimal representation of the given computation, a computation exceeding
+// the range of the decimals, which makes them panic, raising an int_overflow evaluation error.
+func decUnify(vm *engine.VM, result engine.Term, compute func() sdkmath.LegacyDec, name string, cont engine.Cont,
+	env *engine.Env,
+) (promise *engine.Promise) {
+	defer func() {
+		if r := recover(); r != nil {
+			promise = engine.Error(engine.NewException(
+				AtomError.Apply(AtomEvaluationError.Apply(AtomIntOverflow), decIndicator(name)), env))
+		}
+	}()
+	r := compute()
+
+	return engine.Unify(vm, result, engine.NewAtom(r.String()), cont, env)
+}
+
+// decIndicator returns the predicate indicator of the decimal predicate of the given name, all being of arity 3.
+func decIndicator(name string) engine.Term {
+	return engine.NewAtom("/").Apply(engine.NewAtom(name), engine.Integer(3))
+}
+
+// termsToDecs converts the given operands into fixed-point decimals.
+func termsToDecs(x, y engine.Term, env *engine.Env) (sdkmath.LegacyDec, sdkmath.LegacyDec, error) {
+	a, err := termToDec(x, env)
+	if err != nil {
+		return sdkmath.LegacyDec{}, sdkmath.LegacyDec{}, err
+	}
+	b, err := termToDec(y, env)
+	if err != nil {
+		return sdkmath.LegacyDec{}, sdkmath.LegacyDec{}, err
+	}
+
+	return a, b, nil
+}
+
+// termToDec converts the given term into a fixed-point decimal. The term is expected to be either an Integer or an Atom
+// holding the decimal representation of a number with at most 18 digits after the decimal point.
+func termToDec(term engine.Term, env *engine.Env) (sdkmath.LegacyDec, error) {
+	switch t := env.Resolve(term).(type) {
+	case engine.Integer:
+		return sdkmath.LegacyNewDec(int64(t)), nil
+	case engine.Atom:
+		d, err := sdkmath.LegacyNewDecFromStr(t.String())
+		if err != nil {
+			return sdkmath.LegacyDec{}, fmt.Errorf("invalid decimal: %s, should be the decimal representation of a number "+
+				"with at most 18 digits after the decimal point", t)
+		}
+		return d, nil
+	default:
+		return sdkmath.LegacyDec{}, fmt.Errorf("invalid decimal type: %T, should be Integer or Atom", t)
+	}
+}
@@ -0,0 +1,176 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestDec(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `dec_add('0.1', '0.2', Sum).`,
+				wantResult:  []types.TermResults{{"Sum": "'0.300000000000000000'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `dec_add(1, '-1.5', Sum).`,
+				wantResult:  []types.TermResults{{"Sum": "'-0.500000000000000000'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `dec_add('0.1', '0.2', '0.3').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `dec_mul('1000000', '0.334', Product).`,
+				wantResult:  []types.TermResults{{"Product": "'334000.000000000000000000'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `dec_mul('0.000000000000000005', '0.1', Even), dec_mul('0.000000000000000015', '0.1', Odd).`,
+				wantResult:  []types.TermResults{{"Even": "'0.000000000000000000'", "Odd": "'0.000000000000000002'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `dec_div(1, 3, Third), dec_div(2, 3, TwoThirds).`,
+				wantResult:  []types.TermResults{{"Third": "'0.333333333333333333'", "TwoThirds": "'0.666666666666666667'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `dec_div(3341, 10000, Turnout), dec_cmp(>, Turnout, '0.334').`,
+				wantResult:  []types.TermResults{{"Turnout": "'0.334100000000000000'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `dec_cmp(Order, 1, '1.000').`,
+				wantResult:  []types.TermResults{{"Order": "="}},
+				wantSuccess: true,
+			},
+			{
+				query:       `dec_cmp(Order, '-0.000000000000000001', 0).`,
+				wantResult:  []types.TermResults{{"Order": "<"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `dec_cmp(<, '0.5', '0.25').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(dec_div(1, '0.0', Quotient), E, R = caught).`,
+				wantResult:  []types.TermResults{{"Quotient": "_1", "E": "error(evaluation_error(zero_divisor),/(dec_div,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(dec_add('40000000000000000000000000000000000000000000000000000000000000000000000000000', '40000000000000000000000000000000000000000000000000000000000000000000000000000', Sum), E, R = caught).`,
+				wantResult:  []types.TermResults{{"Sum": "_1", "E": "error(evaluation_error(int_overflow),/(dec_add,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(dec_mul('40000000000000000000000000000000000000000000000000000000000000000000000000000', 2, Product), E, R = caught).`,
+				wantResult:  []types.TermResults{{"Product": "_1", "E": "error(evaluation_error(int_overflow),/(dec_mul,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(dec_div('40000000000000000000000000000000000000000000000000000000000000000000000000000', '0.5', Quotient), E, R = caught).`,
+				wantResult:  []types.TermResults{{"Quotient": "_1", "E": "error(evaluation_error(int_overflow),/(dec_div,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `dec_add('0.0000000000000000001', 1, Sum).`,
+				wantError:   fmt.Errorf("dec_add/3: invalid decimal: 0.0000000000000000001, should be the decimal representation of a number with at most 18 digits after the decimal point"),
+				wantSuccess: false,
+			},
+			{
+				query:       `dec_cmp(Order, abc, 1).`,
+				wantError:   fmt.Errorf("dec_cmp/3: invalid decimal: abc, should be the decimal representation of a number with at most 18 digits after the decimal point"),
+				wantSuccess: false,
+			},
+			{
+				query:       `dec_mul(1.5, 1, Product).`,
+				wantError:   fmt.Errorf("dec_mul/3: invalid decimal type: engine.Float, should be Integer or Atom"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("dec_add"), DecAdd)
+						interpreter.Register3(engine.NewAtom("dec_mul"), DecMul)
+						interpreter.Register3(engine.NewAtom("dec_div"), DecDiv)
+						interpreter.Register3(engine.NewAtom("dec_cmp"), DecCmp)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -29,13 +29,21 @@ const DIDPrefix = "did:"
 //   - Components is a compound Term in the format did(Method, ID, Path, Query, Fragment), aligned with the [DID syntax],
 //     where: Method is The method name, ID is The method-specific identifier, Path is the path component, Query is the
 //     query component and Fragment is The fragment component.
-//     For any component not present, its value will be null and thus will be left as an uninstantiated variable.
+//     When decomposing a DID, any component not present is unified with an empty atom. When reconstructing a DID, any
+//     component left as an uninstantiated variable or given as an empty atom is omitted, so that decomposing a DID and
+//     reconstructing it from its components gives it back.
+//
+// The predicate fails for a URI which does not comply with the [DID syntax], including any URI not using the did
+// scheme, e.g. 'https://example.com/foo'.
 //
 // Examples:
 //
 //	# Decompose a DID into its components.
 //	- did_components('did:example:123456?versionId=1', did(Method, ID, Path, Query, Fragment)).
 //
+//	# Extract the method and the method-specific identifier of a did:key DID.
+//	- did_components('did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK', did(Method, ID, _, _, _)).
+//
 //	# Reconstruct a DID from its components.
 //	- did_components(DID, did('example', '123456', _, 'versionId=1', _42)).
 //
@@ -49,7 +57,7 @@ func DIDComponents(vm *engine.VM, did, components engine.Term, cont engine.Cont,
 	case engine.Atom:
 		parsedDid, err := godid.ParseDIDURL(t1.String())
 		if err != nil {
-			return engine.Error(fmt.Errorf("did_components/2: %w", err))
+			return engine.Bool(false)
 		}
 
 		terms, err := didToTerms(parsedDid)
@@ -91,12 +99,16 @@ func DIDComponents(vm *engine.VM, did, components engine.Term, cont engine.Cont,
 				}
 			},
 			func(segment engine.Atom) {
-				buf.WriteString("?")
-				buf.WriteString(url.PathEscape(segment.String()))
+				if segment != engine.NewAtom("") {
+					buf.WriteString("?")
+					buf.WriteString(url.PathEscape(segment.String()))
+				}
 			},
 			func(segment engine.Atom) {
-				buf.WriteString("#")
-				buf.WriteString(url.PathEscape(segment.String()))
+				if segment != engine.NewAtom("") {
+					buf.WriteString("#")
+					buf.WriteString(url.PathEscape(segment.String()))
+				}
 			},
 		}
 
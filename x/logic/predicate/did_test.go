@@ -1,4 +1,4 @@
-//nolint:gocognit
+//nolint:gocognit,lll
 package predicate
 
 import (
@@ -65,10 +65,31 @@ func TestDID(t *testing.T) {
 				wantResult: []types.TermResults{},
 				wantError:  fmt.Errorf("did_components/2: at least one argument must be instantiated"),
 			},
+			{
+				query: `did_components('did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK#z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK', X).`,
+				wantResult: []types.TermResults{{
+					"X": "did(key,z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK,'','',z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK)",
+				}},
+			},
+			{
+				query:      `did_components(X,did(key,z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK,_,_,_)).`,
+				wantResult: []types.TermResults{{"X": "'did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK'"}},
+			},
+			{
+				query:      `did_components(X,did(example,'123456','','','')).`,
+				wantResult: []types.TermResults{{"X": "'did:example:123456'"}},
+			},
+			{
+				query:      `did_components('did:example:123456/path', C), did_components(X, C).`,
+				wantResult: []types.TermResults{{"C": "did(example,'123456',path,'','')", "X": "'did:example:123456/path'"}},
+			},
+			{
+				query:      `did_components('https://example.com/foo',X).`,
+				wantResult: []types.TermResults{},
+			},
 			{
 				query:      `did_components('foo',X).`,
 				wantResult: []types.TermResults{},
-				wantError:  fmt.Errorf("did_components/2: invalid DID: input length is less than 7"),
 			},
 			{
 				query:      `did_components(123,X).`,
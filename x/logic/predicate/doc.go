@@ -1,2 +1,17 @@
 // Package predicate provides a set of predicates for use with the logic module.
+//
+// # Timing safety
+//
+// The predicates comparing secrets, or values derived from secrets, do so in constant time (see
+// util.ConstantTimeEqual), so that the time they take does not leak how close a forged input is to a valid one. This
+// is the case of the MAC checks of jwt_verify/3 (HS algorithms) and signed_token_verify/4, the only predicates
+// handling a secret key, and of hash_equal/2, which the programs computing a hash or a MAC by themselves shall use
+// rather than ==/2 to compare it with an expected one.
+//
+// The signature verification predicates (eddsa_verify/4, eddsa_verify_batch/2, ecdsa_verify/4, rsa_verify/4,
+// sshsig_verify/4, verify_any/5, eth_verify_address/3 and the asymmetric algorithms of jwt_verify/3) only handle
+// public data, and their failure paths are not required to be constant time: a malformed input raises an error while
+// a well-formed but invalid signature makes the predicate fail, which is observable anyway. Their comparisons of keys
+// and addresses are nonetheless done in constant time as well, so that all the comparison paths of the verification
+// predicates go through the same audited function.
 package predicate
@@ -0,0 +1,235 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+var (
+	// AtomBase64 is the term used to indicate the base64 encoding.
+	AtomBase64 = engine.NewAtom("base64")
+
+	// AtomCBOR is the term used to indicate the CBOR encoding.
+	AtomCBOR = engine.NewAtom("cbor")
+)
+
+// EncodedLength is a predicate which computes the number of bytes a term would occupy once encoded, without encoding
+// it, so that the size of a payload can be checked before paying for its encoding.
+//
+// The signature is as follows:
+//
+//	encoded_length(+Term, +Encoding, -Bytes) is det
+//
+// Where:
+//   - Term is the term to encode, according to Encoding (see below for details).
+//   - Encoding is the encoding, among hex, base64, json and cbor.
+//   - Bytes is the number of bytes of the encoded Term.
+//
+// For Encoding, the supported encodings are:
+//
+//   - hex: the hexadecimal encoding of Term, given as a list of bytes or as an Atom whose text is encoded, as given
+//     by hex_bytes/2.
+//   - base64: the standard base64 encoding of Term, with padding, as specified by RFC 4648, Term being given as for
+//     hex.
+//   - json: the JSON encoding of Term, given as a JSON term, as given by json_prolog/2.
+//   - cbor: the CBOR encoding of Term, given as a JSON term, where the integers, the strings, the arrays, the objects
+//     and the literals are encoded as the corresponding CBOR data items in their shortest form, as specified by the
+//     core deterministic encoding of RFC 8949, the integers which do not fit in 64 bits being encoded as bignums.
+//
+// An unbound Encoding raises an instantiation_error, and an unsupported one a domain_error(encoding, Encoding) error.
+//
+// Examples:
+//
+//	# Compute the length of the hexadecimal encoding of a list of bytes.
+//	- encoded_length([1, 2, 3], hex, Bytes).
+//
+//	# Reject a JSON payload larger than 1024 bytes.
+//	- encoded_length(json([foo-bar]), json, Bytes), Bytes =< 1024.
+func EncodedLength(vm *engine.VM, term, encoding, length engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "encoded_length/3"
+
+		enc, ok := env.Resolve(encoding).(engine.Atom)
+		if !ok {
+			return engine.Error(domainError(AtomEncoding, encoding, env))
+		}
+
+		var n int
+		switch enc {
+		case AtomHex, AtomBase64:
+			var data []byte
+			if a, ok := env.Resolve(term).(engine.Atom); ok && a != util.AtomEmptyList {
+				data = []byte(a.String())
+			} else {
+				bs, err := decodeBytes(ctx, term, AtomOctet, env)
+				if err != nil {
+					return engine.Error(err)
+				}
+				data = bs
+			}
+			n = 2 * len(data)
+			if enc == AtomBase64 {
+				n = 4 * ((len(data) + 2) / 3)
+			}
+		case AtomJSON, AtomCBOR:
+			var err error
+			if n, err = jsonTermLength(term, enc == AtomCBOR, env); err != nil {
+				return engine.Error(fmt.Errorf("%s: %w", functor, err))
+			}
+		default:
+			return engine.Error(domainError(AtomEncoding, enc, env))
+		}
+
+		return engine.Unify(vm, length, engine.Integer(n), cont, env)
+	})
+}
+
+// jsonTermLength returns the length of the JSON encoding of the given JSON term, as given by json_prolog/2, or of its
+// CBOR encoding if cbor is true.
+//
+//nolint:cyclop
+func jsonTermLength(term engine.Term, cbor bool, env *engine.Env) (int, error) {
+	switch t := env.Resolve(term).(type) {
+	case engine.Atom:
+		if cbor {
+			return cborHeadLength(uint64(len(t.String()))) + len(t.String()), nil
+		}
+		return jsonStringLength(t.String()), nil
+	case engine.Integer:
+		if cbor {
+			return cborIntegerLength(big.NewInt(int64(t))), nil
+		}
+		return len(strconv.FormatInt(int64(t), 10)), nil
+	case engine.Compound:
+		switch {
+		case t.Functor().String() == "." && t.Arity() == 2:
+			count, n := 0, 0
+			iter := engine.ListIterator{List: t, Env: env}
+			for iter.Next() {
+				l, err := jsonTermLength(iter.Current(), cbor, env)
+				if err != nil {
+					return 0, err
+				}
+				count, n = count+1, n+l
+			}
+			return n + jsonContainerLength(count, cbor), nil
+		case t.Functor() == AtomJSON:
+			attributes, err := ExtractJSONTerm(t, env)
+			if err != nil {
+				return 0, err
+			}
+			n := 0
+			for key, value := range attributes {
+				k, _ := jsonTermLength(engine.NewAtom(key), cbor, env)
+				l, err := jsonTermLength(value, cbor, env)
+				if err != nil {
+					return 0, err
+				}
+				n += k + l
+				if !cbor {
+					n++ // the colon between the key and the value
+				}
+			}
+			return n + jsonContainerLength(len(attributes), cbor), nil
+		case t.Functor() == AtomBig && t.Arity() == 1:
+			a, err := util.ResolveToAtom(env, t.Arg(0))
+			if err != nil {
+				return 0, err
+			}
+			r, ok := new(big.Int).SetString(a.String(), 10)
+			if !ok {
+				return 0, fmt.Errorf("invalid big integer: %s", a)
+			}
+			if cbor {
+				return cborIntegerLength(r), nil
+			}
+			return len(r.String()), nil
+		case cbor && (MakeBool(true).Compare(t, env) == 0 || MakeBool(false).Compare(t, env) == 0 ||
+			MakeNull().Compare(t, env) == 0 || MakeEmptyArray().Compare(t, env) == 0):
+			return 1, nil
+		case MakeBool(true).Compare(t, env) == 0, MakeNull().Compare(t, env) == 0:
+			return 4, nil
+		case MakeBool(false).Compare(t, env) == 0:
+			return 5, nil
+		case MakeEmptyArray().Compare(t, env) == 0:
+			return 2, nil
+		}
+		return 0, fmt.Errorf("invalid functor %s", t.Functor())
+	default:
+		return 0, fmt.Errorf("could not convert %s {%T} to json", t, t)
+	}
+}
+
+// jsonContainerLength returns the length of the delimiters of a JSON array or object of the given number of elements,
+// i.e. its brackets and its commas, or the length of the head of the corresponding CBOR data item if cbor is true.
+func jsonContainerLength(count int, cbor bool) int {
+	if cbor {
+		return cborHeadLength(uint64(count))
+	}
+	return 2 + max(count-1, 0)
+}
+
+// jsonStringLength returns the length of the JSON encoding of the given string, escaped as by the encoding/json
+// package, i.e. with the HTML characters escaped and the invalid UTF-8 bytes replaced by the replacement character.
+func jsonStringLength(s string) int {
+	n := 2
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			switch {
+			case b == '"' || b == '\\' || b == '\b' || b == '\f' || b == '\n' || b == '\r' || b == '\t':
+				n += 2
+			case b < 0x20 || b == '<' || b == '>' || b == '&':
+				n += 6
+			default:
+				n++
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || r == '\u2028' || r == '\u2029' {
+			n += 6
+		} else {
+			n += size
+		}
+		i += size
+	}
+	return n
+}
+
+// cborHeadLength returns the length of the head of a CBOR data item with the given argument, in its shortest form.
+func cborHeadLength(arg uint64) int {
+	switch {
+	case arg < 24:
+		return 1
+	case arg <= 0xff:
+		return 2
+	case arg <= 0xffff:
+		return 3
+	case arg <= 0xffffffff:
+		return 5
+	default:
+		return 9
+	}
+}
+
+// cborIntegerLength returns the length of the CBOR encoding of the given integer, either as an unsigned or a negative
+// integer if it fits in 64 bits, or as a bignum otherwise.
+func cborIntegerLength(i *big.Int) int {
+	arg := new(big.Int).Set(i)
+	if i.Sign() < 0 {
+		arg.Neg(arg).Sub(arg, big.NewInt(1))
+	}
+	if arg.IsUint64() {
+		return cborHeadLength(arg.Uint64())
+	}
+	content := len(arg.Bytes())
+	return 1 + cborHeadLength(uint64(content)) + content
+}
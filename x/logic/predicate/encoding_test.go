@@ -0,0 +1,221 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestEncodedLength(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `encoded_length([1, 2, 3], hex, L).`,
+				wantResult:  []types.TermResults{{"L": "6"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `encoded_length('hello', hex, L), hex_bytes(H, [104, 101, 108, 108, 111]), atom_length(H, L).`,
+				wantResult:  []types.TermResults{{"L": "10", "H": "'68656c6c6f'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `encoded_length([], hex, L).`,
+				wantResult:  []types.TermResults{{"L": "0"}},
+				wantSuccess: true,
+			},
+			{ // base64('foob') = 'Zm9vYg=='
+				query:       `encoded_length(foob, base64, L).`,
+				wantResult:  []types.TermResults{{"L": "8"}},
+				wantSuccess: true,
+			},
+			{ // base64('foobar') = 'Zm9vYmFy'
+				query:       `encoded_length([102, 111, 111, 98, 97, 114], base64, L).`,
+				wantResult:  []types.TermResults{{"L": "8"}},
+				wantSuccess: true,
+			},
+			{ // base64('f') = 'Zg=='
+				query:       `encoded_length([102], base64, L).`,
+				wantResult:  []types.TermResults{{"L": "4"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `encoded_length([], base64, L).`,
+				wantResult:  []types.TermResults{{"L": "0"}},
+				wantSuccess: true,
+			},
+			{
+				query: `T = json([foo-bar, n-42, l-[1, -2, 'a"b', @(true), @(false), @(null), @([])], o-json([]), b-big('123456789012345678901234567890')]),
+				        encoded_length(T, json, L), json_prolog(J, T), atom_length(J, L).`,
+				wantResult: []types.TermResults{{
+					"T": "json([foo-bar,n-42,l-[1,-2,'a\"b',@(true),@(false),@(null),@([])],o-json([]),b-big('123456789012345678901234567890')])",
+					"L": "99",
+					"J": "'{\"b\":123456789012345678901234567890,\"foo\":\"bar\",\"l\":[1,-2,\"a\\\\\"b\",true,false,null,[]],\"n\":42,\"o\":{}}'",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query:       `encoded_length('<a & b>\n\t\x01\', json, L), json_prolog(J, '<a & b>\n\t\x01\'), atom_length(J, L).`,
+				wantResult:  []types.TermResults{{"L": "34", "J": "'\"\\\\u003ca \\\\u0026 b\\\\u003e\\\\n\\\\t\\\\u0001\"'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `encoded_length('héllo', json, L).`,
+				wantResult:  []types.TermResults{{"L": "8"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `encoded_length([], json, L), json_prolog(J, []), atom_length(J, L).`,
+				wantResult:  []types.TermResults{{"L": "4", "J": "'\"[]\"'"}},
+				wantSuccess: true,
+			},
+			{ // 0x83 0x01 0x82 0x02 0x03 0x82 0x04 0x05 (RFC 8949, appendix A)
+				query:       `encoded_length([1, [2, 3], [4, 5]], cbor, L).`,
+				wantResult:  []types.TermResults{{"L": "8"}},
+				wantSuccess: true,
+			},
+			{ // 0xa2 0x61 0x61 0x01 0x61 0x62 0x82 0x02 0x03 (RFC 8949, appendix A)
+				query:       `encoded_length(json([a-1, b-[2, 3]]), cbor, L).`,
+				wantResult:  []types.TermResults{{"L": "9"}},
+				wantSuccess: true,
+			},
+			{ // 0x64 0x49 0x45 0x54 0x46 (RFC 8949, appendix A)
+				query:       `encoded_length('IETF', cbor, L).`,
+				wantResult:  []types.TermResults{{"L": "5"}},
+				wantSuccess: true,
+			},
+			{ // 0x1a 0x00 0x0f 0x42 0x40 (RFC 8949, appendix A)
+				query:       `encoded_length(1000000, cbor, L).`,
+				wantResult:  []types.TermResults{{"L": "5"}},
+				wantSuccess: true,
+			},
+			{ // 0x39 0x03 0xe7 (RFC 8949, appendix A)
+				query:       `encoded_length(-1000, cbor, L).`,
+				wantResult:  []types.TermResults{{"L": "3"}},
+				wantSuccess: true,
+			},
+			{ // 0x1b 0xff 0xff 0xff 0xff 0xff 0xff 0xff 0xff (RFC 8949, appendix A)
+				query:       `encoded_length(big('18446744073709551615'), cbor, L).`,
+				wantResult:  []types.TermResults{{"L": "9"}},
+				wantSuccess: true,
+			},
+			{ // 0xc2 0x49 0x01 0x00 0x00 0x00 0x00 0x00 0x00 0x00 0x00 (RFC 8949, appendix A)
+				query:       `encoded_length(big('18446744073709551616'), cbor, L).`,
+				wantResult:  []types.TermResults{{"L": "11"}},
+				wantSuccess: true,
+			},
+			{ // 0xc3 0x49 0x01 0x00 0x00 0x00 0x00 0x00 0x00 0x00 0x00 (RFC 8949, appendix A)
+				query:       `encoded_length(big('-18446744073709551617'), cbor, L).`,
+				wantResult:  []types.TermResults{{"L": "11"}},
+				wantSuccess: true,
+			},
+			{ // 0xf5 0xf6 0x80 0x74 followed by 20 bytes
+				query:       `encoded_length([@(true), @(null), @([]), 'abcdefghijklmnopqrst'], cbor, L).`,
+				wantResult:  []types.TermResults{{"L": "25"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(encoded_length([1], base32, L), E, R = caught).`,
+				wantResult:  []types.TermResults{{"L": "_1", "E": "error(domain_error(encoding,base32),/(encoded_length,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(encoded_length([1], Enc, L), E, R = caught).`,
+				wantResult:  []types.TermResults{{"Enc": "_1", "L": "_1", "E": "error(instantiation_error,/(encoded_length,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(encoded_length([256], hex, L), E, R = caught).`,
+				wantResult:  []types.TermResults{{"L": "_1", "E": "error(type_error(byte,256),/(encoded_length,3))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:     `encoded_length(foo(bar), json, L).`,
+				wantError: fmt.Errorf("encoded_length/3: invalid functor foo"),
+			},
+			{
+				query:     `encoded_length(json([a-[1, foo(b)]]), cbor, L).`,
+				wantError: fmt.Errorf("encoded_length/3: invalid functor foo"),
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("encoded_length"), EncodedLength)
+						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
+						interpreter.Register2(engine.NewAtom("json_prolog"), JSONProlog)
+						interpreter.Register2(engine.NewAtom("atom_length"), engine.AtomLength)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package predicate
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+var (
+	// AtomAtom is the term used to indicate the atom type in a type error.
+	AtomAtom = engine.NewAtom("atom")
+
+	// AtomInteger is the term used to indicate the integer type in a type error.
+	AtomInteger = engine.NewAtom("integer")
+
+	// AtomByte is the term used to indicate the byte type in a type error.
+	AtomByte = engine.NewAtom("byte")
+
+	// AtomAlgorithm is the term used to indicate the algorithm domain in a domain error.
+	AtomAlgorithm = engine.NewAtom("algorithm")
+
+	// AtomPublicKey is the term used to indicate the public key domain in a domain error.
+	AtomPublicKey = engine.NewAtom("public_key")
+
+	// AtomSignature is the term used to indicate the signature domain in a domain error.
+	AtomSignature = engine.NewAtom("signature")
+
+	// AtomPermissionError are terms with principal functor permission_error/3, used to indicate that an operation is not
+	// permitted.
+	AtomPermissionError = engine.NewAtom("permission_error")
+
+	// AtomAcyclicTerm is the term used to indicate the acyclic term type in a type error.
+	AtomAcyclicTerm = engine.NewAtom("acyclic_term")
+
+	// AtomRepresentationError are terms with principal functor representation_error/1, used to indicate that an
+	// implementation defined limit has been breached.
+	AtomRepresentationError = engine.NewAtom("representation_error")
+
+	// AtomContext are terms with principal functor context/2, used to give the context of an error with a message.
+	AtomContext = engine.NewAtom("context")
+)
+
+// errOddHexLength is the message of the error raised for an hexadecimal encoding of odd length, which cannot be
+// decoded into bytes.
+const errOddHexLength = "odd number of hex digits"
+
+// The functions below build the ISO error terms raised by the predicates, which, unlike the errors built from Go
+// errors, can be caught by catch/3. The context of the error is left unbound, and is filled by the interpreter with the
+// indicator of the predicate raising it.
+
+// typeError returns a type_error(Type, Culprit) error, or an instantiation_error if the culprit is not instantiated.
+func typeError(typ engine.Atom, culprit engine.Term, env *engine.Env) error {
+	if _, ok := env.Resolve(culprit).(engine.Variable); ok {
+		return engine.InstantiationError(env)
+	}
+	return engine.TypeError(typ, culprit, env)
+}
+
+// domainError returns a domain_error(Domain, Culprit) error, or an instantiation_error if the culprit is not
+// instantiated.
+func domainError(domain, culprit engine.Term, env *engine.Env) error {
+	if _, ok := env.Resolve(culprit).(engine.Variable); ok {
+		return engine.InstantiationError(env)
+	}
+	return engine.DomainError(domain, culprit, env)
+}
+
+// isoError returns an error(Formal, Context) error, the context being filled as for the other ISO errors, for the
+// errors which have no dedicated constructor.
+func isoError(formal engine.Term, env *engine.Env) engine.Exception {
+	e, _ := engine.InstantiationError(env).Term().(engine.Compound)
+	return engine.NewException(AtomError.Apply(formal, e.Arg(1)), env)
+}
+
+// acyclicTermError returns a type_error(acyclic_term, _) error if the given term is cyclic, and nil otherwise.
+func acyclicTermError(t engine.Term, env *engine.Env) error {
+	if !isCyclicTerm(t, env) {
+		return nil
+	}
+	return cyclicTermError(env)
+}
+
+// cyclicTermError returns a type_error(acyclic_term, _) error, the culprit being left unbound as a cyclic term cannot
+// be written.
+func cyclicTermError(env *engine.Env) engine.Exception {
+	return isoError(engine.NewAtom("type_error").Apply(AtomAcyclicTerm, engine.NewVariable()), env)
+}
+
+// permissionError returns a permission_error(Operation, PermissionType, Culprit) error.
+func permissionError(operation, permissionType engine.Atom, culprit engine.Term, env *engine.Env) engine.Exception {
+	return isoError(AtomPermissionError.Apply(operation, permissionType, culprit), env)
+}
+
+// representationError returns a representation_error(Flag) error, the flag being the limit which has been breached.
+func representationError(flag engine.Atom, env *engine.Env) engine.Exception {
+	return isoError(AtomRepresentationError.Apply(flag), env)
+}
+
+// withMessage returns the given ISO error with a message explaining it, the context of the error being replaced by
+// context(Context, Message), as SWI-Prolog does.
+func withMessage(err engine.Exception, message string, env *engine.Env) engine.Exception {
+	e, ok := err.Term().(engine.Compound)
+	if !ok || e.Functor() != AtomError || e.Arity() != 2 {
+		return err
+	}
+	return engine.NewException(AtomError.Apply(e.Arg(0), AtomContext.Apply(e.Arg(1), engine.NewAtom(message))), env)
+}
+
+// decodeBytes converts the given term into bytes according to the given encoding, as TermToBytes does, but raises ISO
+// errors for an invalid term:
+//   - an instantiation_error if the term, or an element of its list, is not instantiated;
+//   - a type_error(list, Term) or a type_error(atom, Term) if the term is not of the type of the encoding;
+//   - a type_error(byte, Element) if an element of its list is not a byte;
+//   - a domain_error(encoding(hex), Term) if the term is not a valid hexadecimal encoding, with the message odd number of
+//     hex digits if it is of odd length;
+//   - a domain_error(encoding(base64), Term) or a domain_error(encoding(base58), Term) if the term is not a valid
+//     base64 or base58 encoding;
+//   - a domain_error(encoding, Encoding) if the encoding is not supported.
+//
+// Exceeding the maximum input size is not an error of the program but a limit of the sandbox: it is reported as a Go
+// error so that it cannot be caught.
+func decodeBytes(ctx context.Context, term engine.Term, encoding engine.Term, env *engine.Env) ([]byte, error) {
+	switch env.Resolve(encoding) {
+	case AtomOctet:
+		v := env.Resolve(term)
+		if c, ok := v.(engine.Compound); v != util.AtomEmptyList && (!ok || !util.IsList(c)) {
+			return nil, typeError(util.AtomList, term, env)
+		}
+		if err := checkListBytes(ctx, term, env); err != nil {
+			return nil, err
+		}
+		return ListToBytes(ctx, engine.ListIterator{List: term, Env: env}, env)
+	case AtomHex:
+		atom, ok := env.Resolve(term).(engine.Atom)
+		if !ok {
+			return nil, typeError(AtomAtom, term, env)
+		}
+		if err := checkInputSize(ctx, hex.DecodedLen(len(atom.String()))); err != nil {
+			return nil, err
+		}
+		if len(atom.String())%2 != 0 {
+			return nil, withMessage(engine.DomainError(AtomEncoding.Apply(AtomHex), atom, env), errOddHexLength, env)
+		}
+		decoded, err := hex.DecodeString(atom.String())
+		if err != nil {
+			return nil, domainError(AtomEncoding.Apply(AtomHex), term, env)
+		}
+		return decoded, nil
+	case AtomBase64, AtomBase58:
+		atom, ok := env.Resolve(term).(engine.Atom)
+		if !ok {
+			return nil, typeError(AtomAtom, term, env)
+		}
+		decoded, err := decodeBaseText(ctx, env.Resolve(encoding).(engine.Atom), atom.String())
+		if errors.Is(err, errInvalidBaseText) {
+			return nil, domainError(AtomEncoding.Apply(env.Resolve(encoding)), term, env)
+		}
+		return decoded, err
+	default:
+		return nil, domainError(AtomEncoding, encoding, env)
+	}
+}
+
+// checkListBytes checks that all the elements of the given list are bytes, i.e. integers between 0 and 255, failing
+// as soon as the list exceeds the maximum input size carried by the context, if any, as ListToBytes does.
+func checkListBytes(ctx context.Context, list engine.Term, env *engine.Env) error {
+	limit, limited := maxInputSize(ctx)
+	iter := engine.ListIterator{List: list, Env: env}
+	for index := uint64(1); iter.Next(); index++ {
+		if limited && index > limit {
+			return fmt.Errorf("input exceeds the maximum size of %d bytes", limit)
+		}
+		if i, ok := env.Resolve(iter.Current()).(engine.Integer); !ok || i < 0 || i > 255 {
+			return typeError(AtomByte, iter.Current(), env)
+		}
+	}
+	return iter.Err()
+}
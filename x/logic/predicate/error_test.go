@@ -0,0 +1,93 @@
+//nolint:lll
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+	"github.com/samber/lo"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestDecodeBytes(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			term         engine.Term
+			encoding     engine.Term
+			maxInputSize *sdkmath.Uint
+			result       []byte
+			wantError    error
+			wantISOError string
+		}{
+			{
+				term:         engine.List(engine.Integer(72), engine.Integer(101), engine.Integer(121), engine.Integer(33)),
+				encoding:     AtomOctet,
+				maxInputSize: lo.ToPtr(sdkmath.NewUint(4)),
+				result:       []byte{72, 101, 121, 33},
+			},
+			{
+				term:         engine.List(engine.Integer(72), engine.Integer(256)),
+				encoding:     AtomOctet,
+				maxInputSize: lo.ToPtr(sdkmath.NewUint(4)),
+				wantISOError: "type_error(byte,256)",
+			},
+			{
+				term:         engine.List(engine.Integer(72), engine.Integer(101), engine.Integer(121), engine.Integer(33), engine.Integer(32)),
+				encoding:     AtomOctet,
+				maxInputSize: lo.ToPtr(sdkmath.NewUint(4)),
+				wantError:    fmt.Errorf("input exceeds the maximum size of 4 bytes"),
+			},
+			{
+				term:         engine.List(engine.Integer(72), engine.Integer(101), engine.Integer(121), engine.Integer(33), engine.Integer(32), engine.NewAtom("foo")),
+				encoding:     AtomOctet,
+				maxInputSize: lo.ToPtr(sdkmath.NewUint(4)),
+				wantError:    fmt.Errorf("input exceeds the maximum size of 4 bytes"),
+			},
+			{
+				term:         engine.NewAtom("2c2g"),
+				encoding:     AtomHex,
+				wantISOError: "domain_error(encoding(hex),2c2g)",
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the term #%d: %s", nc, tc.term), func() {
+				Convey("when decoding it", func() {
+					env := engine.Env{}
+					ctx := context.Background()
+					if tc.maxInputSize != nil {
+						ctx = context.WithValue(ctx, types.MaxInputSizeContextKey, *tc.maxInputSize)
+					}
+					result, err := decodeBytes(ctx, tc.term, tc.encoding, &env)
+
+					switch {
+					case tc.wantISOError != "":
+						Convey("then an ISO error should be raised", func() {
+							So(err, ShouldHaveSameTypeAs, engine.Exception{})
+
+							var sb strings.Builder
+							So(err.(engine.Exception).Term().(engine.Compound).Arg(0).WriteTerm(&sb, &engine.WriteOptions{}, nil), ShouldBeNil)
+							So(sb.String(), ShouldEqual, tc.wantISOError)
+						})
+					case tc.wantError != nil:
+						Convey("then an error should be returned", func() {
+							So(err, ShouldResemble, tc.wantError)
+						})
+					default:
+						Convey("then the result should be as expected", func() {
+							So(err, ShouldBeNil)
+							So(result, ShouldResemble, tc.result)
+						})
+					}
+				})
+			})
+		}
+	})
+}
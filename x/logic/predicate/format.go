@@ -0,0 +1,452 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+// AtomFormat are terms with principal functor format/1.
+// It is used to represent the formal part of the errors raised for an invalid format or invalid format arguments.
+var AtomFormat = engine.NewAtom("format")
+
+// FormatAtom is a predicate which formats the given arguments according to the given format into an atom, as the
+// format/2 predicate of SWI-Prolog does.
+//
+// The signature is as follows:
+//
+//	format_atom(-Atom, +Format, +Args) is det
+//
+// Where:
+//   - Atom is the formatted text.
+//   - Format is the format, as an Atom or a list of characters or character codes, whose characters are copied as is,
+//     except for the directives starting with a tilde (~), described below.
+//   - Args is the list of the arguments of the directives, a term which is not a list being the single argument.
+//
+// A directive is ~, optionally followed by a numeric argument, given either as a non-negative integer (e.g. ~3d), as
+// * to take it from the next argument (e.g. ~*c), or as a backquote followed by a character to take its code (e.g.
+// ~`-t), and then by one of the following characters:
+//   - w: writes the next argument, as write/1 does.
+//   - q: writes the next argument quoted, as writeq/1 does.
+//   - a: writes the next argument, which shall be an atomic term.
+//   - d: writes the next argument, which shall be an Integer. With a numeric argument N > 0, a decimal point is
+//     inserted N digits before the end, e.g. ~2d writes 1234 as 12.34.
+//   - e and f: write the next argument, which shall be a number, in the exponential (e.g. 1.500000e+00) and in the
+//     fixed-point (e.g. 1.500000) notations, with as many digits after the decimal point as the numeric argument, 6 by
+//     default.
+//   - s: writes the next argument, which shall be a list of characters or character codes.
+//   - c: writes the character of the code given by the next argument, repeated as many times as the numeric argument,
+//     1 by default.
+//   - i: ignores the next argument.
+//   - n: writes as many newlines as the numeric argument, 1 by default.
+//   - ~: writes a tilde.
+//   - t: sets a fill point in the current column, whose fill character is given by the numeric argument, a space by
+//     default.
+//   - | and +: set a column stop, at the column given by the numeric argument for |, the current column by default,
+//     and at as many columns as the numeric argument past the previous column stop for +, 8 by default. The text of the
+//     column is padded up to the column stop by distributing the fill characters as evenly as possible among its fill
+//     points, the first ones getting the remainder, or with spaces at its end if it has no fill point.
+//
+// The variables of the arguments are written as _0, _1, ... in the order of their first occurrence, so that the
+// output is the same on all the nodes. An unknown directive, a missing or extra argument, or an argument of the wrong
+// type raises a catchable error(format(Message), Context) exception, an unbound Format an instantiation_error, and
+// cyclic arguments a type_error(acyclic_term, _). The characters generated by a repetition (~Nc and ~Nn), a padding
+// or a number of digits (~Nd, ~Ne and ~Nf) count as an input for the max_input_size limit, and may not exceed 65536
+// in total even without limit, their number exceeding either raising a format error as well.
+//
+// Examples:
+//
+//	# Build a message.
+//	- format_atom(Atom, 'Transfer of ~d ~a to ~w', [100, uknow, okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm]).
+//
+//	# Format an amount with 6 decimals.
+//	- format_atom(Atom, '~6d', [1500000]).
+//
+//	# Align a table.
+//	- format_atom(Atom, '~a~t~10|~`.t~a~20|', [alice, 10]).
+func FormatAtom(vm *engine.VM, atom, format, args engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		if _, ok := env.Resolve(format).(engine.Variable); ok {
+			return engine.Error(engine.InstantiationError(env))
+		}
+		f, err := termToText(format, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("format_atom/3: %w", err))
+		}
+		if err := acyclicTermError(args, env); err != nil {
+			return engine.Error(err)
+		}
+
+		s := formatState{ctx: ctx, vm: vm, args: formatArgs(args, env), env: env}
+		names := make(map[engine.Variable]engine.Atom)
+		for i, v := range termVariables(engine.List(s.args...), env) {
+			names[v] = engine.NewAtom(fmt.Sprintf("_%d", i))
+		}
+		s.names = names
+
+		if err := s.format(f); err != nil {
+			return engine.Error(err)
+		}
+
+		return engine.Unify(vm, atom, engine.NewAtom(s.out.String()), cont, env)
+	})
+}
+
+// formatArgs returns the arguments of the directives, a term which is not a list being the single argument.
+func formatArgs(args engine.Term, env *engine.Env) []engine.Term {
+	terms := make([]engine.Term, 0)
+	iter := engine.ListIterator{List: args, Env: env}
+	for iter.Next() {
+		terms = append(terms, iter.Current())
+	}
+	if err := iter.Err(); err != nil {
+		return []engine.Term{args}
+	}
+	return terms
+}
+
+// formatMaxGenerated is the maximum number of characters the repetitions, paddings and numbers of digits of a format
+// may generate in total, whatever the max_input_size limit.
+const formatMaxGenerated = 65536
+
+// fillPoint is a fill point of a column, at the given position (in characters) of its text.
+type fillPoint struct {
+	pos  int
+	char rune
+}
+
+// formatState is the state of the formatting of the arguments of format_atom/3.
+type formatState struct {
+	ctx   context.Context
+	vm    *engine.VM
+	args  []engine.Term
+	names map[engine.Variable]engine.Atom
+	env   *engine.Env
+
+	// out is the formatted text, up to the current column.
+	out strings.Builder
+	// column is the text of the current column, and stop the column of its start in the current line.
+	column []rune
+	stop   int
+	// fills are the fill points of the current column.
+	fills []fillPoint
+	// generated is the number of characters generated so far by the repetitions, paddings and numbers of digits.
+	generated int
+}
+
+// format formats the arguments according to the given format.
+func (s *formatState) format(f string) error {
+	runes := []rune(f)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '~' {
+			s.write(string(runes[i]))
+			continue
+		}
+
+		i++
+		numArg, next, err := s.numericArgument(runes, i)
+		if err != nil {
+			return err
+		}
+		i = next
+		if i >= len(runes) {
+			return s.error("truncated format specification")
+		}
+		if err := s.directive(runes[i], numArg); err != nil {
+			return err
+		}
+	}
+	if len(s.args) > 0 {
+		return s.error("too many arguments")
+	}
+	return s.flush(0)
+}
+
+// numericArgument parses the optional numeric argument of the directive starting at the given position of the format,
+// returning it, or -1 if there is none, along with the position of the directive character.
+func (s *formatState) numericArgument(runes []rune, i int) (int, int, error) {
+	switch {
+	case i < len(runes) && runes[i] == '*':
+		arg, err := s.next()
+		if err != nil {
+			return 0, 0, err
+		}
+		n, ok := s.env.Resolve(arg).(engine.Integer)
+		if !ok || n < 0 {
+			return 0, 0, s.error("* expects a non-negative integer argument")
+		}
+		return int(n), i + 1, nil
+	case i+1 < len(runes) && runes[i] == '`':
+		return int(runes[i+1]), i + 2, nil
+	default:
+		j := i
+		for j < len(runes) && runes[j] >= '0' && runes[j] <= '9' {
+			j++
+		}
+		if j == i {
+			return -1, i, nil
+		}
+		n, err := strconv.Atoi(string(runes[i:j]))
+		if err != nil {
+			return 0, 0, s.error("invalid numeric argument")
+		}
+		return n, j, nil
+	}
+}
+
+// directive applies the directive of the given character, with the given numeric argument (-1 if there is none).
+//
+//nolint:cyclop
+func (s *formatState) directive(d rune, numArg int) error {
+	switch d {
+	case 'w', 'q':
+		arg, err := s.next()
+		if err != nil {
+			return err
+		}
+		text, err := s.writeTerm(arg, d == 'q')
+		if err != nil {
+			return err
+		}
+		s.write(text)
+	case 'a':
+		return s.atomic()
+	case 'd':
+		return s.integer(numArg)
+	case 'e', 'f':
+		return s.float(d, numArg)
+	case 's':
+		arg, err := s.next()
+		if err != nil {
+			return err
+		}
+		if _, ok := s.env.Resolve(arg).(engine.Compound); !ok && s.env.Resolve(arg) != util.AtomEmptyList {
+			return s.error("~s expects a list of characters or codes argument")
+		}
+		text, err := termToText(arg, s.env)
+		if err != nil {
+			return s.error("~s expects a list of characters or codes argument")
+		}
+		s.write(text)
+	case 'c':
+		arg, err := s.next()
+		if err != nil {
+			return err
+		}
+		code, ok := s.env.Resolve(arg).(engine.Integer)
+		if !ok || code < 0 || code > utf8.MaxRune {
+			return s.error("~c expects a character code argument")
+		}
+		if err := s.generate(max(numArg, 1)); err != nil {
+			return err
+		}
+		s.write(strings.Repeat(string(rune(code)), max(numArg, 1)))
+	case 'i':
+		_, err := s.next()
+		return err
+	case 'n':
+		if err := s.generate(max(numArg, 1)); err != nil {
+			return err
+		}
+		s.write(strings.Repeat("\n", max(numArg, 1)))
+	case '~':
+		s.write("~")
+	case 't':
+		char := ' '
+		if numArg >= 0 {
+			char = rune(numArg)
+		}
+		s.fills = append(s.fills, fillPoint{pos: len(s.column), char: char})
+	case '|':
+		target := s.stop + len(s.column)
+		if numArg >= 0 {
+			target = numArg
+		}
+		return s.flush(target)
+	case '+':
+		if numArg < 0 {
+			numArg = 8
+		}
+		return s.flush(s.stop + numArg)
+	default:
+		return s.error(fmt.Sprintf("unknown directive ~%c", d))
+	}
+
+	return nil
+}
+
+// atomic applies the ~a directive.
+func (s *formatState) atomic() error {
+	arg, err := s.next()
+	if err != nil {
+		return err
+	}
+	switch s.env.Resolve(arg).(type) {
+	case engine.Atom, engine.Integer, engine.Float:
+		text, err := termToText(arg, s.env)
+		if err != nil {
+			return err
+		}
+		s.write(text)
+		return nil
+	default:
+		return s.error("~a expects an atomic argument")
+	}
+}
+
+// integer applies the ~d directive, inserting a decimal point the given number of digits before the end.
+func (s *formatState) integer(numArg int) error {
+	arg, err := s.next()
+	if err != nil {
+		return err
+	}
+	n, ok := s.env.Resolve(arg).(engine.Integer)
+	if !ok {
+		return s.error("~d expects an integer argument")
+	}
+
+	digits := strconv.FormatInt(int64(n), 10)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	if numArg > 0 {
+		if err := s.generate(numArg); err != nil {
+			return err
+		}
+		if len(digits) <= numArg {
+			digits = strings.Repeat("0", numArg-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-numArg] + "." + digits[len(digits)-numArg:]
+	}
+	s.write(sign + digits)
+
+	return nil
+}
+
+// float applies the ~e and ~f directives, with the given number of digits after the decimal point.
+func (s *formatState) float(d rune, numArg int) error {
+	arg, err := s.next()
+	if err != nil {
+		return err
+	}
+	var f float64
+	switch n := s.env.Resolve(arg).(type) {
+	case engine.Integer:
+		f = float64(n)
+	case engine.Float:
+		f = float64(n)
+	default:
+		return s.error(fmt.Sprintf("~%c expects a numeric argument", d))
+	}
+	if numArg < 0 {
+		numArg = 6
+	}
+	if err := s.generate(numArg); err != nil {
+		return err
+	}
+	s.write(strconv.FormatFloat(f, byte(d), numArg, 64))
+
+	return nil
+}
+
+// next returns the next argument, raising an error if there is none.
+func (s *formatState) next() (engine.Term, error) {
+	if len(s.args) == 0 {
+		return nil, s.error("not enough arguments")
+	}
+	arg := s.args[0]
+	s.args = s.args[1:]
+	return arg, nil
+}
+
+// writeTerm returns the text of the given term, as written by write/1, or writeq/1 if quoted.
+func (s *formatState) writeTerm(term engine.Term, quoted bool) (string, error) {
+	names := make([]engine.Term, 0, len(s.names))
+	for _, v := range termVariables(term, s.env) {
+		names = append(names, engine.NewAtom("=").Apply(s.names[v], v))
+	}
+	options := engine.List(
+		engine.NewAtom("quoted").Apply(engine.NewAtom(strconv.FormatBool(quoted))),
+		engine.NewAtom("variable_names").Apply(engine.List(names...)),
+	)
+
+	var sb strings.Builder
+	if _, err := engine.WriteTerm(s.vm, engine.NewOutputTextStream(&sb), term, options, engine.Success, s.env).
+		Force(s.ctx); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// generate checks that the given number of characters may be generated by a repetition, a padding or a number of
+// digits, according to the max_input_size limit and to formatMaxGenerated, which applies even without limit.
+func (s *formatState) generate(n int) error {
+	if err := checkInputSize(s.ctx, n); err != nil {
+		return s.error(err.Error())
+	}
+	s.generated += n
+	if s.generated > formatMaxGenerated {
+		return s.error(fmt.Sprintf("generated characters exceed the maximum of %d", formatMaxGenerated))
+	}
+	return nil
+}
+
+// write writes the given text in the current column, a newline ending the column without padding it.
+func (s *formatState) write(text string) {
+	for {
+		line, rest, found := strings.Cut(text, "\n")
+		s.column = append(s.column, []rune(line)...)
+		if !found {
+			return
+		}
+		_ = s.flush(0)
+		s.out.WriteString("\n")
+		s.stop = 0
+		text = rest
+	}
+}
+
+// flush ends the current column at the given column stop, padding its text up to it.
+func (s *formatState) flush(target int) error {
+	pad := target - s.stop - len(s.column)
+	column := s.column
+	if pad > 0 {
+		if err := s.generate(pad); err != nil {
+			return err
+		}
+		if len(s.fills) == 0 {
+			s.fills = []fillPoint{{pos: len(column), char: ' '}}
+		}
+		padded := make([]rune, 0, len(column)+pad)
+		prev := 0
+		for i, fill := range s.fills {
+			n := pad / len(s.fills)
+			if i < pad%len(s.fills) {
+				n++
+			}
+			padded = append(padded, column[prev:fill.pos]...)
+			padded = append(padded, []rune(strings.Repeat(string(fill.char), n))...)
+			prev = fill.pos
+		}
+		column = append(padded, column[prev:]...)
+	}
+
+	s.out.WriteString(string(column))
+	s.stop += len(column)
+	s.column = s.column[:0]
+	s.fills = nil
+
+	return nil
+}
+
+// error returns a format(Message) error.
+func (s *formatState) error(message string) error {
+	return isoError(AtomFormat.Apply(engine.NewAtom(message)), s.env)
+}
@@ -0,0 +1,250 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestFormatAtom(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `format_atom(A, 'Transfer of ~d ~a to ~w', [100, uknow, 'okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm']).`,
+				wantResult:  []types.TermResults{{"A": "'Transfer of 100 uknow to okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_atom(A, "hello ~w", world).`,
+				wantResult:  []types.TermResults{{"A": "'hello world'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_atom(A, '~w and ~q', ['It''s', 'It''s']).`,
+				wantResult:  []types.TermResults{{"A": "'It\\'s and \\'It\\\\\\'s\\''"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `written(A) :- format_atom(A, '~w-~w-~w', [f(_, Y), Y, "ab"]).`,
+				query:       `written(A), A == 'f(_0,_1)-_1-[a,b]'.`,
+				wantResult:  []types.TermResults{{"A": "'f(_1,_2)-_3-[a,b]'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_atom(A, '~6d|~2d|~2d|~2d|~d', [1500000, -5, 123, 0, -42]).`,
+				wantResult:  []types.TermResults{{"A": "'1.500000|-0.05|1.23|0.00|-42'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_atom(A, '~e ~3e ~f ~2f ~0f', [1.5, 12345, 0.1, 2, 2.5]).`,
+				wantResult:  []types.TermResults{{"A": "'1.500000e+00 1.234e+04 0.100000 2.00 2'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_atom(A, '[~s] [~s]', [[0'a, 0'b], [c, d]]).`,
+				wantResult:  []types.TermResults{{"A": "'[ab] [cd]'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_atom(A, '~c~3c~*c', [0'a, 0'b, 2, 0'c]).`,
+				wantResult:  []types.TermResults{{"A": "abbbcc"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_atom(A, '~i~w~~~n~2n', [skipped, kept]).`,
+				wantResult:  []types.TermResults{{"A": "'kept~\\n\\n\\n'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       "format_atom(A, '~a~t~10|~`.t~a~20|', [alice, 10]).",
+				wantResult:  []types.TermResults{{"A": "'alice     ........10'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_atom(A, '~t~a~8|~t~a~t~8+~a~4+|', [right, center, left]).`,
+				wantResult:  []types.TermResults{{"A": "'   right center left|'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       "format_atom(A, '~`-t~30|~nabc~w~t~6|', [d]).",
+				wantResult:  []types.TermResults{{"A": "'------------------------------\\nabcd  '"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_atom(A, '~a~2|~a', [toolong, x]).`,
+				wantResult:  []types.TermResults{{"A": "toolongx"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_atom(A, [0'~, 0'a], [héllo]).`,
+				wantResult:  []types.TermResults{{"A": "héllo"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_atom('hello world', 'hello ~w', [world]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `format_atom('hello you', 'hello ~w', [world]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(format_atom(_, '~w ~w', [a]), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('not enough arguments'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, '~w', [a, b]), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('too many arguments'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, '~z', [a]), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('unknown directive ~z'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, 'abc~', []), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('truncated format specification'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, '~d', [a]), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('~d expects an integer argument'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, '~a', [f(x)]), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('~a expects an atomic argument'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, '~e', [a]), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('~e expects a numeric argument'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, '~s', [abc]), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('~s expects a list of characters or codes argument'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, '~c', [-1]), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('~c expects a character code argument'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, '~*c', [a, 0'x]), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('* expects a non-negative integer argument'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, '~a~t~9999999999|', [x]), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('generated characters exceed the maximum of 65536'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, '~*c~*c', [40000, 0'x, 40000, 0'y]), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('generated characters exceed the maximum of 65536'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, '~99999999f', [1.5]), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(format('generated characters exceed the maximum of 65536'),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch((X = f(X), format_atom(_, '~w', [X])), E, true).`,
+				wantResult:  []types.TermResults{{"X": "_1", "E": "error(type_error(acyclic_term,_1),/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(format_atom(_, _, []), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(instantiation_error,/(format_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:     `format_atom(_, f(x), []).`,
+				wantError: fmt.Errorf("format_atom/3: invalid text type: *engine.compound, should be atomic or a list of characters or codes"),
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("format_atom"), FormatAtom)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
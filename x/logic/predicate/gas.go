@@ -0,0 +1,46 @@
+package predicate
+
+import (
+	"context"
+	"math"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/okp4/okp4d/x/logic/types"
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+// consumeAlgorithmGas consumes the gas due to the use of the given algorithm on an input of the given size (in bytes),
+// according to the algorithm costs of the gas policy carried by the context. No gas is consumed if no cost is defined
+// for the algorithm.
+//
+// The gas is consumed on the gas meter of the SDK context, which panics with the standard out of gas error when the
+// limit is exceeded, aborting the whole execution.
+func consumeAlgorithmGas(ctx context.Context, functor, algorithm string, size int) error {
+	sdkContext, err := util.UnwrapSDKContext(ctx)
+	if err != nil {
+		return err
+	}
+	costs, ok := sdkContext.Value(types.AlgorithmCostsContextKey).([]types.AlgorithmCost)
+	if !ok {
+		return nil
+	}
+
+	for _, cost := range costs {
+		if cost.Algorithm != algorithm {
+			continue
+		}
+
+		gas := util.DerefOrDefault(cost.ByteCost, sdkmath.ZeroUint()).
+			MulUint64(uint64(size)).
+			Add(util.DerefOrDefault(cost.BaseCost, sdkmath.ZeroUint()))
+		amount := uint64(math.MaxUint64)
+		if gas.LTE(sdkmath.NewUint(math.MaxUint64)) {
+			amount = gas.Uint64()
+		}
+		sdkContext.GasMeter().ConsumeGas(amount, functor+" "+algorithm)
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ichiban/prolog/engine"
+
+	gov "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+
+	"github.com/okp4/okp4d/x/logic/types"
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+var (
+	// AtomProposal are terms with principal functor proposal/3.
+	// It is used to represent a governance proposal.
+	AtomProposal = engine.NewAtom("proposal")
+
+	// AtomTally are terms with principal functor tally/4.
+	// It is used to represent the tally of the votes of a governance proposal.
+	AtomTally = engine.NewAtom("tally")
+)
+
+// GovProposal is a predicate which unifies the given term with the governance proposal of the given identifier.
+//
+// The signature is as follows:
+//
+//	gov_proposal(+ProposalID, -Proposal) is semidet
+//
+// Where:
+//   - ProposalID is the identifier of the proposal, either as a non-negative Integer or as an Atom holding its decimal
+//     representation.
+//   - Proposal is the proposal, as a proposal(Status, VotingEndTime, Tally) term (see below).
+//
+// In a proposal, Status is one of unspecified, deposit_period, voting_period, passed, rejected or failed,
+// VotingEndTime is the end of its voting period, as an Atom holding the number of seconds since the Unix epoch, or
+// none if the proposal has not reached its voting period, and Tally is the final tally of its votes, as a
+// tally(Yes, Abstain, No, NoWithVeto) term of Atoms holding the voting power of each option, which are zero until the
+// end of the voting period.
+//
+// The proposal is read from the committed state at the current height. The predicate fails if there is no proposal with
+// the given identifier, and an unbound ProposalID raises an instantiation_error.
+//
+// Examples:
+//
+//	# Query the status of the proposal 1.
+//	- gov_proposal(1, proposal(Status, _, _)).
+//
+//	# Query the votes in favor of the proposal 1.
+//	- gov_proposal('1', proposal(passed, _, tally(Yes, _, _, _))).
+func GovProposal(vm *engine.VM, proposalID, proposal engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		sdkContext, err := util.UnwrapSDKContext(ctx)
+		if err != nil {
+			return engine.Error(err)
+		}
+		govKeeper := sdkContext.Value(types.GovKeeperContextKey).(types.GovKeeper)
+
+		id, err := termToProposalID(proposalID, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		p, found := govKeeper.GetProposal(sdkContext, id)
+		if !found {
+			return engine.Bool(false)
+		}
+
+		return engine.Unify(vm, proposal, proposalToTerm(p), cont, env)
+	})
+}
+
+// termToProposalID converts the given term, either a non-negative Integer or an Atom holding its decimal
+// representation, into the identifier of a proposal.
+func termToProposalID(term engine.Term, env *engine.Env) (uint64, error) {
+	switch t := env.Resolve(term).(type) {
+	case engine.Variable:
+		return 0, engine.InstantiationError(env)
+	case engine.Integer:
+		if t >= 0 {
+			return uint64(t), nil
+		}
+	case engine.Atom:
+		if id, err := strconv.ParseUint(t.String(), 10, 64); err == nil {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("gov_proposal/2: invalid proposal id: %v, should be a non-negative Integer", env.Resolve(term))
+}
+
+// proposalToTerm converts the given governance proposal into a proposal(Status, VotingEndTime, Tally) term.
+func proposalToTerm(p gov.Proposal) engine.Term {
+	status := engine.NewAtom(strings.ToLower(strings.TrimPrefix(p.Status.String(), "PROPOSAL_STATUS_")))
+
+	votingEndTime := AtomNone
+	if p.VotingEndTime != nil {
+		votingEndTime = engine.NewAtom(strconv.FormatInt(p.VotingEndTime.Unix(), 10))
+	}
+
+	tally := gov.EmptyTallyResult()
+	if p.FinalTallyResult != nil {
+		tally = *p.FinalTallyResult
+	}
+
+	return AtomProposal.Apply(
+		status,
+		votingEndTime,
+		AtomTally.Apply(
+			engine.NewAtom(tally.YesCount),
+			engine.NewAtom(tally.AbstainCount),
+			engine.NewAtom(tally.NoCount),
+			engine.NewAtom(tally.NoWithVetoCount),
+		),
+	)
+}
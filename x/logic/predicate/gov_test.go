@@ -0,0 +1,140 @@
+//nolint:gocognit
+package predicate
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	gov "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestGovProposal(t *testing.T) {
+	Convey("Under a mocked environment", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		votingEndTime := time.Unix(1685613600, 0)
+		tally := gov.NewTallyResult(sdk.NewInt(7000), sdk.NewInt(12), sdk.NewIntFromUint64(18446744073709551615), sdk.ZeroInt())
+		proposals := []gov.Proposal{
+			{Id: 1, Status: gov.StatusPassed, VotingEndTime: &votingEndTime, FinalTallyResult: &tally},
+			{Id: 2, Status: gov.StatusVotingPeriod, VotingEndTime: &votingEndTime, FinalTallyResult: &gov.TallyResult{
+				YesCount: "0", AbstainCount: "0", NoCount: "0", NoWithVetoCount: "0",
+			}},
+			{Id: 3, Status: gov.StatusDepositPeriod},
+		}
+
+		cases := []struct {
+			query      string
+			wantResult []types.TermResults
+			wantError  error
+		}{
+			{
+				query:      `gov_proposal(1, Proposal).`,
+				wantResult: []types.TermResults{{"Proposal": "proposal(passed,'1685613600',tally('7000','12','18446744073709551615','0'))"}},
+			},
+			{
+				query:      `gov_proposal('2', proposal(Status, End, _)).`,
+				wantResult: []types.TermResults{{"Status": "voting_period", "End": "'1685613600'"}},
+			},
+			{
+				query:      `gov_proposal(3, Proposal).`,
+				wantResult: []types.TermResults{{"Proposal": "proposal(deposit_period,none,tally('0','0','0','0'))"}},
+			},
+			{
+				query:      `gov_proposal(1, proposal(rejected, _, _)).`,
+				wantResult: nil,
+			},
+			{
+				query:      `gov_proposal(42, Proposal).`,
+				wantResult: nil,
+			},
+			{
+				query:     `gov_proposal(-1, Proposal).`,
+				wantError: fmt.Errorf("gov_proposal/2: invalid proposal id: -1, should be a non-negative Integer"),
+			},
+			{
+				query:     `gov_proposal(foo, Proposal).`,
+				wantError: fmt.Errorf("gov_proposal/2: invalid proposal id: foo, should be a non-negative Integer"),
+			},
+			{
+				query:      `catch(gov_proposal(_, _), E, R = caught).`,
+				wantResult: []types.TermResults{{"E": "error(instantiation_error,/(gov_proposal,2))", "R": "caught"}},
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					govKeeper := testutil.NewMockGovKeeper(ctrl)
+					ctx := sdk.
+						NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger()).
+						WithValue(types.GovKeeperContextKey, govKeeper)
+
+					Convey("and a gov keeper initialized with the preconfigured proposals", func() {
+						govKeeper.
+							EXPECT().
+							GetProposal(ctx, gomock.Any()).
+							AnyTimes().
+							DoAndReturn(func(_ sdk.Context, id uint64) (gov.Proposal, bool) {
+								for _, proposal := range proposals {
+									if proposal.Id == id {
+										return proposal, true
+									}
+								}
+								return gov.Proposal{}, false
+							})
+
+						Convey("and a vm", func() {
+							interpreter := testutil.NewLightInterpreterMust(ctx)
+							interpreter.Register2(engine.NewAtom("gov_proposal"), GovProposal)
+							interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+							Convey("When the predicate is called", func() {
+								sols, err := interpreter.QueryContext(ctx, tc.query)
+
+								Convey("Then the error should be nil", func() {
+									So(err, ShouldBeNil)
+									So(sols, ShouldNotBeNil)
+
+									Convey("and the bindings should be as expected", func() {
+										var got []types.TermResults
+										for sols.Next() {
+											m := types.TermResults{}
+											err := sols.Scan(m)
+											So(err, ShouldBeNil)
+
+											got = append(got, m)
+										}
+										if tc.wantError != nil {
+											So(sols.Err(), ShouldNotBeNil)
+											So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+										} else {
+											So(sols.Err(), ShouldBeNil)
+											So(got, ShouldResemble, tc.wantResult)
+										}
+									})
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package predicate
+
+import (
+	"context"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+// PhraseCheck is a predicate which validates a list of tokens against a grammar defined by DCG rules, i.e. clauses of
+// the form Head --> Body, succeeding if and only if the whole list is accepted.
+//
+// The signature is as follows:
+//
+//	phrase_check(+RuleName, +Tokens) is semidet
+//
+// Where:
+//   - RuleName is the non-terminal of the grammar to validate the tokens against, e.g. the name of a DCG rule, or any
+//     grammar rule body accepted by phrase/3.
+//   - Tokens is the list of tokens to validate.
+//
+// The predicate succeeds if the grammar accepts Tokens entirely, i.e. as phrase(RuleName, Tokens, []), and fails if
+// it rejects them or only accepts a prefix of them. The grammar is only run until its first solution, and its bindings
+// are not kept, so that the predicate is a pure check. An unbound RuleName or a partial list of Tokens raises an
+// instantiation_error, a Tokens which is not a list raises a type_error(list, Tokens) error, and the errors raised by
+// the grammar are propagated.
+//
+// Examples:
+//
+//	# Validate a list of tokens against a grammar.
+//	- phrase_check(greeting, [hello, world]).
+func PhraseCheck(vm *engine.VM, rule, tokens engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		if _, ok := env.Resolve(rule).(engine.Variable); ok {
+			return engine.Error(engine.InstantiationError(env))
+		}
+		iter := engine.ListIterator{List: tokens, Env: env}
+		for iter.Next() {
+		}
+		if err := iter.Err(); err != nil {
+			return engine.Error(err)
+		}
+
+		ok, err := engine.Phrase(vm, rule, tokens, engine.List(), engine.Success, env).Force(ctx)
+		if err != nil {
+			return engine.Error(err)
+		}
+		if !ok {
+			return engine.Bool(false)
+		}
+
+		return cont(env)
+	})
+}
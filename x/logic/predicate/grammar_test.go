@@ -0,0 +1,165 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestPhraseCheck(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		grammar := `:-(op(1200, xfx, -->)).
+			greeting --> [hello], name.
+			name --> [world].
+			name --> [alice].
+			as --> [].
+			as --> [a], as.`
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				program:     grammar,
+				query:       `phrase_check(greeting, [hello, world]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     grammar,
+				query:       `phrase_check(greeting, [hello, alice]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     grammar,
+				query:       `phrase_check(as, [a, a, a]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     grammar,
+				query:       `phrase_check(as, []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     grammar,
+				query:       `phrase_check(([hello], name), [hello, world]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     grammar,
+				query:       `phrase_check(greeting, [hello, bob]).`,
+				wantSuccess: false,
+			},
+			{
+				program:     grammar,
+				query:       `phrase_check(greeting, [hello, world, again]).`,
+				wantSuccess: false,
+			},
+			{
+				program:     grammar,
+				query:       `phrase_check(as, [a, b]).`,
+				wantSuccess: false,
+			},
+			{
+				program: grammar,
+				query:   `catch(phrase_check(greeting, [hello|T]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"T": "_1", "E": "error(instantiation_error,/(phrase_check,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				program: grammar,
+				query:   `catch(phrase_check(greeting, foo), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"E": "error(type_error(list,foo),/(phrase_check,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				program: grammar,
+				query:   `catch(phrase_check(Rule, [hello]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Rule": "_1", "E": "error(instantiation_error,/(phrase_check,2))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("phrase_check"), PhraseCheck)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
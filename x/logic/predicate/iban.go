@@ -0,0 +1,132 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+// ibanLengths is the length of the IBANs of each country, indexed by ISO 3166-1 alpha-2 country code, as specified by
+// the IBAN registry.
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22, "BH": 22, "BI": 27, "BR": 29,
+	"BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20, "EG": 29,
+	"ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27, "GT": 28,
+	"HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27, "JO": 30, "KW": 30, "KZ": 20, "LB": 28,
+	"LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20,
+	"MR": 27, "MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23, "PK": 24, "PL": 28, "PS": 29, "PT": 25,
+	"QA": 29, "RO": 24, "RS": 22, "RU": 33, "SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27,
+	"SO": 23, "ST": 25, "SV": 28, "TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// IBANValid is a predicate which checks that an atom is a valid International Bank Account Number (IBAN).
+//
+// The signature is as follows:
+//
+//	iban_valid(+IBAN) is semi-det
+//
+// Where:
+//   - IBAN is the IBAN to check, as an Atom, either in its electronic form (e.g. 'DE89370400440532013000') or in its
+//     printed form, grouped by blocks separated by spaces (e.g. 'DE89 3704 0044 0532 0130 00').
+//
+// The IBAN is normalized by removing its white spaces and converting it to upper case. It is valid if it starts with
+// the code of a country using IBANs followed by 2 check digits, if its length is the one of the IBANs of this country,
+// if it is only made of letters and digits, and if its check digits are consistent, i.e. if the number obtained by
+// moving its first 4 characters to its end and replacing its letters with 2 digits (A = 10, B = 11, ..., Z = 35) gives
+// a remainder of 1 when divided by 97, as specified by ISO 13616. The predicate fails otherwise.
+//
+// Examples:
+//
+//	# Check an IBAN.
+//	- iban_valid('DE89 3704 0044 0532 0130 00').
+func IBANValid(_ *engine.VM, iban engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		normalized, err := termToIBAN(iban, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("iban_valid/1: %w", err))
+		}
+		if !isValidIBAN(normalized) {
+			return engine.Bool(false)
+		}
+
+		return cont(env)
+	})
+}
+
+// IBANComponents is a predicate which decomposes a valid International Bank Account Number (IBAN) into its
+// components.
+//
+// The signature is as follows:
+//
+//	iban_components(+IBAN, -Country, -CheckDigits, -BBAN) is semi-det
+//
+// Where:
+//   - IBAN is the IBAN to decompose, as an Atom, either in its electronic form or in its printed form.
+//   - Country is the ISO 3166-1 alpha-2 code of the country of the IBAN, as an Atom (e.g. 'DE').
+//   - CheckDigits is the 2 check digits of the IBAN, as an Atom (e.g. '89').
+//   - BBAN is the Basic Bank Account Number, i.e. the country specific part of the IBAN, as an Atom.
+//
+// The IBAN is normalized and checked as for iban_valid/1, and the predicate fails if it is not valid.
+//
+// Examples:
+//
+//	# Decompose an IBAN.
+//	- iban_components('GB82 WEST 1234 5698 7654 32', Country, CheckDigits, BBAN).
+func IBANComponents(vm *engine.VM, iban, country, checkDigits, bban engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		normalized, err := termToIBAN(iban, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("iban_components/4: %w", err))
+		}
+		if !isValidIBAN(normalized) {
+			return engine.Bool(false)
+		}
+
+		return engine.Unify(vm,
+			Tuple(country, checkDigits, bban),
+			Tuple(engine.NewAtom(normalized[:2]), engine.NewAtom(normalized[2:4]), engine.NewAtom(normalized[4:])),
+			cont, env)
+	})
+}
+
+// termToIBAN converts the given atom into an IBAN in its normalized electronic form, i.e. without white spaces and in
+// upper case.
+func termToIBAN(iban engine.Term, env *engine.Env) (string, error) {
+	atom, ok := env.Resolve(iban).(engine.Atom)
+	if !ok {
+		return "", fmt.Errorf("invalid iban type: %T, should be Atom", env.Resolve(iban))
+	}
+
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, atom.String())), nil
+}
+
+// isValidIBAN checks whether the given normalized IBAN has the length of the IBANs of its country, is only made of
+// letters and digits, and has consistent check digits.
+func isValidIBAN(iban string) bool {
+	if len(iban) < 4 || ibanLengths[iban[:2]] != len(iban) || iban[2] < '0' || iban[2] > '9' || iban[3] < '0' ||
+		iban[3] > '9' {
+		return false
+	}
+
+	remainder := 0
+	for _, c := range iban[4:] + iban[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+
+	return remainder == 1
+}
@@ -0,0 +1,158 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestIBAN(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `iban_valid('DE89370400440532013000').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `iban_valid('DE89 3704 0044 0532 0130 00').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `iban_valid('gb82 west 1234 5698 7654 32').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `iban_valid('NO9386011117947').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `iban_valid('DE89370400440532013001').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `iban_valid('DE98370400440532013000').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `iban_valid('DE8937040044053201300').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `iban_valid('XX89370400440532013000').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `iban_valid('GB82-WEST-1234-5698-7654').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `iban_valid('').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `iban_components('GB82 WEST 1234 5698 7654 32', Country, CheckDigits, BBAN).`,
+				wantResult:  []types.TermResults{{"Country": "'GB'", "CheckDigits": "'82'", "BBAN": "'WEST12345698765432'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `iban_components('fr1420041010050500013m02606', Country, CheckDigits, BBAN).`,
+				wantResult:  []types.TermResults{{"Country": "'FR'", "CheckDigits": "'14'", "BBAN": "'20041010050500013M02606'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `iban_components('DE89370400440532013001', Country, CheckDigits, BBAN).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `iban_valid(42).`,
+				wantError:   fmt.Errorf("iban_valid/1: invalid iban type: engine.Integer, should be Atom"),
+				wantSuccess: false,
+			},
+			{
+				query:       `iban_components(X, Country, CheckDigits, BBAN).`,
+				wantError:   fmt.Errorf("iban_components/4: invalid iban type: engine.Variable, should be Atom"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register1(engine.NewAtom("iban_valid"), IBANValid)
+						interpreter.Register4(engine.NewAtom("iban_components"), IBANComponents)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
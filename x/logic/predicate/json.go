@@ -1,19 +1,25 @@
 package predicate
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/ichiban/prolog/engine"
 	"github.com/samber/lo"
 
-	"cosmossdk.io/math"
-
-	sdk "github.com/cosmos/cosmos-sdk/types"
-
 	"github.com/okp4/okp4d/x/logic/util"
 )
 
@@ -29,10 +35,29 @@ import (
 //
 // In addition, when passing Json and Term, this predicate return true if both result match.
 //
+// The JSON values are mapped as follows:
+//   - An object is represented as json([Key-Value, ...]), where each Key is an Atom. The pairs are always sorted by
+//     key in ascending (byte-wise) order, whatever the order of the keys in the original JSON document.
+//   - An array is represented as a list, the empty array being represented as @([]).
+//   - A string is represented as an Atom.
+//   - An integer number is represented as an Integer. An integer number which does not fit in a 64-bit signed integer
+//     is represented as a big(Atom) term, Atom holding its decimal representation, so no precision is lost and the
+//     number is converted back into a JSON number (see json_read/3 for other representations). Decimal numbers are
+//     not supported.
+//   - The literals true, false and null are respectively represented as @(true), @(false) and @(null).
+//
+// When converting a Term into JSON, the output is compact (without any insignificant whitespace) and the keys of the
+// objects are sorted in ascending order, so the same Term always gives the same JSON string. A big(Atom) term, where
+// Atom holds the decimal representation of an integer, is converted into a JSON number, while an Atom is always
+// converted into a JSON string.
+//
 // Examples:
 //
 //	# JSON conversion to Prolog.
 //	- json_prolog('{"foo": "bar"}', json([foo-bar])).
+//
+//	# Prolog conversion to JSON of a big integer.
+//	- json_prolog(Json, json([amount-big('100000000000000000000')])).
 func JSONProlog(vm *engine.VM, j, term engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
 	return engine.Delay(func(ctx context.Context) *engine.Promise {
 		var result engine.Term
@@ -40,7 +65,7 @@ func JSONProlog(vm *engine.VM, j, term engine.Term, cont engine.Cont, env *engin
 		switch t1 := env.Resolve(j).(type) {
 		case engine.Variable:
 		case engine.Atom:
-			terms, err := jsonStringToTerms(t1.String())
+			terms, err := jsonStringToTerms(t1.String(), true)
 			if err != nil {
 				return engine.Error(fmt.Errorf("json_prolog/2: %w", err))
 			}
@@ -61,16 +86,484 @@ func JSONProlog(vm *engine.VM, j, term engine.Term, cont engine.Cont, env *engin
 				return engine.Error(fmt.Errorf("json_prolog/2: %w", err))
 			}
 
-			b, err = sdk.SortJSON(b)
+			return engine.Unify(vm, j, util.StringToTerm(string(b)), cont, env)
+		}
+	})
+}
+
+// bigIntegerAsOption returns whether the big integers shall be represented as big(Atom) terms according to the
+// big_integer_as option.
+func bigIntegerAsOption(options engine.Term, env *engine.Env) (bool, error) {
+	bigAs, err := util.GetOptionWithDefault(engine.NewAtom("big_integer_as"), options, AtomBig, env)
+	if err != nil {
+		return false, err
+	}
+	bigAsAtom, err := util.ResolveToAtom(env, bigAs)
+	if err != nil {
+		return false, err
+	}
+	switch bigAsAtom.String() {
+	case "atom":
+		return false, nil
+	case "big":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid big_integer_as option: %s, valid values are 'atom' or 'big'", bigAsAtom)
+	}
+}
+
+// JSONRead is a predicate that parses a JSON document from the given source into Prolog terms, with options to bound
+// the resources used.
+//
+// The signature is as follows:
+//
+//	json_read(+Source, -Term, +Options) is det
+//
+// Where:
+//   - Source is the JSON document, given either as an Atom or as a list of bytes (UTF-8 encoded).
+//   - Term is the Prolog representation of the JSON document, using the same mapping as json_prolog/2.
+//   - Options are additional configurations for the parsing. Supported options include: max_depth(+N) which
+//     specifies the maximum nesting depth of arrays and objects allowed in the document (unbounded by default),
+//     value_string_as(+Type) which specifies how the JSON strings values are represented: either as an Atom (atom,
+//     the default) or as a list of character codes (codes), object keys being always represented as Atoms, and
+//     big_integer_as(+Type) which specifies how the integer numbers which do not fit in a 64-bit signed integer are
+//     represented: either as a big(Atom) term (big, the default, as for json_prolog/2) or as an Atom (atom), the Atom
+//     holding the decimal representation of the number. The former allows to convert them back into JSON numbers
+//     with json_prolog/2, while the latter converts them into JSON strings.
+//
+// The document is parsed incrementally, so the parsing stops as soon as an error is encountered or the maximum depth
+// is exceeded, without building the whole term. Parse errors carry the byte offset in the Source where the error
+// occurred. Trailing data after the JSON value is rejected.
+//
+// Examples:
+//
+//	# Parse a JSON document, bounding its nesting depth.
+//	- json_read('{"foo": ["bar"]}', Term, [max_depth(2)]).
+//
+//	# Parse a JSON document given as a list of bytes, representing its string values as codes.
+//	- json_read([34, 102, 111, 111, 34], Term, [value_string_as(codes)]).
+//
+//	# Parse a JSON document preserving its big integers, and convert it back into JSON.
+//	- json_read('{"amount": 100000000000000000000}', Term, []), json_prolog(Json, Term).
+func JSONRead(vm *engine.VM, source, term, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		reader, err := newJSONReader(options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("json_read/3: %w", err))
+		}
+
+		var data []byte
+		switch s := env.Resolve(source).(type) {
+		case engine.Atom:
+			data = []byte(s.String())
+		default:
+			data, err = TermToBytes(ctx, s, AtomEncoding.Apply(AtomOctet), env)
 			if err != nil {
-				return engine.Error(fmt.Errorf("json_prolog/2: %w", err))
+				return engine.Error(fmt.Errorf("json_read/3: invalid source: %w", err))
 			}
-			return engine.Unify(vm, j, util.StringToTerm(string(b)), cont, env)
 		}
+
+		result, err := reader.read(data)
+		if err != nil {
+			return engine.Error(fmt.Errorf("json_read/3: %w", err))
+		}
+
+		return engine.Unify(vm, term, result, cont, env)
+	})
+}
+
+// jsonReader parses a JSON document token by token into Prolog terms.
+type jsonReader struct {
+	decoder      *json.Decoder
+	maxDepth     int
+	stringAs     engine.Atom
+	bigAsWrapper bool
+}
+
+// newJSONReader creates a new jsonReader configured from the given options.
+func newJSONReader(options engine.Term, env *engine.Env) (*jsonReader, error) {
+	reader := &jsonReader{maxDepth: -1}
+
+	maxDepth, err := util.GetOption(engine.NewAtom("max_depth"), options, env)
+	if err != nil {
+		return nil, err
+	}
+	if maxDepth != nil {
+		n, ok := env.Resolve(maxDepth).(engine.Integer)
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("invalid max_depth option: %v, should be a non-negative Integer", env.Resolve(maxDepth))
+		}
+		reader.maxDepth = int(n)
+	}
+
+	stringAs, err := util.GetOptionWithDefault(engine.NewAtom("value_string_as"), options, engine.NewAtom("atom"), env)
+	if err != nil {
+		return nil, err
+	}
+	reader.bigAsWrapper, err = bigIntegerAsOption(options, env)
+	if err != nil {
+		return nil, err
+	}
+
+	reader.stringAs, err = util.ResolveToAtom(env, stringAs)
+	if err != nil {
+		return nil, err
+	}
+	if reader.stringAs.String() != "atom" && reader.stringAs.String() != "codes" {
+		return nil, fmt.Errorf("invalid value_string_as option: %s, valid values are 'atom' or 'codes'", reader.stringAs)
+	}
+
+	return reader, nil
+}
+
+// read parses the given JSON document.
+func (r *jsonReader) read(data []byte) (engine.Term, error) {
+	r.decoder = json.NewDecoder(bytes.NewReader(data))
+	r.decoder.UseNumber()
+
+	result, err := r.readValue(0)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := r.decoder.InputOffset()
+	if _, err := r.decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, r.errorf(offset, "unexpected data after JSON value")
+	}
+
+	return result, nil
+}
+
+// readValue reads the next JSON value, at the given nesting depth.
+func (r *jsonReader) readValue(depth int) (engine.Term, error) {
+	token, err := r.decoder.Token()
+	if err != nil {
+		return nil, r.wrapError(err)
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		if r.maxDepth >= 0 && depth >= r.maxDepth {
+			return nil, r.errorf(r.decoder.InputOffset()-1, "maximum depth of %d exceeded", r.maxDepth)
+		}
+		if t == '{' {
+			return r.readObject(depth + 1)
+		}
+		return r.readArray(depth + 1)
+	case string:
+		if r.stringAs.String() == "codes" {
+			return engine.List(util.Map([]rune(t), func(c rune) engine.Term { return engine.Integer(c) })...), nil
+		}
+		return util.StringToTerm(t), nil
+	default:
+		return jsonToTerms(t, r.bigAsWrapper)
+	}
+}
+
+// readObject reads the members of a JSON object, once its opening delimiter has been read.
+func (r *jsonReader) readObject(depth int) (engine.Term, error) {
+	members := make(map[string]engine.Term)
+	for r.decoder.More() {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return nil, r.wrapError(err)
+		}
+		key, ok := token.(string)
+		if !ok {
+			return nil, r.errorf(r.decoder.InputOffset(), "invalid object key")
+		}
+
+		value, err := r.readValue(depth)
+		if err != nil {
+			return nil, err
+		}
+		members[key] = value
+	}
+	if _, err := r.decoder.Token(); err != nil {
+		return nil, r.wrapError(err)
+	}
+
+	keys := lo.Keys(members)
+	sort.Strings(keys)
+
+	attributes := make([]engine.Term, 0, len(members))
+	for _, key := range keys {
+		attributes = append(attributes, AtomPair.Apply(engine.NewAtom(key), members[key]))
+	}
+	return AtomJSON.Apply(engine.List(attributes...)), nil
+}
+
+// readArray reads the elements of a JSON array, once its opening delimiter has been read.
+func (r *jsonReader) readArray(depth int) (engine.Term, error) {
+	elements := make([]engine.Term, 0)
+	for r.decoder.More() {
+		element, err := r.readValue(depth)
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+	}
+	if _, err := r.decoder.Token(); err != nil {
+		return nil, r.wrapError(err)
+	}
+
+	if len(elements) == 0 {
+		return MakeEmptyArray(), nil
+	}
+	return engine.List(elements...), nil
+}
+
+// errorf returns a new error located at the given offset of the document.
+func (r *jsonReader) errorf(offset int64, format string, args ...any) error {
+	return fmt.Errorf("invalid JSON at offset %d: %s", offset, fmt.Sprintf(format, args...))
+}
+
+// wrapError wraps the given parsing error with the offset where it occurred.
+func (r *jsonReader) wrapError(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("invalid JSON at offset %d: %w", syntaxErr.Offset, err)
+	}
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid JSON at offset %d: %w", r.decoder.InputOffset(), io.ErrUnexpectedEOF)
+	}
+	return fmt.Errorf("invalid JSON at offset %d: %w", r.decoder.InputOffset(), err)
+}
+
+// JSONGet is a predicate that unifies the value addressed by a JSON Pointer in a JSON document.
+//
+// The signature is as follows:
+//
+//	json_get(+Json, +Pointer, -Value) is semidet
+//
+// Where:
+//   - Json is the JSON document, given either as an Atom holding its textual representation, or as a term as
+//     produced by json_prolog/2.
+//   - Pointer is the JSON Pointer, as an Atom, compliant with [RFC 6901]: either the empty atom, addressing the whole
+//     document, or a sequence of reference tokens each prefixed by a /, where ~1 and ~0 respectively stand for / and ~.
+//   - Value is the addressed value, as a term using the same representation as json_prolog/2.
+//
+// The predicate fails if the Pointer addresses a value which does not exist in the document, i.e. a missing object
+// member or an out of range array index, so it can be used as a guard.
+//
+// Examples:
+//
+//	# Get a nested value of a JSON document.
+//	- json_get('{"foo": {"bar": ["a", "b"]}}', '/foo/bar/1', Value).
+//
+//	# Get a value of an already parsed JSON document.
+//	- json_prolog('{"foo": "bar"}', Term), json_get(Term, '/foo', Value).
+//
+// [RFC 6901]: https://datatracker.ietf.org/doc/html/rfc6901
+func JSONGet(vm *engine.VM, j, pointer, value engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		pointerAtom, err := util.ResolveToAtom(env, pointer)
+		if err != nil {
+			return engine.Error(fmt.Errorf("json_get/3: %w", err))
+		}
+		tokens, err := parseJSONPointer(pointerAtom.String())
+		if err != nil {
+			return engine.Error(fmt.Errorf("json_get/3: %w", err))
+		}
+
+		document := env.Resolve(j)
+		if atom, ok := document.(engine.Atom); ok {
+			document, err = jsonStringToTerms(atom.String(), false)
+			if err != nil {
+				return engine.Error(fmt.Errorf("json_get/3: %w", err))
+			}
+		}
+
+		for _, token := range tokens {
+			var found bool
+			document, found, err = jsonTermChild(document, token, env)
+			if err != nil {
+				return engine.Error(fmt.Errorf("json_get/3: %w", err))
+			}
+			if !found {
+				return engine.Bool(false)
+			}
+		}
+
+		return engine.Unify(vm, value, document, cont, env)
+	})
+}
+
+// JSONSortBy is a predicate that sorts a JSON array of objects by the value found at the given key path.
+//
+// The signature is as follows:
+//
+//	json_sort_by(+Json, +KeyPath, +Order, -Sorted) is det
+//
+// Where:
+//   - Json is the JSON array to sort, given either as an Atom holding its textual representation, or as a term as
+//     produced by json_prolog/2.
+//   - KeyPath is the list of the keys leading to the value to sort on, each key being an Atom (an Integer being
+//     accepted as an index in a nested array).
+//   - Order is either ascending or descending.
+//   - Sorted is the sorted JSON array, as a term using the same representation as json_prolog/2.
+//
+// The values are compared according to the standard order of terms, so numbers are ordered by their value and come
+// before atoms, ordered alphabetically. The sort is stable: elements with equal values keep their relative order,
+// whatever the Order. Elements for which KeyPath addresses no value (including elements which are not objects) are
+// always placed at the end of the array, in their original relative order.
+//
+// Examples:
+//
+//	# Sort an array of objects by a numeric field.
+//	- json_sort_by('[{"name": "b", "age": 42}, {"name": "a", "age": 7}]', [age], ascending, Sorted).
+func JSONSortBy(vm *engine.VM, j, keyPath, order, sorted engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		orderAtom, err := util.ResolveToAtom(env, order)
+		if err != nil {
+			return engine.Error(fmt.Errorf("json_sort_by/4: %w", err))
+		}
+		if orderAtom.String() != "ascending" && orderAtom.String() != "descending" {
+			return engine.Error(fmt.Errorf("json_sort_by/4: invalid order: %s, valid values are 'ascending' or 'descending'",
+				orderAtom))
+		}
+
+		tokens, err := termToJSONKeyPath(keyPath, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("json_sort_by/4: %w", err))
+		}
+
+		document := env.Resolve(j)
+		if atom, ok := document.(engine.Atom); ok {
+			document, err = jsonStringToTerms(atom.String(), false)
+			if err != nil {
+				return engine.Error(fmt.Errorf("json_sort_by/4: %w", err))
+			}
+		}
+		if MakeEmptyArray().Compare(document, env) == 0 {
+			return engine.Unify(vm, sorted, document, cont, env)
+		}
+		if c, ok := document.(engine.Compound); !ok || !util.IsList(c) {
+			return engine.Error(fmt.Errorf("json_sort_by/4: invalid JSON type: %T, should be a JSON array", document))
+		}
+
+		type entry struct {
+			element engine.Term
+			value   engine.Term
+		}
+		entries := make([]entry, 0)
+		iter := engine.ListIterator{List: document, Env: env}
+		for iter.Next() {
+			value, found := iter.Current(), true
+			for _, token := range tokens {
+				if value, found, err = jsonTermChild(value, token, env); err != nil {
+					return engine.Error(fmt.Errorf("json_sort_by/4: %w", err))
+				} else if !found {
+					break
+				}
+			}
+			if !found {
+				value = nil
+			}
+			entries = append(entries, entry{element: iter.Current(), value: value})
+		}
+
+		descending := orderAtom.String() == "descending"
+		sort.SliceStable(entries, func(i, j int) bool {
+			switch {
+			case entries[i].value == nil:
+				return false
+			case entries[j].value == nil:
+				return true
+			case descending:
+				return entries[i].value.Compare(entries[j].value, env) > 0
+			default:
+				return entries[i].value.Compare(entries[j].value, env) < 0
+			}
+		})
+
+		return engine.Unify(vm, sorted, engine.List(util.Map(entries, func(e entry) engine.Term { return e.element })...), cont, env)
 	})
 }
 
-func jsonStringToTerms(j string) (engine.Term, error) {
+// termToJSONKeyPath converts the given list of keys into the list of the corresponding reference tokens.
+func termToJSONKeyPath(keyPath engine.Term, env *engine.Env) ([]string, error) {
+	tokens := make([]string, 0)
+	iter := engine.ListIterator{List: keyPath, Env: env}
+	for iter.Next() {
+		switch k := env.Resolve(iter.Current()).(type) {
+		case engine.Atom:
+			tokens = append(tokens, k.String())
+		case engine.Integer:
+			tokens = append(tokens, strconv.FormatInt(int64(k), 10))
+		default:
+			return nil, fmt.Errorf("invalid key type: %T, should be an Atom or an Integer", k)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("invalid key path: %w", err)
+	}
+
+	return tokens, nil
+}
+
+var (
+	// jsonPointerInvalidEscapeRegexp matches a reference token containing an invalid escape sequence.
+	jsonPointerInvalidEscapeRegexp = regexp.MustCompile(`~([^01]|$)`)
+
+	// jsonPointerIndexRegexp matches a reference token being a valid array index.
+	jsonPointerIndexRegexp = regexp.MustCompile(`^(0|[1-9][0-9]*)$`)
+)
+
+// parseJSONPointer parses the given JSON Pointer into its unescaped reference tokens.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer: %s, should be empty or start with '/'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		if jsonPointerInvalidEscapeRegexp.MatchString(token) {
+			return nil, fmt.Errorf("invalid JSON pointer: %s, invalid escape sequence in '%s'", pointer, token)
+		}
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// jsonTermChild returns the child of the given JSON term addressed by the given reference token, and whether it exists.
+func jsonTermChild(term engine.Term, token string, env *engine.Env) (engine.Term, bool, error) {
+	switch t := env.Resolve(term).(type) {
+	case engine.Compound:
+		switch {
+		case t.Functor() == AtomJSON:
+			members, err := ExtractJSONTerm(t, env)
+			if err != nil {
+				return nil, false, err
+			}
+			child, ok := members[token]
+			return child, ok, nil
+		case util.IsList(t):
+			if !jsonPointerIndexRegexp.MatchString(token) {
+				return nil, false, nil
+			}
+			index, err := strconv.Atoi(token)
+			if err != nil {
+				return nil, false, nil //nolint:nilerr // an index too large to be parsed is out of range.
+			}
+			iter := engine.ListIterator{List: t, Env: env}
+			for i := 0; iter.Next(); i++ {
+				if i == index {
+					return iter.Current(), true, nil
+				}
+			}
+			return nil, false, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+func jsonStringToTerms(j string, bigAsWrapper bool) (engine.Term, error) {
 	var values any
 	decoder := json.NewDecoder(strings.NewReader(j))
 	decoder.UseNumber() // unmarshal a number into an interface{} as a Number instead of as a float64
@@ -79,7 +572,7 @@ func jsonStringToTerms(j string) (engine.Term, error) {
 		return nil, err
 	}
 
-	return jsonToTerms(values)
+	return jsonToTerms(values, bigAsWrapper)
 }
 
 func termsToJSON(term engine.Term, env *engine.Env) ([]byte, error) {
@@ -121,6 +614,19 @@ func termsToJSON(term engine.Term, env *engine.Env) ([]byte, error) {
 				attributes[key] = raw
 			}
 			return json.Marshal(attributes)
+		case AtomBig.String():
+			if t.Arity() != 1 {
+				return nil, fmt.Errorf("wrong term arity for big integer, give %d, expected %d", t.Arity(), 1)
+			}
+			n, err := util.ResolveToAtom(env, t.Arg(0))
+			if err != nil {
+				return nil, err
+			}
+			r, ok := new(big.Int).SetString(n.String(), 10)
+			if !ok {
+				return nil, fmt.Errorf("invalid big integer: %s", n)
+			}
+			return []byte(r.String()), nil
 		}
 
 		switch {
@@ -140,17 +646,20 @@ func termsToJSON(term engine.Term, env *engine.Env) ([]byte, error) {
 	}
 }
 
-func jsonToTerms(value any) (engine.Term, error) {
+func jsonToTerms(value any, bigAsWrapper bool) (engine.Term, error) {
 	switch v := value.(type) {
 	case string:
 		return util.StringToTerm(v), nil
 	case json.Number:
-		r, ok := math.NewIntFromString(string(v))
+		r, ok := new(big.Int).SetString(string(v), 10)
 		if !ok {
 			return nil, fmt.Errorf("could not convert number '%s' into integer term, decimal number is not handled yet", v)
 		}
 		if !r.IsInt64() {
-			return nil, fmt.Errorf("could not convert number '%s' into integer term, overflow", v)
+			if bigAsWrapper {
+				return AtomBig.Apply(engine.NewAtom(r.String())), nil
+			}
+			return engine.NewAtom(r.String()), nil
 		}
 		return engine.Integer(r.Int64()), nil
 	case bool:
@@ -163,7 +672,7 @@ func jsonToTerms(value any) (engine.Term, error) {
 
 		attributes := make([]engine.Term, 0, len(v))
 		for _, key := range keys {
-			attributeValue, err := jsonToTerms(v[key])
+			attributeValue, err := jsonToTerms(v[key], bigAsWrapper)
 			if err != nil {
 				return nil, err
 			}
@@ -177,7 +686,7 @@ func jsonToTerms(value any) (engine.Term, error) {
 		}
 
 		for _, element := range v {
-			term, err := jsonToTerms(element)
+			term, err := jsonToTerms(element, bigAsWrapper)
 			if err != nil {
 				return nil, err
 			}
@@ -188,3 +697,309 @@ func jsonToTerms(value any) (engine.Term, error) {
 		return nil, fmt.Errorf("could not convert %s (%T) to a prolog term", v, v)
 	}
 }
+
+// JSONCanonical is a predicate that serializes a JSON document into its canonical form, as specified by the JSON
+// Canonicalization Scheme (JCS), so that it gives the same bytes to hash or sign whatever the way it has been written.
+//
+// The signature is as follows:
+//
+//	json_canonical(+Json, -Canonical) is det
+//
+// Where:
+//   - Json is the JSON document, given either as an Atom holding its textual representation, or as a term as
+//     produced by json_prolog/2, Floats and big(Atom) terms being accepted as numbers.
+//   - Canonical is the canonical form of Json, as a list of bytes (UTF-8 encoded). When given as an Atom, it is
+//     compared with the text of the canonical form instead.
+//
+// The canonical form, compliant with [RFC 8785], has no insignificant whitespace, its object members are sorted by
+// their keys, compared as sequences of UTF-16 code units, and its strings are escaped only where required, as
+// JSON.stringify does in ECMAScript. The numbers are IEEE 754 double precision values, serialized as ECMAScript does,
+// e.g. 1E30 as 1e+30 and 4.50 as 4.5: an integer beyond 2^53 in magnitude is therefore rounded to the nearest double,
+// and shall be conveyed as a string to keep its precision. A number out of the range of the doubles, or an object
+// with duplicated keys in a textual Json, is rejected.
+//
+// Examples:
+//
+//	# Get the canonical form of a JSON document as text.
+//	- json_canonical('{"b": [1E2, 0.50], "a": true}', '{"a":true,"b":[100,0.5]}').
+//
+//	# Hash a JSON document whatever its formatting.
+//	- json_canonical('{ "amount": 100, "denom": "uknow" }', Canonical), sha3_hash(Canonical, Hash, []).
+//
+// [RFC 8785]: https://datatracker.ietf.org/doc/html/rfc8785
+func JSONCanonical(vm *engine.VM, j, canonical engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		var result []byte
+		var err error
+		switch t := env.Resolve(j).(type) {
+		case engine.Variable:
+			return engine.Error(engine.InstantiationError(env))
+		case engine.Atom:
+			result, err = canonicalJSONText([]byte(t.String()))
+		default:
+			result, err = canonicalJSONTerm(t, env)
+		}
+		if err != nil {
+			return engine.Error(fmt.Errorf("json_canonical/2: %w", err))
+		}
+
+		if atom, ok := env.Resolve(canonical).(engine.Atom); ok && atom != util.AtomEmptyList {
+			return engine.Unify(vm, canonical, engine.NewAtom(string(result)), cont, env)
+		}
+		return engine.Unify(vm, canonical, BytesToList(result), cont, env)
+	})
+}
+
+// canonicalJSONText returns the canonical form of the given textual JSON document (see json_canonical/2).
+func canonicalJSONText(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	result, err := canonicalJSONValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after JSON value")
+	}
+
+	return result, nil
+}
+
+// canonicalJSONValue reads the next JSON value from the given decoder and returns its canonical form.
+func canonicalJSONValue(decoder *json.Decoder) ([]byte, error) {
+	token, err := decoder.Token()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid JSON: %w", io.ErrUnexpectedEOF)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		if t == '{' {
+			members := make(map[string][]byte)
+			for decoder.More() {
+				token, err := decoder.Token()
+				if err != nil {
+					return nil, fmt.Errorf("invalid JSON: %w", err)
+				}
+				key, ok := token.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid JSON: invalid object key")
+				}
+				if _, ok := members[key]; ok {
+					return nil, fmt.Errorf("invalid JSON: duplicated key '%s'", key)
+				}
+				if members[key], err = canonicalJSONValue(decoder); err != nil {
+					return nil, err
+				}
+			}
+			if _, err := decoder.Token(); err != nil {
+				return nil, fmt.Errorf("invalid JSON: %w", err)
+			}
+			return canonicalJSONObject(members)
+		}
+
+		elements := make([][]byte, 0)
+		for decoder.More() {
+			element, err := canonicalJSONValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+		}
+		if _, err := decoder.Token(); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return canonicalJSONArray(elements), nil
+	case string:
+		return canonicalJSONString(t)
+	case json.Number:
+		f, err := strconv.ParseFloat(t.String(), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number: %s, out of the range of the doubles", t)
+		}
+		return canonicalJSONNumber(f)
+	case bool:
+		return strconv.AppendBool(nil, t), nil
+	default:
+		return []byte("null"), nil
+	}
+}
+
+// canonicalJSONTerm returns the canonical form of the given JSON term (see json_canonical/2).
+func canonicalJSONTerm(term engine.Term, env *engine.Env) ([]byte, error) {
+	switch t := env.Resolve(term).(type) {
+	case engine.Atom:
+		return canonicalJSONString(t.String())
+	case engine.Integer:
+		return canonicalJSONNumber(float64(t))
+	case engine.Float:
+		return canonicalJSONNumber(float64(t))
+	case engine.Compound:
+		switch {
+		case util.IsList(t):
+			elements := make([][]byte, 0)
+			iter := engine.ListIterator{List: t, Env: env}
+			for iter.Next() {
+				element, err := canonicalJSONTerm(iter.Current(), env)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, element)
+			}
+			if err := iter.Err(); err != nil {
+				return nil, err
+			}
+			return canonicalJSONArray(elements), nil
+		case t.Functor() == AtomJSON:
+			terms, err := ExtractJSONTerm(t, env)
+			if err != nil {
+				return nil, err
+			}
+			members := make(map[string][]byte, len(terms))
+			for key, term := range terms {
+				if members[key], err = canonicalJSONTerm(term, env); err != nil {
+					return nil, err
+				}
+			}
+			return canonicalJSONObject(members)
+		case t.Functor() == AtomBig && t.Arity() == 1:
+			n, err := util.ResolveToAtom(env, t.Arg(0))
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := new(big.Int).SetString(n.String(), 10); !ok {
+				return nil, fmt.Errorf("invalid big integer: %s", n)
+			}
+			f, err := strconv.ParseFloat(n.String(), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number: %s, out of the range of the doubles", n)
+			}
+			return canonicalJSONNumber(f)
+		case MakeBool(true).Compare(t, env) == 0:
+			return []byte("true"), nil
+		case MakeBool(false).Compare(t, env) == 0:
+			return []byte("false"), nil
+		case MakeEmptyArray().Compare(t, env) == 0:
+			return []byte("[]"), nil
+		case MakeNull().Compare(t, env) == 0:
+			return []byte("null"), nil
+		}
+		return nil, fmt.Errorf("invalid functor %s", t.Functor())
+	default:
+		return nil, fmt.Errorf("could not convert %s {%T} to json", t, t)
+	}
+}
+
+// canonicalJSONObject returns the canonical form of the JSON object with the given members, given in their canonical
+// form, sorted by their keys compared as sequences of UTF-16 code units.
+func canonicalJSONObject(members map[string][]byte) ([]byte, error) {
+	keys := lo.Keys(members)
+	units := make(map[string][]uint16, len(keys))
+	for _, key := range keys {
+		units[key] = utf16.Encode([]rune(key))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return slices.Compare(units[keys[i]], units[keys[j]]) < 0
+	})
+
+	result := []byte{'{'}
+	for i, key := range keys {
+		if i > 0 {
+			result = append(result, ',')
+		}
+		k, err := canonicalJSONString(key)
+		if err != nil {
+			return nil, err
+		}
+		result = append(append(append(result, k...), ':'), members[key]...)
+	}
+	return append(result, '}'), nil
+}
+
+// canonicalJSONArray returns the canonical form of the JSON array with the given elements, given in their canonical
+// form.
+func canonicalJSONArray(elements [][]byte) []byte {
+	return append(append([]byte{'['}, bytes.Join(elements, []byte{','})...), ']')
+}
+
+// canonicalJSONString returns the canonical form of the given string, which escapes only the quotation mark, the
+// reverse solidus and the control characters, the latter by their short escape sequence if any.
+func canonicalJSONString(s string) ([]byte, error) {
+	if !utf8.ValidString(s) {
+		return nil, fmt.Errorf("invalid string: %q, should be UTF-8 encoded", s)
+	}
+
+	result := []byte{'"'}
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			result = append(result, '\\', byte(r))
+		case '\b':
+			result = append(result, `\b`...)
+		case '\f':
+			result = append(result, `\f`...)
+		case '\n':
+			result = append(result, `\n`...)
+		case '\r':
+			result = append(result, `\r`...)
+		case '\t':
+			result = append(result, `\t`...)
+		default:
+			if r < 0x20 {
+				result = fmt.Appendf(result, `\u%04x`, r)
+			} else {
+				result = utf8.AppendRune(result, r)
+			}
+		}
+	}
+	return append(result, '"'), nil
+}
+
+// canonicalJSONNumber returns the canonical form of the given number, i.e. its shortest decimal representation
+// identifying it among the doubles, formatted as the ECMAScript Number.prototype.toString does.
+func canonicalJSONNumber(f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("invalid number: %v, should be finite", f)
+	}
+	if f == 0 {
+		return []byte("0"), nil
+	}
+
+	var result []byte
+	if f < 0 {
+		result, f = append(result, '-'), -f
+	}
+
+	// The shortest digits, and the position n of the decimal point relative to them, i.e. f = 0.digits × 10^n.
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(f, 'e', -1, 64), "e")
+	digits := strings.Replace(mantissa, ".", "", 1)
+	e, err := strconv.Atoi(exponent)
+	if err != nil {
+		return nil, err
+	}
+	k, n := len(digits), e+1
+
+	switch {
+	case k <= n && n <= 21:
+		result = append(append(result, digits...), strings.Repeat("0", n-k)...)
+	case 0 < n && n <= 21:
+		result = append(append(append(result, digits[:n]...), '.'), digits[n:]...)
+	case -6 < n && n <= 0:
+		result = append(append(append(result, "0."...), strings.Repeat("0", -n)...), digits...)
+	default:
+		result = append(result, digits[0])
+		if k > 1 {
+			result = append(append(result, '.'), digits[1:]...)
+		}
+		result = append(result, 'e')
+		if n-1 >= 0 {
+			result = append(result, '+')
+		}
+		result = strconv.AppendInt(result, int64(n-1), 10)
+	}
+	return result, nil
+}
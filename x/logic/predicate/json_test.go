@@ -3,6 +3,7 @@ package predicate
 
 import (
 	"fmt"
+	"math"
 	"testing"
 
 	"github.com/ichiban/prolog/engine"
@@ -108,8 +109,40 @@ func TestJsonProlog(t *testing.T) {
 			{
 				description: "convert large json number into prolog",
 				query:       `json_prolog('100000000000000000000', Term).`,
+				wantResult: []types.TermResults{{
+					"Term": "big('100000000000000000000')",
+				}},
+				wantSuccess: true,
+			},
+			{
+				description: "convert large negative json number into prolog",
+				query:       `json_prolog('-9223372036854775809', Term).`,
+				wantResult: []types.TermResults{{
+					"Term": "big('-9223372036854775809')",
+				}},
+				wantSuccess: true,
+			},
+			{
+				description: "convert the largest 64-bit json integer into prolog",
+				query:       `json_prolog('9223372036854775807', Term).`,
+				wantResult: []types.TermResults{{
+					"Term": "9223372036854775807",
+				}},
+				wantSuccess: true,
+			},
+			{
+				description: "convert json object with large number into prolog",
+				query:       `json_prolog('{"amount": 340282366920938463463374607431768211456, "denom": "uknow"}', Term).`,
+				wantResult: []types.TermResults{{
+					"Term": "json([amount-big('340282366920938463463374607431768211456'),denom-uknow])",
+				}},
+				wantSuccess: true,
+			},
+			{
+				description: "decimal number with exponent not compatible yet",
+				query:       `json_prolog('1e3', Term).`,
 				wantSuccess: false,
-				wantError:   fmt.Errorf("json_prolog/2: could not convert number '100000000000000000000' into integer term, overflow"),
+				wantError:   fmt.Errorf("json_prolog/2: could not convert number '1e3' into integer term, decimal number is not handled yet"),
 			},
 			{
 				description: "decimal number not compatible yet",
@@ -256,6 +289,14 @@ func TestJsonProlog(t *testing.T) {
 				}},
 				wantSuccess: true,
 			},
+			{
+				description: "ensure determinism on nested object attribute key sorted alphabetically",
+				query:       `json_prolog(Json, json([z-json([y-1,x-[json([w-2,v-3])]]),a-b])).`,
+				wantResult: []types.TermResults{{
+					"Json": "'{\"a\":\"b\",\"z\":{\"x\":[{\"v\":3,\"w\":2}],\"y\":1}}'",
+				}},
+				wantSuccess: true,
+			},
 			{
 				description: "invalid json term compound",
 				query:       `json_prolog(Json, foo([a-b])).`,
@@ -284,6 +325,59 @@ func TestJsonProlog(t *testing.T) {
 				}},
 				wantSuccess: true,
 			},
+			{
+				description: "convert the largest 64-bit integer from prolog without precision loss",
+				query:       `json_prolog(Json, 9223372036854775807).`,
+				wantResult: []types.TermResults{{
+					"Json": "'9223372036854775807'",
+				}},
+				wantSuccess: true,
+			},
+			{
+				description: "round trip a big integer through json_prolog/2",
+				query:       `json_prolog('{"a": 18446744073709551616}', Term), json_prolog(Json, Term).`,
+				wantResult: []types.TermResults{{
+					"Term": "json([a-big('18446744073709551616')])",
+					"Json": "'{\"a\":18446744073709551616}'",
+				}},
+				wantSuccess: true,
+			},
+			{
+				description: "convert big integer from prolog",
+				query:       `json_prolog(Json, json([amount-big('100000000000000000000')])).`,
+				wantResult: []types.TermResults{{
+					"Json": "'{\"amount\":100000000000000000000}'",
+				}},
+				wantSuccess: true,
+			},
+			{
+				description: "convert negative big integer from prolog",
+				query:       `json_prolog(Json, [big('-9223372036854775809')]).`,
+				wantResult: []types.TermResults{{
+					"Json": "'[-9223372036854775809]'",
+				}},
+				wantSuccess: true,
+			},
+			{
+				description: "convert big integer atom from prolog as a string",
+				query:       `json_prolog(Json, '100000000000000000000').`,
+				wantResult: []types.TermResults{{
+					"Json": "'\"100000000000000000000\"'",
+				}},
+				wantSuccess: true,
+			},
+			{
+				description: "invalid big integer",
+				query:       `json_prolog(Json, big('1.5')).`,
+				wantSuccess: false,
+				wantError:   fmt.Errorf("json_prolog/2: invalid big integer: 1.5"),
+			},
+			{
+				description: "invalid big integer arity",
+				query:       `json_prolog(Json, big('1', '2')).`,
+				wantSuccess: false,
+				wantError:   fmt.Errorf("json_prolog/2: wrong term arity for big integer, give 2, expected 1"),
+			},
 			{
 				description: "decimal number not compatible yet",
 				query:       `json_prolog(Json, 10.4).`,
@@ -454,6 +548,21 @@ func TestJsonPrologWithMoreComplexStructBidirectional(t *testing.T) {
 				term:        "json([object-json([array-[1,2,3],arrayobject-[json([name-toto]),json([name-tata])],bool- @(true),boolean- @(false),null- @(null)])])",
 				wantSuccess: true,
 			},
+			{
+				json:        "'{\"a\":{\"c\":[{\"e\":1,\"f\":2}],\"d\":2},\"b\":1}'",
+				term:        "json([a-json([c-[json([e-1,f-2])],d-2]),b-1])",
+				wantSuccess: true,
+			},
+			{
+				json:        `'{"a":{"c":[{"e":1,"f":2}],"d":2},"b":1}'`,
+				term:        `json([a-json([c-[json([e-1,f-2])],d-2]),b-1])`,
+				wantSuccess: true,
+			},
+			{
+				json:        `'{"foo":{}}'`,
+				term:        `json([foo-json([])])`,
+				wantSuccess: true,
+			},
 			{
 				json:        "'{\"foo\":\"bar\"}'",
 				term:        "json([a-b])",
@@ -579,3 +688,676 @@ func TestJsonPrologWithMoreComplexStructBidirectional(t *testing.T) {
 		}
 	})
 }
+
+func TestJSONRead(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `json_read('{"foo": ["bar", 1, true, null, {}, []]}', Term, []).`,
+				wantResult:  []types.TermResults{{"Term": "json([foo-[bar,1,@(true),@(null),json([]),@([])]])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_read('{"b": "a", "a": {"d": 100000000000000000000, "c": "b"}}', Term, []).`,
+				wantResult:  []types.TermResults{{"Term": "json([a-json([c-b,d-big('100000000000000000000')]),b-a])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_read([123, 34, 102, 111, 111, 34, 58, 34, 98, 97, 114, 34, 125], Term, []).`,
+				wantResult:  []types.TermResults{{"Term": "json([foo-bar])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_read('{"foo": "bar"}', Term, [value_string_as(codes)]).`,
+				wantResult:  []types.TermResults{{"Term": "json([foo-[98,97,114]])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_read('"héllo"', Term, [value_string_as(codes)]).`,
+				wantResult:  []types.TermResults{{"Term": "[104,233,108,108,111]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_read('{"foo": "bar"}', json([foo-bar]), [value_string_as(atom)]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_read('{"foo": ["bar"]}', Term, [max_depth(2)]).`,
+				wantResult:  []types.TermResults{{"Term": "json([foo-[bar]])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_read('"foo"', Term, [max_depth(0)]).`,
+				wantResult:  []types.TermResults{{"Term": "foo"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_read('{"foo": [["bar"]]}', Term, [max_depth(2)]).`,
+				wantError:   fmt.Errorf("json_read/3: invalid JSON at offset 9: maximum depth of 2 exceeded"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_read('{"foo": bar}', Term, []).`,
+				wantError:   fmt.Errorf("json_read/3: invalid JSON at offset 9: invalid character 'b' looking for beginning of value"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_read('{"foo": "bar"', Term, []).`,
+				wantError:   fmt.Errorf("json_read/3: invalid JSON at offset 13: unexpected end of JSON input"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_read('{"foo": "bar"} {}', Term, []).`,
+				wantError:   fmt.Errorf("json_read/3: invalid JSON at offset 14: unexpected data after JSON value"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_read('{"a": 9223372036854775808, "b": -9223372036854775809, "c": 42}', Term, [big_integer_as(big)]).`,
+				wantResult:  []types.TermResults{{"Term": "json([a-big('9223372036854775808'),b-big('-9223372036854775809'),c-42])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_read('[9223372036854775808]', Term, [big_integer_as(atom)]).`,
+				wantResult:  []types.TermResults{{"Term": "['9223372036854775808']"}},
+				wantSuccess: true,
+			},
+			{
+				program: `round_trip(In, Out) :- json_read(In, Term, [big_integer_as(big)]), json_prolog(Out, Term).`,
+				query:   `round_trip('{"max": 9223372036854775807, "over": 9223372036854775808, "min": -9223372036854775808, "under": -9223372036854775809, "u64": 18446744073709551616}', Out).`,
+				wantResult: []types.TermResults{{
+					"Out": `'{"max":9223372036854775807,"min":-9223372036854775808,"over":9223372036854775808,"u64":18446744073709551616,"under":-9223372036854775809}'`,
+				}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_read('1', Term, [big_integer_as(string)]).`,
+				wantError:   fmt.Errorf("json_read/3: invalid big_integer_as option: string, valid values are 'atom' or 'big'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_read('1.5', Term, []).`,
+				wantError:   fmt.Errorf("json_read/3: could not convert number '1.5' into integer term, decimal number is not handled yet"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_read('{}', Term, [max_depth(-1)]).`,
+				wantError:   fmt.Errorf("json_read/3: invalid max_depth option: -1, should be a non-negative Integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_read('{}', Term, [value_string_as(string)]).`,
+				wantError:   fmt.Errorf("json_read/3: invalid value_string_as option: string, valid values are 'atom' or 'codes'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_read(42, Term, []).`,
+				wantError:   fmt.Errorf("json_read/3: invalid source: term should be a List, given engine.Integer"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("json_read"), JSONRead)
+						interpreter.Register2(engine.NewAtom("json_prolog"), JSONProlog)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestJSONGet(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `json_get('{"foo": {"bar": ["a", "b"]}}', '/foo/bar/1', Value).`,
+				wantResult:  []types.TermResults{{"Value": "b"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_get('{"foo": {"bar": ["a", "b"]}}', '/foo', Value).`,
+				wantResult:  []types.TermResults{{"Value": "json([bar-[a,b]])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_get('{"foo": "bar"}', '', Value).`,
+				wantResult:  []types.TermResults{{"Value": "json([foo-bar])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_get('{"a/b": 1, "m~n": 2, "": 3}', '/a~1b', Value).`,
+				wantResult:  []types.TermResults{{"Value": "1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_get('{"a/b": 1, "m~n": 2, "": 3}', '/m~0n', Value).`,
+				wantResult:  []types.TermResults{{"Value": "2"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_get('{"a/b": 1, "m~n": 2, "": 3}', '/', Value).`,
+				wantResult:  []types.TermResults{{"Value": "3"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_get('{"price": {"amount": 100000000000000000000}}', '/price/amount', Value).`,
+				wantResult:  []types.TermResults{{"Value": "'100000000000000000000'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_get('{"foo": [true, null]}', '/foo/0', Value).`,
+				wantResult:  []types.TermResults{{"Value": "@(true)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_get(json([foo-json([bar-[a,b]])]), '/foo/bar/0', Value).`,
+				wantResult:  []types.TermResults{{"Value": "a"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_get('{"foo": "bar"}', '/foo', bar).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_get('{"foo": "bar"}', '/foo', baz).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `json_get('{"foo": "bar"}', '/baz', Value).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `json_get('{"foo": ["a", "b"]}', '/foo/2', Value).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `json_get('{"foo": ["a", "b"]}', '/foo/01', Value).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `json_get('{"foo": ["a", "b"]}', '/foo/-', Value).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `json_get('{"foo": []}', '/foo/0', Value).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `json_get('{"foo": {}}', '/foo/bar', Value).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `json_get('{"foo": "bar"}', '/foo/bar', Value).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `json_get('{"foo": "bar"}', 'foo', Value).`,
+				wantError:   fmt.Errorf("json_get/3: invalid JSON pointer: foo, should be empty or start with '/'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_get('{"foo": "bar"}', '/foo~2', Value).`,
+				wantError:   fmt.Errorf("json_get/3: invalid JSON pointer: /foo~2, invalid escape sequence in 'foo~2'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_get('{"foo": "bar"', '/foo', Value).`,
+				wantError:   fmt.Errorf("json_get/3: unexpected EOF"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("json_get"), JSONGet)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestJSONSortBy(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `json_sort_by('[{"name": "b", "age": 42}, {"name": "a", "age": 7}, {"name": "c", "age": 13}]', [age], ascending, Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[json([age-7,name-a]),json([age-13,name-c]),json([age-42,name-b])]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_sort_by('[{"name": "b", "age": 42}, {"name": "a", "age": 7}, {"name": "c", "age": 13}]', [age], descending, Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[json([age-42,name-b]),json([age-13,name-c]),json([age-7,name-a])]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_sort_by('[{"id": 1, "n": 2}, {"id": 2, "n": 1}, {"id": 3, "n": 2}, {"id": 4, "n": 1}]', [n], ascending, Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[json([id-2,n-1]),json([id-4,n-1]),json([id-1,n-2]),json([id-3,n-2])]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_sort_by('[{"id": 1, "n": 2}, {"id": 2, "n": 1}, {"id": 3, "n": 2}, {"id": 4, "n": 1}]', [n], descending, Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[json([id-1,n-2]),json([id-3,n-2]),json([id-2,n-1]),json([id-4,n-1])]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_sort_by('[{"id": 1}, {"id": 2, "n": 5}, "x", {"id": 3, "n": 1}]', [n], descending, Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[json([id-2,n-5]),json([id-3,n-1]),json([id-1]),x]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_sort_by('[{"a": {"b": [0, "y"]}}, {"a": {"b": [0, "x"]}}]', [a, b, 1], ascending, Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[json([a-json([b-[0,x]])]),json([a-json([b-[0,y]])])]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_sort_by([json([k-b]), json([k-a])], [k], ascending, Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[json([k-a]),json([k-b])]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_sort_by('[]', [k], ascending, Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "@([])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_sort_by('{"k": 1}', [k], ascending, Sorted).`,
+				wantError:   fmt.Errorf("json_sort_by/4: invalid JSON type: *engine.compound, should be a JSON array"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_sort_by('[]', [k], up, Sorted).`,
+				wantError:   fmt.Errorf("json_sort_by/4: invalid order: up, valid values are 'ascending' or 'descending'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_sort_by('[]', [f(k)], ascending, Sorted).`,
+				wantError:   fmt.Errorf("json_sort_by/4: invalid key type: *engine.compound, should be an Atom or an Integer"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register4(engine.NewAtom("json_sort_by"), JSONSortBy)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestJSONCanonical(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `json_canonical('{"b": [1E2, 0.50], "a": true}', Canonical).`,
+				wantResult:  []types.TermResults{{"Canonical": "[123,34,97,34,58,116,114,117,101,44,34,98,34,58,91,49,48,48,44,48,46,53,93,125]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_canonical('{"b": [1E2, 0.50], "a": true}', '{"a":true,"b":[100,0.5]}').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_canonical('{ "b" : 1 , "a" : [ ] }', '{"a":[],"b":1}').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_canonical('{"b": 1, "a": 2}', '{"b":1,"a":2}').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `json_canonical('[]', []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `json_canonical('"<&>\\u2028"', '"<&>\x2028\"').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_canonical('[9007199254740993, -0, 1e21, 0.0000001, 100000000000000000000]', '[9007199254740992,0,1e+21,1e-7,100000000000000000000]').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_canonical(json([b-big('100000000000000000000'), a-[1.5, @(true), @(null)], c- @([]), d-json([])]), '{"a":[1.5,true,null],"b":100000000000000000000,"c":[],"d":{}}').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `json_prolog('{"denom": "uknow", "amount": 100}', Term), json_canonical(Term, '{"amount":100,"denom":"uknow"}').`,
+				wantResult:  []types.TermResults{{"Term": "json([amount-100,denom-uknow])"}},
+				wantSuccess: true,
+			},
+			// RFC 8785, section 3.2.2.
+			{
+				query:       `json_canonical('{"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001], "string": "\\u20ac$\\u000F\\u000aA''\\u0042\\u0022\\u005c\\\\\\"\\/", "literals": [null, true, false]}', '{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"\x20ac\$\\u000f\\nA''B\\"\\\\\\\\\\"/"}').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			// RFC 8785, section 3.2.3.
+			{
+				query:       `json_canonical('{"\\u20ac": "Euro Sign", "\\r": "Carriage Return", "\\ufb33": "Hebrew Letter Dalet With Dagesh", "1": "One", "\\ud83d\\ude00": "Emoji: Grinning Face", "\\u0080": "Control", "\\u00f6": "Latin Small Letter O With Diaeresis"}', '{"\\r":"Carriage Return","1":"One","\x80\":"Control","\xf6\":"Latin Small Letter O With Diaeresis","\x20ac\":"Euro Sign","\x1f600\":"Emoji: Grinning Face","\xfb33\":"Hebrew Letter Dalet With Dagesh"}').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+
+			{
+				query:       `json_canonical('{"a": 1, "a": 2}', Canonical).`,
+				wantError:   fmt.Errorf("json_canonical/2: invalid JSON: duplicated key 'a'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_canonical('[1e400]', Canonical).`,
+				wantError:   fmt.Errorf("json_canonical/2: invalid number: 1e400, out of the range of the doubles"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_canonical('{"a": ', Canonical).`,
+				wantError:   fmt.Errorf("json_canonical/2: invalid JSON: unexpected EOF"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_canonical('{"a": 1} 2', Canonical).`,
+				wantError:   fmt.Errorf("json_canonical/2: invalid JSON: unexpected data after JSON value"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_canonical(json([a-foo(bar)]), Canonical).`,
+				wantError:   fmt.Errorf("json_canonical/2: invalid functor foo"),
+				wantSuccess: false,
+			},
+			{
+				query:       `json_canonical(json([a-big('1.5')]), Canonical).`,
+				wantError:   fmt.Errorf("json_canonical/2: invalid big integer: 1.5"),
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(json_canonical(_, _), error(instantiation_error, _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("json_canonical"), JSONCanonical)
+						interpreter.Register2(engine.NewAtom("json_prolog"), JSONProlog)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestCanonicalJSONNumber(t *testing.T) {
+	Convey("Given the number test vectors of RFC 8785, appendix B", t, func() {
+		cases := []struct {
+			bits    uint64
+			want    string
+			wantErr bool
+		}{
+			{bits: 0x0000000000000000, want: "0"},
+			{bits: 0x8000000000000000, want: "0"},
+			{bits: 0x0000000000000001, want: "5e-324"},
+			{bits: 0x8000000000000001, want: "-5e-324"},
+			{bits: 0x7fefffffffffffff, want: "1.7976931348623157e+308"},
+			{bits: 0xffefffffffffffff, want: "-1.7976931348623157e+308"},
+			{bits: 0x4340000000000000, want: "9007199254740992"},
+			{bits: 0xc340000000000000, want: "-9007199254740992"},
+			{bits: 0x4430000000000000, want: "295147905179352830000"},
+			{bits: 0x7fffffffffffffff, wantErr: true},
+			{bits: 0x7ff0000000000000, wantErr: true},
+			{bits: 0x44b52d02c7e14af5, want: "9.999999999999997e+22"},
+			{bits: 0x44b52d02c7e14af6, want: "1e+23"},
+			{bits: 0x44b52d02c7e14af7, want: "1.0000000000000001e+23"},
+			{bits: 0x444b1ae4d6e2ef4e, want: "999999999999999700000"},
+			{bits: 0x444b1ae4d6e2ef4f, want: "999999999999999900000"},
+			{bits: 0x444b1ae4d6e2ef50, want: "1e+21"},
+			{bits: 0x3eb0c6f7a0b5ed8c, want: "9.999999999999997e-7"},
+			{bits: 0x3eb0c6f7a0b5ed8d, want: "0.000001"},
+			{bits: 0x41b3de4355555553, want: "333333333.3333332"},
+			{bits: 0x41b3de4355555554, want: "333333333.33333325"},
+			{bits: 0x41b3de4355555555, want: "333333333.3333333"},
+			{bits: 0x41b3de4355555556, want: "333333333.3333334"},
+			{bits: 0x41b3de4355555557, want: "333333333.33333343"},
+			{bits: 0xbecbf647612f3696, want: "-0.0000033333333333333333"},
+			{bits: 0x43143ff3c1cb0959, want: "1424953923781206.2"},
+		}
+		for _, tc := range cases {
+			Convey(fmt.Sprintf("When serializing the number %016x", tc.bits), func() {
+				got, err := canonicalJSONNumber(math.Float64frombits(tc.bits))
+
+				Convey("Then the result should be as expected", func() {
+					if tc.wantErr {
+						So(err, ShouldNotBeNil)
+					} else {
+						So(err, ShouldBeNil)
+						So(string(got), ShouldEqual, tc.want)
+					}
+				})
+			})
+		}
+	})
+}
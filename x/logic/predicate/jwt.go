@@ -0,0 +1,337 @@
+package predicate
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+var (
+	// AtomHMAC are terms with principal functor hmac/1, used to give a HMAC shared secret to jwt_verify/3.
+	AtomHMAC = engine.NewAtom("hmac")
+
+	// AtomRSA are terms with principal functor rsa/1, used to give a RSA public key to jwt_verify/3.
+	AtomRSA = engine.NewAtom("rsa")
+)
+
+// jwtHashes maps the suffixes of the JWT signature algorithms to their hash algorithm, as specified by RFC 7518.
+var jwtHashes = map[string]crypto.Hash{
+	"256": crypto.SHA256,
+	"384": crypto.SHA384,
+	"512": crypto.SHA512,
+}
+
+// jwtHeader is the part of the JOSE header of a JWT used by jwt_verify/3.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+// jwtTimeClaims are the registered claims of a JWT bounding its validity period.
+type jwtTimeClaims struct {
+	Exp json.RawMessage `json:"exp"`
+	Nbf json.RawMessage `json:"nbf"`
+}
+
+// JWTVerify is a predicate which verifies a JSON Web Token (JWT) and unifies its claims.
+//
+// The signature is as follows:
+//
+//	jwt_verify(+Token, +Key, -Claims) is semi-det
+//
+// Where:
+//   - Token is the JWT in its compact serialization, as an Atom of the form Header.Payload.Signature, each part being
+//     base64url encoded.
+//   - Key is the key to verify the signature with, as a Type(Key) term giving the type of the key (see below for
+//     details).
+//   - Claims is the payload of the token, as a JSON term (see json_prolog/2).
+//
+// For the alg parameter, the supported algorithms and the keys they expect are:
+//
+//   - HS256, HS384 and HS512: the HMAC with SHA-2 algorithms, with a hmac(Secret) key, Secret being the shared secret
+//     as a list of bytes.
+//   - RS256, RS384 and RS512: the RSASSA-PKCS1-v1_5 with SHA-2 algorithms, with a rsa(PublicKey) key, PublicKey being
+//     the RSA public key either PEM encoded as an Atom or DER encoded as a list of bytes (see rsa_verify/4).
+//   - PS256, PS384 and PS512: the RSASSA-PSS with SHA-2 algorithms, with a rsa(PublicKey) key as for the RS algorithms.
+//   - ES256: the ECDSA with P-256 and SHA-256 algorithm, with a secp256r1(PublicKey) key, PublicKey being as for
+//     ecdsa_verify/4.
+//   - ES256K: the ECDSA with secp256k1 and SHA-256 algorithm, with a secp256k1(PublicKey) key, PublicKey being as for
+//     ecdsa_verify/4.
+//   - EdDSA: the EdDSA with Ed25519 algorithm, with an ed25519(PublicKey) key, PublicKey being as for eddsa_verify/4.
+//
+// The type of the key is never inferred from the token: a token whose alg parameter does not expect the type of the
+// given key is rejected, so that a token signed with a HMAC algorithm using the bytes of a public key as secret cannot
+// be verified against this public key.
+//
+// The predicate fails if the signature is not valid, if the alg parameter of the token does not match the type of the
+// key, or if the token is not valid at the time of the current block, i.e. if the block time is not before its exp
+// claim or is before its nbf claim, when present. A malformed token, a token using an unsupported algorithm (including
+// the unsecured none algorithm), and a key of an unknown type or not suitable for the algorithm raise an error.
+//
+// Examples:
+//
+//	# Verify a token signed with the EdDSA algorithm, and get its subject.
+//	- jwt_verify('eyJhbGciOiJFZERTQSIsInR5cCI6IkpXVCJ9...', ed25519([127, ...]), json(Claims)), member(sub-Subject, Claims).
+func JWTVerify(vm *engine.VM, token, key, claims engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "jwt_verify/3"
+
+		tokenAtom, ok := env.Resolve(token).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("%s: invalid token type: %T, should be Atom", functor, env.Resolve(token)))
+		}
+		if err := checkInputSize(ctx, len(tokenAtom.String())); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		keyType, keyValue, err := termToJWTKey(key, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		header, payload, signature, err := decodeJWT(tokenAtom.String())
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		expected, err := jwtAlgKeyType(header.Alg)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		if keyType != expected {
+			return engine.Bool(false)
+		}
+
+		signingInput := tokenAtom.String()[:strings.LastIndex(tokenAtom.String(), ".")]
+		valid, err := verifyJWTSignature(ctx, functor, header.Alg, keyValue, []byte(signingInput), signature, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		if !valid {
+			return engine.Bool(false)
+		}
+
+		sdkContext, err := util.UnwrapSDKContext(ctx)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		current, err := isJWTCurrent(payload, sdkContext.BlockTime().Unix())
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		if !current {
+			return engine.Bool(false)
+		}
+
+		terms, err := jsonStringToTerms(string(payload), false)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: invalid token payload: %w", functor, err))
+		}
+
+		return engine.Unify(vm, claims, terms, cont, env)
+	})
+}
+
+// termToJWTKey splits the given Type(Key) term into the type of the key and the key itself.
+func termToJWTKey(key engine.Term, env *engine.Env) (engine.Atom, engine.Term, error) {
+	k, ok := env.Resolve(key).(engine.Compound)
+	if !ok || k.Arity() != 1 {
+		return 0, nil, fmt.Errorf("invalid key: %s, should be a Type(Key) term", env.Resolve(key))
+	}
+	switch k.Functor() {
+	case AtomHMAC, AtomRSA, engine.NewAtom(util.Ed25519.String()), engine.NewAtom(util.Secp256r1.String()),
+		engine.NewAtom(util.Secp256k1.String()):
+		return k.Functor(), k.Arg(0), nil
+	default:
+		return 0, nil, fmt.Errorf("unknown key type: %s", k.Functor())
+	}
+}
+
+// jwtAlgKeyType returns the type of the keys expected by the given JWT signature algorithm.
+func jwtAlgKeyType(alg string) (engine.Atom, error) {
+	family, size := alg[:min(2, len(alg))], alg[min(2, len(alg)):]
+	_, ok := jwtHashes[size]
+	switch {
+	case alg == "EdDSA":
+		return engine.NewAtom(util.Ed25519.String()), nil
+	case alg == "ES256":
+		return engine.NewAtom(util.Secp256r1.String()), nil
+	case alg == "ES256K":
+		return engine.NewAtom(util.Secp256k1.String()), nil
+	case ok && family == "HS":
+		return AtomHMAC, nil
+	case ok && (family == "RS" || family == "PS"):
+		return AtomRSA, nil
+	default:
+		return 0, fmt.Errorf("unsupported token algorithm: %q", alg)
+	}
+}
+
+// decodeJWT splits the given compact JWT into its decoded header, payload and signature.
+func decodeJWT(token string) (*jwtHeader, []byte, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, nil, fmt.Errorf("invalid token: should be made of 3 parts separated by dots, got %d", len(parts))
+	}
+
+	decoded := make([][]byte, 0, len(parts))
+	for i, name := range []string{"header", "payload", "signature"} {
+		part, err := base64.RawURLEncoding.DecodeString(parts[i])
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid token %s: %w", name, err)
+		}
+		decoded = append(decoded, part)
+	}
+
+	var header jwtHeader
+	if err := json.Unmarshal(decoded[0], &header); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid token header: %w", err)
+	}
+
+	return &header, decoded[1], decoded[2], nil
+}
+
+// verifyJWTSignature verifies the given signature of the given signing input of a JWT, using the given algorithm and
+// key, the key being of the type expected by the algorithm (see jwtAlgKeyType).
+//
+//nolint:cyclop
+func verifyJWTSignature(ctx context.Context, functor, alg string, key engine.Term, input, sig []byte, env *engine.Env,
+) (bool, error) {
+	family, size := alg[:min(2, len(alg))], alg[min(2, len(alg)):]
+	h, ok := jwtHashes[size]
+	switch {
+	case alg == "EdDSA":
+		return verifyJWTWithAlg(ctx, functor, util.Ed25519, key, input, sig, env)
+	case alg == "ES256":
+		return verifyJWTWithAlg(ctx, functor, util.Secp256r1, key, jwtDigest(crypto.SHA256, input), jwtECDSASignature(sig), env)
+	case alg == "ES256K":
+		return verifyJWTWithAlg(ctx, functor, util.Secp256k1, key, jwtDigest(crypto.SHA256, input), jwtECDSASignature(sig), env)
+	case ok && family == "HS":
+		secret, err := TermToBytes(ctx, key, AtomEncoding.Apply(AtomOctet), env)
+		if err != nil {
+			return false, fmt.Errorf("failed to decode secret: %w", err)
+		}
+		if err := consumeAlgorithmGas(ctx, functor, "hmac", len(input)); err != nil {
+			return false, err
+		}
+		mac := hmac.New(h.New, secret)
+		mac.Write(input)
+		return util.ConstantTimeEqual(mac.Sum(nil), sig), nil
+	case ok && (family == "RS" || family == "PS"):
+		pubKey, err := termToJWTRSAPublicKey(ctx, key, env)
+		if err != nil {
+			return false, err
+		}
+		if err := consumeAlgorithmGas(ctx, functor, "rsa", len(input)); err != nil {
+			return false, err
+		}
+		if family == "RS" {
+			return rsa.VerifyPKCS1v15(pubKey, h, jwtDigest(h, input), sig) == nil, nil
+		}
+		return rsa.VerifyPSS(pubKey, h, jwtDigest(h, input), sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto}) == nil, nil
+	default:
+		return false, fmt.Errorf("unsupported token algorithm: %q", alg)
+	}
+}
+
+// verifyJWTWithAlg verifies the given signature of the given data with the given key, using the given algorithm of
+// the signature verification predicates.
+func verifyJWTWithAlg(ctx context.Context, functor string, alg util.Alg, key engine.Term, data, sig []byte,
+	env *engine.Env,
+) (bool, error) {
+	pubKey, err := TermToBytes(ctx, key, AtomEncoding.Apply(AtomOctet), env)
+	if err != nil {
+		return false, fmt.Errorf("failed to decode public key: %w", err)
+	}
+	if err := consumeAlgorithmGas(ctx, functor, alg.String(), len(data)); err != nil {
+		return false, err
+	}
+
+	r, err := util.VerifySignature(alg, pubKey, data, sig)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify signature: %w", err)
+	}
+	return r, nil
+}
+
+// termToJWTRSAPublicKey decodes the given RSA public key, either PEM encoded as an Atom or DER encoded as a list of
+// bytes.
+func termToJWTRSAPublicKey(ctx context.Context, key engine.Term, env *engine.Env) (*rsa.PublicKey, error) {
+	if text, ok := env.Resolve(key).(engine.Atom); ok && text != util.AtomEmptyList {
+		block, _ := pem.Decode([]byte(strings.TrimSpace(text.String())))
+		if block == nil {
+			return nil, fmt.Errorf("failed to decode public key: no PEM block found")
+		}
+		return parseRSAPEMBlock(block)
+	}
+
+	der, err := TermToBytes(ctx, key, AtomEncoding.Apply(AtomOctet), env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key: %w", err)
+	}
+	return parseRSAPublicKey(der)
+}
+
+// jwtDigest returns the digest of the given data with the given hash algorithm.
+func jwtDigest(h crypto.Hash, data []byte) []byte {
+	hasher := h.New()
+	hasher.Write(data)
+	return hasher.Sum(nil)
+}
+
+// jwtECDSASignature converts the given JWS ECDSA signature, i.e. the concatenation of R and S, into its ASN.1 form,
+// as expected by the signature verification predicates. A signature of odd length is returned as is, and is rejected
+// by the verification.
+func jwtECDSASignature(sig []byte) []byte {
+	if len(sig) == 0 || len(sig)%2 != 0 {
+		return sig
+	}
+
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{
+		R: new(big.Int).SetBytes(sig[:len(sig)/2]),
+		S: new(big.Int).SetBytes(sig[len(sig)/2:]),
+	})
+	if err != nil {
+		return sig
+	}
+	return der
+}
+
+// isJWTCurrent checks whether the given time, in seconds since the epoch, is within the validity period of a JWT, as
+// given by the exp and nbf claims of its payload, if any.
+func isJWTCurrent(payload []byte, now int64) (bool, error) {
+	var claims jwtTimeClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return false, fmt.Errorf("invalid token payload: %w", err)
+	}
+
+	if claims.Exp != nil {
+		exp, err := strconv.ParseInt(string(claims.Exp), 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid exp claim: %s, should be an integer", claims.Exp)
+		}
+		if now >= exp {
+			return false, nil
+		}
+	}
+	if claims.Nbf != nil {
+		nbf, err := strconv.ParseInt(string(claims.Nbf), 10, 64)
+		if err != nil {
+			return false, fmt.Errorf("invalid nbf claim: %s, should be an integer", claims.Nbf)
+		}
+		if now < nbf {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
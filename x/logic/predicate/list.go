@@ -0,0 +1,809 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"slices"
+	"sort"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+// Contiguous is a predicate which checks whether a list of integers forms a contiguous range, i.e. a range without
+// any gap, and unifies its endpoints.
+//
+// The signature is as follows:
+//
+//	contiguous(+List, -Low, -High) is semidet
+//
+// Where:
+//   - List is the list of Integers, in any order.
+//   - Low is the lowest Integer of List.
+//   - High is the highest Integer of List.
+//
+// The predicate succeeds if and only if List, once sorted, holds each Integer between Low and High exactly once. It
+// fails if List is empty, has a gap (see first_gap/2) or holds duplicated elements.
+//
+// Examples:
+//
+//	# Check that a list of identifiers is contiguous.
+//	- contiguous([3, 1, 2, 4], Low, High).
+func Contiguous(vm *engine.VM, list, low, high engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		ints, err := termToSortedIntegers(list, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("contiguous/3: %w", err))
+		}
+		if len(ints) == 0 {
+			return engine.Bool(false)
+		}
+
+		for i := 1; i < len(ints); i++ {
+			if ints[i] != ints[i-1]+1 {
+				return engine.Bool(false)
+			}
+		}
+
+		return engine.Unify(vm, Tuple(low, high), Tuple(ints[0], ints[len(ints)-1]), cont, env)
+	})
+}
+
+// FirstGap is a predicate which unifies the first gap of a list of integers, i.e. the lowest Integer missing between
+// its lowest and its highest elements.
+//
+// The signature is as follows:
+//
+//	first_gap(+List, -Gap) is semidet
+//
+// Where:
+//   - List is the list of Integers, in any order.
+//   - Gap is the lowest Integer which is greater than the lowest element of List, lower than its highest element, and
+//     which is not an element of List.
+//
+// The predicate fails if List has no gap, which includes the case of an empty List. Duplicated elements are not
+// considered as gaps.
+//
+// Examples:
+//
+//	# Find the first missing identifier.
+//	- first_gap([1, 2, 4, 6], Gap).
+func FirstGap(vm *engine.VM, list, gap engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		ints, err := termToSortedIntegers(list, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("first_gap/2: %w", err))
+		}
+
+		for i := 1; i < len(ints); i++ {
+			if ints[i] > ints[i-1] && ints[i]-1 != ints[i-1] {
+				return engine.Unify(vm, gap, ints[i-1]+1, cont, env)
+			}
+		}
+
+		return engine.Bool(false)
+	})
+}
+
+// RLEEncode is a predicate which computes the run-length encoding of a list, i.e. the list of the runs of consecutive
+// equal elements.
+//
+// The signature is as follows:
+//
+//	rle_encode(+List, -Runs) is det
+//
+// Where:
+//   - List is the list to encode.
+//   - Runs is the list of Count-Element pairs, where Count is the number of consecutive occurrences of Element in List.
+//
+// The elements are compared according to the standard order of terms, i.e. consecutive elements are part of the same
+// run if they are identical (see ==/2).
+//
+// Examples:
+//
+//	# Encode a list with repeated elements.
+//	- rle_encode([a, a, a, b, c, c], Runs).
+func RLEEncode(vm *engine.VM, list, runs engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		pairs := make([]engine.Term, 0)
+		var current engine.Term
+		count := engine.Integer(0)
+
+		iter := engine.ListIterator{List: list, Env: env}
+		for iter.Next() {
+			elem := env.Resolve(iter.Current())
+			if count > 0 && current.Compare(elem, env) == 0 {
+				count++
+				continue
+			}
+			if count > 0 {
+				pairs = append(pairs, AtomPair.Apply(count, current))
+			}
+			current, count = elem, 1
+		}
+		if err := iter.Err(); err != nil {
+			return engine.Error(fmt.Errorf("rle_encode/2: invalid list: %w", err))
+		}
+		if count > 0 {
+			pairs = append(pairs, AtomPair.Apply(count, current))
+		}
+
+		return engine.Unify(vm, runs, engine.List(pairs...), cont, env)
+	})
+}
+
+// RLEDecode is a predicate which decodes a run-length encoded list.
+//
+// The signature is as follows:
+//
+//	rle_decode(+Runs, -List) is det
+//
+// Where:
+//   - Runs is the list of Count-Element pairs, where Count is a positive Integer.
+//   - List is the list made of each Element repeated Count times, in the order of Runs.
+//
+// This predicate is the inverse of rle_encode/2. An error is raised if the length of List exceeds the maximum
+// collection size limit of the module, before any element is generated.
+//
+// Examples:
+//
+//	# Decode a run-length encoded list.
+//	- rle_decode([3-a, 1-b, 2-c], List).
+func RLEDecode(vm *engine.VM, runs, list engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		var pairs []engine.Compound
+		var total uint64
+		iter := engine.ListIterator{List: runs, Env: env}
+		for iter.Next() {
+			pair, ok := env.Resolve(iter.Current()).(engine.Compound)
+			if !ok || pair.Functor() != AtomPair || pair.Arity() != 2 {
+				return engine.Error(fmt.Errorf("rle_decode/2: invalid run type: %T, should be Count-Element", env.Resolve(iter.Current())))
+			}
+			count, ok := env.Resolve(pair.Arg(0)).(engine.Integer)
+			if !ok {
+				return engine.Error(fmt.Errorf("rle_decode/2: invalid run count type: %T, should be Integer", env.Resolve(pair.Arg(0))))
+			}
+			if count <= 0 {
+				return engine.Error(fmt.Errorf("rle_decode/2: invalid run count: %d, should be positive", count))
+			}
+			if uint64(count) > math.MaxInt64-total {
+				return engine.Error(fmt.Errorf("rle_decode/2: too many elements"))
+			}
+			total += uint64(count)
+			pairs = append(pairs, pair)
+		}
+		if err := iter.Err(); err != nil {
+			return engine.Error(fmt.Errorf("rle_decode/2: invalid runs: %w", err))
+		}
+		if err := checkCollectionSize(ctx, total); err != nil {
+			return engine.Error(fmt.Errorf("rle_decode/2: %w", err))
+		}
+
+		elems := make([]engine.Term, 0, total)
+		for _, pair := range pairs {
+			for i := engine.Integer(0); i < env.Resolve(pair.Arg(0)).(engine.Integer); i++ {
+				elems = append(elems, pair.Arg(1))
+			}
+		}
+
+		return engine.Unify(vm, list, engine.List(elems...), cont, env)
+	})
+}
+
+// Interleave is a predicate which interleaves the elements of several lists, taking them in turn from each list.
+//
+// The signature is as follows:
+//
+//	interleave(+Lists, -Combined) is det
+//
+// Where:
+//   - Lists is the list of the lists to interleave, which must all have the same length.
+//   - Combined is the list made of the first element of each list, in the order of Lists, followed by the second
+//     element of each list, and so on.
+//
+// An error is raised if the lists do not all have the same length.
+//
+// Examples:
+//
+//	# Interleave two lists.
+//	- interleave([[a, b, c], [1, 2, 3]], Combined).
+func Interleave(vm *engine.VM, lists, combined engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		parts := make([][]engine.Term, 0)
+		iter := engine.ListIterator{List: lists, Env: env}
+		for iter.Next() {
+			elems, err := termToSlice(iter.Current(), env)
+			if err != nil {
+				return engine.Error(fmt.Errorf("interleave/2: %w", err))
+			}
+			if len(parts) > 0 && len(elems) != len(parts[0]) {
+				return engine.Error(fmt.Errorf("interleave/2: lists should have the same length, given %d and %d",
+					len(parts[0]), len(elems)))
+			}
+			parts = append(parts, elems)
+		}
+		if err := iter.Err(); err != nil {
+			return engine.Error(fmt.Errorf("interleave/2: invalid lists: %w", err))
+		}
+
+		elems := make([]engine.Term, 0)
+		if len(parts) > 0 {
+			for i := range parts[0] {
+				for _, part := range parts {
+					elems = append(elems, part[i])
+				}
+			}
+		}
+
+		return engine.Unify(vm, combined, engine.List(elems...), cont, env)
+	})
+}
+
+// Deinterleave is a predicate which splits a list into several lists, distributing its elements in turn to each list.
+//
+// The signature is as follows:
+//
+//	deinterleave(+N, +Combined, -Lists) is det
+//
+// Where:
+//   - N is the number of lists to split Combined into, as a positive Integer.
+//   - Combined is the list to split, whose length must be a multiple of N.
+//   - Lists is the list of the N resulting lists, the first one holding the first element of Combined, the second one
+//     its second element, and so on, the N+1th element of Combined going back to the first list.
+//
+// This predicate is the inverse of interleave/2. An error is raised if the length of Combined is not a multiple of N,
+// or if N exceeds the maximum collection size limit of the module, as N lists are generated even from an empty list.
+//
+// Examples:
+//
+//	# Split a list into two lists.
+//	- deinterleave(2, [a, 1, b, 2, c, 3], Lists).
+func Deinterleave(vm *engine.VM, n, combined, lists engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		count, ok := env.Resolve(n).(engine.Integer)
+		if !ok {
+			return engine.Error(fmt.Errorf("deinterleave/3: invalid count type: %T, should be Integer", env.Resolve(n)))
+		}
+		if count <= 0 {
+			return engine.Error(fmt.Errorf("deinterleave/3: invalid count: %d, should be positive", count))
+		}
+		elems, err := termToSlice(combined, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("deinterleave/3: %w", err))
+		}
+		if engine.Integer(len(elems))%count != 0 {
+			return engine.Error(fmt.Errorf("deinterleave/3: invalid list length: %d, should be a multiple of %d", len(elems), count))
+		}
+		if err := checkCollectionSize(ctx, uint64(count)); err != nil {
+			return engine.Error(fmt.Errorf("deinterleave/3: %w", err))
+		}
+
+		size := len(elems) / int(count)
+		parts := make([][]engine.Term, count)
+		for i := range parts {
+			parts[i] = make([]engine.Term, 0, size)
+		}
+		for i, elem := range elems {
+			parts[engine.Integer(i)%count] = append(parts[engine.Integer(i)%count], elem)
+		}
+
+		result := make([]engine.Term, 0, count)
+		for _, part := range parts {
+			result = append(result, engine.List(part...))
+		}
+
+		return engine.Unify(vm, lists, engine.List(result...), cont, env)
+	})
+}
+
+// Powerset is a predicate which computes the subsets of an ordered set.
+//
+// The signature is as follows:
+//
+//	powerset(+Set, -Subsets, +Options) is det
+//
+// Where:
+//   - Set is the ordered set, i.e. a list sorted in strictly ascending standard order of terms, as produced by sort/2.
+//   - Subsets is the list of the subsets of Set, each one being an ordered set. The subsets are ordered by ascending
+//     cardinality, and the subsets of the same cardinality in the lexicographic order of the positions of their
+//     elements in Set.
+//   - Options are additional configurations for the generation. Supported options include: max_size(+K) which limits
+//     the generated subsets to the ones holding at most K elements (unbounded by default).
+//
+// As the number of subsets grows exponentially with the size of Set, an error is raised if it exceeds the maximum
+// collection size limit of the module, before any subset is generated.
+//
+// Examples:
+//
+//	# Compute the power set of a set.
+//	- powerset([a, b, c], Subsets, []).
+//
+//	# Compute the pairs and singletons of a set.
+//	- powerset([a, b, c], Subsets, [max_size(2)]).
+func Powerset(vm *engine.VM, set, subsets, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		elems, err := termToOrdSet(set, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("powerset/3: %w", err))
+		}
+		maxSize := len(elems)
+		maxSizeTerm, err := util.GetOption(engine.NewAtom("max_size"), options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("powerset/3: %w", err))
+		}
+		if maxSizeTerm != nil {
+			k, ok := env.Resolve(maxSizeTerm).(engine.Integer)
+			if !ok || k < 0 {
+				return engine.Error(fmt.Errorf("powerset/3: invalid max_size option: %v, should be a non-negative Integer",
+					env.Resolve(maxSizeTerm)))
+			}
+			maxSize = min(maxSize, int(k))
+		}
+
+		count := new(big.Int)
+		for k := 0; k <= maxSize; k++ {
+			count.Add(count, new(big.Int).Binomial(int64(len(elems)), int64(k)))
+		}
+		if !count.IsUint64() {
+			return engine.Error(fmt.Errorf("powerset/3: too many subsets: %s", count))
+		}
+		if err := checkCollectionSize(ctx, count.Uint64()); err != nil {
+			return engine.Error(fmt.Errorf("powerset/3: %w", err))
+		}
+
+		result := make([]engine.Term, 0, count.Uint64())
+		for k := 0; k <= maxSize; k++ {
+			forEachCombination(len(elems), k, func(indexes []int) {
+				subset := make([]engine.Term, 0, k)
+				for _, i := range indexes {
+					subset = append(subset, elems[i])
+				}
+				result = append(result, engine.List(subset...))
+			})
+		}
+
+		return engine.Unify(vm, subsets, engine.List(result...), cont, env)
+	})
+}
+
+// Combination is a predicate which enumerates the combinations of K elements of a list on backtracking.
+//
+// The signature is as follows:
+//
+//	combination(+K, +List, -Combination) is nondet
+//
+// Where:
+//   - K is the number of elements of each combination, as a non-negative Integer.
+//   - List is the list to pick the elements from.
+//   - Combination is a list of K elements of List, in the order they appear in List.
+//
+// The combinations are enumerated in the lexicographic order of the positions of their elements in List, and are
+// generated lazily, one at a time, so that a rule can cut the enumeration early. The elements of List are considered
+// distinct by their position, so duplicated elements produce duplicated combinations. The predicate fails if K is
+// greater than the length of List.
+//
+// Examples:
+//
+//	# Enumerate the pairs of a list.
+//	- combination(2, [a, b, c], Combination).
+func Combination(vm *engine.VM, k, list, combination engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		size, elems, err := termToPickArgs(k, list, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("combination/3: %w", err))
+		}
+
+		return enumeratePicks(elems, size, false, func(picked []engine.Term) *engine.Promise {
+			return engine.Unify(vm, combination, engine.List(picked...), cont, env)
+		})
+	})
+}
+
+// PermutationK is a predicate which enumerates the permutations of K elements of a list on backtracking.
+//
+// The signature is as follows:
+//
+//	permutation_k(+K, +List, -Permutation) is nondet
+//
+// Where:
+//   - K is the number of elements of each permutation, as a non-negative Integer.
+//   - List is the list to pick the elements from.
+//   - Permutation is a list of K distinct elements of List, in any order.
+//
+// The permutations are enumerated in the lexicographic order of the positions of their elements in List, and are
+// generated lazily, one at a time, so that a rule can cut the enumeration early. The elements of List are considered
+// distinct by their position, so duplicated elements produce duplicated permutations. The predicate fails if K is
+// greater than the length of List.
+//
+// Examples:
+//
+//	# Enumerate the ordered pairs of a list.
+//	- permutation_k(2, [a, b, c], Permutation).
+func PermutationK(vm *engine.VM, k, list, permutation engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		size, elems, err := termToPickArgs(k, list, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("permutation_k/3: %w", err))
+		}
+
+		return enumeratePicks(elems, size, true, func(picked []engine.Term) *engine.Promise {
+			return engine.Unify(vm, permutation, engine.List(picked...), cont, env)
+		})
+	})
+}
+
+// termToPickArgs converts the given number of elements to pick and list to pick them from.
+func termToPickArgs(k, list engine.Term, env *engine.Env) (int, []engine.Term, error) {
+	size, ok := env.Resolve(k).(engine.Integer)
+	if !ok {
+		return 0, nil, fmt.Errorf("invalid count type: %T, should be Integer", env.Resolve(k))
+	}
+	if size < 0 {
+		return 0, nil, fmt.Errorf("invalid count: %d, should be non-negative", size)
+	}
+	elems, err := termToSlice(list, env)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return int(size), elems, nil
+}
+
+// enumeratePicks lazily enumerates, in lexicographic order of positions, the ways to pick k elements of the given
+// ones, calling the given continuation with each of them on backtracking. If ordered is false, the elements are picked
+// in the order they appear (combinations), otherwise in any order (permutations).
+func enumeratePicks(elems []engine.Term, k int, ordered bool, cont func([]engine.Term) *engine.Promise) *engine.Promise {
+	if k > len(elems) {
+		return engine.Bool(false)
+	}
+
+	var pick func(indexes []int) *engine.Promise
+	pick = func(indexes []int) *engine.Promise {
+		if len(indexes) == k {
+			return cont(util.Map(indexes, func(i int) engine.Term { return elems[i] }))
+		}
+
+		start := 0
+		if !ordered && len(indexes) > 0 {
+			start = indexes[len(indexes)-1] + 1
+		}
+		ks := make([]func(context.Context) *engine.Promise, 0, len(elems)-start)
+		for i := start; i < len(elems); i++ {
+			if ordered && slices.Contains(indexes, i) {
+				continue
+			}
+			i := i
+			ks = append(ks, func(context.Context) *engine.Promise {
+				return pick(append(indexes[:len(indexes):len(indexes)], i))
+			})
+		}
+
+		return engine.Delay(ks...)
+	}
+
+	return pick(make([]int, 0, k))
+}
+
+// forEachCombination calls the given function with each combination of k indexes among n, in lexicographic order.
+// The given slice of indexes is reused between calls, and must not be retained.
+func forEachCombination(n, k int, fn func(indexes []int)) {
+	if k > n {
+		return
+	}
+	indexes := make([]int, k)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	for {
+		fn(indexes)
+
+		i := k - 1
+		for i >= 0 && indexes[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		indexes[i]++
+		for j := i + 1; j < k; j++ {
+			indexes[j] = indexes[j-1] + 1
+		}
+	}
+}
+
+// BinPack is a predicate which assigns items to bins of a given capacity, opening as few bins as a greedy algorithm can.
+//
+// The signature is as follows:
+//
+//	bin_pack(+Items, +Capacity, -Bins, +Options) is det
+//
+// Where:
+//   - Items is the list of the items to pack, as Name-Size pairs where Size is a non-negative Integer.
+//   - Capacity is the capacity of each bin, as a positive Integer.
+//   - Bins is the list of the bins, each bin being the list of the Name-Size pairs of the items it holds, whose sizes
+//     sum up to at most Capacity.
+//   - Options are additional configurations for the packing. Supported options include: algorithm(+Algorithm) which
+//     specifies the packing algorithm, among first_fit_decreasing (default) which considers the items by decreasing
+//     size, and first_fit which considers the items in the order of Items. In both cases, each item is put in the first
+//     bin having enough room left, a new bin being opened if none has.
+//
+// The packing is deterministic: the items of the same size are considered in the order of Items, and the bins are
+// given in the order they are opened. An error is raised if an item is larger than Capacity.
+//
+// Examples:
+//
+//	# Pack items into bins of capacity 10.
+//	- bin_pack([a-5, b-7, c-3, d-2, e-4], 10, Bins, []).
+func BinPack(vm *engine.VM, items, capacity, bins, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		limit, ok := env.Resolve(capacity).(engine.Integer)
+		if !ok {
+			return engine.Error(fmt.Errorf("bin_pack/4: invalid capacity type: %T, should be Integer", env.Resolve(capacity)))
+		}
+		if limit <= 0 {
+			return engine.Error(fmt.Errorf("bin_pack/4: invalid capacity: %d, should be positive", limit))
+		}
+		algorithm, err := util.GetOptionWithDefault(engine.NewAtom("algorithm"), options, engine.NewAtom("first_fit_decreasing"), env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("bin_pack/4: %w", err))
+		}
+
+		elems, err := termToBinPackItems(items, limit, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("bin_pack/4: %w", err))
+		}
+
+		switch env.Resolve(algorithm) {
+		case engine.NewAtom("first_fit_decreasing"):
+			sort.SliceStable(elems, func(i, j int) bool { return elems[i].size > elems[j].size })
+		case engine.NewAtom("first_fit"):
+		default:
+			return engine.Error(fmt.Errorf("bin_pack/4: invalid algorithm option: %v, valid values are 'first_fit_decreasing' or 'first_fit'",
+				env.Resolve(algorithm)))
+		}
+
+		packed := make([][]engine.Term, 0)
+		room := make([]engine.Integer, 0)
+		for _, e := range elems {
+			i := slices.IndexFunc(room, func(r engine.Integer) bool { return r >= e.size })
+			if i < 0 {
+				i = len(room)
+				packed, room = append(packed, nil), append(room, limit)
+			}
+			packed[i], room[i] = append(packed[i], e.pair), room[i]-e.size
+		}
+
+		return engine.Unify(vm, bins, engine.List(util.Map(packed, func(bin []engine.Term) engine.Term {
+			return engine.List(bin...)
+		})...), cont, env)
+	})
+}
+
+// binPackItem is an item to pack by bin_pack/4.
+type binPackItem struct {
+	pair engine.Term
+	size engine.Integer
+}
+
+// termToBinPackItems converts the given list of Name-Size pairs into the items to pack into bins of the given capacity.
+func termToBinPackItems(items engine.Term, capacity engine.Integer, env *engine.Env) ([]binPackItem, error) {
+	elems := make([]binPackItem, 0)
+	iter := engine.ListIterator{List: items, Env: env}
+	for iter.Next() {
+		pair, ok := env.Resolve(iter.Current()).(engine.Compound)
+		if !ok || pair.Functor() != AtomPair || pair.Arity() != 2 {
+			return nil, fmt.Errorf("invalid item type: %T, should be Name-Size", env.Resolve(iter.Current()))
+		}
+		size, ok := env.Resolve(pair.Arg(1)).(engine.Integer)
+		if !ok || size < 0 {
+			return nil, fmt.Errorf("invalid item size: %v, should be a non-negative Integer", env.Resolve(pair.Arg(1)))
+		}
+		if size > capacity {
+			return nil, fmt.Errorf("item %v of size %d exceeds the capacity of %d", env.Resolve(pair.Arg(0)), size, capacity)
+		}
+		elems = append(elems, binPackItem{pair: pair, size: size})
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("invalid items: %w", err)
+	}
+
+	return elems, nil
+}
+
+// RoundRobin is a predicate which computes a fair round-robin schedule, pairing each participant with each of the
+// others in turn.
+//
+// The signature is as follows:
+//
+//	round_robin(+Participants, +Rounds, -Schedule) is det
+//
+// Where:
+//   - Participants is the list of the participants.
+//   - Rounds is the number of rounds to schedule, as a non-negative Integer.
+//   - Schedule is the list of the rounds, each round being the list of its pairings, as A-B pairs, where each
+//     participant appears exactly once. With an odd number of participants, the one sitting out of a round is given
+//     as bye(A) at the end of the round.
+//
+// The schedule is computed with the circle method: the first participant stays in place while the others rotate, so
+// that within N - 1 rounds, N being the number of participants rounded up to an even number, each participant meets
+// each of the others exactly once and sits out at most once, the schedule repeating itself beyond. The side of the
+// first participant alternates from one round to the next. The schedule is deterministic as it only depends on the
+// order of Participants.
+//
+// An error is raised if the number of the pairings of the schedule, counting an empty round as one, exceeds the
+// maximum collection size limit of the module, before any round is generated.
+//
+// Examples:
+//
+//	# Schedule the rounds of a tournament between 4 participants.
+//	- round_robin([alice, bob, carol, dave], 3, Schedule).
+func RoundRobin(vm *engine.VM, participants, rounds, schedule engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		count, ok := env.Resolve(rounds).(engine.Integer)
+		if !ok {
+			return engine.Error(fmt.Errorf("round_robin/3: invalid rounds type: %T, should be Integer", env.Resolve(rounds)))
+		}
+		if count < 0 {
+			return engine.Error(fmt.Errorf("round_robin/3: invalid rounds: %d, should be non-negative", count))
+		}
+		elems, err := termToSlice(participants, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("round_robin/3: %w", err))
+		}
+		// each round holds at least one term, even without any participant.
+		hi, size := bits.Mul64(uint64(count), uint64(max(1, (len(elems)+1)/2)))
+		if hi != 0 || size > math.MaxInt64 {
+			return engine.Error(fmt.Errorf("round_robin/3: too many rounds: %d", count))
+		}
+		if err := checkCollectionSize(ctx, size); err != nil {
+			return engine.Error(fmt.Errorf("round_robin/3: %w", err))
+		}
+
+		return engine.Unify(vm, schedule, engine.List(roundRobinSchedule(elems, int(count))...), cont, env)
+	})
+}
+
+// roundRobinSchedule returns the given number of rounds of the round-robin schedule of the given participants,
+// computed with the circle method.
+func roundRobinSchedule(participants []engine.Term, rounds int) []engine.Term {
+	seats := make([]int, len(participants), len(participants)+1)
+	for i := range seats {
+		seats[i] = i
+	}
+	if len(seats)%2 != 0 {
+		seats = append(seats, -1)
+	}
+
+	schedule := make([]engine.Term, 0, rounds)
+	for r := 0; r < rounds; r++ {
+		pairings, byes := make([]engine.Term, 0, len(seats)/2), make([]engine.Term, 0, 1)
+		for i := 0; i < len(seats)/2; i++ {
+			a, b := seats[i], seats[len(seats)-1-i]
+			if i == 0 && r%2 != 0 {
+				a, b = b, a
+			}
+			switch {
+			case a < 0:
+				byes = append(byes, engine.NewAtom("bye").Apply(participants[b]))
+			case b < 0:
+				byes = append(byes, engine.NewAtom("bye").Apply(participants[a]))
+			default:
+				pairings = append(pairings, AtomPair.Apply(participants[a], participants[b]))
+			}
+		}
+		schedule = append(schedule, engine.List(append(pairings, byes...)...))
+
+		if len(seats) > 2 {
+			last := seats[len(seats)-1]
+			copy(seats[2:], seats[1:len(seats)-1])
+			seats[1] = last
+		}
+	}
+
+	return schedule
+}
+
+// termToSlice converts the given list into a slice of its elements.
+func termToSlice(list engine.Term, env *engine.Env) ([]engine.Term, error) {
+	elems := make([]engine.Term, 0)
+	iter := engine.ListIterator{List: list, Env: env}
+	for iter.Next() {
+		elems = append(elems, iter.Current())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("invalid list: %w", err)
+	}
+
+	return elems, nil
+}
+
+// termToSortedIntegers converts the given list of integers into a slice of integers, sorted in ascending order.
+func termToSortedIntegers(list engine.Term, env *engine.Env) ([]engine.Integer, error) {
+	ints := make([]engine.Integer, 0)
+	iter := engine.ListIterator{List: list, Env: env}
+	for iter.Next() {
+		i, ok := env.Resolve(iter.Current()).(engine.Integer)
+		if !ok {
+			return nil, fmt.Errorf("invalid element type: %T, should be Integer", env.Resolve(iter.Current()))
+		}
+		ints = append(ints, i)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("invalid list: %w", err)
+	}
+
+	sort.Slice(ints, func(i, j int) bool { return ints[i] < ints[j] })
+	return ints, nil
+}
+
+// OrdSymdiff is a predicate which computes the symmetric difference of two ordered sets, i.e. the ordered set of the
+// elements which belong to exactly one of them.
+//
+// The signature is as follows:
+//
+//	ord_symdiff(+Set1, +Set2, -Difference) is det
+//
+// Where:
+//   - Set1 and Set2 are ordered sets, i.e. lists sorted in strictly ascending standard order of terms, as produced by
+//     sort/2.
+//   - Difference is the ordered set of the elements of Set1 which are not in Set2, and of the elements of Set2 which
+//     are not in Set1.
+//
+// The sets are merged in linear time. An error is raised if Set1 or Set2 is not an ordered set.
+//
+// Examples:
+//
+//	# Compute the symmetric difference of two sets.
+//	- ord_symdiff([a, b, c], [b, c, d], Difference).
+func OrdSymdiff(vm *engine.VM, set1, set2, diff engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		elems1, err := termToOrdSet(set1, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("ord_symdiff/3: %w", err))
+		}
+		elems2, err := termToOrdSet(set2, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("ord_symdiff/3: %w", err))
+		}
+
+		elems := make([]engine.Term, 0, len(elems1)+len(elems2))
+		i, j := 0, 0
+		for i < len(elems1) && j < len(elems2) {
+			switch c := elems1[i].Compare(elems2[j], env); {
+			case c < 0:
+				elems = append(elems, elems1[i])
+				i++
+			case c > 0:
+				elems = append(elems, elems2[j])
+				j++
+			default:
+				i++
+				j++
+			}
+		}
+		elems = append(elems, elems1[i:]...)
+		elems = append(elems, elems2[j:]...)
+
+		return engine.Unify(vm, diff, engine.List(elems...), cont, env)
+	})
+}
+
+// termToOrdSet converts the given ordered set into a slice of its elements, checking they are in strictly ascending
+// standard order.
+func termToOrdSet(set engine.Term, env *engine.Env) ([]engine.Term, error) {
+	elems, err := termToSlice(set, env)
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(elems); i++ {
+		if elems[i-1].Compare(elems[i], env) >= 0 {
+			return nil, fmt.Errorf("invalid ordered set: elements at position %d and %d are not in strictly ascending order", i, i+1)
+		}
+	}
+
+	return elems, nil
+}
@@ -0,0 +1,1284 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+	"github.com/samber/lo"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestContiguous(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `contiguous([3, 1, 2, 4], Low, High).`,
+				wantResult:  []types.TermResults{{"Low": "1", "High": "4"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `contiguous([-1], Low, High).`,
+				wantResult:  []types.TermResults{{"Low": "-1", "High": "-1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `contiguous([2, 3, 1], 1, 3).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `contiguous([2, 3, 1], 0, 3).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `contiguous([1, 2, 4], Low, High).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `contiguous([1, 2, 2, 3], Low, High).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `contiguous([], Low, High).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `contiguous([1, two, 3], Low, High).`,
+				wantError:   fmt.Errorf("contiguous/3: invalid element type: engine.Atom, should be Integer"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("contiguous"), Contiguous)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestFirstGap(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `first_gap([6, 1, 4, 2], Gap).`,
+				wantResult:  []types.TermResults{{"Gap": "3"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `first_gap([1, 1, 3], Gap).`,
+				wantResult:  []types.TermResults{{"Gap": "2"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `first_gap([1, 2, 3], Gap).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `first_gap([1, 2, 2, 3], Gap).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `first_gap([], Gap).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `first_gap([9223372036854775807, 9223372036854775807], Gap).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `first_gap(foo, Gap).`,
+				wantError:   fmt.Errorf("first_gap/2: invalid list: error(type_error(list,foo),first_gap/2)"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("first_gap"), FirstGap)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestRLE(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program           string
+			query             string
+			maxCollectionSize *sdkmath.Uint
+			wantResult        []types.TermResults
+			wantError         error
+			wantSuccess       bool
+		}{
+			{
+				query:       `rle_encode([a, a, b, c, c, c, a], Runs).`,
+				wantResult:  []types.TermResults{{"Runs": "[2-a,1-b,3-c,1-a]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `rle_encode([f(1), f(1), 1, 1.0, 'A', a], Runs).`,
+				wantResult:  []types.TermResults{{"Runs": "[2-f(1),1-1,1-1.0,1-'A',1-a]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `rle_encode([], Runs).`,
+				wantResult:  []types.TermResults{{"Runs": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `rle_encode([a, a, b, c, c, c, a], Runs), rle_decode(Runs, List).`,
+				wantResult:  []types.TermResults{{"Runs": "[2-a,1-b,3-c,1-a]", "List": "[a,a,b,c,c,c,a]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `rle_decode([3-x, 1-y], [x, x, x, y]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:             `rle_decode([3-x, 2-y], List).`,
+				maxCollectionSize: lo.ToPtr(sdkmath.NewUint(5)),
+				wantResult:        []types.TermResults{{"List": "[x,x,x,y,y]"}},
+				wantSuccess:       true,
+			},
+			{
+				query:             `rle_decode([3-x, 3-y], List).`,
+				maxCollectionSize: lo.ToPtr(sdkmath.NewUint(5)),
+				wantError:         fmt.Errorf("rle_decode/2: collection of 6 elements exceeds the maximum size of 5 elements"),
+				wantSuccess:       false,
+			},
+			{
+				query:             `rle_decode([100000000000000-a], List).`,
+				maxCollectionSize: lo.ToPtr(sdkmath.NewUint(10000)),
+				wantError:         fmt.Errorf("rle_decode/2: collection of 100000000000000 elements exceeds the maximum size of 10000 elements"),
+				wantSuccess:       false,
+			},
+			{
+				query:       `rle_decode([9223372036854775807-a, 1-b], List).`,
+				wantError:   fmt.Errorf("rle_decode/2: too many elements"),
+				wantSuccess: false,
+			},
+			{
+				query:       `rle_decode([0-x], List).`,
+				wantError:   fmt.Errorf("rle_decode/2: invalid run count: 0, should be positive"),
+				wantSuccess: false,
+			},
+			{
+				query:       `rle_decode([x-3], List).`,
+				wantError:   fmt.Errorf("rle_decode/2: invalid run count type: engine.Atom, should be Integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `rle_decode([x], List).`,
+				wantError:   fmt.Errorf("rle_decode/2: invalid run type: engine.Atom, should be Count-Element"),
+				wantSuccess: false,
+			},
+			{
+				query:       `rle_encode(foo, Runs).`,
+				wantError:   fmt.Errorf("rle_encode/2: invalid list: error(type_error(list,foo),rle_encode/2)"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+					if tc.maxCollectionSize != nil {
+						ctx = ctx.WithValue(types.MaxCollectionSizeContextKey, *tc.maxCollectionSize)
+					}
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("rle_encode"), RLEEncode)
+						interpreter.Register2(engine.NewAtom("rle_decode"), RLEDecode)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestInterleave(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program           string
+			query             string
+			maxCollectionSize *sdkmath.Uint
+			wantResult        []types.TermResults
+			wantError         error
+			wantSuccess       bool
+		}{
+			{
+				query:       `interleave([[a, b, c], [1, 2, 3]], Combined).`,
+				wantResult:  []types.TermResults{{"Combined": "[a,1,b,2,c,3]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `interleave([[a, b], [1, 2], [x, y]], Combined).`,
+				wantResult:  []types.TermResults{{"Combined": "[a,1,x,b,2,y]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `interleave([], Combined).`,
+				wantResult:  []types.TermResults{{"Combined": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `interleave([[a, b, c], [1, 2, 3]], Combined), deinterleave(2, Combined, Lists).`,
+				wantResult:  []types.TermResults{{"Combined": "[a,1,b,2,c,3]", "Lists": "[[a,b,c],[1,2,3]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deinterleave(3, [a, 1, x, b, 2, y], Lists), interleave(Lists, Combined).`,
+				wantResult:  []types.TermResults{{"Lists": "[[a,b],[1,2],[x,y]]", "Combined": "[a,1,x,b,2,y]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deinterleave(2, [], Lists).`,
+				wantResult:  []types.TermResults{{"Lists": "[[],[]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:             `deinterleave(100000000000000, [], Lists).`,
+				maxCollectionSize: lo.ToPtr(sdkmath.NewUint(10000)),
+				wantError:         fmt.Errorf("deinterleave/3: collection of 100000000000000 elements exceeds the maximum size of 10000 elements"),
+				wantSuccess:       false,
+			},
+			{
+				query:       `interleave([[a, b, c], [1, 2]], Combined).`,
+				wantError:   fmt.Errorf("interleave/2: lists should have the same length, given 3 and 2"),
+				wantSuccess: false,
+			},
+			{
+				query:       `deinterleave(2, [a, 1, b], Lists).`,
+				wantError:   fmt.Errorf("deinterleave/3: invalid list length: 3, should be a multiple of 2"),
+				wantSuccess: false,
+			},
+			{
+				query:       `deinterleave(0, [a, 1, b], Lists).`,
+				wantError:   fmt.Errorf("deinterleave/3: invalid count: 0, should be positive"),
+				wantSuccess: false,
+			},
+			{
+				query:       `deinterleave(N, [a, 1, b], Lists).`,
+				wantError:   fmt.Errorf("deinterleave/3: invalid count type: engine.Variable, should be Integer"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+					if tc.maxCollectionSize != nil {
+						ctx = ctx.WithValue(types.MaxCollectionSizeContextKey, *tc.maxCollectionSize)
+					}
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("interleave"), Interleave)
+						interpreter.Register3(engine.NewAtom("deinterleave"), Deinterleave)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestOrdSymdiff(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `ord_symdiff([a, b, c], [b, c, d], Diff).`,
+				wantResult:  []types.TermResults{{"Diff": "[a,d]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `ord_symdiff([1, 3, 5], [2, 4], Diff).`,
+				wantResult:  []types.TermResults{{"Diff": "[1,2,3,4,5]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `ord_symdiff([a, b], [a, b], Diff).`,
+				wantResult:  []types.TermResults{{"Diff": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `ord_symdiff([], [x, y], Diff).`,
+				wantResult:  []types.TermResults{{"Diff": "[x,y]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `ord_symdiff([1, a, f(x)], [a, b], Diff).`,
+				wantResult:  []types.TermResults{{"Diff": "[1,b,f(x)]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `ord_symdiff([a, b], [b, c], [a, c]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `ord_symdiff([a, b], [b, c], [a]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `ord_symdiff([b, a], [c], Diff).`,
+				wantError:   fmt.Errorf("ord_symdiff/3: invalid ordered set: elements at position 1 and 2 are not in strictly ascending order"),
+				wantSuccess: false,
+			},
+			{
+				query:       `ord_symdiff([a], [b, c, c], Diff).`,
+				wantError:   fmt.Errorf("ord_symdiff/3: invalid ordered set: elements at position 2 and 3 are not in strictly ascending order"),
+				wantSuccess: false,
+			},
+			{
+				query:       `ord_symdiff(foo, [a], Diff).`,
+				wantError:   fmt.Errorf("ord_symdiff/3: invalid list: error(type_error(list,foo),ord_symdiff/3)"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("ord_symdiff"), OrdSymdiff)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestPowerset(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program           string
+			query             string
+			maxCollectionSize *sdkmath.Uint
+			wantResult        []types.TermResults
+			wantError         error
+			wantSuccess       bool
+		}{
+			{
+				query:       `powerset([a, b, c], Subsets, []).`,
+				wantResult:  []types.TermResults{{"Subsets": "[[],[a],[b],[c],[a,b],[a,c],[b,c],[a,b,c]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `powerset([], Subsets, []).`,
+				wantResult:  []types.TermResults{{"Subsets": "[[]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `powerset([a, b, c, d], Subsets, [max_size(2)]).`,
+				wantResult:  []types.TermResults{{"Subsets": "[[],[a],[b],[c],[d],[a,b],[a,c],[a,d],[b,c],[b,d],[c,d]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `powerset([a, b], Subsets, [max_size(5)]).`,
+				wantResult:  []types.TermResults{{"Subsets": "[[],[a],[b],[a,b]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `powerset([a, b], Subsets, [max_size(0)]).`,
+				wantResult:  []types.TermResults{{"Subsets": "[[]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:             `powerset([a, b, c], Subsets, []).`,
+				maxCollectionSize: lo.ToPtr(sdkmath.NewUint(8)),
+				wantResult:        []types.TermResults{{"Subsets": "[[],[a],[b],[c],[a,b],[a,c],[b,c],[a,b,c]]"}},
+				wantSuccess:       true,
+			},
+			{
+				query:             `powerset([a, b, c, d], Subsets, []).`,
+				maxCollectionSize: lo.ToPtr(sdkmath.NewUint(8)),
+				wantError:         fmt.Errorf("powerset/3: collection of 16 elements exceeds the maximum size of 8 elements"),
+				wantSuccess:       false,
+			},
+			{
+				query:             `powerset([a, b, c, d], Subsets, [max_size(1)]).`,
+				maxCollectionSize: lo.ToPtr(sdkmath.NewUint(8)),
+				wantResult:        []types.TermResults{{"Subsets": "[[],[a],[b],[c],[d]]"}},
+				wantSuccess:       true,
+			},
+			{
+				query:       `powerset([b, a], Subsets, []).`,
+				wantError:   fmt.Errorf("powerset/3: invalid ordered set: elements at position 1 and 2 are not in strictly ascending order"),
+				wantSuccess: false,
+			},
+			{
+				query:       `powerset([a], Subsets, [max_size(-1)]).`,
+				wantError:   fmt.Errorf("powerset/3: invalid max_size option: -1, should be a non-negative Integer"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+					if tc.maxCollectionSize != nil {
+						ctx = ctx.WithValue(types.MaxCollectionSizeContextKey, *tc.maxCollectionSize)
+					}
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("powerset"), Powerset)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestCombination(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query: `combination(2, [a, b, c, d], C).`,
+				wantResult: []types.TermResults{
+					{"C": "[a,b]"}, {"C": "[a,c]"}, {"C": "[a,d]"}, {"C": "[b,c]"}, {"C": "[b,d]"}, {"C": "[c,d]"},
+				},
+				wantSuccess: true,
+			},
+			{
+				program:     `first_ending_with_d(C) :- combination(3, [a, b, c, d, e], C), C = [_, _, d], !.`,
+				query:       `first_ending_with_d(C).`,
+				wantResult:  []types.TermResults{{"C": "[a,b,d]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `combination(0, [a, b], C).`,
+				wantResult:  []types.TermResults{{"C": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `combination(3, [a, b, c], C).`,
+				wantResult:  []types.TermResults{{"C": "[a,b,c]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `combination(3, [a, b], C).`,
+				wantSuccess: false,
+			},
+			{
+				program:     `count(K, L, N) :- findall(C, combination(K, L, C), Cs), length(Cs, N).`,
+				query:       `count(3, [1, 2, 3, 4, 5, 6], N).`,
+				wantResult:  []types.TermResults{{"N": "20"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `combination(-1, [a], C).`,
+				wantError:   fmt.Errorf("combination/3: invalid count: -1, should be non-negative"),
+				wantSuccess: false,
+			},
+			{
+				query:       `combination(two, [a], C).`,
+				wantError:   fmt.Errorf("combination/3: invalid count type: engine.Atom, should be Integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `combination(1, foo, C).`,
+				wantError:   fmt.Errorf("combination/3: invalid list: error(type_error(list,foo),combination/3)"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("combination"), Combination)
+						interpreter.Register3(engine.NewAtom("findall"), engine.FindAll)
+						interpreter.Register2(engine.NewAtom("length"), engine.Length)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestPermutationK(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query: `permutation_k(2, [a, b, c], P).`,
+				wantResult: []types.TermResults{
+					{"P": "[a,b]"}, {"P": "[a,c]"}, {"P": "[b,a]"}, {"P": "[b,c]"}, {"P": "[c,a]"}, {"P": "[c,b]"},
+				},
+				wantSuccess: true,
+			},
+			{
+				query: `permutation_k(3, [1, 2, 3], P).`,
+				wantResult: []types.TermResults{
+					{"P": "[1,2,3]"}, {"P": "[1,3,2]"}, {"P": "[2,1,3]"}, {"P": "[2,3,1]"}, {"P": "[3,1,2]"}, {"P": "[3,2,1]"},
+				},
+				wantSuccess: true,
+			},
+			{
+				program:     `first(P) :- permutation_k(2, [a, b, c, d], P), !.`,
+				query:       `first(P).`,
+				wantResult:  []types.TermResults{{"P": "[a,b]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `count(K, L, N) :- findall(P, permutation_k(K, L, P), Ps), length(Ps, N).`,
+				query:       `count(3, [1, 2, 3, 4, 5], N).`,
+				wantResult:  []types.TermResults{{"N": "60"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `count(K, L, N) :- findall(P, permutation_k(K, L, P), Ps), length(Ps, N).`,
+				query:       `count(4, [1, 2, 3, 4], N).`,
+				wantResult:  []types.TermResults{{"N": "24"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `permutation_k(0, [], P).`,
+				wantResult:  []types.TermResults{{"P": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `permutation_k(1, [], P).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `permutation_k(-2, [a], P).`,
+				wantError:   fmt.Errorf("permutation_k/3: invalid count: -2, should be non-negative"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("permutation_k"), PermutationK)
+						interpreter.Register3(engine.NewAtom("findall"), engine.FindAll)
+						interpreter.Register2(engine.NewAtom("length"), engine.Length)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestBinPack(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `bin_pack([a-5, b-7, c-3, d-2, e-4, f-6], 10, Bins, []).`,
+				wantResult:  []types.TermResults{{"Bins": "[[b-7,c-3],[f-6,e-4],[a-5,d-2]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bin_pack([a-5, b-7, c-3, d-2, e-4, f-6], 10, Bins, [algorithm(first_fit)]).`,
+				wantResult:  []types.TermResults{{"Bins": "[[a-5,c-3,d-2],[b-7],[e-4,f-6]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bin_pack([x-2, y-2, z-2], 4, Bins, []).`,
+				wantResult:  []types.TermResults{{"Bins": "[[x-2,y-2],[z-2]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bin_pack([x-10, y-0], 10, Bins, []).`,
+				wantResult:  []types.TermResults{{"Bins": "[[x-10,y-0]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bin_pack([], 10, Bins, []).`,
+				wantResult:  []types.TermResults{{"Bins": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `bin_pack([a-5, b-11], 10, Bins, []).`,
+				wantError:   fmt.Errorf("bin_pack/4: item b of size 11 exceeds the capacity of 10"),
+				wantSuccess: false,
+			},
+			{
+				query:       `bin_pack([a-5, b], 10, Bins, []).`,
+				wantError:   fmt.Errorf("bin_pack/4: invalid item type: engine.Atom, should be Name-Size"),
+				wantSuccess: false,
+			},
+			{
+				query:       `bin_pack([a-(-1)], 10, Bins, []).`,
+				wantError:   fmt.Errorf("bin_pack/4: invalid item size: -1, should be a non-negative Integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `bin_pack([a-1], 0, Bins, []).`,
+				wantError:   fmt.Errorf("bin_pack/4: invalid capacity: 0, should be positive"),
+				wantSuccess: false,
+			},
+			{
+				query:       `bin_pack([a-1], 10, Bins, [algorithm(best_fit)]).`,
+				wantError:   fmt.Errorf("bin_pack/4: invalid algorithm option: best_fit, valid values are 'first_fit_decreasing' or 'first_fit'"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register4(engine.NewAtom("bin_pack"), BinPack)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestRoundRobin(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		program := `
+			mem(X, [X|_]).
+			mem(X, [_|T]) :- mem(X, T).
+			appears(S, P) :- mem(R, S), mem(M, R), =..(M, [_|Args]), mem(P, Args).
+			sits_out(S, P) :- mem(R, S), mem(bye(P), R).
+			meets(S, P, Q) :- mem(R, S), mem(P-Q, R).
+			meets(S, P, Q) :- mem(R, S), mem(Q-P, R).
+			counts(_, _, [], []).
+			counts(S, G, [P|Ps], [P-N|Ns]) :- =..(G, [F|Args]), =..(H, [F, S, P|Args]), findall(P, H, L), length(L, N), counts(S, G, Ps, Ns).
+			appearances(Ps, Rounds, Ns) :- round_robin(Ps, Rounds, S), counts(S, appears, Ps, Ns).
+			byes(Ps, Rounds, Ns) :- round_robin(Ps, Rounds, S), counts(S, sits_out, Ps, Ns).
+			meetings(Ps, Rounds, P, Ns) :- round_robin(Ps, Rounds, S), counts(S, meets(P), Ps, Ns).
+		`
+		cases := []struct {
+			program           string
+			query             string
+			maxCollectionSize *sdkmath.Uint
+			wantResult        []types.TermResults
+			wantError         error
+			wantSuccess       bool
+		}{
+			{
+				query:       `round_robin([a, b, c, d], 3, Schedule).`,
+				wantResult:  []types.TermResults{{"Schedule": "[[a-d,b-c],[c-a,d-b],[a-b,c-d]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `round_robin([a, b, c], 4, Schedule).`,
+				wantResult:  []types.TermResults{{"Schedule": "[[b-c,bye(a)],[c-a,bye(b)],[a-b,bye(c)],[b-c,bye(a)]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `round_robin([a, b], 2, Schedule).`,
+				wantResult:  []types.TermResults{{"Schedule": "[[a-b],[b-a]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `round_robin([a], 2, Schedule).`,
+				wantResult:  []types.TermResults{{"Schedule": "[[bye(a)],[bye(a)]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `round_robin([], 2, Schedule).`,
+				wantResult:  []types.TermResults{{"Schedule": "[[],[]]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `round_robin([a, b, c, d], 0, Schedule).`,
+				wantResult:  []types.TermResults{{"Schedule": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `appearances([a, b, c, d, e, f], 5, Ns).`,
+				wantResult:  []types.TermResults{{"Ns": "[a-5,b-5,c-5,d-5,e-5,f-5]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `appearances([a, b, c, d, e], 10, Ns).`,
+				wantResult:  []types.TermResults{{"Ns": "[a-10,b-10,c-10,d-10,e-10]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `byes([a, b, c, d, e], 5, Ns).`,
+				wantResult:  []types.TermResults{{"Ns": "[a-1,b-1,c-1,d-1,e-1]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `byes([a, b, c, d, e, f], 5, Ns).`,
+				wantResult:  []types.TermResults{{"Ns": "[a-0,b-0,c-0,d-0,e-0,f-0]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `meetings([a, b, c, d, e, f, g], 7, c, Ns).`,
+				wantResult:  []types.TermResults{{"Ns": "[a-1,b-1,c-0,d-1,e-1,f-1,g-1]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `meetings([a, b, c, d, e, f], 10, a, Ns).`,
+				wantResult:  []types.TermResults{{"Ns": "[a-0,b-2,c-2,d-2,e-2,f-2]"}},
+				wantSuccess: true,
+			},
+			{
+				query:             `round_robin([a, b, c, d], 3, Schedule).`,
+				maxCollectionSize: lo.ToPtr(sdkmath.NewUint(6)),
+				wantResult:        []types.TermResults{{"Schedule": "[[a-d,b-c],[c-a,d-b],[a-b,c-d]]"}},
+				wantSuccess:       true,
+			},
+			{
+				query:             `round_robin([a, b, c, d], 4, Schedule).`,
+				maxCollectionSize: lo.ToPtr(sdkmath.NewUint(6)),
+				wantError:         fmt.Errorf("round_robin/3: collection of 8 elements exceeds the maximum size of 6 elements"),
+				wantSuccess:       false,
+			},
+			{
+				query:             `round_robin([], 100000000000000, Schedule).`,
+				maxCollectionSize: lo.ToPtr(sdkmath.NewUint(10000)),
+				wantError:         fmt.Errorf("round_robin/3: collection of 100000000000000 elements exceeds the maximum size of 10000 elements"),
+				wantSuccess:       false,
+			},
+			{
+				query:             `round_robin([a, b, c, d, e, f, g, h], 4611686018427387904, Schedule).`,
+				maxCollectionSize: lo.ToPtr(sdkmath.NewUint(10000)),
+				wantError:         fmt.Errorf("round_robin/3: too many rounds: 4611686018427387904"),
+				wantSuccess:       false,
+			},
+			{
+				query:       `round_robin([a, b], -1, Schedule).`,
+				wantError:   fmt.Errorf("round_robin/3: invalid rounds: -1, should be non-negative"),
+				wantSuccess: false,
+			},
+			{
+				query:       `round_robin([a, b], foo, Schedule).`,
+				wantError:   fmt.Errorf("round_robin/3: invalid rounds type: engine.Atom, should be Integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `round_robin(foo, 1, Schedule).`,
+				wantError:   fmt.Errorf("round_robin/3: invalid list: error(type_error(list,foo),round_robin/3)"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+					if tc.maxCollectionSize != nil {
+						ctx = ctx.WithValue(types.MaxCollectionSizeContextKey, *tc.maxCollectionSize)
+					}
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("round_robin"), RoundRobin)
+						interpreter.Register3(engine.NewAtom("findall"), engine.FindAll)
+						interpreter.Register2(engine.NewAtom("length"), engine.Length)
+						interpreter.Register2(engine.NewAtom("=.."), engine.Univ)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
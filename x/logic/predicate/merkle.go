@@ -0,0 +1,131 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/cometbft/cometbft/crypto/merkle"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+// MerkleFileRoot is a predicate which computes the root of the Merkle tree of a file given as a stream of chunks, so
+// that large off-chain contents can be addressed and verified by their root.
+//
+// The signature is as follows:
+//
+//	merkle_file_root(+Chunks, -Root, +Options) is det
+//
+// Where:
+//   - Chunks is the list of the chunks of the file, in order, each of them being encoded according to the encoding
+//     option.
+//   - Root is the root of the Merkle tree of the chunks, as a list of bytes.
+//   - Options are additional configurations for the computation. Supported options include: encoding(+Format) which
+//     specifies the encoding used for the chunks, as for ecdsa_verify/4, and chunk_size(+Size) which, when given,
+//     splits the concatenation of the chunks into chunks of Size bytes (the last one being possibly shorter) before
+//     building the tree, so that the root only depends on the content of the file and not on the way it is streamed.
+//     Any other option raises a domain_error(option, Option) error.
+//
+// The tree follows the RFC 6962 scheme, also used by CometBFT, with SHA-256 and a domain separation between the leaves
+// and the internal nodes:
+//
+//   - the hash of a chunk is SHA-256(0x00 || Chunk);
+//   - the hash of an internal node is SHA-256(0x01 || Left || Right), where Left is the root of the tree of the first
+//     k chunks, k being the largest power of 2 smaller than the number of chunks, and Right the root of the tree of
+//     the remaining ones;
+//   - the root of an empty list of chunks is SHA-256 of the empty string.
+//
+// Examples:
+//
+//	# Compute the root of a file streamed in 3 chunks of octets.
+//	- merkle_file_root([[104, 101], [108, 108], [111]], Root, [encoding(octet)]).
+func MerkleFileRoot(vm *engine.VM, chunks, root, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "merkle_file_root/3"
+
+		if err := util.CheckOptions(options, []engine.Atom{AtomEncoding, engine.NewAtom("chunk_size")}, env); err != nil {
+			return engine.Error(err)
+		}
+		items, err := termToChunks(ctx, chunks, options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		items, err = rechunk(items, options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		if err := checkCollectionSize(ctx, uint64(len(items))); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		size := 0
+		for _, item := range items {
+			size += len(item)
+		}
+		if err := consumeAlgorithmGas(ctx, functor, "sha256", size); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		return engine.Unify(vm, root, BytesToList(merkle.HashFromByteSlices(items)), cont, env)
+	})
+}
+
+// termToChunks decodes the given list of chunks according to the encoding option.
+func termToChunks(ctx context.Context, chunks, options engine.Term, env *engine.Env) ([][]byte, error) {
+	terms, err := termToSlice(chunks, env)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chunks: %w", err)
+	}
+
+	items := make([][]byte, 0, len(terms))
+	size := 0
+	for i, t := range terms {
+		item, err := TermToBytes(ctx, t, options, env)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode chunk at position %d: %w", i+1, err)
+		}
+		size += len(item)
+		if err := checkInputSize(ctx, size); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// rechunk splits the concatenation of the given chunks into chunks of the size given by the chunk_size option, if
+// any, or returns them unchanged otherwise.
+func rechunk(items [][]byte, options engine.Term, env *engine.Env) ([][]byte, error) {
+	sizeTerm, err := util.GetOption(engine.NewAtom("chunk_size"), options, env)
+	if err != nil {
+		return nil, err
+	}
+	if sizeTerm == nil {
+		return items, nil
+	}
+	size, ok := env.Resolve(sizeTerm).(engine.Integer)
+	if !ok || size <= 0 {
+		return nil, fmt.Errorf("invalid chunk_size option: %v, should be a positive Integer", env.Resolve(sizeTerm))
+	}
+
+	var content []byte
+	for _, item := range items {
+		content = append(content, item...)
+	}
+
+	// the size is clamped to the length of the content, so that the count of the chunks cannot overflow.
+	chunk := min(len(content), int(size))
+	if chunk == 0 {
+		return [][]byte{}, nil
+	}
+	result := make([][]byte, 0, len(content)/chunk+min(len(content)%chunk, 1))
+	for len(content) > 0 {
+		n := min(len(content), chunk)
+		result = append(result, content[:n])
+		content = content[n:]
+	}
+	return result, nil
+}
@@ -0,0 +1,171 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestMerkleFileRoot(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `merkle_file_root([[104, 101], [108, 108], [111]], Root, [encoding(octet)]).`,
+				wantResult:  []types.TermResults{{"Root": "[37,206,43,123,201,199,1,204,69,125,200,24,80,230,68,223,142,205,114,174,115,138,164,7,2,139,73,188,112,108,249,32]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `merkle_file_root([[104, 101, 108, 108, 111]], Root, [encoding(octet)]).`,
+				wantResult:  []types.TermResults{{"Root": "[138,42,92,155,118,136,39,222,90,149,82,195,138,4,76,102,149,156,104,246,210,242,27,82,96,175,84,210,248,125,184,39]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `merkle_file_root([], Root, []).`,
+				wantResult:  []types.TermResults{{"Root": "[227,176,196,66,152,252,28,20,154,251,244,200,153,111,185,36,39,174,65,228,100,155,147,76,164,149,153,27,120,82,184,85]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `merkle_file_root([[97], [98], [99], [100], [101]], Root, [encoding(octet)]).`,
+				wantResult:  []types.TermResults{{"Root": "[254,20,165,66,111,189,112,192,250,115,245,35,66,175,237,13,160,189,35,196,131,134,98,204,246,184,138,48,112,234,217,123]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `merkle_file_root([cafe, babe], Root, []).`,
+				wantResult:  []types.TermResults{{"Root": "[166,174,251,86,216,190,203,72,146,55,158,30,106,6,44,95,106,99,198,173,224,119,37,39,184,225,75,44,158,246,198,241]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `merkle_file_root(['68656c', '6c6f'], Root, [encoding(hex)]).`,
+				wantResult:  []types.TermResults{{"Root": "[84,67,227,227,130,241,228,90,71,226,16,132,132,32,128,39,136,199,184,42,225,14,31,237,96,34,197,126,118,59,5,38]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `merkle_file_root([[104], [101, 108, 108, 111]], Root, [encoding(octet), chunk_size(2)]).`,
+				wantResult:  []types.TermResults{{"Root": "[37,206,43,123,201,199,1,204,69,125,200,24,80,230,68,223,142,205,114,174,115,138,164,7,2,139,73,188,112,108,249,32]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `merkle_file_root([[104, 101, 108, 108, 111]], Root, [encoding(octet), chunk_size(3)]).`,
+				wantResult:  []types.TermResults{{"Root": "[84,67,227,227,130,241,228,90,71,226,16,132,132,32,128,39,136,199,184,42,225,14,31,237,96,34,197,126,118,59,5,38]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `merkle_file_root([[104, 101, 108, 108, 111]], Root, [encoding(octet), chunk_size(10)]).`,
+				wantResult:  []types.TermResults{{"Root": "[138,42,92,155,118,136,39,222,90,149,82,195,138,4,76,102,149,156,104,246,210,242,27,82,96,175,84,210,248,125,184,39]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `merkle_file_root([[104, 101, 108, 108, 111]], Root, [encoding(octet), chunk_size(9223372036854775807)]).`,
+				wantResult:  []types.TermResults{{"Root": "[138,42,92,155,118,136,39,222,90,149,82,195,138,4,76,102,149,156,104,246,210,242,27,82,96,175,84,210,248,125,184,39]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `merkle_file_root([[104, 101], [108, 108], [111]], Root, [encoding(octet), chunk_size(3)]).`,
+				wantResult:  []types.TermResults{{"Root": "[84,67,227,227,130,241,228,90,71,226,16,132,132,32,128,39,136,199,184,42,225,14,31,237,96,34,197,126,118,59,5,38]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `merkle_file_root([[104, 101], [108, 108], [111]], [84,67,227,227,130,241,228,90,71,226,16,132,132,32,128,39,136,199,184,42,225,14,31,237,96,34,197,126,118,59,5,38], [encoding(octet)]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `merkle_file_root(foo, Root, []).`,
+				wantError:   fmt.Errorf("merkle_file_root/3: invalid chunks: invalid list: error(type_error(list,foo),merkle_file_root/3)"),
+				wantSuccess: false,
+			},
+			{
+				query:       `merkle_file_root([[104], [256]], Root, [encoding(octet)]).`,
+				wantError:   fmt.Errorf("merkle_file_root/3: failed to decode chunk at position 2: invalid integer value in list at position 1: 256 is out of byte range (0-255)"),
+				wantSuccess: false,
+			},
+			{
+				query:       `merkle_file_root([[104]], Root, [encoding(octet), chunk_size(0)]).`,
+				wantError:   fmt.Errorf("merkle_file_root/3: invalid chunk_size option: 0, should be a positive Integer"),
+				wantSuccess: false,
+			},
+			{
+				query: `catch(merkle_file_root([], Root, encoding), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Root": "_1", "E": "error(type_error(list,encoding),/(merkle_file_root,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("merkle_file_root"), MerkleFileRoot)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+var (
+	// AtomRadix is the term used to indicate the radix domain in a domain error.
+	AtomRadix = engine.NewAtom("radix")
+
+	// AtomAuto is the term auto, used to request the detection of the radix of a number from its prefix.
+	AtomAuto = engine.NewAtom("auto")
+
+	// AtomMaxInteger is the term used to indicate the max_integer limit in a representation error.
+	AtomMaxInteger = engine.NewAtom("max_integer")
+
+	// AtomMinInteger is the term used to indicate the min_integer limit in a representation error.
+	AtomMinInteger = engine.NewAtom("min_integer")
+)
+
+// radixPrefixes are the prefixes of the numbers in the supported radixes, other than 10.
+var radixPrefixes = map[string]int{"0x": 16, "0o": 8, "0b": 2}
+
+// integerLiteral matches the Prolog integer literals, but the character codes, which cannot exceed the range of the
+// Integers: an optional minus sign followed by the digits of the integer, prefixed by its radix if not 10.
+var integerLiteral = regexp.MustCompile(`^(-?)\s*(0x[0-9a-fA-F]+|0o[0-7]+|0b[01]+|[0-9]+)$`)
+
+// NumberString is a predicate which converts a number into its textual representation, and the other way around.
+//
+// The signature is as follows:
+//
+//	number_string(?Number, ?String) is det
+//
+// Where:
+//   - Number is the number.
+//   - String is the textual representation of Number, as a list of characters, or, if bound, as an Atom or a list of
+//     characters or character codes.
+//
+// If String is bound, it is parsed as a Prolog number, as number_codes/2 does, after having removed its leading and
+// trailing white spaces, e.g. " 42 ", "0xff", "0b1010", "0o17" and "0'a" being numbers. A malformed number raises a
+// catchable error(syntax_error(Message), Context) exception, and an integer exceeding the range of the Integers a
+// representation_error(max_integer) or representation_error(min_integer) one. Otherwise, Number is written as
+// number_chars/2 does.
+//
+// Examples:
+//
+//	# Parse a number.
+//	- number_string(N, " 0xff ").
+//
+//	# Write a number.
+//	- number_string(42, S).
+func NumberString(vm *engine.VM, number, str engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		if _, ok := env.Resolve(str).(engine.Variable); ok {
+			return engine.NumberChars(vm, number, str, cont, env)
+		}
+
+		text, err := termToText(str, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("number_string/2: %w", err))
+		}
+		text = strings.TrimSpace(text)
+		if err := integerRangeError(text, env); err != nil {
+			return engine.Error(err)
+		}
+
+		return engine.NumberCodes(vm, number, engine.CodeList(text), cont, env)
+	})
+}
+
+// integerRangeError returns a representation_error(max_integer) or a representation_error(min_integer) error if the
+// given text is an integer literal exceeding the range of the Integers, and nil otherwise, as the parser of the engine
+// reports them as the message of a syntax error.
+func integerRangeError(text string, env *engine.Env) error {
+	matches := integerLiteral.FindStringSubmatch(text)
+	if matches == nil {
+		return nil
+	}
+	digits, base := matches[2], 10
+	for prefix, radix := range radixPrefixes {
+		if strings.HasPrefix(digits, prefix) {
+			digits, base = digits[len(prefix):], radix
+		}
+	}
+
+	n, _ := new(big.Int).SetString(matches[1]+digits, base)
+	switch {
+	case n.IsInt64():
+		return nil
+	case n.Sign() > 0:
+		return representationError(AtomMaxInteger, env)
+	default:
+		return representationError(AtomMinInteger, env)
+	}
+}
+
+// ReadNumberRadix is a predicate which parses an integer written in the given radix.
+//
+// The signature is as follows:
+//
+//	read_number_radix(+Text, +Radix, -Number) is semidet
+//
+// Where:
+//   - Text is the textual representation of the integer, as an Atom or a list of characters or character codes.
+//   - Radix is the radix of the integer, either as an Integer between 2 and 36, or as the Atom auto to detect it from
+//     the prefix of the integer: 0x for 16, 0o for 8, 0b for 2, and 10 without prefix.
+//   - Number is the integer, as an Integer, or as an Atom holding its decimal representation if it exceeds the range of
+//     the Integers (see bignum_add/3).
+//
+// The integer is an optional sign (+ or -), followed by the prefix of its radix, which is optional with an explicit
+// radix, and by its digits in this radix, the letters being case-insensitive, without any white space.
+//
+// Unlike number_string/2, the predicate fails if Text is not a well-formed integer in the radix, so that it can be used
+// to check the data. An invalid Radix raises a domain_error(radix, Radix), and an unbound Text or Radix an
+// instantiation_error.
+//
+// Examples:
+//
+//	# Parse an hexadecimal amount.
+//	- read_number_radix('0xDE0B6B3A7640000', auto, N).
+//
+//	# Parse a binary mask.
+//	- read_number_radix('1010', 2, N).
+func ReadNumberRadix(vm *engine.VM, text, radix, number engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		if _, ok := env.Resolve(text).(engine.Variable); ok {
+			return engine.Error(engine.InstantiationError(env))
+		}
+		t, err := termToText(text, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("read_number_radix/3: %w", err))
+		}
+		base, err := termToRadix(radix, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		n, ok := parseRadixInteger(t, base)
+		if !ok {
+			return engine.Bool(false)
+		}
+		if n.IsInt64() {
+			return engine.Unify(vm, number, engine.Integer(n.Int64()), cont, env)
+		}
+		return engine.Unify(vm, number, engine.NewAtom(n.String()), cont, env)
+	})
+}
+
+// termToRadix converts the given radix, an Integer between 2 and 36 or auto, into a base, 0 standing for auto.
+func termToRadix(radix engine.Term, env *engine.Env) (int, error) {
+	switch r := env.Resolve(radix).(type) {
+	case engine.Integer:
+		if r >= 2 && r <= 36 {
+			return int(r), nil
+		}
+	case engine.Atom:
+		if r == AtomAuto {
+			return 0, nil
+		}
+	}
+	return 0, domainError(AtomRadix, radix, env)
+}
+
+// parseRadixInteger parses the given integer written in the given base, 0 standing for a base detected from its
+// prefix.
+func parseRadixInteger(text string, base int) (*big.Int, bool) {
+	digits := text
+	sign := ""
+	if s := strings.TrimLeft(digits, "+-"); len(digits)-len(s) == 1 {
+		sign, digits = digits[:1], s
+	}
+
+	if len(digits) > 2 {
+		if b, ok := radixPrefixes[strings.ToLower(digits[:2])]; ok && (base == 0 || base == b) {
+			base, digits = b, digits[2:]
+		}
+	}
+	if base == 0 {
+		base = 10
+	}
+	if digits == "" || strings.IndexFunc(digits, func(r rune) bool {
+		return r > unicode.MaxASCII || !unicode.IsDigit(r) && !unicode.IsLetter(r)
+	}) >= 0 {
+		return nil, false
+	}
+
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, false
+	}
+	if sign == "-" {
+		n.Neg(n)
+	}
+	return n, true
+}
@@ -0,0 +1,321 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestNumberString(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `number_string(N, " 42 ").`,
+				wantResult:  []types.TermResults{{"N": "42"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `number_string(N, '0xff').`,
+				wantResult:  []types.TermResults{{"N": "255"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `number_string(N, [0'0, 0'b, 0'1, 0'0, 0'1, 0'0]).`,
+				wantResult:  []types.TermResults{{"N": "10"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `number_string(N, "0o17").`,
+				wantResult:  []types.TermResults{{"N": "15"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `number_string(N, "-1.5e3").`,
+				wantResult:  []types.TermResults{{"N": "-1500.0"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `number_string(42, S).`,
+				wantResult:  []types.TermResults{{"S": "['4','2']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `number_string(255, "0xff").`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `number_string(1, "2").`,
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(number_string(_, "12a"), error(syntax_error(_), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(number_string(_, " 99999999999999999999 "), error(E, C), true).`,
+				wantResult:  []types.TermResults{{"E": "representation_error(max_integer)", "C": "/(number_string,2)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(number_string(_, "- 0x8000000000000001"), error(E, C), true).`,
+				wantResult:  []types.TermResults{{"E": "representation_error(min_integer)", "C": "/(number_string,2)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `number_string(N, "-0x8000000000000000").`,
+				wantResult:  []types.TermResults{{"N": "-9223372036854775808"}},
+				wantSuccess: true,
+			},
+			{
+				query:     `number_string(_, f(x)).`,
+				wantError: fmt.Errorf("number_string/2: invalid text type: *engine.compound, should be atomic or a list of characters or codes"),
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("number_string"), NumberString)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestReadNumberRadix(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `read_number_radix('0xDE0B6B3A7640000', auto, N).`,
+				wantResult:  []types.TermResults{{"N": "1000000000000000000"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_number_radix('-0B1010', auto, N).`,
+				wantResult:  []types.TermResults{{"N": "-10"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_number_radix("+0o777", auto, N).`,
+				wantResult:  []types.TermResults{{"N": "511"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_number_radix('017', auto, N).`,
+				wantResult:  []types.TermResults{{"N": "17"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_number_radix('1010', 2, N).`,
+				wantResult:  []types.TermResults{{"N": "10"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_number_radix('0x1F', 16, N).`,
+				wantResult:  []types.TermResults{{"N": "31"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_number_radix(zz, 36, N).`,
+				wantResult:  []types.TermResults{{"N": "1295"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_number_radix('0xffffffffffffffffffff', auto, N).`,
+				wantResult:  []types.TermResults{{"N": "'1208925819614629174706175'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_number_radix('-9223372036854775808', 10, N).`,
+				wantResult:  []types.TermResults{{"N": "-9223372036854775808"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_number_radix('102', 2, N).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `read_number_radix('0x', auto, N).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `read_number_radix('', auto, N).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `read_number_radix(' 12', auto, N).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `read_number_radix('--12', auto, N).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `read_number_radix('1_000', auto, N).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `read_number_radix('0b12', auto, N).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `read_number_radix('12', 10, 13).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(read_number_radix('12', 37, _), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(radix,37),/(read_number_radix,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(read_number_radix('12', hex, _), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(domain_error(radix,hex),/(read_number_radix,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(read_number_radix('12', _, _), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(instantiation_error,/(read_number_radix,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(read_number_radix(_, auto, _), E, true).`,
+				wantResult:  []types.TermResults{{"E": "error(instantiation_error,/(read_number_radix,3))"}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("read_number_radix"), ReadNumberRadix)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package predicate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+// AtomBitPosition is the term used to indicate the bit position domain in a domain error.
+var AtomBitPosition = engine.NewAtom("bit_position")
+
+// maxPermissionPosition is the highest bit position a permission schema may declare, so that a permission set never
+// exceeds 32 bytes.
+const maxPermissionPosition = 255
+
+// PermissionsDecode is a predicate that decodes a compact bit-packed permission set into the list of granted permission
+// names, according to the given schema.
+//
+// The signature is as follows:
+//
+//	permissions_decode(+Bytes, +Schema, -Granted) is det
+//
+// Where:
+//   - Bytes is the bit-packed permission set, represented as a list of bytes.
+//   - Schema is the list of Name-Position pairs mapping each permission name (an Atom) to its bit position (an
+//     Integer between 0 and 255).
+//   - Granted is the list of the granted permission names, in schema order.
+//
+// Bit positions are numbered from the least significant bit of the first byte: position N refers to the bit N mod 8 of
+// the byte N div 8. Positions beyond the length of Bytes are considered as not granted. A position greater than 255
+// raises a domain_error(bit_position, Position).
+//
+// Examples:
+//
+//	# Decode a permission set.
+//	- permissions_decode([5], [read-0, write-1, admin-2], Granted).
+func PermissionsDecode(vm *engine.VM, bts, schema, granted engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		entries, err := termToPermissionSchema(schema, env)
+		var exception engine.Exception
+		if errors.As(err, &exception) {
+			return engine.Error(err)
+		}
+		if err != nil {
+			return engine.Error(fmt.Errorf("permissions_decode/3: %w", err))
+		}
+
+		data, err := TermToBytes(ctx, bts, AtomEncoding.Apply(AtomOctet), env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("permissions_decode/3: failed to decode bytes: %w", err))
+		}
+
+		names := make([]engine.Term, 0, len(entries))
+		for _, entry := range entries {
+			index := entry.position / 8
+			if index < len(data) && data[index]&(1<<(entry.position%8)) != 0 {
+				names = append(names, entry.name)
+			}
+		}
+
+		return engine.Unify(vm, granted, engine.List(names...), cont, env)
+	})
+}
+
+// PermissionsEncode is a predicate that encodes a list of granted permission names into a compact bit-packed
+// permission set, according to the given schema.
+//
+// The signature is as follows:
+//
+//	permissions_encode(+Granted, +Schema, -Bytes) is det
+//
+// Where:
+//   - Granted is the list of the granted permission names (Atoms). Each name must be declared in the schema.
+//   - Schema is the list of Name-Position pairs mapping each permission name (an Atom) to its bit position (an
+//     Integer between 0 and 255).
+//   - Bytes is the bit-packed permission set, represented as a list of bytes.
+//
+// The bit numbering is the same as for permissions_decode/3, and the resulting list of bytes is always long enough to
+// hold the highest bit position declared in the schema.
+//
+// Examples:
+//
+//	# Encode a permission set.
+//	- permissions_encode([read, admin], [read-0, write-1, admin-2], Bytes).
+func PermissionsEncode(vm *engine.VM, granted, schema, bts engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		entries, err := termToPermissionSchema(schema, env)
+		var exception engine.Exception
+		if errors.As(err, &exception) {
+			return engine.Error(err)
+		}
+		if err != nil {
+			return engine.Error(fmt.Errorf("permissions_encode/3: %w", err))
+		}
+
+		positions := make(map[engine.Atom]int, len(entries))
+		size := 0
+		for _, entry := range entries {
+			positions[entry.name] = entry.position
+			if entry.position/8+1 > size {
+				size = entry.position/8 + 1
+			}
+		}
+
+		if err := checkInputSize(ctx, size); err != nil {
+			return engine.Error(fmt.Errorf("permissions_encode/3: %w", err))
+		}
+		data := make([]byte, size)
+		iter := engine.ListIterator{List: granted, Env: env}
+		for iter.Next() {
+			name, ok := env.Resolve(iter.Current()).(engine.Atom)
+			if !ok {
+				return engine.Error(fmt.Errorf("permissions_encode/3: invalid permission name: %s, should be Atom",
+					iter.Current()))
+			}
+			position, ok := positions[name]
+			if !ok {
+				return engine.Error(fmt.Errorf("permissions_encode/3: unknown permission: %s", name))
+			}
+			data[position/8] |= 1 << (position % 8)
+		}
+		if err := iter.Err(); err != nil {
+			return engine.Error(fmt.Errorf("permissions_encode/3: invalid granted permissions: %w", err))
+		}
+
+		return engine.Unify(vm, bts, BytesToList(data), cont, env)
+	})
+}
+
+// permissionEntry is a single entry of a permission schema.
+type permissionEntry struct {
+	name     engine.Atom
+	position int
+}
+
+// termToPermissionSchema converts the given term into the list of permission entries it declares.
+// The term is expected to be a list of Name-Position pairs, without duplicated names or positions, a position greater
+// than maxPermissionPosition being reported as a domain_error.
+func termToPermissionSchema(schema engine.Term, env *engine.Env) ([]permissionEntry, error) {
+	entries := make([]permissionEntry, 0)
+	names := make(map[engine.Atom]struct{})
+	positions := make(map[int]struct{})
+
+	iter := engine.ListIterator{List: schema, Env: env}
+	for iter.Next() {
+		pair, ok := env.Resolve(iter.Current()).(engine.Compound)
+		if !ok || pair.Functor() != AtomPair || pair.Arity() != 2 {
+			return nil, fmt.Errorf("schema entries should be Name-Position pairs, given %s", iter.Current())
+		}
+		name, ok := env.Resolve(pair.Arg(0)).(engine.Atom)
+		if !ok {
+			return nil, fmt.Errorf("invalid permission name: %s, should be Atom", pair.Arg(0))
+		}
+		position, ok := env.Resolve(pair.Arg(1)).(engine.Integer)
+		if !ok || position < 0 {
+			return nil, fmt.Errorf("invalid bit position for permission %s: %s, should be a non-negative Integer",
+				name, pair.Arg(1))
+		}
+		if position > maxPermissionPosition {
+			return nil, domainError(AtomBitPosition, position, env)
+		}
+		if _, ok := names[name]; ok {
+			return nil, fmt.Errorf("duplicated permission in schema: %s", name)
+		}
+		if _, ok := positions[int(position)]; ok {
+			return nil, fmt.Errorf("duplicated bit position in schema: %d", position)
+		}
+		names[name] = struct{}{}
+		positions[int(position)] = struct{}{}
+
+		entries = append(entries, permissionEntry{name: name, position: int(position)})
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	return entries, nil
+}
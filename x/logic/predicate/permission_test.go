@@ -0,0 +1,186 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestPermissions(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `permissions_decode([5], [read-0, write-1, admin-2], Granted).`,
+				wantResult:  []types.TermResults{{"Granted": "[read,admin]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `permissions_decode([0], [read-0, write-1, admin-2], Granted).`,
+				wantResult:  []types.TermResults{{"Granted": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `permissions_decode([1], [admin-10, write-1, read-0], Granted).`,
+				wantResult:  []types.TermResults{{"Granted": "[read]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `permissions_decode([3, 4], [admin-10, write-1, read-0], Granted).`,
+				wantResult:  []types.TermResults{{"Granted": "[admin,write,read]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `permissions_decode([5], [read-0, write-1, admin-2], [read, admin]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `permissions_decode([5], [read-0, write-1, admin-2], [read]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `permissions_encode([read, admin], [read-0, write-1, admin-2], Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[5]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `permissions_encode([], [read-0, write-1, admin-9], Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[0,0]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `permissions_encode([delete], [read-0, write-1, admin-2], Bytes).`,
+				wantError:   fmt.Errorf("permissions_encode/3: unknown permission: delete"),
+				wantSuccess: false,
+			},
+			{
+				query:       `permissions_decode([5], [read-0, write-a], Granted).`,
+				wantError:   fmt.Errorf("permissions_decode/3: invalid bit position for permission write: a, should be a non-negative Integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `permissions_decode([5], [read-0, write-0], Granted).`,
+				wantError:   fmt.Errorf("permissions_decode/3: duplicated bit position in schema: 0"),
+				wantSuccess: false,
+			},
+			{
+				query:       `permissions_encode([read], [read-0, read-1], Bytes).`,
+				wantError:   fmt.Errorf("permissions_encode/3: duplicated permission in schema: read"),
+				wantSuccess: false,
+			},
+			{
+				query: `catch(permissions_encode([a], [a-1000000000000000], Bytes), E, true).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "E": "error(domain_error(bit_position,1000000000000000),/(permissions_encode,3))",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(permissions_decode([1], [a-0, b-9223372036854775807], Granted), E, true).`,
+				wantResult: []types.TermResults{{
+					"Granted": "_1", "E": "error(domain_error(bit_position,9223372036854775807),/(permissions_decode,3))",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query:       `permissions_encode([a], [a-255], Bytes), length(Bytes, N).`,
+				wantResult:  []types.TermResults{{"Bytes": "[0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,128]", "N": "32"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `permissions_decode([256], [read-0], Granted).`,
+				wantError:   fmt.Errorf("permissions_decode/3: failed to decode bytes: invalid integer value in list at position 1: 256 is out of byte range (0-255)"),
+				wantSuccess: false,
+			},
+			{
+				program: `schema([read-0, write-1, list-7, admin-8, audit-13, owner-23]).
+round_trip(Granted, Decoded, Bytes) :-
+  schema(Schema),
+  permissions_encode(Granted, Schema, Bytes),
+  permissions_decode(Bytes, Schema, Decoded).`,
+				query:       `round_trip([owner, read, audit, list], Decoded, Bytes).`,
+				wantResult:  []types.TermResults{{"Decoded": "[read,list,audit,owner]", "Bytes": "[129,32,128]"}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("permissions_decode"), PermissionsDecode)
+						interpreter.Register3(engine.NewAtom("permissions_encode"), PermissionsEncode)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+						interpreter.Register2(engine.NewAtom("length"), engine.Length)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
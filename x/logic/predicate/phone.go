@@ -0,0 +1,138 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+// phoneRegion holds the numbering plan metadata of a region used by phone_e164/3.
+type phoneRegion struct {
+	// countryCode is the country calling code of the region.
+	countryCode string
+	// internationalPrefix is the prefix dialed in the region to call abroad.
+	internationalPrefix string
+	// nationalPrefix is the trunk prefix dialed in the region before the national numbers, removed from E.164 numbers.
+	nationalPrefix string
+	// lengths are the possible lengths of the national significant numbers of the region.
+	lengths []int
+}
+
+// phoneRegions is the compiled-in subset of the numbering plans supported by phone_e164/3, indexed by ISO 3166-1
+// alpha-2 region code. It is deliberately frozen in the code so that the normalization is the same on all the nodes.
+var phoneRegions = map[string]phoneRegion{
+	"AT": {"43", "00", "0", []int{7, 8, 9, 10, 11, 12, 13}},
+	"AU": {"61", "0011", "0", []int{9}},
+	"BE": {"32", "00", "0", []int{8, 9}},
+	"BR": {"55", "00", "0", []int{10, 11}},
+	"CA": {"1", "011", "1", []int{10}},
+	"CH": {"41", "00", "0", []int{9}},
+	"CN": {"86", "00", "0", []int{10, 11}},
+	"DE": {"49", "00", "0", []int{6, 7, 8, 9, 10, 11, 12, 13}},
+	"DK": {"45", "00", "", []int{8}},
+	"ES": {"34", "00", "", []int{9}},
+	"FI": {"358", "00", "0", []int{6, 7, 8, 9, 10, 11, 12}},
+	"FR": {"33", "00", "0", []int{9}},
+	"GB": {"44", "00", "0", []int{9, 10}},
+	"IE": {"353", "00", "0", []int{7, 8, 9}},
+	"IN": {"91", "00", "0", []int{10}},
+	"IT": {"39", "00", "", []int{6, 7, 8, 9, 10, 11}},
+	"JP": {"81", "010", "0", []int{9, 10}},
+	"LU": {"352", "00", "", []int{4, 5, 6, 7, 8, 9, 10, 11}},
+	"NL": {"31", "00", "0", []int{9}},
+	"NO": {"47", "00", "", []int{8}},
+	"PL": {"48", "00", "", []int{9}},
+	"PT": {"351", "00", "", []int{9}},
+	"SE": {"46", "00", "0", []int{7, 8, 9}},
+	"SG": {"65", "000", "", []int{8}},
+	"US": {"1", "011", "1", []int{10}},
+}
+
+// PhoneE164 is a predicate which normalizes a phone number into its E.164 form.
+//
+// The signature is as follows:
+//
+//	phone_e164(+Input, +DefaultRegion, -E164) is semi-det
+//
+// Where:
+//   - Input is the phone number to normalize, as an Atom, either in its international form (e.g. '+33 6 12 34 56 78'
+//     or '0033 6 12 34 56 78' when dialed from DefaultRegion) or in the national form of DefaultRegion (e.g.
+//     '06 12 34 56 78' in France).
+//   - DefaultRegion is the ISO 3166-1 alpha-2 code of the region whose numbering plan applies to the numbers given in
+//     national or dialed international form, as an Atom (e.g. 'FR').
+//   - E164 is the normalized phone number, as an Atom, made of a + sign followed by the country calling code and the
+//     national significant number (e.g. '+33612345678').
+//
+// The spaces and the usual separators (i.e. the -, ., /, ( and ) characters) of Input are ignored. The national
+// numbers are stripped of the trunk prefix of DefaultRegion (e.g. the leading 0 in France), and the predicate fails if
+// Input is not made of digits, or if the length of its national significant number is not a possible one in its
+// region.
+//
+// The numbering plans are a compiled-in subset of the ITU-T ones, so that the normalization is deterministic, which
+// covers the following regions: AT, AU, BE, BR, CA, CH, CN, DE, DK, ES, FI, FR, GB, IE, IN, IT, JP, LU, NL, NO, PL,
+// PT, SE, SG and US. An error is raised for any other DefaultRegion, and the predicate fails on the international
+// numbers of other regions.
+//
+// Examples:
+//
+//	# Normalize a French number given in its national form.
+//	- phone_e164('06 12 34 56 78', 'FR', E164).
+func PhoneE164(vm *engine.VM, input, defaultRegion, e164 engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		in, ok := env.Resolve(input).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("phone_e164/3: invalid input type: %T, should be Atom", env.Resolve(input)))
+		}
+		code, ok := env.Resolve(defaultRegion).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("phone_e164/3: invalid region type: %T, should be Atom", env.Resolve(defaultRegion)))
+		}
+		region, ok := phoneRegions[strings.ToUpper(code.String())]
+		if !ok {
+			return engine.Error(fmt.Errorf("phone_e164/3: unsupported region: %s", code))
+		}
+
+		number, ok := normalizePhoneNumber(in.String(), region)
+		if !ok {
+			return engine.Bool(false)
+		}
+
+		return engine.Unify(vm, e164, engine.NewAtom(number), cont, env)
+	})
+}
+
+// normalizePhoneNumber returns the E.164 form of the given phone number, according to the numbering plan of the given
+// default region, and whether it is a possible number.
+func normalizePhoneNumber(input string, region phoneRegion) (string, bool) {
+	digits := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" \t-./()", r) {
+			return -1
+		}
+		return r
+	}, input)
+
+	number, isInternational := strings.CutPrefix(digits, "+")
+	if !isInternational {
+		number, isInternational = strings.CutPrefix(digits, region.internationalPrefix)
+	}
+	if number == "" || strings.Trim(number, "0123456789") != "" {
+		return "", false
+	}
+
+	if !isInternational {
+		national := strings.TrimPrefix(number, region.nationalPrefix)
+		return "+" + region.countryCode + national, slices.Contains(region.lengths, len(national))
+	}
+
+	// the country calling codes being prefix-free, the number matches at most one of them.
+	for _, r := range phoneRegions {
+		if national, ok := strings.CutPrefix(number, r.countryCode); ok && slices.Contains(r.lengths, len(national)) {
+			return "+" + number, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,174 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestPhoneE164(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `phone_e164('06 12 34 56 78', 'FR', E164).`,
+				wantResult:  []types.TermResults{{"E164": "'+33612345678'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `phone_e164('(415) 555-2671', 'US', E164).`,
+				wantResult:  []types.TermResults{{"E164": "'+14155552671'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `phone_e164('1-415-555-2671', us, E164).`,
+				wantResult:  []types.TermResults{{"E164": "'+14155552671'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `phone_e164('020 7946 0958', 'GB', E164).`,
+				wantResult:  []types.TermResults{{"E164": "'+442079460958'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `phone_e164('06 6982 1234', 'IT', E164).`,
+				wantResult:  []types.TermResults{{"E164": "'+390669821234'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `phone_e164('+33612345678', 'US', E164).`,
+				wantResult:  []types.TermResults{{"E164": "'+33612345678'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `phone_e164('+49 30 123456', 'FR', E164).`,
+				wantResult:  []types.TermResults{{"E164": "'+4930123456'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `phone_e164('0033 6.12.34.56.78', 'FR', E164).`,
+				wantResult:  []types.TermResults{{"E164": "'+33612345678'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `phone_e164('011 44 20 7946 0958', 'US', E164).`,
+				wantResult:  []types.TermResults{{"E164": "'+442079460958'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `phone_e164('06 12 34 56 78', 'FR', '+33612345678').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `phone_e164('06 12 34 56', 'FR', E164).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `phone_e164('06 12 34 56 AB', 'FR', E164).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `phone_e164('+33 6 12 34 56 789', 'FR', E164).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `phone_e164('+999 123456789', 'FR', E164).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `phone_e164('+', 'FR', E164).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `phone_e164(612345678, 'FR', E164).`,
+				wantError:   fmt.Errorf("phone_e164/3: invalid input type: engine.Integer, should be Atom"),
+				wantSuccess: false,
+			},
+			{
+				query:       `phone_e164('06 12 34 56 78', Region, E164).`,
+				wantError:   fmt.Errorf("phone_e164/3: invalid region type: engine.Variable, should be Atom"),
+				wantSuccess: false,
+			},
+			{
+				query:       `phone_e164('06 12 34 56 78', 'ZZ', E164).`,
+				wantError:   fmt.Errorf("phone_e164/3: unsupported region: ZZ"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("phone_e164"), PhoneE164)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+
+	"github.com/ichiban/prolog/engine"
+
+	cometcrypto "github.com/cometbft/cometbft/crypto"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+// AtomSHA256 is the term used to designate the SHA-256 hash algorithm.
+var AtomSHA256 = engine.NewAtom("sha256")
+
+// PowVerify is a predicate that verifies a proof of work, i.e. that the hash of the concatenation of a challenge and a
+// nonce has at least a given number of leading zero bits.
+//
+// The signature is as follows:
+//
+//	pow_verify(+Challenge, +Nonce, +Difficulty, +Options) is semi-det
+//
+// Where:
+//   - Challenge is the challenge, represented as either a hexadecimal atom or a list of bytes.
+//   - Nonce is the nonce found by the prover, represented as either a hexadecimal atom or a list of bytes.
+//   - Difficulty is the minimum number of leading zero bits required, as a non-negative Integer.
+//   - Options are additional configurations for the verification process. Supported options include:
+//     encoding(+Format) which specifies the encoding used for both the Challenge and the Nonce (hex by default, octet,
+//     base64 or base58, as for ecdsa_verify/4), and algorithm(+Alg) which specifies the hash algorithm to use. The
+//     only supported algorithm is sha256, which is the default.
+//
+// The predicate succeeds if and only if Hash(Challenge || Nonce) has at least Difficulty leading zero bits.
+//
+// Examples:
+//
+//	# Verify a proof of work requiring 16 leading zero bits.
+//	- pow_verify('6f6b7034', '000047ba', 16, []).
+//
+//	# Verify a proof of work given as lists of bytes.
+//	- pow_verify([111, 107, 112, 52], [0, 0, 71, 186], 16, [encoding(octet)]).
+func PowVerify(_ *engine.VM, challenge, nonce, difficulty, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	algorithmOpt := engine.NewAtom("algorithm")
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		algorithmTerm, err := util.GetOptionWithDefault(algorithmOpt, options, AtomSHA256, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("pow_verify/4: %w", err))
+		}
+		algorithm, err := util.ResolveToAtom(env, algorithmTerm)
+		if err != nil {
+			return engine.Error(fmt.Errorf("pow_verify/4: %w", err))
+		}
+		if algorithm != AtomSHA256 {
+			return engine.Error(fmt.Errorf("pow_verify/4: invalid algorithm: %s. Possible values: %s", algorithm, AtomSHA256))
+		}
+
+		minBits, ok := env.Resolve(difficulty).(engine.Integer)
+		if !ok || minBits < 0 {
+			return engine.Error(fmt.Errorf("pow_verify/4: invalid difficulty: %v, should be a non-negative Integer",
+				env.Resolve(difficulty)))
+		}
+
+		decodedChallenge, err := TermToBytes(ctx, challenge, options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("pow_verify/4: failed to decode challenge: %w", err))
+		}
+
+		decodedNonce, err := TermToBytes(ctx, nonce, options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("pow_verify/4: failed to decode nonce: %w", err))
+		}
+
+		hash := cometcrypto.Sha256(append(decodedChallenge, decodedNonce...))
+		if leadingZeros(hash) < int(minBits) {
+			return engine.Bool(false)
+		}
+
+		return cont(env)
+	})
+}
+
+// PowLeadingZeros is a predicate that unifies the number of leading zero bits of the given hash.
+//
+// The signature is as follows:
+//
+//	pow_leading_zeros(+Hash, -Bits) is det
+//
+// Where:
+//   - Hash is the hash to inspect, as a list of bytes.
+//   - Bits is the number of leading zero bits of Hash, starting from the most significant bit of its first byte.
+//
+// Examples:
+//
+//	# Count the leading zero bits of a hash.
+//	- pow_leading_zeros([0, 0, 132, 83], Bits).
+func PowLeadingZeros(vm *engine.VM, hash, count engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		decodedHash, err := TermToBytes(ctx, hash, AtomEncoding.Apply(AtomOctet), env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("pow_leading_zeros/2: failed to decode hash: %w", err))
+		}
+
+		return engine.Unify(vm, count, engine.Integer(leadingZeros(decodedHash)), cont, env)
+	})
+}
+
+// leadingZeros returns the number of leading zero bits of the given bytes.
+func leadingZeros(bs []byte) int {
+	n := 0
+	for _, b := range bs {
+		n += bits.LeadingZeros8(b)
+		if b != 0 {
+			break
+		}
+	}
+
+	return n
+}
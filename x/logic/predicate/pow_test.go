@@ -0,0 +1,163 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestPow(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `pow_verify('6f6b7034', '000047ba', 16, []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pow_verify([111, 107, 112, 52], [0, 0, 71, 186], 16, [encoding(octet), algorithm(sha256)]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pow_verify('6f6b7034', '000047ba', 17, []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `pow_verify('6f6b7034', '00000000', 16, []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `pow_verify('6f6b7034', '00000000', 8, []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pow_verify('6f6b7034', '00000000', 0, []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pow_verify('6f6b7034', '000047ba', 16, [algorithm(md5)]).`,
+				wantError:   fmt.Errorf("pow_verify/4: invalid algorithm: md5. Possible values: sha256"),
+				wantSuccess: false,
+			},
+			{
+				query:       `pow_verify('6f6b7034', '000047ba', -1, []).`,
+				wantError:   fmt.Errorf("pow_verify/4: invalid difficulty: -1, should be a non-negative Integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `pow_verify('6f6b7034', [0, 0, 71, 186], 16, []).`,
+				wantError:   fmt.Errorf("pow_verify/4: failed to decode nonce: invalid term type: engine.list, should be an atom"),
+				wantSuccess: false,
+			},
+			{
+				query:       `pow_leading_zeros([0, 0, 132, 83], Bits).`,
+				wantResult:  []types.TermResults{{"Bits": "16"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pow_leading_zeros([1, 0], Bits).`,
+				wantResult:  []types.TermResults{{"Bits": "7"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pow_leading_zeros([128], Bits).`,
+				wantResult:  []types.TermResults{{"Bits": "0"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pow_leading_zeros([0, 0], Bits).`,
+				wantResult:  []types.TermResults{{"Bits": "16"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pow_leading_zeros([], Bits).`,
+				wantResult:  []types.TermResults{{"Bits": "0"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `pow_leading_zeros(foo, Bits).`,
+				wantError:   fmt.Errorf("pow_leading_zeros/2: failed to decode hash: term should be a List, given engine.Atom"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register4(engine.NewAtom("pow_verify"), PowVerify)
+						interpreter.Register2(engine.NewAtom("pow_leading_zeros"), PowLeadingZeros)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package predicate
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ichiban/prolog/engine"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/types"
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+var (
+	// AtomProgram are terms with principal functor program/2.
+	// It is used to reference a program stored in an objectarium smart contract.
+	AtomProgram = engine.NewAtom("program")
+
+	// AtomConsult is the term used to indicate the consult operation in a permission error.
+	AtomConsult = engine.NewAtom("consult")
+
+	// AtomProcedure is the term used to indicate the procedure permission type in a permission error.
+	AtomProcedure = engine.NewAtom("procedure")
+)
+
+// programForbiddenPredicates are the indicators of the predicates a consulted program cannot refer to, as they have
+// side effects outliving the goals calling them: the modification of the database, of the global state of the
+// interpreter, or the loading of other programs.
+var programForbiddenPredicates = map[string]struct{}{
+	"asserta/1":         {},
+	"assertz/1":         {},
+	"retract/1":         {},
+	"abolish/1":         {},
+	"op/3":              {},
+	"char_conversion/2": {},
+	"set_prolog_flag/2": {},
+	"set_input/1":       {},
+	"set_output/1":      {},
+	"consult/1":         {},
+	"consult_program/1": {},
+}
+
+// ConsultProgram is a predicate which loads the clauses of a program stored on-chain into the database of the query.
+//
+// The signature is as follows:
+//
+//	consult_program(+Source) is det
+//
+// Where:
+//   - Source is the reference of the program, as a program(StorageAddr, ObjectID) term where StorageAddr is the
+//     address of the objectarium smart contract storing the program (in Bech32 format), and ObjectID the identifier of
+//     the object holding its Prolog text, i.e. its hash.
+//
+// The clauses are added to the database of the current query only, as consult/1 does, and are never persisted: they
+// are dropped with the interpreter at the end of the query. The loading consumes gas in proportion to the size of the
+// program, as reading it from the state does, on top of the gas of the query of the storage.
+//
+// A program referring anywhere in its clauses to a predicate with side effects outliving the goal calling it (i.e.
+// asserta/1, assertz/1, retract/1, abolish/1, op/3, char_conversion/2, set_prolog_flag/2, set_input/1, set_output/1,
+// consult/1 or consult_program/1) is rejected before any of its clauses is loaded, and raises a catchable
+// error(permission_error(consult, procedure, PI), Context) exception, where PI is the indicator of the first such
+// predicate.
+//
+// This check is advisory only: it is a static scan of the text of the program, which cannot see the goals the
+// program builds at runtime, e.g. with =../2 and call/1, so that it catches the programs referring to these predicates
+// by mistake, not the ones intending to call them. It is not a security boundary: neither the clauses of the program
+// nor the side effects of these predicates outlive the interpreter of the query, and the predicates forbidden by the
+// permissions of the module are enforced at call time for any goal, including the ones of a consulted program.
+//
+// A storage failing to answer raises an error(contract_error(StorageAddr), context(Context, Message)) exception, as
+// for smart_query/3, and an unbound Source an instantiation_error.
+//
+// Examples:
+//
+//	# Load a library of rules stored in an objectarium.
+//	- consult_program(program('okp415ekvz3qdter33mdnk98v8whv5qdr53yusksnfgc08xd26fpdn3ts8gddht',
+//	  '2f4d1e0be8d2e8ba8265a0e38094f6d4f1d089a1fc71e582b2e05c1f3b8a7a22')).
+func ConsultProgram(vm *engine.VM, source engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		sdkContext, err := util.UnwrapSDKContext(ctx)
+		if err != nil {
+			return engine.Error(err)
+		}
+		wasmKeeper := sdkContext.Value(types.WasmKeeperContextKey).(types.WasmKeeper)
+
+		storage, id, err := termToProgramRef(source, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		req, err := json.Marshal(map[string]any{"object_data": map[string]any{"id": id}})
+		if err != nil {
+			return engine.Error(fmt.Errorf("consult_program/1: %w", err))
+		}
+
+		// the writes of the cached context are never committed, so that the query cannot mutate the state.
+		cacheContext, _ := sdkContext.CacheContext()
+		res, err := wasmKeeper.QuerySmart(cacheContext, storage, req)
+		if err != nil {
+			formal := AtomContractError.Apply(engine.NewAtom(storage.String()))
+			return engine.Error(withMessage(isoError(formal, env), err.Error(), env))
+		}
+		program, err := decodeProgram(res)
+		if err != nil {
+			return engine.Error(fmt.Errorf("consult_program/1: invalid program %s: %w", id, err))
+		}
+
+		sdkContext.GasMeter().ConsumeGas(sdkContext.KVGasConfig().ReadCostPerByte*uint64(len(program)), "consult_program/1")
+
+		pi, err := forbiddenPredicate(vm, program)
+		if err != nil {
+			return engine.Error(fmt.Errorf("consult_program/1: invalid program %s: %w", id, err))
+		}
+		if pi != nil {
+			return engine.Error(permissionError(AtomConsult, AtomProcedure, pi, env))
+		}
+
+		if err := vm.Compile(ctx, program); err != nil {
+			return engine.Error(fmt.Errorf("consult_program/1: failed to load program %s: %w", id, err))
+		}
+
+		return cont(env)
+	})
+}
+
+// termToProgramRef converts the given program(StorageAddr, ObjectID) term into the address of the storage and the
+// identifier of the object holding the program.
+func termToProgramRef(term engine.Term, env *engine.Env) (sdk.AccAddress, string, error) {
+	switch t := env.Resolve(term).(type) {
+	case engine.Variable:
+		return nil, "", engine.InstantiationError(env)
+	case engine.Compound:
+		if t.Functor() != AtomProgram || t.Arity() != 2 {
+			break
+		}
+		storage, err := getBech32(env, t.Arg(0))
+		if err != nil {
+			return nil, "", fmt.Errorf("consult_program/1: %w", err)
+		}
+		id, ok := env.Resolve(t.Arg(1)).(engine.Atom)
+		if storage == nil || !ok {
+			return nil, "", engine.InstantiationError(env)
+		}
+		return storage, id.String(), nil
+	}
+	return nil, "", typeError(AtomProgram, term, env)
+}
+
+// decodeProgram decodes the Prolog text of a program from the response of the storage, which holds the data of the
+// object as a base64 encoded JSON string.
+func decodeProgram(res []byte) (string, error) {
+	var data string
+	if err := json.Unmarshal(res, &data); err != nil {
+		return "", err
+	}
+	program, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", err
+	}
+	return string(program), nil
+}
+
+// forbiddenPredicate returns the indicator of the first predicate of programForbiddenPredicates the given program
+// refers to, or nil if there is none. Any subterm of the clauses and directives of the program is considered as a
+// reference, so that the predicates given to the meta-predicates (e.g. findall/3) are checked as well, but not the goals
+// built at runtime, making the check advisory only (see ConsultProgram).
+func forbiddenPredicate(vm *engine.VM, program string) (engine.Term, error) {
+	p := engine.NewParser(vm, strings.NewReader(program))
+	for p.More() {
+		t, err := p.Term()
+		if err != nil {
+			return nil, err
+		}
+		if pi := forbiddenSubterm(t); pi != nil {
+			return pi, nil
+		}
+	}
+	return nil, nil
+}
+
+// forbiddenSubterm returns the indicator of the first predicate of programForbiddenPredicates the given term, or one
+// of its subterms, is a goal of, or nil if there is none.
+func forbiddenSubterm(term engine.Term) engine.Term {
+	c, ok := term.(engine.Compound)
+	if !ok {
+		return nil
+	}
+	if _, ok := programForbiddenPredicates[fmt.Sprintf("%s/%d", c.Functor(), c.Arity())]; ok {
+		return engine.NewAtom("/").Apply(c.Functor(), engine.Integer(c.Arity()))
+	}
+	for i := 0; i < c.Arity(); i++ {
+		if pi := forbiddenSubterm(c.Arg(i)); pi != nil {
+			return pi
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	errorsmod "cosmossdk.io/errors"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestConsultProgram(t *testing.T) {
+	Convey("Under a mocked environment", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdk.GetConfig().SetBech32PrefixForAccount("okp4", "okp4pub")
+		storage := "okp415ekvz3qdter33mdnk98v8whv5qdr53yusksnfgc08xd26fpdn3ts8gddht"
+		encode := func(program string) string {
+			return fmt.Sprintf("%q", base64.StdEncoding.EncodeToString([]byte(program)))
+		}
+
+		cases := []struct {
+			query      string
+			wantQuery  string
+			response   string
+			queryErr   error
+			wantResult []types.TermResults
+			wantError  error
+		}{
+			{
+				query:      fmt.Sprintf(`consult_program(program('%s', abc)), member(X, [a, b]), foo(X).`, storage),
+				wantQuery:  `{"object_data":{"id":"abc"}}`,
+				response:   encode("foo(a).\nbar(b).\n"),
+				wantResult: []types.TermResults{{"X": "a"}},
+			},
+			{
+				query:      fmt.Sprintf(`consult_program(program('%s', abc)), allowed(X).`, storage),
+				wantQuery:  `{"object_data":{"id":"abc"}}`,
+				response:   encode("allowed(X) :- member(X, [alice, bob]).\n"),
+				wantResult: []types.TermResults{{"X": "alice"}, {"X": "bob"}},
+			},
+			{
+				query:      fmt.Sprintf(`catch(consult_program(program('%s', abc)), E, true).`, storage),
+				wantQuery:  `{"object_data":{"id":"abc"}}`,
+				response:   encode("grant(X) :- findall(Y, member(Y, [X]), Ys), assertz(granted(Ys)).\n"),
+				wantResult: []types.TermResults{{"E": "error(permission_error(consult,procedure,/(assertz,1)),/(consult_program,1))"}},
+			},
+			{
+				query:      fmt.Sprintf(`catch(consult_program(program('%s', abc)), E, true).`, storage),
+				wantQuery:  `{"object_data":{"id":"abc"}}`,
+				response:   encode(":-(set_prolog_flag(double_quotes, atom)).\n"),
+				wantResult: []types.TermResults{{"E": "error(permission_error(consult,procedure,/(set_prolog_flag,2)),/(consult_program,1))"}},
+			},
+			{
+				query:     fmt.Sprintf(`catch(consult_program(program('%s', abc)), error(contract_error(C), context(_, M)), true).`, storage),
+				wantQuery: `{"object_data":{"id":"abc"}}`,
+				queryErr:  errorsmod.Wrap(wasmtypes.ErrQueryFailed, "object not found"),
+				wantResult: []types.TermResults{{
+					"C": "okp415ekvz3qdter33mdnk98v8whv5qdr53yusksnfgc08xd26fpdn3ts8gddht",
+					"M": "'object not found: query wasm contract failed'",
+				}},
+			},
+			{
+				query:     fmt.Sprintf(`consult_program(program('%s', abc)).`, storage),
+				wantQuery: `{"object_data":{"id":"abc"}}`,
+				response:  `"foo(a)."`,
+				wantError: fmt.Errorf("consult_program/1: invalid program abc: illegal base64 data at input byte 3"),
+			},
+			{
+				query:     fmt.Sprintf(`consult_program(program('%s', abc)).`, storage),
+				wantQuery: `{"object_data":{"id":"abc"}}`,
+				response:  encode("foo(a"),
+				wantError: fmt.Errorf("consult_program/1: invalid program abc: EOF"),
+			},
+			{
+				query:      `catch(consult_program(foo), E, true).`,
+				wantResult: []types.TermResults{{"E": "error(type_error(program,foo),/(consult_program,1))"}},
+			},
+			{
+				query:      `catch(consult_program(_), E, true).`,
+				wantResult: []types.TermResults{{"E": "error(instantiation_error,/(consult_program,1))"}},
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					wasmKeeper := testutil.NewMockWasmKeeper(ctrl)
+					ctx := sdk.
+						NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger()).
+						WithValue(types.WasmKeeperContextKey, wasmKeeper)
+
+					Convey("and a wasm keeper answering the query", func() {
+						wasmKeeper.
+							EXPECT().
+							QuerySmart(gomock.Any(), sdk.MustAccAddressFromBech32(storage), []byte(tc.wantQuery)).
+							AnyTimes().
+							Return([]byte(tc.response), tc.queryErr)
+
+						Convey("and a vm", func() {
+							interpreter := testutil.NewLightInterpreterMust(ctx)
+							interpreter.Register1(engine.NewAtom("consult_program"), ConsultProgram)
+							interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+							interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise {
+								return cont(env)
+							})
+
+							Convey("When the predicate is called", func() {
+								sols, err := interpreter.QueryContext(ctx, tc.query)
+
+								Convey("Then the error should be nil", func() {
+									So(err, ShouldBeNil)
+									So(sols, ShouldNotBeNil)
+
+									Convey("and the bindings should be as expected", func() {
+										var got []types.TermResults
+										for sols.Next() {
+											m := types.TermResults{}
+											err := sols.Scan(m)
+											So(err, ShouldBeNil)
+
+											got = append(got, m)
+										}
+										if tc.wantError != nil {
+											So(sols.Err(), ShouldNotBeNil)
+											So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+										} else {
+											So(sols.Err(), ShouldBeNil)
+											So(got, ShouldResemble, tc.wantResult)
+										}
+									})
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,276 @@
+package predicate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/ichiban/prolog/engine"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+// hashBuckets is the number of buckets the seeds are mapped to by hash_bucket_percent/2.
+const hashBuckets = 100
+
+// cryptoRandomInfo is the HKDF info of crypto_random_bytes/3, separating its output from any other use of the block
+// data as key material.
+const cryptoRandomInfo = "okp4d/logic/crypto_random_bytes/v1"
+
+// cryptoRandomMaxLength is the maximum number of bytes of crypto_random_bytes/3, which is the maximum output length
+// of HKDF with SHA-256.
+const cryptoRandomMaxLength = 255 * sha256.Size
+
+// AtomLength is the term used to indicate the length domain in a domain error.
+var AtomLength = engine.NewAtom("length")
+
+// HashBucketPercent is a predicate which deterministically maps a seed to a bucket, numbered from 0 to 99, allowing
+// to implement stable sampling decisions such as progressive rollouts.
+//
+// The signature is as follows:
+//
+//	hash_bucket_percent(+Seed, -Percent) is det
+//
+// Where:
+//   - Seed is the seed, as any atomic term (Atom or number) or as a list of characters or character codes.
+//   - Percent is the bucket of the seed, as an Integer between 0 and 99.
+//
+// The seed is hashed with the SHA-256 algorithm, and the digest is mapped uniformly to a bucket by rejection
+// sampling, without any modulo bias. As it only depends on the seed, the bucket is the same on all the nodes.
+//
+// Examples:
+//
+//	# Check whether an address belongs to a cohort of 10%.
+//	- hash_bucket_percent('okp41ffzp0xmjhwkltuxcvccl0z9tyfuu7txp5ke0tpkcjpzuq9fcj3pqrteqt3', Percent), Percent < 10.
+func HashBucketPercent(vm *engine.VM, seed, percent engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		text, err := termToText(seed, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("hash_bucket_percent/2: %w", err))
+		}
+
+		return engine.Unify(vm, percent, engine.Integer(hashBucket(text, hashBuckets)), cont, env)
+	})
+}
+
+// DeterministicRandom is a predicate which derives a pseudo-random integer from an explicit seed.
+//
+// The signature is as follows:
+//
+//	deterministic_random(+Seed, +Bound, -Value) is det
+//
+// Where:
+//   - Seed is the seed, as any ground acyclic term, e.g. seed(Height, Time) built from the block data.
+//   - Bound is the exclusive upper bound of Value, as a positive Integer.
+//   - Value is the pseudo-random Integer, uniformly distributed between 0 and Bound - 1.
+//
+// No random source is involved: Value is a pure function of Seed and Bound, so that it is the same on all the nodes.
+// The seeding contract is the following: Seed is serialized into its canonical textual form, as written by
+// write_canonical/1 (i.e. quoted and ignoring the operators), and the pseudo-random numbers are drawn from the
+// SHA-256 digests of this text suffixed by a 64 bits big-endian counter starting at 0, each digest giving a number
+// from its first 8 bytes, rejected when falling in the incomplete last range of values to avoid any modulo bias.
+// Consequently, the same Seed always yields the same values, and distinct values are obtained from distinct seeds,
+// typically by including the block height, time or hash (see block_height/1, block_time/1 and comet_header_hash/2)
+// along with a discriminating term, e.g. seed(Height, lottery).
+//
+// Examples:
+//
+//	# Draw a number between 0 and 99 from the block height.
+//	- block_height(Height), deterministic_random(seed(Height, draw), 100, Value).
+func DeterministicRandom(vm *engine.VM, seed, bound, value engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		n, ok := env.Resolve(bound).(engine.Integer)
+		if !ok {
+			return engine.Error(fmt.Errorf("deterministic_random/3: invalid bound type: %T, should be Integer", env.Resolve(bound)))
+		}
+		if n <= 0 {
+			return engine.Error(fmt.Errorf("deterministic_random/3: invalid bound: %d, should be positive", n))
+		}
+
+		return withDeterministicRand(vm, seed, env, func(r *deterministicRand) *engine.Promise {
+			return engine.Unify(vm, value, engine.Integer(r.intn(uint64(n))), cont, env)
+		}, "deterministic_random/3")
+	})
+}
+
+// DeterministicRandomPermutation is a predicate which shuffles a list pseudo-randomly from an explicit seed.
+//
+// The signature is as follows:
+//
+//	deterministic_random_permutation(+Seed, +List, -Permutation) is det
+//
+// Where:
+//   - Seed is the seed, as any ground acyclic term, e.g. seed(Height, Time) built from the block data.
+//   - List is the list to shuffle.
+//   - Permutation is a permutation of List, uniformly drawn among all its permutations.
+//
+// No random source is involved: Permutation is a pure function of Seed and List, so that it is the same on all the
+// nodes. The pseudo-random numbers are drawn from Seed following the seeding contract of deterministic_random/3, and
+// the list is shuffled with the Fisher-Yates algorithm, swapping its last element with one of the elements up to it,
+// and so on down to its second element.
+//
+// Examples:
+//
+//	# Shuffle candidates to break ties.
+//	- block_height(Height), deterministic_random_permutation(seed(Height, ties), [alice, bob, carol], Permutation).
+func DeterministicRandomPermutation(
+	vm *engine.VM, seed, list, permutation engine.Term, cont engine.Cont, env *engine.Env,
+) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		elems, err := termToSlice(list, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("deterministic_random_permutation/3: %w", err))
+		}
+
+		return withDeterministicRand(vm, seed, env, func(r *deterministicRand) *engine.Promise {
+			for i := len(elems) - 1; i > 0; i-- {
+				j := r.intn(uint64(i + 1))
+				elems[i], elems[j] = elems[j], elems[i]
+			}
+			return engine.Unify(vm, permutation, engine.List(elems...), cont, env)
+		}, "deterministic_random_permutation/3")
+	})
+}
+
+// CryptoRandomBytes is a predicate which derives pseudo-random bytes from the current block, so that all the nodes
+// executing the same query in the same block get the same bytes.
+//
+// The signature is as follows:
+//
+//	crypto_random_bytes(+N, -Bytes, +Options) is det
+//
+// Where:
+//   - N is the number of bytes to derive, as an Integer between 0 and 8160.
+//   - Bytes is the list of the N derived bytes.
+//   - Options are additional configurations for the derivation. Supported options include: context(+Context) which
+//     specifies an Atom separating the bytes derived for distinct purposes in the same block (default ”). Any other
+//     option raises a domain_error(option, Option) error.
+//
+// The bytes are derived with HKDF-SHA256 (see RFC 5869) from the input key material made of the height of the block,
+// as a 64 bits big-endian integer, its time, as the 64 bits big-endian number of seconds since the Unix epoch followed
+// by the 32 bits big-endian number of nanoseconds within the second, and the hash of the application state, the salt
+// being the text of Context and the info the okp4d/logic/crypto_random_bytes/v1 text. The bytes of a shorter N are the
+// prefix of the ones of a longer N.
+//
+// No random source is involved, the bytes being a pure function of the block and of Context: they are unpredictable
+// before the block is proposed, but known by its proposer before and by everyone after, and must not be used as a
+// secret, e.g. as a private key, but only, for instance, as a nonce or as a public challenge.
+//
+// An unbound N or Context raises an instantiation_error, an N which is not an Integer a type_error(integer, N) error, an
+// N out of range a domain_error(length, N) error, and a Context which is not an Atom a type_error(atom, Context) error.
+//
+// Examples:
+//
+//	# Derive a 32 bytes nonce for a commitment scheme.
+//	- crypto_random_bytes(32, Nonce, [context(commit)]).
+func CryptoRandomBytes(vm *engine.VM, n, bts, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "crypto_random_bytes/3"
+
+		length, ok := env.Resolve(n).(engine.Integer)
+		if !ok {
+			return engine.Error(typeError(AtomInteger, n, env))
+		}
+		if length < 0 || length > cryptoRandomMaxLength {
+			return engine.Error(domainError(AtomLength, length, env))
+		}
+		if err := util.CheckOptions(options, []engine.Atom{AtomContext}, env); err != nil {
+			return engine.Error(err)
+		}
+		contextTerm, err := util.GetOptionWithDefault(AtomContext, options, engine.NewAtom(""), env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		salt, ok := env.Resolve(contextTerm).(engine.Atom)
+		if !ok {
+			return engine.Error(typeError(AtomAtom, contextTerm, env))
+		}
+
+		sdkContext, err := util.UnwrapSDKContext(ctx)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		if err := consumeAlgorithmGas(ctx, functor, "hkdf", int(length)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		header := sdkContext.BlockHeader()
+		secret := binary.BigEndian.AppendUint64(nil, uint64(header.Height))
+		secret = binary.BigEndian.AppendUint64(secret, uint64(header.Time.Unix()))
+		secret = binary.BigEndian.AppendUint32(secret, uint32(header.Time.Nanosecond()))
+		secret = append(secret, header.AppHash...)
+
+		result := make([]byte, length)
+		if _, err := io.ReadFull(hkdf.New(sha256.New, secret, []byte(salt.String()), []byte(cryptoRandomInfo)), result); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		return engine.Unify(vm, bts, BytesToList(result), cont, env)
+	})
+}
+
+// deterministicRand is a deterministic source of pseudo-random numbers, derived from a seed by hashing it along with
+// a counter.
+type deterministicRand struct {
+	seed    []byte
+	counter uint64
+}
+
+// withDeterministicRand calls the given function with a deterministicRand seeded with the canonical textual form of
+// the given seed, which must be ground.
+func withDeterministicRand(
+	vm *engine.VM, seed engine.Term, env *engine.Env, fn func(*deterministicRand) *engine.Promise, indicator string,
+) *engine.Promise {
+	if err := acyclicTermError(seed, env); err != nil {
+		return engine.Error(err)
+	}
+	if len(termVariables(seed, env)) > 0 {
+		return engine.Error(fmt.Errorf("%s: seed is not sufficiently instantiated", indicator))
+	}
+
+	var sb strings.Builder
+	options := engine.List(engine.NewAtom("quoted").Apply(AtomTrue), engine.NewAtom("ignore_ops").Apply(AtomTrue))
+	return engine.WriteTerm(vm, engine.NewOutputTextStream(&sb), seed, options, func(env *engine.Env) *engine.Promise {
+		return fn(&deterministicRand{seed: []byte(sb.String())})
+	}, env)
+}
+
+// next returns the next pseudo-random number, from the first 8 bytes of the SHA-256 digest of the seed suffixed by
+// the counter.
+func (r *deterministicRand) next() uint64 {
+	data := binary.BigEndian.AppendUint64(append([]byte{}, r.seed...), r.counter)
+	r.counter++
+	digest := sha256.Sum256(data)
+	return binary.BigEndian.Uint64(digest[:8])
+}
+
+// intn returns a pseudo-random number uniformly distributed between 0 and n - 1, rejecting the numbers falling in the
+// incomplete last range of values, which would otherwise bias the lower ones.
+func (r *deterministicRand) intn(n uint64) uint64 {
+	limit := math.MaxUint64 - math.MaxUint64%n
+	for {
+		if v := r.next(); v < limit {
+			return v % n
+		}
+	}
+}
+
+// hashBucket maps the given seed uniformly to one of the given number of buckets.
+//
+// The first 64 bits of the SHA-256 digest of the seed are used as a random number, which is rejected, and the digest
+// hashed again, when it falls in the incomplete last range of values, which would otherwise bias the lower buckets.
+func hashBucket(seed string, buckets uint64) uint64 {
+	limit := math.MaxUint64 - math.MaxUint64%buckets
+	digest := sha256.Sum256([]byte(seed))
+	for {
+		if v := binary.BigEndian.Uint64(digest[:8]); v < limit {
+			return v % buckets
+		}
+		digest = sha256.Sum256(digest[:])
+	}
+}
@@ -0,0 +1,545 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"go/build"
+	"testing"
+	"time"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestHashBucketPercent(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `hash_bucket_percent('okp41ffzp0xmjhwkltuxcvccl0z9tyfuu7txp5ke0tpkcjpzuq9fcj3pqrteqt3', Percent).`,
+				wantResult:  []types.TermResults{{"Percent": "49"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hash_bucket_percent(foo, Percent).`,
+				wantResult:  []types.TermResults{{"Percent": "39"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hash_bucket_percent([f,o,o], Percent).`,
+				wantResult:  []types.TermResults{{"Percent": "39"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hash_bucket_percent(42, Percent).`,
+				wantResult:  []types.TermResults{{"Percent": "37"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hash_bucket_percent('42', 37).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `hash_bucket_percent(foo, 40).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `hash_bucket_percent(foo(bar), Percent).`,
+				wantError:   fmt.Errorf("hash_bucket_percent/2: invalid text type: *engine.compound, should be atomic or a list of characters or codes"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("hash_bucket_percent"), HashBucketPercent)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestDeterministicRandom(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `deterministic_random(foo, 100, Value).`,
+				wantResult:  []types.TermResults{{"Value": "86"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random(seed(42, draw), 100, Value).`,
+				wantResult:  []types.TermResults{{"Value": "79"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random(seed(43, draw), 100, Value).`,
+				wantResult:  []types.TermResults{{"Value": "73"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random('seed(42,draw)', 100, Value).`,
+				wantResult:  []types.TermResults{{"Value": "69"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random(foo, 1000000000000, Value).`,
+				wantResult:  []types.TermResults{{"Value": "319209500886"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random(foo, 1, Value).`,
+				wantResult:  []types.TermResults{{"Value": "0"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random(seed(42, draw), 100, 79).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random(seed(42, draw), 100, 80).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `catch((S = seed(S), deterministic_random(S, 100, Value)), E, R = caught).`,
+				wantResult:  []types.TermResults{{"S": "_1", "Value": "_1", "R": "caught", "E": "error(type_error(acyclic_term,_1),/(deterministic_random,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random(seed(42, draw), 100, X), deterministic_random(seed(42, draw), 100, Y), X == Y.`,
+				wantResult:  []types.TermResults{{"X": "79", "Y": "79"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random(Seed, 100, Value).`,
+				wantError:   fmt.Errorf("deterministic_random/3: seed is not sufficiently instantiated"),
+				wantSuccess: false,
+			},
+			{
+				query:       `deterministic_random(seed(_), 100, Value).`,
+				wantError:   fmt.Errorf("deterministic_random/3: seed is not sufficiently instantiated"),
+				wantSuccess: false,
+			},
+			{
+				query:       `deterministic_random(foo, 0, Value).`,
+				wantError:   fmt.Errorf("deterministic_random/3: invalid bound: 0, should be positive"),
+				wantSuccess: false,
+			},
+			{
+				query:       `deterministic_random(foo, bar, Value).`,
+				wantError:   fmt.Errorf("deterministic_random/3: invalid bound type: engine.Atom, should be Integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `deterministic_random_permutation(seed(42, ties), [alice, bob, carol], Permutation).`,
+				wantResult:  []types.TermResults{{"Permutation": "[bob,alice,carol]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random_permutation(foo, [1,2,3,4,5,6,7,8,9,10], Permutation).`,
+				wantResult:  []types.TermResults{{"Permutation": "[1,3,2,8,5,9,4,10,6,7]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random_permutation(foo, [x], Permutation).`,
+				wantResult:  []types.TermResults{{"Permutation": "[x]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random_permutation(foo, [], Permutation).`,
+				wantResult:  []types.TermResults{{"Permutation": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random_permutation(foo, [X, Y], Permutation).`,
+				wantResult:  []types.TermResults{{"X": "_1", "Y": "_1", "Permutation": "[_1,_2]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `deterministic_random_permutation(S, [a, b], Permutation).`,
+				wantError:   fmt.Errorf("deterministic_random_permutation/3: seed is not sufficiently instantiated"),
+				wantSuccess: false,
+			},
+			{
+				query:       `deterministic_random_permutation(foo, bar, Permutation).`,
+				wantError:   fmt.Errorf("deterministic_random_permutation/3: invalid list: error(type_error(list,bar),deterministic_random_permutation/3)"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("deterministic_random"), DeterministicRandom)
+						interpreter.Register3(engine.NewAtom("deterministic_random_permutation"), DeterministicRandomPermutation)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestHashBucketUniformity(t *testing.T) {
+	Convey("Given a large number of seeds", t, func() {
+		const seeds = 100000
+		counts := make([]int, hashBuckets)
+
+		Convey("When the seeds are mapped to buckets", func() {
+			for i := 0; i < seeds; i++ {
+				counts[hashBucket(fmt.Sprintf("okp4-%d", i), hashBuckets)]++
+			}
+
+			Convey("Then the buckets should be evenly filled", func() {
+				for _, count := range counts {
+					So(count, ShouldBeBetween, seeds/hashBuckets*85/100, seeds/hashBuckets*115/100)
+				}
+			})
+
+			Convey("And the mapping should be reproducible", func() {
+				for i := 0; i < 100; i++ {
+					seed := fmt.Sprintf("okp4-%d", i)
+					So(hashBucket(seed, hashBuckets), ShouldEqual, hashBucket(seed, hashBuckets))
+				}
+			})
+		})
+	})
+}
+
+func TestCryptoRandomBytes(t *testing.T) {
+	Convey("Given a block and test cases", t, func() {
+		header := tmproto.Header{Height: 42, Time: time.Unix(1494505756, 500), AppHash: []byte{0xde, 0xad, 0xbe, 0xef}}
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `crypto_random_bytes(8, Bytes, []).`,
+				wantResult:  []types.TermResults{{"Bytes": "[109,45,67,72,39,68,170,19]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `crypto_random_bytes(8, Bytes, [context('')]).`,
+				wantResult:  []types.TermResults{{"Bytes": "[109,45,67,72,39,68,170,19]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `crypto_random_bytes(16, Bytes, [context(commit)]).`,
+				wantResult:  []types.TermResults{{"Bytes": "[196,37,118,221,111,30,22,181,37,103,39,66,187,11,187,61]"}},
+				wantSuccess: true,
+			},
+			{ // longer than a SHA-256 digest, and starting with the bytes of a shorter length
+				query: `crypto_random_bytes(40, Bytes, [context(commit)]), hex_bytes(Hex, Bytes),
+				        crypto_random_bytes(16, Prefix, [context(commit)]), append(Prefix, _, Bytes).`,
+				wantResult: []types.TermResults{{
+					"Bytes":  "[196,37,118,221,111,30,22,181,37,103,39,66,187,11,187,61,217,94,24,193,33,181,96,153,66,99,133,21,103,28,115,12,153,12,34,249,210,204,34,214]",
+					"Hex":    "c42576dd6f1e16b525672742bb0bbb3dd95e18c121b5609942638515671c730c990c22f9d2cc22d6",
+					"Prefix": "[196,37,118,221,111,30,22,181,37,103,39,66,187,11,187,61]",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query:       `crypto_random_bytes(0, Bytes, []).`,
+				wantResult:  []types.TermResults{{"Bytes": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `crypto_random_bytes(32, A, [context(nonce)]), crypto_random_bytes(32, B, [context(nonce)]), A == B.`,
+				wantResult:  []types.TermResults{{"A": "[95,196,140,138,145,254,17,122,28,70,206,88,91,104,104,116,61,40,196,200,54,17,188,231,2,160,126,24,229,88,79,132]", "B": "[95,196,140,138,145,254,17,122,28,70,206,88,91,104,104,116,61,40,196,200,54,17,188,231,2,160,126,24,229,88,79,132]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `crypto_random_bytes(32, A, [context(a)]), crypto_random_bytes(32, A, [context(b)]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `findall(L, (crypto_random_bytes(8160, Bytes, []), length(Bytes, L)), Ls).`,
+				wantResult:  []types.TermResults{{"L": "_1", "Bytes": "_1", "Ls": "[8160]"}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crypto_random_bytes(N, Bytes, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"N": "_1", "Bytes": "_1", "E": "error(instantiation_error,/(crypto_random_bytes,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crypto_random_bytes(foo, Bytes, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "E": "error(type_error(integer,foo),/(crypto_random_bytes,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crypto_random_bytes(-1, Bytes, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "E": "error(domain_error(length,-1),/(crypto_random_bytes,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crypto_random_bytes(8161, Bytes, []), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "E": "error(domain_error(length,8161),/(crypto_random_bytes,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crypto_random_bytes(8, Bytes, [context(42)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "E": "error(type_error(atom,42),/(crypto_random_bytes,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crypto_random_bytes(8, Bytes, [context(C)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "C": "_1", "E": "error(instantiation_error,/(crypto_random_bytes,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `catch(crypto_random_bytes(8, Bytes, [seed(42)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Bytes": "_1", "E": "error(domain_error(option,seed(42)),/(crypto_random_bytes,3))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, header, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("crypto_random_bytes"), CryptoRandomBytes)
+						interpreter.Register3(engine.NewAtom("findall"), engine.FindAll)
+						interpreter.Register2(engine.NewAtom("hex_bytes"), HexBytes)
+						interpreter.Register3(engine.NewAtom("append"), engine.Append)
+						interpreter.Register2(engine.NewAtom("length"), engine.Length)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestCryptoRandomBytesDependsOnTheBlock(t *testing.T) {
+	Convey("Given distinct blocks", t, func() {
+		headers := []tmproto.Header{
+			{Height: 42, Time: time.Unix(1494505756, 500), AppHash: []byte{0xde, 0xad, 0xbe, 0xef}},
+			{Height: 43, Time: time.Unix(1494505756, 500), AppHash: []byte{0xde, 0xad, 0xbe, 0xef}},
+			{Height: 42, Time: time.Unix(1494505757, 500), AppHash: []byte{0xde, 0xad, 0xbe, 0xef}},
+			{Height: 42, Time: time.Unix(1494505756, 501), AppHash: []byte{0xde, 0xad, 0xbe, 0xef}},
+			{Height: 42, Time: time.Unix(1494505756, 500), AppHash: []byte{0xde, 0xad, 0xbe, 0xee}},
+			{Height: 42, Time: time.Unix(1494505756, 500)},
+		}
+
+		Convey("When deriving bytes twice in each of them", func() {
+			derive := func(header tmproto.Header) string {
+				db := tmdb.NewMemDB()
+				stateStore := store.NewCommitMultiStore(db)
+				ctx := sdk.NewContext(stateStore, header, false, log.NewNopLogger())
+				interpreter := testutil.NewLightInterpreterMust(ctx)
+				interpreter.Register3(engine.NewAtom("crypto_random_bytes"), CryptoRandomBytes)
+
+				sols, err := interpreter.QueryContext(ctx, `crypto_random_bytes(32, Bytes, []).`)
+				So(err, ShouldBeNil)
+				So(sols.Next(), ShouldBeTrue)
+				m := types.TermResults{}
+				So(sols.Scan(m), ShouldBeNil)
+				return string(m["Bytes"])
+			}
+
+			Convey("Then the bytes should be the same in a block but differ between blocks", func() {
+				seen := map[string]int{}
+				for i, header := range headers {
+					bytes := derive(header)
+					So(derive(header), ShouldEqual, bytes)
+					So(seen, ShouldNotContainKey, bytes)
+					seen[bytes] = i
+				}
+			})
+		})
+	})
+}
+
+func TestCryptoRandomBytesUsesNoRandomSource(t *testing.T) {
+	Convey("Given the imports of the predicates", t, func() {
+		pkg, err := build.ImportDir(".", 0)
+		So(err, ShouldBeNil)
+
+		Convey("Then no random source should be imported, the derived bytes being deterministic by construction", func() {
+			So(pkg.Imports, ShouldNotContain, "crypto/rand")
+			So(pkg.Imports, ShouldNotContain, "math/rand")
+			So(pkg.Imports, ShouldNotContain, "math/rand/v2")
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+var (
+	// AtomRBAC are terms with principal functor rbac/3.
+	// It is used to represent a role based access control policy.
+	AtomRBAC = engine.NewAtom("rbac")
+
+	// AtomGrant are terms with principal functor grant/3.
+	// It is used to represent the grant of a permission to a role.
+	AtomGrant = engine.NewAtom("grant")
+)
+
+// rbacWildcard is the wildcard matching any action or resource in a grant.
+const rbacWildcard = "*"
+
+// RBACAllowed is a predicate which checks whether a subject is allowed to perform an action on a resource, according
+// to a role based access control (RBAC) policy.
+//
+// The signature is as follows:
+//
+//	rbac_allowed(+Subject, +Action, +Resource, +Policy) is semidet
+//
+// Where:
+//   - Subject is the subject performing the action, as an Atom.
+//   - Action is the action to perform, as an Atom.
+//   - Resource is the resource the action is performed on, as an Atom.
+//   - Policy is the policy, as a rbac(Assignments, Grants, Inheritance) term where:
+//     Assignments is the list of Subject-Role pairs assigning roles to subjects;
+//     Grants is the list of grant(Role, Action, Resource) terms granting the permission to perform an action on a
+//     resource to a role;
+//     Inheritance is the list of Role-Parent pairs, stating that Role inherits all the grants of Parent.
+//
+// The predicate succeeds if and only if one of the roles of the subject, either directly assigned or inherited
+// (transitively), has a grant matching the action and the resource. In a grant, the action or the resource '*' matches
+// anything, while a value ending with '*' matches any value starting with the same prefix. Cycles in the role
+// inheritance graph are allowed, each role being considered only once.
+//
+// Examples:
+//
+//	# Check that alice, being an editor inheriting from viewer, can read any document.
+//	- rbac_allowed(alice, read, 'doc/42', rbac([alice-editor], [grant(viewer, read, 'doc/*')], [editor-viewer])).
+func RBACAllowed(_ *engine.VM, subject, action, resource, policy engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		subjectAtom, ok := env.Resolve(subject).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("rbac_allowed/4: invalid subject type: %T, should be Atom", env.Resolve(subject)))
+		}
+		actionAtom, ok := env.Resolve(action).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("rbac_allowed/4: invalid action type: %T, should be Atom", env.Resolve(action)))
+		}
+		resourceAtom, ok := env.Resolve(resource).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("rbac_allowed/4: invalid resource type: %T, should be Atom", env.Resolve(resource)))
+		}
+
+		p, err := termToRBACPolicy(policy, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("rbac_allowed/4: %w", err))
+		}
+
+		visited := make(map[engine.Atom]struct{})
+		roles := append([]engine.Atom{}, p.assignments[subjectAtom]...)
+		for len(roles) > 0 {
+			role := roles[0]
+			roles = roles[1:]
+			if _, ok := visited[role]; ok {
+				continue
+			}
+			visited[role] = struct{}{}
+
+			for _, g := range p.grants[role] {
+				if rbacMatches(g.action, actionAtom) && rbacMatches(g.resource, resourceAtom) {
+					return cont(env)
+				}
+			}
+			roles = append(roles, p.parents[role]...)
+		}
+
+		return engine.Bool(false)
+	})
+}
+
+// rbacGrant is the permission to perform an action on a resource.
+type rbacGrant struct {
+	action   engine.Atom
+	resource engine.Atom
+}
+
+// rbacPolicy is a role based access control policy.
+type rbacPolicy struct {
+	assignments map[engine.Atom][]engine.Atom
+	grants      map[engine.Atom][]rbacGrant
+	parents     map[engine.Atom][]engine.Atom
+}
+
+// termToRBACPolicy converts the given rbac(Assignments, Grants, Inheritance) term into a policy.
+func termToRBACPolicy(policy engine.Term, env *engine.Env) (*rbacPolicy, error) {
+	c, ok := env.Resolve(policy).(engine.Compound)
+	if !ok || c.Functor() != AtomRBAC || c.Arity() != 3 {
+		return nil, fmt.Errorf("invalid policy type: %T, should be rbac(Assignments, Grants, Inheritance)", env.Resolve(policy))
+	}
+
+	assignments, err := termToAtomPairs(c.Arg(0), env)
+	if err != nil {
+		return nil, fmt.Errorf("invalid assignments: %w", err)
+	}
+	parents, err := termToAtomPairs(c.Arg(2), env)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inheritance: %w", err)
+	}
+
+	grants := make(map[engine.Atom][]rbacGrant)
+	iter := engine.ListIterator{List: c.Arg(1), Env: env}
+	for iter.Next() {
+		g, ok := env.Resolve(iter.Current()).(engine.Compound)
+		if !ok || g.Functor() != AtomGrant || g.Arity() != 3 {
+			return nil, fmt.Errorf("invalid grants: invalid grant type: %T, should be grant(Role, Action, Resource)",
+				env.Resolve(iter.Current()))
+		}
+		args := make([]engine.Atom, 0, 3)
+		for i := 0; i < g.Arity(); i++ {
+			a, ok := env.Resolve(g.Arg(i)).(engine.Atom)
+			if !ok {
+				return nil, fmt.Errorf("invalid grants: invalid grant argument type: %T, should be Atom", env.Resolve(g.Arg(i)))
+			}
+			args = append(args, a)
+		}
+		grants[args[0]] = append(grants[args[0]], rbacGrant{action: args[1], resource: args[2]})
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("invalid grants: %w", err)
+	}
+
+	return &rbacPolicy{assignments: assignments, grants: grants, parents: parents}, nil
+}
+
+// termToAtomPairs converts the given list of Key-Value pairs of atoms into a map of the values indexed by key.
+func termToAtomPairs(pairs engine.Term, env *engine.Env) (map[engine.Atom][]engine.Atom, error) {
+	result := make(map[engine.Atom][]engine.Atom)
+
+	iter := engine.ListIterator{List: pairs, Env: env}
+	for iter.Next() {
+		pair, ok := env.Resolve(iter.Current()).(engine.Compound)
+		if !ok || pair.Functor() != AtomPair || pair.Arity() != 2 {
+			return nil, fmt.Errorf("invalid pair type: %T, should be Key-Value", env.Resolve(iter.Current()))
+		}
+		key, ok := env.Resolve(pair.Arg(0)).(engine.Atom)
+		if !ok {
+			return nil, fmt.Errorf("invalid pair key type: %T, should be Atom", env.Resolve(pair.Arg(0)))
+		}
+		value, ok := env.Resolve(pair.Arg(1)).(engine.Atom)
+		if !ok {
+			return nil, fmt.Errorf("invalid pair value type: %T, should be Atom", env.Resolve(pair.Arg(1)))
+		}
+		result[key] = append(result[key], value)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// rbacMatches checks whether the given pattern of a grant matches the given value.
+func rbacMatches(pattern, value engine.Atom) bool {
+	p := pattern.String()
+	if prefix, ok := strings.CutSuffix(p, rbacWildcard); ok {
+		return strings.HasPrefix(value.String(), prefix)
+	}
+
+	return p == value.String()
+}
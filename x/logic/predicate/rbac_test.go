@@ -0,0 +1,159 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestRBACAllowed(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		program := `policy(rbac(
+  [alice-admin, bob-editor, carol-viewer],
+  [grant(viewer, read, 'doc/*'), grant(editor, write, 'doc/*'), grant(admin, '*', '*')],
+  [editor-viewer, admin-editor]
+)).
+allowed(Subject, Action, Resource) :- policy(P), rbac_allowed(Subject, Action, Resource, P).`
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `rbac_allowed(alice, read, report, rbac([alice-viewer], [grant(viewer, read, report)], [])).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `rbac_allowed(alice, write, report, rbac([alice-viewer], [grant(viewer, read, report)], [])).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `rbac_allowed(bob, read, report, rbac([alice-viewer], [grant(viewer, read, report)], [])).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `allowed(bob, read, 'doc/42').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `allowed(carol, write, 'doc/42').`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `allowed(bob, read, 'img/42').`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `allowed(alice, delete, 'img/42').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `rbac_allowed(alice, read, report, rbac([alice-a, alice-b], [grant(c, read, report)], [a-b, b-a, b-c])).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `rbac_allowed(alice, write, report, rbac([alice-a], [grant(c, read, report)], [a-b, b-c, c-a])).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `rbac_allowed(alice, read, report, [alice-viewer]).`,
+				wantError:   fmt.Errorf("rbac_allowed/4: invalid policy type: engine.list, should be rbac(Assignments, Grants, Inheritance)"),
+				wantSuccess: false,
+			},
+			{
+				query:       `rbac_allowed(alice, read, report, rbac([alice-viewer], [grant(viewer, read)], [])).`,
+				wantError:   fmt.Errorf("rbac_allowed/4: invalid grants: invalid grant type: *engine.compound, should be grant(Role, Action, Resource)"),
+				wantSuccess: false,
+			},
+			{
+				query:       `rbac_allowed(alice, read, report, rbac([alice-1], [], [])).`,
+				wantError:   fmt.Errorf("rbac_allowed/4: invalid assignments: invalid pair value type: engine.Integer, should be Atom"),
+				wantSuccess: false,
+			},
+			{
+				query:       `rbac_allowed(Who, read, report, rbac([], [], [])).`,
+				wantError:   fmt.Errorf("rbac_allowed/4: invalid subject type: engine.Variable, should be Atom"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register4(engine.NewAtom("rbac_allowed"), RBACAllowed)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+var (
+	// AtomCaseInsensitive is the term used to indicate the case_insensitive option.
+	AtomCaseInsensitive = engine.NewAtom("case_insensitive")
+
+	// AtomCapture is the term used to indicate the capture option.
+	AtomCapture = engine.NewAtom("capture")
+)
+
+// ReMatch is a predicate which checks whether a regular expression matches a string.
+//
+// The signature is as follows:
+//
+//	re_match(+Pattern, +String, +Options) is semidet
+//
+// Where:
+//   - Pattern is the regular expression, as an Atom, following the RE2 syntax (see https://github.com/google/re2/wiki/Syntax).
+//   - String is the string to match.
+//   - Options are additional configurations for the matching. Supported options include: case_insensitive(+Bool) which
+//     specifies whether the matching ignores the case (false by default).
+//
+// The predicate succeeds if and only if Pattern matches any part of String: anchors (^ and $) must be used to match the
+// whole String. Strings are given as any atomic term (Atom or number) or as a list of characters or character codes.
+//
+// The regular expressions are evaluated by the Go regexp engine, whose running time is linear in the size of the input,
+// which makes them safe against catastrophic backtracking.
+//
+// Examples:
+//
+//	# Check that a string is made of lower case hexadecimal digits.
+//	- re_match('^[0-9a-f]+$', 'deadbeef', []).
+//
+//	# Check that a string contains a word, ignoring the case.
+//	- re_match('hello', 'Hello World', [case_insensitive(true)]).
+func ReMatch(_ *engine.VM, pattern, str, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		re, err := termToRegexp(pattern, options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("re_match/3: %w", err))
+		}
+		text, err := termToText(str, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("re_match/3: %w", err))
+		}
+
+		if !re.MatchString(text) {
+			return engine.Bool(false)
+		}
+
+		return cont(env)
+	})
+}
+
+// ReMatchSub is a predicate which matches a regular expression against a string and unifies the matched part of the
+// string, as well as the capture groups of the regular expression.
+//
+// The signature is as follows:
+//
+//	re_matchsub(+Pattern, +String, -Match, +Options) is semidet
+//
+// Where:
+//   - Pattern is the regular expression, as an Atom, following the RE2 syntax (see https://github.com/google/re2/wiki/Syntax).
+//   - String is the string to match.
+//   - Match is the leftmost part of String matched by Pattern, as an Atom.
+//   - Options are additional configurations for the matching. Supported options include: case_insensitive(+Bool) which
+//     specifies whether the matching ignores the case (false by default), and capture(-Groups) whose Groups is unified
+//     with the list of the parts of String matched by the capture groups of Pattern, in order, as Atoms. A group which
+//     does not participate to the match is represented by the empty Atom.
+//
+// The predicate fails if Pattern does not match String. See re_match/3 for the details about the matching.
+//
+// Examples:
+//
+//	# Extract the components of a date.
+//	- re_matchsub('(\\d{4})-(\\d{2})-(\\d{2})', 'on 2023-06-01', Match, [capture(Groups)]).
+func ReMatchSub(vm *engine.VM, pattern, str, match, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		re, err := termToRegexp(pattern, options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("re_matchsub/4: %w", err))
+		}
+		text, err := termToText(str, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("re_matchsub/4: %w", err))
+		}
+		capture, err := util.GetOption(AtomCapture, options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("re_matchsub/4: %w", err))
+		}
+
+		matches := re.FindStringSubmatch(text)
+		if matches == nil {
+			return engine.Bool(false)
+		}
+
+		if capture == nil {
+			return engine.Unify(vm, match, util.StringToTerm(matches[0]), cont, env)
+		}
+
+		groups := make([]engine.Term, 0, len(matches)-1)
+		for _, group := range matches[1:] {
+			groups = append(groups, util.StringToTerm(group))
+		}
+
+		return engine.Unify(vm, Tuple(match, capture), Tuple(util.StringToTerm(matches[0]), engine.List(groups...)), cont, env)
+	})
+}
+
+// ReReplace is a predicate which replaces the parts of a string matched by a regular expression.
+//
+// The signature is as follows:
+//
+//	re_replace(+Pattern, +With, +String, -NewString) is det
+//
+// Where:
+//   - Pattern is the regular expression, as an Atom, following the RE2 syntax (see https://github.com/google/re2/wiki/Syntax).
+//   - With is the replacement string, where $N (or ${N}) denotes the part matched by the Nth capture group, ${Name} the
+//     part matched by the capture group named Name, and $$ a literal $.
+//   - String is the string in which the replacements are made.
+//   - NewString is the resulting string, as an Atom.
+//
+// All the non-overlapping matches of Pattern in String are replaced. See re_match/3 for the details about the
+// matching.
+//
+// Examples:
+//
+//	# Mask all the digits of a string.
+//	- re_replace('[0-9]', '#', 'card 1234', NewString).
+//
+//	# Swap two words.
+//	- re_replace('(\\w+) (\\w+)', '$2 $1', 'hello world', NewString).
+func ReReplace(vm *engine.VM, pattern, with, str, newStr engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		re, err := termToRegexp(pattern, engine.List(), env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("re_replace/4: %w", err))
+		}
+		replacement, err := termToText(with, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("re_replace/4: %w", err))
+		}
+		text, err := termToText(str, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("re_replace/4: %w", err))
+		}
+
+		return engine.Unify(vm, newStr, util.StringToTerm(re.ReplaceAllString(text, replacement)), cont, env)
+	})
+}
+
+// termToRegexp compiles the given pattern into a regular expression, according to the given options.
+func termToRegexp(pattern, options engine.Term, env *engine.Env) (*regexp.Regexp, error) {
+	patternAtom, err := util.ResolveToAtom(env, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	caseInsensitiveTerm, err := util.GetOptionWithDefault(AtomCaseInsensitive, options, AtomFalse, env)
+	if err != nil {
+		return nil, err
+	}
+	caseInsensitive, err := util.ResolveToAtom(env, caseInsensitiveTerm)
+	if err != nil {
+		return nil, err
+	}
+	if caseInsensitive != AtomTrue && caseInsensitive != AtomFalse {
+		return nil, fmt.Errorf("invalid case_insensitive option: %s, valid values are 'true' or 'false'", caseInsensitive)
+	}
+
+	expr := patternAtom.String()
+	if caseInsensitive == AtomTrue {
+		expr = "(?i)" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return re, nil
+}
@@ -0,0 +1,168 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestRegex(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `re_match('^[0-9a-f]+$', deadbeef, []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `re_match('^[0-9a-f]+$', 'DEADBEEF', []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `re_match('^[0-9a-f]+$', 'DEADBEEF', [case_insensitive(true)]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `re_match('world', "hello world", [case_insensitive(false)]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `re_match('^\\d+$', 1234, []).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `re_match('^(a+)+$', 'aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaab', []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `re_match('(', foo, []).`,
+				wantError:   fmt.Errorf("re_match/3: invalid pattern: error parsing regexp: missing closing ): `(`"),
+				wantSuccess: false,
+			},
+			{
+				query:       `re_match(foo, foo, [case_insensitive(maybe)]).`,
+				wantError:   fmt.Errorf("re_match/3: invalid case_insensitive option: maybe, valid values are 'true' or 'false'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `re_matchsub('(\\d{4})-(\\d{2})-(\\d{2})', 'on 2023-06-01', Match, [capture(Groups)]).`,
+				wantResult:  []types.TermResults{{"Match": "'2023-06-01'", "Groups": "['2023','06','01']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `re_matchsub('(?P<user>\\w+)@(?P<domain>\\w+)', 'mail: Alice@Example', Match, [case_insensitive(true)]).`,
+				wantResult:  []types.TermResults{{"Match": "'Alice@Example'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `re_matchsub('a(x)?b', ab, Match, [capture(Groups)]).`,
+				wantResult:  []types.TermResults{{"Match": "ab", "Groups": "['']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `re_matchsub('\\d+', abc, Match, [capture(Groups)]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `re_replace('[0-9]', '#', 'card 1234', NewString).`,
+				wantResult:  []types.TermResults{{"NewString": "'card ####'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `re_replace('(\\w+) (\\w+)', '$2 $1', 'hello world', NewString).`,
+				wantResult:  []types.TermResults{{"NewString": "'world hello'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `re_replace('(?P<key>\\w+)=(?P<value>\\w+)', '${value}:${key}', 'a=1, b=2', NewString).`,
+				wantResult:  []types.TermResults{{"NewString": "'1:a, 2:b'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `re_replace(x, y, abc, NewString).`,
+				wantResult:  []types.TermResults{{"NewString": "abc"}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("re_match"), ReMatch)
+						interpreter.Register4(engine.NewAtom("re_matchsub"), ReMatchSub)
+						interpreter.Register4(engine.NewAtom("re_replace"), ReReplace)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
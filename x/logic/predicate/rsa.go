@@ -0,0 +1,237 @@
+package predicate
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+var (
+	// AtomDer is the term used to indicate the DER key format option.
+	AtomDer = engine.NewAtom("der")
+
+	// AtomPem is the term used to indicate the PEM key format option.
+	AtomPem = engine.NewAtom("pem")
+
+	// AtomPKCS1v15 is the term used to indicate the PKCS #1 v1.5 signature scheme option.
+	AtomPKCS1v15 = engine.NewAtom("pkcs1v15")
+
+	// AtomPSS is the term used to indicate the PSS signature scheme option.
+	AtomPSS = engine.NewAtom("pss")
+)
+
+// rsaVerifyOptions are the options supported by rsa_verify/4.
+var rsaVerifyOptions = []engine.Atom{
+	AtomEncoding, engine.NewAtom("key_format"), engine.NewAtom("hash"), engine.NewAtom("scheme"),
+}
+
+// rsaHashes maps the hash algorithms supported by rsa_verify/4 to their implementation, ordered by digest size.
+var rsaHashes = []crypto.Hash{crypto.SHA256, crypto.SHA384, crypto.SHA512}
+
+// rsaHashNames maps the names of the hash algorithms supported by rsa_verify/4 to their implementation.
+var rsaHashNames = map[string]crypto.Hash{
+	"sha256": crypto.SHA256,
+	"sha384": crypto.SHA384,
+	"sha512": crypto.SHA512,
+}
+
+// RSAVerify determines if a given signature is valid as per the RSA algorithm for the provided data, using the
+// specified public key.
+//
+// The signature is as follows:
+//
+//	rsa_verify(+PubKey, +Data, +Signature, +Options) is semi-det
+//
+// Where:
+//   - PubKey is the RSA public key, either DER encoded as a list of bytes, or PEM encoded as an Atom, according to
+//     the key_format option.
+//   - Data is the data to verify, which can be either an atom or a list of bytes according to the encoding option.
+//     It's the hash of the signed message, unless the hash option is given, in which case it's the message itself.
+//   - Signature is the signature corresponding to the Data, as a list of bytes.
+//   - Options are additional configurations for the verification process. Supported options include:
+//     encoding(+Format) which specifies the encoding used for the Data, as for ecdsa_verify/4, key_format(+KeyFormat)
+//     which specifies the encoding of PubKey, hash(+Alg) which specifies the hash algorithm to apply to Data before
+//     the verification, and scheme(+Scheme) which specifies the padding scheme of the signature (see below for
+//     details).
+//
+// For KeyFormat, the supported formats are:
+//
+//   - der (default): the DER encoding of either a PKIX (i.e. SubjectPublicKeyInfo) or a PKCS #1 public key.
+//   - pem: the PEM encoding of either a PUBLIC KEY, a RSA PUBLIC KEY or a CERTIFICATE block, in which case the public
+//     key of the X.509 certificate is used.
+//
+// For Alg, the supported algorithms are sha256, sha384 and sha512. Without the hash option, Data is considered as
+// already hashed, the hash algorithm being deduced from its length.
+//
+// For Scheme, the supported schemes are:
+//
+//   - pkcs1v15 (default): the RSASSA-PKCS1-v1_5 scheme, as used by the RS256, RS384 and RS512 JWT algorithms.
+//   - pss: the RSASSA-PSS scheme, with a salt of any length, as used by the PS256, PS384 and PS512 JWT algorithms.
+//
+// As for the other signature verification predicates, the predicate fails if the signature is not valid, whereas a
+// malformed input raises an error, and an unknown option raises a domain_error(option, Option) error.
+//
+// Examples:
+//
+//	# Verify a RS256 signature of a message.
+//	- rsa_verify('-----BEGIN PUBLIC KEY-----\n...', [104, 101, 108, 108, 111], [23, 56, ...],
+//	  [encoding(octet), key_format(pem), hash(sha256)]).
+func RSAVerify(_ *engine.VM, key, data, sig, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "rsa_verify/4"
+
+		if err := util.CheckOptions(options, rsaVerifyOptions, env); err != nil {
+			return engine.Error(err)
+		}
+		pubKey, err := termToRSAPublicKey(ctx, key, options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		decodedData, err := TermToBytes(ctx, data, options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: failed to decode data: %w", functor, err))
+		}
+		decodedSignature, err := TermToBytes(ctx, sig, AtomEncoding.Apply(AtomOctet), env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: failed to decode signature: %w", functor, err))
+		}
+		h, digest, err := rsaDigest(decodedData, options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		scheme, err := util.GetOptionWithDefault(engine.NewAtom("scheme"), options, AtomPKCS1v15, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		if err := consumeAlgorithmGas(ctx, functor, "rsa", len(decodedData)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		switch env.Resolve(scheme) {
+		case AtomPKCS1v15:
+			err = rsa.VerifyPKCS1v15(pubKey, h, digest, decodedSignature)
+		case AtomPSS:
+			err = rsa.VerifyPSS(pubKey, h, digest, decodedSignature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+		default:
+			return engine.Error(fmt.Errorf("%s: invalid scheme option: %v, valid values are '%s' or '%s'",
+				functor, env.Resolve(scheme), AtomPKCS1v15, AtomPSS))
+		}
+		if err != nil {
+			return engine.Bool(false)
+		}
+
+		return cont(env)
+	})
+}
+
+// termToRSAPublicKey decodes the given RSA public key according to the key_format option.
+func termToRSAPublicKey(ctx context.Context, key, options engine.Term, env *engine.Env) (*rsa.PublicKey, error) {
+	format, err := util.GetOptionWithDefault(engine.NewAtom("key_format"), options, AtomDer, env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch env.Resolve(format) {
+	case AtomDer:
+		der, err := TermToBytes(ctx, key, AtomEncoding.Apply(AtomOctet), env)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode public key: %w", err)
+		}
+		return parseRSAPublicKey(der)
+	case AtomPem:
+		text, ok := env.Resolve(key).(engine.Atom)
+		if !ok {
+			return nil, fmt.Errorf("invalid public key type: %T, should be Atom", env.Resolve(key))
+		}
+		if err := checkInputSize(ctx, len(text.String())); err != nil {
+			return nil, fmt.Errorf("failed to decode public key: %w", err)
+		}
+		block, _ := pem.Decode([]byte(strings.TrimSpace(text.String())))
+		if block == nil {
+			return nil, fmt.Errorf("failed to decode public key: no PEM block found")
+		}
+		return parseRSAPEMBlock(block)
+	default:
+		return nil, fmt.Errorf("invalid key_format option: %v, valid values are '%s' or '%s'", env.Resolve(format), AtomDer, AtomPem)
+	}
+}
+
+// parseRSAPEMBlock decodes the RSA public key held by the given PEM block, according to its type.
+func parseRSAPEMBlock(block *pem.Block) (*rsa.PublicKey, error) {
+	switch block.Type {
+	case "PUBLIC KEY", "RSA PUBLIC KEY":
+		return parseRSAPublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode certificate: %w", err)
+		}
+		pubKey, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("invalid certificate public key type: %T, should be a RSA public key", cert.PublicKey)
+		}
+		return pubKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type: %s", block.Type)
+	}
+}
+
+// parseRSAPublicKey decodes the given DER encoded RSA public key, either in the PKIX or in the PKCS #1 form.
+func parseRSAPublicKey(der []byte) (*rsa.PublicKey, error) {
+	if pubKey, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return pubKey, nil
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key: %w", err)
+	}
+	pubKey, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid public key type: %T, should be a RSA public key", parsed)
+	}
+	return pubKey, nil
+}
+
+// rsaDigest returns the hash algorithm and the digest of the given data according to the hash option: the data is
+// hashed with the given algorithm if any, or considered as a digest whose algorithm is deduced from its length
+// otherwise.
+func rsaDigest(data []byte, options engine.Term, env *engine.Env) (crypto.Hash, []byte, error) {
+	alg, err := util.GetOption(engine.NewAtom("hash"), options, env)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if alg == nil {
+		idx := slices.IndexFunc(rsaHashes, func(h crypto.Hash) bool { return h.Size() == len(data) })
+		if idx == -1 {
+			return 0, nil, fmt.Errorf("invalid digest length: %d, should be the length of a sha256, sha384 or sha512 digest",
+				len(data))
+		}
+		return rsaHashes[idx], data, nil
+	}
+
+	var h crypto.Hash
+	name, ok := env.Resolve(alg).(engine.Atom)
+	if ok {
+		h, ok = rsaHashNames[name.String()]
+	}
+	if !ok {
+		return 0, nil, fmt.Errorf("invalid hash option: %v, valid values are 'sha256', 'sha384' or 'sha512'", env.Resolve(alg))
+	}
+	hasher := h.New()
+	hasher.Write(data)
+	return h, hasher.Sum(nil), nil
+}
@@ -0,0 +1,263 @@
+package predicate
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ichiban/prolog/engine"
+)
+
+var (
+	// AtomOrder is the term used to indicate the order domain in a domain error.
+	AtomOrder = engine.NewAtom("order")
+
+	// AtomCompound is the term used to indicate the compound type in a type error.
+	AtomCompound = engine.NewAtom("compound")
+
+	// AtomNotLessThanZero is the term used to indicate the non-negative domain in a domain error.
+	AtomNotLessThanZero = engine.NewAtom("not_less_than_zero")
+
+	// AtomLess is the term <, the order of a term preceding another one.
+	AtomLess = engine.NewAtom("<")
+
+	// AtomGreater is the term >, the order of a term following another one.
+	AtomGreater = engine.NewAtom(">")
+
+	// AtomEqual is the term =, the order of a term equal to another one.
+	AtomEqual = engine.NewAtom("=")
+)
+
+// sortOrders are the orders supported by sort/4, associated to whether they are descending and keep the duplicates.
+var sortOrders = map[engine.Atom]struct{ descending, duplicates bool }{
+	engine.NewAtom("@<"):  {descending: false, duplicates: false},
+	engine.NewAtom("@=<"): {descending: false, duplicates: true},
+	engine.NewAtom("@>"):  {descending: true, duplicates: false},
+	engine.NewAtom("@>="): {descending: true, duplicates: true},
+}
+
+// MSort is a predicate which sorts a list according to the standard order of terms, keeping the duplicates.
+//
+// The signature is as follows:
+//
+//	msort(+List, -Sorted) is det
+//
+// Where:
+//   - List is the list to sort.
+//   - Sorted is the list of the elements of List in the standard order of terms.
+//
+// Unlike sort/2, the duplicates are not removed. The sort is stable, so that the result only depends on List, e.g. to
+// hash a list of coins whatever the order in which they have been collected. A partial List raises an
+// instantiation_error, and a List which is not a list a type_error(list, List).
+//
+// Examples:
+//
+//	# Sort a list keeping its duplicates.
+//	- msort([b, a, c, a], Sorted).
+func MSort(vm *engine.VM, list, sorted engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		elems, err := listElements(list, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		sort.SliceStable(elems, func(i, j int) bool {
+			return elems[i].Compare(elems[j], env) < 0
+		})
+
+		return engine.Unify(vm, sorted, engine.List(elems...), cont, env)
+	})
+}
+
+// Sort4 is a predicate which sorts a list on a key of its elements, in the given order.
+//
+// The signature is as follows:
+//
+//	sort(+Key, +Order, +List, -Sorted) is det
+//
+// Where:
+//   - Key is the argument of the elements to sort on, as a positive Integer, or 0 to sort on the whole elements.
+//   - Order is the order of the sort, as one of the Atoms @< (ascending, removing the duplicates), @=< (ascending,
+//     keeping the duplicates), @> (descending, removing the duplicates) or @>= (descending, keeping the duplicates).
+//   - List is the list to sort.
+//   - Sorted is the list of the elements of List sorted on their Key.
+//
+// The keys are compared according to the standard order of terms. The sort is stable: the elements with equal keys
+// keep their relative order, the first of them being kept when removing the duplicates, i.e. the elements whose key
+// equals the one of a kept element.
+//
+// An invalid Key raises a type_error(integer, Key) or a domain_error(not_less_than_zero, Key), an invalid Order a
+// domain_error(order, Order), an element which is not a compound, with a positive Key, a type_error(compound, Element),
+// and an element with less than Key arguments an existence_error(key, Key, Element).
+//
+// Examples:
+//
+//	# Sort pairs on their value, in descending order, keeping the duplicates.
+//	- sort(2, @>=, [a-1, b-3, c-1], Sorted).
+func Sort4(vm *engine.VM, key, order, list, sorted engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		k, ok := env.Resolve(key).(engine.Integer)
+		if !ok {
+			return engine.Error(typeError(AtomInteger, key, env))
+		}
+		if k < 0 {
+			return engine.Error(domainError(AtomNotLessThanZero, key, env))
+		}
+		o, ok := env.Resolve(order).(engine.Atom)
+		opts, found := sortOrders[o]
+		if !ok || !found {
+			return engine.Error(domainError(AtomOrder, order, env))
+		}
+		elems, err := listElements(list, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		keys := make([]engine.Term, len(elems))
+		for i, elem := range elems {
+			if keys[i], err = sortKey(elem, k, env); err != nil {
+				return engine.Error(err)
+			}
+		}
+
+		indexes := make([]int, len(elems))
+		for i := range indexes {
+			indexes[i] = i
+		}
+		sort.SliceStable(indexes, func(i, j int) bool {
+			c := keys[indexes[i]].Compare(keys[indexes[j]], env)
+			if opts.descending {
+				return c > 0
+			}
+			return c < 0
+		})
+
+		result := make([]engine.Term, 0, len(elems))
+		for n, i := range indexes {
+			if !opts.duplicates && n > 0 && keys[i].Compare(keys[indexes[n-1]], env) == 0 {
+				continue
+			}
+			result = append(result, elems[i])
+		}
+
+		return engine.Unify(vm, sorted, engine.List(result...), cont, env)
+	})
+}
+
+// PredSort is a predicate which sorts a list using a custom comparator, removing the elements it deems equal.
+//
+// The signature is as follows:
+//
+//	predsort(:Pred, +List, -Sorted) is semidet
+//
+// Where:
+//   - Pred is the comparator, called as call(Pred, Order, A, B) to unify Order with <, > or = depending on whether the
+//     element A precedes, follows or equals the element B.
+//   - List is the list to sort.
+//   - Sorted is the list of the elements of List sorted according to Pred, an element being removed if Pred deems it
+//     equal to another one.
+//
+// The list is sorted by a merge sort, Pred being called once for each comparison, only its first solution being
+// considered. The predicate fails if Pred fails, and an Order other than <, > or = raises a domain_error(order, Order).
+// The errors raised by Pred are propagated.
+//
+// Examples:
+//
+//	# Sort pairs on their value, removing the pairs of equal value, given by_value(O, _-A, _-B) :- compare(O, A, B).
+//	- predsort(by_value, [a-2, b-1, c-2], Sorted).
+func PredSort(vm *engine.VM, pred, list, sorted engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		elems, err := listElements(list, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+
+		compare := func(a, b engine.Term) (engine.Atom, bool, error) {
+			o := engine.NewVariable()
+			var order engine.Term
+			ok, err := engine.Call3(vm, pred, o, a, b, func(solution *engine.Env) *engine.Promise {
+				order = solution.Resolve(o)
+				return engine.Bool(true)
+			}, env).Force(ctx)
+			if err != nil || !ok {
+				return 0, false, err
+			}
+			if atom, ok := order.(engine.Atom); ok && (atom == AtomLess || atom == AtomGreater || atom == AtomEqual) {
+				return atom, true, nil
+			}
+			return 0, false, domainError(AtomOrder, order, env)
+		}
+
+		result, ok, err := predMergeSort(elems, compare)
+		if err != nil {
+			return engine.Error(err)
+		}
+		if !ok {
+			return engine.Bool(false)
+		}
+
+		return engine.Unify(vm, sorted, engine.List(result...), cont, env)
+	})
+}
+
+// predMergeSort sorts the given elements using the given comparator, removing the second of two elements it deems
+// equal, and reports false if the comparator fails.
+func predMergeSort(
+	elems []engine.Term, compare func(a, b engine.Term) (engine.Atom, bool, error),
+) ([]engine.Term, bool, error) {
+	if len(elems) < 2 {
+		return elems, true, nil
+	}
+
+	left, ok, err := predMergeSort(elems[:len(elems)/2], compare)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	right, ok, err := predMergeSort(elems[len(elems)/2:], compare)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	merged := make([]engine.Term, 0, len(left)+len(right))
+	for len(left) > 0 && len(right) > 0 {
+		order, ok, err := compare(left[0], right[0])
+		if err != nil || !ok {
+			return nil, ok, err
+		}
+		switch order {
+		case AtomLess:
+			merged, left = append(merged, left[0]), left[1:]
+		case AtomGreater:
+			merged, right = append(merged, right[0]), right[1:]
+		default:
+			right = right[1:]
+		}
+	}
+
+	return append(append(merged, left...), right...), true, nil
+}
+
+// listElements returns the resolved elements of the given list, raising an instantiation_error for a partial list and
+// a type_error(list, List) for a term which is not a list.
+func listElements(list engine.Term, env *engine.Env) ([]engine.Term, error) {
+	elems := make([]engine.Term, 0)
+	iter := engine.ListIterator{List: list, Env: env}
+	for iter.Next() {
+		elems = append(elems, env.Resolve(iter.Current()))
+	}
+	return elems, iter.Err()
+}
+
+// sortKey returns the key of the given element to sort on, i.e. its Nth argument, or the element itself for 0.
+func sortKey(elem engine.Term, n engine.Integer, env *engine.Env) (engine.Term, error) {
+	if n == 0 {
+		return elem, nil
+	}
+	c, ok := elem.(engine.Compound)
+	if !ok {
+		return nil, typeError(AtomCompound, elem, env)
+	}
+	if c.Arity() < int(n) {
+		return nil, isoError(engine.NewAtom("existence_error").Apply(engine.NewAtom("key"), n, elem), env)
+	}
+	return c.Arg(int(n) - 1), nil
+}
@@ -0,0 +1,211 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestSort(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `msort([b, a, c, a], Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[a,a,b,c]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `msort([f(2), 1, "b", a, 2.0, f(1, 1), X], Sorted).`,
+				wantResult:  []types.TermResults{{"X": "_1", "Sorted": "[_1,2.0,1,a,f(2),[b],f(1,1)]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `msort([], Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(msort([a|_], _), error(instantiation_error, _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(msort(foo, _), error(type_error(list, foo), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sort(0, @<, [b, a, c, a], Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[a,b,c]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sort(0, @>=, [b, a, c, a], Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[c,b,a,a]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sort(2, @>=, [a-1, b-3, c-1], Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[b-3,a-1,c-1]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sort(2, @=<, [a-1, b-3, c-1], Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[a-1,c-1,b-3]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sort(2, @<, [a-1, b-3, c-1], Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[a-1,b-3]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sort(1, @>, [coin(uknow, 2), coin(uatom, 1), coin(uknow, 3)], Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[coin(uknow,2),coin(uatom,1)]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(sort(-1, @<, [], _), error(domain_error(not_less_than_zero, -1), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(sort(a, @<, [], _), error(type_error(integer, a), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(sort(0, <, [], _), error(domain_error(order, <), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(sort(1, @<, [a], _), error(type_error(compound, a), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(sort(2, @<, [f(a)], _), error(existence_error(key, 2, f(a)), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     `by_value(O, _-A, _-B) :- compare(O, A, B).`,
+				query:       `predsort(by_value, [a-2, b-1, c-2, d-0], Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[d-0,b-1,a-2]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `by_value(O, _-A, _-B) :- compare(O, A, B).`,
+				query:       `predsort(by_value, [], Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `desc(O, A, B) :- compare(O, B, A).`,
+				query:       `predsort(desc, [1, 3, 2, 3], Sorted).`,
+				wantResult:  []types.TermResults{{"Sorted": "[3,2,1]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `never(_, _, _) :- fail.`,
+				query:       `predsort(never, [1, 2], Sorted).`,
+				wantSuccess: false,
+			},
+			{
+				program:     `invalid(foo, _, _).`,
+				query:       `catch(predsort(invalid, [1, 2], _), error(domain_error(order, foo), _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				program:     `invalid(_, _, _).`,
+				query:       `catch(predsort(invalid, [1, 2], _), error(instantiation_error, _), true).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("msort"), MSort)
+						interpreter.Register4(engine.NewAtom("sort"), Sort4)
+						interpreter.Register3(engine.NewAtom("predsort"), PredSort)
+						interpreter.Register3(engine.NewAtom("compare"), engine.Compare)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+						interpreter.Register0(engine.NewAtom("fail"), func(_ *engine.VM, _ engine.Cont, _ *engine.Env) *engine.Promise { return engine.Bool(false) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
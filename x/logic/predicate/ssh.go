@@ -0,0 +1,336 @@
+package predicate
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+// sshKeyAlgorithms maps the supported OpenSSH public key types to their algorithm.
+var sshKeyAlgorithms = map[string]util.Alg{
+	"ssh-ed25519": util.Ed25519,
+}
+
+const (
+	// sshSigMagic is the magic preamble of the SSHSIG signatures.
+	sshSigMagic = "SSHSIG"
+	// sshSigVersion is the only supported version of the SSHSIG signatures.
+	sshSigVersion = 1
+	// sshSigArmorBegin is the first line of an armored SSHSIG signature.
+	sshSigArmorBegin = "-----BEGIN SSH SIGNATURE-----"
+	// sshSigArmorEnd is the last line of an armored SSHSIG signature.
+	sshSigArmorEnd = "-----END SSH SIGNATURE-----"
+)
+
+// sshSigHashes maps the hash algorithms supported by the SSHSIG signatures to their implementation.
+var sshSigHashes = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// sshSig is a decoded SSHSIG signature.
+type sshSig struct {
+	publicKey     []byte
+	namespace     string
+	reserved      []byte
+	hashAlgorithm string
+	signatureType string
+	signature     []byte
+}
+
+// OpenSSHPubKey is a predicate which decodes a public key given in the OpenSSH format, as found in the authorized_keys
+// files or in the .pub files generated by ssh-keygen.
+//
+// The signature is as follows:
+//
+//	openssh_pubkey(+Text, -Algorithm, -KeyBytes) is det
+//
+// Where:
+//   - Text is the public key line, as an Atom of the form '<type> <base64 blob> [comment]'.
+//   - Algorithm is the algorithm of the key, as an Atom which can be given as the type option of the signature
+//     verification predicates (e.g. ed25519 for a ssh-ed25519 key).
+//   - KeyBytes is the raw public key, as a list of bytes.
+//
+// The blob is decoded according to the SSH wire format, and the key type it embeds must match the one of the line.
+// The only supported key type is ssh-ed25519, any other type raises an error.
+//
+// Examples:
+//
+//	# Decode an Ed25519 public key.
+//	- openssh_pubkey('ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGXRI1jrJFufI31VDjz46cmSNacigwAve5tLvHxoZ0LE alice@okp4',
+//	  Algorithm, KeyBytes).
+func OpenSSHPubKey(vm *engine.VM, text, algorithm, keyBytes engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		textAtom, ok := env.Resolve(text).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("openssh_pubkey/3: invalid text type: %T, should be Atom", env.Resolve(text)))
+		}
+
+		alg, key, err := parseSSHPubKey(textAtom.String())
+		if err != nil {
+			return engine.Error(fmt.Errorf("openssh_pubkey/3: %w", err))
+		}
+
+		return engine.Unify(vm, Tuple(algorithm, keyBytes), Tuple(engine.NewAtom(alg.String()), BytesToList(key)), cont, env)
+	})
+}
+
+// SSHSigVerify is a predicate which verifies a signature in the SSHSIG format, as produced by ssh-keygen -Y sign
+// (e.g. when signing git commits with a SSH key).
+//
+// The signature is as follows:
+//
+//	sshsig_verify(+Message, +Signature, +PubKey, +Namespace) is semidet
+//
+// Where:
+//   - Message is the signed message, either as an Atom whose text is signed or as a list of bytes.
+//   - Signature is the signature, either as an Atom holding the armored signature (i.e. enclosed in the
+//     '-----BEGIN SSH SIGNATURE-----' and '-----END SSH SIGNATURE-----' lines) or as a list of bytes holding the binary
+//     signature.
+//   - PubKey is the public key of the signer, as an Atom in the OpenSSH format (see openssh_pubkey/3).
+//   - Namespace is the namespace the signature is bound to, as an Atom (e.g. git or file).
+//
+// The predicate succeeds if and only if the signature has been produced by the key PubKey over Message, the data
+// being signed with the namespace and the hash algorithm declared by the signature. It fails if the signature embeds
+// another public key than PubKey. The predicate raises an error if the signature is malformed, uses an unsupported
+// algorithm, or is bound to another namespace than Namespace. The only supported key type is ssh-ed25519, and the
+// supported hash algorithms are sha256 and sha512.
+//
+// Examples:
+//
+//	# Verify the signature of a file.
+//	- sshsig_verify('hello okp4', '-----BEGIN SSH SIGNATURE-----\n...\n-----END SSH SIGNATURE-----',
+//	  'ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGXRI1jrJFufI31VDjz46cmSNacigwAve5tLvHxoZ0LE alice@okp4', file).
+func SSHSigVerify(_ *engine.VM, message, signature, pubKey, namespace engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "sshsig_verify/4"
+
+		msg, err := termToSSHSigInput(ctx, message, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: invalid message: %w", functor, err))
+		}
+		sig, err := termToSSHSig(ctx, signature, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		pubKeyAtom, ok := env.Resolve(pubKey).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("%s: invalid public key type: %T, should be Atom", functor, env.Resolve(pubKey)))
+		}
+		alg, key, err := parseSSHPubKey(pubKeyAtom.String())
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		namespaceAtom, ok := env.Resolve(namespace).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("%s: invalid namespace type: %T, should be Atom", functor, env.Resolve(namespace)))
+		}
+
+		if sig.namespace != namespaceAtom.String() {
+			return engine.Error(fmt.Errorf("%s: namespace mismatch, %s expected but %s signed", functor, namespaceAtom, sig.namespace))
+		}
+		newHash, ok := sshSigHashes[sig.hashAlgorithm]
+		if !ok {
+			return engine.Error(fmt.Errorf("%s: unsupported hash algorithm: %s", functor, sig.hashAlgorithm))
+		}
+		if _, ok := sshKeyAlgorithms[sig.signatureType]; !ok {
+			return engine.Error(fmt.Errorf("%s: unsupported signature type: %s", functor, sig.signatureType))
+		}
+		keyType, _, _ := readSSHString(sig.publicKey)
+		if signerAlg, ok := sshKeyAlgorithms[string(keyType)]; !ok || signerAlg != alg {
+			return engine.Bool(false)
+		}
+		_, signerKey, err := parseSSHPubKeyBlob(string(keyType), alg, sig.publicKey)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		if !util.ConstantTimeEqual(signerKey, key) {
+			return engine.Bool(false)
+		}
+
+		h := newHash()
+		h.Write(msg)
+		signed := sshSigSignedData(sig, h.Sum(nil))
+		if err := consumeAlgorithmGas(ctx, functor, alg.String(), len(signed)); err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		if !ed25519.Verify(key, signed, sig.signature) {
+			return engine.Bool(false)
+		}
+
+		return cont(env)
+	})
+}
+
+// termToSSHSigInput converts the given term, either an Atom or a list of bytes, into bytes.
+func termToSSHSigInput(ctx context.Context, term engine.Term, env *engine.Env) ([]byte, error) {
+	switch t := env.Resolve(term).(type) {
+	case engine.Atom:
+		if t == util.AtomEmptyList {
+			return []byte{}, nil
+		}
+		if err := checkInputSize(ctx, len(t.String())); err != nil {
+			return nil, err
+		}
+		return []byte(t.String()), nil
+	case engine.Compound:
+		if util.IsList(t) {
+			return ListToBytes(ctx, engine.ListIterator{List: t, Env: env}, env)
+		}
+	}
+
+	return nil, fmt.Errorf("invalid term type: %T, should be Atom or List", env.Resolve(term))
+}
+
+// termToSSHSig converts the given term, either an Atom holding an armored signature or a list of bytes holding a
+// binary signature, into a decoded SSHSIG signature.
+func termToSSHSig(ctx context.Context, term engine.Term, env *engine.Env) (*sshSig, error) {
+	var blob []byte
+	switch t := env.Resolve(term).(type) {
+	case engine.Atom:
+		armored := strings.TrimSpace(t.String())
+		body, hasBegin := strings.CutPrefix(armored, sshSigArmorBegin)
+		body, hasEnd := strings.CutSuffix(body, sshSigArmorEnd)
+		if !hasBegin || !hasEnd {
+			return nil, fmt.Errorf("invalid SSH signature: should be enclosed in '%s' and '%s'", sshSigArmorBegin, sshSigArmorEnd)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SSH signature: %w", err)
+		}
+		blob = decoded
+	case engine.Compound:
+		if !util.IsList(t) {
+			return nil, fmt.Errorf("invalid signature type: %T, should be Atom or List", t)
+		}
+		decoded, err := ListToBytes(ctx, engine.ListIterator{List: t, Env: env}, env)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature: %w", err)
+		}
+		blob = decoded
+	default:
+		return nil, fmt.Errorf("invalid signature type: %T, should be Atom or List", t)
+	}
+
+	return parseSSHSig(blob)
+}
+
+// parseSSHSig decodes the given binary SSHSIG signature, as specified by the PROTOCOL.sshsig document of OpenSSH.
+func parseSSHSig(blob []byte) (*sshSig, error) {
+	rest, ok := bytes.CutPrefix(blob, []byte(sshSigMagic))
+	if !ok || len(rest) < 4 {
+		return nil, fmt.Errorf("invalid SSH signature: missing %s preamble", sshSigMagic)
+	}
+	if version := binary.BigEndian.Uint32(rest); version != sshSigVersion {
+		return nil, fmt.Errorf("invalid SSH signature: unsupported version %d", version)
+	}
+	rest = rest[4:]
+
+	fields := make([][]byte, 5)
+	for i := range fields {
+		if fields[i], rest, ok = readSSHString(rest); !ok {
+			return nil, fmt.Errorf("invalid SSH signature: malformed signature data")
+		}
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("invalid SSH signature: malformed signature data")
+	}
+
+	sigType, rest, ok := readSSHString(fields[4])
+	if !ok {
+		return nil, fmt.Errorf("invalid SSH signature: malformed signature data")
+	}
+	sig, rest, ok := readSSHString(rest)
+	if !ok || len(rest) != 0 {
+		return nil, fmt.Errorf("invalid SSH signature: malformed signature data")
+	}
+
+	return &sshSig{
+		publicKey:     fields[0],
+		namespace:     string(fields[1]),
+		reserved:      fields[2],
+		hashAlgorithm: string(fields[3]),
+		signatureType: string(sigType),
+		signature:     sig,
+	}, nil
+}
+
+// sshSigSignedData returns the data actually signed for the given signature and digest of the message.
+func sshSigSignedData(sig *sshSig, digest []byte) []byte {
+	data := []byte(sshSigMagic)
+	for _, field := range [][]byte{[]byte(sig.namespace), sig.reserved, []byte(sig.hashAlgorithm), digest} {
+		data = binary.BigEndian.AppendUint32(data, uint32(len(field)))
+		data = append(data, field...)
+	}
+
+	return data
+}
+
+// parseSSHPubKey parses the given OpenSSH public key line, returning the algorithm and the raw bytes of the key.
+func parseSSHPubKey(line string) (util.Alg, []byte, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return "", nil, fmt.Errorf("invalid OpenSSH public key: should be of the form '<type> <base64 blob> [comment]'")
+	}
+
+	alg, ok := sshKeyAlgorithms[fields[0]]
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported key type: %s", fields[0])
+	}
+
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid OpenSSH public key: %w", err)
+	}
+
+	return parseSSHPubKeyBlob(fields[0], alg, blob)
+}
+
+// parseSSHPubKeyBlob decodes the given public key blob, encoded according to the SSH wire format, checking that it
+// embeds the given key type.
+func parseSSHPubKeyBlob(keyType string, alg util.Alg, blob []byte) (util.Alg, []byte, error) {
+	embeddedType, rest, ok := readSSHString(blob)
+	if !ok {
+		return "", nil, fmt.Errorf("invalid OpenSSH public key: malformed key type")
+	}
+	if string(embeddedType) != keyType {
+		return "", nil, fmt.Errorf("invalid OpenSSH public key: key type mismatch, %s declared but %s embedded", keyType, embeddedType)
+	}
+
+	key, rest, ok := readSSHString(rest)
+	if !ok || len(rest) != 0 {
+		return "", nil, fmt.Errorf("invalid OpenSSH public key: malformed key data")
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return "", nil, fmt.Errorf("invalid OpenSSH public key: should be %d bytes long, given %d", ed25519.PublicKeySize, len(key))
+	}
+
+	return alg, key, nil
+}
+
+// readSSHString reads a string encoded according to the SSH wire format (RFC 4251), i.e. prefixed by its length as a
+// 32 bits big endian unsigned integer, returning the string and the remaining data.
+func readSSHString(data []byte) ([]byte, []byte, bool) {
+	if len(data) < 4 {
+		return nil, nil, false
+	}
+	length := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint64(len(data)) < uint64(length) {
+		return nil, nil, false
+	}
+
+	return data[:length], data[length:], true
+}
@@ -0,0 +1,268 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestOpenSSHPubKey(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query: `openssh_pubkey('ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGXRI1jrJFufI31VDjz46cmSNacigwAve5tLvHxoZ0LE alice@okp4', Algorithm, KeyBytes).`,
+				wantResult: []types.TermResults{{
+					"Algorithm": "ed25519",
+					"KeyBytes":  "[101,209,35,88,235,36,91,159,35,125,85,14,60,248,233,201,146,53,167,34,131,0,47,123,155,75,188,124,104,103,66,196]",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query: `openssh_pubkey('  ssh-ed25519   AAAAC3NzaC1lZDI1NTE5AAAAIGXRI1jrJFufI31VDjz46cmSNacigwAve5tLvHxoZ0LE', ed25519, KeyBytes).`,
+				wantResult: []types.TermResults{{
+					"KeyBytes": "[101,209,35,88,235,36,91,159,35,125,85,14,60,248,233,201,146,53,167,34,131,0,47,123,155,75,188,124,104,103,66,196]",
+				}},
+				wantSuccess: true,
+			},
+			{
+				query:       `openssh_pubkey('ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGXRI1jrJFufI31VDjz46cmSNacigwAve5tLvHxoZ0LE alice@okp4', secp256k1, _).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `openssh_pubkey('ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBK7BBSO/lpqtEHKzQ63wpIrs5Z02VnfH9KJeKS1tsLVjyZCiXkP1l89pElu0RXmIQ+Ew0bD7j/yAQ8a2wmCIqvs= bob', Algorithm, KeyBytes).`,
+				wantError:   fmt.Errorf("openssh_pubkey/3: unsupported key type: ecdsa-sha2-nistp256"),
+				wantSuccess: false,
+			},
+			{
+				query:       `openssh_pubkey('ssh-ed25519 AAAAC3NzaC1lZDI1NTE4AAAAIGXRI1jrJFufI31VDjz46cmSNacigwAve5tLvHxoZ0LE', Algorithm, KeyBytes).`,
+				wantError:   fmt.Errorf("openssh_pubkey/3: invalid OpenSSH public key: key type mismatch, ssh-ed25519 declared but ssh-ed25518 embedded"),
+				wantSuccess: false,
+			},
+			{
+				query:       `openssh_pubkey('ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGXRI1jrJFufI31VDjz46cmSNacigwAve5tLvHxoZ0I=', Algorithm, KeyBytes).`,
+				wantError:   fmt.Errorf("openssh_pubkey/3: invalid OpenSSH public key: malformed key data"),
+				wantSuccess: false,
+			},
+			{
+				query:       `openssh_pubkey('ssh-ed25519 not-base64!', Algorithm, KeyBytes).`,
+				wantError:   fmt.Errorf("openssh_pubkey/3: invalid OpenSSH public key: illegal base64 data at input byte 3"),
+				wantSuccess: false,
+			},
+			{
+				query:       `openssh_pubkey('ssh-ed25519', Algorithm, KeyBytes).`,
+				wantError:   fmt.Errorf("openssh_pubkey/3: invalid OpenSSH public key: should be of the form '<type> <base64 blob> [comment]'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `openssh_pubkey(Text, Algorithm, KeyBytes).`,
+				wantError:   fmt.Errorf("openssh_pubkey/3: invalid text type: engine.Variable, should be Atom"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("openssh_pubkey"), OpenSSHPubKey)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestSSHSigVerify(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		program := `alice('ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGXRI1jrJFufI31VDjz46cmSNacigwAve5tLvHxoZ0LE alice@okp4').
+bob('ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIN1dvPixlmzweqtqoCekH7t8O9nzsa1E9fCm8GG72Tfv bob@okp4').
+sig(git, '-----BEGIN SSH SIGNATURE-----\nU1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgZdEjWOskW58jfVUOPPjpyZI1py\nKDAC97m0u8fGhnQsQAAAADZ2l0AAAAAAAAAAZzaGE1MTIAAABTAAAAC3NzaC1lZDI1NTE5\nAAAAQHpTBjKgK/L6rIJ5phY3j53QQu0pi47ki8AEVUAqVAee5I/U1395VrK4nn9mbt0wvb\nioG+XS7xegVZfj5STjugk=\n-----END SSH SIGNATURE-----').
+sig(file, '-----BEGIN SSH SIGNATURE-----\nU1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgZdEjWOskW58jfVUOPPjpyZI1py\nKDAC97m0u8fGhnQsQAAAAEZmlsZQAAAAAAAAAGc2hhMjU2AAAAUwAAAAtzc2gtZWQyNTUx\nOQAAAEDiZVnGcug23eeJeZMURB76wpAT9vORoNfWcsA0VkEdaI9Yso7ptKVfyY2nDXCsE4\ngzBuk8jgnFmwtXVYJPbOAP\n-----END SSH SIGNATURE-----').`
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				program:     program,
+				query:       `alice(K), sig(git, S), sshsig_verify('hello okp4', S, K, git).`,
+				wantResult:  []types.TermResults{{"K": "'ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGXRI1jrJFufI31VDjz46cmSNacigwAve5tLvHxoZ0LE alice@okp4'", "S": "'-----BEGIN SSH SIGNATURE-----\\nU1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgZdEjWOskW58jfVUOPPjpyZI1py\\nKDAC97m0u8fGhnQsQAAAADZ2l0AAAAAAAAAAZzaGE1MTIAAABTAAAAC3NzaC1lZDI1NTE5\\nAAAAQHpTBjKgK/L6rIJ5phY3j53QQu0pi47ki8AEVUAqVAee5I/U1395VrK4nn9mbt0wvb\\nioG+XS7xegVZfj5STjugk=\\n-----END SSH SIGNATURE-----'"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `alice(K), sig(file, S), sshsig_verify([104,101,108,108,111,32,111,107,112,52], S, K, file).`,
+				wantResult:  []types.TermResults{{"K": "'ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGXRI1jrJFufI31VDjz46cmSNacigwAve5tLvHxoZ0LE alice@okp4'", "S": "'-----BEGIN SSH SIGNATURE-----\\nU1NIU0lHAAAAAQAAADMAAAALc3NoLWVkMjU1MTkAAAAgZdEjWOskW58jfVUOPPjpyZI1py\\nKDAC97m0u8fGhnQsQAAAAEZmlsZQAAAAAAAAAGc2hhMjU2AAAAUwAAAAtzc2gtZWQyNTUx\\nOQAAAEDiZVnGcug23eeJeZMURB76wpAT9vORoNfWcsA0VkEdaI9Yso7ptKVfyY2nDXCsE4\\ngzBuk8jgnFmwtXVYJPbOAP\\n-----END SSH SIGNATURE-----'"}},
+				wantSuccess: true,
+			},
+			{
+				program:     program,
+				query:       `alice(K), sig(git, S), sshsig_verify('hello okp5', S, K, git).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `bob(K), sig(git, S), sshsig_verify('hello okp4', S, K, git).`,
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `alice(K), sig(git, S), sshsig_verify('hello okp4', S, K, file).`,
+				wantError:   fmt.Errorf("sshsig_verify/4: namespace mismatch, file expected but git signed"),
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `alice(K), sshsig_verify('hello okp4', 'U1NIU0lHAAAAAQ==', K, git).`,
+				wantError:   fmt.Errorf("sshsig_verify/4: invalid SSH signature: should be enclosed in '-----BEGIN SSH SIGNATURE-----' and '-----END SSH SIGNATURE-----'"),
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `alice(K), sshsig_verify('hello okp4', '-----BEGIN SSH SIGNATURE-----\nU1NIU0lHAAAAAQAAAAM=\n-----END SSH SIGNATURE-----', K, git).`,
+				wantError:   fmt.Errorf("sshsig_verify/4: invalid SSH signature: malformed signature data"),
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `alice(K), sshsig_verify('hello okp4', [1,2,3], K, git).`,
+				wantError:   fmt.Errorf("sshsig_verify/4: invalid SSH signature: missing SSHSIG preamble"),
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `alice(K), sshsig_verify('hello okp4', S, K, git).`,
+				wantError:   fmt.Errorf("sshsig_verify/4: invalid signature type: engine.Variable, should be Atom or List"),
+				wantSuccess: false,
+			},
+			{
+				program:     program,
+				query:       `sig(git, S), sshsig_verify('hello okp4', S, K, git).`,
+				wantError:   fmt.Errorf("sshsig_verify/4: invalid public key type: engine.Variable, should be Atom"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register4(engine.NewAtom("sshsig_verify"), SSHSigVerify)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
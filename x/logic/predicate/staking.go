@@ -0,0 +1,75 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/types"
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+// AtomDelegation are terms with principal functor delegation/3.
+// It is used to represent a delegation of tokens to a validator.
+var AtomDelegation = engine.NewAtom("delegation")
+
+// StakingDelegations is a predicate which unifies the given term with the list of the delegations of the given
+// account.
+//
+// The signature is as follows:
+//
+//	staking_delegations(+Delegator, -Delegations) is det
+//
+// Where:
+//   - Delegator represents the address of the delegator account (in Bech32 format).
+//   - Delegations represents the delegations of the account, as a list of delegation(Validator, Shares, Amount) terms
+//     where Validator is the operator address of the validator (in Bech32 format), Shares the delegated shares, as an
+//     Atom holding a decimal with 18 digits after the decimal point (see dec_add/3), and Amount the amount of tokens
+//     the shares are worth, as an Atom holding an integer (see bignum_add/3).
+//
+// The delegations are read from the committed state at the current height, in the order of the addresses of their
+// validator, and the reads consume gas as any other access to the state. An unbound Delegator raises an
+// instantiation_error.
+//
+// Examples:
+//
+//	# Query the delegations of the account.
+//	- staking_delegations('okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm', Delegations).
+//
+//	# Query the validators the account delegates to.
+//	- staking_delegations('okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm', Delegations),
+//	  member(delegation(Validator, _, _), Delegations).
+func StakingDelegations(vm *engine.VM, delegator, delegations engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		sdkContext, err := util.UnwrapSDKContext(ctx)
+		if err != nil {
+			return engine.Error(err)
+		}
+		stakingKeeper := sdkContext.Value(types.StakingKeeperContextKey).(types.StakingKeeper)
+
+		address, err := getBech32(env, delegator)
+		if err != nil {
+			return engine.Error(fmt.Errorf("staking_delegations/2: %w", err))
+		}
+		if address == nil {
+			return engine.Error(engine.InstantiationError(env))
+		}
+
+		terms := make([]engine.Term, 0)
+		for _, delegation := range stakingKeeper.GetAllDelegatorDelegations(sdkContext, address) {
+			validator, found := stakingKeeper.GetValidator(sdkContext, delegation.GetValidatorAddr())
+			if !found {
+				return engine.Error(fmt.Errorf("staking_delegations/2: validator not found: %s", delegation.ValidatorAddress))
+			}
+
+			terms = append(terms, AtomDelegation.Apply(
+				engine.NewAtom(delegation.ValidatorAddress),
+				engine.NewAtom(delegation.Shares.String()),
+				engine.NewAtom(validator.TokensFromShares(delegation.Shares).TruncateInt().String()),
+			))
+		}
+
+		return engine.Unify(vm, delegations, engine.List(terms...), cont, env)
+	})
+}
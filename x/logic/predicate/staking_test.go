@@ -0,0 +1,179 @@
+//nolint:gocognit
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ichiban/prolog"
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestStakingDelegations(t *testing.T) {
+	Convey("Under a mocked environment", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdk.GetConfig().SetBech32PrefixForAccount("okp4", "okp4pub")
+		sdk.GetConfig().SetBech32PrefixForValidator("okp4valoper", "okp4valoperpub")
+		validator1 := sdk.ValAddress("validator1__________").String()
+		validator2 := sdk.ValAddress("validator2__________").String()
+
+		cases := []struct {
+			delegations map[string][]staking.Delegation
+			validators  []staking.Validator
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+		}{
+			{
+				delegations: map[string][]staking.Delegation{
+					"okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm": {
+						{
+							DelegatorAddress: "okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm",
+							ValidatorAddress: validator1,
+							Shares:           sdk.NewDec(300),
+						},
+						{
+							DelegatorAddress: "okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm",
+							ValidatorAddress: validator2,
+							Shares:           sdk.MustNewDecFromStr("1.5"),
+						},
+					},
+				},
+				validators: []staking.Validator{
+					{OperatorAddress: validator1, Tokens: sdk.NewInt(2000), DelegatorShares: sdk.NewDec(1000)},
+					{OperatorAddress: validator2, Tokens: sdk.NewInt(1000), DelegatorShares: sdk.NewDec(3)},
+				},
+				query: `staking_delegations('okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm', Delegations).`,
+				wantResult: []types.TermResults{{
+					"Delegations": prolog.TermString(fmt.Sprintf(
+						"[delegation(%s,'300.000000000000000000','600'),delegation(%s,'1.500000000000000000','500')]",
+						validator1, validator2)),
+				}},
+			},
+			{
+				delegations: map[string][]staking.Delegation{
+					"okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm": {
+						{
+							DelegatorAddress: "okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm",
+							ValidatorAddress: validator2,
+							Shares:           sdk.NewDec(1),
+						},
+					},
+				},
+				validators: []staking.Validator{
+					{OperatorAddress: validator2, Tokens: sdk.NewInt(1000), DelegatorShares: sdk.NewDec(3)},
+				},
+				query:      `staking_delegations('okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm', [delegation(V, _, Amount)]).`,
+				wantResult: []types.TermResults{{"V": prolog.TermString(validator2), "Amount": "'333'"}},
+			},
+			{
+				query:      `staking_delegations('okp41wze8mn5nsgl9qrgazq6a92fvh7m5e6pslyrz38', Delegations).`,
+				wantResult: []types.TermResults{{"Delegations": "[]"}},
+			},
+			{
+				delegations: map[string][]staking.Delegation{
+					"okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm": {
+						{
+							DelegatorAddress: "okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm",
+							ValidatorAddress: validator1,
+							Shares:           sdk.NewDec(1),
+						},
+					},
+				},
+				query:     `staking_delegations('okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm', Delegations).`,
+				wantError: fmt.Errorf("staking_delegations/2: validator not found: %s", validator1),
+			},
+			{
+				query:     `staking_delegations('foo', Delegations).`,
+				wantError: fmt.Errorf("staking_delegations/2: decoding bech32 failed: invalid bech32 string length 3"),
+			},
+			{
+				query:      `catch(staking_delegations(_, _), E, R = caught).`,
+				wantResult: []types.TermResults{{"E": "error(instantiation_error,/(staking_delegations,2))", "R": "caught"}},
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					stakingKeeper := testutil.NewMockStakingKeeper(ctrl)
+					ctx := sdk.
+						NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger()).
+						WithValue(types.StakingKeeperContextKey, stakingKeeper)
+
+					Convey("and a staking keeper initialized with the preconfigured delegations", func() {
+						stakingKeeper.
+							EXPECT().
+							GetAllDelegatorDelegations(ctx, gomock.Any()).
+							AnyTimes().
+							DoAndReturn(func(_ sdk.Context, delegator sdk.AccAddress) []staking.Delegation {
+								return tc.delegations[delegator.String()]
+							})
+						stakingKeeper.
+							EXPECT().
+							GetValidator(ctx, gomock.Any()).
+							AnyTimes().
+							DoAndReturn(func(_ sdk.Context, addr sdk.ValAddress) (staking.Validator, bool) {
+								for _, validator := range tc.validators {
+									if validator.OperatorAddress == addr.String() {
+										return validator, true
+									}
+								}
+								return staking.Validator{}, false
+							})
+
+						Convey("and a vm", func() {
+							interpreter := testutil.NewLightInterpreterMust(ctx)
+							interpreter.Register2(engine.NewAtom("staking_delegations"), StakingDelegations)
+							interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+							Convey("When the predicate is called", func() {
+								sols, err := interpreter.QueryContext(ctx, tc.query)
+
+								Convey("Then the error should be nil", func() {
+									So(err, ShouldBeNil)
+									So(sols, ShouldNotBeNil)
+
+									Convey("and the bindings should be as expected", func() {
+										var got []types.TermResults
+										for sols.Next() {
+											m := types.TermResults{}
+											err := sols.Scan(m)
+											So(err, ShouldBeNil)
+
+											got = append(got, m)
+										}
+										if tc.wantError != nil {
+											So(sols.Err(), ShouldNotBeNil)
+											So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+										} else {
+											So(sols.Err(), ShouldBeNil)
+											So(got, ShouldResemble, tc.wantResult)
+										}
+									})
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -5,13 +5,32 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/ichiban/prolog/engine"
+	"github.com/samber/lo"
 
 	"github.com/okp4/okp4d/x/logic/util"
 )
 
+var (
+	// AtomAs is the term used to indicate the as option, giving the type of a text.
+	AtomAs = engine.NewAtom("as")
+
+	// AtomString is the term used to indicate the string type of text, i.e. a list of characters.
+	AtomString = engine.NewAtom("string")
+
+	// AtomCodes is the term used to indicate the codes type of text, i.e. a list of character codes.
+	AtomCodes = engine.NewAtom("codes")
+
+	// AtomTextType is the term used to indicate the text type domain in a domain error.
+	AtomTextType = engine.NewAtom("text_type")
+)
+
 // ReadString is a predicate that reads characters from the provided Stream and unifies them with String.
 // Users can optionally specify a maximum length for reading; if the stream reaches this length, the reading stops.
 // If Length remains unbound, the entire Stream is read, and upon completion, Length is unified with the count of characters read.
@@ -77,3 +96,803 @@ func ReadString(vm *engine.VM, stream, length, result engine.Term, cont engine.C
 		return engine.Unify(vm, Tuple(result, length), Tuple(util.StringToTerm(builder.String()), engine.Integer(totalLen)), cont, env)
 	})
 }
+
+// ParseByTemplate is a predicate that parses the given Input according to the given Template, extracting the values
+// of the template placeholders.
+//
+// The signature is as follows:
+//
+//	parse_by_template(+Template, +Input, -Bindings) is semidet
+//	parse_by_template(+Template, +Input, -Bindings, +Options) is semidet
+//
+// Where:
+//   - Template is an Atom made of literal text and {Name} placeholders, where each Name is made of letters, digits and
+//     underscores (and does not start with a digit). The literal characters { and } are escaped as {{ and }}.
+//   - Input is the Atom to parse.
+//   - Bindings is the list of Name-Value pairs, in the order of the placeholders in Template, where each Value is the
+//     Atom corresponding to the span of Input matched by the placeholder.
+//   - Options are additional configurations for the parsing. Supported options include: mode(+Mode) which specifies
+//     how the placeholders match the Input: either lazy (the default) where each placeholder matches the shortest
+//     possible span, or greedy where each placeholder matches the longest possible span.
+//
+// The whole Input must match the Template, otherwise the predicate fails. A placeholder may match an empty span.
+// parse_by_template/3 is the same as parse_by_template/4 with no options.
+//
+// Examples:
+//
+//	# Parse a log line.
+//	- parse_by_template('{ts} {level} {msg}', '2023-06-01T10:00:00Z INFO block committed', Bindings).
+//
+//	# Parse a key-value pair, the first placeholder matching the longest possible span.
+//	- parse_by_template('{key}={value}', 'a=b=c', Bindings, [mode(greedy)]).
+func ParseByTemplate(
+	vm *engine.VM, template, input, bindings, options engine.Term, cont engine.Cont, env *engine.Env,
+) *engine.Promise {
+	const functor = "parse_by_template/4"
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		modeTerm, err := util.GetOptionWithDefault(engine.NewAtom("mode"), options, engine.NewAtom("lazy"), env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		mode, err := util.ResolveToAtom(env, modeTerm)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		if mode.String() != "lazy" && mode.String() != "greedy" {
+			return engine.Error(fmt.Errorf("%s: invalid mode option: %s, valid values are 'lazy' or 'greedy'", functor, mode))
+		}
+
+		templateAtom, err := util.ResolveToAtom(env, template)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		inputAtom, err := util.ResolveToAtom(env, input)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		re, names, err := compileTemplate(templateAtom.String(), mode.String() == "greedy")
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		matches := re.FindStringSubmatch(inputAtom.String())
+		if matches == nil {
+			return engine.Bool(false)
+		}
+
+		pairs := make([]engine.Term, 0, len(names))
+		for i, name := range names {
+			pairs = append(pairs, AtomPair.Apply(engine.NewAtom(name), util.StringToTerm(matches[i+1])))
+		}
+
+		return engine.Unify(vm, bindings, engine.List(pairs...), cont, env)
+	})
+}
+
+// templatePlaceholderRegexp matches the name of a template placeholder.
+var templatePlaceholderRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// compileTemplate compiles the given template into a regular expression matching a whole input, returning it along
+// with the names of the placeholders, in order.
+func compileTemplate(template string, greedy bool) (*regexp.Regexp, []string, error) {
+	capture := "(.*?)"
+	if greedy {
+		capture = "(.*)"
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("(?s)^")
+	names := make([]string, 0)
+	seen := make(map[string]struct{})
+	for i := 0; i < len(template); i++ {
+		switch c := template[i]; {
+		case c == '{' && strings.HasPrefix(template[i:], "{{"), c == '}' && strings.HasPrefix(template[i:], "}}"):
+			pattern.WriteString(regexp.QuoteMeta(template[i : i+1]))
+			i++
+		case c == '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end == -1 {
+				return nil, nil, fmt.Errorf("invalid template: unclosed placeholder at position %d", i)
+			}
+			name := template[i+1 : i+end]
+			if !templatePlaceholderRegexp.MatchString(name) {
+				return nil, nil, fmt.Errorf("invalid template: invalid placeholder name '%s'", name)
+			}
+			if _, ok := seen[name]; ok {
+				return nil, nil, fmt.Errorf("invalid template: duplicated placeholder '%s'", name)
+			}
+			seen[name] = struct{}{}
+			names = append(names, name)
+			pattern.WriteString(capture)
+			i += end
+		case c == '}':
+			return nil, nil, fmt.Errorf("invalid template: unexpected '}' at position %d", i)
+		default:
+			pattern.WriteString(regexp.QuoteMeta(template[i : i+1]))
+		}
+	}
+	pattern.WriteString("$")
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return re, names, nil
+}
+
+// StringConcat is a predicate that concatenates two strings, or splits a string into two parts.
+//
+// The signature is as follows:
+//
+//	string_concat(?String1, ?String2, ?String3) is nondet
+//
+// Where:
+//   - String1 and String2 are the parts to concatenate.
+//   - String3 is the concatenation of String1 and String2.
+//
+// Strings are given as any atomic term (Atom or number) or as a list of characters or character codes, and are always
+// returned as Atoms. If String3 is unbound, String1 and String2 must be bound, or an instantiation_error is raised, and
+// String3 is unified with their concatenation. Otherwise, the predicate enumerates on backtracking all the ways String3 can be split into String1 and
+// String2, starting with the empty String1.
+//
+// Examples:
+//
+//	# Concatenate two strings.
+//	- string_concat(foo, bar, X).
+//
+//	# Enumerate all the splits of a string.
+//	- string_concat(X, Y, abc).
+func StringConcat(vm *engine.VM, str1, str2, str3 engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		arg1, ok1, err := termToOptionalText(str1, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("string_concat/3: %w", err))
+		}
+		arg2, ok2, err := termToOptionalText(str2, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("string_concat/3: %w", err))
+		}
+		whole, ok3, err := termToOptionalText(str3, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("string_concat/3: %w", err))
+		}
+
+		switch {
+		case !ok3:
+			if !ok1 || !ok2 {
+				return engine.Error(engine.InstantiationError(env))
+			}
+			return engine.Unify(vm, str3, util.StringToTerm(arg1+arg2), cont, env)
+		case ok1 && ok2:
+			if arg1+arg2 != whole {
+				return engine.Bool(false)
+			}
+			return cont(env)
+		case ok1:
+			if !strings.HasPrefix(whole, arg1) {
+				return engine.Bool(false)
+			}
+			return engine.Unify(vm, str2, util.StringToTerm(strings.TrimPrefix(whole, arg1)), cont, env)
+		case ok2:
+			if !strings.HasSuffix(whole, arg2) {
+				return engine.Bool(false)
+			}
+			return engine.Unify(vm, str1, util.StringToTerm(strings.TrimSuffix(whole, arg2)), cont, env)
+		}
+
+		s := whole
+		pattern := Tuple(str1, str2)
+		ks := make([]func(context.Context) *engine.Promise, 0, len(s)+1)
+		for i := range s {
+			prefix, suffix := s[:i], s[i:]
+			ks = append(ks, func(context.Context) *engine.Promise {
+				return engine.Unify(vm, pattern, Tuple(util.StringToTerm(prefix), util.StringToTerm(suffix)), cont, env)
+			})
+		}
+		ks = append(ks, func(context.Context) *engine.Promise {
+			return engine.Unify(vm, pattern, Tuple(util.StringToTerm(s), util.StringToTerm("")), cont, env)
+		})
+
+		return engine.Delay(ks...)
+	})
+}
+
+// AtomicListConcat is a predicate that joins a list of atomic terms with a separator, or splits an atom on a separator.
+//
+// The signature is as follows:
+//
+//	atomic_list_concat(+List, +Separator, -Atom) is det
+//	atomic_list_concat(-List, +Separator, +Atom) is det
+//
+// Where:
+//   - List is the list of atomic terms (Atoms or numbers) to join.
+//   - Separator is the atomic term inserted between each element of List.
+//   - Atom is the result of the join.
+//
+// If List is a proper list of instantiated elements, Atom is unified with the concatenation of its elements separated
+// by Separator. Otherwise, Atom must be bound and Separator must not be empty: Atom is then split on each occurrence of
+// Separator and the resulting list of Atoms is unified with List.
+//
+// An unbound Separator, or an unbound Atom when List is not a proper list of instantiated elements, raises an
+// instantiation_error.
+//
+// Examples:
+//
+//	# Join a list in the CSV fashion.
+//	- atomic_list_concat([alice, 42, bob], ',', X).
+//
+//	# Split an atom on a separator.
+//	- atomic_list_concat(X, ',', 'alice,42,bob').
+func AtomicListConcat(vm *engine.VM, list, separator, atom engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		sep, ok, err := termToOptionalText(separator, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("atomic_list_concat/3: %w", err))
+		}
+		if !ok {
+			return engine.Error(engine.InstantiationError(env))
+		}
+
+		if elems, ok, err := termToTextList(list, env); err != nil {
+			return engine.Error(fmt.Errorf("atomic_list_concat/3: %w", err))
+		} else if ok {
+			return engine.Unify(vm, atom, util.StringToTerm(strings.Join(elems, sep)), cont, env)
+		}
+
+		whole, ok, err := termToOptionalText(atom, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("atomic_list_concat/3: %w", err))
+		}
+		if !ok {
+			return engine.Error(engine.InstantiationError(env))
+		}
+		if sep == "" {
+			return engine.Error(fmt.Errorf("atomic_list_concat/3: separator cannot be empty when splitting"))
+		}
+
+		parts := strings.Split(whole, sep)
+		terms := make([]engine.Term, 0, len(parts))
+		for _, part := range parts {
+			terms = append(terms, util.StringToTerm(part))
+		}
+
+		return engine.Unify(vm, list, engine.List(terms...), cont, env)
+	})
+}
+
+// SplitString is a predicate that splits a string into substrings, according to a set of separator characters, and
+// removes the padding characters from each substring.
+//
+// The signature is as follows:
+//
+//	split_string(+String, +SepChars, +PadChars, -SubStrings) is det
+//
+// Where:
+//   - String is the string to split.
+//   - SepChars is the set of the characters on which String is split.
+//   - PadChars is the set of the characters removed from the beginning and the end of each substring.
+//   - SubStrings is the resulting list of substrings, as Atoms.
+//
+// Strings and sets of characters are given as any atomic term (Atom or number) or as a list of characters or
+// character codes. The semantics is the one of SWI-Prolog: if SepChars is empty, String is not split and the
+// predicate only removes the padding from its ends. If SepChars and PadChars have common characters, sequences of
+// adjacent separators act as a single separator.
+//
+// Examples:
+//
+//	# Split a comma separated line, removing the white spaces around each value.
+//	- split_string('alice, 42 ,bob', ',', ' ', SubStrings).
+//
+//	# Remove the white spaces around a string.
+//	- split_string('  hello  ', '', ' ', SubStrings).
+func SplitString(vm *engine.VM, str, sepChars, padChars, subStrings engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		text, err := termToText(str, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("split_string/4: %w", err))
+		}
+		seps, err := termToText(sepChars, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("split_string/4: %w", err))
+		}
+		pads, err := termToText(padChars, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("split_string/4: %w", err))
+		}
+
+		isSep := func(r rune) bool { return strings.ContainsRune(seps, r) }
+		isPad := func(r rune) bool { return strings.ContainsRune(pads, r) }
+
+		rs := []rune(text)
+		start, end := 0, len(rs)
+		for start < end && isPad(rs[start]) {
+			start++
+		}
+		for end > start && isPad(rs[end-1]) {
+			end--
+		}
+
+		parts := make([]engine.Term, 0)
+		for {
+			for start < end && isPad(rs[start]) {
+				start++
+			}
+			sep := start
+			for sep < end && !isSep(rs[sep]) {
+				sep++
+			}
+			last := sep
+			for last > start && isPad(rs[last-1]) {
+				last--
+			}
+			parts = append(parts, util.StringToTerm(string(rs[start:last])))
+
+			if sep == end {
+				break
+			}
+			start = sep + 1
+		}
+
+		return engine.Unify(vm, subStrings, engine.List(parts...), cont, env)
+	})
+}
+
+// StringLower is a predicate that converts a string to lower case.
+//
+// The signature is as follows:
+//
+//	string_lower(+String, -Lower) is det
+//
+// Where:
+//   - String is the string to convert, given as any atomic term (Atom or number) or as a list of characters or
+//     character codes.
+//   - Lower is the lower case version of String, as an Atom.
+//
+// The conversion is Unicode-aware: each character is mapped to its lower case according to the Unicode standard.
+//
+// Examples:
+//
+//	# Convert a denomination to lower case.
+//	- string_lower('UKNOW', Lower).
+func StringLower(vm *engine.VM, str, lower engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		text, err := termToText(str, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("string_lower/2: %w", err))
+		}
+
+		return engine.Unify(vm, lower, util.StringToTerm(strings.ToLower(text)), cont, env)
+	})
+}
+
+// StringUpper is a predicate that converts a string to upper case.
+//
+// The signature is as follows:
+//
+//	string_upper(+String, -Upper) is det
+//
+// Where:
+//   - String is the string to convert, given as any atomic term (Atom or number) or as a list of characters or
+//     character codes.
+//   - Upper is the upper case version of String, as an Atom.
+//
+// The conversion is Unicode-aware: each character is mapped to its upper case according to the Unicode standard.
+//
+// Examples:
+//
+//	# Convert a hexadecimal string to upper case.
+//	- string_upper('0xdeadbeef', Upper).
+func StringUpper(vm *engine.VM, str, upper engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		text, err := termToText(str, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("string_upper/2: %w", err))
+		}
+
+		return engine.Unify(vm, upper, util.StringToTerm(strings.ToUpper(text)), cont, env)
+	})
+}
+
+// UTF8Bytes is a predicate that unifies an Atom with the list of bytes of its UTF-8 encoding.
+//
+// The signature is as follows:
+//
+//	utf8_bytes(+Atom, -Bytes) is det
+//	utf8_bytes(-Atom, +Bytes) is det
+//
+// Where:
+//   - Atom is the Atom whose text is encoded.
+//   - Bytes is the list of numbers between 0 and 255 that represent the UTF-8 encoding of the text of Atom.
+//
+// When Atom is not bound, Bytes must hold a valid UTF-8 byte sequence, otherwise an error is raised. This is the
+// encoding implicitly used by sha_hash/2 when hashing an Atom.
+//
+// Examples:
+//
+//	# Get the UTF-8 encoding of an Atom.
+//	- utf8_bytes('héllo', Bytes).
+//
+//	# Decode a UTF-8 byte sequence.
+//	- utf8_bytes(Atom, [104, 195, 169, 108, 108, 111]).
+func UTF8Bytes(vm *engine.VM, atom, bts engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		switch a := env.Resolve(atom).(type) {
+		case engine.Atom:
+			return engine.Unify(vm, bts, BytesToList([]byte(a.String())), cont, env)
+		case engine.Variable:
+			src, err := ListToBytes(ctx, engine.ListIterator{List: bts, Env: env}, env)
+			if err != nil {
+				return engine.Error(fmt.Errorf("utf8_bytes/2: failed convert list into bytes: %w", err))
+			}
+			if !utf8.Valid(src) {
+				return engine.Error(fmt.Errorf("utf8_bytes/2: invalid UTF-8 byte sequence"))
+			}
+			return engine.Unify(vm, atom, engine.NewAtom(string(src)), cont, env)
+		default:
+			return engine.Error(fmt.Errorf("utf8_bytes/2: invalid atom type: %T, should be Atom or Variable", a))
+		}
+	})
+}
+
+// SubAtom is a predicate which enumerates the sub atoms of an atom, as defined by the ISO standard.
+//
+// The signature is as follows:
+//
+//	sub_atom(+Atom, ?Before, ?Length, ?After, ?SubAtom) is nondet
+//
+// Where:
+//   - Atom is the atom to extract the sub atoms from.
+//   - Before is the number of characters of Atom before SubAtom.
+//   - Length is the number of characters of SubAtom.
+//   - After is the number of characters of Atom after SubAtom.
+//   - SubAtom is the sub atom of Atom.
+//
+// The solutions are computed directly from the bound arguments rather than by enumerating all the sub atoms of Atom:
+// when SubAtom is bound, only its occurrences in Atom are enumerated, and when two of Before, Length and After are
+// bound, the only possible solution is computed at once. The solutions are generated lazily, in the ascending order of
+// Before then of Length.
+//
+// Examples:
+//
+//	# Find the position of a sub atom.
+//	- sub_atom(hello_world, Before, _, _, world).
+//
+//	# Extract a sub atom from its position and length.
+//	- sub_atom(hello_world, 6, 5, _, SubAtom).
+func SubAtom(vm *engine.VM, atom, before, length, after, subAtom engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		var whole engine.Atom
+		switch a := env.Resolve(atom).(type) {
+		case engine.Variable:
+			return engine.Error(engine.InstantiationError(env))
+		case engine.Atom:
+			whole = a
+		default:
+			return engine.Error(engine.TypeError(engine.NewAtom("atom"), atom, env))
+		}
+
+		b, l, a, err := subAtomBounds(before, length, after, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		rs := []rune(whole.String())
+		n := len(rs)
+		pattern := Tuple(before, length, after, subAtom)
+		solution := func(b, l int) *engine.Promise {
+			return engine.Unify(vm, pattern,
+				Tuple(engine.Integer(b), engine.Integer(l), engine.Integer(n-b-l), engine.NewAtom(string(rs[b:b+l]))), cont, env)
+		}
+
+		switch sub := env.Resolve(subAtom).(type) {
+		case engine.Variable:
+		case engine.Atom:
+			subLen := utf8.RuneCountInString(sub.String())
+			switch {
+			case l >= 0 && l != subLen:
+				return engine.Bool(false)
+			case b >= 0:
+				if b+subLen > n || string(rs[b:b+subLen]) != sub.String() {
+					return engine.Bool(false)
+				}
+				return solution(b, subLen)
+			}
+			return subAtomOccurrences(whole.String(), sub.String(), 0, 0, func(b int) *engine.Promise {
+				return solution(b, subLen)
+			})
+		default:
+			return engine.Error(engine.TypeError(engine.NewAtom("atom"), subAtom, env))
+		}
+
+		bFrom, bTo, lRange := subAtomRanges(n, b, l, a)
+
+		return enumerateRange(max(bFrom, 0), min(bTo, n), func(b int) *engine.Promise {
+			lFrom, lTo := lRange(b)
+			return enumerateRange(max(lFrom, 0), min(lTo, n-b), func(l int) *engine.Promise {
+				return solution(b, l)
+			})
+		})
+	})
+}
+
+// SubAtomIcasechk is a predicate which checks whether an atom contains a sub atom, ignoring the case.
+//
+// The signature is as follows:
+//
+//	sub_atom_icasechk(+Atom, ?Start, +SubAtom) is semidet
+//
+// Where:
+//   - Atom is the atom to search in.
+//   - Start is the number of characters of Atom before the first occurrence of SubAtom.
+//   - SubAtom is the atom to search for.
+//
+// The characters are compared after conversion to lower case. If Start is bound, the predicate checks whether SubAtom
+// occurs in Atom at this position, otherwise Start is unified with the position of its first occurrence. The check
+// is performed in linear time, without backtracking.
+//
+// Examples:
+//
+//	# Check that an atom contains a word, ignoring the case.
+//	- sub_atom_icasechk('Hello World', _, world).
+func SubAtomIcasechk(vm *engine.VM, atom, start, subAtom engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		whole, ok := env.Resolve(atom).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("sub_atom_icasechk/3: invalid atom type: %T, should be Atom", env.Resolve(atom)))
+		}
+		sub, ok := env.Resolve(subAtom).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("sub_atom_icasechk/3: invalid sub atom type: %T, should be Atom", env.Resolve(subAtom)))
+		}
+		haystack, needle := strings.Map(unicode.ToLower, whole.String()), strings.Map(unicode.ToLower, sub.String())
+
+		switch s := env.Resolve(start).(type) {
+		case engine.Variable:
+			i := strings.Index(haystack, needle)
+			if i < 0 {
+				return engine.Bool(false)
+			}
+			return engine.Unify(vm, start, engine.Integer(utf8.RuneCountInString(haystack[:i])), cont, env)
+		case engine.Integer:
+			rs := []rune(haystack)
+			if s < 0 || int(s) > len(rs) || !strings.HasPrefix(string(rs[s:]), needle) {
+				return engine.Bool(false)
+			}
+			return cont(env)
+		default:
+			return engine.Error(fmt.Errorf("sub_atom_icasechk/3: invalid start type: %T, should be Integer or Variable", s))
+		}
+	})
+}
+
+// subAtomBounds returns the values of the given Before, Length and After arguments of sub_atom/5, or -1 for the ones
+// which are not bound.
+func subAtomBounds(before, length, after engine.Term, env *engine.Env) (int, int, int, error) {
+	bounds := make([]int, 0, 3)
+	for _, t := range []engine.Term{before, length, after} {
+		switch i := env.Resolve(t).(type) {
+		case engine.Variable:
+			bounds = append(bounds, -1)
+		case engine.Integer:
+			if i < 0 {
+				return 0, 0, 0, engine.DomainError(engine.NewAtom("not_less_than_zero"), t, env)
+			}
+			bounds = append(bounds, int(i))
+		default:
+			return 0, 0, 0, engine.TypeError(engine.NewAtom("integer"), t, env)
+		}
+	}
+
+	return bounds[0], bounds[1], bounds[2], nil
+}
+
+// subAtomRanges returns the range of the Before argument of sub_atom/5 for an atom of the given length, and the function
+// giving the range of the Length argument for a given Before, as constrained by the given bound arguments, or -1 for
+// the ones which are not bound.
+func subAtomRanges(n, b, l, a int) (int, int, func(int) (int, int)) {
+	bFrom, bTo := 0, n
+	switch {
+	case b >= 0:
+		bFrom, bTo = b, b
+	case l >= 0 && a >= 0:
+		bFrom, bTo = n-l-a, n-l-a
+	case l >= 0:
+		bTo = n - l
+	case a >= 0:
+		bTo = n - a
+	}
+
+	return bFrom, bTo, func(b int) (int, int) {
+		switch {
+		case l >= 0:
+			return l, l
+		case a >= 0:
+			return n - b - a, n - b - a
+		default:
+			return 0, n - b
+		}
+	}
+}
+
+// subAtomOccurrences lazily enumerates the occurrences of sub in s from the given byte offset, whose position in
+// characters is given, calling the given function with the position in characters of each of them on backtracking.
+func subAtomOccurrences(s, sub string, offset, pos int, fn func(int) *engine.Promise) *engine.Promise {
+	i := strings.Index(s[offset:], sub)
+	if i < 0 {
+		return engine.Bool(false)
+	}
+	pos += utf8.RuneCountInString(s[offset : offset+i])
+	offset += i
+
+	return engine.Delay(func(context.Context) *engine.Promise {
+		return fn(pos)
+	}, func(context.Context) *engine.Promise {
+		if offset >= len(s) {
+			return engine.Bool(false)
+		}
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		return subAtomOccurrences(s, sub, offset+size, pos+1, fn)
+	})
+}
+
+// enumerateRange lazily enumerates the integers from the given one to the given one, inclusive, calling the given
+// function with each of them on backtracking.
+func enumerateRange(from, to int, fn func(int) *engine.Promise) *engine.Promise {
+	if from > to {
+		return engine.Bool(false)
+	}
+
+	return engine.Delay(func(context.Context) *engine.Promise {
+		return fn(from)
+	}, func(context.Context) *engine.Promise {
+		return enumerateRange(from+1, to, fn)
+	})
+}
+
+// termToTextList converts the given list of atomic terms into the list of their texts. It returns false, without any
+// error, if the list is partial or contains variables.
+func termToTextList(list engine.Term, env *engine.Env) ([]string, bool, error) {
+	elems := make([]string, 0)
+	iter := engine.ListIterator{List: list, Env: env, AllowPartial: true}
+	for iter.Next() {
+		switch e := env.Resolve(iter.Current()).(type) {
+		case engine.Variable:
+			return nil, false, nil
+		case engine.Atom, engine.Integer, engine.Float:
+			text, err := termToText(e, env)
+			if err != nil {
+				return nil, false, err
+			}
+			elems = append(elems, text)
+		default:
+			return nil, false, fmt.Errorf("invalid list element type: %T, should be atomic", e)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, false, fmt.Errorf("invalid list: %w", err)
+	}
+	if _, ok := env.Resolve(iter.Suffix()).(engine.Variable); ok {
+		return nil, false, nil
+	}
+
+	return elems, true, nil
+}
+
+// termToOptionalText converts the given term into its text, also returning false if the term is a variable.
+func termToOptionalText(term engine.Term, env *engine.Env) (string, bool, error) {
+	if _, ok := env.Resolve(term).(engine.Variable); ok {
+		return "", false, nil
+	}
+
+	text, err := termToText(term, env)
+	if err != nil {
+		return "", false, err
+	}
+	return text, true, nil
+}
+
+// termToText converts the given term into its text. The term is expected to be either an atomic term (Atom or number)
+// or a list of characters or character codes.
+func termToText(term engine.Term, env *engine.Env) (string, error) {
+	switch t := env.Resolve(term).(type) {
+	case engine.Atom:
+		return t.String(), nil
+	case engine.Integer:
+		return strconv.FormatInt(int64(t), 10), nil
+	case engine.Float:
+		var sb strings.Builder
+		if err := t.WriteTerm(&sb, &engine.WriteOptions{}, env); err != nil {
+			return "", err
+		}
+		return sb.String(), nil
+	case engine.Compound:
+		if !util.IsList(t) {
+			return "", fmt.Errorf("invalid text type: %T, should be atomic or a list of characters or codes", t)
+		}
+		var sb strings.Builder
+		iter := engine.ListIterator{List: t, Env: env}
+		for iter.Next() {
+			switch e := env.Resolve(iter.Current()).(type) {
+			case engine.Atom:
+				if utf8.RuneCountInString(e.String()) != 1 {
+					return "", fmt.Errorf("invalid character: %s", e)
+				}
+				sb.WriteString(e.String())
+			case engine.Integer:
+				if e < 0 || e > utf8.MaxRune {
+					return "", fmt.Errorf("invalid character code: %d", e)
+				}
+				sb.WriteRune(rune(e))
+			default:
+				return "", fmt.Errorf("invalid character type: %T, should be Atom or Integer", e)
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return "", fmt.Errorf("invalid text: %w", err)
+		}
+		return sb.String(), nil
+	default:
+		return "", fmt.Errorf("invalid text type: %T, should be atomic or a list of characters or codes", t)
+	}
+}
+
+// textTypes are the types of the text terms given by the as(Type) option of the encoding predicates.
+var textTypes = []engine.Atom{AtomAtom, AtomString, AtomCodes}
+
+// textTypeOption returns the type of text given by the as(Type) option of the given options, defaulting to atom. It
+// raises an instantiation_error if Type is unbound, a type_error(atom, Type) error if it is not an Atom, and a
+// domain_error(text_type, Type) error if it is not among atom, string and codes.
+func textTypeOption(options engine.Term, env *engine.Env) (engine.Atom, error) {
+	typeTerm, err := util.GetOptionWithDefault(AtomAs, options, AtomAtom, env)
+	if err != nil {
+		return 0, err
+	}
+	typ, ok := env.Resolve(typeTerm).(engine.Atom)
+	if !ok {
+		return 0, typeError(AtomAtom, typeTerm, env)
+	}
+	if !lo.Contains(textTypes, typ) {
+		return 0, domainError(AtomTextType, typ, env)
+	}
+	return typ, nil
+}
+
+// typedText returns the text of the given term, either an Atom or a list of characters or character codes, along with
+// its type, as given by the as(Type) option. The empty list being both the empty list of characters and of codes, its
+// type is the given default type. It returns false if the term is not a text.
+func typedText(term engine.Term, defaultType engine.Atom, env *engine.Env) (string, engine.Atom, bool) {
+	switch t := env.Resolve(term).(type) {
+	case engine.Atom:
+		if t == util.AtomEmptyList {
+			return "", defaultType, true
+		}
+		return t.String(), AtomAtom, true
+	case engine.Compound:
+		if !util.IsList(t) {
+			return "", 0, false
+		}
+		text, err := termToText(t, env)
+		if err != nil {
+			return "", 0, false
+		}
+		if _, ok := env.Resolve(t.Arg(0)).(engine.Integer); ok {
+			return text, AtomCodes, true
+		}
+		return text, AtomString, true
+	default:
+		return "", 0, false
+	}
+}
+
+// textToTerm returns the term of the given text, according to the given type: an Atom for atom, a list of characters
+// for string, as the double quoted strings are by default, and a list of character codes for codes.
+func textToTerm(text string, typ engine.Atom) engine.Term {
+	switch typ {
+	case AtomString:
+		return engine.CharList(text)
+	case AtomCodes:
+		return engine.CodeList(text)
+	default:
+		return engine.NewAtom(text)
+	}
+}
@@ -1,4 +1,4 @@
-//nolint:gocognit
+//nolint:gocognit,lll
 package predicate
 
 import (
@@ -197,3 +197,1062 @@ func TestReadString(t *testing.T) {
 		}
 	})
 }
+
+func TestParseByTemplate(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `parse_by_template('{ts} {level} {msg}', '2023-06-01T10:00:00Z INFO block committed', Bindings, []).`,
+				wantResult:  []types.TermResults{{"Bindings": "[ts-'2023-06-01T10:00:00Z',level-'INFO',msg-'block committed']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `parse_by_template('{ts} {level} {msg}', '2023-06-01T10:00:00Z INFO block committed', [ts-TS, level-'INFO', msg-Msg], []).`,
+				wantResult:  []types.TermResults{{"TS": "'2023-06-01T10:00:00Z'", "Msg": "'block committed'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `parse_by_template('{ts} {level} {msg}', '2023-06-01T10:00:00Z INFO block committed', Bindings, [mode(greedy)]).`,
+				wantResult:  []types.TermResults{{"Bindings": "[ts-'2023-06-01T10:00:00Z INFO',level-block,msg-committed]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `parse_by_template('{ts} {level} {msg}', '2023-06-01T10:00:00Z', Bindings, []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `parse_by_template('[{level}] {msg}', 'INFO: started', Bindings, []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `parse_by_template('[{level}] {msg}', '[WARN] disk (90%) full', Bindings, []).`,
+				wantResult:  []types.TermResults{{"Bindings": "[level-'WARN',msg-'disk (90%) full']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `parse_by_template('{key}={value}', 'a=b=c', Bindings, [mode(lazy)]).`,
+				wantResult:  []types.TermResults{{"Bindings": "[key-a,value-'b=c']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `parse_by_template('{key}={value}', 'a=b=c', Bindings, [mode(greedy)]).`,
+				wantResult:  []types.TermResults{{"Bindings": "[key-'a=b',value-c]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `parse_by_template('{{{name}}} é {v}', '{x} é y', Bindings, []).`,
+				wantResult:  []types.TermResults{{"Bindings": "[name-x,v-y]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `parse_by_template('no placeholder', 'no placeholder', Bindings, []).`,
+				wantResult:  []types.TermResults{{"Bindings": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `parse_by_template('{ts} {ts}', 'a b', Bindings, []).`,
+				wantError:   fmt.Errorf("parse_by_template/4: invalid template: duplicated placeholder 'ts'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `parse_by_template('{ts', 'a b', Bindings, []).`,
+				wantError:   fmt.Errorf("parse_by_template/4: invalid template: unclosed placeholder at position 0"),
+				wantSuccess: false,
+			},
+			{
+				query:       `parse_by_template('{1ts} x', 'a b', Bindings, []).`,
+				wantError:   fmt.Errorf("parse_by_template/4: invalid template: invalid placeholder name '1ts'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `parse_by_template('a}', 'a}', Bindings, []).`,
+				wantError:   fmt.Errorf("parse_by_template/4: invalid template: unexpected '}' at position 1"),
+				wantSuccess: false,
+			},
+			{
+				query:       `parse_by_template('{a}', 'b', Bindings, [mode(eager)]).`,
+				wantError:   fmt.Errorf("parse_by_template/4: invalid mode option: eager, valid values are 'lazy' or 'greedy'"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register4(engine.NewAtom("parse_by_template"), ParseByTemplate)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestStringConcat(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `string_concat(foo, bar, X).`,
+				wantResult:  []types.TermResults{{"X": "foobar"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_concat(abc, 42, X).`,
+				wantResult:  []types.TermResults{{"X": "abc42"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_concat("héllo", [0' , 0'w], X).`,
+				wantResult:  []types.TermResults{{"X": "'héllo w'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_concat(X, Y, abc).`,
+				wantResult:  []types.TermResults{{"X": "''", "Y": "abc"}, {"X": "a", "Y": "bc"}, {"X": "ab", "Y": "c"}, {"X": "abc", "Y": "''"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_concat(X, Y, éà).`,
+				wantResult:  []types.TermResults{{"X": "''", "Y": "éà"}, {"X": "é", "Y": "à"}, {"X": "éà", "Y": "''"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_concat(ab, X, abcd).`,
+				wantResult:  []types.TermResults{{"X": "cd"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_concat(X, 12, ab12).`,
+				wantResult:  []types.TermResults{{"X": "ab"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_concat(abc, X, abd).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `string_concat(a, b, ab).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_concat(a, b, ba).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(string_concat(a, _, _), error(E, C), true).`,
+				wantResult:  []types.TermResults{{"E": "instantiation_error", "C": "/(string_concat,3)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_concat(f(a), b, X).`,
+				wantError:   fmt.Errorf("string_concat/3: invalid text type: *engine.compound, should be atomic or a list of characters or codes"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("string_concat"), StringConcat)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestAtomicListConcat(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `atomic_list_concat([alice, 42, bob], ',', X).`,
+				wantResult:  []types.TermResults{{"X": "'alice,42,bob'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `atomic_list_concat([a, b, c], '', X).`,
+				wantResult:  []types.TermResults{{"X": "abc"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `atomic_list_concat([], '-', X).`,
+				wantResult:  []types.TermResults{{"X": "''"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `atomic_list_concat([1.5, x], ' ', X).`,
+				wantResult:  []types.TermResults{{"X": "'1.5 x'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `atomic_list_concat(X, ',', 'alice,42,,bob').`,
+				wantResult:  []types.TermResults{{"X": "[alice,'42','',bob]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `atomic_list_concat([alice, X], ', ', 'alice, bob').`,
+				wantResult:  []types.TermResults{{"X": "bob"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `atomic_list_concat([a | T], '/', 'a/b/c').`,
+				wantResult:  []types.TermResults{{"T": "[b,c]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `atomic_list_concat([a, b], '/', 'a-b').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `atomic_list_concat(X, '', abc).`,
+				wantError:   fmt.Errorf("atomic_list_concat/3: separator cannot be empty when splitting"),
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(atomic_list_concat(_, ',', _), error(E, C), true).`,
+				wantResult:  []types.TermResults{{"E": "instantiation_error", "C": "/(atomic_list_concat,3)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(atomic_list_concat([a, b], _, _), error(E, _), true).`,
+				wantResult:  []types.TermResults{{"E": "instantiation_error"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `atomic_list_concat([a, f(b)], ',', Y).`,
+				wantError:   fmt.Errorf("atomic_list_concat/3: invalid list element type: *engine.compound, should be atomic"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("atomic_list_concat"), AtomicListConcat)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestSplitString(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `split_string('a.b.c.d', '.', '', L).`,
+				wantResult:  []types.TermResults{{"L": "[a,b,c,d]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `split_string('/home//jan///nice/path', '/', '', L).`,
+				wantResult:  []types.TermResults{{"L": "['',home,'',jan,'','',nice,path]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `split_string('SWI-Prolog, 7.0', ',', ' ', L).`,
+				wantResult:  []types.TermResults{{"L": "['SWI-Prolog','7.0']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `split_string('  a word ', '', ' ', L).`,
+				wantResult:  []types.TermResults{{"L": "['a word']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `split_string('//a//b//', '/', '/', L).`,
+				wantResult:  []types.TermResults{{"L": "[a,b]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `split_string("price=42;volume=7", "=;", "", L).`,
+				wantResult:  []types.TermResults{{"L": "[price,'42',volume,'7']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `split_string('a, ', ',', ' ', L).`,
+				wantResult:  []types.TermResults{{"L": "[a,'']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `split_string('', '', '', L).`,
+				wantResult:  []types.TermResults{{"L": "['']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `split_string(12.34, '.', '', L).`,
+				wantResult:  []types.TermResults{{"L": "['12','34']"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `split_string('a b', ' ', '', [a]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `split_string(S, ',', '', L).`,
+				wantError:   fmt.Errorf("split_string/4: invalid text type: engine.Variable, should be atomic or a list of characters or codes"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register4(engine.NewAtom("split_string"), SplitString)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestStringCase(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `string_lower('UKNOW', Lower).`,
+				wantResult:  []types.TermResults{{"Lower": "uknow"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_upper('0xdeadbeef', Upper).`,
+				wantResult:  []types.TermResults{{"Upper": "'0XDEADBEEF'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_lower('ÉCOLE Ωμέγα ÇA', Lower).`,
+				wantResult:  []types.TermResults{{"Lower": "'école ωμέγα ça'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_upper('école ωμέγα ça', Upper).`,
+				wantResult:  []types.TermResults{{"Upper": "'ÉCOLE ΩΜΈΓΑ ÇA'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_upper('жёлтый', 'ЖЁЛТЫЙ').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_lower("MiXeD", Lower).`,
+				wantResult:  []types.TermResults{{"Lower": "mixed"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_lower(42, Lower).`,
+				wantResult:  []types.TermResults{{"Lower": "'42'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `string_upper(abc, abc).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `string_upper(X, Upper).`,
+				wantError:   fmt.Errorf("string_upper/2: invalid text type: engine.Variable, should be atomic or a list of characters or codes"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("string_lower"), StringLower)
+						interpreter.Register2(engine.NewAtom("string_upper"), StringUpper)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestUTF8Bytes(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `utf8_bytes('héllo', Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[104,195,169,108,108,111]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `utf8_bytes('', Bytes).`,
+				wantResult:  []types.TermResults{{"Bytes": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `utf8_bytes(Atom, [104, 195, 169, 108, 108, 111]).`,
+				wantResult:  []types.TermResults{{"Atom": "héllo"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `utf8_bytes(Atom, []).`,
+				wantResult:  []types.TermResults{{"Atom": "''"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `utf8_bytes(foo, [102, 111, 111]).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `utf8_bytes(foo, [102, 111]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `utf8_bytes(Atom, [104, 195]).`,
+				wantError:   fmt.Errorf("utf8_bytes/2: invalid UTF-8 byte sequence"),
+				wantSuccess: false,
+			},
+			{
+				query:       `utf8_bytes(Atom, [104, 300]).`,
+				wantError:   fmt.Errorf("utf8_bytes/2: failed convert list into bytes: invalid integer value in list at position 2: 300 is out of byte range (0-255)"),
+				wantSuccess: false,
+			},
+			{
+				query:       `utf8_bytes(42, Bytes).`,
+				wantError:   fmt.Errorf("utf8_bytes/2: invalid atom type: engine.Integer, should be Atom or Variable"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("utf8_bytes"), UTF8Bytes)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestSubAtom(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query: `sub_atom(abc, B, L, A, Sub).`,
+				wantResult: []types.TermResults{
+					{"B": "0", "L": "0", "A": "3", "Sub": "''"},
+					{"B": "0", "L": "1", "A": "2", "Sub": "a"},
+					{"B": "0", "L": "2", "A": "1", "Sub": "ab"},
+					{"B": "0", "L": "3", "A": "0", "Sub": "abc"},
+					{"B": "1", "L": "0", "A": "2", "Sub": "''"},
+					{"B": "1", "L": "1", "A": "1", "Sub": "b"},
+					{"B": "1", "L": "2", "A": "0", "Sub": "bc"},
+					{"B": "2", "L": "0", "A": "1", "Sub": "''"},
+					{"B": "2", "L": "1", "A": "0", "Sub": "c"},
+					{"B": "3", "L": "0", "A": "0", "Sub": "''"},
+				},
+				wantSuccess: true,
+			},
+			{
+				query: `sub_atom(abcab, B, L, A, ab).`,
+				wantResult: []types.TermResults{
+					{"B": "0", "L": "2", "A": "3"},
+					{"B": "3", "L": "2", "A": "0"},
+				},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom(aaa, B, _, _, aa).`,
+				wantResult:  []types.TermResults{{"B": "0"}, {"B": "1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom(ab, B, _, _, '').`,
+				wantResult:  []types.TermResults{{"B": "0"}, {"B": "1"}, {"B": "2"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom(hello_world, 6, 5, A, Sub).`,
+				wantResult:  []types.TermResults{{"A": "0", "Sub": "world"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom(hello_world, B, 5, 0, Sub).`,
+				wantResult:  []types.TermResults{{"B": "6", "Sub": "world"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom(hello_world, 2, L, 6, Sub).`,
+				wantResult:  []types.TermResults{{"L": "3", "Sub": "llo"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom(abc, B, 2, _, Sub).`,
+				wantResult:  []types.TermResults{{"B": "0", "Sub": "ab"}, {"B": "1", "Sub": "bc"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom(abc, B, L, 1, Sub).`,
+				wantResult:  []types.TermResults{{"B": "0", "L": "2", "Sub": "ab"}, {"B": "1", "L": "1", "Sub": "b"}, {"B": "2", "L": "0", "Sub": "''"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom(abc, 1, L, _, Sub).`,
+				wantResult:  []types.TermResults{{"L": "0", "Sub": "''"}, {"L": "1", "Sub": "b"}, {"L": "2", "Sub": "bc"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom('héllo', B, L, A, llo).`,
+				wantResult:  []types.TermResults{{"B": "2", "L": "3", "A": "0"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom(abc, 1, _, _, bc).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom(abc, 2, _, _, bc).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `sub_atom(abc, _, 1, _, bc).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `sub_atom(abc, 4, _, _, _).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `sub_atom(abc, B, 2, 2, _).`,
+				wantSuccess: false,
+			},
+			{
+				query:       fmt.Sprintf(`sub_atom('%sneedle%s', B, _, A, needle).`, strings.Repeat("x", 100000), strings.Repeat("y", 100000)),
+				wantResult:  []types.TermResults{{"B": "100000", "A": "100000"}},
+				wantSuccess: true,
+			},
+			{
+				query:       fmt.Sprintf(`sub_atom('%s', 99990, 3, A, Sub).`, strings.Repeat("x", 100000)),
+				wantResult:  []types.TermResults{{"A": "7", "Sub": "xxx"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(sub_atom(_, _, _, _, _), error(E, _), R = caught).`,
+				wantResult:  []types.TermResults{{"R": "caught", "E": "instantiation_error"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(sub_atom(abc, -1, _, _, _), error(E, _), R = caught).`,
+				wantResult:  []types.TermResults{{"R": "caught", "E": "domain_error(not_less_than_zero,-1)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(sub_atom(abc, _, foo, _, _), error(E, _), R = caught).`,
+				wantResult:  []types.TermResults{{"R": "caught", "E": "type_error(integer,foo)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(sub_atom(abc, _, _, _, 42), error(E, _), R = caught).`,
+				wantResult:  []types.TermResults{{"R": "caught", "E": "type_error(atom,42)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(sub_atom(f(x), _, _, _, _), error(E, _), R = caught).`,
+				wantResult:  []types.TermResults{{"R": "caught", "E": "type_error(atom,f(x))"}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register5(engine.NewAtom("sub_atom"), SubAtom)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestSubAtomIcasechk(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `sub_atom_icasechk('Hello World', S, world).`,
+				wantResult:  []types.TermResults{{"S": "6"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom_icasechk('Héllo HÉLLO', S, 'LLO').`,
+				wantResult:  []types.TermResults{{"S": "2"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom_icasechk('Héllo HÉLLO', 8, 'llo').`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom_icasechk('Hello World', 0, world).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `sub_atom_icasechk('Hello World', 20, world).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `sub_atom_icasechk('Hello World', _, planet).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `sub_atom_icasechk(abc, S, '').`,
+				wantResult:  []types.TermResults{{"S": "0"}},
+				wantSuccess: true,
+			},
+			{
+				query:       fmt.Sprintf(`sub_atom_icasechk('%sNeEdLe', S, needle).`, strings.Repeat("x", 100000)),
+				wantResult:  []types.TermResults{{"S": "100000"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `sub_atom_icasechk(42, _, foo).`,
+				wantError:   fmt.Errorf("sub_atom_icasechk/3: invalid atom type: engine.Integer, should be Atom"),
+				wantSuccess: false,
+			},
+			{
+				query:       `sub_atom_icasechk(foo, _, X).`,
+				wantError:   fmt.Errorf("sub_atom_icasechk/3: invalid sub atom type: engine.Variable, should be Atom"),
+				wantSuccess: false,
+			},
+			{
+				query:       `sub_atom_icasechk(foo, bar, foo).`,
+				wantError:   fmt.Errorf("sub_atom_icasechk/3: invalid start type: engine.Atom, should be Integer or Variable"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("sub_atom_icasechk"), SubAtomIcasechk)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,499 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+var (
+	// AtomError are terms with principal functor error/2.
+	// It is used to represent ISO error terms, such as error(syntax_error(Message), Context).
+	AtomError = engine.NewAtom("error")
+
+	// AtomSyntaxError are terms with principal functor syntax_error/1.
+	// It is used to represent the formal part of the errors raised when parsing a term.
+	AtomSyntaxError = engine.NewAtom("syntax_error")
+)
+
+var (
+	readTermFromAtomIndicator = engine.NewAtom("/").Apply(engine.NewAtom("read_term_from_atom"), engine.Integer(3))
+	termToAtomIndicator       = engine.NewAtom("/").Apply(engine.NewAtom("term_to_atom"), engine.Integer(2))
+)
+
+// ReadTermFromAtom is a predicate which parses an atom into a term, using the operators defined in the VM.
+//
+// The signature is as follows:
+//
+//	read_term_from_atom(+Atom, -Term, +Options) is det
+//
+// Where:
+//   - Atom is the textual representation of the term to parse, with or without its terminating full stop.
+//   - Term is the parsed term.
+//   - Options are additional configurations for the parsing. Supported options include: ground(+Bool) which, when
+//     true, rejects the terms holding variables (false by default), max_depth(+N) which specifies the maximum nesting
+//     depth of compounds allowed in the term (unbounded by default), where the elements of a list count as one level,
+//     and variable_names(-Vars) which unifies Vars with the list of the Name = Var pairs of the named variables of the
+//     term.
+//
+// The predicate is intended to ingest untrusted data: the text must hold exactly one term, and a syntax error, as well
+// as a term violating the ground or max_depth restrictions, raises a catchable error(syntax_error(Message), Context)
+// exception rather than aborting the query.
+//
+// Examples:
+//
+//	# Parse a term.
+//	- read_term_from_atom('foo(X, bar)', Term, []).
+//
+//	# Parse an untrusted term, only accepting ground terms of limited depth.
+//	- catch(read_term_from_atom(Data, Term, [ground(true), max_depth(5)]), error(syntax_error(_), _), fail).
+func ReadTermFromAtom(vm *engine.VM, atom, term, options engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		text, ok := env.Resolve(atom).(engine.Atom)
+		if !ok {
+			return engine.Error(fmt.Errorf("read_term_from_atom/3: invalid atom type: %T, should be Atom", env.Resolve(atom)))
+		}
+		opts, err := newReadTermFromAtomOptions(options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("read_term_from_atom/3: %w", err))
+		}
+
+		t, vars, err := parseTermFromAtom(vm, text)
+		if err != nil {
+			return syntaxError(err.Error(), readTermFromAtomIndicator, env)
+		}
+		if opts.ground && len(vars) > 0 {
+			return syntaxError(fmt.Sprintf("variable %s not allowed in ground term", vars[0].Name), readTermFromAtomIndicator, env)
+		}
+		if opts.maxDepth >= 0 && termDepthExceeds(t, opts.maxDepth, env) {
+			return syntaxError(fmt.Sprintf("maximum depth of %d exceeded", opts.maxDepth), readTermFromAtomIndicator, env)
+		}
+
+		pairs := make([]engine.Term, 0, len(vars))
+		for _, v := range vars {
+			pairs = append(pairs, engine.NewAtom("=").Apply(v.Name, v.Variable))
+		}
+
+		return engine.Unify(vm, Tuple(term, opts.variableNames), Tuple(t, engine.List(pairs...)), cont, env)
+	})
+}
+
+// TermToAtom is a predicate which converts a term into its textual representation, and the other way around.
+//
+// The signature is as follows:
+//
+//	term_to_atom(?Term, ?Atom) is det
+//
+// Where:
+//   - Term is the term to convert.
+//   - Atom is the textual representation of Term.
+//
+// If Atom is bound, it is parsed as with read_term_from_atom/3 and the resulting term is unified with Term, a syntax
+// error raising a catchable error(syntax_error(Message), Context) exception. Otherwise, Term is written in its
+// canonical form: atoms are quoted where needed, operators are written according to the operators defined in the VM,
+// and the variables are named _0, _1, ... in the order of their first occurrence in Term. As it only depends on the
+// term and the program, the textual representation is the same on all the nodes, which makes it suitable to be hashed
+// or signed. A cyclic Term, which has no textual representation, raises a type_error(acyclic_term, _).
+//
+// Examples:
+//
+//	# Convert a term into an atom.
+//	- term_to_atom(foo(X, 'Bar', [1, 2]), Atom).
+//
+//	# Convert an atom into a term.
+//	- term_to_atom(Term, 'foo(X, bar)').
+func TermToAtom(vm *engine.VM, term, atom engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		switch a := env.Resolve(atom).(type) {
+		case engine.Atom:
+			t, _, err := parseTermFromAtom(vm, a)
+			if err != nil {
+				return syntaxError(err.Error(), termToAtomIndicator, env)
+			}
+			return engine.Unify(vm, term, t, cont, env)
+		case engine.Variable:
+			if _, ok := env.Resolve(term).(engine.Variable); ok {
+				return engine.Error(fmt.Errorf("term_to_atom/2: at least one of Term or Atom should be bound"))
+			}
+		default:
+			return engine.Error(fmt.Errorf("term_to_atom/2: invalid atom type: %T, should be Atom or Variable", a))
+		}
+		if err := acyclicTermError(term, env); err != nil {
+			return engine.Error(err)
+		}
+
+		vars := termVariables(term, env)
+		names := make([]engine.Term, 0, len(vars))
+		for i, v := range vars {
+			names = append(names, engine.NewAtom("=").Apply(engine.NewAtom(fmt.Sprintf("_%d", i)), v))
+		}
+		options := engine.List(
+			engine.NewAtom("quoted").Apply(AtomTrue),
+			engine.NewAtom("variable_names").Apply(engine.List(names...)),
+		)
+
+		var sb strings.Builder
+		return engine.WriteTerm(vm, engine.NewOutputTextStream(&sb), term, options, func(env *engine.Env) *engine.Promise {
+			return engine.Unify(vm, atom, engine.NewAtom(sb.String()), cont, env)
+		}, env)
+	})
+}
+
+// Reify is a predicate which runs a goal once and unifies its outcome as a term, so that the failure of the goal can
+// be handled as a value rather than by backtracking.
+//
+// The signature is as follows:
+//
+//	reify(:Goal, -Outcome) is det
+//
+// Where:
+//   - Goal is the goal to run.
+//   - Outcome is unified with true(Bindings) if Goal succeeds, where Bindings is the list of the Var = Value pairs of
+//     the variables of Goal, in the order of their first occurrence, Value being the value of Var in the first solution
+//     of Goal, and with false if Goal fails.
+//
+// Goal is run in isolation, as with findall/3: its bindings are only given by Bindings and are not applied to the
+// variables of Goal, which can be bound by calling the pairs of Bindings, e.g. with maplist(call, Bindings). The
+// errors raised by Goal are propagated, and a Value which is a cyclic term raises a type_error(acyclic_term, _).
+//
+// Examples:
+//
+//	# Run a goal and get the value of its variables.
+//	- reify(member(X, [1, 2]), true([X = V])).
+//
+//	# Reify the failure of a goal.
+//	- reify(member(3, [1, 2]), false).
+func Reify(vm *engine.VM, goal, outcome engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		if err := acyclicTermError(goal, env); err != nil {
+			return engine.Error(err)
+		}
+		vars := termVariables(goal, env)
+		var bindings []engine.Term
+		cyclic := false
+		ok, err := engine.Call(vm, goal, func(solution *engine.Env) *engine.Promise {
+			bindings = make([]engine.Term, 0, len(vars))
+			for _, v := range vars {
+				if cyclic = isCyclicTerm(v, solution); cyclic {
+					break
+				}
+				bindings = append(bindings, engine.NewAtom("=").Apply(v, resolveTerm(v, solution)))
+			}
+			return engine.Bool(true)
+		}, env).Force(ctx)
+		if err != nil {
+			return engine.Error(err)
+		}
+		if cyclic {
+			return engine.Error(cyclicTermError(env))
+		}
+		if !ok {
+			return engine.Unify(vm, outcome, AtomFalse, cont, env)
+		}
+
+		return engine.Unify(vm, outcome, AtomTrue.Apply(engine.List(bindings...)), cont, env)
+	})
+}
+
+// FindAll4 is a predicate which collects the instances of a template for all the solutions of a goal, as findall/3
+// does, in front of a given tail.
+//
+// The signature is as follows:
+//
+//	findall(+Template, :Goal, -Bag, ?Tail) is det
+//
+// Where:
+//   - Template is the term to instantiate for each solution of Goal.
+//   - Goal is the goal to run.
+//   - Bag is the list of the instances of Template for the solutions of Goal, in the order of the solutions, followed
+//     by Tail, i.e. Tail itself if Goal has no solution.
+//   - Tail is the rest of Bag.
+//
+// As Bag is a difference list, the results of several goals can be accumulated without appending them, e.g. with
+// findall(X, G1, Bag, Tail1), findall(Y, G2, Tail1, []). The errors raised by Goal are propagated, and a Bag which is
+// neither a list nor a partial list raises a type_error(list, Bag).
+//
+// Examples:
+//
+//	# Collect the solutions of two goals into the same list.
+//	- findall(X, member(X, [1, 2]), Bag, Tail), findall(Y, member(Y, [3]), Tail, []).
+func FindAll4(vm *engine.VM, template, goal, bag, tail engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	iter := engine.ListIterator{List: bag, Env: env, AllowPartial: true}
+	for iter.Next() {
+	}
+	if err := iter.Err(); err != nil {
+		return engine.Error(err)
+	}
+
+	instances := engine.NewVariable()
+	return engine.FindAll(vm, template, goal, instances, func(env *engine.Env) *engine.Promise {
+		elems, err := listElements(instances, env)
+		if err != nil {
+			return engine.Error(err)
+		}
+		return engine.Unify(vm, bag, engine.PartialList(tail, elems...), cont, env)
+	}, env)
+}
+
+// Substitute is a predicate which replaces the subterms of a term according to a substitution map, e.g. to expand
+// the placeholders of a template.
+//
+// The signature is as follows:
+//
+//	substitute(+Term, +Substitutions, -Result) is det
+//
+// Where:
+//   - Term is the term to apply the substitutions to.
+//   - Substitutions is the list of the From-To pairs of the substitutions, where From is the subterm to replace and To
+//     its replacement.
+//   - Result is Term with every occurrence of each From replaced by its To.
+//
+// The subterms are compared by the standard order of terms, so that a From only matches the subterms identical to it,
+// a variable only matching itself. The substitutions are applied bottom-up: the arguments of a compound are
+// substituted before the compound itself, which is then compared with the result, so that nested substitutions
+// compose. The replacements are not substituted again. When several pairs have the same From, the first one applies.
+// A cyclic Term or Substitutions raises a type_error(acyclic_term, _).
+//
+// Examples:
+//
+//	# Expand the placeholders of a template.
+//	- substitute(greet(name, [title, name]), [name-'Alice', title-'Dr'], Result).
+//
+//	# Compose nested substitutions.
+//	- substitute(f(g(a)), [a-b, g(b)-c], Result).
+func Substitute(vm *engine.VM, term, substitutions, result engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		if err := acyclicTermError(Tuple(term, substitutions), env); err != nil {
+			return engine.Error(err)
+		}
+		subs := make([]engine.Compound, 0)
+		iter := engine.ListIterator{List: substitutions, Env: env}
+		for iter.Next() {
+			pair, ok := env.Resolve(iter.Current()).(engine.Compound)
+			if !ok || pair.Functor() != AtomPair || pair.Arity() != 2 {
+				return engine.Error(fmt.Errorf("substitute/3: invalid substitution type: %T, should be From-To",
+					env.Resolve(iter.Current())))
+			}
+			subs = append(subs, pair)
+		}
+		if err := iter.Err(); err != nil {
+			return engine.Error(fmt.Errorf("substitute/3: invalid substitutions: %w", err))
+		}
+
+		return engine.Unify(vm, result, substituteTerm(term, subs, env), cont, env)
+	})
+}
+
+// readTermFromAtomOptions holds the options of read_term_from_atom/3.
+type readTermFromAtomOptions struct {
+	ground        bool
+	maxDepth      int
+	variableNames engine.Term
+}
+
+// newReadTermFromAtomOptions extracts the options of read_term_from_atom/3 from the given options.
+func newReadTermFromAtomOptions(options engine.Term, env *engine.Env) (*readTermFromAtomOptions, error) {
+	opts := &readTermFromAtomOptions{maxDepth: -1, variableNames: engine.NewVariable()}
+
+	ground, err := util.GetOptionWithDefault(engine.NewAtom("ground"), options, AtomFalse, env)
+	if err != nil {
+		return nil, err
+	}
+	switch env.Resolve(ground) {
+	case AtomTrue:
+		opts.ground = true
+	case AtomFalse:
+	default:
+		return nil, fmt.Errorf("invalid ground option: %v, valid values are 'true' or 'false'", env.Resolve(ground))
+	}
+
+	maxDepth, err := util.GetOption(engine.NewAtom("max_depth"), options, env)
+	if err != nil {
+		return nil, err
+	}
+	if maxDepth != nil {
+		n, ok := env.Resolve(maxDepth).(engine.Integer)
+		if !ok || n < 0 {
+			return nil, fmt.Errorf("invalid max_depth option: %v, should be a non-negative Integer", env.Resolve(maxDepth))
+		}
+		opts.maxDepth = int(n)
+	}
+
+	variableNames, err := util.GetOption(engine.NewAtom("variable_names"), options, env)
+	if err != nil {
+		return nil, err
+	}
+	if variableNames != nil {
+		opts.variableNames = variableNames
+	}
+
+	return opts, nil
+}
+
+// parseTermFromAtom parses the given atom, which must hold exactly one term with or without its terminating full stop,
+// using the operators defined in the VM. It returns the parsed term along with its named variables.
+func parseTermFromAtom(vm *engine.VM, atom engine.Atom) (engine.Term, []engine.ParsedVariable, error) {
+	input := strings.TrimSpace(atom.String())
+	if !strings.HasSuffix(input, ".") {
+		input += " ."
+	}
+
+	p := engine.NewParser(vm, strings.NewReader(input))
+	t, err := p.Term()
+	if err != nil {
+		return nil, nil, err
+	}
+	if p.More() {
+		return nil, nil, fmt.Errorf("unexpected data after term")
+	}
+
+	return t, p.Vars, nil
+}
+
+// syntaxError returns a promise raising the error(syntax_error(Message), Context) exception, where Context is the
+// indicator of the predicate raising it.
+func syntaxError(message string, indicator engine.Term, env *engine.Env) *engine.Promise {
+	return engine.Error(engine.NewException(AtomError.Apply(AtomSyntaxError.Apply(engine.NewAtom(message)), indicator), env))
+}
+
+// termDepthExceeds checks whether the nesting depth of the compounds of the given term exceeds the given maximum,
+// the elements of a list being considered as one level of nesting.
+//
+// The term is explored no deeper than the given maximum, so the check is bounded whatever the size of the term.
+func termDepthExceeds(t engine.Term, maxDepth int, env *engine.Env) bool {
+	c, ok := env.Resolve(t).(engine.Compound)
+	if !ok {
+		return false
+	}
+	if maxDepth == 0 {
+		return true
+	}
+
+	if util.IsList(c) {
+		iter := engine.ListIterator{List: c, Env: env}
+		for iter.Next() {
+			if termDepthExceeds(iter.Current(), maxDepth-1, env) {
+				return true
+			}
+		}
+		if iter.Err() == nil {
+			return false
+		}
+	}
+
+	for i := 0; i < c.Arity(); i++ {
+		if termDepthExceeds(c.Arg(i), maxDepth-1, env) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isCyclicTerm reports whether the given term is cyclic, i.e. whether it holds itself through the bindings of its
+// variables, e.g. X once X = f(X) is unified. The term is walked iteratively, each bound variable being expanded once,
+// so that the walk terminates on any term, unlike the walks of the engine.
+func isCyclicTerm(t engine.Term, env *engine.Env) bool {
+	type frame struct {
+		term engine.Term
+		exit engine.Variable
+	}
+	const (
+		expanding = iota + 1
+		expanded
+	)
+
+	states := make(map[engine.Variable]int)
+	stack := []frame{{term: t}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if f.term == nil {
+			states[f.exit] = expanded
+			continue
+		}
+
+		t := f.term
+		if v, ok := t.(engine.Variable); ok {
+			t = env.Resolve(v)
+			if _, ok := t.(engine.Compound); !ok {
+				continue
+			}
+			switch states[v] {
+			case expanding:
+				return true
+			case expanded:
+				continue
+			}
+			states[v] = expanding
+			stack = append(stack, frame{exit: v})
+		}
+		if c, ok := t.(engine.Compound); ok {
+			for i := c.Arity() - 1; i >= 0; i-- {
+				stack = append(stack, frame{term: c.Arg(i)})
+			}
+		}
+	}
+
+	return false
+}
+
+// termVariables returns the distinct variables of the given term, in the order of their first occurrence.
+func termVariables(t engine.Term, env *engine.Env) []engine.Variable {
+	vars := make([]engine.Variable, 0)
+	seen := make(map[engine.Variable]struct{})
+	stack := []engine.Term{t}
+	for len(stack) > 0 {
+		t, stack = env.Resolve(stack[len(stack)-1]), stack[:len(stack)-1]
+		switch t := t.(type) {
+		case engine.Variable:
+			if _, ok := seen[t]; !ok {
+				seen[t] = struct{}{}
+				vars = append(vars, t)
+			}
+		case engine.Compound:
+			for i := t.Arity() - 1; i >= 0; i-- {
+				stack = append(stack, t.Arg(i))
+			}
+		}
+	}
+
+	return vars
+}
+
+// resolveTerm returns the given term with all its bound variables replaced by their value in the given environment.
+func resolveTerm(t engine.Term, env *engine.Env) engine.Term {
+	c, ok := env.Resolve(t).(engine.Compound)
+	if !ok {
+		return env.Resolve(t)
+	}
+
+	args := make([]engine.Term, c.Arity())
+	for i := range args {
+		args[i] = resolveTerm(c.Arg(i), env)
+	}
+	return c.Functor().Apply(args...)
+}
+
+// substituteTerm replaces the subterms of the given term matching the From of a substitution with its To, bottom-up.
+func substituteTerm(t engine.Term, subs []engine.Compound, env *engine.Env) engine.Term {
+	t = env.Resolve(t)
+	if c, ok := t.(engine.Compound); ok {
+		args := make([]engine.Term, c.Arity())
+		for i := range args {
+			args[i] = substituteTerm(c.Arg(i), subs, env)
+		}
+		t = c.Functor().Apply(args...)
+	}
+
+	for _, sub := range subs {
+		if sub.Arg(0).Compare(t, env) == 0 {
+			return sub.Arg(1)
+		}
+	}
+	return t
+}
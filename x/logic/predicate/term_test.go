@@ -0,0 +1,653 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestReadTermFromAtom(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `read_term_from_atom('foo(bar, [1, 2], "baz")', T, []).`,
+				wantResult:  []types.TermResults{{"T": "foo(bar,[1,2],[b,a,z])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_term_from_atom('foo(bar).', T, []).`,
+				wantResult:  []types.TermResults{{"T": "foo(bar)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_term_from_atom('X = f(Y, X)', T, [variable_names(Vars)]).`,
+				wantResult:  []types.TermResults{{"T": "_1=f(_2,_3)", "Vars": "['X'=_1,'Y'=_2]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `:-(op(700, xfx, ===>)).`,
+				query:       `read_term_from_atom('a ===> b', T, []), T == ===>(a, b).`,
+				wantResult:  []types.TermResults{{"T": "a===>b"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_term_from_atom('foo(bar)', foo(X), []).`,
+				wantResult:  []types.TermResults{{"X": "bar"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_term_from_atom('foo(bar)', baz, []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `read_term_from_atom('foo(bar, [baz])', T, [ground(true), max_depth(2)]).`,
+				wantResult:  []types.TermResults{{"T": "foo(bar,[baz])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(read_term_from_atom('foo(X)', T, [ground(true)]), E, R = caught).`,
+				wantResult:  []types.TermResults{{"T": "_1", "R": "caught", "E": "error(syntax_error('variable X not allowed in ground term'),/(read_term_from_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(read_term_from_atom('foo(bar(baz))', T, [max_depth(1)]), E, R = caught).`,
+				wantResult:  []types.TermResults{{"T": "_1", "R": "caught", "E": "error(syntax_error('maximum depth of 1 exceeded'),/(read_term_from_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(read_term_from_atom('[a, [b]]', T, [max_depth(1)]), E, R = caught).`,
+				wantResult:  []types.TermResults{{"T": "_1", "R": "caught", "E": "error(syntax_error('maximum depth of 1 exceeded'),/(read_term_from_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(read_term_from_atom('foo(', T, []), E, R = caught).`,
+				wantResult:  []types.TermResults{{"T": "_1", "R": "caught", "E": "error(syntax_error('unexpected token: end(.)'),/(read_term_from_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(read_term_from_atom('foo. bar.', T, []), E, R = caught).`,
+				wantResult:  []types.TermResults{{"T": "_1", "R": "caught", "E": "error(syntax_error('unexpected data after term'),/(read_term_from_atom,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(read_term_from_atom('foo(', T, []), error(syntax_error(_), _), R = caught).`,
+				wantResult:  []types.TermResults{{"T": "_1", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `read_term_from_atom(42, T, []).`,
+				wantError:   fmt.Errorf("read_term_from_atom/3: invalid atom type: engine.Integer, should be Atom"),
+				wantSuccess: false,
+			},
+			{
+				query:       `read_term_from_atom(foo, T, [ground(maybe)]).`,
+				wantError:   fmt.Errorf("read_term_from_atom/3: invalid ground option: maybe, valid values are 'true' or 'false'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `read_term_from_atom(foo, T, [max_depth(-1)]).`,
+				wantError:   fmt.Errorf("read_term_from_atom/3: invalid max_depth option: -1, should be a non-negative Integer"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("read_term_from_atom"), ReadTermFromAtom)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestTermToAtom(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `term_to_atom(foo(bar, 'Baz', "qux", [1, 2.5]), A).`,
+				wantResult:  []types.TermResults{{"A": "'foo(bar,\\'Baz\\',[q,u,x],[1,2.5])'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `term_to_atom(f(X, Y, X), A), A == 'f(_0,_1,_0)'.`,
+				wantResult:  []types.TermResults{{"X": "_1", "Y": "_1", "A": "'f(_1,_2,_3)'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `term_to_atom('hello world', A).`,
+				wantResult:  []types.TermResults{{"A": "'\\'hello world\\''"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `:-(op(700, xfx, ===>)).`,
+				query:       `term_to_atom(===>(a, f(b)), A), term_to_atom(T, A).`,
+				wantResult:  []types.TermResults{{"A": "'a===>f(b)'", "T": "a===>f(b)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `term_to_atom(T, 'foo(X, bar, "baz")').`,
+				wantResult:  []types.TermResults{{"T": "foo(_1,bar,[b,a,z])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `term_to_atom(foo(X), 'foo(bar)').`,
+				wantResult:  []types.TermResults{{"X": "bar"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `term_to_atom(foo(bar), 'foo(baz)').`,
+				wantSuccess: false,
+			},
+			{
+				query:       `term_to_atom(T, 'foo(bar, \'Baz\')'), term_to_atom(T, A).`,
+				wantResult:  []types.TermResults{{"T": "foo(bar,'Baz')", "A": "'foo(bar,\\'Baz\\')'"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(term_to_atom(T, 'foo('), E, R = caught).`,
+				wantResult:  []types.TermResults{{"T": "_1", "R": "caught", "E": "error(syntax_error('unexpected token: end(.)'),/(term_to_atom,2))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch((T = f(T), term_to_atom(T, A)), E, R = caught).`,
+				wantResult:  []types.TermResults{{"T": "_1", "A": "_1", "R": "caught", "E": "error(type_error(acyclic_term,_1),/(term_to_atom,2))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `term_to_atom(T, A).`,
+				wantError:   fmt.Errorf("term_to_atom/2: at least one of Term or Atom should be bound"),
+				wantSuccess: false,
+			},
+			{
+				query:       `term_to_atom(T, 42).`,
+				wantError:   fmt.Errorf("term_to_atom/2: invalid atom type: engine.Integer, should be Atom or Variable"),
+				wantSuccess: false,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("term_to_atom"), TermToAtom)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestReify(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				program:     `p(1). p(2).`,
+				query:       `reify(p(X), Outcome).`,
+				wantResult:  []types.TermResults{{"X": "_1", "Outcome": "true([_1=1])"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `p(1). p(2).`,
+				query:       `reify((p(X), p(Y)), true([X = V, Y = W])).`,
+				wantResult:  []types.TermResults{{"X": "_1", "Y": "_1", "V": "1", "W": "1"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `p(1). p(2).`,
+				query:       `reify(p(3), Outcome).`,
+				wantResult:  []types.TermResults{{"Outcome": "false"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `p(1). p(2).`,
+				query:       `reify(p(X), false).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `catch(reify(X = f(X), Outcome), E, R = caught).`,
+				wantResult:  []types.TermResults{{"X": "_1", "Outcome": "_1", "E": "error(type_error(acyclic_term,_1),/(reify,2))", "R": "caught"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(reify(Goal, Outcome), E, R = caught).`,
+				wantResult:  []types.TermResults{{"Goal": "_1", "Outcome": "_1", "E": "error(instantiation_error,/(reify,2))", "R": "caught"}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("reify"), Reify)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestFindAll4(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				program:     `p(1). p(2).`,
+				query:       `findall(X, p(X), Bag, Tail).`,
+				wantResult:  []types.TermResults{{"X": "_1", "Bag": "[1,2|_1]", "Tail": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `p(1). p(2).`,
+				query:       `findall(X-Y, p(X), Bag, [end]).`,
+				wantResult:  []types.TermResults{{"X": "_1", "Y": "_1", "Bag": "[1-_1,2-_2,end]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `p(1). p(2). q(a).`,
+				query:       `findall(X, p(X), Bag, Tail), findall(Y, q(Y), Tail, []).`,
+				wantResult:  []types.TermResults{{"X": "_1", "Y": "_1", "Bag": "[1,2,a]", "Tail": "[a]"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `p(1). p(2).`,
+				query:       `findall(X, p(X), [1, 2], []).`,
+				wantResult:  []types.TermResults{{"X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `p(1). p(2).`,
+				query:       `findall(X, p(X), [2, 1], []).`,
+				wantSuccess: false,
+			},
+			{
+				program:     `p(1). p(2).`,
+				query:       `findall(X, p(3), Bag, Tail).`,
+				wantResult:  []types.TermResults{{"X": "_1", "Bag": "_1", "Tail": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				program:     `p(1). p(2).`,
+				query:       `findall(X, p(3), Bag, [a]).`,
+				wantResult:  []types.TermResults{{"X": "_1", "Bag": "[a]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `findall(X, fail, Bag, []).`,
+				wantResult:  []types.TermResults{{"X": "_1", "Bag": "[]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(findall(X, fail, foo, []), error(type_error(list, foo), _), true).`,
+				wantResult:  []types.TermResults{{"X": "_1"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch(findall(X, _, _, []), error(instantiation_error, _), true).`,
+				wantResult:  []types.TermResults{{"X": "_1"}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register4(engine.NewAtom("findall"), FindAll4)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+						interpreter.Register0(engine.NewAtom("fail"), func(_ *engine.VM, _ engine.Cont, _ *engine.Env) *engine.Promise { return engine.Bool(false) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
+
+func TestSubstitute(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `substitute(greet(name, [title, name]), [name-'Alice', title-'Dr'], Result).`,
+				wantResult:  []types.TermResults{{"Result": "greet('Alice',['Dr','Alice'])"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `substitute(f(g(a), h(g(a))), [g(a)-x], Result).`,
+				wantResult:  []types.TermResults{{"Result": "f(x,h(x))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `substitute(f(g(a)), [a-b, g(b)-c], Result).`,
+				wantResult:  []types.TermResults{{"Result": "f(c)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `substitute(f(a), [a-g(a)], Result).`,
+				wantResult:  []types.TermResults{{"Result": "f(g(a))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `substitute(f(X, Y, 1), [X-x, 1.0-one], Result).`,
+				wantResult:  []types.TermResults{{"X": "_1", "Y": "_1", "Result": "f(x,_1,1)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `substitute(f(a), [a-b, a-c], f(b)).`,
+				wantResult:  []types.TermResults{{}},
+				wantSuccess: true,
+			},
+			{
+				query:       `substitute(f(a), [], Result).`,
+				wantResult:  []types.TermResults{{"Result": "f(a)"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `substitute(f(a), [a], Result).`,
+				wantError:   fmt.Errorf("substitute/3: invalid substitution type: engine.Atom, should be From-To"),
+				wantSuccess: false,
+			},
+			{
+				query:       `substitute(f(a), foo, Result).`,
+				wantError:   fmt.Errorf("substitute/3: invalid substitutions: error(type_error(list,foo),substitute/3)"),
+				wantSuccess: false,
+			},
+			{
+				query:       `catch((X = f(X), substitute(X, [a-b], R)), E, true).`,
+				wantResult:  []types.TermResults{{"X": "_1", "R": "_1", "E": "error(type_error(acyclic_term,_1),/(substitute,3))"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `catch((X = f(X), substitute(g(a), [a-X], R)), E, true).`,
+				wantResult:  []types.TermResults{{"X": "_1", "R": "_1", "E": "error(type_error(acyclic_term,_1),/(substitute,3))"}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register3(engine.NewAtom("substitute"), Substitute)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+						interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise { return cont(env) })
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
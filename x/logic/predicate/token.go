@@ -0,0 +1,163 @@
+package predicate
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+var (
+	// AtomValid is the term used to indicate that a signed token is valid.
+	AtomValid = engine.NewAtom("valid")
+
+	// AtomExpired is the term used to indicate that a signed token is authentic but expired.
+	AtomExpired = engine.NewAtom("expired")
+
+	// AtomBadMAC is the term used to indicate that the MAC of a signed token does not match its content.
+	AtomBadMAC = engine.NewAtom("bad_mac")
+)
+
+// signedTokenOptions are the options supported by signed_token_verify/4.
+var signedTokenOptions = []engine.Atom{engine.NewAtom("algorithm"), engine.NewAtom("check_expiry"), engine.NewAtom("reason")}
+
+// SignedTokenVerify is a predicate which verifies a HMAC signed token with an expiry, as used for session cookies.
+//
+// The signature is as follows:
+//
+//	signed_token_verify(+Token, +Key, -Payload, +Options) is semi-det
+//
+// Where:
+//   - Token is the token, as an Atom of the form Payload.Expiry.MAC, where Payload is the base64url encoded payload,
+//     Expiry the time after which the token expires, as the decimal number of seconds since the Unix epoch, and MAC the
+//     base64url encoded HMAC of the Payload.Expiry part of the token (see base64url_bytes/2).
+//   - Key is the secret key of the HMAC, as a list of bytes.
+//   - Payload is the decoded payload of the token, as a list of bytes.
+//   - Options are additional configurations for the verification. Supported options include: algorithm(+Alg) which
+//     specifies the hash algorithm of the HMAC, among sha256 (default), sha384 and sha512, check_expiry(+Bool) which
+//     specifies whether the expiry is checked (default true), and reason(-Reason) (see below for details).
+//
+// A token is expired when the time of the current block is not before its expiry. Without the reason option, the
+// predicate fails if the MAC does not match or if the token is expired. With the reason option, the predicate succeeds
+// and Reason tells these cases apart: it is unified with valid for a valid token, with expired for an authentic but
+// expired token (Payload being still unified), and with bad_mac for a token whose MAC does not match (Payload being
+// left unbound). A malformed token raises an error, as well as an unknown option, which raises a
+// domain_error(option, Option) error.
+//
+// Examples:
+//
+//	# Verify a session token and get its payload.
+//	- signed_token_verify('YWxpY2U.1700000000.p6_Hp9AEzGv5PfsxGD5QbemYfijWGFg48OKEyYKDtiU', [115, 101, 99, 114, 101, 116],
+//	  Payload, [algorithm(sha256)]).
+func SignedTokenVerify(vm *engine.VM, token, key, payload, options engine.Term, cont engine.Cont, env *engine.Env,
+) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		functor := "signed_token_verify/4"
+
+		if err := util.CheckOptions(options, signedTokenOptions, env); err != nil {
+			return engine.Error(err)
+		}
+		reason, err := util.GetOption(engine.NewAtom("reason"), options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+		content, expiry, valid, err := verifySignedToken(ctx, functor, token, key, options, env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("%s: %w", functor, err))
+		}
+
+		status := AtomBadMAC
+		if valid {
+			status = AtomValid
+			checkExpiry, err := util.GetOptionWithDefault(engine.NewAtom("check_expiry"), options, engine.NewAtom("true"), env)
+			if err != nil {
+				return engine.Error(fmt.Errorf("%s: %w", functor, err))
+			}
+			switch env.Resolve(checkExpiry) {
+			case engine.NewAtom("true"):
+				sdkContext, err := util.UnwrapSDKContext(ctx)
+				if err != nil {
+					return engine.Error(fmt.Errorf("%s: %w", functor, err))
+				}
+				if sdkContext.BlockTime().Unix() >= expiry {
+					status = AtomExpired
+				}
+			case engine.NewAtom("false"):
+			default:
+				return engine.Error(fmt.Errorf("%s: invalid check_expiry option: %v, valid values are 'true' or 'false'",
+					functor, env.Resolve(checkExpiry)))
+			}
+		}
+
+		switch {
+		case reason == nil && status != AtomValid:
+			return engine.Bool(false)
+		case reason == nil:
+			return engine.Unify(vm, payload, BytesToList(content), cont, env)
+		case status == AtomBadMAC:
+			return engine.Unify(vm, reason, status, cont, env)
+		default:
+			return engine.Unify(vm, Tuple(payload, reason), Tuple(BytesToList(content), status), cont, env)
+		}
+	})
+}
+
+// verifySignedToken decodes the given signed token and checks its MAC, returning its payload, its expiry and whether
+// the MAC matches.
+func verifySignedToken(ctx context.Context, functor string, token, key, options engine.Term, env *engine.Env,
+) ([]byte, int64, bool, error) {
+	atom, ok := env.Resolve(token).(engine.Atom)
+	if !ok {
+		return nil, 0, false, fmt.Errorf("invalid token type: %T, should be Atom", env.Resolve(token))
+	}
+	if err := checkInputSize(ctx, len(atom.String())); err != nil {
+		return nil, 0, false, err
+	}
+	parts := strings.Split(atom.String(), ".")
+	if len(parts) != 3 {
+		return nil, 0, false, fmt.Errorf("invalid token: should be made of 3 parts separated by dots, got %d", len(parts))
+	}
+
+	content, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("invalid token payload: %w", err)
+	}
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("invalid token expiry: %s, should be an integer", parts[1])
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("invalid token mac: %w", err)
+	}
+
+	secret, err := TermToBytes(ctx, key, AtomEncoding.Apply(AtomOctet), env)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("failed to decode key: %w", err)
+	}
+	alg, err := util.GetOptionWithDefault(engine.NewAtom("algorithm"), options, engine.NewAtom("sha256"), env)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	name, _ := env.Resolve(alg).(engine.Atom)
+	h, ok := rsaHashNames[name.String()]
+	if !ok {
+		return nil, 0, false, fmt.Errorf("invalid algorithm option: %v, valid values are 'sha256', 'sha384' or 'sha512'",
+			env.Resolve(alg))
+	}
+
+	input := atom.String()[:strings.LastIndex(atom.String(), ".")]
+	if err := consumeAlgorithmGas(ctx, functor, "hmac", len(input)); err != nil {
+		return nil, 0, false, err
+	}
+	hasher := hmac.New(h.New, secret)
+	hasher.Write([]byte(input))
+
+	return content, expiry, util.ConstantTimeEqual(hasher.Sum(nil), mac), nil
+}
@@ -0,0 +1,188 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestSignedTokenVerify(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `signed_token_verify('YWxpY2U.1700000000.p6_Hp9AEzGv5PfsxGD5QbemYfijWGFg48OKEyYKDtiU', [115, 101, 99, 114, 101, 116], Payload, []).`,
+				wantResult:  []types.TermResults{{"Payload": "[97,108,105,99,101]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.1700000000.p6_Hp9AEzGv5PfsxGD5QbemYfijWGFg48OKEyYKDtiU', [115, 101, 99, 114, 101, 116], Payload, [algorithm(sha256), check_expiry(true)]).`,
+				wantResult:  []types.TermResults{{"Payload": "[97,108,105,99,101]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.1700000000.a6-DU_fOJdrrRXX2TDYaOLdZ5FOCkUbe-ew-4KIGXpRirKvX4rxuPvSFWuFM3CT770HzEEp1NEEp9an4f0hqkQ', [115, 101, 99, 114, 101, 116], Payload, [algorithm(sha512)]).`,
+				wantResult:  []types.TermResults{{"Payload": "[97,108,105,99,101]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.1700000000.p6_Hp9AEzGv5PfsxGD5QbemYfijWGFg48OKEyYKDtiU', [115, 101, 99, 114, 101, 116], Payload, [reason(Reason)]).`,
+				wantResult:  []types.TermResults{{"Payload": "[97,108,105,99,101]", "Reason": "valid"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.1600000000.sl2MGaZAT8cFgb1XclTknznY0bE7sL6aSVMSnpCEJ7s', [115, 101, 99, 114, 101, 116], Payload, []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.1600000000.sl2MGaZAT8cFgb1XclTknznY0bE7sL6aSVMSnpCEJ7s', [115, 101, 99, 114, 101, 116], Payload, [reason(Reason)]).`,
+				wantResult:  []types.TermResults{{"Payload": "[97,108,105,99,101]", "Reason": "expired"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.1600000000.sl2MGaZAT8cFgb1XclTknznY0bE7sL6aSVMSnpCEJ7s', [115, 101, 99, 114, 101, 116], Payload, [check_expiry(false)]).`,
+				wantResult:  []types.TermResults{{"Payload": "[97,108,105,99,101]"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `signed_token_verify('Ym9i.1700000000.p6_Hp9AEzGv5PfsxGD5QbemYfijWGFg48OKEyYKDtiU', [115, 101, 99, 114, 101, 116], Payload, []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `signed_token_verify('Ym9i.1700000000.p6_Hp9AEzGv5PfsxGD5QbemYfijWGFg48OKEyYKDtiU', [115, 101, 99, 114, 101, 116], Payload, [reason(Reason)]).`,
+				wantResult:  []types.TermResults{{"Payload": "_1", "Reason": "bad_mac"}},
+				wantSuccess: true,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.1700000000.p6_Hp9AEzGv5PfsxGD5QbemYfijWGFg48OKEyYKDtiU', [115, 101, 99, 114, 101, 115], Payload, []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.1700000000.a6-DU_fOJdrrRXX2TDYaOLdZ5FOCkUbe-ew-4KIGXpRirKvX4rxuPvSFWuFM3CT770HzEEp1NEEp9an4f0hqkQ', [115, 101, 99, 114, 101, 116], Payload, []).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.1700000000.p6_Hp9AEzGv5PfsxGD5QbemYfijWGFg48OKEyYKDtiU', [115, 101, 99, 114, 101, 116], Payload, [reason(expired)]).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `signed_token_verify(foo, [115, 101, 99, 114, 101, 116], Payload, []).`,
+				wantError:   fmt.Errorf("signed_token_verify/4: invalid token: should be made of 3 parts separated by dots, got 1"),
+				wantSuccess: false,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.soon.p6_Hp9AEzGv5PfsxGD5QbemYfijWGFg48OKEyYKDtiU', [115, 101, 99, 114, 101, 116], Payload, []).`,
+				wantError:   fmt.Errorf("signed_token_verify/4: invalid token expiry: soon, should be an integer"),
+				wantSuccess: false,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U=.1700000000.p6_Hp9AEzGv5PfsxGD5QbemYfijWGFg48OKEyYKDtiU', [115, 101, 99, 114, 101, 116], Payload, []).`,
+				wantError:   fmt.Errorf("signed_token_verify/4: invalid token payload: illegal base64 data at input byte 7"),
+				wantSuccess: false,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.1700000000.!', [115, 101, 99, 114, 101, 116], Payload, []).`,
+				wantError:   fmt.Errorf("signed_token_verify/4: invalid token mac: illegal base64 data at input byte 0"),
+				wantSuccess: false,
+			},
+			{
+				query:       `signed_token_verify(X, [115, 101, 99, 114, 101, 116], Payload, []).`,
+				wantError:   fmt.Errorf("signed_token_verify/4: invalid token type: engine.Variable, should be Atom"),
+				wantSuccess: false,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.1700000000.p6_Hp9AEzGv5PfsxGD5QbemYfijWGFg48OKEyYKDtiU', [115, 101, 99, 114, 101, 116], Payload, [algorithm(md5)]).`,
+				wantError:   fmt.Errorf("signed_token_verify/4: invalid algorithm option: md5, valid values are 'sha256', 'sha384' or 'sha512'"),
+				wantSuccess: false,
+			},
+			{
+				query:       `signed_token_verify('YWxpY2U.1700000000.p6_Hp9AEzGv5PfsxGD5QbemYfijWGFg48OKEyYKDtiU', [115, 101, 99, 114, 101, 116], Payload, [check_expiry(yes)]).`,
+				wantError:   fmt.Errorf("signed_token_verify/4: invalid check_expiry option: yes, valid values are 'true' or 'false'"),
+				wantSuccess: false,
+			},
+			{
+				query: `catch(signed_token_verify(foo, [], Payload, [check_expiry(true), expiry(true)]), E, R = caught).`,
+				wantResult: []types.TermResults{{
+					"Payload": "_1", "E": "error(domain_error(option,expiry(true)),/(signed_token_verify,4))", "R": "caught",
+				}},
+				wantSuccess: true,
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{Time: time.Unix(1650000000, 0)}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register4(engine.NewAtom("signed_token_verify"), SignedTokenVerify)
+						interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
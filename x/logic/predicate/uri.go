@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"regexp"
+	"strings"
 
 	"github.com/ichiban/prolog/engine"
 
@@ -21,6 +23,15 @@ const (
 
 const upperhex = "0123456789ABCDEF"
 
+// AtomURIComponents is a term which represents the components of an URI as a compound term
+// `uri_components(Scheme, Authority, Path, Search, Fragment)`.
+var AtomURIComponents = engine.NewAtom("uri_components")
+
+// uriRegexp is the regular expression given in [RFC 3986] Appendix B to break down a URI reference into its components.
+//
+// [RFC 3986]: https://www.rfc-editor.org/rfc/rfc3986#appendix-B
+var uriRegexp = regexp.MustCompile(`^(([^:/?#]+):)?(//([^/?#]*))?([^?#]*)(\?([^#]*))?(#(.*))?$`)
+
 func NewComponent(v string) (Component, error) {
 	switch v {
 	case string(QueryComponent):
@@ -191,3 +202,102 @@ func URIEncoded(vm *engine.VM, component, decoded, encoded engine.Term, cont eng
 		}
 	})
 }
+
+// URIComponents is a predicate which breaks down a URI into its components, or builds a URI from its components,
+// according to [RFC 3986].
+//
+// The signature is as follows:
+//
+//	uri_components(+URI, -Components) is det
+//	uri_components(-URI, +Components) is det
+//
+// Where:
+//   - URI represents the URI, given as an Atom.
+//   - Components is a compound Term in the format uri_components(Scheme, Authority, Path, Search, Fragment), where
+//     Scheme is the scheme, Authority is the authority (i.e. user information, host and port), Path is the path,
+//     Search is the query and Fragment is the fragment of the URI.
+//     When decomposing a URI, any component not present is unified with an empty atom. When reconstructing a URI,
+//     any component left as an uninstantiated variable, or given as an empty atom, is omitted.
+//
+// The components are given as they appear in the URI, without any percent-decoding. Use uri_encoded/3 to encode or
+// decode them.
+//
+// Examples:
+//
+//	# Decompose a URI into its components.
+//	- uri_components('https://okp4.network/foo?bar=baz#qux', uri_components(Scheme, Authority, Path, Search, Fragment)).
+//
+//	# Reconstruct a URI from its components.
+//	- uri_components(URI, uri_components(https, 'okp4.network', '/foo', _, _)).
+//
+// [RFC 3986]: https://www.rfc-editor.org/rfc/rfc3986#section-3
+func URIComponents(vm *engine.VM, uri, components engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	switch t1 := env.Resolve(uri).(type) {
+	case engine.Variable:
+	case engine.Atom:
+		m := uriRegexp.FindStringSubmatch(t1.String())
+		if m == nil {
+			return engine.Error(fmt.Errorf("uri_components/2: invalid URI: %s", t1.String()))
+		}
+
+		return engine.Unify(vm, components, AtomURIComponents.Apply(
+			engine.NewAtom(m[2]),
+			engine.NewAtom(m[4]),
+			engine.NewAtom(m[5]),
+			engine.NewAtom(m[7]),
+			engine.NewAtom(m[9]),
+		), cont, env)
+	default:
+		return engine.Error(fmt.Errorf("uri_components/2: cannot unify uri with %T", t1))
+	}
+
+	switch t2 := env.Resolve(components).(type) {
+	case engine.Variable:
+		return engine.Error(fmt.Errorf("uri_components/2: at least one argument must be instantiated"))
+	case engine.Compound:
+		if t2.Functor() != AtomURIComponents {
+			return engine.Error(fmt.Errorf("uri_components/2: invalid functor %s. Expected %s",
+				t2.Functor().String(), AtomURIComponents.String()))
+		}
+		if t2.Arity() != 5 {
+			return engine.Error(fmt.Errorf("uri_components/2: invalid arity %d. Expected 5", t2.Arity()))
+		}
+
+		buf := strings.Builder{}
+		processors := []func(engine.Atom){
+			func(segment engine.Atom) {
+				buf.WriteString(segment.String())
+				buf.WriteString(":")
+			},
+			func(segment engine.Atom) {
+				buf.WriteString("//")
+				buf.WriteString(segment.String())
+			},
+			func(segment engine.Atom) {
+				buf.WriteString(segment.String())
+			},
+			func(segment engine.Atom) {
+				buf.WriteString("?")
+				buf.WriteString(segment.String())
+			},
+			func(segment engine.Atom) {
+				buf.WriteString("#")
+				buf.WriteString(segment.String())
+			},
+		}
+
+		for i := 0; i < t2.Arity(); i++ {
+			if err := processSegment(t2, uint8(i), func(segment engine.Atom) {
+				if segment != engine.NewAtom("") {
+					processors[i](segment)
+				}
+			}, env); err != nil {
+				return engine.Error(fmt.Errorf("uri_components/2: %w", err))
+			}
+		}
+
+		return engine.Unify(vm, uri, engine.NewAtom(buf.String()), cont, env)
+	default:
+		return engine.Error(fmt.Errorf("uri_components/2: cannot unify uri with %T", t2))
+	}
+}
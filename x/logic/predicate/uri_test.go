@@ -220,3 +220,140 @@ func TestURIEncoded(t *testing.T) {
 		}
 	})
 }
+
+func TestURIComponents(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		cases := []struct {
+			program     string
+			query       string
+			wantResult  []types.TermResults
+			wantError   error
+			wantSuccess bool
+		}{
+			{
+				query:       `uri_components('https://okp4.network/foo?bar=baz#qux', X).`,
+				wantSuccess: true,
+				wantResult:  []types.TermResults{{"X": "uri_components(https,'okp4.network','/foo','bar=baz',qux)"}},
+			},
+			{
+				query:       `uri_components('https://user@okp4.network:8443', uri_components(S, A, P, Q, F)).`,
+				wantSuccess: true,
+				wantResult: []types.TermResults{{
+					"S": "https", "A": "'user@okp4.network:8443'", "P": "''", "Q": "''", "F": "''",
+				}},
+			},
+			{
+				query:       `uri_components('mailto:info@okp4.network', X).`,
+				wantSuccess: true,
+				wantResult:  []types.TermResults{{"X": "uri_components(mailto,'','info@okp4.network','','')"}},
+			},
+			{
+				query:       `uri_components('foo/bar%20baz?q', X).`,
+				wantSuccess: true,
+				wantResult:  []types.TermResults{{"X": "uri_components('','','foo/bar%20baz',q,'')"}},
+			},
+			{
+				query:       `uri_components('https://okp4.network/foo', uri_components(http, _, _, _, _)).`,
+				wantSuccess: false,
+			},
+			{
+				query:       `uri_components(X, uri_components(https, 'okp4.network', '/foo', 'bar=baz', qux)).`,
+				wantSuccess: true,
+				wantResult:  []types.TermResults{{"X": "'https://okp4.network/foo?bar=baz#qux'"}},
+			},
+			{
+				query:       `uri_components(X, uri_components(https, 'okp4.network', _, _, '')).`,
+				wantSuccess: true,
+				wantResult:  []types.TermResults{{"X": "'https://okp4.network'"}},
+			},
+			{
+				query:       `uri_components(X, uri_components(urn, _, 'isbn:0451450523', _, _)).`,
+				wantSuccess: true,
+				wantResult:  []types.TermResults{{"X": "'urn:isbn:0451450523'"}},
+			},
+			{
+				program: `round_trip(URI, Out) :-
+  uri_components(URI, uri_components(S, A, P, Q, F)),
+  uri_components(Out, uri_components(S, A, P, Q, F)).`,
+				query:       `round_trip('ftp://ftp.is.co.za/rfc/rfc1808.txt', Out).`,
+				wantSuccess: true,
+				wantResult:  []types.TermResults{{"Out": "'ftp://ftp.is.co.za/rfc/rfc1808.txt'"}},
+			},
+			{
+				query:       `uri_components(X, Y).`,
+				wantSuccess: false,
+				wantError:   fmt.Errorf("uri_components/2: at least one argument must be instantiated"),
+			},
+			{
+				query:       `uri_components(X, foo(https, _, _, _, _)).`,
+				wantSuccess: false,
+				wantError:   fmt.Errorf("uri_components/2: invalid functor foo. Expected uri_components"),
+			},
+			{
+				query:       `uri_components(X, uri_components(https, _, _)).`,
+				wantSuccess: false,
+				wantError:   fmt.Errorf("uri_components/2: invalid arity 3. Expected 5"),
+			},
+			{
+				query:       `uri_components(42, X).`,
+				wantSuccess: false,
+				wantError:   fmt.Errorf("uri_components/2: cannot unify uri with engine.Integer"),
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					ctx := sdk.NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger())
+
+					Convey("and a vm", func() {
+						interpreter := testutil.NewLightInterpreterMust(ctx)
+						interpreter.Register2(engine.NewAtom("uri_components"), URIComponents)
+
+						err := interpreter.Compile(ctx, tc.program)
+						So(err, ShouldBeNil)
+
+						Convey("When the predicate is called", func() {
+							sols, err := interpreter.QueryContext(ctx, tc.query)
+
+							Convey("Then the error should be nil", func() {
+								So(err, ShouldBeNil)
+								So(sols, ShouldNotBeNil)
+
+								Convey("and the bindings should be as expected", func() {
+									var got []types.TermResults
+									for sols.Next() {
+										m := types.TermResults{}
+										err := sols.Scan(m)
+										So(err, ShouldBeNil)
+
+										got = append(got, m)
+									}
+									if tc.wantError != nil {
+										So(sols.Err(), ShouldNotBeNil)
+										So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+									} else {
+										So(sols.Err(), ShouldBeNil)
+
+										if tc.wantSuccess {
+											So(len(got), ShouldBeGreaterThan, 0)
+											So(len(got), ShouldEqual, len(tc.wantResult))
+											for iGot, resultGot := range got {
+												for varGot, termGot := range resultGot {
+													So(testutil.ReindexUnknownVariables(termGot), ShouldEqual, tc.wantResult[iGot][varGot])
+												}
+											}
+										} else {
+											So(len(got), ShouldEqual, 0)
+										}
+									}
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
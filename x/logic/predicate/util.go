@@ -1,11 +1,17 @@
 package predicate
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"sort"
 
 	"github.com/ichiban/prolog/engine"
+	"github.com/shengdoushi/base58"
+
+	sdkmath "cosmossdk.io/math"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
@@ -22,6 +28,9 @@ var (
 
 	// AtomOctet is the term used to indicate the byte encoding type option.
 	AtomOctet = engine.NewAtom("octet")
+
+	// AtomBase58 is the term used to indicate the base58 encoding type option.
+	AtomBase58 = engine.NewAtom("base58")
 )
 
 // SortBalances by coin denomination.
@@ -85,7 +94,13 @@ func BytesToList(bt []byte) engine.Term {
 //     where Format is the encoding format to use. Possible values are:
 //     -- `hex` (default): hexadecimal encoding represented as an atom.
 //     -- `octet`: plain bytes encoding represented as a list of integers between 0 and 255.
-func TermToBytes(term, options engine.Term, env *engine.Env) ([]byte, error) {
+//     -- `base64`: standard base64 encoding, with padding, as specified by RFC 4648, represented as an atom.
+//     -- `base58`: base58 encoding with the Bitcoin alphabet, represented as an atom.
+//
+// An unsupported Format raises a domain_error(encoding, Format) error.
+//
+// The size of the resulting bytes is bounded by the maximum input size carried by the context, if any.
+func TermToBytes(ctx context.Context, term, options engine.Term, env *engine.Env) ([]byte, error) {
 	encoding, err := util.GetOptionWithDefault(AtomEncoding, options, AtomHex, env)
 	if err != nil {
 		return nil, err
@@ -96,35 +111,90 @@ func TermToBytes(term, options engine.Term, env *engine.Env) ([]byte, error) {
 		switch enc {
 		case AtomOctet:
 			v := env.Resolve(term)
+			if v == util.AtomEmptyList {
+				return []byte{}, nil
+			}
 			if c, ok := v.(engine.Compound); ok && util.IsList(c) {
 				iter := engine.ListIterator{List: v, Env: env}
-				return ListToBytes(iter, env)
+				return ListToBytes(ctx, iter, env)
 			}
 			return nil, fmt.Errorf("term should be a List, given %T", term)
 		case AtomHex:
 			v := env.Resolve(term)
 			if atom, ok := v.(engine.Atom); ok {
 				src := []byte(atom.String())
+				if err := checkInputSize(ctx, hex.DecodedLen(len(src))); err != nil {
+					return nil, err
+				}
 				result := make([]byte, hex.DecodedLen(len(src)))
 				_, err := hex.Decode(result, src)
 				return result, err
 			}
 			return nil, fmt.Errorf("invalid term type: %T, should be an atom", term)
+		case AtomBase64, AtomBase58:
+			v := env.Resolve(term)
+			if atom, ok := v.(engine.Atom); ok {
+				return decodeBaseText(ctx, enc, atom.String())
+			}
+			return nil, fmt.Errorf("invalid term type: %T, should be an atom", term)
 		default:
-			return nil, fmt.Errorf("invalid encoding option: %s, valid values are '%s' or '%s'", enc, AtomHex, AtomOctet)
+			return nil, domainError(AtomEncoding, enc, env)
 		}
 	default:
 		return nil, fmt.Errorf("invalid term '%s' - expected engine.Atom but got %T", encoding, encoding)
 	}
 }
 
-func ListToBytes(terms engine.ListIterator, env *engine.Env) ([]byte, error) {
+// errInvalidBaseText is the error returned by decodeBaseText for a text which is not valid in its encoding.
+var errInvalidBaseText = errors.New("invalid encoding")
+
+// decodeBaseText decodes the given base64 or base58 encoded text, returning errInvalidBaseText if it is not valid in
+// the given encoding.
+//
+// The size of the resulting bytes is bounded by the maximum input size carried by the context, if any. As the
+// decoding of a base58 text takes a time quadratic in its length, its size is checked beforehand against a lower bound
+// of the size of the decoded bytes, a base58 digit holding log(58) / log(256) > 0.732 bytes.
+func decodeBaseText(ctx context.Context, encoding engine.Atom, text string) ([]byte, error) {
+	var decoded []byte
+	switch encoding {
+	case AtomBase64:
+		d, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidBaseText, err)
+		}
+		decoded = d
+	default:
+		if err := checkInputSize(ctx, max(len(text)-1, 0)*732/1000); err != nil {
+			return nil, err
+		}
+		d, err := base58.Decode(text, base58.BitcoinAlphabet)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errInvalidBaseText, err)
+		}
+		decoded = d
+	}
+
+	if err := checkInputSize(ctx, len(decoded)); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// ListToBytes converts a list of integers between 0 and 255 into native golang []byte.
+//
+// The size of the resulting bytes is bounded by the maximum input size carried by the context, if any, the conversion
+// failing as soon as the limit is exceeded.
+func ListToBytes(ctx context.Context, terms engine.ListIterator, env *engine.Env) ([]byte, error) {
 	bt := make([]byte, 0)
 	index := 0
+	limit, limited := maxInputSize(ctx)
 
 	for terms.Next() {
 		term := env.Resolve(terms.Current())
 		index++
+		if limited && uint64(index) > limit {
+			return nil, fmt.Errorf("input exceeds the maximum size of %d bytes", limit)
+		}
 
 		switch t := term.(type) {
 		case engine.Integer:
@@ -141,6 +211,35 @@ func ListToBytes(terms engine.ListIterator, env *engine.Env) ([]byte, error) {
 	return bt, nil
 }
 
+// maxInputSize returns the maximum size, in bytes, of the data accepted as input by the predicates, as carried by the
+// context, and whether such a limit is defined.
+func maxInputSize(ctx context.Context) (uint64, bool) {
+	limit, ok := ctx.Value(types.MaxInputSizeContextKey).(sdkmath.Uint)
+	if !ok || !limit.BigInt().IsUint64() {
+		return 0, false
+	}
+	return limit.Uint64(), true
+}
+
+// checkInputSize checks that the given size, in bytes, of an input does not exceed the maximum input size carried by
+// the context, if any.
+func checkInputSize(ctx context.Context, size int) error {
+	if limit, ok := maxInputSize(ctx); ok && uint64(size) > limit {
+		return fmt.Errorf("input exceeds the maximum size of %d bytes", limit)
+	}
+	return nil
+}
+
+// checkCollectionSize checks that the given number of elements of a generated collection does not exceed the maximum
+// collection size carried by the context, if any.
+func checkCollectionSize(ctx context.Context, size uint64) error {
+	limit, ok := ctx.Value(types.MaxCollectionSizeContextKey).(sdkmath.Uint)
+	if ok && limit.BigInt().IsUint64() && size > limit.Uint64() {
+		return fmt.Errorf("collection of %d elements exceeds the maximum size of %d elements", size, limit.Uint64())
+	}
+	return nil
+}
+
 // ExtractJSONTerm is an utility function that would extract all attribute of a JSON object
 // that is represented in prolog with the `json` atom.
 //
@@ -182,6 +281,11 @@ func ExtractJSONTerm(term engine.Compound, env *engine.Env) (map[string]engine.T
 			terms[key.String()] = pair.Arg(1)
 		}
 		return terms, nil
+	case engine.Atom:
+		if l == util.AtomEmptyList {
+			return map[string]engine.Term{}, nil
+		}
+		return nil, fmt.Errorf("json compound should contains one list, give %T", l)
 	default:
 		return nil, fmt.Errorf("json compound should contains one list, give %T", l)
 	}
@@ -2,12 +2,19 @@
 package predicate
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/ichiban/prolog/engine"
+	"github.com/samber/lo"
 
 	. "github.com/smartystreets/goconvey/convey"
+
+	sdkmath "cosmossdk.io/math"
+
+	"github.com/okp4/okp4d/x/logic/types"
 )
 
 func TestExtractJsonTerm(t *testing.T) {
@@ -84,11 +91,13 @@ func TestExtractJsonTerm(t *testing.T) {
 func TestTermToBytes(t *testing.T) {
 	Convey("Given a test cases", t, func() {
 		cases := []struct {
-			term        engine.Term
-			options     engine.Term
-			result      []byte
-			wantSuccess bool
-			wantError   error
+			term         engine.Term
+			options      engine.Term
+			result       []byte
+			maxInputSize *sdkmath.Uint
+			wantSuccess  bool
+			wantError    error
+			wantISOError string
 		}{
 			{ // If no option, by default, given term is in hexadecimal format.
 				term:        engine.NewAtom("486579202120596f752077616e7420746f20736565207468697320746578742c20776f6e64657266756c21"),
@@ -116,11 +125,50 @@ func TestTermToBytes(t *testing.T) {
 				wantSuccess: true,
 			},
 			{
-				term:        engine.NewAtom("486579202120596f752077616e7420746f20736565207468697320746578742c20776f6e64657266756c21"),
-				options:     engine.NewAtom("encoding").Apply(engine.NewAtom("foo")),
+				term:        engine.List(),
+				options:     engine.NewAtom("encoding").Apply(engine.NewAtom("octet")),
+				result:      []byte{},
+				wantSuccess: true,
+			},
+			{
+				term:         engine.NewAtom("486579202120596f752077616e7420746f20736565207468697320746578742c20776f6e64657266756c21"),
+				options:      engine.NewAtom("encoding").Apply(engine.NewAtom("foo")),
+				result:       nil,
+				wantSuccess:  false,
+				wantISOError: "domain_error(encoding,foo)",
+			},
+			{
+				term:        engine.NewAtom("SGV5ICEgWW91IHdhbnQgdG8gc2VlIHRoaXMgdGV4dCwgd29uZGVyZnVsIQ=="),
+				options:     engine.NewAtom("encoding").Apply(engine.NewAtom("base64")),
+				result:      []byte{72, 101, 121, 32, 33, 32, 89, 111, 117, 32, 119, 97, 110, 116, 32, 116, 111, 32, 115, 101, 101, 32, 116, 104, 105, 115, 32, 116, 101, 120, 116, 44, 32, 119, 111, 110, 100, 101, 114, 102, 117, 108, 33},
+				wantSuccess: true,
+			},
+			{
+				term:        engine.NewAtom("SGV5IQ"),
+				options:     engine.NewAtom("encoding").Apply(engine.NewAtom("base64")),
 				result:      nil,
 				wantSuccess: false,
-				wantError:   fmt.Errorf("invalid encoding option: foo, valid values are 'hex' or 'octet'"),
+				wantError:   fmt.Errorf("%w: illegal base64 data at input byte 4", errInvalidBaseText),
+			},
+			{
+				term:        engine.NewAtom("11JxF12TrwUP45BMd"),
+				options:     engine.NewAtom("encoding").Apply(engine.NewAtom("base58")),
+				result:      []byte{0, 0, 72, 101, 108, 108, 111, 32, 87, 111, 114, 108, 100},
+				wantSuccess: true,
+			},
+			{
+				term:        engine.NewAtom("JxF12TrwUP45BMd0"),
+				options:     engine.NewAtom("encoding").Apply(engine.NewAtom("base58")),
+				result:      nil,
+				wantSuccess: false,
+				wantError:   fmt.Errorf("%w: invalid base58 string", errInvalidBaseText),
+			},
+			{
+				term:        engine.List(engine.Integer(72)),
+				options:     engine.NewAtom("encoding").Apply(engine.NewAtom("base58")),
+				result:      nil,
+				wantSuccess: false,
+				wantError:   fmt.Errorf("invalid term type: engine.list, should be an atom"),
 			},
 			{
 				term:        engine.NewAtom("486579202120596f752077616e7420746f20736565207468697320746578742c20776f6e64657266756c21"),
@@ -157,12 +205,77 @@ func TestTermToBytes(t *testing.T) {
 				wantSuccess: false,
 				wantError:   fmt.Errorf("invalid term 'foo' - expected engine.Compound but got engine.Atom"),
 			},
+			{
+				term:         engine.NewAtom("48657921"),
+				options:      engine.NewAtom("encoding").Apply(engine.NewAtom("hex")),
+				maxInputSize: lo.ToPtr(sdkmath.NewUint(4)),
+				result:       []byte{72, 101, 121, 33},
+				wantSuccess:  true,
+			},
+			{
+				term:         engine.NewAtom("4865792120"),
+				options:      engine.NewAtom("encoding").Apply(engine.NewAtom("hex")),
+				maxInputSize: lo.ToPtr(sdkmath.NewUint(4)),
+				result:       nil,
+				wantSuccess:  false,
+				wantError:    fmt.Errorf("input exceeds the maximum size of 4 bytes"),
+			},
+			{
+				term:         engine.List(engine.Integer(72), engine.Integer(101), engine.Integer(121), engine.Integer(33)),
+				options:      engine.NewAtom("encoding").Apply(engine.NewAtom("octet")),
+				maxInputSize: lo.ToPtr(sdkmath.NewUint(4)),
+				result:       []byte{72, 101, 121, 33},
+				wantSuccess:  true,
+			},
+			{
+				term:         engine.List(engine.Integer(72), engine.Integer(101), engine.Integer(121), engine.Integer(33), engine.Integer(32)),
+				options:      engine.NewAtom("encoding").Apply(engine.NewAtom("octet")),
+				maxInputSize: lo.ToPtr(sdkmath.NewUint(4)),
+				result:       nil,
+				wantSuccess:  false,
+				wantError:    fmt.Errorf("input exceeds the maximum size of 4 bytes"),
+			},
+			{
+				term:         engine.NewAtom("SGV5IQ=="),
+				options:      engine.NewAtom("encoding").Apply(engine.NewAtom("base64")),
+				maxInputSize: lo.ToPtr(sdkmath.NewUint(4)),
+				result:       []byte{72, 101, 121, 33},
+				wantSuccess:  true,
+			},
+			{
+				term:         engine.NewAtom("SGV5ICA="),
+				options:      engine.NewAtom("encoding").Apply(engine.NewAtom("base64")),
+				maxInputSize: lo.ToPtr(sdkmath.NewUint(4)),
+				result:       nil,
+				wantSuccess:  false,
+				wantError:    fmt.Errorf("input exceeds the maximum size of 4 bytes"),
+			},
+			{
+				term:         engine.NewAtom("11111"),
+				options:      engine.NewAtom("encoding").Apply(engine.NewAtom("base58")),
+				maxInputSize: lo.ToPtr(sdkmath.NewUint(4)),
+				result:       nil,
+				wantSuccess:  false,
+				wantError:    fmt.Errorf("input exceeds the maximum size of 4 bytes"),
+			},
+			{
+				term:         engine.NewAtom("JxF12TrwUP45BMd"),
+				options:      engine.NewAtom("encoding").Apply(engine.NewAtom("base58")),
+				maxInputSize: lo.ToPtr(sdkmath.NewUint(4)),
+				result:       nil,
+				wantSuccess:  false,
+				wantError:    fmt.Errorf("input exceeds the maximum size of 4 bytes"),
+			},
 		}
 		for nc, tc := range cases {
 			Convey(fmt.Sprintf("Given the term #%d: %s", nc, tc.term), func() {
 				Convey("when check try convert", func() {
 					env := engine.Env{}
-					result, err := TermToBytes(tc.term, tc.options, &env)
+					ctx := context.Background()
+					if tc.maxInputSize != nil {
+						ctx = context.WithValue(ctx, types.MaxInputSizeContextKey, *tc.maxInputSize)
+					}
+					result, err := TermToBytes(ctx, tc.term, tc.options, &env)
 
 					if tc.wantSuccess {
 						Convey("then no error should be thrown", func() {
@@ -177,7 +290,15 @@ func TestTermToBytes(t *testing.T) {
 							So(err, ShouldNotBeNil)
 
 							Convey("and should be as expected", func() {
-								So(err, ShouldResemble, tc.wantError)
+								if tc.wantISOError != "" {
+									So(err, ShouldHaveSameTypeAs, engine.Exception{})
+
+									var sb strings.Builder
+									So(err.(engine.Exception).Term().(engine.Compound).Arg(0).WriteTerm(&sb, &engine.WriteOptions{}, nil), ShouldBeNil)
+									So(sb.String(), ShouldEqual, tc.wantISOError)
+								} else {
+									So(err, ShouldResemble, tc.wantError)
+								}
 							})
 						})
 					}
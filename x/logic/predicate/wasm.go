@@ -0,0 +1,74 @@
+package predicate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ichiban/prolog/engine"
+
+	"github.com/okp4/okp4d/x/logic/types"
+	"github.com/okp4/okp4d/x/logic/util"
+)
+
+// AtomContractError are terms with principal functor contract_error/1.
+// It is used to represent the formal part of the errors raised when a smart contract fails to answer a query.
+var AtomContractError = engine.NewAtom("contract_error")
+
+// SmartQuery is a predicate which queries a CosmWasm smart contract.
+//
+// The signature is as follows:
+//
+//	smart_query(+ContractAddr, +QueryMsg, -Response) is det
+//
+// Where:
+//   - ContractAddr is the address of the contract (in Bech32 format).
+//   - QueryMsg is the query message, as a JSON term (see json_prolog/2).
+//   - Response is the response of the contract, as a JSON term (see json_prolog/2).
+//
+// The query is a smart query, answered by the query entry point of the contract: it cannot mutate the state, and any
+// change made while answering it is discarded. It consumes the gas of the query, as any other access to the state.
+//
+// A contract failing to answer the query raises a catchable error(contract_error(ContractAddr), context(Context,
+// Message)) exception, where Message is the error returned by the contract, and an unbound ContractAddr an
+// instantiation_error.
+//
+// Examples:
+//
+//	# Query the configuration of a contract.
+//	- smart_query('okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm', json([config-json([])]), Response).
+func SmartQuery(vm *engine.VM, contract, query, response engine.Term, cont engine.Cont, env *engine.Env) *engine.Promise {
+	return engine.Delay(func(ctx context.Context) *engine.Promise {
+		sdkContext, err := util.UnwrapSDKContext(ctx)
+		if err != nil {
+			return engine.Error(err)
+		}
+		wasmKeeper := sdkContext.Value(types.WasmKeeperContextKey).(types.WasmKeeper)
+
+		address, err := getBech32(env, contract)
+		if err != nil {
+			return engine.Error(fmt.Errorf("smart_query/3: %w", err))
+		}
+		if address == nil {
+			return engine.Error(engine.InstantiationError(env))
+		}
+		req, err := termsToJSON(env.Resolve(query), env)
+		if err != nil {
+			return engine.Error(fmt.Errorf("smart_query/3: %w", err))
+		}
+
+		// the writes of the cached context are never committed, so that the query cannot mutate the state.
+		cacheContext, _ := sdkContext.CacheContext()
+		res, err := wasmKeeper.QuerySmart(cacheContext, address, req)
+		if err != nil {
+			formal := AtomContractError.Apply(engine.NewAtom(address.String()))
+			return engine.Error(withMessage(isoError(formal, env), err.Error(), env))
+		}
+
+		result, err := jsonStringToTerms(string(res), false)
+		if err != nil {
+			return engine.Error(fmt.Errorf("smart_query/3: invalid response: %w", err))
+		}
+
+		return engine.Unify(vm, response, result, cont, env)
+	})
+}
@@ -0,0 +1,141 @@
+//nolint:gocognit,lll
+package predicate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ichiban/prolog/engine"
+
+	. "github.com/smartystreets/goconvey/convey"
+
+	errorsmod "cosmossdk.io/errors"
+
+	tmdb "github.com/cometbft/cometbft-db"
+	"github.com/cometbft/cometbft/libs/log"
+	tmproto "github.com/cometbft/cometbft/proto/tendermint/types"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+
+	"github.com/cosmos/cosmos-sdk/store"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/okp4/okp4d/x/logic/testutil"
+	"github.com/okp4/okp4d/x/logic/types"
+)
+
+func TestSmartQuery(t *testing.T) {
+	Convey("Under a mocked environment", t, func() {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdk.GetConfig().SetBech32PrefixForAccount("okp4", "okp4pub")
+		contract := "okp415ekvz3qdter33mdnk98v8whv5qdr53yusksnfgc08xd26fpdn3ts8gddht"
+
+		cases := []struct {
+			query      string
+			wantQuery  string
+			response   string
+			queryErr   error
+			wantResult []types.TermResults
+			wantError  error
+		}{
+			{
+				query:      fmt.Sprintf(`smart_query('%s', json([config-json([])]), Response).`, contract),
+				wantQuery:  `{"config":{}}`,
+				response:   `{"owner":"okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm","limits":[1,2],"paused":false}`,
+				wantResult: []types.TermResults{{"Response": "json([limits-[1,2],owner-okp41ffd5wx65l407yvm478cxzlgygw07h79sq0m3fm,paused- @(false)])"}},
+			},
+			{
+				query:      fmt.Sprintf(`smart_query('%s', json([balance-json([address-foo])]), json([amount-Amount])).`, contract),
+				wantQuery:  `{"balance":{"address":"foo"}}`,
+				response:   `{"amount":"100000000000000000000"}`,
+				wantResult: []types.TermResults{{"Amount": "'100000000000000000000'"}},
+			},
+			{
+				query:     fmt.Sprintf(`catch(smart_query('%s', json([unknown-json([])]), _), error(contract_error(C), context(_, M)), true).`, contract),
+				wantQuery: `{"unknown":{}}`,
+				queryErr:  errorsmod.Wrap(wasmtypes.ErrQueryFailed, "Error parsing into type msg::QueryMsg: unknown variant `unknown`"),
+				wantResult: []types.TermResults{{
+					"C": "okp415ekvz3qdter33mdnk98v8whv5qdr53yusksnfgc08xd26fpdn3ts8gddht",
+					"M": "'Error parsing into type msg::QueryMsg: unknown variant `unknown`: query wasm contract failed'",
+				}},
+			},
+			{
+				query:     fmt.Sprintf(`smart_query('%s', json([config-json([])]), Response).`, contract),
+				wantQuery: `{"config":{}}`,
+				response:  `{"owner"`,
+				wantError: fmt.Errorf("smart_query/3: invalid response: unexpected EOF"),
+			},
+			{
+				query:     fmt.Sprintf(`smart_query('%s', foo(bar), Response).`, contract),
+				wantError: fmt.Errorf("smart_query/3: invalid functor foo"),
+			},
+			{
+				query:     `smart_query(foo, json([config-json([])]), Response).`,
+				wantError: fmt.Errorf("smart_query/3: decoding bech32 failed: invalid bech32 string length 3"),
+			},
+			{
+				query:      `catch(smart_query(_, json([config-json([])]), _), E, true).`,
+				wantResult: []types.TermResults{{"E": "error(instantiation_error,/(smart_query,3))"}},
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the query #%d: %s", nc, tc.query), func() {
+				Convey("and a context", func() {
+					db := tmdb.NewMemDB()
+					stateStore := store.NewCommitMultiStore(db)
+					wasmKeeper := testutil.NewMockWasmKeeper(ctrl)
+					ctx := sdk.
+						NewContext(stateStore, tmproto.Header{}, false, log.NewNopLogger()).
+						WithValue(types.WasmKeeperContextKey, wasmKeeper)
+
+					Convey("and a wasm keeper answering the query", func() {
+						wasmKeeper.
+							EXPECT().
+							QuerySmart(gomock.Any(), sdk.MustAccAddressFromBech32(contract), []byte(tc.wantQuery)).
+							AnyTimes().
+							Return([]byte(tc.response), tc.queryErr)
+
+						Convey("and a vm", func() {
+							interpreter := testutil.NewLightInterpreterMust(ctx)
+							interpreter.Register3(engine.NewAtom("smart_query"), SmartQuery)
+							interpreter.Register3(engine.NewAtom("catch"), engine.Catch)
+							interpreter.Register0(engine.NewAtom("true"), func(_ *engine.VM, cont engine.Cont, env *engine.Env) *engine.Promise {
+								return cont(env)
+							})
+
+							Convey("When the predicate is called", func() {
+								sols, err := interpreter.QueryContext(ctx, tc.query)
+
+								Convey("Then the error should be nil", func() {
+									So(err, ShouldBeNil)
+									So(sols, ShouldNotBeNil)
+
+									Convey("and the bindings should be as expected", func() {
+										var got []types.TermResults
+										for sols.Next() {
+											m := types.TermResults{}
+											err := sols.Scan(m)
+											So(err, ShouldBeNil)
+
+											got = append(got, m)
+										}
+										if tc.wantError != nil {
+											So(sols.Err(), ShouldNotBeNil)
+											So(sols.Err().Error(), ShouldEqual, tc.wantError.Error())
+										} else {
+											So(sols.Err(), ShouldBeNil)
+											So(got, ShouldResemble, tc.wantResult)
+										}
+									})
+								})
+							})
+						})
+					})
+				})
+			})
+		}
+	})
+}
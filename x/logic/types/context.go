@@ -8,4 +8,17 @@ const (
 	AuthKeeperContextKey = ContextKey("authKeeper")
 	// BankKeeperContextKey is the context key for the bank keeper.
 	BankKeeperContextKey = ContextKey("bankKeeper")
+	// StakingKeeperContextKey is the context key for the staking keeper.
+	StakingKeeperContextKey = ContextKey("stakingKeeper")
+	// GovKeeperContextKey is the context key for the gov keeper.
+	GovKeeperContextKey = ContextKey("govKeeper")
+	// WasmKeeperContextKey is the context key for the wasm keeper.
+	WasmKeeperContextKey = ContextKey("wasmKeeper")
+	// AlgorithmCostsContextKey is the context key for the gas costs of the algorithms used by the predicates.
+	AlgorithmCostsContextKey = ContextKey("algorithmCosts")
+	// MaxInputSizeContextKey is the context key for the maximum size of the data accepted as input by the predicates.
+	MaxInputSizeContextKey = ContextKey("maxInputSize")
+	// MaxCollectionSizeContextKey is the context key for the maximum number of elements of the collections generated by
+	// the predicates.
+	MaxCollectionSizeContextKey = ContextKey("maxCollectionSize")
 )
@@ -4,6 +4,8 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	auth "github.com/cosmos/cosmos-sdk/x/auth/types"
 	bank "github.com/cosmos/cosmos-sdk/x/bank/types"
+	gov "github.com/cosmos/cosmos-sdk/x/gov/types/v1"
+	staking "github.com/cosmos/cosmos-sdk/x/staking/types"
 )
 
 // AccountKeeper defines the expected account keeper used for simulations (noalias).
@@ -20,6 +22,17 @@ type BankKeeper interface {
 	LockedCoins(ctx sdk.Context, addr sdk.AccAddress) sdk.Coins
 }
 
+// StakingKeeper defines the expected interface needed to retrieve delegations.
+type StakingKeeper interface {
+	GetAllDelegatorDelegations(ctx sdk.Context, delegator sdk.AccAddress) []staking.Delegation
+	GetValidator(ctx sdk.Context, addr sdk.ValAddress) (validator staking.Validator, found bool)
+}
+
+// GovKeeper defines the expected interface needed to retrieve governance proposals.
+type GovKeeper interface {
+	GetProposal(ctx sdk.Context, proposalID uint64) (gov.Proposal, bool)
+}
+
 // WasmKeeper defines the expected interface needed to request smart contracts.
 type WasmKeeper interface {
 	QuerySmart(ctx sdk.Context, contractAddr sdk.AccAddress, req []byte) ([]byte, error)
@@ -21,8 +21,31 @@ var (
 	DefaultMaxGas              = math.NewUint(uint64(100000))
 	DefaultMaxSize             = math.NewUint(uint64(5000))
 	DefaultMaxResultCount      = math.NewUint(uint64(1))
+	DefaultMaxInputSize        = math.NewUint(uint64(1048576))
+	DefaultMaxCollectionSize   = math.NewUint(uint64(10000))
 )
 
+// CryptoPredicates are the names of the cryptographic predicates, which are always permitted to the programs unless
+// they are explicitly included in the blacklist of the predicates permissions.
+var CryptoPredicates = []string{
+	"sha_hash",
+	"sha3_hash",
+	"blake2b",
+	"hash_equal",
+	"eddsa_verify",
+	"eddsa_verify_batch",
+	"ecdsa_verify",
+	"rsa_verify",
+	"sshsig_verify",
+	"jwt_verify",
+	"cose_sign1_verify",
+	"signed_token_verify",
+	"verify_any",
+	"eth_verify_address",
+	"tendermint_verify",
+	"comet_verify_commit",
+}
+
 // NewParams creates a new Params object.
 func NewParams(interpreter Interpreter, limits Limits) Params {
 	return Params{
@@ -41,7 +64,10 @@ func (p Params) Validate() error {
 	if err := validateInterpreter(p.Interpreter); err != nil {
 		return err
 	}
-	return validateLimits(p.Limits)
+	if err := validateLimits(p.Limits); err != nil {
+		return err
+	}
+	return validateGasPolicy(p.GasPolicy)
 }
 
 // String implements the Stringer interface.
@@ -99,6 +125,20 @@ func WithVirtualFilesBlacklist(blacklist []string) InterpreterOption {
 	}
 }
 
+// WithPredicatesPermissionsWhitelist sets the whitelist of the predicates the programs are permitted to call.
+func WithPredicatesPermissionsWhitelist(whitelist []string) InterpreterOption {
+	return func(i *Interpreter) {
+		i.PredicatesPermissions.Whitelist = whitelist
+	}
+}
+
+// WithPredicatesPermissionsBlacklist sets the blacklist of the predicates the programs are permitted to call.
+func WithPredicatesPermissionsBlacklist(blacklist []string) InterpreterOption {
+	return func(i *Interpreter) {
+		i.PredicatesPermissions.Blacklist = blacklist
+	}
+}
+
 // WithBootstrap sets the bootstrap program.
 func WithBootstrap(bootstrap string) InterpreterOption {
 	return func(i *Interpreter) {
@@ -157,6 +197,35 @@ func WithMaxUserOutputSize(maxUserOutputSize math.Uint) LimitsOption {
 	}
 }
 
+// WithMaxInputSize sets the maximum size, in bytes, of the data accepted as input by the predicates decoding bytes.
+func WithMaxInputSize(maxInputSize math.Uint) LimitsOption {
+	return func(i *Limits) {
+		i.MaxInputSize = &maxInputSize
+	}
+}
+
+// WithMaxSteps sets the maximum number of execution steps the interpreter is allowed to perform for a request.
+func WithMaxSteps(maxSteps math.Uint) LimitsOption {
+	return func(i *Limits) {
+		i.MaxSteps = &maxSteps
+	}
+}
+
+// WithMaxCollectionSize sets the maximum number of elements of the collections generated by the combinatorial
+// predicates.
+func WithMaxCollectionSize(maxCollectionSize math.Uint) LimitsOption {
+	return func(i *Limits) {
+		i.MaxCollectionSize = &maxCollectionSize
+	}
+}
+
+// WithMaxDepth sets the maximum depth of the derivations the interpreter is allowed to build for a request.
+func WithMaxDepth(maxDepth math.Uint) LimitsOption {
+	return func(i *Limits) {
+		i.MaxDepth = &maxDepth
+	}
+}
+
 // NewLimits creates a new Limits object.
 func NewLimits(opts ...LimitsOption) Limits {
 	l := Limits{}
@@ -176,6 +245,14 @@ func NewLimits(opts ...LimitsOption) Limits {
 		l.MaxResultCount = &DefaultMaxResultCount
 	}
 
+	if l.MaxInputSize == nil {
+		l.MaxInputSize = &DefaultMaxInputSize
+	}
+
+	if l.MaxCollectionSize == nil {
+		l.MaxCollectionSize = &DefaultMaxCollectionSize
+	}
+
 	return l
 }
 
@@ -188,3 +265,23 @@ func validateLimits(i interface{}) error {
 	// TODO: Validate limits params.
 	return nil
 }
+
+func validateGasPolicy(i interface{}) error {
+	gasPolicy, ok := i.(GasPolicy)
+	if !ok {
+		return fmt.Errorf("invalid parameter type: %T", i)
+	}
+
+	algorithms := make(map[string]struct{}, len(gasPolicy.AlgorithmCosts))
+	for _, cost := range gasPolicy.AlgorithmCosts {
+		if cost.Algorithm == "" {
+			return fmt.Errorf("invalid algorithm cost: empty algorithm name")
+		}
+		if _, ok := algorithms[cost.Algorithm]; ok {
+			return fmt.Errorf("duplicated algorithm cost: %s", cost.Algorithm)
+		}
+		algorithms[cost.Algorithm] = struct{}{}
+	}
+
+	return nil
+}
@@ -106,6 +106,25 @@ type Limits struct {
 	// this size, the interpreter will overwrite the oldest bytes with the new ones to keep the size constant.
 	// nil value or 0 value means that no user output is used at all.
 	MaxUserOutputSize *github_com_cosmos_cosmos_sdk_types.Uint `protobuf:"bytes,4,opt,name=max_user_output_size,json=maxUserOutputSize,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Uint" json:"max_user_output_size,omitempty" yaml:"max_user_output_size"`
+	// max_input_size specifies the maximum size, in bytes, of the data that is accepted as input by the predicates
+	// decoding bytes (e.g. for hashing or signature verification). Oversized inputs are rejected before being processed.
+	// nil value remove input size limitation.
+	MaxInputSize *github_com_cosmos_cosmos_sdk_types.Uint `protobuf:"bytes,5,opt,name=max_input_size,json=maxInputSize,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Uint" json:"max_input_size,omitempty" yaml:"max_input_size"`
+	// max_steps specifies the maximum number of execution steps the interpreter is allowed to perform when executing a
+	// request. Once exceeded, the execution is aborted with an error. As it only depends on the program being executed,
+	// the abortion is deterministic.
+	// nil value remove max steps limitation.
+	MaxSteps *github_com_cosmos_cosmos_sdk_types.Uint `protobuf:"bytes,6,opt,name=max_steps,json=maxSteps,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Uint" json:"max_steps,omitempty" yaml:"max_steps"`
+	// max_collection_size specifies the maximum number of elements of the collections generated by the combinatorial
+	// predicates (e.g. the subsets computed by powerset/3). Oversized collections are rejected before being generated.
+	// nil value remove collection size limitation.
+	MaxCollectionSize *github_com_cosmos_cosmos_sdk_types.Uint `protobuf:"bytes,7,opt,name=max_collection_size,json=maxCollectionSize,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Uint" json:"max_collection_size,omitempty" yaml:"max_collection_size"`
+	// max_depth specifies the maximum depth of the derivations the interpreter is allowed to build when executing a
+	// request, i.e. the number of predicates called along a branch of the resolution. Once exceeded, a
+	// resource_error(stack) exception is raised, which the program may catch. As it only depends on the program being
+	// executed, the exception is deterministic.
+	// nil value remove max depth limitation.
+	MaxDepth *github_com_cosmos_cosmos_sdk_types.Uint `protobuf:"bytes,8,opt,name=max_depth,json=maxDepth,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Uint" json:"max_depth,omitempty" yaml:"max_depth"`
 }
 
 func (m *Limits) Reset()         { *m = Limits{} }
@@ -223,6 +242,14 @@ type Interpreter struct {
 	// allowed, regardless of the rest of the components. But `file2` will not be allowed.
 	// If the component is not included in the filter, then the component is ignored and the next component is considered.
 	VirtualFilesFilter Filter `protobuf:"bytes,4,opt,name=virtual_files_filter,json=virtualFilesFilter,proto3" json:"virtual_files_filter" yaml:"filesystem_filter"`
+	// predicates_permissions specifies the filter for the predicates the programs are permitted to call, among the
+	// predicates allowed by the predicates_filter, represented in the same way. Unlike the predicates excluded by the
+	// predicates_filter, which do not exist in the interpreter, the predicates which are not permitted exist but raise a
+	// permission_error(execute, procedure, Predicate) when called, e.g. to sandbox the programs submitted by untrusted users.
+	// The cryptographic predicates (e.g. `sha_hash/2` or `eddsa_verify/4`) are always permitted, unless they are
+	// explicitly included in the blacklist.
+	// If this field is not specified, all predicates are permitted.
+	PredicatesPermissions Filter `protobuf:"bytes,5,opt,name=predicates_permissions,json=predicatesPermissions,proto3" json:"predicates_permissions" yaml:"predicates_permissions"`
 }
 
 func (m *Interpreter) Reset()         { *m = Interpreter{} }
@@ -279,6 +306,13 @@ func (m *Interpreter) GetVirtualFilesFilter() Filter {
 	return Filter{}
 }
 
+func (m *Interpreter) GetPredicatesPermissions() Filter {
+	if m != nil {
+		return m.PredicatesPermissions
+	}
+	return Filter{}
+}
+
 // GasPolicy defines the policy for calculating predicate invocation costs and the resulting gas consumption.
 // The gas policy is defined as a list of predicates and their associated unit costs, a default unit cost for predicates
 // if not specified in the list, and a weighting factor that is applied to the unit cost of each predicate to yield.
@@ -292,6 +326,9 @@ type GasPolicy struct {
 	DefaultPredicateCost *github_com_cosmos_cosmos_sdk_types.Uint `protobuf:"bytes,2,opt,name=default_predicate_cost,json=defaultPredicateCost,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Uint" json:"default_predicate_cost,omitempty" yaml:"default_predicate_cost"`
 	// PredicateCosts is the list of predicates and their associated unit costs.
 	PredicateCosts []PredicateCost `protobuf:"bytes,3,rep,name=predicate_costs,json=predicateCosts,proto3" json:"predicate_costs" yaml:"predicate_cost"`
+	// AlgorithmCosts is the list of algorithms (e.g. hashing, signature verification) and their associated costs,
+	// consumed by the predicates relying on them in proportion to the size of their input.
+	AlgorithmCosts []AlgorithmCost `protobuf:"bytes,4,rep,name=algorithm_costs,json=algorithmCosts,proto3" json:"algorithm_costs" yaml:"algorithm_costs"`
 }
 
 func (m *GasPolicy) Reset()         { *m = GasPolicy{} }
@@ -334,6 +371,13 @@ func (m *GasPolicy) GetPredicateCosts() []PredicateCost {
 	return nil
 }
 
+func (m *GasPolicy) GetAlgorithmCosts() []AlgorithmCost {
+	if m != nil {
+		return m.AlgorithmCosts
+	}
+	return nil
+}
+
 // PredicateCost defines the unit cost of a predicate during its invocation by the interpreter.
 type PredicateCost struct {
 	// Predicate is the name of the predicate, optionally followed by its arity (e.g. "findall/3").
@@ -383,6 +427,57 @@ func (m *PredicateCost) GetPredicate() string {
 	return ""
 }
 
+// AlgorithmCost defines the gas consumed by an algorithm (e.g. hashing, signature verification) when used by a predicate.
+// The gas consumed is the base cost plus the byte cost multiplied by the size of the input, in bytes.
+type AlgorithmCost struct {
+	// Algorithm is the name of the algorithm (e.g. "sha256", "ed25519").
+	Algorithm string `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty" yaml:"algorithm"`
+	// BaseCost is the fixed amount of gas consumed on each use of the algorithm.
+	BaseCost *github_com_cosmos_cosmos_sdk_types.Uint `protobuf:"bytes,2,opt,name=base_cost,json=baseCost,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Uint" json:"base_cost,omitempty" yaml:"base_cost",omitempty`
+	// ByteCost is the amount of gas consumed per byte of input processed by the algorithm.
+	ByteCost *github_com_cosmos_cosmos_sdk_types.Uint `protobuf:"bytes,3,opt,name=byte_cost,json=byteCost,proto3,customtype=github.com/cosmos/cosmos-sdk/types.Uint" json:"byte_cost,omitempty" yaml:"byte_cost",omitempty`
+}
+
+func (m *AlgorithmCost) Reset()         { *m = AlgorithmCost{} }
+func (m *AlgorithmCost) String() string { return proto.CompactTextString(m) }
+func (*AlgorithmCost) ProtoMessage()    {}
+func (*AlgorithmCost) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3af0daa241de0fa3, []int{6}
+}
+func (m *AlgorithmCost) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AlgorithmCost) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AlgorithmCost.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AlgorithmCost) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AlgorithmCost.Merge(m, src)
+}
+func (m *AlgorithmCost) XXX_Size() int {
+	return m.Size()
+}
+func (m *AlgorithmCost) XXX_DiscardUnknown() {
+	xxx_messageInfo_AlgorithmCost.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AlgorithmCost proto.InternalMessageInfo
+
+func (m *AlgorithmCost) GetAlgorithm() string {
+	if m != nil {
+		return m.Algorithm
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Params)(nil), "logic.v1beta2.Params")
 	proto.RegisterType((*Limits)(nil), "logic.v1beta2.Limits")
@@ -390,60 +485,74 @@ func init() {
 	proto.RegisterType((*Interpreter)(nil), "logic.v1beta2.Interpreter")
 	proto.RegisterType((*GasPolicy)(nil), "logic.v1beta2.GasPolicy")
 	proto.RegisterType((*PredicateCost)(nil), "logic.v1beta2.PredicateCost")
+	proto.RegisterType((*AlgorithmCost)(nil), "logic.v1beta2.AlgorithmCost")
 }
 
 func init() { proto.RegisterFile("logic/v1beta2/params.proto", fileDescriptor_3af0daa241de0fa3) }
 
 var fileDescriptor_3af0daa241de0fa3 = []byte{
-	// 759 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x4f, 0x6f, 0xd3, 0x48,
-	0x14, 0x8f, 0x93, 0x28, 0xbb, 0x9e, 0xa8, 0x6d, 0x3a, 0x4a, 0x77, 0xbd, 0xd9, 0x6d, 0x12, 0xcd,
-	0x1e, 0xe8, 0x01, 0x12, 0x51, 0x50, 0x0f, 0x95, 0xb8, 0xb8, 0xa8, 0xe5, 0x9f, 0x20, 0x32, 0xaa,
-	0x84, 0x10, 0x28, 0x9a, 0x38, 0x53, 0x77, 0x54, 0x3b, 0x63, 0x79, 0xc6, 0x6d, 0xd2, 0x0b, 0x12,
-	0x07, 0xce, 0x1c, 0x39, 0x70, 0xe0, 0xc2, 0x77, 0xa9, 0x38, 0xf5, 0x88, 0x38, 0x44, 0xa8, 0xfd,
-	0x04, 0xf4, 0x13, 0x20, 0x8f, 0x27, 0xb6, 0x63, 0x7a, 0x09, 0x97, 0xd6, 0x7a, 0xbf, 0xf7, 0xfb,
-	0x33, 0xcf, 0x6f, 0x62, 0xd0, 0x70, 0x99, 0x43, 0xed, 0xee, 0xf1, 0xed, 0x01, 0x11, 0x78, 0xb3,
-	0xeb, 0xe3, 0x00, 0x7b, 0xbc, 0xe3, 0x07, 0x4c, 0x30, 0xb8, 0x24, 0xb1, 0x8e, 0xc2, 0x1a, 0x75,
-	0x87, 0x39, 0x4c, 0x22, 0xdd, 0xe8, 0x29, 0x6e, 0x42, 0x6f, 0x8b, 0xa0, 0xd2, 0x93, 0x2c, 0xf8,
-	0x02, 0x54, 0xe9, 0x48, 0x90, 0xc0, 0x0f, 0x88, 0x20, 0x81, 0xa1, 0xb5, 0xb5, 0x8d, 0xea, 0x66,
-	0xa3, 0x33, 0xa7, 0xd2, 0x79, 0x98, 0x76, 0x98, 0x8d, 0xb3, 0x69, 0xab, 0x70, 0x35, 0x6d, 0xc1,
-	0x09, 0xf6, 0xdc, 0x6d, 0x94, 0x21, 0x23, 0x2b, 0x2b, 0x05, 0xef, 0x83, 0x8a, 0x4b, 0x3d, 0x2a,
-	0xb8, 0x51, 0x94, 0xa2, 0x6b, 0x39, 0xd1, 0x27, 0x12, 0x34, 0xd7, 0x94, 0xde, 0x52, 0xac, 0x17,
-	0x53, 0x90, 0xa5, 0xb8, 0xd0, 0x02, 0xc0, 0xc1, 0xbc, 0xef, 0x33, 0x97, 0xda, 0x13, 0xa3, 0x24,
-	0x95, 0x8c, 0x9c, 0xd2, 0x1e, 0xe6, 0x3d, 0x89, 0x9b, 0xff, 0x28, 0xb1, 0xd5, 0x58, 0x2c, 0x65,
-	0x22, 0x4b, 0x77, 0x66, 0x5d, 0xdb, 0xe5, 0x0f, 0x9f, 0x5a, 0x05, 0xf4, 0xa5, 0x04, 0x2a, 0x71,
-	0x06, 0x38, 0x04, 0x7f, 0x78, 0x78, 0xdc, 0x77, 0x30, 0x97, 0x03, 0xd0, 0xcd, 0xc7, 0x67, 0xd3,
-	0x96, 0xf6, 0x6d, 0xda, 0xba, 0xe1, 0x50, 0x71, 0x18, 0x0e, 0x3a, 0x36, 0xf3, 0xba, 0x36, 0xe3,
-	0x1e, 0xe3, 0xea, 0xdf, 0x2d, 0x3e, 0x3c, 0xea, 0x8a, 0x89, 0x4f, 0x78, 0x67, 0x9f, 0x8e, 0xc4,
-	0xd5, 0xb4, 0x65, 0xc4, 0x96, 0x4a, 0x07, 0xdd, 0x64, 0x1e, 0x15, 0xc4, 0xf3, 0xc5, 0xc4, 0xaa,
-	0x78, 0x78, 0xbc, 0x87, 0x39, 0x7c, 0x0d, 0xfe, 0x8c, 0x50, 0x4e, 0x4f, 0x89, 0x3c, 0x88, 0x6e,
-	0x9a, 0x8b, 0xdb, 0xac, 0xa4, 0x36, 0x91, 0x10, 0xb2, 0xa2, 0xe4, 0xcf, 0xe9, 0x29, 0x81, 0x02,
-	0xd4, 0xa2, 0x6a, 0x40, 0x78, 0xe8, 0x8a, 0xbe, 0xcd, 0xc2, 0x91, 0x90, 0x93, 0xd7, 0xcd, 0x47,
-	0x8b, 0xdb, 0xfc, 0x9d, 0xda, 0x64, 0x05, 0x91, 0xb5, 0xec, 0xe1, 0xb1, 0x25, 0x2b, 0x3b, 0x51,
-	0x01, 0xbe, 0x01, 0xf5, 0xa8, 0x29, 0xe4, 0x24, 0xe8, 0xb3, 0x50, 0xf8, 0xa1, 0x88, 0x0f, 0x58,
-	0x96, 0xce, 0x4f, 0x17, 0x77, 0xfe, 0x37, 0x75, 0xce, 0x8b, 0x22, 0x6b, 0xd5, 0xc3, 0xe3, 0x7d,
-	0x4e, 0x82, 0x67, 0xb2, 0x18, 0x1d, 0x5b, 0xbe, 0x4c, 0x0d, 0x8d, 0x41, 0x65, 0x97, 0xba, 0xd1,
-	0xda, 0x6d, 0x01, 0xfd, 0xe4, 0x90, 0x0a, 0xe2, 0x52, 0x2e, 0x0c, 0xad, 0x5d, 0xda, 0xd0, 0x4d,
-	0x23, 0x4a, 0x71, 0x35, 0x6d, 0xd5, 0x62, 0xe9, 0x04, 0x46, 0x56, 0xda, 0x1a, 0xf1, 0x06, 0x2e,
-	0xb6, 0x8f, 0x24, 0xaf, 0x78, 0x1d, 0x2f, 0x81, 0x91, 0x95, 0xb6, 0xa2, 0x8f, 0x45, 0x50, 0xcd,
-	0xdc, 0x0f, 0x38, 0x04, 0xab, 0x7e, 0x40, 0x86, 0xd4, 0xc6, 0x82, 0xf0, 0xfe, 0x81, 0x0c, 0xa5,
-	0xae, 0x55, 0xfe, 0x06, 0xc4, 0x89, 0xcd, 0xb6, 0x5a, 0x5a, 0xb5, 0x41, 0xbf, 0xb0, 0x91, 0x55,
-	0x4b, 0x6b, 0xe9, 0x29, 0x07, 0x8c, 0x09, 0x2e, 0x02, 0xec, 0xab, 0x65, 0xca, 0xa7, 0x9d, 0xc1,
-	0x51, 0xda, 0xd9, 0x33, 0xa4, 0xa0, 0x7e, 0x4c, 0x03, 0x11, 0x62, 0x37, 0x12, 0x4f, 0x03, 0x96,
-	0x17, 0x08, 0x28, 0x89, 0x13, 0x2e, 0x88, 0x97, 0x04, 0x84, 0x4a, 0x74, 0x37, 0x82, 0x62, 0x96,
-	0x7a, 0x31, 0x3f, 0x8a, 0x40, 0x4f, 0xee, 0x27, 0x0c, 0x41, 0xed, 0x84, 0x50, 0xe7, 0x50, 0xd0,
-	0x91, 0xd3, 0x3f, 0xc0, 0xb6, 0x60, 0x81, 0xba, 0x71, 0xbf, 0xbf, 0xa3, 0x79, 0x41, 0x64, 0xad,
-	0x24, 0xa5, 0x5d, 0x59, 0x81, 0xef, 0x34, 0xf0, 0xd7, 0x90, 0x1c, 0xe0, 0x68, 0x8f, 0x93, 0x51,
-	0xf6, 0x6d, 0xc6, 0x67, 0x37, 0xa4, 0xb7, 0xb8, 0xfb, 0x7a, 0xec, 0x7e, 0xbd, 0x2c, 0xb2, 0xea,
-	0x0a, 0xe8, 0xcd, 0xea, 0x3b, 0x8c, 0x0b, 0x38, 0x04, 0x2b, 0xf3, 0x8d, 0xdc, 0x28, 0xb5, 0x4b,
-	0x1b, 0xd5, 0xcd, 0xff, 0x72, 0x93, 0x9f, 0xa3, 0x99, 0xeb, 0xea, 0x05, 0xac, 0xe5, 0x36, 0x44,
-	0x79, 0x2d, 0xfb, 0xd9, 0x6e, 0x8e, 0x3e, 0x6b, 0x60, 0x69, 0xde, 0x77, 0x0b, 0xe8, 0x49, 0x8f,
-	0x1a, 0x78, 0x6e, 0x5d, 0x12, 0x18, 0x59, 0x69, 0x2b, 0x7c, 0x05, 0xca, 0x99, 0x29, 0x3d, 0x58,
-	0x7c, 0x4a, 0x2a, 0xb1, 0xcc, 0x99, 0xf9, 0x49, 0x94, 0xaa, 0xe6, 0xbd, 0xb3, 0x8b, 0xa6, 0x76,
-	0x7e, 0xd1, 0xd4, 0xbe, 0x5f, 0x34, 0xb5, 0xf7, 0x97, 0xcd, 0xc2, 0xf9, 0x65, 0xb3, 0xf0, 0xf5,
-	0xb2, 0x59, 0x78, 0xf9, 0x7f, 0xc6, 0x81, 0x1d, 0xf9, 0x77, 0xe5, 0x9f, 0x61, 0x77, 0xdc, 0x8d,
-	0xbf, 0x7c, 0xd2, 0x62, 0x50, 0x91, 0x1f, 0xb3, 0x3b, 0x3f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x4a,
-	0xe5, 0x9f, 0xfc, 0x0f, 0x07, 0x00, 0x00,
+	// 966 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x96, 0x4d, 0x6f, 0x1b, 0x45,
+	0x18, 0xc7, 0xb3, 0xb1, 0xeb, 0x66, 0x27, 0xe4, 0x6d, 0x88, 0xc3, 0x62, 0xa8, 0x1d, 0x0d, 0x42,
+	0xe4, 0x00, 0xb6, 0x08, 0xa8, 0x87, 0x4a, 0x1c, 0xd8, 0x54, 0x29, 0xe5, 0xd5, 0x9a, 0xaa, 0x12,
+	0x42, 0x20, 0x33, 0x5e, 0x4f, 0xd6, 0x43, 0x76, 0x3d, 0xab, 0x9d, 0x71, 0x6b, 0x57, 0x42, 0x48,
+	0x1c, 0x38, 0xc3, 0x8d, 0x1b, 0x5c, 0xf8, 0x2e, 0x39, 0xf6, 0x58, 0x71, 0xb0, 0x50, 0xf2, 0x0d,
+	0x7a, 0x47, 0x42, 0x33, 0x3b, 0xde, 0x59, 0x6f, 0x2d, 0x24, 0xb7, 0xbd, 0x24, 0xa3, 0xe7, 0xe5,
+	0xff, 0x7b, 0x9e, 0x79, 0x79, 0xbc, 0xa0, 0x11, 0xf1, 0x90, 0x05, 0x9d, 0x07, 0xef, 0xf7, 0xa9,
+	0x24, 0xc7, 0x9d, 0x84, 0xa4, 0x24, 0x16, 0xed, 0x24, 0xe5, 0x92, 0xc3, 0x2d, 0xed, 0x6b, 0x1b,
+	0x5f, 0x63, 0x3f, 0xe4, 0x21, 0xd7, 0x9e, 0x8e, 0x5a, 0x65, 0x41, 0xe8, 0xe7, 0x75, 0x50, 0xeb,
+	0xea, 0x2c, 0xf8, 0x35, 0xd8, 0x64, 0x23, 0x49, 0xd3, 0x24, 0xa5, 0x92, 0xa6, 0x9e, 0x73, 0xe8,
+	0x1c, 0x6d, 0x1e, 0x37, 0xda, 0x0b, 0x2a, 0xed, 0xbb, 0x36, 0xc2, 0x6f, 0x5c, 0xcc, 0x5a, 0x6b,
+	0x4f, 0x67, 0x2d, 0x38, 0x25, 0x71, 0x74, 0x0b, 0x15, 0x92, 0x11, 0x2e, 0x4a, 0xc1, 0xdb, 0xa0,
+	0x16, 0xb1, 0x98, 0x49, 0xe1, 0xad, 0x6b, 0xd1, 0x7a, 0x49, 0xf4, 0x73, 0xed, 0xf4, 0xeb, 0x46,
+	0x6f, 0x2b, 0xd3, 0xcb, 0x52, 0x10, 0x36, 0xb9, 0x10, 0x03, 0x10, 0x12, 0xd1, 0x4b, 0x78, 0xc4,
+	0x82, 0xa9, 0x57, 0xd1, 0x4a, 0x5e, 0x49, 0xe9, 0x0e, 0x11, 0x5d, 0xed, 0xf7, 0x5f, 0x37, 0x62,
+	0x7b, 0x99, 0x98, 0xcd, 0x44, 0xd8, 0x0d, 0xe7, 0x51, 0xb7, 0xaa, 0xbf, 0xff, 0xd9, 0x5a, 0x43,
+	0xff, 0xd6, 0x40, 0x2d, 0xab, 0x01, 0x0e, 0xc0, 0xf5, 0x98, 0x4c, 0x7a, 0x21, 0x11, 0x7a, 0x03,
+	0x5c, 0xff, 0xb3, 0x8b, 0x59, 0xcb, 0xf9, 0x7b, 0xd6, 0x7a, 0x27, 0x64, 0x72, 0x38, 0xee, 0xb7,
+	0x03, 0x1e, 0x77, 0x02, 0x2e, 0x62, 0x2e, 0xcc, 0xbf, 0xf7, 0xc4, 0xe0, 0xbc, 0x23, 0xa7, 0x09,
+	0x15, 0xed, 0xfb, 0x6c, 0x24, 0x9f, 0xce, 0x5a, 0x5e, 0x86, 0x34, 0x3a, 0xe8, 0x5d, 0x1e, 0x33,
+	0x49, 0xe3, 0x44, 0x4e, 0x71, 0x2d, 0x26, 0x93, 0x3b, 0x44, 0xc0, 0xef, 0xc0, 0x86, 0xf2, 0x0a,
+	0xf6, 0x88, 0xea, 0x46, 0x5c, 0xdf, 0x5f, 0x1d, 0xb3, 0x63, 0x31, 0x4a, 0x08, 0x61, 0x55, 0xf9,
+	0x3d, 0xf6, 0x88, 0x42, 0x09, 0x76, 0x95, 0x35, 0xa5, 0x62, 0x1c, 0xc9, 0x5e, 0xc0, 0xc7, 0x23,
+	0xa9, 0x77, 0xde, 0xf5, 0x3f, 0x5d, 0x1d, 0xf3, 0x9a, 0xc5, 0x14, 0x05, 0x11, 0xde, 0x8e, 0xc9,
+	0x04, 0x6b, 0xcb, 0x89, 0x32, 0xc0, 0x9f, 0xc0, 0xbe, 0x0a, 0x1a, 0x0b, 0x9a, 0xf6, 0xf8, 0x58,
+	0x26, 0x63, 0x99, 0x35, 0x58, 0xd5, 0xe4, 0x2f, 0x57, 0x27, 0xbf, 0x61, 0xc9, 0x65, 0x51, 0x84,
+	0xf7, 0x62, 0x32, 0xb9, 0x2f, 0x68, 0xfa, 0x95, 0x36, 0xea, 0xb6, 0x47, 0x40, 0x95, 0xd4, 0x63,
+	0xa3, 0x1c, 0x7d, 0x4d, 0xa3, 0x3f, 0x59, 0x1d, 0x5d, 0xb7, 0x68, 0x2b, 0x87, 0xf0, 0x2b, 0x31,
+	0x99, 0xdc, 0x1d, 0xcd, 0x79, 0xdf, 0x03, 0x57, 0x6f, 0xbe, 0xa4, 0x89, 0xf0, 0x6a, 0x1a, 0x75,
+	0xb2, 0x3a, 0x6a, 0xb7, 0x70, 0x8c, 0x4a, 0x09, 0x61, 0x75, 0x37, 0xee, 0xa9, 0x25, 0xfc, 0x11,
+	0xbc, 0xaa, 0xec, 0x01, 0x8f, 0x22, 0x1a, 0x48, 0xc6, 0x47, 0x59, 0x5b, 0xd7, 0x35, 0xeb, 0x8b,
+	0xd5, 0x59, 0x0d, 0xcb, 0x2a, 0x69, 0x66, 0x1b, 0x7a, 0x92, 0x1b, 0x8b, 0x0d, 0x0e, 0x68, 0x22,
+	0x87, 0xde, 0xc6, 0x4b, 0x68, 0x50, 0x2b, 0x65, 0x0d, 0xde, 0x56, 0x4b, 0xfd, 0xfe, 0x1c, 0x34,
+	0x01, 0xb5, 0x53, 0x16, 0xa9, 0x49, 0x71, 0x13, 0xb8, 0x0f, 0x87, 0x4c, 0xd2, 0x88, 0x09, 0xe9,
+	0x39, 0x87, 0x95, 0x23, 0xd7, 0xf7, 0x14, 0xd1, 0xca, 0xe4, 0x6e, 0x84, 0x6d, 0xa8, 0xca, 0xeb,
+	0x47, 0x24, 0x38, 0xd7, 0x79, 0xeb, 0xcb, 0xf2, 0x72, 0x37, 0xc2, 0x36, 0x14, 0xfd, 0x56, 0x01,
+	0x9b, 0x85, 0x91, 0x06, 0x07, 0x60, 0x2f, 0x49, 0xe9, 0x80, 0x05, 0x44, 0x52, 0xd1, 0x3b, 0xd3,
+	0x45, 0x79, 0xce, 0xd2, 0xa1, 0x95, 0x55, 0xec, 0x1f, 0x9a, 0x39, 0x63, 0x1e, 0xfd, 0x33, 0xd9,
+	0x08, 0xef, 0x5a, 0x9b, 0xed, 0xb2, 0xcf, 0xb9, 0x14, 0x32, 0x25, 0x89, 0x79, 0xff, 0xe5, 0x6a,
+	0xe7, 0x6e, 0x55, 0xed, 0x7c, 0x0d, 0x19, 0xd8, 0x7f, 0xc0, 0x52, 0x39, 0x26, 0x91, 0x12, 0xb7,
+	0x05, 0x56, 0x57, 0x28, 0x50, 0x27, 0x4e, 0x85, 0xa4, 0x71, 0x5e, 0x20, 0x34, 0xa2, 0xa7, 0xca,
+	0x65, 0x4a, 0x14, 0xe0, 0xa0, 0xd0, 0x4a, 0x42, 0xd3, 0x98, 0x09, 0xc1, 0xf8, 0x48, 0x78, 0xd7,
+	0xfe, 0x0f, 0xf6, 0xb6, 0x81, 0xdd, 0x78, 0x66, 0x37, 0x0a, 0x12, 0x08, 0xd7, 0xad, 0xa3, 0x6b,
+	0xed, 0xe6, 0x36, 0x3c, 0xa9, 0x00, 0x37, 0x9f, 0xe3, 0x70, 0x0c, 0x76, 0x1f, 0x52, 0x16, 0x0e,
+	0x25, 0x1b, 0x85, 0xbd, 0x33, 0x12, 0x48, 0x9e, 0x7a, 0xce, 0x0b, 0xce, 0xb2, 0xb2, 0x20, 0xc2,
+	0x3b, 0xb9, 0xe9, 0x54, 0x5b, 0xe0, 0x2f, 0x0e, 0x38, 0x18, 0xd0, 0x33, 0xa2, 0xe6, 0x5d, 0x5e,
+	0x6c, 0x2f, 0xe0, 0x62, 0x3e, 0x49, 0xbb, 0xab, 0xd3, 0xcd, 0xa6, 0x2c, 0x97, 0x45, 0x78, 0xdf,
+	0x38, 0xba, 0x73, 0xfb, 0x09, 0x17, 0x12, 0x0e, 0xc0, 0xce, 0x62, 0xa0, 0xf0, 0x2a, 0x87, 0x95,
+	0xa3, 0xcd, 0xe3, 0x37, 0x4b, 0x27, 0xb0, 0x90, 0xe6, 0xdf, 0x30, 0x07, 0x51, 0x2f, 0x1d, 0x84,
+	0x61, 0x6d, 0x27, 0xc5, 0x68, 0x01, 0x29, 0xd8, 0x21, 0x51, 0xc8, 0x53, 0x26, 0x87, 0xb1, 0xa1,
+	0x54, 0x97, 0x52, 0x3e, 0x9e, 0x47, 0x69, 0x4a, 0xd3, 0x50, 0x0e, 0x32, 0x4a, 0x49, 0x02, 0xe1,
+	0x6d, 0x52, 0x0c, 0x17, 0xe8, 0x2f, 0x07, 0x6c, 0x2d, 0xb6, 0x77, 0x13, 0xb8, 0x79, 0x29, 0x9e,
+	0xb3, 0xec, 0x29, 0xe4, 0x6e, 0x84, 0x6d, 0x28, 0xfc, 0x16, 0x54, 0x0b, 0x87, 0xf1, 0xfc, 0x13,
+	0x5e, 0x6f, 0x47, 0xe1, 0x17, 0x5a, 0xab, 0xa2, 0x3f, 0xd6, 0xc1, 0xd6, 0x42, 0xa7, 0xaa, 0xce,
+	0xbc, 0x97, 0xe5, 0x75, 0xe6, 0x6e, 0x84, 0x6d, 0x28, 0xfc, 0x01, 0xb8, 0x7d, 0x22, 0x16, 0x6e,
+	0xce, 0xf3, 0xcf, 0xed, 0x5c, 0xa9, 0x58, 0xf1, 0x86, 0xb2, 0xea, 0x1a, 0x15, 0x6b, 0x3a, 0xbf,
+	0xa5, 0x95, 0x17, 0x65, 0x4d, 0xe5, 0x32, 0xd6, 0xd4, 0xdc, 0xaf, 0x8f, 0xbe, 0x79, 0xab, 0x20,
+	0xc9, 0xcf, 0x93, 0x0f, 0xf5, 0x9f, 0x41, 0x67, 0xd2, 0xc9, 0x3e, 0x49, 0xb5, 0xe6, 0xc5, 0x65,
+	0xd3, 0x79, 0x7c, 0xd9, 0x74, 0xfe, 0xb9, 0x6c, 0x3a, 0xbf, 0x5e, 0x35, 0xd7, 0x1e, 0x5f, 0x35,
+	0xd7, 0x9e, 0x5c, 0x35, 0xd7, 0xfa, 0x35, 0xfd, 0xf5, 0xf9, 0xc1, 0x7f, 0x03, 0x00, 0xca, 0x37,
+	0x51, 0xc9, 0xc0, 0x0a, 0x00, 0x00,
 }
 
 func (m *Params) Marshal() (dAtA []byte, err error) {
@@ -519,6 +628,54 @@ func (m *Limits) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.MaxDepth != nil {
+		{
+			size := m.MaxDepth.Size()
+			i -= size
+			if _, err := m.MaxDepth.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintParams(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.MaxCollectionSize != nil {
+		{
+			size := m.MaxCollectionSize.Size()
+			i -= size
+			if _, err := m.MaxCollectionSize.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintParams(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3a
+	}
+	if m.MaxSteps != nil {
+		{
+			size := m.MaxSteps.Size()
+			i -= size
+			if _, err := m.MaxSteps.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintParams(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	if m.MaxInputSize != nil {
+		{
+			size := m.MaxInputSize.Size()
+			i -= size
+			if _, err := m.MaxInputSize.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintParams(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x2a
+	}
 	if m.MaxUserOutputSize != nil {
 		{
 			size := m.MaxUserOutputSize.Size()
@@ -631,6 +788,16 @@ func (m *Interpreter) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	{
+		size, err := m.PredicatesPermissions.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintParams(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x2a
 	{
 		size, err := m.VirtualFilesFilter.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -681,6 +848,20 @@ func (m *GasPolicy) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.AlgorithmCosts) > 0 {
+		for iNdEx := len(m.AlgorithmCosts) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.AlgorithmCosts[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintParams(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if len(m.PredicateCosts) > 0 {
 		for iNdEx := len(m.PredicateCosts) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -764,6 +945,60 @@ func (m *PredicateCost) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *AlgorithmCost) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AlgorithmCost) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AlgorithmCost) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.ByteCost != nil {
+		{
+			size := m.ByteCost.Size()
+			i -= size
+			if _, err := m.ByteCost.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintParams(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.BaseCost != nil {
+		{
+			size := m.BaseCost.Size()
+			i -= size
+			if _, err := m.BaseCost.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintParams(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Algorithm) > 0 {
+		i -= len(m.Algorithm)
+		copy(dAtA[i:], m.Algorithm)
+		i = encodeVarintParams(dAtA, i, uint64(len(m.Algorithm)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintParams(dAtA []byte, offset int, v uint64) int {
 	offset -= sovParams(v)
 	base := offset
@@ -812,6 +1047,22 @@ func (m *Limits) Size() (n int) {
 		l = m.MaxUserOutputSize.Size()
 		n += 1 + l + sovParams(uint64(l))
 	}
+	if m.MaxInputSize != nil {
+		l = m.MaxInputSize.Size()
+		n += 1 + l + sovParams(uint64(l))
+	}
+	if m.MaxSteps != nil {
+		l = m.MaxSteps.Size()
+		n += 1 + l + sovParams(uint64(l))
+	}
+	if m.MaxCollectionSize != nil {
+		l = m.MaxCollectionSize.Size()
+		n += 1 + l + sovParams(uint64(l))
+	}
+	if m.MaxDepth != nil {
+		l = m.MaxDepth.Size()
+		n += 1 + l + sovParams(uint64(l))
+	}
 	return n
 }
 
@@ -850,6 +1101,8 @@ func (m *Interpreter) Size() (n int) {
 	}
 	l = m.VirtualFilesFilter.Size()
 	n += 1 + l + sovParams(uint64(l))
+	l = m.PredicatesPermissions.Size()
+	n += 1 + l + sovParams(uint64(l))
 	return n
 }
 
@@ -873,6 +1126,12 @@ func (m *GasPolicy) Size() (n int) {
 			n += 1 + l + sovParams(uint64(l))
 		}
 	}
+	if len(m.AlgorithmCosts) > 0 {
+		for _, e := range m.AlgorithmCosts {
+			l = e.Size()
+			n += 1 + l + sovParams(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -893,6 +1152,27 @@ func (m *PredicateCost) Size() (n int) {
 	return n
 }
 
+func (m *AlgorithmCost) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Algorithm)
+	if l > 0 {
+		n += 1 + l + sovParams(uint64(l))
+	}
+	if m.BaseCost != nil {
+		l = m.BaseCost.Size()
+		n += 1 + l + sovParams(uint64(l))
+	}
+	if m.ByteCost != nil {
+		l = m.ByteCost.Size()
+		n += 1 + l + sovParams(uint64(l))
+	}
+	return n
+}
+
 func sovParams(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1221,6 +1501,150 @@ func (m *Limits) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxInputSize", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthParams
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthParams
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v github_com_cosmos_cosmos_sdk_types.Uint
+			m.MaxInputSize = &v
+			if err := m.MaxInputSize.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSteps", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthParams
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthParams
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v github_com_cosmos_cosmos_sdk_types.Uint
+			m.MaxSteps = &v
+			if err := m.MaxSteps.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxCollectionSize", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthParams
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthParams
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v github_com_cosmos_cosmos_sdk_types.Uint
+			m.MaxCollectionSize = &v
+			if err := m.MaxCollectionSize.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxDepth", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthParams
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthParams
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v github_com_cosmos_cosmos_sdk_types.Uint
+			m.MaxDepth = &v
+			if err := m.MaxDepth.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipParams(dAtA[iNdEx:])
@@ -1483,6 +1907,39 @@ func (m *Interpreter) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PredicatesPermissions", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthParams
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthParams
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.PredicatesPermissions.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipParams(dAtA[iNdEx:])
@@ -1639,6 +2096,40 @@ func (m *GasPolicy) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AlgorithmCosts", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthParams
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthParams
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AlgorithmCosts = append(m.AlgorithmCosts, AlgorithmCost{})
+			if err := m.AlgorithmCosts[len(m.AlgorithmCosts)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipParams(dAtA[iNdEx:])
@@ -1778,6 +2269,160 @@ func (m *PredicateCost) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *AlgorithmCost) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowParams
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AlgorithmCost: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AlgorithmCost: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Algorithm", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthParams
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthParams
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Algorithm = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BaseCost", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthParams
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthParams
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v github_com_cosmos_cosmos_sdk_types.Uint
+			m.BaseCost = &v
+			if err := m.BaseCost.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ByteCost", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowParams
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthParams
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthParams
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			var v github_com_cosmos_cosmos_sdk_types.Uint
+			m.ByteCost = &v
+			if err := m.ByteCost.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipParams(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthParams
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipParams(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
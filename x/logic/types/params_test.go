@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/samber/lo"
+
 	. "github.com/smartystreets/goconvey/convey"
 
 	"cosmossdk.io/math"
@@ -33,6 +35,7 @@ func TestValidateParams(t *testing.T) {
 						types.WithPredicatesBlacklist([]string{"halt/1"}),
 						types.WithPredicatesWhitelist([]string{"source_file/1"}),
 						types.WithVirtualFilesBlacklist([]string{"file1"}),
+						types.WithPredicatesPermissionsBlacklist([]string{"consult/1"}),
 						types.WithVirtualFilesWhitelist([]string{"file2"}),
 					),
 					types.NewLimits(
@@ -40,6 +43,10 @@ func TestValidateParams(t *testing.T) {
 						types.WithMaxSize(math.NewUint(2)),
 						types.WithMaxResultCount(math.NewUint(3)),
 						types.WithMaxUserOutputSize(math.NewUint(4)),
+						types.WithMaxInputSize(math.NewUint(5)),
+						types.WithMaxSteps(math.NewUint(6)),
+						types.WithMaxCollectionSize(math.NewUint(7)),
+						types.WithMaxDepth(math.NewUint(8)),
 					),
 				),
 				expectErr: false,
@@ -67,6 +74,48 @@ func TestValidateParams(t *testing.T) {
 				expectErr: true,
 				err:       fmt.Errorf("invalid virtual file in whitelist: https://foo{bar/"),
 			},
+			{
+				name: "validate gas policy with algorithm costs",
+				params: types.Params{
+					Interpreter: types.NewInterpreter(),
+					Limits:      types.NewLimits(),
+					GasPolicy: types.GasPolicy{
+						AlgorithmCosts: []types.AlgorithmCost{
+							{Algorithm: "sha256", BaseCost: lo.ToPtr(math.NewUint(10)), ByteCost: lo.ToPtr(math.NewUint(1))},
+							{Algorithm: "ed25519", BaseCost: lo.ToPtr(math.NewUint(100))},
+						},
+					},
+				},
+				expectErr: false,
+				err:       nil,
+			},
+			{
+				name: "validate gas policy with an unnamed algorithm cost",
+				params: types.Params{
+					Interpreter: types.NewInterpreter(),
+					Limits:      types.NewLimits(),
+					GasPolicy: types.GasPolicy{
+						AlgorithmCosts: []types.AlgorithmCost{{BaseCost: lo.ToPtr(math.NewUint(10))}},
+					},
+				},
+				expectErr: true,
+				err:       fmt.Errorf("invalid algorithm cost: empty algorithm name"),
+			},
+			{
+				name: "validate gas policy with duplicated algorithm costs",
+				params: types.Params{
+					Interpreter: types.NewInterpreter(),
+					Limits:      types.NewLimits(),
+					GasPolicy: types.GasPolicy{
+						AlgorithmCosts: []types.AlgorithmCost{
+							{Algorithm: "sha256", BaseCost: lo.ToPtr(math.NewUint(10))},
+							{Algorithm: "sha256", ByteCost: lo.ToPtr(math.NewUint(1))},
+						},
+					},
+				},
+				expectErr: true,
+				err:       fmt.Errorf("duplicated algorithm cost: sha256"),
+			},
 		}
 
 		for nc, tc := range cases {
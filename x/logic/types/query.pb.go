@@ -118,6 +118,15 @@ type QueryServiceAskRequest struct {
 	Program string `protobuf:"bytes,1,opt,name=program,proto3" json:"program,omitempty" yaml:"program",omitempty`
 	// query is the query string to be executed.
 	Query string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty" yaml:"query",omitempty`
+	// limit is the maximum number of solutions to return, which cannot exceed the max_result_count limit.
+	// 0 value returns as many solutions as the max_result_count limit permits.
+	Limit uint64 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty" yaml:"limit",omitempty`
+	// offset is the number of solutions to skip before returning the following ones, so that the solutions of a query
+	// can be retrieved page by page, each page being requested with the offset of the previous one increased by the number
+	// of solutions it holds. As the solutions are found in an order only depending on the program, the query and the state
+	// they are executed against, the pages of a query executed at the same height are consistent with each other.
+	// The skipped solutions are computed anyway, and thus consume gas.
+	Offset uint64 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty" yaml:"offset",omitempty`
 }
 
 func (m *QueryServiceAskRequest) Reset()         { *m = QueryServiceAskRequest{} }
@@ -167,6 +176,20 @@ func (m *QueryServiceAskRequest) GetQuery() string {
 	return ""
 }
 
+func (m *QueryServiceAskRequest) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *QueryServiceAskRequest) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
 // QueryServiceAskResponse is response type for the QueryService/Ask RPC method.
 type QueryServiceAskResponse struct {
 	// height is the block height at which the query was executed.
@@ -241,50 +264,168 @@ func (m *QueryServiceAskResponse) GetUserOutput() string {
 	return ""
 }
 
+// QueryServiceEstimateGasRequest is request type for the QueryService/EstimateGas RPC method.
+type QueryServiceEstimateGasRequest struct {
+	// program is the logic program to be queried.
+	Program string `protobuf:"bytes,1,opt,name=program,proto3" json:"program,omitempty" yaml:"program",omitempty`
+	// query is the query string to be executed.
+	Query string `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty" yaml:"query",omitempty`
+}
+
+func (m *QueryServiceEstimateGasRequest) Reset()         { *m = QueryServiceEstimateGasRequest{} }
+func (m *QueryServiceEstimateGasRequest) String() string { return proto.CompactTextString(m) }
+func (*QueryServiceEstimateGasRequest) ProtoMessage()    {}
+func (*QueryServiceEstimateGasRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_008a54e610b23239, []int{4}
+}
+func (m *QueryServiceEstimateGasRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryServiceEstimateGasRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryServiceEstimateGasRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryServiceEstimateGasRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryServiceEstimateGasRequest.Merge(m, src)
+}
+func (m *QueryServiceEstimateGasRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryServiceEstimateGasRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryServiceEstimateGasRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryServiceEstimateGasRequest proto.InternalMessageInfo
+
+func (m *QueryServiceEstimateGasRequest) GetProgram() string {
+	if m != nil {
+		return m.Program
+	}
+	return ""
+}
+
+func (m *QueryServiceEstimateGasRequest) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+// QueryServiceEstimateGasResponse is response type for the QueryService/EstimateGas RPC method.
+type QueryServiceEstimateGasResponse struct {
+	// height is the block height at which the query was executed.
+	Height uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty" yaml:"height",omitempty`
+	// gas_used is the amount of gas used to execute the query.
+	GasUsed uint64 `protobuf:"varint,2,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty" yaml:"gas_used",omitempty`
+}
+
+func (m *QueryServiceEstimateGasResponse) Reset()         { *m = QueryServiceEstimateGasResponse{} }
+func (m *QueryServiceEstimateGasResponse) String() string { return proto.CompactTextString(m) }
+func (*QueryServiceEstimateGasResponse) ProtoMessage()    {}
+func (*QueryServiceEstimateGasResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_008a54e610b23239, []int{5}
+}
+func (m *QueryServiceEstimateGasResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryServiceEstimateGasResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryServiceEstimateGasResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryServiceEstimateGasResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryServiceEstimateGasResponse.Merge(m, src)
+}
+func (m *QueryServiceEstimateGasResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryServiceEstimateGasResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryServiceEstimateGasResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryServiceEstimateGasResponse proto.InternalMessageInfo
+
+func (m *QueryServiceEstimateGasResponse) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *QueryServiceEstimateGasResponse) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*QueryServiceParamsRequest)(nil), "logic.v1beta2.QueryServiceParamsRequest")
 	proto.RegisterType((*QueryServiceParamsResponse)(nil), "logic.v1beta2.QueryServiceParamsResponse")
 	proto.RegisterType((*QueryServiceAskRequest)(nil), "logic.v1beta2.QueryServiceAskRequest")
 	proto.RegisterType((*QueryServiceAskResponse)(nil), "logic.v1beta2.QueryServiceAskResponse")
+	proto.RegisterType((*QueryServiceEstimateGasRequest)(nil), "logic.v1beta2.QueryServiceEstimateGasRequest")
+	proto.RegisterType((*QueryServiceEstimateGasResponse)(nil), "logic.v1beta2.QueryServiceEstimateGasResponse")
 }
 
 func init() { proto.RegisterFile("logic/v1beta2/query.proto", fileDescriptor_008a54e610b23239) }
 
 var fileDescriptor_008a54e610b23239 = []byte{
-	// 526 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x53, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0x8d, 0xd3, 0x10, 0x60, 0x0b, 0x97, 0x15, 0x6d, 0x1c, 0x37, 0xb5, 0x23, 0x23, 0x50, 0x91,
-	0x90, 0x0d, 0x29, 0x42, 0xa8, 0x12, 0x87, 0xf8, 0xc8, 0x81, 0x8f, 0x22, 0x2e, 0x5c, 0xaa, 0x4d,
-	0xb2, 0xda, 0x58, 0x89, 0xbd, 0xae, 0x77, 0x5d, 0xc8, 0x09, 0x09, 0x71, 0xe0, 0x88, 0xc4, 0x85,
-	0x0b, 0x12, 0x3f, 0xa7, 0xc7, 0x4a, 0x5c, 0x38, 0x59, 0x28, 0xe1, 0x17, 0xf8, 0x17, 0xa0, 0xec,
-	0x6c, 0x54, 0xbb, 0x2d, 0x1f, 0x97, 0x28, 0x9a, 0xf7, 0xde, 0xbc, 0x37, 0xe3, 0x59, 0xd4, 0x9e,
-	0x72, 0x16, 0x0e, 0xfd, 0xa3, 0xfb, 0x03, 0x2a, 0x49, 0xcf, 0x3f, 0xcc, 0x68, 0x3a, 0xf3, 0x92,
-	0x94, 0x4b, 0x8e, 0xaf, 0x2b, 0xc8, 0xd3, 0x90, 0x75, 0x83, 0x71, 0xc6, 0x15, 0xe2, 0x2f, 0xff,
-	0x01, 0xc9, 0xea, 0x30, 0xce, 0xd9, 0x94, 0xfa, 0x24, 0x09, 0x7d, 0x12, 0xc7, 0x5c, 0x12, 0x19,
-	0xf2, 0x58, 0x68, 0xd4, 0xaa, 0x76, 0x4f, 0x48, 0x4a, 0xa2, 0x15, 0x76, 0xc6, 0x59, 0xce, 0x12,
-	0xaa, 0x21, 0x77, 0x0b, 0xb5, 0x5f, 0x2c, 0x83, 0xbc, 0xa4, 0xe9, 0x51, 0x38, 0xa4, 0xcf, 0x95,
-	0x6c, 0x9f, 0x1e, 0x66, 0x54, 0x48, 0x77, 0x8a, 0xac, 0x8b, 0x40, 0x91, 0xf0, 0x58, 0x50, 0xfc,
-	0x14, 0x35, 0xc1, 0xc5, 0x34, 0xba, 0xc6, 0xce, 0x7a, 0x6f, 0xc3, 0xab, 0x4c, 0xe1, 0x01, 0x3d,
-	0x70, 0x8e, 0x73, 0xa7, 0x56, 0xe4, 0x4e, 0x6b, 0x46, 0xa2, 0xe9, 0x9e, 0x0b, 0x12, 0xf7, 0x2e,
-	0x8f, 0x42, 0x49, 0xa3, 0x44, 0xce, 0xf6, 0x75, 0x17, 0xf7, 0xa3, 0x81, 0x36, 0xcb, 0x76, 0x7d,
-	0x31, 0xd1, 0x41, 0xf0, 0x43, 0x74, 0x39, 0x49, 0x39, 0x4b, 0x49, 0xa4, 0xbc, 0xae, 0x06, 0x9d,
-	0x22, 0x77, 0x4c, 0xdd, 0x10, 0x80, 0x72, 0xc7, 0x15, 0x19, 0xdf, 0x43, 0x97, 0xd4, 0x9a, 0xcd,
-	0xba, 0x52, 0x59, 0x45, 0xee, 0x6c, 0x82, 0x4a, 0x95, 0xcb, 0x1a, 0x20, 0xee, 0x35, 0xbe, 0x7c,
-	0x73, 0x0c, 0xf7, 0x6b, 0x1d, 0xb5, 0xce, 0x45, 0xd1, 0x63, 0xef, 0xa2, 0xe6, 0x98, 0x86, 0x6c,
-	0x2c, 0x55, 0x94, 0x46, 0xb0, 0x75, 0x3a, 0x1b, 0xd4, 0x2b, 0xb3, 0x41, 0x09, 0x3f, 0x42, 0x57,
-	0x18, 0x11, 0x07, 0x99, 0xa0, 0x23, 0x95, 0xa5, 0x11, 0x6c, 0x17, 0xb9, 0xd3, 0x06, 0xd9, 0x0a,
-	0xa9, 0x8c, 0xc0, 0x88, 0x78, 0x25, 0xe8, 0x08, 0x3f, 0x41, 0x4d, 0x12, 0x8b, 0x37, 0x34, 0x35,
-	0xd7, 0x2e, 0xdc, 0x72, 0x5f, 0x81, 0xe5, 0x14, 0x40, 0xaf, 0xa4, 0x80, 0x12, 0xee, 0xa3, 0xf5,
-	0x4c, 0xd0, 0xf4, 0x80, 0x67, 0x32, 0xc9, 0xa4, 0xd9, 0x50, 0x4b, 0xe9, 0x16, 0xb9, 0xd3, 0x01,
-	0x65, 0x09, 0x2c, 0xcb, 0xd1, 0xb2, 0xfe, 0x4c, 0x95, 0x61, 0x3f, 0xbd, 0x0f, 0x75, 0x74, 0xad,
-	0xbc, 0x1f, 0xfc, 0x0e, 0x35, 0xe1, 0x73, 0xe3, 0x9d, 0x33, 0xf9, 0xfe, 0x78, 0x5d, 0xd6, 0x9d,
-	0xff, 0x60, 0xc2, 0xce, 0xdd, 0xee, 0xfb, 0xef, 0xbf, 0x3e, 0xd7, 0x2d, 0x6c, 0xfa, 0x7c, 0x92,
-	0x3c, 0x50, 0x3f, 0x23, 0x1f, 0x8e, 0x1a, 0x8e, 0x07, 0x0b, 0xb4, 0xd6, 0x17, 0x13, 0x7c, 0xeb,
-	0x2f, 0x3d, 0x4f, 0xef, 0xc9, 0xba, 0xfd, 0x2f, 0x9a, 0xf6, 0xdd, 0x56, 0xbe, 0x2d, 0xbc, 0x71,
-	0xde, 0x97, 0x88, 0x49, 0xf0, 0xf8, 0x78, 0x6e, 0x1b, 0x27, 0x73, 0xdb, 0xf8, 0x39, 0xb7, 0x8d,
-	0x4f, 0x0b, 0xbb, 0x76, 0xb2, 0xb0, 0x6b, 0x3f, 0x16, 0x76, 0xed, 0xf5, 0x4d, 0x16, 0xca, 0x71,
-	0x36, 0xf0, 0x86, 0x3c, 0x2a, 0x4b, 0xdf, 0x6a, 0xb1, 0x7a, 0x81, 0x83, 0xa6, 0x7a, 0x82, 0xbb,
-	0xbf, 0x03, 0x00, 0x00, 0xff, 0xff, 0x12, 0x6b, 0x07, 0x1c, 0x19, 0x04, 0x00, 0x00,
+	// 621 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x54, 0x4d, 0x6b, 0x13, 0x4f,
+	0x18, 0xcf, 0x34, 0xf9, 0xef, 0xdf, 0x4e, 0xf5, 0x32, 0xd8, 0x66, 0xb3, 0x4d, 0x77, 0xc3, 0x8a,
+	0x25, 0x82, 0xee, 0x6a, 0x22, 0x22, 0x05, 0x0f, 0x09, 0x88, 0xe0, 0xc1, 0x97, 0x8a, 0x17, 0x2f,
+	0x61, 0x92, 0x4c, 0x37, 0x4b, 0xb2, 0x99, 0xed, 0xce, 0x6c, 0x35, 0x27, 0xc1, 0x4f, 0x50, 0x50,
+	0x44, 0x10, 0xc1, 0x8f, 0xd3, 0x63, 0xc1, 0x8b, 0xa7, 0x45, 0x12, 0xef, 0x42, 0x3e, 0x81, 0x64,
+	0x66, 0x42, 0x77, 0x93, 0xb6, 0xf6, 0xa4, 0x97, 0x10, 0x7e, 0x2f, 0xcf, 0xf3, 0x7b, 0x9e, 0xd9,
+	0x19, 0x58, 0x1a, 0x50, 0xcf, 0xef, 0xb8, 0x07, 0x77, 0xda, 0x84, 0xe3, 0x9a, 0xbb, 0x1f, 0x93,
+	0x68, 0xe4, 0x84, 0x11, 0xe5, 0x14, 0x5d, 0x11, 0x94, 0xa3, 0x28, 0xe3, 0xaa, 0x47, 0x3d, 0x2a,
+	0x18, 0x77, 0xf6, 0x4f, 0x8a, 0x8c, 0xb2, 0x47, 0xa9, 0x37, 0x20, 0x2e, 0x0e, 0x7d, 0x17, 0x0f,
+	0x87, 0x94, 0x63, 0xee, 0xd3, 0x21, 0x53, 0xac, 0x91, 0xad, 0x1e, 0xe2, 0x08, 0x07, 0x73, 0x6e,
+	0xa1, 0x33, 0x1f, 0x85, 0x44, 0x51, 0xf6, 0x26, 0x2c, 0x3d, 0x9f, 0x05, 0x79, 0x41, 0xa2, 0x03,
+	0xbf, 0x43, 0x9e, 0x09, 0xdb, 0x2e, 0xd9, 0x8f, 0x09, 0xe3, 0xf6, 0x00, 0x1a, 0xa7, 0x91, 0x2c,
+	0xa4, 0x43, 0x46, 0xd0, 0x13, 0xa8, 0xc9, 0x2e, 0x3a, 0xa8, 0x80, 0xea, 0x5a, 0x6d, 0xdd, 0xc9,
+	0x4c, 0xe1, 0x48, 0x79, 0xd3, 0x3a, 0x4a, 0xac, 0xdc, 0x34, 0xb1, 0x8a, 0x23, 0x1c, 0x0c, 0x76,
+	0x6c, 0x69, 0xb1, 0x6f, 0xd2, 0xc0, 0xe7, 0x24, 0x08, 0xf9, 0x68, 0x57, 0x55, 0xb1, 0x7f, 0x01,
+	0xb8, 0x91, 0x6e, 0xd7, 0x60, 0x7d, 0x15, 0x04, 0xdd, 0x83, 0xff, 0x87, 0x11, 0xf5, 0x22, 0x1c,
+	0x88, 0x5e, 0xab, 0xcd, 0xf2, 0x34, 0xb1, 0x74, 0x55, 0x50, 0x12, 0xe9, 0x8a, 0x73, 0x31, 0xba,
+	0x0d, 0xff, 0x13, 0x6b, 0xd6, 0x57, 0x84, 0xcb, 0x98, 0x26, 0xd6, 0x86, 0x74, 0x09, 0x38, 0xed,
+	0x91, 0xc2, 0x99, 0x63, 0xe0, 0x07, 0x3e, 0xd7, 0xf3, 0x15, 0x50, 0x2d, 0xa4, 0x1d, 0x02, 0xce,
+	0x38, 0x04, 0x82, 0xea, 0x50, 0xa3, 0x7b, 0x7b, 0x8c, 0x70, 0xbd, 0x20, 0x2c, 0x9b, 0x27, 0xb3,
+	0x4a, 0x3c, 0x33, 0xab, 0x84, 0x76, 0x0a, 0x9f, 0xbe, 0x5a, 0xc0, 0xfe, 0xb2, 0x02, 0x8b, 0x4b,
+	0x13, 0xab, 0xed, 0xd6, 0xa1, 0xd6, 0x23, 0xbe, 0xd7, 0xe3, 0x3a, 0x58, 0x2c, 0x2b, 0xf1, 0x4c,
+	0x59, 0x09, 0xa1, 0xfb, 0xf0, 0x92, 0x87, 0x59, 0x2b, 0x66, 0xa4, 0x2b, 0x46, 0x2e, 0x34, 0xb7,
+	0xa6, 0x89, 0x55, 0x92, 0xb6, 0x39, 0x93, 0xd9, 0x94, 0x87, 0xd9, 0x4b, 0x46, 0xba, 0xe8, 0x31,
+	0xd4, 0xf0, 0x90, 0xbd, 0x26, 0x91, 0x9e, 0x3f, 0xf5, 0x30, 0x1b, 0x82, 0x4c, 0xa7, 0x90, 0xf2,
+	0x4c, 0x0a, 0x09, 0xa1, 0x06, 0x5c, 0x8b, 0x19, 0x89, 0x5a, 0x34, 0xe6, 0x61, 0x2c, 0xd7, 0xb2,
+	0xda, 0xac, 0x4c, 0x13, 0xab, 0x2c, 0x9d, 0x29, 0x32, 0x6d, 0x87, 0x33, 0xfc, 0xa9, 0x80, 0xd5,
+	0x7e, 0x0e, 0x01, 0x34, 0xd3, 0xfb, 0x79, 0xc8, 0xb8, 0x1f, 0x60, 0x4e, 0x1e, 0x61, 0xf6, 0xd7,
+	0xbf, 0x0c, 0x15, 0xe9, 0x23, 0x80, 0xd6, 0x99, 0x91, 0xfe, 0xc9, 0xd1, 0xc9, 0x60, 0xb5, 0xcf,
+	0x79, 0x78, 0x39, 0x1d, 0x0c, 0xbd, 0x85, 0x9a, 0xbc, 0x81, 0xa8, 0xba, 0x70, 0x96, 0x67, 0x5e,
+	0x78, 0xe3, 0xc6, 0x05, 0x94, 0x72, 0x48, 0xbb, 0xf2, 0xee, 0xdb, 0xcf, 0xf7, 0x2b, 0x06, 0xd2,
+	0x5d, 0xda, 0x0f, 0xef, 0x8a, 0x9f, 0xae, 0x2b, 0xdf, 0x19, 0x79, 0x9f, 0x11, 0x83, 0xf9, 0x06,
+	0xeb, 0xa3, 0xeb, 0xe7, 0xd4, 0x3c, 0xb9, 0xe2, 0xc6, 0xf6, 0x9f, 0x64, 0xaa, 0xef, 0x96, 0xe8,
+	0x5b, 0x44, 0xeb, 0xcb, 0x7d, 0x31, 0xeb, 0xa3, 0x0f, 0x00, 0xae, 0xa5, 0xce, 0x04, 0xdd, 0x3a,
+	0xa7, 0xec, 0xf2, 0xe7, 0x64, 0x38, 0x17, 0x95, 0xab, 0x34, 0xdb, 0x22, 0x4d, 0x05, 0x99, 0xcb,
+	0x69, 0x88, 0x92, 0xb7, 0x3c, 0xcc, 0x9a, 0x0f, 0x5e, 0x5d, 0xf3, 0x7c, 0xde, 0x8b, 0xdb, 0x4e,
+	0x87, 0x06, 0x69, 0xed, 0x1b, 0xa5, 0x16, 0x6f, 0xf2, 0xd1, 0xd8, 0x04, 0xc7, 0x63, 0x13, 0xfc,
+	0x18, 0x9b, 0xe0, 0x70, 0x62, 0xe6, 0x8e, 0x27, 0x66, 0xee, 0xfb, 0xc4, 0xcc, 0xb5, 0x35, 0xf1,
+	0x58, 0xd7, 0x7f, 0x0f, 0x00, 0x0f, 0xc7, 0xd1, 0x0a, 0x43, 0x06, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -305,6 +446,11 @@ type QueryServiceClient interface {
 	// Since the query is without any side-effect, the query is not executed in the context of a transaction and no fee
 	// is charged for this, but the execution is constrained by the current limits configured in the module.
 	Ask(ctx context.Context, in *QueryServiceAskRequest, opts ...grpc.CallOption) (*QueryServiceAskResponse, error)
+	// EstimateGas executes a logic query as Ask does, and returns the amount of gas consumed by its execution, so that
+	// the gas required by the query can be known beforehand, in the same way as the simulation of a transaction.
+	// The execution is constrained by the current limits configured in the module, including the max_gas limit, so that
+	// an estimation does not cost more to the node than the query itself: a query exceeding it fails as Ask does.
+	EstimateGas(ctx context.Context, in *QueryServiceEstimateGasRequest, opts ...grpc.CallOption) (*QueryServiceEstimateGasResponse, error)
 }
 
 type queryServiceClient struct {
@@ -333,6 +479,15 @@ func (c *queryServiceClient) Ask(ctx context.Context, in *QueryServiceAskRequest
 	return out, nil
 }
 
+func (c *queryServiceClient) EstimateGas(ctx context.Context, in *QueryServiceEstimateGasRequest, opts ...grpc.CallOption) (*QueryServiceEstimateGasResponse, error) {
+	out := new(QueryServiceEstimateGasResponse)
+	err := c.cc.Invoke(ctx, "/logic.v1beta2.QueryService/EstimateGas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QueryServiceServer is the server API for QueryService service.
 type QueryServiceServer interface {
 	// Params queries all parameters for the logic module.
@@ -341,6 +496,11 @@ type QueryServiceServer interface {
 	// Since the query is without any side-effect, the query is not executed in the context of a transaction and no fee
 	// is charged for this, but the execution is constrained by the current limits configured in the module.
 	Ask(context.Context, *QueryServiceAskRequest) (*QueryServiceAskResponse, error)
+	// EstimateGas executes a logic query as Ask does, and returns the amount of gas consumed by its execution, so that
+	// the gas required by the query can be known beforehand, in the same way as the simulation of a transaction.
+	// The execution is constrained by the current limits configured in the module, including the max_gas limit, so that
+	// an estimation does not cost more to the node than the query itself: a query exceeding it fails as Ask does.
+	EstimateGas(context.Context, *QueryServiceEstimateGasRequest) (*QueryServiceEstimateGasResponse, error)
 }
 
 // UnimplementedQueryServiceServer can be embedded to have forward compatible implementations.
@@ -353,6 +513,9 @@ func (*UnimplementedQueryServiceServer) Params(ctx context.Context, req *QuerySe
 func (*UnimplementedQueryServiceServer) Ask(ctx context.Context, req *QueryServiceAskRequest) (*QueryServiceAskResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ask not implemented")
 }
+func (*UnimplementedQueryServiceServer) EstimateGas(ctx context.Context, req *QueryServiceEstimateGasRequest) (*QueryServiceEstimateGasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateGas not implemented")
+}
 
 func RegisterQueryServiceServer(s grpc1.Server, srv QueryServiceServer) {
 	s.RegisterService(&_QueryService_serviceDesc, srv)
@@ -394,6 +557,24 @@ func _QueryService_Ask_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _QueryService_EstimateGas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryServiceEstimateGasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QueryServiceServer).EstimateGas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/logic.v1beta2.QueryService/EstimateGas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QueryServiceServer).EstimateGas(ctx, req.(*QueryServiceEstimateGasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _QueryService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "logic.v1beta2.QueryService",
 	HandlerType: (*QueryServiceServer)(nil),
@@ -406,6 +587,10 @@ var _QueryService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Ask",
 			Handler:    _QueryService_Ask_Handler,
 		},
+		{
+			MethodName: "EstimateGas",
+			Handler:    _QueryService_EstimateGas_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "logic/v1beta2/query.proto",
@@ -487,6 +672,16 @@ func (m *QueryServiceAskRequest) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	_ = i
 	var l int
 	_ = l
+	if m.Offset != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Offset))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.Limit != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x18
+	}
 	if len(m.Query) > 0 {
 		i -= len(m.Query)
 		copy(dAtA[i:], m.Query)
@@ -556,6 +751,76 @@ func (m *QueryServiceAskResponse) MarshalToSizedBuffer(dAtA []byte) (int, error)
 	return len(dAtA) - i, nil
 }
 
+func (m *QueryServiceEstimateGasRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryServiceEstimateGasRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryServiceEstimateGasRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Program) > 0 {
+		i -= len(m.Program)
+		copy(dAtA[i:], m.Program)
+		i = encodeVarintQuery(dAtA, i, uint64(len(m.Program)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *QueryServiceEstimateGasResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryServiceEstimateGasResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryServiceEstimateGasResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.GasUsed != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.GasUsed))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Height != 0 {
+		i = encodeVarintQuery(dAtA, i, uint64(m.Height))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintQuery(dAtA []byte, offset int, v uint64) int {
 	offset -= sovQuery(v)
 	base := offset
@@ -601,6 +866,12 @@ func (m *QueryServiceAskRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovQuery(uint64(l))
 	}
+	if m.Limit != 0 {
+		n += 1 + sovQuery(uint64(m.Limit))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovQuery(uint64(m.Offset))
+	}
 	return n
 }
 
@@ -627,6 +898,38 @@ func (m *QueryServiceAskResponse) Size() (n int) {
 	return n
 }
 
+func (m *QueryServiceEstimateGasRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Program)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovQuery(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryServiceEstimateGasResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovQuery(uint64(m.Height))
+	}
+	if m.GasUsed != 0 {
+		n += 1 + sovQuery(uint64(m.GasUsed))
+	}
+	return n
+}
+
 func sovQuery(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -859,6 +1162,44 @@ func (m *QueryServiceAskRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.Query = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQuery(dAtA[iNdEx:])
@@ -1036,6 +1377,208 @@ func (m *QueryServiceAskResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *QueryServiceEstimateGasRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryServiceEstimateGasRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryServiceEstimateGasRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Program", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Program = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Query", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthQuery
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Query = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryServiceEstimateGasResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowQuery
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryServiceEstimateGasResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryServiceEstimateGasResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GasUsed", wireType)
+			}
+			m.GasUsed = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQuery
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GasUsed |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipQuery(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthQuery
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipQuery(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -25,7 +25,8 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 
 // Term is the representation of a piece of data and can be a constant, a variable, or an atom.
 type Term struct {
-	// name is the name of the term.
+	// name is the name of the term, which, for the term a variable is substituted with, is the rendering of the whole
+	// term in the Prolog syntax (e.g. foo(bar,[1,2])).
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty" yaml:"name",omitempty`
 	// arguments are the arguments of the term, which can be constants, variables, or atoms.
 	Arguments []Term `protobuf:"bytes,2,rep,name=arguments,proto3" json:"arguments" yaml:"arguments",omitempty`
@@ -4,9 +4,17 @@ import (
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
+	"crypto/subtle"
+	"encoding/hex"
 	"fmt"
+	"math/big"
+	"strings"
 
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secp256k1ecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
 	"github.com/dustinxie/ecc"
+	"github.com/hdevalence/ed25519consensus"
+	"golang.org/x/crypto/sha3"
 )
 
 // Alg is the type of algorithm supported by the crypto util functions.
@@ -24,6 +32,13 @@ const (
 )
 
 // VerifySignature verifies the signature of the given message with the given public key using the given algorithm.
+//
+// The Ed25519 signatures are validated according to the criteria of ZIP 215, as for the consensus, so that their
+// verification one by one agrees with their batch verification.
+//
+// All its inputs are public, so that its timing does not leak any secret: the Ed25519 and ECDSA verifications only
+// depend on the key, the message and the signature, and compare the computed values with the signature through field
+// and point arithmetic rather than byte comparisons returning early.
 func VerifySignature(alg Alg, pubKey []byte, msg, sig []byte) (_ bool, err error) {
 	defer func() {
 		if recoveredErr := recover(); recoveredErr != nil {
@@ -33,7 +48,10 @@ func VerifySignature(alg Alg, pubKey []byte, msg, sig []byte) (_ bool, err error
 
 	switch alg {
 	case Ed25519:
-		return ed25519.Verify(pubKey, msg, sig), nil
+		if len(pubKey) != ed25519.PublicKeySize {
+			return false, fmt.Errorf("ed25519: bad public key length: %d", len(pubKey))
+		}
+		return ed25519consensus.Verify(pubKey, msg, sig), nil
 	case Secp256r1:
 		return verifySignatureWithCurve(elliptic.P256(), pubKey, msg, sig)
 	case Secp256k1:
@@ -43,13 +61,59 @@ func VerifySignature(alg Alg, pubKey []byte, msg, sig []byte) (_ bool, err error
 	}
 }
 
+// NormalizePublicKey returns the given ECDSA public key of the given algorithm (in compressed or uncompressed form
+// specified in section 4.3.6 of ANSI X9.62) in the compressed form if compressed is true, or in the uncompressed form
+// otherwise. The key is checked to be a valid point of the curve, as for the verification of a signature.
+func NormalizePublicKey(alg Alg, pubKey []byte, compressed bool) (_ []byte, err error) {
+	defer func() {
+		if recoveredErr := recover(); recoveredErr != nil {
+			err = fmt.Errorf("%s", recoveredErr)
+		}
+	}()
+
+	var curve elliptic.Curve
+	switch alg {
+	case Secp256r1:
+		curve = elliptic.P256()
+	case Secp256k1:
+		curve = ecc.P256k1()
+	default:
+		return nil, fmt.Errorf("algo %s does not support public key normalization", alg)
+	}
+
+	x, y, err := unmarshalPublicKey(curve, pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	byteLen := (curve.Params().BitSize + 7) / 8
+	if compressed {
+		key := make([]byte, 1+byteLen)
+		key[0] = byte(0x02 | y.Bit(0))
+		x.FillBytes(key[1:])
+		return key, nil
+	}
+	key := make([]byte, 1+2*byteLen)
+	key[0] = 0x04
+	x.FillBytes(key[1 : 1+byteLen])
+	y.FillBytes(key[1+byteLen:])
+	return key, nil
+}
+
+// ConstantTimeEqual reports whether the two given byte slices are equal, in a time which only depends on their
+// lengths, so that the comparison of a secret, or of a value derived from a secret such as a MAC, does not leak how
+// many of its leading bytes match.
+func ConstantTimeEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
 // verifySignatureWithCurve verifies the ASN1 signature of the given message with the given
-// public key (in compressed form specified in section 4.3.6 of ANSI X9.62.) using the given
+// public key (in compressed or uncompressed form specified in section 4.3.6 of ANSI X9.62.) using the given
 // elliptic curve.
 func verifySignatureWithCurve(curve elliptic.Curve, pubKey, msg, sig []byte) (bool, error) {
-	x, y := ecc.UnmarshalCompressed(curve, pubKey)
-	if x == nil || y == nil {
-		return false, fmt.Errorf("failed to parse compressed public key (first 10 bytes): %x", pubKey[:10])
+	x, y, err := unmarshalPublicKey(curve, pubKey)
+	if err != nil {
+		return false, err
 	}
 
 	pk := &ecdsa.PublicKey{
@@ -60,3 +124,125 @@ func verifySignatureWithCurve(curve elliptic.Curve, pubKey, msg, sig []byte) (bo
 
 	return ecc.VerifyASN1(pk, msg, sig), nil
 }
+
+// unmarshalPublicKey decodes the given public key, encoded either in compressed form (0x02 or 0x03 prefix followed by
+// the X coordinate) or in uncompressed form (0x04 prefix followed by the X and Y coordinates) as specified in section
+// 4.3.6 of ANSI X9.62, into a point of the given elliptic curve. The form is detected by the length and the prefix.
+func unmarshalPublicKey(curve elliptic.Curve, pubKey []byte) (*big.Int, *big.Int, error) {
+	byteLen := (curve.Params().BitSize + 7) / 8
+	compressedLen, uncompressedLen := 1+byteLen, 1+2*byteLen
+
+	switch len(pubKey) {
+	case compressedLen:
+		if pubKey[0] != 0x02 && pubKey[0] != 0x03 {
+			return nil, nil, fmt.Errorf("invalid compressed public key prefix: 0x%02x, expected 0x02 or 0x03", pubKey[0])
+		}
+		x, y := ecc.UnmarshalCompressed(curve, pubKey)
+		if x == nil || y == nil {
+			return nil, nil, fmt.Errorf("failed to parse compressed public key (first 10 bytes): %x", pubKey[:10])
+		}
+		return x, y, validatePoint(curve, x, y)
+	case uncompressedLen:
+		if pubKey[0] != 0x04 {
+			return nil, nil, fmt.Errorf("invalid uncompressed public key prefix: 0x%02x, expected 0x04", pubKey[0])
+		}
+		x := new(big.Int).SetBytes(pubKey[1:compressedLen])
+		y := new(big.Int).SetBytes(pubKey[compressedLen:])
+		return x, y, validatePoint(curve, x, y)
+	default:
+		return nil, nil, fmt.Errorf("invalid public key length: %d, expected %d (compressed) or %d (uncompressed)",
+			len(pubKey), compressedLen, uncompressedLen)
+	}
+}
+
+// validatePoint checks that the given point is a valid public key of the given elliptic curve, i.e. that its
+// coordinates are reduced modulo the field prime, that it is on the curve, that it is not the point at infinity and
+// that it belongs to the subgroup of prime order generated by the base point.
+//
+// The check does not rely on the verification of the signature, whose behavior with an invalid point depends on the
+// implementation of the curve, so that an invalid public key is always rejected explicitly.
+func validatePoint(curve elliptic.Curve, x, y *big.Int) error {
+	params := curve.Params()
+	switch {
+	case x.Sign() < 0 || y.Sign() < 0 || x.Cmp(params.P) >= 0 || y.Cmp(params.P) >= 0:
+		return fmt.Errorf("invalid public key: coordinates out of the field range")
+	case x.Sign() == 0 && y.Sign() == 0:
+		return fmt.Errorf("invalid public key: point at infinity")
+	case !curve.IsOnCurve(x, y):
+		return fmt.Errorf("invalid public key: point not on the %s curve", params.Name)
+	}
+
+	// n·P must be the point at infinity, represented as (0, 0), for P to be in the subgroup of order n.
+	if nx, ny := curve.ScalarMult(x, y, params.N.Bytes()); nx.Sign() != 0 || ny.Sign() != 0 {
+		return fmt.Errorf("invalid public key: point not in the prime order subgroup of the %s curve", params.Name)
+	}
+
+	return nil
+}
+
+// RecoverPublicKey recovers the public key (in compressed form specified in section 4.3.6 of ANSI X9.62) which
+// produced the given recoverable signature of the given message hash using the given algorithm.
+// Only the secp256k1 algorithm supports public key recovery, with a 65-byte signature in the [R || S || V] form, where
+// V is the recovery id (either 0, 1, 27 or 28).
+func RecoverPublicKey(alg Alg, hash, sig []byte) ([]byte, error) {
+	switch alg {
+	case Secp256k1:
+		if len(sig) != 65 {
+			return nil, fmt.Errorf("invalid recoverable signature length: %d, expected 65", len(sig))
+		}
+		v := sig[64]
+		if v >= 27 {
+			v -= 27
+		}
+		if v > 1 {
+			return nil, fmt.Errorf("invalid recovery id: %d", sig[64])
+		}
+
+		// the compact form expected by the secp256k1 ecdsa package is [27 + 4 + V || R || S] for compressed public keys.
+		compact := make([]byte, 0, 65)
+		compact = append(compact, 27+4+v)
+		compact = append(compact, sig[:64]...)
+
+		pubKey, _, err := secp256k1ecdsa.RecoverCompact(compact, hash)
+		if err != nil {
+			return nil, err
+		}
+
+		return pubKey.SerializeCompressed(), nil
+	default:
+		return nil, fmt.Errorf("algo %s does not support public key recovery", alg)
+	}
+}
+
+// EthereumAddress returns the 20-byte Ethereum address of the given secp256k1 public key (in compressed or
+// uncompressed form specified in section 4.3.6 of ANSI X9.62), i.e. the last 20 bytes of the Keccak-256 hash of its
+// uncompressed form, without its prefix.
+func EthereumAddress(pubKey []byte) ([]byte, error) {
+	key, err := secp256k1.ParsePubKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(key.SerializeUncompressed()[1:])
+	return hash.Sum(nil)[12:], nil
+}
+
+// EthereumChecksumAddress returns the textual representation of the given 20-byte Ethereum address, with the mixed
+// case checksum specified by EIP-55, e.g. 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed.
+func EthereumChecksumAddress(address []byte) string {
+	lower := hex.EncodeToString(address)
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(lower))
+	digest := hash.Sum(nil)
+
+	var sb strings.Builder
+	sb.WriteString("0x")
+	for i, c := range lower {
+		if c >= 'a' && digest[i/2]>>(4*(1-i%2))&0x0f >= 8 {
+			c -= 'a' - 'A'
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
@@ -0,0 +1,210 @@
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secp256k1ecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/dustinxie/ecc"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestVerifySignatureRejectsInvalidPoints(t *testing.T) {
+	Convey("Given a message signed with secp256r1 and secp256k1 keys", t, func() {
+		digest := sha256.Sum256([]byte("hello okp4"))
+
+		r1Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+		r1Sig, err := ecdsa.SignASN1(rand.Reader, r1Key, digest[:])
+		So(err, ShouldBeNil)
+
+		k1Key := secp256k1.PrivKeyFromBytes([]byte("0123456789abcdef0123456789abcdef"))
+		k1Sig := secp256k1ecdsa.Sign(k1Key, digest[:]).Serialize()
+
+		uncompressed := func(x, y *big.Int) []byte {
+			key := make([]byte, 65)
+			key[0] = 0x04
+			x.FillBytes(key[1:33])
+			y.FillBytes(key[33:])
+			return key
+		}
+		r1Params, k1Params := elliptic.P256().Params(), ecc.P256k1().Params()
+
+		cases := []struct {
+			name      string
+			alg       Alg
+			pubKey    []byte
+			sig       []byte
+			wantValid bool
+			wantError error
+		}{
+			{
+				name:      "a valid uncompressed secp256r1 key",
+				alg:       Secp256r1,
+				pubKey:    uncompressed(r1Key.X, r1Key.Y),
+				sig:       r1Sig,
+				wantValid: true,
+			},
+			{
+				name:      "a valid compressed secp256k1 key",
+				alg:       Secp256k1,
+				pubKey:    k1Key.PubKey().SerializeCompressed(),
+				sig:       k1Sig,
+				wantValid: true,
+			},
+			{
+				name:      "a secp256r1 point not on the curve",
+				alg:       Secp256r1,
+				pubKey:    uncompressed(r1Key.X, new(big.Int).Add(r1Key.Y, big.NewInt(1))),
+				sig:       r1Sig,
+				wantError: fmt.Errorf("invalid public key: point not on the P-256 curve"),
+			},
+			{
+				name:      "a secp256k1 point not on the curve",
+				alg:       Secp256k1,
+				pubKey:    uncompressed(k1Params.Gx, big.NewInt(1)),
+				sig:       k1Sig,
+				wantError: fmt.Errorf("invalid public key: point not on the P-256k1 curve"),
+			},
+			{
+				name:      "the secp256r1 point at infinity",
+				alg:       Secp256r1,
+				pubKey:    uncompressed(big.NewInt(0), big.NewInt(0)),
+				sig:       r1Sig,
+				wantError: fmt.Errorf("invalid public key: point at infinity"),
+			},
+			{
+				name:      "the secp256k1 point at infinity",
+				alg:       Secp256k1,
+				pubKey:    uncompressed(big.NewInt(0), big.NewInt(0)),
+				sig:       k1Sig,
+				wantError: fmt.Errorf("invalid public key: point at infinity"),
+			},
+			{
+				name:      "a secp256r1 point with an unreduced coordinate",
+				alg:       Secp256r1,
+				pubKey:    uncompressed(r1Params.P, r1Key.Y),
+				sig:       r1Sig,
+				wantError: fmt.Errorf("invalid public key: coordinates out of the field range"),
+			},
+			{
+				name:      "a secp256k1 point with an unreduced coordinate",
+				alg:       Secp256k1,
+				pubKey:    uncompressed(k1Params.P, big.NewInt(7)),
+				sig:       k1Sig,
+				wantError: fmt.Errorf("invalid public key: coordinates out of the field range"),
+			},
+			{
+				name:      "a compressed secp256r1 key with an unreduced X",
+				alg:       Secp256r1,
+				pubKey:    append([]byte{0x02}, r1Params.P.Bytes()...),
+				sig:       r1Sig,
+				wantError: fmt.Errorf("failed to parse compressed public key (first 10 bytes): 02ffffffff0000000100"),
+			},
+		}
+		for _, tc := range cases {
+			Convey(fmt.Sprintf("When verifying the signature with %s", tc.name), func() {
+				valid, err := VerifySignature(tc.alg, tc.pubKey, digest[:], tc.sig)
+
+				if tc.wantError != nil {
+					Convey("Then the key should be rejected", func() {
+						So(err, ShouldBeError, tc.wantError.Error())
+						So(valid, ShouldBeFalse)
+					})
+				} else {
+					Convey("Then the signature should be valid", func() {
+						So(err, ShouldBeNil)
+						So(valid, ShouldEqual, tc.wantValid)
+					})
+				}
+			})
+		}
+	})
+}
+
+func TestNormalizePublicKey(t *testing.T) {
+	Convey("Given secp256r1 and secp256k1 keys", t, func() {
+		r1Key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		So(err, ShouldBeNil)
+		r1Compressed := elliptic.MarshalCompressed(elliptic.P256(), r1Key.X, r1Key.Y)
+		r1Uncompressed := make([]byte, 65)
+		r1Uncompressed[0] = 0x04
+		r1Key.X.FillBytes(r1Uncompressed[1:33])
+		r1Key.Y.FillBytes(r1Uncompressed[33:])
+
+		k1Key := secp256k1.PrivKeyFromBytes([]byte("0123456789abcdef0123456789abcdef")).PubKey()
+
+		cases := []struct {
+			name         string
+			alg          Alg
+			compressed   []byte
+			uncompressed []byte
+		}{
+			{name: "secp256r1", alg: Secp256r1, compressed: r1Compressed, uncompressed: r1Uncompressed},
+			{name: "secp256k1", alg: Secp256k1, compressed: k1Key.SerializeCompressed(), uncompressed: k1Key.SerializeUncompressed()},
+		}
+		for _, tc := range cases {
+			Convey(fmt.Sprintf("When normalizing both forms of the %s key", tc.name), func() {
+				for _, key := range [][]byte{tc.compressed, tc.uncompressed} {
+					compressed, err := NormalizePublicKey(tc.alg, key, true)
+					So(err, ShouldBeNil)
+					uncompressed, err := NormalizePublicKey(tc.alg, key, false)
+					So(err, ShouldBeNil)
+
+					Convey(fmt.Sprintf("Then the %d-byte key should give the same forms", len(key)), func() {
+						So(compressed, ShouldResemble, tc.compressed)
+						So(uncompressed, ShouldResemble, tc.uncompressed)
+					})
+				}
+			})
+		}
+
+		Convey("When normalizing a key with an unsupported algorithm", func() {
+			_, err := NormalizePublicKey(Ed25519, r1Compressed, true)
+
+			Convey("Then an error should be returned", func() {
+				So(err, ShouldBeError, "algo ed25519 does not support public key normalization")
+			})
+		})
+
+		Convey("When normalizing a secp256r1 key as a secp256k1 one", func() {
+			_, err := NormalizePublicKey(Secp256k1, r1Uncompressed, true)
+
+			Convey("Then the key should be rejected", func() {
+				So(err, ShouldBeError, "invalid public key: point not on the P-256k1 curve")
+			})
+		})
+	})
+}
+
+func TestConstantTimeEqual(t *testing.T) {
+	Convey("Given byte slices to compare", t, func() {
+		cases := []struct {
+			a, b     []byte
+			expected bool
+		}{
+			{nil, nil, true},
+			{[]byte{}, nil, true},
+			{[]byte{1, 2, 3}, []byte{1, 2, 3}, true},
+			{[]byte{1, 2, 3}, []byte{1, 2, 4}, false},
+			{[]byte{1, 2, 3}, []byte{0, 2, 3}, false},
+			{[]byte{1, 2, 3}, []byte{1, 2}, false},
+			{[]byte{1, 2}, []byte{1, 2, 3}, false},
+			{nil, []byte{0}, false},
+		}
+		for _, tc := range cases {
+			Convey(fmt.Sprintf("When comparing %#v with %#v", tc.a, tc.b), func() {
+				Convey(fmt.Sprintf("Then the result should be %t", tc.expected), func() {
+					So(ConstantTimeEqual(tc.a, tc.b), ShouldEqual, tc.expected)
+				})
+			})
+		}
+	})
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/ichiban/prolog/engine"
@@ -13,6 +14,15 @@ var (
 
 	// AtomEmpty is the term used to represent empty.
 	AtomEmpty = engine.NewAtom("")
+
+	// AtomEmptyList is the term used to represent an empty list.
+	AtomEmptyList = engine.NewAtom("[]")
+
+	// AtomList is the term used to indicate the list type in a type error.
+	AtomList = engine.NewAtom("list")
+
+	// AtomOption is the term used to indicate the option domain in a domain error.
+	AtomOption = engine.NewAtom("option")
 )
 
 // StringToTerm converts a string to a term.
@@ -59,7 +69,7 @@ func IsList(compound engine.Compound) bool {
 // GetOption returns the value of the first option with the given name in the given options.
 // An option is a compound with the given name as functor and one argument which is
 // a term, for instance `opt(v)`.
-// The options are either a list of options or an option.
+// The options are either a list of options (possibly empty) or an option.
 // If no option is found nil is returned.
 func GetOption(name engine.Atom, options engine.Term, env *engine.Env) (engine.Term, error) {
 	extractOption := func(term engine.Term) (engine.Term, error) {
@@ -81,6 +91,9 @@ func GetOption(name engine.Atom, options engine.Term, env *engine.Env) (engine.T
 	}
 
 	resolvedTerm := env.Resolve(options)
+	if resolvedTerm == AtomEmptyList {
+		return nil, nil
+	}
 
 	compound, ok := resolvedTerm.(engine.Compound)
 	if ok && IsList(compound) {
@@ -114,3 +127,45 @@ func GetOptionWithDefault(name engine.Atom, options engine.Term, defaultValue en
 	}
 	return defaultValue, nil
 }
+
+// CheckOptions checks that the given options are well-formed and only made of the allowed ones, so that a misspelled
+// option is reported instead of being silently ignored by GetOption.
+// The options are either a list of options (possibly empty) or an option, an option being a compound with one argument
+// whose name is among the allowed ones.
+// It returns an instantiation error if the options are not sufficiently instantiated, a type_error(list, Options) if
+// they are neither a list nor an option, and a domain_error(option, Option) for a malformed or unknown option.
+func CheckOptions(options engine.Term, allowed []engine.Atom, env *engine.Env) error {
+	checkOption := func(term engine.Term) error {
+		switch v := env.Resolve(term).(type) {
+		case engine.Variable:
+			return engine.InstantiationError(env)
+		case engine.Compound:
+			if v.Arity() == 1 && slices.Contains(allowed, v.Functor()) {
+				return nil
+			}
+		}
+		return engine.DomainError(AtomOption, term, env)
+	}
+
+	switch v := env.Resolve(options).(type) {
+	case engine.Variable:
+		return engine.InstantiationError(env)
+	case engine.Compound:
+		if !IsList(v) {
+			return checkOption(v)
+		}
+	default:
+		if v != AtomEmptyList {
+			return engine.TypeError(AtomList, options, env)
+		}
+		return nil
+	}
+
+	iter := engine.ListIterator{List: options, Env: env}
+	for iter.Next() {
+		if err := checkOption(iter.Current()); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/ichiban/prolog/engine"
@@ -111,6 +112,12 @@ func TestGetOption(t *testing.T) {
 				wantResult: nil,
 				wantError:  nil,
 			},
+			{
+				option:     engine.NewAtom("foo"),
+				options:    engine.List(),
+				wantResult: nil,
+				wantError:  nil,
+			},
 			{
 				option:     engine.NewAtom("foo"),
 				options:    engine.NewAtom("foo"),
@@ -167,3 +174,80 @@ func TestGetOption(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckOptions(t *testing.T) {
+	Convey("Given a test cases", t, func() {
+		allowed := []engine.Atom{engine.NewAtom("foo"), engine.NewAtom("bar")}
+		cases := []struct {
+			options   engine.Term
+			wantError string
+		}{
+			{
+				options: engine.List(),
+			},
+			{
+				options: engine.NewAtom("foo").Apply(engine.NewAtom("x")),
+			},
+			{
+				options: engine.List(
+					engine.NewAtom("foo").Apply(engine.NewAtom("x")),
+					engine.NewAtom("bar").Apply(engine.NewVariable()),
+					engine.NewAtom("foo").Apply(engine.NewAtom("y"))),
+			},
+			{
+				options:   engine.List(engine.NewAtom("foo").Apply(engine.NewAtom("x")), engine.NewAtom("fooo").Apply(engine.NewAtom("y"))),
+				wantError: "domain_error(option,fooo(y))",
+			},
+			{
+				options:   engine.NewAtom("baz").Apply(engine.NewAtom("x")),
+				wantError: "domain_error(option,baz(x))",
+			},
+			{
+				options:   engine.List(engine.NewAtom("foo").Apply(engine.NewAtom("x"), engine.NewAtom("y"))),
+				wantError: "domain_error(option,foo(x,y))",
+			},
+			{
+				options:   engine.List(engine.NewAtom("foo")),
+				wantError: "domain_error(option,foo)",
+			},
+			{
+				options:   engine.NewAtom("foo"),
+				wantError: "type_error(list,foo)",
+			},
+			{
+				options:   engine.NewVariable(),
+				wantError: "instantiation_error",
+			},
+			{
+				options:   engine.List(engine.NewAtom("foo").Apply(engine.NewAtom("x")), engine.NewVariable()),
+				wantError: "instantiation_error",
+			},
+			{
+				options:   engine.PartialList(engine.NewVariable(), engine.NewAtom("foo").Apply(engine.NewAtom("x"))),
+				wantError: "instantiation_error",
+			},
+		}
+		for nc, tc := range cases {
+			Convey(fmt.Sprintf("Given the options #%d", nc), func() {
+				Convey("when checking the options", func() {
+					env := engine.Env{}
+					err := CheckOptions(tc.options, allowed, &env)
+
+					if tc.wantError == "" {
+						Convey("then no error should be thrown", func() {
+							So(err, ShouldBeNil)
+						})
+					} else {
+						Convey("then the expected error should be thrown", func() {
+							So(err, ShouldHaveSameTypeAs, engine.Exception{})
+
+							var sb strings.Builder
+							So(err.(engine.Exception).Term().(engine.Compound).Arg(0).WriteTerm(&sb, &engine.WriteOptions{}, nil), ShouldBeNil)
+							So(sb.String(), ShouldEqual, tc.wantError)
+						})
+					}
+				})
+			})
+		}
+	})
+}
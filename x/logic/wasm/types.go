@@ -8,6 +8,8 @@ import "github.com/okp4/okp4d/x/logic/types"
 type AskQuery struct {
 	Program string `json:"program"`
 	Query   string `json:"query"`
+	Limit   uint64 `json:"limit,omitempty"`
+	Offset  uint64 `json:"offset,omitempty"`
 }
 
 // AskResponse implements the Ask query response JSON schema in a wasm custom query purpose, it redefines the existing